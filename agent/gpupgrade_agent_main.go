@@ -21,6 +21,8 @@ func main() {
 	//	os.Exit(utils.GetExitCodeForError(err))
 	//}
 	var logdir, statedir string
+	var port int
+	var useTLS bool
 	var shouldDaemonize bool
 
 	var RootCmd = &cobra.Command{
@@ -28,14 +30,36 @@ func main() {
 		Short: "Start the Command Listener (blocks)",
 		Long:  `Start the Command Listener (blocks)`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			gplog.InitializeLogging("gpupgrade_agent", logdir)
-			defer log.WritePanics()
+			settings, err := utils.LoadSettings(statedir)
+			if err != nil {
+				return err
+			}
+
+			// Flags that were given explicitly win over the settings file
+			// and the environment.
+			if cmd.Flags().Changed("port") {
+				settings.AgentPort = port
+			}
+			if cmd.Flags().Changed("log-directory") {
+				settings.LogDir = logdir
+			}
+			if cmd.Flags().Changed("tls") {
+				settings.TLS = useTLS
+			}
+			if err := settings.Validate(); err != nil {
+				return err
+			}
 
 			conf := services.AgentConfig{
-				Port:     6416,
+				Port:     settings.AgentPort,
 				StateDir: statedir,
+				LogDir:   settings.LogDir,
+				TLS:      settings.TLS,
 			}
 
+			gplog.InitializeLogging("gpupgrade_agent", conf.LogDir)
+			defer log.WritePanics()
+
 			agentServer := services.NewAgentServer(&cluster.GPDBExecutor{}, conf)
 			if shouldDaemonize {
 				agentServer.MakeDaemon()
@@ -51,6 +75,8 @@ func main() {
 
 	RootCmd.Flags().StringVar(&logdir, "log-directory", "", "command_listener log directory")
 	RootCmd.Flags().StringVar(&statedir, "state-directory", utils.GetStateDir(), "Agent state directory")
+	RootCmd.Flags().IntVar(&port, "port", utils.DefaultAgentPort, "port to listen on for the gpupgrade_hub")
+	RootCmd.Flags().BoolVar(&useTLS, "tls", false, "accept only a gpupgrade_hub with a certificate from its certificate authority")
 
 	daemon.MakeDaemonizable(RootCmd, &shouldDaemonize)
 
@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/certs"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
 	"github.com/greenplum-db/gpupgrade/utils/log"
 
@@ -19,9 +20,11 @@ import (
 )
 
 type AgentServer struct {
-	GetDiskUsage func() (map[string]float64, error)
-	executor     cluster.Executor
-	conf         AgentConfig
+	GetDiskUsage       func() (map[string]float64, error)
+	GetDataDirSize     func(dataDir string) (total uint64, relations uint64, err error)
+	GetFilesystemSpace func(path string) (filesystem string, free uint64, err error)
+	executor           cluster.Executor
+	conf               AgentConfig
 
 	mu      sync.Mutex
 	server  *grpc.Server
@@ -33,14 +36,18 @@ type AgentServer struct {
 type AgentConfig struct {
 	Port     int
 	StateDir string
+	LogDir   string
+	TLS      bool // serve with the certificates that the hub put in StateDir
 }
 
 func NewAgentServer(executor cluster.Executor, conf AgentConfig) *AgentServer {
 	return &AgentServer{
-		GetDiskUsage: diskUsage,
-		executor:     executor,
-		conf:         conf,
-		stopped:      make(chan struct{}, 1),
+		GetDiskUsage:       diskUsage,
+		GetDataDirSize:     dataDirSize,
+		GetFilesystemSpace: filesystemSpace,
+		executor:           executor,
+		conf:               conf,
+		stopped:            make(chan struct{}, 1),
 	}
 }
 
@@ -63,7 +70,15 @@ func (a *AgentServer) Start() {
 		defer log.WritePanics()
 		return handler(ctx, req)
 	}
-	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+	options := []grpc.ServerOption{grpc.UnaryInterceptor(interceptor)}
+	if a.conf.TLS {
+		creds, err := certs.ServerCredentials(certs.Dir(a.conf.StateDir), certs.AGENT_CERT, certs.AGENT_KEY)
+		if err != nil {
+			gplog.Fatal(err, "failed to load certificates")
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	server := grpc.NewServer(options...)
 
 	a.mu.Lock()
 	a.server = server
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+)
+
+// CancelConvertPrimarySegments stops the pg_upgrade of each segment that is
+// still being converted, along with any server it left running, and replies
+// with the segments that it stopped.
+func (s *AgentServer) CancelConvertPrimarySegments(ctx context.Context, in *pb.CancelConvertPrimarySegmentsRequest) (*pb.CancelConvertPrimarySegmentsReply, error) {
+	gplog.Info("got a request to cancel the primary conversion from the hub")
+
+	reply := &pb.CancelConvertPrimarySegmentsReply{}
+	for _, segment := range in.DataDirPairs {
+		pid, err := utils.StopPgUpgrade(s.executor, s.segmentUpgradeDir(segment), utils.DefaultTerminateTimeout,
+			utils.PgServer{BinDir: in.OldBinDir, DataDir: segment.OldDataDir},
+			utils.PgServer{BinDir: in.NewBinDir, DataDir: segment.NewDataDir},
+		)
+		if err != nil {
+			gplog.Error("Could not cancel pg_upgrade for segment %d: %s", segment.Content, err)
+			return reply, errors.Wrapf(err, "could not cancel pg_upgrade for segment %d", segment.Content)
+		}
+		if pid == 0 {
+			continue
+		}
+
+		gplog.Info("Stopped pg_upgrade for segment %d (pid %d)", segment.Content, pid)
+		reply.Processes = append(reply.Processes, &pb.SegmentProcess{Content: segment.Content, Pid: int32(pid)})
+	}
+
+	return reply, nil
+}
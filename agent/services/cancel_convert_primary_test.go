@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CancelConvertPrimarySegments", func() {
+	const pid = 4242
+
+	var (
+		agent        *services.AgentServer
+		dir          string
+		testExecutor *testhelper.TestExecutor
+		request      *pb.CancelConvertPrimarySegmentsRequest
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		testExecutor = &testhelper.TestExecutor{}
+		agent = services.NewAgentServer(testExecutor, services.AgentConfig{StateDir: dir})
+
+		request = &pb.CancelConvertPrimarySegmentsRequest{
+			OldBinDir: "/old/bin",
+			NewBinDir: "/new/bin",
+			DataDirPairs: []*pb.DataDirPair{
+				{OldDataDir: "/old/datadir1", NewDataDir: "/new/datadir1", Content: 0},
+				{OldDataDir: "/old/datadir2", NewDataDir: "/new/datadir2", Content: 1},
+			},
+		}
+
+		// Only the first segment is still being converted, and its
+		// pg_upgrade exits as soon as it is asked to.
+		segDir := filepath.Join(dir, "pg_upgrade", "seg-0")
+		err = os.MkdirAll(segDir, 0700)
+		Expect(err).ToNot(HaveOccurred())
+		err = utils.WritePidFile(filepath.Join(segDir, utils.PgUpgradePidFile), pid)
+		Expect(err).ToNot(HaveOccurred())
+
+		exited := false
+		utils.System.Kill = func(p int, sig syscall.Signal) error {
+			Expect(p).To(Equal(-pid))
+			if sig != 0 {
+				exited = true
+			}
+			if exited {
+				return syscall.ESRCH
+			}
+			return nil
+		}
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	It("stops pg_upgrade on the segments that are still being converted", func() {
+		reply, err := agent.CancelConvertPrimarySegments(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Processes).To(Equal([]*pb.SegmentProcess{{Content: 0, Pid: pid}}))
+		Expect(testExecutor.LocalCommands).To(ContainElement("pgrep -F /old/datadir1/postmaster.pid"))
+		Expect(testExecutor.LocalCommands).To(ContainElement("pgrep -F /new/datadir1/postmaster.pid"))
+		Expect(filepath.Join(dir, "pg_upgrade", "seg-0", utils.PgUpgradePidFile)).ToNot(BeAnExistingFile())
+	})
+
+	It("returns an error if a segment's pid file cannot be read", func() {
+		err := ioutil.WriteFile(filepath.Join(dir, "pg_upgrade", "seg-0", utils.PgUpgradePidFile), []byte("garbage"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = agent.CancelConvertPrimarySegments(nil, request)
+		Expect(err).To(MatchError(ContainSubstring("could not cancel pg_upgrade for segment 0")))
+	})
+})
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"os"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+)
+
+// CheckPaths reports which of the data directories and state files that the
+// hub asks about are missing from this host.
+func (s *AgentServer) CheckPaths(ctx context.Context, in *pb.CheckPathsRequest) (*pb.CheckPathsReply, error) {
+	gplog.Info("got a request to check paths from the hub")
+
+	reply := &pb.CheckPathsReply{}
+	for _, dataDir := range in.Datadirs {
+		missing, err := isMissing(dataDir)
+		if err != nil {
+			return nil, err
+		}
+		if missing {
+			reply.Missing = append(reply.Missing, dataDir)
+		}
+	}
+
+	for _, name := range in.StateFiles {
+		path, err := StateDirPath(s.conf.StateDir, name)
+		if err != nil {
+			return nil, err
+		}
+		missing, err := isMissing(path)
+		if err != nil {
+			return nil, err
+		}
+		if missing {
+			reply.Missing = append(reply.Missing, name)
+		}
+	}
+
+	for _, path := range reply.Missing {
+		gplog.Warn("%s is missing", path)
+	}
+	return reply, nil
+}
+
+func isMissing(path string) (bool, error) {
+	_, err := utils.System.Stat(path)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	return false, errors.Wrapf(err, "%s cannot be checked", path)
+}
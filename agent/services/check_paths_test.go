@@ -0,0 +1,69 @@
+package services_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckPaths", func() {
+	var (
+		agent *services.AgentServer
+		dir   string
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		agent = services.NewAgentServer(&testhelper.TestExecutor{}, services.AgentConfig{StateDir: dir})
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("reports nothing when every path is there", func() {
+		dataDir := filepath.Join(dir, "gpseg0")
+		Expect(os.Mkdir(dataDir, 0700)).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(dir, "pg_upgrade"), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "pg_upgrade", "oids.sql"), nil, 0600)).To(Succeed())
+
+		reply, err := agent.CheckPaths(nil, &pb.CheckPathsRequest{
+			Datadirs:   []string{dataDir},
+			StateFiles: []string{"pg_upgrade/oids.sql"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Missing).To(BeEmpty())
+	})
+
+	It("reports the data directories and state files that are missing", func() {
+		dataDir := filepath.Join(dir, "gpseg0")
+		Expect(os.Mkdir(dataDir, 0700)).To(Succeed())
+
+		reply, err := agent.CheckPaths(nil, &pb.CheckPathsRequest{
+			Datadirs:   []string{dataDir, filepath.Join(dir, "gpseg1")},
+			StateFiles: []string{"pg_upgrade/oids.sql"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Missing).To(Equal([]string{filepath.Join(dir, "gpseg1"), "pg_upgrade/oids.sql"}))
+	})
+
+	It("refuses state files outside of the state directory", func() {
+		_, err := agent.CheckPaths(nil, &pb.CheckPathsRequest{
+			StateFiles: []string{"../passwd"},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// CheckPorts reports, for each requested port, whether something on this host
+// is already listening on it.
+func (s *AgentServer) CheckPorts(ctx context.Context, in *pb.CheckPortsRequestToAgent) (*pb.CheckPortsReplyFromAgent, error) {
+	gplog.Info("got a request to check ports from the hub")
+
+	reply := &pb.CheckPortsReplyFromAgent{}
+	for _, port := range in.Ports {
+		reply.Ports = append(reply.Ports, s.portStatus(int(port)))
+	}
+	return reply, nil
+}
+
+// portStatus tries to listen on the port. If that fails, it asks lsof which
+// process holds the port; the process is left out if lsof can't tell, for
+// instance because it belongs to another user.
+func (s *AgentServer) portStatus(port int) *pb.PortStatus {
+	status := &pb.PortStatus{Port: int32(port)}
+
+	lis, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err == nil {
+		lis.Close()
+		return status
+	}
+	status.InUse = true
+
+	output, err := s.executor.ExecuteLocalCommand(fmt.Sprintf("lsof -nP -iTCP:%d -sTCP:LISTEN -Fpc", port))
+	if err != nil {
+		gplog.Info("could not find the process listening on port %d: %s", port, err)
+		return status
+	}
+	status.Pid, status.Process = ParseLsofProcess(output)
+	return status
+}
+
+// ParseLsofProcess returns the first process in the output of lsof -Fpc,
+// which gives the pid and the command of each process on lines of their own,
+// prefixed by "p" and "c".
+func ParseLsofProcess(output string) (int32, string) {
+	var pid int32
+	var process string
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			if pid != 0 {
+				return pid, process
+			}
+			p, err := strconv.Atoi(line[1:])
+			if err == nil {
+				pid = int32(p)
+			}
+		case 'c':
+			if process == "" {
+				process = line[1:]
+			}
+		}
+	}
+	return pid, process
+}
+
+// describePort says who holds a port that is in use, as far as is known.
+func describePort(status *pb.PortStatus) string {
+	if status.Pid == 0 {
+		return fmt.Sprintf("port %d is in use", status.Port)
+	}
+	return fmt.Sprintf("port %d is in use by %s (pid %d)", status.Port, status.Process, status.Pid)
+}
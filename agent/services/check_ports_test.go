@@ -0,0 +1,80 @@
+package services_test
+
+import (
+	"errors"
+	"net"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckPorts", func() {
+	var (
+		agent        *services.AgentServer
+		testExecutor *testhelper.TestExecutor
+		busyPort     int
+		freePort     int
+		lis          net.Listener
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+		testExecutor = &testhelper.TestExecutor{}
+		agent = services.NewAgentServer(testExecutor, services.AgentConfig{})
+
+		var err error
+		lis, err = net.Listen("tcp", ":0")
+		Expect(err).ToNot(HaveOccurred())
+		busyPort = lis.Addr().(*net.TCPAddr).Port
+
+		free, err := net.Listen("tcp", ":0")
+		Expect(err).ToNot(HaveOccurred())
+		freePort = free.Addr().(*net.TCPAddr).Port
+		free.Close()
+	})
+
+	AfterEach(func() {
+		lis.Close()
+	})
+
+	It("reports which ports are in use, and by which process", func() {
+		testExecutor.LocalOutput = "p4321\ncpostgres\nf5\n"
+
+		reply, err := agent.CheckPorts(nil, &pb.CheckPortsRequestToAgent{
+			Ports: []int32{int32(freePort), int32(busyPort)},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Ports).To(Equal([]*pb.PortStatus{
+			{Port: int32(freePort)},
+			{Port: int32(busyPort), InUse: true, Pid: 4321, Process: "postgres"},
+		}))
+
+		Expect(testExecutor.NumExecutions).To(Equal(1))
+		Expect(testExecutor.LocalCommands[0]).To(ContainSubstring("lsof"))
+	})
+
+	It("still reports a port in use when its process can't be found", func() {
+		testExecutor.LocalError = errors.New("exit status 1")
+
+		reply, err := agent.CheckPorts(nil, &pb.CheckPortsRequestToAgent{
+			Ports: []int32{int32(busyPort)},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Ports).To(Equal([]*pb.PortStatus{
+			{Port: int32(busyPort), InUse: true},
+		}))
+	})
+
+	Describe("ParseLsofProcess", func() {
+		It("takes the first process listed", func() {
+			pid, process := services.ParseLsofProcess("p100\ncnginx\np200\ncnginx\n")
+			Expect(pid).To(Equal(int32(100)))
+			Expect(process).To(Equal("nginx"))
+		})
+	})
+})
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// CheckTargetLayout reports every requested port that can't be listened on,
+// every requested data directory that gpinitsystem won't be able to create,
+// and, for link mode, every target data directory that would be created on a
+// different filesystem from its source, on this host.
+func (s *AgentServer) CheckTargetLayout(ctx context.Context, in *pb.CheckTargetLayoutRequest) (*pb.CheckTargetLayoutReply, error) {
+	gplog.Info("got a request to check the target layout from the hub")
+
+	reply := &pb.CheckTargetLayoutReply{}
+	for _, port := range in.Ports {
+		status := s.portStatus(int(port))
+		if status.InUse {
+			reply.Problems = append(reply.Problems, describePort(status))
+		}
+	}
+	for _, dataDir := range in.Datadirs {
+		err := checkDataDirCanBeCreated(dataDir)
+		if err != nil {
+			reply.Problems = append(reply.Problems, err.Error())
+		}
+	}
+	for _, pair := range in.LinkedDatadirs {
+		err := checkLinkable(pair.OldDataDir, pair.NewDataDir)
+		if err != nil {
+			reply.Problems = append(reply.Problems, err.Error())
+		}
+	}
+
+	for _, problem := range reply.Problems {
+		gplog.Warn(problem)
+	}
+	return reply, nil
+}
+
+// checkDataDirCanBeCreated makes sure that dataDir doesn't exist yet, and that
+// the closest directory above it that does is writable, so that it and any
+// missing parents can be made.
+func checkDataDirCanBeCreated(dataDir string) error {
+	_, err := utils.System.Stat(dataDir)
+	if err == nil {
+		return fmt.Errorf("data directory %s already exists", dataDir)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("data directory %s cannot be checked: %s", dataDir, err)
+	}
+
+	dir, err := existingAncestor(filepath.Dir(dataDir))
+	if err != nil {
+		return err
+	}
+
+	probe, err := ioutil.TempFile(dir, ".gpupgrade_probe")
+	if err != nil {
+		return fmt.Errorf("data directory %s cannot be created, because %s is not writable: %s", dataDir, dir, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// checkLinkable makes sure that the target data directory, once it has been
+// created, will be on the same filesystem as the source data directory, so
+// that pg_upgrade can hard-link between them. Whatever parents of the target
+// are missing are created within the closest one that exists, so that is the
+// filesystem the target will end up on.
+func checkLinkable(sourceDataDir, targetDataDir string) error {
+	dir, err := existingAncestor(filepath.Dir(targetDataDir))
+	if err != nil {
+		return err
+	}
+
+	same, err := utils.SameFilesystem(sourceDataDir, dir)
+	if err != nil {
+		return err
+	}
+	if !same {
+		return fmt.Errorf("link mode needs source data directory %s and target data directory %s on the same filesystem, but %s is on another", sourceDataDir, targetDataDir, dir)
+	}
+	return nil
+}
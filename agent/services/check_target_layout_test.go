@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckTargetLayout", func() {
+	var (
+		agent *services.AgentServer
+		dir   string
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+		agent = services.NewAgentServer(&testhelper.TestExecutor{LocalOutput: "p1234\ncpostgres\n"}, services.AgentConfig{})
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("reports nothing when the ports are free and the data directories can be created", func() {
+		lis, err := net.Listen("tcp", ":0")
+		Expect(err).ToNot(HaveOccurred())
+		freePort := lis.Addr().(*net.TCPAddr).Port
+		lis.Close()
+
+		reply, err := agent.CheckTargetLayout(nil, &pb.CheckTargetLayoutRequest{
+			Ports:    []int32{int32(freePort)},
+			Datadirs: []string{filepath.Join(dir, "primary_upgrade", "gpseg0")},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Problems).To(BeEmpty())
+	})
+
+	It("reports ports that are in use", func() {
+		lis, err := net.Listen("tcp", ":0")
+		Expect(err).ToNot(HaveOccurred())
+		defer lis.Close()
+		busyPort := lis.Addr().(*net.TCPAddr).Port
+
+		reply, err := agent.CheckTargetLayout(nil, &pb.CheckTargetLayoutRequest{
+			Ports: []int32{int32(busyPort)},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Problems).To(ConsistOf(fmt.Sprintf("port %d is in use by postgres (pid 1234)", busyPort)))
+	})
+
+	It("reports data directories that already exist", func() {
+		existing := filepath.Join(dir, "gpseg0")
+		Expect(os.Mkdir(existing, 0755)).To(Succeed())
+
+		reply, err := agent.CheckTargetLayout(nil, &pb.CheckTargetLayoutRequest{
+			Datadirs: []string{existing},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Problems).To(ConsistOf("data directory " + existing + " already exists"))
+	})
+
+	It("reports data directories that cannot be created", func() {
+		if os.Geteuid() == 0 {
+			Skip("root can write to any directory")
+		}
+		readOnly := filepath.Join(dir, "readonly")
+		Expect(os.Mkdir(readOnly, 0555)).To(Succeed())
+
+		reply, err := agent.CheckTargetLayout(nil, &pb.CheckTargetLayoutRequest{
+			Datadirs: []string{filepath.Join(readOnly, "primary_upgrade", "gpseg0")},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Problems).To(ConsistOf(ContainSubstring("because " + readOnly + " is not writable")))
+	})
+
+	Describe("in link mode", func() {
+		var source string
+
+		BeforeEach(func() {
+			source = filepath.Join(dir, "primary", "gpseg0")
+			Expect(os.MkdirAll(source, 0700)).To(Succeed())
+		})
+
+		It("accepts target data directories that will be on the source's filesystem", func() {
+			reply, err := agent.CheckTargetLayout(nil, &pb.CheckTargetLayoutRequest{
+				LinkedDatadirs: []*pb.DataDirPair{
+					{OldDataDir: source, NewDataDir: filepath.Join(dir, "primary_upgrade", "gpseg0")},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reply.Problems).To(BeEmpty())
+		})
+
+		It("reports target data directories that would be created on another filesystem", func() {
+			reply, err := agent.CheckTargetLayout(nil, &pb.CheckTargetLayoutRequest{
+				LinkedDatadirs: []*pb.DataDirPair{
+					{OldDataDir: source, NewDataDir: "/proc/primary_upgrade/gpseg0"},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reply.Problems).To(ConsistOf(fmt.Sprintf("link mode needs source data directory %s and target data directory "+
+				"/proc/primary_upgrade/gpseg0 on the same filesystem, but /proc is on another", source)))
+		})
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
@@ -14,28 +15,66 @@ func (s *AgentServer) CheckConversionStatus(ctx context.Context, in *pb.CheckCon
 	if len(in.GetSegments()) == 0 {
 		return nil, errors.New("no segment information was passed to the agent")
 	}
-	format := "%s - DBID %d - CONTENT ID %d - %s - %s"
 
-	var replies []string
-	var master string
+	var replies []*pb.ConversionStatus
+	var master *pb.ConversionStatus
 	for _, segment := range in.GetSegments() {
-		status := upgradestatus.SegmentConversionStatus(
-			filepath.Join(s.conf.StateDir, "pg_upgrade", fmt.Sprintf("seg-%d", segment.GetContent())),
-			segment.GetDataDir(),
-			s.executor,
-		)
+		pgUpgradePath := filepath.Join(s.conf.StateDir, "pg_upgrade", fmt.Sprintf("seg-%d", segment.GetContent()))
+		logPath := filepath.Join(pgUpgradePath, "pg_upgrade_segment.log")
+
+		status := &pb.ConversionStatus{
+			Dbid:     segment.GetDbid(),
+			Content:  segment.GetContent(),
+			Role:     pb.SegmentRole_PRIMARY,
+			Hostname: in.GetHostname(),
+			Status: upgradestatus.SegmentConversionStatus(
+				pgUpgradePath,
+				segment.GetDataDir(),
+				s.executor,
+			),
+		}
+
+		progress := upgradestatus.SegmentConversionProgress(pgUpgradePath, filepath.Base(logPath))
+		status.StartTime = unixTime(progress.Started)
+
+		switch status.Status {
+		case pb.StepStatus_RUNNING:
+			status.Phase = progress.Phase
+			status.PercentDone = int32(progress.Percent)
+		case pb.StepStatus_FAILED:
+			status.Phase = progress.Phase
+			status.PercentDone = int32(progress.Percent)
+			status.EndTime = unixTime(progress.Updated)
+			status.ErrorMessage = fmt.Sprintf("pg_upgrade failed; see %s for details", logPath)
+			if progress.Phase != "" {
+				status.ErrorMessage = fmt.Sprintf("pg_upgrade failed during %q; see %s for details", progress.Phase, logPath)
+			}
+		case pb.StepStatus_COMPLETE:
+			status.PercentDone = 100
+			status.EndTime = unixTime(progress.Updated)
+		}
 
-		// FIXME: we have status codes; why convert to strings?
 		if segment.GetDbid() == 1 && segment.GetContent() == -1 {
-			master = fmt.Sprintf(format, status.String(), segment.GetDbid(), segment.GetContent(), "MASTER", in.GetHostname())
+			status.Role = pb.SegmentRole_MASTER
+			master = status
 		} else {
-			replies = append(replies, fmt.Sprintf(format, status.String(), segment.GetDbid(), segment.GetContent(), "PRIMARY", in.GetHostname()))
+			replies = append(replies, status)
 		}
 	}
 
-	replies = append([]string{master}, replies...)
+	if master != nil {
+		replies = append([]*pb.ConversionStatus{master}, replies...)
+	}
 
 	return &pb.CheckConversionStatusReply{
 		Statuses: replies,
 	}, nil
 }
+
+// unixTime converts t for the wire, keeping the zero time as zero.
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -41,7 +42,7 @@ var _ = Describe("CommandListener", func() {
 		os.RemoveAll(dir)
 	})
 
-	It("returns a status string for each DBID passed from the hub", func() {
+	It("returns a status for each DBID passed from the hub, with the master first", func() {
 		status, err := agent.CheckConversionStatus(nil, &pb.CheckConversionStatusRequest{
 			Segments: []*pb.SegmentInfo{{
 				Content: 1,
@@ -56,10 +57,19 @@ var _ = Describe("CommandListener", func() {
 		})
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(status.GetStatuses()).To(Equal([]string{
-			"PENDING - DBID 1 - CONTENT ID -1 - MASTER - localhost",
-			"PENDING - DBID 3 - CONTENT ID 1 - PRIMARY - localhost",
-		}))
+		Expect(status.GetStatuses()).To(Equal([]*pb.ConversionStatus{{
+			Dbid:     1,
+			Content:  -1,
+			Role:     pb.SegmentRole_MASTER,
+			Hostname: "localhost",
+			Status:   pb.StepStatus_PENDING,
+		}, {
+			Dbid:     3,
+			Content:  1,
+			Role:     pb.SegmentRole_PRIMARY,
+			Hostname: "localhost",
+			Status:   pb.StepStatus_PENDING,
+		}}))
 	})
 
 	It("returns running for segments that have the upgrade in progress", func() {
@@ -85,10 +95,44 @@ var _ = Describe("CommandListener", func() {
 		})
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(status.GetStatuses()).To(Equal([]string{
-			"PENDING - DBID 1 - CONTENT ID -1 - MASTER - localhost",
-			"RUNNING - DBID 3 - CONTENT ID 1 - PRIMARY - localhost",
-		}))
+		statuses := status.GetStatuses()
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].Status).To(Equal(pb.StepStatus_PENDING))
+		Expect(statuses[1].Status).To(Equal(pb.StepStatus_RUNNING))
+		Expect(statuses[1].StartTime).ToNot(BeZero())
+		Expect(statuses[1].EndTime).To(BeZero())
+	})
+
+	It("includes the pg_upgrade phase and percent done for running segments", func() {
+		segDir := filepath.Join(dir, "pg_upgrade", "seg-1")
+		err := os.MkdirAll(segDir, 0700)
+		Expect(err).ToNot(HaveOccurred())
+		err = ioutil.WriteFile(filepath.Join(segDir, "1.done"), []byte("Creating dump of global objects\n"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+		err = ioutil.WriteFile(filepath.Join(segDir, "2.inprogress"), []byte("Copying user relation files\n"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		testExecutor.LocalOutput = "pid1"
+
+		status, err := agent.CheckConversionStatus(nil, &pb.CheckConversionStatusRequest{
+			Segments: []*pb.SegmentInfo{{
+				Content: 1,
+				Dbid:    3,
+				DataDir: "/old/data/dir",
+			}, {
+				Content: -1,
+				Dbid:    1,
+				DataDir: "/old/dir",
+			}},
+			Hostname: "localhost",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		statuses := status.GetStatuses()
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[1].Status).To(Equal(pb.StepStatus_RUNNING))
+		Expect(statuses[1].Phase).To(Equal("Copying user relation files"))
+		Expect(statuses[1].PercentDone).To(Equal(int32(60)))
 	})
 
 	It("returns COMPLETE for segments that have completed the upgrade", func() {
@@ -113,10 +157,42 @@ var _ = Describe("CommandListener", func() {
 		})
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(status.GetStatuses()).To(Equal([]string{
-			"COMPLETE - DBID 1 - CONTENT ID -1 - MASTER - localhost",
-			"PENDING - DBID 3 - CONTENT ID 1 - PRIMARY - localhost",
-		}))
+		statuses := status.GetStatuses()
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].Role).To(Equal(pb.SegmentRole_MASTER))
+		Expect(statuses[0].Status).To(Equal(pb.StepStatus_COMPLETE))
+		Expect(statuses[0].PercentDone).To(Equal(int32(100)))
+		Expect(statuses[0].StartTime).ToNot(BeZero())
+		Expect(statuses[0].EndTime).ToNot(BeZero())
+		Expect(statuses[1].Status).To(Equal(pb.StepStatus_PENDING))
+	})
+
+	It("returns FAILED with an error message for segments whose upgrade died", func() {
+		segDir := filepath.Join(dir, "pg_upgrade", "seg-1")
+		err := os.MkdirAll(segDir, 0700)
+		Expect(err).ToNot(HaveOccurred())
+		err = ioutil.WriteFile(filepath.Join(segDir, "1.inprogress"), []byte("Restoring database schemas in the new cluster\n"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		testExecutor.LocalError = errors.New("exit status 1")
+
+		status, err := agent.CheckConversionStatus(nil, &pb.CheckConversionStatusRequest{
+			Segments: []*pb.SegmentInfo{{
+				Content: 1,
+				Dbid:    3,
+				DataDir: "/old/data/dir",
+			}},
+			Hostname: "localhost",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		statuses := status.GetStatuses()
+		Expect(statuses).To(HaveLen(1))
+		Expect(statuses[0].Status).To(Equal(pb.StepStatus_FAILED))
+		Expect(statuses[0].Phase).To(Equal("Restoring database schemas in the new cluster"))
+		Expect(statuses[0].ErrorMessage).To(ContainSubstring("pg_upgrade failed during"))
+		Expect(statuses[0].ErrorMessage).To(ContainSubstring(filepath.Join(segDir, "pg_upgrade_segment.log")))
+		Expect(statuses[0].EndTime).ToNot(BeZero())
 	})
 
 	It("returns an error if no segments are passed", func() {
@@ -15,6 +15,20 @@ import (
 func (s *AgentServer) UpgradeConvertPrimarySegments(ctx context.Context, in *pb.UpgradeConvertPrimarySegmentsRequest) (*pb.UpgradeConvertPrimarySegmentsReply, error) {
 	gplog.Info("got a request to convert primary from the hub")
 
+	if in.DryRun {
+		return &pb.UpgradeConvertPrimarySegmentsReply{Plan: s.planConvertPrimarySegments(in)}, nil
+	}
+
+	if in.Mode == utils.LINK_MODE {
+		for _, segment := range in.DataDirPairs {
+			err := utils.CheckSameFilesystem(segment.OldDataDir, segment.NewDataDir)
+			if err != nil {
+				gplog.Error(err.Error())
+				return &pb.UpgradeConvertPrimarySegmentsReply{}, err
+			}
+		}
+	}
+
 	filename := "pg_upgrade_dump_*_oids.sql"
 	shareOIDfilePath := filepath.Join(s.conf.StateDir, "pg_upgrade", filename)
 	oidFiles, err := utils.System.FilePathGlob(shareOIDfilePath)
@@ -28,8 +42,9 @@ func (s *AgentServer) UpgradeConvertPrimarySegments(ctx context.Context, in *pb.
 		return &pb.UpgradeConvertPrimarySegmentsReply{}, errors.New("No OID files found")
 	}
 
+	var processes []*pb.SegmentProcess
 	for _, segment := range in.DataDirPairs {
-		pathToSegment := filepath.Join(s.conf.StateDir, "pg_upgrade", fmt.Sprintf("seg-%d", segment.Content))
+		pathToSegment := s.segmentUpgradeDir(segment)
 		err := utils.System.MkdirAll(pathToSegment, 0700)
 		if err != nil {
 			gplog.Error("Could not create segment directory. Err: %v", err)
@@ -44,15 +59,62 @@ func (s *AgentServer) UpgradeConvertPrimarySegments(ctx context.Context, in *pb.
 			}
 		}
 
-		convertPrimaryCmd := fmt.Sprintf("cd %s && nohup %s --old-bindir=%s --old-datadir=%s --new-bindir=%s --new-datadir=%s --old-port=%d --new-port=%d --progress",
-			pathToSegment, in.NewBinDir+"/pg_upgrade", in.OldBinDir, segment.OldDataDir, in.NewBinDir, segment.NewDataDir, segment.OldPort, segment.NewPort)
+		err = utils.RemoveProgressFiles(pathToSegment)
+		if err != nil {
+			gplog.Error("Could not remove the progress files of an earlier upgrade of segment %d: %s", segment.Content, err)
+			return &pb.UpgradeConvertPrimarySegmentsReply{}, err
+		}
 
-		err = utils.System.RunCommandAsync(convertPrimaryCmd, filepath.Join(pathToSegment, "pg_upgrade_segment.log"))
+		convertPrimaryCmd := convertPrimaryCommand(in, segment, pathToSegment)
+
+		pid, err := utils.System.RunCommandAsync(convertPrimaryCmd, filepath.Join(pathToSegment, "pg_upgrade_segment.log"))
 		if err != nil {
 			gplog.Error("An error occurred: %v", err)
 			return &pb.UpgradeConvertPrimarySegmentsReply{}, err
 		}
+		processes = append(processes, &pb.SegmentProcess{Content: segment.Content, Pid: int32(pid)})
+
+		err = utils.WritePidFile(filepath.Join(pathToSegment, utils.PgUpgradePidFile), pid)
+		if err != nil {
+			gplog.Error("Could not record the pid of pg_upgrade for segment %d, so it cannot be cancelled: %s", segment.Content, err)
+		}
 	}
 
-	return &pb.UpgradeConvertPrimarySegmentsReply{}, nil
+	return &pb.UpgradeConvertPrimarySegmentsReply{Processes: processes}, nil
+}
+
+// planConvertPrimarySegments lists what UpgradeConvertPrimarySegments would
+// run on this host. The hub fills in the hostname. The OID files don't exist
+// until the master has been converted, so the plan copies whatever matches
+// their pattern at that point.
+func (s *AgentServer) planConvertPrimarySegments(in *pb.UpgradeConvertPrimarySegmentsRequest) *pb.DryRunPlan {
+	oidFiles := filepath.Join(s.conf.StateDir, "pg_upgrade", "pg_upgrade_dump_*_oids.sql")
+
+	plan := &pb.DryRunPlan{}
+	for _, segment := range in.DataDirPairs {
+		pathToSegment := s.segmentUpgradeDir(segment)
+		logFile := filepath.Join(pathToSegment, "pg_upgrade_segment.log")
+
+		for _, command := range []string{
+			fmt.Sprintf("mkdir -p %s", pathToSegment),
+			fmt.Sprintf("cp %s %s", oidFiles, pathToSegment),
+			utils.AsyncCommandString(convertPrimaryCommand(in, segment, pathToSegment), logFile),
+		} {
+			plan.Commands = append(plan.Commands, &pb.PlannedCommand{Command: command})
+		}
+	}
+	return plan
+}
+
+func (s *AgentServer) segmentUpgradeDir(segment *pb.DataDirPair) string {
+	return filepath.Join(s.conf.StateDir, "pg_upgrade", fmt.Sprintf("seg-%d", segment.Content))
+}
+
+func convertPrimaryCommand(in *pb.UpgradeConvertPrimarySegmentsRequest, segment *pb.DataDirPair, pathToSegment string) string {
+	cmd := fmt.Sprintf("cd %s && nohup %s --old-bindir=%s --old-datadir=%s --new-bindir=%s --new-datadir=%s --old-port=%d --new-port=%d --progress",
+		pathToSegment, in.NewBinDir+"/pg_upgrade", in.OldBinDir, segment.OldDataDir, in.NewBinDir, segment.NewDataDir, segment.OldPort, segment.NewPort)
+	if in.Mode == utils.LINK_MODE {
+		cmd += " --link"
+	}
+	return cmd
 }
@@ -52,11 +52,11 @@ var _ = Describe("CommandListener", func() {
 
 	It("successfully runs pg_upgrade", func() {
 		// We want to check what commands are passed to RunCommandAsync, so we have testExecutor record them for us
-		utils.System.RunCommandAsync = func(cmdStr, logFile string) error {
+		utils.System.RunCommandAsync = func(cmdStr, logFile string) (int, error) {
 			_, err := testExecutor.ExecuteLocalCommand(cmdStr)
-			return err
+			return 4242, err
 		}
-		_, err := agent.UpgradeConvertPrimarySegments(nil, &pb.UpgradeConvertPrimarySegmentsRequest{
+		reply, err := agent.UpgradeConvertPrimarySegments(nil, &pb.UpgradeConvertPrimarySegmentsRequest{
 			OldBinDir: "/old/bin",
 			NewBinDir: "/new/bin",
 			DataDirPairs: []*pb.DataDirPair{
@@ -66,6 +66,14 @@ var _ = Describe("CommandListener", func() {
 		})
 		Expect(err).ToNot(HaveOccurred())
 
+		Expect(reply.Processes).To(Equal([]*pb.SegmentProcess{
+			{Content: 0, Pid: 4242},
+			{Content: 1, Pid: 4242},
+		}))
+		pid, err := utils.ReadPidFile(filepath.Join(dir, "pg_upgrade", "seg-1", utils.PgUpgradePidFile))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pid).To(Equal(4242))
+
 		Expect(testExecutor.NumExecutions).To(Equal(4))
 
 		Expect(testExecutor.LocalCommands).To(ContainElement(fmt.Sprintf("cp %s %s/pg_upgrade/seg-0", oidFile, dir)))
@@ -74,6 +82,73 @@ var _ = Describe("CommandListener", func() {
 		Expect(testExecutor.LocalCommands).To(ContainElement(fmt.Sprintf("cd %s/pg_upgrade/seg-1 && nohup /new/bin/pg_upgrade --old-bindir=/old/bin --old-datadir=old/datadir2 --new-bindir=/new/bin --new-datadir=new/datadir2 --old-port=2 --new-port=22 --progress", dir)))
 	})
 
+	It("runs pg_upgrade in link mode", func() {
+		utils.System.RunCommandAsync = func(cmdStr, logFile string) (int, error) {
+			_, err := testExecutor.ExecuteLocalCommand(cmdStr)
+			return 4242, err
+		}
+		oldDataDir := filepath.Join(dir, "seg1")
+		newDataDir := filepath.Join(dir, "seg1_upgrade")
+		Expect(os.Mkdir(oldDataDir, 0700)).To(Succeed())
+		Expect(os.Mkdir(newDataDir, 0700)).To(Succeed())
+
+		_, err := agent.UpgradeConvertPrimarySegments(nil, &pb.UpgradeConvertPrimarySegmentsRequest{
+			OldBinDir: "/old/bin",
+			NewBinDir: "/new/bin",
+			DataDirPairs: []*pb.DataDirPair{
+				{OldDataDir: oldDataDir, NewDataDir: newDataDir, Content: 0, OldPort: 1, NewPort: 11},
+			},
+			Mode: utils.LINK_MODE,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(testExecutor.LocalCommands).To(ContainElement(fmt.Sprintf("cd %s/pg_upgrade/seg-0 && nohup /new/bin/pg_upgrade --old-bindir=/old/bin --old-datadir=%s --new-bindir=/new/bin --new-datadir=%s --old-port=1 --new-port=11 --progress --link", dir, oldDataDir, newDataDir)))
+	})
+
+	It("refuses link mode when a target data directory is on another filesystem", func() {
+		utils.System.RunCommandAsync = func(cmdStr, logFile string) (int, error) {
+			Fail("pg_upgrade was started")
+			return 0, nil
+		}
+
+		_, err := agent.UpgradeConvertPrimarySegments(nil, &pb.UpgradeConvertPrimarySegmentsRequest{
+			OldBinDir: "/old/bin",
+			NewBinDir: "/new/bin",
+			DataDirPairs: []*pb.DataDirPair{
+				{OldDataDir: dir, NewDataDir: "/proc", Content: 0, OldPort: 1, NewPort: 11},
+			},
+			Mode: utils.LINK_MODE,
+		})
+		Expect(err).To(MatchError(ContainSubstring("link mode needs source data directory")))
+		Expect(testExecutor.NumExecutions).To(Equal(0))
+	})
+
+	It("plans pg_upgrade without running anything for a dry run", func() {
+		utils.System.MkdirAll = func(path string, perm os.FileMode) error {
+			Fail("dry run created " + path)
+			return nil
+		}
+
+		reply, err := agent.UpgradeConvertPrimarySegments(nil, &pb.UpgradeConvertPrimarySegmentsRequest{
+			OldBinDir: "/old/bin",
+			NewBinDir: "/new/bin",
+			DataDirPairs: []*pb.DataDirPair{
+				{OldDataDir: "old/datadir1", NewDataDir: "new/datadir1", Content: 0, OldPort: 1, NewPort: 11},
+			},
+			DryRun: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(testExecutor.NumExecutions).To(Equal(0))
+
+		segDir := filepath.Join(dir, "pg_upgrade", "seg-0")
+		pgUpgrade := fmt.Sprintf("cd %s && nohup /new/bin/pg_upgrade --old-bindir=/old/bin --old-datadir=old/datadir1 --new-bindir=/new/bin --new-datadir=new/datadir1 --old-port=1 --new-port=11 --progress", segDir)
+		Expect(reply.Plan.Commands).To(Equal([]*pb.PlannedCommand{
+			{Command: "mkdir -p " + segDir},
+			{Command: fmt.Sprintf("cp %s %s", filepath.Join(dir, "pg_upgrade", "pg_upgrade_dump_*_oids.sql"), segDir)},
+			{Command: utils.AsyncCommandString(pgUpgrade, filepath.Join(segDir, "pg_upgrade_segment.log"))},
+		}))
+	})
+
 	It("returns an an error if the oid files glob fails", func() {
 		utils.System.FilePathGlob = func(pattern string) ([]string, error) {
 			return []string{}, errors.New("failed to find files")
@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"os"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
@@ -15,17 +14,13 @@ func (s *AgentServer) CreateSegmentDataDirectories(ctx context.Context, in *pb.C
 
 	datadirs := in.Datadirs
 	for _, segDataDir := range datadirs {
-		_, err := utils.System.Stat(segDataDir)
-		if os.IsNotExist(err) {
-			err = os.Mkdir(segDataDir, 0755)
-			if err != nil {
-				gplog.Error("Error creating directory %s: %s", segDataDir, err)
-				return &pb.CreateSegmentDataDirReply{}, err
-			}
-			gplog.Info("Successfully created directory %s", segDataDir)
-		} else if err != nil {
-			gplog.Error("Error statting new directory %s: %s", segDataDir, err)
+		created, err := utils.CreateTargetDir(segDataDir)
+		if err != nil {
+			gplog.Error("Error creating directory %s: %s", segDataDir, err)
 			return &pb.CreateSegmentDataDirReply{}, err
+		}
+		if created {
+			gplog.Info("Successfully created directory %s", segDataDir)
 		} else {
 			gplog.Info("Directory %s already exists", segDataDir)
 		}
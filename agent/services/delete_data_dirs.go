@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+func (s *AgentServer) DeleteSegmentDataDirectories(ctx context.Context, in *pb.DeleteSegmentDataDirRequest) (*pb.DeleteSegmentDataDirReply, error) {
+	gplog.Info("got a request to delete segment data directories from the hub")
+
+	datadirs := in.Datadirs
+	for _, segDataDir := range datadirs {
+		_, err := utils.System.Stat(segDataDir)
+		if os.IsNotExist(err) {
+			gplog.Info("Directory %s does not exist", segDataDir)
+			continue
+		}
+
+		// Only ever delete directories that were created for the target
+		// cluster; a bad request must never be able to remove source data.
+		if !utils.IsTargetDir(segDataDir) {
+			err := fmt.Errorf("refusing to delete directory %s: it was not created by gpupgrade", segDataDir)
+			gplog.Error(err.Error())
+			return &pb.DeleteSegmentDataDirReply{}, err
+		}
+
+		err = utils.System.RemoveAll(segDataDir)
+		if err != nil {
+			gplog.Error("Error deleting directory %s: %s", segDataDir, err)
+			return &pb.DeleteSegmentDataDirReply{}, err
+		}
+		gplog.Info("Successfully deleted directory %s", segDataDir)
+	}
+	return &pb.DeleteSegmentDataDirReply{}, nil
+}
@@ -0,0 +1,86 @@
+package services_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteSegmentDataDirectories", func() {
+	var (
+		agent   *services.AgentServer
+		removed []string
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		agent = &services.AgentServer{}
+		removed = nil
+		existing := map[string]bool{
+			"/data/primary":         true,
+			"/data/primary_upgrade": true,
+			filepath.Join("/data/primary_upgrade", utils.TARGET_DIR_MARKER): true,
+			"/data/mirror_upgrade": true,
+			filepath.Join("/data/mirror_upgrade", utils.TARGET_DIR_MARKER): true,
+		}
+		utils.System.Stat = func(name string) (os.FileInfo, error) {
+			if !existing[name] {
+				return nil, os.ErrNotExist
+			}
+			return nil, nil
+		}
+		utils.System.RemoveAll = func(name string) error {
+			removed = append(removed, name)
+			return nil
+		}
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+	})
+
+	It("removes every requested upgrade directory", func() {
+		_, err := agent.DeleteSegmentDataDirectories(nil, &pb.DeleteSegmentDataDirRequest{
+			Datadirs: []string{"/data/primary_upgrade", "/data/mirror_upgrade"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(Equal([]string{"/data/primary_upgrade", "/data/mirror_upgrade"}))
+	})
+
+	It("skips directories that do not exist", func() {
+		_, err := agent.DeleteSegmentDataDirectories(nil, &pb.DeleteSegmentDataDirRequest{
+			Datadirs: []string{"/data/missing_upgrade", "/data/primary_upgrade"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(Equal([]string{"/data/primary_upgrade"}))
+	})
+
+	It("refuses to remove a directory that was not created for the upgrade", func() {
+		_, err := agent.DeleteSegmentDataDirectories(nil, &pb.DeleteSegmentDataDirRequest{
+			Datadirs: []string{"/data/primary_upgrade", "/data/primary"},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(removed).To(Equal([]string{"/data/primary_upgrade"}))
+	})
+
+	It("returns an error if a directory cannot be removed", func() {
+		utils.System.RemoveAll = func(name string) error {
+			return errors.New("permission denied")
+		}
+
+		_, err := agent.DeleteSegmentDataDirectories(nil, &pb.DeleteSegmentDataDirRequest{
+			Datadirs: []string{"/data/primary_upgrade"},
+		})
+		Expect(err).To(MatchError("permission denied"))
+	})
+})
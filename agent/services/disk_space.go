@@ -2,52 +2,180 @@ package services
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/cloudfoundry/gosigar"
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
 )
 
+// FirstNormalObjectId is the lowest OID, and so relfilenode, that can be
+// assigned to a user object; anything below it belongs to the catalog.
+const FirstNormalObjectId = 16384
+
+// relationFile matches the name of a relation's data file, capturing its
+// relfilenode: the main fork and its segments ("16385", "16385.1"), the other
+// forks ("16385_fsm"), and append-optimized segment files.
+var relationFile = regexp.MustCompile(`^([0-9]+)(_[a-z]+)?(\.[0-9]+)?$`)
+
 func (s *AgentServer) CheckDiskSpaceOnAgents(ctx context.Context, in *pb.CheckDiskSpaceRequestToAgent) (*pb.CheckDiskSpaceReplyFromAgent, error) {
 	gplog.Info("got a check disk command from the hub")
-	diskUsage, err := s.GetDiskUsage()
+
+	reply := &pb.CheckDiskSpaceReplyFromAgent{}
+	for _, pair := range in.DataDirPairs {
+		total, relations, err := s.GetDataDirSize(pair.OldDataDir)
+		if err != nil {
+			gplog.Error(err.Error())
+			return nil, err
+		}
+
+		filesystem, free, err := s.GetFilesystemSpace(pair.NewDataDir)
+		if err != nil {
+			gplog.Error(err.Error())
+			return nil, err
+		}
+
+		reply.Usages = append(reply.Usages, &pb.DataDirDiskUsage{
+			TotalBytes:       total,
+			RelationBytes:    relations,
+			TargetFilesystem: filesystem,
+			TargetFreeBytes:  free,
+		})
+	}
+
+	usage, err := s.GetDiskUsage()
 	if err != nil {
 		gplog.Error(err.Error())
 		return nil, err
 	}
-	var listDiskUsages []*pb.FileSysUsage
-	for k, v := range diskUsage {
-		listDiskUsages = append(listDiskUsages, &pb.FileSysUsage{Filesystem: k, Usage: v})
+	for filesystem, percent := range usage {
+		reply.ListOfFileSysUsage = append(reply.ListOfFileSysUsage, &pb.FileSysUsage{Filesystem: filesystem, Usage: percent})
 	}
-	return &pb.CheckDiskSpaceReplyFromAgent{ListOfFileSysUsage: listDiskUsages}, nil
+	sort.Slice(reply.ListOfFileSysUsage, func(i, j int) bool {
+		return reply.ListOfFileSysUsage[i].Filesystem < reply.ListOfFileSysUsage[j].Filesystem
+	})
+
+	return reply, nil
 }
 
-// diskUsage() wraps a pair of calls to the gosigar library.
-// This is local repetition of the sys_utils function pointer pattern. If there was more than one of these,
-// we would've refactored.
+// diskUsage returns how full, as a percentage, each mounted filesystem is.
 // "Adapted" from the gosigar usage example at https://github.com/cloudfoundry/gosigar/blob/master/examples/df.go
 func diskUsage() (map[string]float64, error) {
 	diskUsagePerFS := make(map[string]float64)
 	fslist := sigar.FileSystemList{}
 	err := fslist.Get()
 	if err != nil {
-		gplog.Error(err.Error())
-		return nil, err
+		return nil, errors.Wrap(err, "could not list filesystems")
 	}
 
 	for _, fs := range fslist.List {
-		dirName := fs.DirName
-
 		usage := sigar.FileSystemUsage{}
+		err = usage.Get(fs.DirName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get the usage of %s", fs.DirName)
+		}
+		diskUsagePerFS[fs.DirName] = usage.UsePercent()
+	}
+	return diskUsagePerFS, nil
+}
+
+// dataDirSize adds up the sizes of the files in a data directory, and
+// separately of the user relation files among them, which pg_upgrade's link
+// mode hard-links instead of copying. Tablespaces outside the data directory
+// aren't followed.
+func dataDirSize(dataDir string) (total uint64, relations uint64, err error) {
+	baseDir := filepath.Join(dataDir, "base") + string(filepath.Separator)
 
-		err = usage.Get(dirName)
+	err = filepath.Walk(dataDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			gplog.Error(err.Error())
-			return nil, err
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
 		}
 
-		diskUsagePerFS[dirName] = usage.UsePercent()
+		size := uint64(info.Size())
+		total += size
+		if strings.HasPrefix(path, baseDir) && IsUserRelationFile(info.Name()) {
+			relations += size
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "could not measure data directory %s", dataDir)
 	}
-	return diskUsagePerFS, nil
+	return total, relations, nil
+}
+
+// IsUserRelationFile returns whether a file in a database directory holds the
+// data of a user relation, as opposed to a catalog table or bookkeeping.
+func IsUserRelationFile(name string) bool {
+	match := relationFile.FindStringSubmatch(name)
+	if match == nil {
+		return false
+	}
+	relfilenode, err := strconv.ParseUint(match[1], 10, 32)
+	return err == nil && relfilenode >= FirstNormalObjectId
+}
+
+// filesystemSpace finds the filesystem that holds path, or will hold it once
+// it's created, and returns its mount point along with the bytes available on
+// it. It wraps a pair of calls to the gosigar library, "adapted" from the
+// usage example at https://github.com/cloudfoundry/gosigar/blob/master/examples/df.go
+func filesystemSpace(path string) (string, uint64, error) {
+	dir, err := existingAncestor(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	usage := sigar.FileSystemUsage{}
+	err = usage.Get(dir)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "could not get the free space for %s", dir)
+	}
+
+	fslist := sigar.FileSystemList{}
+	err = fslist.Get()
+	if err != nil {
+		return "", 0, errors.Wrap(err, "could not list filesystems")
+	}
+
+	mountPoint := ""
+	for _, fs := range fslist.List {
+		if len(fs.DirName) > len(mountPoint) && isParent(fs.DirName, dir) {
+			mountPoint = fs.DirName
+		}
+	}
+
+	// gosigar reports sizes in kilobytes.
+	return mountPoint, usage.Avail * 1024, nil
+}
+
+// existingAncestor returns path if it exists, or else the closest directory
+// above it that does.
+func existingAncestor(path string) (string, error) {
+	dir := filepath.Clean(path)
+	for {
+		_, err := utils.System.Stat(dir)
+		if err == nil {
+			return dir, nil
+		} else if !os.IsNotExist(err) {
+			return "", errors.Wrapf(err, "directory %s cannot be checked", dir)
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// isParent returns whether dir is path or one of its parents.
+func isParent(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
 }
@@ -1,6 +1,10 @@
 package services_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
 	"github.com/greenplum-db/gpupgrade/agent/services"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
@@ -10,16 +14,23 @@ import (
 	"github.com/pkg/errors"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("CommandListener", func() {
 	var (
 		testLogFile *gbytes.Buffer
+		request     *pb.CheckDiskSpaceRequestToAgent
 	)
 
 	BeforeEach(func() {
 		_, _, testLogFile = testhelper.SetupTestLogger()
+
+		request = &pb.CheckDiskSpaceRequestToAgent{DataDirPairs: []*pb.DataDirPair{
+			{OldDataDir: "/data/seg0", NewDataDir: "/data_upgrade/seg0"},
+			{OldDataDir: "/data/seg1", NewDataDir: "/data_upgrade/seg1"},
+		}}
 	})
 
 	AfterEach(func() {
@@ -27,32 +38,102 @@ var _ = Describe("CommandListener", func() {
 		utils.System = utils.InitializeSystemFunctions()
 	})
 
-	It("returns information that a helper function got about filesystems", func() {
-		getDiskUsage := func() (map[string]float64, error) {
-			fakeDiskUsage := make(map[string]float64)
-			fakeDiskUsage["/data"] = 25.4
-			return fakeDiskUsage, nil
+	It("returns the size of each data directory and the space free for its target", func() {
+		listener := &services.AgentServer{
+			GetDiskUsage: func() (map[string]float64, error) {
+				return map[string]float64{"/data_upgrade": 42.5, "/": 10}, nil
+			},
+			GetDataDirSize: func(dataDir string) (uint64, uint64, error) {
+				if dataDir == "/data/seg0" {
+					return 1000, 800, nil
+				}
+				return 2000, 1500, nil
+			},
+			GetFilesystemSpace: func(path string) (string, uint64, error) {
+				return "/data_upgrade", 5000, nil
+			},
 		}
-		listener := &services.AgentServer{GetDiskUsage: getDiskUsage}
 
-		resp, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{})
-		Expect(err).To(BeNil())
-		for _, val := range resp.ListOfFileSysUsage {
-			if val.Filesystem == "/data" {
-				Expect(val.Usage).To(BeNumerically("~", 25.4, 0.001))
-				break
+		resp, err := listener.CheckDiskSpaceOnAgents(nil, request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Usages).To(Equal([]*pb.DataDirDiskUsage{
+			{TotalBytes: 1000, RelationBytes: 800, TargetFilesystem: "/data_upgrade", TargetFreeBytes: 5000},
+			{TotalBytes: 2000, RelationBytes: 1500, TargetFilesystem: "/data_upgrade", TargetFreeBytes: 5000},
+		}))
+		Expect(resp.ListOfFileSysUsage).To(Equal([]*pb.FileSysUsage{
+			{Filesystem: "/", Usage: 10},
+			{Filesystem: "/data_upgrade", Usage: 42.5},
+		}))
+	})
 
-			}
+	It("returns an error if a data directory cannot be measured", func() {
+		listener := &services.AgentServer{
+			GetDataDirSize: func(string) (uint64, uint64, error) {
+				return 0, 0, errors.New("fake error")
+			},
 		}
+		_, err := listener.CheckDiskSpaceOnAgents(nil, request)
+		Expect(err).To(HaveOccurred())
+		Expect(string(testLogFile.Contents())).To(ContainSubstring("fake error"))
 	})
 
-	It("returns an error if the helper function fails", func() {
-		getDiskUsage := func() (map[string]float64, error) {
-			return nil, errors.New("fake error")
+	It("returns an error if the free space cannot be found", func() {
+		listener := &services.AgentServer{
+			GetDataDirSize: func(string) (uint64, uint64, error) {
+				return 1000, 800, nil
+			},
+			GetFilesystemSpace: func(string) (string, uint64, error) {
+				return "", 0, errors.New("fake error")
+			},
 		}
-		listener := &services.AgentServer{GetDiskUsage: getDiskUsage}
-		_, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{})
+		_, err := listener.CheckDiskSpaceOnAgents(nil, request)
 		Expect(err).To(HaveOccurred())
 		Expect(string(testLogFile.Contents())).To(ContainSubstring("fake error"))
 	})
+
+	It("measures data directories, counting user relations separately", func() {
+		dir, err := ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		dataDir := filepath.Join(dir, "seg0")
+		files := map[string]int{
+			"global/pg_control":     10,
+			"base/1/1259":           20,
+			"base/16384/16385":      100,
+			"base/16384/16385.1":    200,
+			"base/16384/16385_vm":   5,
+			"base/16384/PG_VERSION": 3,
+		}
+		for name, size := range files {
+			path := filepath.Join(dataDir, name)
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(path, make([]byte, size), 0600)).To(Succeed())
+		}
+
+		listener := services.NewAgentServer(nil, services.AgentConfig{})
+		resp, err := listener.CheckDiskSpaceOnAgents(nil, &pb.CheckDiskSpaceRequestToAgent{
+			DataDirPairs: []*pb.DataDirPair{
+				{OldDataDir: dataDir, NewDataDir: filepath.Join(dir, "not", "created", "yet")},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Usages).To(HaveLen(1))
+		Expect(resp.Usages[0].TotalBytes).To(Equal(uint64(338)))
+		Expect(resp.Usages[0].RelationBytes).To(Equal(uint64(305)))
+		Expect(resp.Usages[0].TargetFilesystem).ToNot(BeEmpty())
+		Expect(resp.Usages[0].TargetFreeBytes).To(BeNumerically(">", 0))
+	})
+
+	DescribeTable("IsUserRelationFile",
+		func(name string, expected bool) {
+			Expect(services.IsUserRelationFile(name)).To(Equal(expected))
+		},
+		Entry("a user relation", "16385", true),
+		Entry("a segment of a user relation", "16385.12", true),
+		Entry("a fork of a user relation", "16385_fsm", true),
+		Entry("a catalog relation", "1259", false),
+		Entry("a version file", "PG_VERSION", false),
+		Entry("a temporary relation", "t3_16385", false),
+	)
 })
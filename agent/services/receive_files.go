@@ -0,0 +1,190 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+)
+
+// ReceiveFiles writes the files that the hub streams to it into the state
+// directory. Each file goes to a temporary file next to its destination, and
+// only replaces the destination once all of it has arrived and its checksum
+// matches, so a reader never sees half of a file.
+func (s *AgentServer) ReceiveFiles(stream pb.Agent_ReceiveFilesServer) error {
+	gplog.Info("got a request to receive files from the hub")
+
+	reply := &pb.ReceiveFilesReply{}
+	var current *incomingFile
+	defer func() {
+		if current != nil {
+			current.abort()
+		}
+	}()
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if chunk.Path != "" {
+			if current != nil {
+				err = current.finish()
+				if err != nil {
+					return err
+				}
+				reply.Paths = append(reply.Paths, current.name)
+				current = nil
+			}
+
+			current, err = newIncomingFile(s.conf.StateDir, chunk)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(chunk.Data) > 0 {
+			if current == nil {
+				return errors.New("received file data before the file it belongs to")
+			}
+			err = current.write(chunk.Data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if current != nil {
+		err := current.finish()
+		if err != nil {
+			return err
+		}
+		reply.Paths = append(reply.Paths, current.name)
+		current = nil
+	}
+
+	gplog.Info("received %d files from the hub", len(reply.Paths))
+	return stream.SendAndClose(reply)
+}
+
+// StateDirPath resolves a path that the hub gave relative to the state
+// directory, refusing any that would end up outside of it.
+func StateDirPath(stateDir, name string) (string, error) {
+	clean := filepath.Clean(name)
+	if filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("%s is not a path inside the state directory", name)
+	}
+	return filepath.Join(stateDir, clean), nil
+}
+
+type incomingFile struct {
+	name     string // as the hub gave it
+	path     string // where the file ends up
+	temp     *os.File
+	hash     hash.Hash
+	mode     os.FileMode
+	size     uint64
+	written  uint64
+	checksum string
+}
+
+func newIncomingFile(stateDir string, header *pb.ReceiveFilesRequest) (*incomingFile, error) {
+	path, err := StateDirPath(stateDir, header.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create %s", dir)
+	}
+
+	temp, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".")
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create a temporary file for %s", path)
+	}
+
+	mode := os.FileMode(header.Mode).Perm()
+	if mode == 0 {
+		mode = 0600
+	}
+
+	return &incomingFile{
+		name:     header.Path,
+		path:     path,
+		temp:     temp,
+		hash:     sha256.New(),
+		mode:     mode,
+		size:     header.Size,
+		checksum: header.Sha256,
+	}, nil
+}
+
+func (f *incomingFile) write(data []byte) error {
+	if f.written+uint64(len(data)) > f.size {
+		return errors.Errorf("received more than the %d bytes expected for %s", f.size, f.name)
+	}
+
+	_, err := f.temp.Write(data)
+	if err != nil {
+		return errors.Wrapf(err, "could not write %s", f.temp.Name())
+	}
+	f.hash.Write(data)
+	f.written += uint64(len(data))
+	return nil
+}
+
+// finish moves the file into place if it arrived intact, and cleans up after
+// it if not.
+func (f *incomingFile) finish() error {
+	if f.written != f.size {
+		f.abort()
+		return errors.Errorf("received %d of the %d bytes expected for %s", f.written, f.size, f.name)
+	}
+	if checksum := hex.EncodeToString(f.hash.Sum(nil)); checksum != f.checksum {
+		f.abort()
+		return errors.Errorf("%s has checksum %s, but %s was expected", f.name, checksum, f.checksum)
+	}
+
+	err := f.temp.Chmod(f.mode)
+	if err == nil {
+		err = f.temp.Sync()
+	}
+	if err != nil {
+		f.abort()
+		return errors.Wrapf(err, "could not write %s", f.temp.Name())
+	}
+
+	err = f.temp.Close()
+	if err != nil {
+		os.Remove(f.temp.Name())
+		return errors.Wrapf(err, "could not write %s", f.temp.Name())
+	}
+
+	err = os.Rename(f.temp.Name(), f.path)
+	if err != nil {
+		os.Remove(f.temp.Name())
+		return errors.Wrapf(err, "could not move %s into place", f.path)
+	}
+
+	return nil
+}
+
+func (f *incomingFile) abort() {
+	f.temp.Close()
+	os.Remove(f.temp.Name())
+}
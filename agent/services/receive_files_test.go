@@ -0,0 +1,217 @@
+package services_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	"github.com/greenplum-db/gpupgrade/agent/services"
+	hubservices "github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// receiveStream plays the hub's side of ReceiveFiles.
+type receiveStream struct {
+	grpc.ServerStream
+	chunks []*pb.ReceiveFilesRequest
+	reply  *pb.ReceiveFilesReply
+}
+
+func (s *receiveStream) Recv() (*pb.ReceiveFilesRequest, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *receiveStream) SendAndClose(reply *pb.ReceiveFilesReply) error {
+	s.reply = reply
+	return nil
+}
+
+// header starts a file with the given contents, which must then be sent as
+// Data.
+func header(path, contents string) *pb.ReceiveFilesRequest {
+	sum := sha256.Sum256([]byte(contents))
+	return &pb.ReceiveFilesRequest{
+		Path:   path,
+		Mode:   0644,
+		Size:   uint64(len(contents)),
+		Sha256: hex.EncodeToString(sum[:]),
+	}
+}
+
+func data(contents string) *pb.ReceiveFilesRequest {
+	return &pb.ReceiveFilesRequest{Data: []byte(contents)}
+}
+
+var _ = Describe("ReceiveFiles", func() {
+	var (
+		dir   string
+		agent *services.AgentServer
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		agent = services.NewAgentServer(nil, services.AgentConfig{StateDir: dir})
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	// leftovers are any temporary files that weren't cleaned up.
+	leftovers := func() []string {
+		matches, err := filepath.Glob(filepath.Join(dir, "pg_upgrade", ".*"))
+		Expect(err).ToNot(HaveOccurred())
+		return matches
+	}
+
+	It("writes each file into the state directory", func() {
+		stream := &receiveStream{chunks: []*pb.ReceiveFilesRequest{
+			header("pg_upgrade/first.sql", "first file"),
+			data("first "), data("file"),
+			header("pg_upgrade/second.sql", ""),
+		}}
+
+		err := agent.ReceiveFiles(stream)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stream.reply.Paths).To(Equal([]string{"pg_upgrade/first.sql", "pg_upgrade/second.sql"}))
+
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "pg_upgrade", "first.sql"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("first file"))
+
+		info, err := os.Stat(filepath.Join(dir, "pg_upgrade", "first.sql"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0644)))
+
+		contents, err = ioutil.ReadFile(filepath.Join(dir, "pg_upgrade", "second.sql"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(BeEmpty())
+
+		Expect(leftovers()).To(BeEmpty())
+	})
+
+	It("replaces a file that is already there", func() {
+		Expect(os.MkdirAll(filepath.Join(dir, "pg_upgrade"), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "pg_upgrade", "oids.sql"), []byte("old"), 0600)).To(Succeed())
+
+		err := agent.ReceiveFiles(&receiveStream{chunks: []*pb.ReceiveFilesRequest{
+			header("pg_upgrade/oids.sql", "new"), data("new"),
+		}})
+		Expect(err).ToNot(HaveOccurred())
+
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "pg_upgrade", "oids.sql"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("new"))
+	})
+
+	It("keeps the old file if the new one does not match its checksum", func() {
+		Expect(os.MkdirAll(filepath.Join(dir, "pg_upgrade"), 0700)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "pg_upgrade", "oids.sql"), []byte("old"), 0600)).To(Succeed())
+
+		err := agent.ReceiveFiles(&receiveStream{chunks: []*pb.ReceiveFilesRequest{
+			header("pg_upgrade/oids.sql", "new"), data("bad"),
+		}})
+		Expect(err).To(MatchError(ContainSubstring("pg_upgrade/oids.sql has checksum")))
+
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "pg_upgrade", "oids.sql"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("old"))
+		Expect(leftovers()).To(BeEmpty())
+	})
+
+	It("does not write a file that is cut short", func() {
+		err := agent.ReceiveFiles(&receiveStream{chunks: []*pb.ReceiveFilesRequest{
+			header("pg_upgrade/oids.sql", "contents"), data("cont"),
+		}})
+		Expect(err).To(MatchError("received 4 of the 8 bytes expected for pg_upgrade/oids.sql"))
+
+		_, err = os.Stat(filepath.Join(dir, "pg_upgrade", "oids.sql"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		Expect(leftovers()).To(BeEmpty())
+	})
+
+	It("does not write a file that runs long", func() {
+		err := agent.ReceiveFiles(&receiveStream{chunks: []*pb.ReceiveFilesRequest{
+			header("pg_upgrade/oids.sql", "short"), data("short"), data("er"),
+		}})
+		Expect(err).To(MatchError("received more than the 5 bytes expected for pg_upgrade/oids.sql"))
+		Expect(leftovers()).To(BeEmpty())
+	})
+
+	It("rejects data that does not belong to a file", func() {
+		err := agent.ReceiveFiles(&receiveStream{chunks: []*pb.ReceiveFilesRequest{data("stray")}})
+		Expect(err).To(MatchError("received file data before the file it belongs to"))
+	})
+
+	It("refuses to write outside of the state directory", func() {
+		for _, path := range []string{"/etc/passwd", "../outside", "pg_upgrade/../../outside", "."} {
+			err := agent.ReceiveFiles(&receiveStream{chunks: []*pb.ReceiveFilesRequest{header(path, "")}})
+			Expect(err).To(MatchError(path + " is not a path inside the state directory"))
+		}
+	})
+
+	It("receives the files that the hub sends", func() {
+		source, err := ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(source)
+
+		// Big enough to take several messages.
+		large := strings.Repeat("0123456789", hubservices.FileChunkSize/4)
+		Expect(ioutil.WriteFile(filepath.Join(source, "large"), []byte(large), 0640)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(source, "small"), []byte("small"), 0600)).To(Succeed())
+
+		port, err := testutils.GetOpenPort()
+		Expect(err).ToNot(HaveOccurred())
+		agent = services.NewAgentServer(nil, services.AgentConfig{Port: port, StateDir: dir})
+		go agent.Start()
+		defer agent.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := grpc.DialContext(ctx, "localhost:"+strconv.Itoa(port), grpc.WithInsecure(), grpc.WithBlock())
+		Expect(err).ToNot(HaveOccurred())
+		defer conn.Close()
+
+		err = hubservices.SendFiles(context.Background(), pb.NewAgentClient(conn), []hubservices.FileToSend{
+			{Source: filepath.Join(source, "large"), Destination: "pg_upgrade/large"},
+			{Source: filepath.Join(source, "small"), Destination: "pg_upgrade/small"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "pg_upgrade", "large"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal(large))
+
+		info, err := os.Stat(filepath.Join(dir, "pg_upgrade", "large"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0640)))
+
+		contents, err = ioutil.ReadFile(filepath.Join(dir, "pg_upgrade", "small"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("small"))
+	})
+})
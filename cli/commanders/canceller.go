@@ -0,0 +1,58 @@
+package commanders
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+)
+
+// The steps that can be cancelled, by the names that `gpupgrade upgrade` gives
+// them.
+var cancellableSteps = map[string]pb.UpgradeSteps{
+	"convert-master":    pb.UpgradeSteps_CONVERT_MASTER,
+	"convert-primaries": pb.UpgradeSteps_CONVERT_PRIMARIES,
+}
+
+type Canceller struct {
+	client pb.CliToHubClient
+}
+
+func NewCanceller(client pb.CliToHubClient) *Canceller {
+	return &Canceller{client: client}
+}
+
+// Cancel asks the hub to stop the pg_upgrade processes of a running step, so
+// that the step fails and can be retried.
+func (c *Canceller) Cancel(step string) error {
+	code, ok := cancellableSteps[step]
+	if !ok {
+		return fmt.Errorf("%s cannot be cancelled; only convert-master and convert-primaries can", step)
+	}
+
+	reply, err := c.client.Cancel(context.Background(), &pb.CancelRequest{Step: code})
+	if err != nil {
+		return errors.Wrapf(err, "hub returned an error while cancelling %s", step)
+	}
+
+	if OutputFormat != FormatText {
+		output := CancelOutput{Step: step, Processes: []CancelledProcessOutput{}}
+		for _, process := range reply.GetProcesses() {
+			output.Processes = append(output.Processes, CancelledProcessOutput{
+				Hostname: process.Hostname,
+				Content:  process.Content,
+				Pid:      process.Pid,
+			})
+		}
+		return WriteOutput(output)
+	}
+
+	for _, process := range reply.GetProcesses() {
+		gplog.Info("Stopped pg_upgrade for segment %d on %s (pid %d)", process.Content, process.Hostname, process.Pid)
+	}
+	gplog.Info("Cancelled %s; it can be run again, or the upgrade reverted", step)
+	return nil
+}
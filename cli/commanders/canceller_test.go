@@ -0,0 +1,89 @@
+package commanders_test
+
+import (
+	"errors"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+
+	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("canceller", func() {
+	var (
+		client     *mockpb.MockCliToHubClient
+		ctrl       *gomock.Controller
+		testStdout *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		testStdout, _, _ = testhelper.SetupTestLogger()
+
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+	})
+
+	AfterEach(func() {
+		commanders.OutputWriter = os.Stdout
+		commanders.OutputFormat = commanders.FormatText
+		defer ctrl.Finish()
+	})
+
+	It("asks the hub to cancel the step, and reports what it stopped", func() {
+		client.EXPECT().Cancel(
+			gomock.Any(),
+			&pb.CancelRequest{Step: pb.UpgradeSteps_CONVERT_PRIMARIES},
+		).Return(&pb.CancelReply{Processes: []*pb.CancelledProcess{
+			{Hostname: "sdw1", Content: 0, Pid: 4242},
+		}}, nil)
+
+		err := commanders.NewCanceller(client).Cancel("convert-primaries")
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(testStdout).Should(gbytes.Say(`Stopped pg_upgrade for segment 0 on sdw1 \(pid 4242\)`))
+		Eventually(testStdout).Should(gbytes.Say("Cancelled convert-primaries"))
+	})
+
+	It("reports what it stopped as structured output", func() {
+		output := gbytes.NewBuffer()
+		commanders.OutputWriter = output
+		commanders.OutputFormat = commanders.FormatJSON
+
+		client.EXPECT().Cancel(
+			gomock.Any(),
+			&pb.CancelRequest{Step: pb.UpgradeSteps_CONVERT_MASTER},
+		).Return(&pb.CancelReply{Processes: []*pb.CancelledProcess{
+			{Hostname: "mdw", Content: -1, Pid: 4242},
+		}}, nil)
+
+		err := commanders.NewCanceller(client).Cancel("convert-master")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(string(output.Contents())).To(MatchJSON(`{
+			"step": "convert-master",
+			"processes": [{"hostname": "mdw", "content": -1, "pid": 4242}]
+		}`))
+	})
+
+	It("refuses to cancel a step that cannot be cancelled", func() {
+		err := commanders.NewCanceller(client).Cancel("share-oids")
+		Expect(err).To(MatchError(ContainSubstring("share-oids cannot be cancelled")))
+	})
+
+	It("returns an error when the hub returns an error", func() {
+		client.EXPECT().Cancel(
+			gomock.Any(),
+			&pb.CancelRequest{Step: pb.UpgradeSteps_CONVERT_MASTER},
+		).Return(nil, errors.New("hub error"))
+
+		err := commanders.NewCanceller(client).Cancel("convert-master")
+		Expect(err).To(MatchError(ContainSubstring("hub error")))
+	})
+})
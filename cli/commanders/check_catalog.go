@@ -0,0 +1,75 @@
+package commanders
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+type CatalogChecker struct {
+	client pb.CliToHubClient
+}
+
+func NewCatalogChecker(client pb.CliToHubClient) CatalogChecker {
+	return CatalogChecker{client: client}
+}
+
+// Execute reports every object that the hub's catalog checks found, and
+// returns an error if there were any, since the upgrade would fail on them.
+func (req CatalogChecker) Execute() error {
+	reply, err := req.client.CheckCatalog(context.Background(),
+		&pb.CheckCatalogRequest{})
+	if err != nil {
+		gplog.Error("ERROR - gRPC call to hub failed")
+		return err
+	}
+
+	issues := reply.GetIssues()
+	if OutputFormat != FormatText {
+		output := CatalogCheckOutput{
+			Databases: reply.GetDatabases(),
+			Issues:    []CatalogIssueOutput{},
+		}
+		if output.Databases == nil {
+			output.Databases = []string{}
+		}
+		for _, issue := range issues {
+			output.Issues = append(output.Issues, CatalogIssueOutput{
+				Check:       issue.GetCheck(),
+				Database:    issue.GetDatabase(),
+				Object:      issue.GetObject(),
+				Detail:      issue.GetDetail(),
+				Remediation: issue.GetRemediation(),
+			})
+		}
+		err = WriteOutput(output)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, issue := range issues {
+			gplog.Info("%s - database %s - %s: %s", issue.GetCheck(), issue.GetDatabase(), issue.GetObject(), issue.GetDetail())
+			gplog.Info("    %s", issue.GetRemediation())
+		}
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("found %d objects in %d databases that cannot be upgraded", len(issues), countDatabases(issues))
+	}
+
+	if OutputFormat == FormatText {
+		gplog.Info("Catalog check passed for %d databases", len(reply.GetDatabases()))
+	}
+	return nil
+}
+
+func countDatabases(issues []*pb.CatalogIssue) int {
+	databases := make(map[string]bool)
+	for _, issue := range issues {
+		databases[issue.GetDatabase()] = true
+	}
+	return len(databases)
+}
@@ -0,0 +1,80 @@
+package commanders_test
+
+import (
+	"errors"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+
+	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("catalog check", func() {
+	var (
+		client *mockpb.MockCliToHubClient
+		ctrl   *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("reports that every database passed", func() {
+		testStdout, _, _ := testhelper.SetupTestLogger()
+
+		client.EXPECT().CheckCatalog(
+			gomock.Any(),
+			&pb.CheckCatalogRequest{},
+		).Return(&pb.CheckCatalogReply{Databases: []string{"template1", "postgres"}}, nil)
+
+		err := commanders.NewCatalogChecker(client).Execute()
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(testStdout).Should(gbytes.Say("Catalog check passed for 2 databases"))
+	})
+
+	It("reports each object that cannot be upgraded and returns an error", func() {
+		testStdout, _, _ := testhelper.SetupTestLogger()
+
+		client.EXPECT().CheckCatalog(
+			gomock.Any(),
+			&pb.CheckCatalogRequest{},
+		).Return(&pb.CheckCatalogReply{
+			Databases: []string{"template1", "postgres"},
+			Issues: []*pb.CatalogIssue{
+				{Check: "unsupported-column-types", Database: "postgres", Object: "public.t.c", Detail: "regproc", Remediation: "Drop the column."},
+				{Check: "external-tables-unsupported-protocols", Database: "postgres", Object: "public.ext", Detail: "gphdfs://host/path", Remediation: "Use PXF."},
+			},
+		}, nil)
+
+		err := commanders.NewCatalogChecker(client).Execute()
+		Expect(err).To(MatchError("found 2 objects in 1 databases that cannot be upgraded"))
+		Eventually(testStdout).Should(gbytes.Say("unsupported-column-types - database postgres - public.t.c: regproc"))
+		Eventually(testStdout).Should(gbytes.Say("Drop the column."))
+		Eventually(testStdout).Should(gbytes.Say("external-tables-unsupported-protocols - database postgres - public.ext: gphdfs://host/path"))
+		Eventually(testStdout).Should(gbytes.Say("Use PXF."))
+	})
+
+	It("returns an error when the hub cannot be reached", func() {
+		_, testStderr, _ := testhelper.SetupTestLogger()
+
+		client.EXPECT().CheckCatalog(
+			gomock.Any(),
+			&pb.CheckCatalogRequest{},
+		).Return(nil, errors.New("Force failure connection"))
+
+		err := commanders.NewCatalogChecker(client).Execute()
+		Expect(err).To(HaveOccurred())
+		Eventually(testStderr).Should(gbytes.Say("ERROR - gRPC call to hub failed"))
+	})
+})
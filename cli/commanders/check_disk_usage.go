@@ -2,8 +2,10 @@ package commanders
 
 import (
 	"context"
+	"fmt"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 )
@@ -16,6 +18,9 @@ func NewDiskSpaceChecker(client pb.CliToHubClient) DiskSpaceChecker {
 	return DiskSpaceChecker{client: client}
 }
 
+// Execute reports, for each segment, the disk space its upgrade is projected
+// to need in copy and in link mode, and returns an error if any segment
+// doesn't have room for an upgrade in the configured mode.
 func (req DiskSpaceChecker) Execute() error {
 	reply, err := req.client.CheckDiskSpace(context.Background(),
 		&pb.CheckDiskSpaceRequest{})
@@ -24,10 +29,145 @@ func (req DiskSpaceChecker) Execute() error {
 		return err
 	}
 
-	//TODO: do we want to report results to the user earlier? Should we make a gRPC call per db?
-	for _, segmentFileSysUsage := range reply.SegmentFileSysUsage {
-		gplog.Info(segmentFileSysUsage)
+	mode := reply.GetMode()
+	if mode == "" {
+		mode = utils.COPY_MODE
+	}
+
+	segments := reply.GetSegments()
+	failed, linkFits := 0, true
+	for _, segment := range segments {
+		need := segment.GetCopy()
+		if mode == utils.LINK_MODE {
+			need = segment.GetLink()
+		}
+		if segment.GetError() != "" || !need.GetFits() {
+			failed++
+		}
+		if segment.GetError() != "" || !segment.GetLink().GetFits() {
+			linkFits = false
+		}
+	}
+
+	if OutputFormat != FormatText {
+		output := DiskSpaceOutput{Hosts: []HostDiskSpaceOutput{}, Segments: []SegmentDiskSpaceOutput{}}
+		for _, host := range reply.GetHostDiskUsages() {
+			hostOutput := HostDiskSpaceOutput{
+				Hostname:    host.GetHostname(),
+				Filesystems: []FilesystemUsageOutput{},
+				Error:       host.GetError(),
+			}
+			for _, usage := range host.GetListOfFileSysUsage() {
+				hostOutput.Filesystems = append(hostOutput.Filesystems, FilesystemUsageOutput{
+					Filesystem:   usage.GetFilesystem(),
+					UsagePercent: usage.GetUsage(),
+				})
+			}
+			output.Hosts = append(output.Hosts, hostOutput)
+		}
+		for _, segment := range segments {
+			output.Segments = append(output.Segments, SegmentDiskSpaceOutput{
+				Hostname:      segment.GetHostname(),
+				Content:       segment.GetContent(),
+				Role:          segment.GetRole(),
+				DataDir:       segment.GetDataDir(),
+				TargetDataDir: segment.GetTargetDataDir(),
+				DataDirBytes:  segment.GetDataDirBytes(),
+				Filesystem:    segment.GetFilesystem(),
+				FreeBytes:     segment.GetFreeBytes(),
+				Copy:          diskSpaceNeedOutput(segment.GetCopy()),
+				Link:          diskSpaceNeedOutput(segment.GetLink()),
+				Error:         segment.GetError(),
+			})
+		}
+		err = WriteOutput(output)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, host := range reply.GetHostDiskUsages() {
+			for _, line := range describeHostDiskUsage(host) {
+				gplog.Info("%s", line)
+			}
+		}
+		for _, segment := range segments {
+			gplog.Info(describeSegmentDiskSpace(segment))
+		}
+	}
+
+	if failed > 0 {
+		if mode == utils.COPY_MODE && linkFits {
+			gplog.Info("Every segment has room for a link-mode upgrade, which hard-links user data instead of copying it. " +
+				"Use `gpupgrade config set --mode=link` to choose it.")
+		}
+		return fmt.Errorf("%d of the %d segments do not have enough disk space for a %s-mode upgrade", failed, len(segments), mode)
+	}
+
+	if OutputFormat == FormatText {
+		gplog.Info("Disk space check passed: every segment has room for a %s-mode upgrade", mode)
 	}
-	gplog.Info("Check disk space request is processed.")
 	return nil
 }
+
+func diskSpaceNeedOutput(need *pb.DiskSpaceNeed) DiskSpaceNeedOutput {
+	return DiskSpaceNeedOutput{
+		Bytes:           need.GetBytes(),
+		FilesystemBytes: need.GetFilesystemBytes(),
+		Fits:            need.GetFits(),
+	}
+}
+
+// describeHostDiskUsage renders how full each filesystem on a host is, one
+// line per filesystem.
+func describeHostDiskUsage(host *pb.HostDiskUsage) []string {
+	prefix := fmt.Sprintf("diskspace check - %s", host.GetHostname())
+	if host.GetError() != "" {
+		return []string{fmt.Sprintf("%s - could not be checked: %s", prefix, host.GetError())}
+	}
+
+	var lines []string
+	for _, usage := range host.GetListOfFileSysUsage() {
+		lines = append(lines, fmt.Sprintf("%s - %s is %.1f%% full", prefix, usage.GetFilesystem(), usage.GetUsage()))
+	}
+	return lines
+}
+
+func describeSegmentDiskSpace(segment *pb.SegmentDiskSpace) string {
+	prefix := fmt.Sprintf("diskspace check - %s - %s %d", segment.GetHostname(), segment.GetRole(), segment.GetContent())
+	if segment.GetError() != "" {
+		return fmt.Sprintf("%s - could not be checked: %s", prefix, segment.GetError())
+	}
+
+	verdict := func(need *pb.DiskSpaceNeed) string {
+		if need.GetFits() {
+			return "OK"
+		}
+		return "FAILED"
+	}
+
+	return fmt.Sprintf("%s - %s in copy mode, %s in link mode: %s is %s and needs %s (copy) or %s (link) more; "+
+		"%s has %s free and all segments on it need %s (copy) or %s (link)",
+		prefix, verdict(segment.GetCopy()), verdict(segment.GetLink()),
+		segment.GetDataDir(), FormatBytes(segment.GetDataDirBytes()),
+		FormatBytes(segment.GetCopy().GetBytes()), FormatBytes(segment.GetLink().GetBytes()),
+		segment.GetFilesystem(), FormatBytes(segment.GetFreeBytes()),
+		FormatBytes(segment.GetCopy().GetFilesystemBytes()), FormatBytes(segment.GetLink().GetFilesystemBytes()))
+}
+
+// FormatBytes renders a byte count in the largest binary unit that keeps it
+// at or above one, such as "1.5 GiB".
+func FormatBytes(bytes uint64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+
+	value := float64(bytes)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
@@ -11,10 +11,11 @@ import (
 	"github.com/pkg/errors"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
-var _ = Describe("object count tests", func() {
+var _ = Describe("disk space check", func() {
 
 	var (
 		client *mockpb.MockCliToHubClient
@@ -45,28 +46,133 @@ var _ = Describe("object count tests", func() {
 			Expect(err).ToNot(BeNil())
 			Expect(string(testLogFile.Contents())).To(ContainSubstring("ERROR - gRPC call to hub failed"))
 		})
-		It("prints out the results of disk usage check from gRPC reply", func() {
+		It("reports each segment and passes if all of them fit in copy mode", func() {
 			testStdout, _, _ := testhelper.SetupTestLogger()
 
-			var expectedFilesystemsUsage []string
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, "diskspace check - hostC  - Couldn't connect")
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, "diskspace check - hostA  - OK")
-			//to log a percent sign, use %% to avoid logger substitution
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, "diskspace check - hostD  - WARNING /data 90%% use")
+			client.EXPECT().CheckDiskSpace(
+				gomock.Any(),
+				&pb.CheckDiskSpaceRequest{},
+			).Return(&pb.CheckDiskSpaceReply{Segments: []*pb.SegmentDiskSpace{{
+				Hostname:     "sdw1",
+				Content:      0,
+				Role:         "primary",
+				DataDir:      "/data/seg0",
+				DataDirBytes: 3 << 30,
+				Filesystem:   "/data",
+				FreeBytes:    10 << 30,
+				Copy:         &pb.DiskSpaceNeed{Bytes: 3 << 30, FilesystemBytes: 6 << 30, Fits: true},
+				Link:         &pb.DiskSpaceNeed{Bytes: 512 << 20, FilesystemBytes: 1 << 30, Fits: true},
+			}}}, nil)
+
+			err := commanders.NewDiskSpaceChecker(client).Execute()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(string(testStdout.Contents())).To(ContainSubstring(
+				"diskspace check - sdw1 - primary 0 - OK in copy mode, OK in link mode: " +
+					"/data/seg0 is 3.0 GiB and needs 3.0 GiB (copy) or 512.0 MiB (link) more; " +
+					"/data has 10.0 GiB free and all segments on it need 6.0 GiB (copy) or 1.0 GiB (link)"))
+			Expect(string(testStdout.Contents())).To(ContainSubstring("Disk space check passed"))
+		})
+
+		It("reports how full each filesystem on each host is", func() {
+			testStdout, _, _ := testhelper.SetupTestLogger()
 
 			client.EXPECT().CheckDiskSpace(
 				gomock.Any(),
 				&pb.CheckDiskSpaceRequest{},
-			).Return(&pb.CheckDiskSpaceReply{SegmentFileSysUsage: expectedFilesystemsUsage}, nil)
+			).Return(&pb.CheckDiskSpaceReply{
+				HostDiskUsages: []*pb.HostDiskUsage{
+					{Hostname: "sdw1", ListOfFileSysUsage: []*pb.FileSysUsage{
+						{Filesystem: "/data", Usage: 42.5},
+						{Filesystem: "/", Usage: 10},
+					}},
+					{Hostname: "sdw2", Error: "could not connect to the agent: connection refused"},
+				},
+				Segments: []*pb.SegmentDiskSpace{
+					{Hostname: "sdw2", Role: "primary", Error: "could not connect to the agent: connection refused"},
+				},
+			}, nil)
 
-			request := commanders.NewDiskSpaceChecker(client)
-			err := request.Execute()
+			err := commanders.NewDiskSpaceChecker(client).Execute()
+			Expect(err).To(HaveOccurred())
 
-			Expect(err).To(BeNil())
-			Expect(string(testStdout.Contents())).To(ContainSubstring("diskspace check - hostC  - Couldn't connect"))
-			Expect(string(testStdout.Contents())).To(ContainSubstring("diskspace check - hostA  - OK"))
-			Expect(string(testStdout.Contents())).To(ContainSubstring("diskspace check - hostD  - WARNING /data 90% use"))
+			Expect(string(testStdout.Contents())).To(ContainSubstring("diskspace check - sdw1 - /data is 42.5% full"))
+			Expect(string(testStdout.Contents())).To(ContainSubstring("diskspace check - sdw1 - / is 10.0% full"))
+			Expect(string(testStdout.Contents())).To(ContainSubstring("diskspace check - sdw2 - could not be checked: could not connect to the agent: connection refused"))
+		})
 
+		It("returns an error if a segment does not fit in copy mode", func() {
+			testStdout, _, _ := testhelper.SetupTestLogger()
+
+			client.EXPECT().CheckDiskSpace(
+				gomock.Any(),
+				&pb.CheckDiskSpaceRequest{},
+			).Return(&pb.CheckDiskSpaceReply{Segments: []*pb.SegmentDiskSpace{
+				{
+					Hostname: "sdw1",
+					Role:     "primary",
+					Copy:     &pb.DiskSpaceNeed{Fits: false},
+					Link:     &pb.DiskSpaceNeed{Fits: true},
+				},
+				{
+					Hostname: "sdw2",
+					Content:  1,
+					Role:     "primary",
+					Copy:     &pb.DiskSpaceNeed{Fits: true},
+					Link:     &pb.DiskSpaceNeed{Fits: true},
+				},
+			}}, nil)
+
+			err := commanders.NewDiskSpaceChecker(client).Execute()
+			Expect(err).To(MatchError("1 of the 2 segments do not have enough disk space for a copy-mode upgrade"))
+			Expect(string(testStdout.Contents())).To(ContainSubstring("sdw1 - primary 0 - FAILED in copy mode, OK in link mode"))
+			Expect(string(testStdout.Contents())).To(ContainSubstring("Every segment has room for a link-mode upgrade"))
+		})
+
+		It("judges segments by link mode when that is the configured mode", func() {
+			testStdout, _, _ := testhelper.SetupTestLogger()
+
+			client.EXPECT().CheckDiskSpace(
+				gomock.Any(),
+				&pb.CheckDiskSpaceRequest{},
+			).Return(&pb.CheckDiskSpaceReply{Mode: "link", Segments: []*pb.SegmentDiskSpace{
+				{
+					Hostname: "sdw1",
+					Role:     "primary",
+					Copy:     &pb.DiskSpaceNeed{Fits: false},
+					Link:     &pb.DiskSpaceNeed{Fits: true},
+				},
+			}}, nil)
+
+			err := commanders.NewDiskSpaceChecker(client).Execute()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(testStdout.Contents())).To(ContainSubstring("every segment has room for a link-mode upgrade"))
+		})
+
+		It("returns an error if a segment could not be checked", func() {
+			testStdout, _, _ := testhelper.SetupTestLogger()
+
+			client.EXPECT().CheckDiskSpace(
+				gomock.Any(),
+				&pb.CheckDiskSpaceRequest{},
+			).Return(&pb.CheckDiskSpaceReply{Segments: []*pb.SegmentDiskSpace{
+				{Hostname: "sdw1", Role: "mirror", Error: "connection refused"},
+			}}, nil)
+
+			err := commanders.NewDiskSpaceChecker(client).Execute()
+			Expect(err).To(HaveOccurred())
+			Expect(string(testStdout.Contents())).To(ContainSubstring("diskspace check - sdw1 - mirror 0 - could not be checked: connection refused"))
+			Expect(string(testStdout.Contents())).ToNot(ContainSubstring("link-mode"))
 		})
 	})
+
+	DescribeTable("FormatBytes",
+		func(bytes uint64, expected string) {
+			Expect(commanders.FormatBytes(bytes)).To(Equal(expected))
+		},
+		Entry("bytes", uint64(1023), "1023 B"),
+		Entry("kibibytes", uint64(1536), "1.5 KiB"),
+		Entry("gibibytes", uint64(5<<30), "5.0 GiB"),
+		Entry("pebibytes", uint64(2048<<50), "2048.0 PiB"),
+	)
 })
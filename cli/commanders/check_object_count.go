@@ -23,6 +23,18 @@ func (req ObjectCountChecker) Execute() error {
 		gplog.Error("ERROR - gRPC call to hub failed")
 		return err
 	}
+	if OutputFormat != FormatText {
+		output := ObjectCountOutput{Databases: []DatabaseObjectCountOutput{}}
+		for _, count := range reply.ListOfCounts {
+			output.Databases = append(output.Databases, DatabaseObjectCountOutput{
+				Name:      count.DbName,
+				AOCount:   count.AoCount,
+				HeapCount: count.HeapCount,
+			})
+		}
+		return WriteOutput(output)
+	}
+
 	//TODO: do we want to report results to the user earlier? Should we make a gRPC call per db?
 	for _, count := range reply.ListOfCounts {
 		gplog.Info("Checking object counts in database: %s", count.DbName)
@@ -0,0 +1,78 @@
+package commanders
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+type PortChecker struct {
+	client pb.CliToHubClient
+}
+
+func NewPortChecker(client pb.CliToHubClient) PortChecker {
+	return PortChecker{client: client}
+}
+
+// Execute reports every planned target port that is already in use, and
+// returns an error if there were any, since gpinitsystem would fail on them.
+func (req PortChecker) Execute() error {
+	reply, err := req.client.CheckPorts(context.Background(),
+		&pb.CheckPortsRequest{})
+	if err != nil {
+		gplog.Error("ERROR - gRPC call to hub failed")
+		return err
+	}
+
+	ports := reply.GetPorts()
+	inUse := 0
+	for _, port := range ports {
+		if port.GetStatus().GetInUse() {
+			inUse++
+		}
+	}
+
+	if OutputFormat != FormatText {
+		output := PortCheckOutput{Ports: []PortStatusOutput{}}
+		for _, port := range ports {
+			status := port.GetStatus()
+			output.Ports = append(output.Ports, PortStatusOutput{
+				Hostname: port.GetHostname(),
+				Content:  port.GetContent(),
+				Port:     status.GetPort(),
+				InUse:    status.GetInUse(),
+				Pid:      status.GetPid(),
+				Process:  status.GetProcess(),
+			})
+		}
+		err = WriteOutput(output)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, port := range ports {
+			status := port.GetStatus()
+			if !status.GetInUse() {
+				continue
+			}
+			holder := ""
+			if status.GetPid() != 0 {
+				holder = fmt.Sprintf(" by %s (pid %d)", status.GetProcess(), status.GetPid())
+			}
+			gplog.Info("ports check - %s - port %d for content %d is in use%s",
+				port.GetHostname(), status.GetPort(), port.GetContent(), holder)
+		}
+	}
+
+	if inUse > 0 {
+		return fmt.Errorf("%d of the %d ports planned for the target cluster are in use", inUse, len(ports))
+	}
+
+	if OutputFormat == FormatText {
+		gplog.Info("Ports check passed: all %d ports planned for the target cluster are free", len(ports))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package commanders_test
+
+import (
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+
+	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("ports check", func() {
+	var (
+		client *mockpb.MockCliToHubClient
+		ctrl   *gomock.Controller
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("reports that every port is free", func() {
+		testStdout, _, _ := testhelper.SetupTestLogger()
+
+		client.EXPECT().CheckPorts(
+			gomock.Any(),
+			&pb.CheckPortsRequest{},
+		).Return(&pb.CheckPortsReply{Ports: []*pb.TargetPortStatus{
+			{Hostname: "mdw", Content: -1, Status: &pb.PortStatus{Port: 15433}},
+			{Hostname: "sdw1", Content: 0, Status: &pb.PortStatus{Port: 27432}},
+		}}, nil)
+
+		err := commanders.NewPortChecker(client).Execute()
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(testStdout).Should(gbytes.Say("all 2 ports planned for the target cluster are free"))
+	})
+
+	It("reports each port in use and returns an error", func() {
+		testStdout, _, _ := testhelper.SetupTestLogger()
+
+		client.EXPECT().CheckPorts(
+			gomock.Any(),
+			&pb.CheckPortsRequest{},
+		).Return(&pb.CheckPortsReply{Ports: []*pb.TargetPortStatus{
+			{Hostname: "mdw", Content: -1, Status: &pb.PortStatus{Port: 15433, InUse: true}},
+			{Hostname: "sdw1", Content: 0, Status: &pb.PortStatus{Port: 27432, InUse: true, Pid: 99, Process: "java"}},
+			{Hostname: "sdw1", Content: 1, Status: &pb.PortStatus{Port: 27433}},
+		}}, nil)
+
+		err := commanders.NewPortChecker(client).Execute()
+		Expect(err).To(MatchError("2 of the 3 ports planned for the target cluster are in use"))
+		Eventually(testStdout).Should(gbytes.Say("mdw - port 15433 for content -1 is in use"))
+		Eventually(testStdout).Should(gbytes.Say(`sdw1 - port 27432 for content 0 is in use by java \(pid 99\)`))
+	})
+})
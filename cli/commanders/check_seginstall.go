@@ -16,10 +16,10 @@ func NewSeginstallChecker(client pb.CliToHubClient) SeginstallChecker {
 	}
 }
 
-func (req SeginstallChecker) Execute() error {
+func (req SeginstallChecker) Execute(skipPrerequisites bool) error {
 	_, err := req.client.CheckSeginstall(
 		context.Background(),
-		&pb.CheckSeginstallRequest{},
+		&pb.CheckSeginstallRequest{SkipPrerequisites: skipPrerequisites},
 	)
 	return err
 }
@@ -21,14 +21,14 @@ var _ = Describe("CheckSeginstall", func() {
 	})
 
 	It("makes a CheckSeginstallRequest to the hub", func() {
-		err := segChecker.Execute()
+		err := segChecker.Execute(false)
 		Expect(spyClient.checkSeginstallCount).To(Equal(1))
 		Expect(err).ToNot(HaveOccurred())
 	})
 
 	It("returns an error when CheckSeginstallRequest fails", func() {
 		spyClient.err = errors.New("some error")
-		err := segChecker.Execute()
+		err := segChecker.Execute(false)
 		Expect(err).To(HaveOccurred())
 	})
 })
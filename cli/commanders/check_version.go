@@ -25,6 +25,10 @@ func (req VersionChecker) Execute() error {
 		gplog.Error("ERROR - gRPC call to hub failed")
 		return err
 	}
+	if OutputFormat != FormatText {
+		return WriteOutput(VersionCheckOutput{Compatible: resp.IsVersionCompatible})
+	}
+
 	if resp.IsVersionCompatible {
 		gplog.Info("gpupgrade: Version Compatibility Check [OK]\n")
 	} else {
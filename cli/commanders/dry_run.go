@@ -0,0 +1,100 @@
+package commanders
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// ReportPlans shows what a dry run found the hub would do, without anything
+// having been done. Plans for checklist steps are labeled with the step; a
+// plan that isn't for a single step, such as revert's, is not.
+func ReportPlans(plans ...*pb.DryRunPlan) error {
+	if OutputFormat != FormatText {
+		output := DryRunOutput{Steps: []DryRunStepOutput{}}
+		for _, plan := range plans {
+			output.Steps = append(output.Steps, newDryRunStepOutput(plan))
+		}
+		return WriteOutput(output)
+	}
+
+	gplog.Info("Dry run; nothing has been changed")
+	for _, plan := range plans {
+		for _, line := range FormatPlan(plan) {
+			gplog.Info(line)
+		}
+	}
+	return nil
+}
+
+// FormatPlan renders a plan as lines of text: a heading naming the step, if
+// any, then each command prefixed by the host it would run on, then the
+// contents of each file that would be written, then any notes.
+func FormatPlan(plan *pb.DryRunPlan) []string {
+	var lines []string
+	indent := ""
+	if plan.GetStep() != pb.UpgradeSteps_UNKNOWN_STEP {
+		lines = append(lines, fmt.Sprintf("%v %s:", plan.GetStep(), UpgradeStepsMessage[plan.GetStep()]))
+		indent = "  "
+	}
+
+	for _, command := range plan.GetCommands() {
+		lines = append(lines, fmt.Sprintf("%s[%s] %s", indent, command.GetHostname(), command.GetCommand()))
+	}
+	for _, file := range plan.GetFiles() {
+		lines = append(lines, fmt.Sprintf("%s[%s] write %s:", indent, file.GetHostname(), file.GetPath()))
+		for _, line := range strings.Split(strings.TrimRight(file.GetContents(), "\n"), "\n") {
+			lines = append(lines, indent+"    "+line)
+		}
+	}
+	for _, note := range plan.GetNotes() {
+		lines = append(lines, fmt.Sprintf("%snote: %s", indent, note))
+	}
+	if len(lines) == 0 || (indent != "" && len(lines) == 1) {
+		lines = append(lines, indent+"nothing to do")
+	}
+
+	return lines
+}
+
+func newDryRunStepOutput(plan *pb.DryRunPlan) DryRunStepOutput {
+	output := DryRunStepOutput{
+		Commands: []PlannedCommandOutput{},
+		Files:    []PlannedFileOutput{},
+		Notes:    plan.GetNotes(),
+	}
+	if plan.GetStep() != pb.UpgradeSteps_UNKNOWN_STEP {
+		output.Step = plan.GetStep().String()
+		output.Description = stepDescription(plan.GetStep())
+	}
+	if output.Notes == nil {
+		output.Notes = []string{}
+	}
+
+	for _, command := range plan.GetCommands() {
+		output.Commands = append(output.Commands, PlannedCommandOutput{
+			Hostname: command.GetHostname(),
+			Command:  command.GetCommand(),
+		})
+	}
+	for _, file := range plan.GetFiles() {
+		output.Files = append(output.Files, PlannedFileOutput{
+			Hostname: file.GetHostname(),
+			Path:     file.GetPath(),
+			Contents: file.GetContents(),
+		})
+	}
+	return output
+}
+
+// stepPlan labels a plan returned by a single step's RPC with that step.
+func stepPlan(step pb.UpgradeSteps, plan *pb.DryRunPlan) *pb.DryRunPlan {
+	if plan == nil {
+		plan = &pb.DryRunPlan{}
+	}
+	plan.Step = step
+	return plan
+}
@@ -0,0 +1,122 @@
+package commanders_test
+
+import (
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+
+	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("dry runs", func() {
+	var (
+		client     *mockpb.MockCliToHubClient
+		ctrl       *gomock.Controller
+		testStdout *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		testStdout, _, _ = testhelper.SetupTestLogger()
+
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+	})
+
+	AfterEach(func() {
+		commanders.OutputWriter = os.Stdout
+		commanders.OutputFormat = commanders.FormatText
+		ctrl.Finish()
+	})
+
+	Describe("FormatPlan", func() {
+		It("lists commands, files and notes under the step", func() {
+			lines := commanders.FormatPlan(&pb.DryRunPlan{
+				Step:     pb.UpgradeSteps_INIT_CLUSTER,
+				Commands: []*pb.PlannedCommand{{Hostname: "mdw", Command: "gpinitsystem -a -I /tmp/config"}},
+				Files:    []*pb.PlannedFile{{Hostname: "mdw", Path: "/tmp/config", Contents: "ARRAY_NAME=gp\nPORT_BASE=25432\n"}},
+				Notes:    []string{"the target cluster is created with the source's settings"},
+			})
+
+			Expect(lines).To(Equal([]string{
+				"INIT_CLUSTER - Initialize upgrade target cluster:",
+				"  [mdw] gpinitsystem -a -I /tmp/config",
+				"  [mdw] write /tmp/config:",
+				"      ARRAY_NAME=gp",
+				"      PORT_BASE=25432",
+				"  note: the target cluster is created with the source's settings",
+			}))
+		})
+
+		It("says when there is nothing to do", func() {
+			lines := commanders.FormatPlan(&pb.DryRunPlan{Step: pb.UpgradeSteps_CONFIG})
+			Expect(lines).To(Equal([]string{"CONFIG - Configuration Check:", "  nothing to do"}))
+		})
+
+		It("leaves out the heading for plans that aren't for a step", func() {
+			lines := commanders.FormatPlan(&pb.DryRunPlan{
+				Commands: []*pb.PlannedCommand{{Hostname: "mdw", Command: "rm -rf /data"}},
+			})
+			Expect(lines).To(Equal([]string{"[mdw] rm -rf /data"}))
+		})
+	})
+
+	It("reports the plan of a single step", func() {
+		client.EXPECT().UpgradeReconfigurePorts(
+			gomock.Any(),
+			&pb.UpgradeReconfigurePortsRequest{DryRun: true},
+		).Return(&pb.UpgradeReconfigurePortsReply{
+			Plan: &pb.DryRunPlan{Commands: []*pb.PlannedCommand{{Hostname: "mdw", Command: "sed"}}},
+		}, nil)
+
+		err := commanders.NewUpgrader(client).ReconfigurePorts(false, true)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(testStdout).Should(gbytes.Say("Dry run; nothing has been changed"))
+		Eventually(testStdout).Should(gbytes.Say(`RECONFIGURE_PORTS - Adjust upgraded cluster ports:`))
+		Eventually(testStdout).Should(gbytes.Say(`\[mdw\] sed`))
+		Consistently(testStdout).ShouldNot(gbytes.Say("complete"))
+	})
+
+	It("reports the plan of every step for run", func() {
+		output := gbytes.NewBuffer()
+		commanders.OutputWriter = output
+		commanders.OutputFormat = commanders.FormatJSON
+
+		client.EXPECT().Run(
+			gomock.Any(),
+			&pb.RunRequest{DryRun: true},
+		).Return(&pb.RunReply{Plans: []*pb.DryRunPlan{
+			{Step: pb.UpgradeSteps_CONFIG, Notes: []string{"save the config"}},
+			{Step: pb.UpgradeSteps_SEGINSTALL, Commands: []*pb.PlannedCommand{{Hostname: "sdw1", Command: "ls"}}},
+		}}, nil)
+
+		err := commanders.NewRunner(client).Run(true)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(output.Contents()).To(MatchJSON(`{"steps": [
+			{"step": "CONFIG", "description": "Configuration Check", "commands": [], "files": [], "notes": ["save the config"]},
+			{"step": "SEGINSTALL", "description": "Install binaries on segments", "commands": [{"hostname": "sdw1", "command": "ls"}], "files": [], "notes": []}
+		]}`))
+	})
+
+	It("reports the plan of a revert", func() {
+		client.EXPECT().Revert(
+			gomock.Any(),
+			&pb.RevertRequest{DryRun: true},
+		).Return(&pb.RevertReply{
+			Plan: &pb.DryRunPlan{Commands: []*pb.PlannedCommand{{Hostname: "mdw", Command: "gpstart -a"}}},
+		}, nil)
+
+		err := commanders.NewReverter(client).Revert(true)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(testStdout).Should(gbytes.Say(`\[mdw\] gpstart -a`))
+	})
+})
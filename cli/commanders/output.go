@@ -0,0 +1,294 @@
+package commanders
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"gopkg.in/yaml.v2"
+)
+
+// Format selects how commanders report their results.
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// OutputFormat and OutputWriter are set from the global --format flag before
+// any commander runs. With FormatText, commanders log their results through
+// gplog as they always have. Otherwise each result is written to OutputWriter
+// as a single document using one of the *Output types below; a JSON document
+// takes up exactly one line, and a YAML document starts with "---", so that
+// commands which report more than once (status upgrade --follow) produce a
+// stream of documents.
+//
+// The *Output types are the schema that scripts rely on. Fields may be added,
+// but existing fields must not be renamed, removed, or change meaning.
+var (
+	OutputFormat           = FormatText
+	OutputWriter io.Writer = os.Stdout
+)
+
+func ParseFormat(format string) (Format, error) {
+	switch f := Format(format); f {
+	case FormatText, FormatJSON, FormatYAML:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q: must be one of json, yaml or text", format)
+	}
+}
+
+// ErrorOutput replaces a command's usual output when the command fails.
+type ErrorOutput struct {
+	Error      string `json:"error" yaml:"error"`
+	ReturnCode int    `json:"returnCode" yaml:"returnCode"` // also the exit code of gpupgrade
+}
+
+// UpgradeStatusOutput is reported by `gpupgrade status upgrade`. With
+// --follow, a StepStatusOutput is reported on its own for every change.
+type UpgradeStatusOutput struct {
+	Steps            []StepStatusOutput `json:"steps" yaml:"steps"`
+	ElapsedSeconds   int64              `json:"elapsedSeconds" yaml:"elapsedSeconds"`
+	RemainingSeconds int64              `json:"remainingSeconds" yaml:"remainingSeconds"` // estimated, or -1 if it can't be
+}
+
+type StepStatusOutput struct {
+	Step        string             `json:"step" yaml:"step"` // e.g. CONVERT_MASTER
+	Description string             `json:"description" yaml:"description"`
+	Status      string             `json:"status" yaml:"status"` // PENDING, RUNNING, COMPLETE or FAILED
+	Hosts       []HostStatusOutput `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+	Failure     *StepFailureOutput `json:"failure,omitempty" yaml:"failure,omitempty"`
+	Timing      *StepTimingOutput  `json:"timing,omitempty" yaml:"timing,omitempty"` // absent until the step starts
+}
+
+// HostStatusOutput is how a step went on one host, for the steps that work
+// host by host.
+type HostStatusOutput struct {
+	Hostname string `json:"hostname" yaml:"hostname"`
+	Status   string `json:"status" yaml:"status"` // COMPLETE or FAILED
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// StepFailureOutput explains why a step failed. The command, its exit code and
+// the log file are only given when the step failed because a command did.
+type StepFailureOutput struct {
+	Error    string `json:"error" yaml:"error"`
+	Command  string `json:"command,omitempty" yaml:"command,omitempty"`
+	ExitCode int32  `json:"exitCode,omitempty" yaml:"exitCode,omitempty"`
+	LogFile  string `json:"logFile,omitempty" yaml:"logFile,omitempty"`
+}
+
+// StepTimingOutput is how long the latest attempt at a step took, or has taken
+// so far, and how long it took in earlier runs on a cluster of the same size.
+type StepTimingOutput struct {
+	StartTime        string `json:"startTime" yaml:"startTime"`                 // RFC 3339
+	EndTime          string `json:"endTime,omitempty" yaml:"endTime,omitempty"` // RFC 3339
+	ElapsedSeconds   int64  `json:"elapsedSeconds" yaml:"elapsedSeconds"`
+	RemainingSeconds int64  `json:"remainingSeconds" yaml:"remainingSeconds"` // estimated, or -1 if it can't be
+	UsualSeconds     int64  `json:"usualSeconds,omitempty" yaml:"usualSeconds,omitempty"`
+	EarlierRuns      int32  `json:"earlierRuns" yaml:"earlierRuns"`
+}
+
+// ConversionStatusOutput is reported by `gpupgrade status conversion`.
+type ConversionStatusOutput struct {
+	Segments []SegmentConversionOutput `json:"segments" yaml:"segments"`
+}
+
+type SegmentConversionOutput struct {
+	Dbid        int32  `json:"dbid" yaml:"dbid"`
+	Content     int32  `json:"content" yaml:"content"`
+	Role        string `json:"role" yaml:"role"` // MASTER or PRIMARY
+	Hostname    string `json:"hostname" yaml:"hostname"`
+	Status      string `json:"status" yaml:"status"`
+	Phase       string `json:"phase,omitempty" yaml:"phase,omitempty"`
+	PercentDone int32  `json:"percentDone" yaml:"percentDone"`
+	StartTime   string `json:"startTime,omitempty" yaml:"startTime,omitempty"` // RFC 3339
+	EndTime     string `json:"endTime,omitempty" yaml:"endTime,omitempty"`     // RFC 3339
+	Error       string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// AgentStatusOutput is reported by `gpupgrade status agents`.
+type AgentStatusOutput struct {
+	Agents []AgentOutput `json:"agents" yaml:"agents"`
+}
+
+type AgentOutput struct {
+	Hostname  string `json:"hostname" yaml:"hostname"`
+	Healthy   bool   `json:"healthy" yaml:"healthy"`
+	State     string `json:"state" yaml:"state"`
+	Failures  int32  `json:"failures" yaml:"failures"`
+	LastError string `json:"lastError,omitempty" yaml:"lastError,omitempty"`
+	RetryAt   string `json:"retryAt,omitempty" yaml:"retryAt,omitempty"` // RFC 3339
+}
+
+// ObjectCountOutput is reported by `gpupgrade check object-count`.
+type ObjectCountOutput struct {
+	Databases []DatabaseObjectCountOutput `json:"databases" yaml:"databases"`
+}
+
+type DatabaseObjectCountOutput struct {
+	Name      string `json:"name" yaml:"name"`
+	AOCount   int32  `json:"aoCount" yaml:"aoCount"`
+	HeapCount int32  `json:"heapCount" yaml:"heapCount"`
+}
+
+// CatalogCheckOutput is reported by `gpupgrade check catalog`. An empty
+// list of issues means that every database passed.
+type CatalogCheckOutput struct {
+	Databases []string             `json:"databases" yaml:"databases"`
+	Issues    []CatalogIssueOutput `json:"issues" yaml:"issues"`
+}
+
+type CatalogIssueOutput struct {
+	Check       string `json:"check" yaml:"check"` // e.g. unsupported-column-types
+	Database    string `json:"database" yaml:"database"`
+	Object      string `json:"object" yaml:"object"`
+	Detail      string `json:"detail" yaml:"detail"`
+	Remediation string `json:"remediation" yaml:"remediation"`
+}
+
+// PortCheckOutput is reported by `gpupgrade check ports`, with one entry for
+// each segment of the planned target cluster.
+type PortCheckOutput struct {
+	Ports []PortStatusOutput `json:"ports" yaml:"ports"`
+}
+
+type PortStatusOutput struct {
+	Hostname string `json:"hostname" yaml:"hostname"`
+	Content  int32  `json:"content" yaml:"content"`
+	Port     int32  `json:"port" yaml:"port"`
+	InUse    bool   `json:"inUse" yaml:"inUse"`
+	Pid      int32  `json:"pid,omitempty" yaml:"pid,omitempty"`
+	Process  string `json:"process,omitempty" yaml:"process,omitempty"`
+}
+
+// DiskSpaceOutput is reported by `gpupgrade check disk-space`.
+type DiskSpaceOutput struct {
+	Hosts    []HostDiskSpaceOutput    `json:"hosts" yaml:"hosts"`
+	Segments []SegmentDiskSpaceOutput `json:"segments" yaml:"segments"`
+}
+
+type HostDiskSpaceOutput struct {
+	Hostname    string                  `json:"hostname" yaml:"hostname"`
+	Filesystems []FilesystemUsageOutput `json:"filesystems" yaml:"filesystems"`
+	Error       string                  `json:"error,omitempty" yaml:"error,omitempty"` // set if the host couldn't be checked
+}
+
+type FilesystemUsageOutput struct {
+	Filesystem   string  `json:"filesystem" yaml:"filesystem"`
+	UsagePercent float64 `json:"usagePercent" yaml:"usagePercent"`
+}
+
+type SegmentDiskSpaceOutput struct {
+	Hostname      string              `json:"hostname" yaml:"hostname"`
+	Content       int32               `json:"content" yaml:"content"`
+	Role          string              `json:"role" yaml:"role"`
+	DataDir       string              `json:"dataDir" yaml:"dataDir"`
+	TargetDataDir string              `json:"targetDataDir" yaml:"targetDataDir"`
+	DataDirBytes  uint64              `json:"dataDirBytes" yaml:"dataDirBytes"`
+	Filesystem    string              `json:"filesystem" yaml:"filesystem"`
+	FreeBytes     uint64              `json:"freeBytes" yaml:"freeBytes"`
+	Copy          DiskSpaceNeedOutput `json:"copy" yaml:"copy"`
+	Link          DiskSpaceNeedOutput `json:"link" yaml:"link"`
+	Error         string              `json:"error,omitempty" yaml:"error,omitempty"` // set if the segment couldn't be checked
+}
+
+// DiskSpaceNeedOutput is the space projected for one upgrade mode.
+type DiskSpaceNeedOutput struct {
+	Bytes           uint64 `json:"bytes" yaml:"bytes"`
+	FilesystemBytes uint64 `json:"filesystemBytes" yaml:"filesystemBytes"` // for every segment on the filesystem
+	Fits            bool   `json:"fits" yaml:"fits"`
+}
+
+// VersionCheckOutput is reported by `gpupgrade check version`.
+type VersionCheckOutput struct {
+	Compatible bool `json:"compatible" yaml:"compatible"`
+}
+
+// ConfigOutput is reported by `gpupgrade config show`, keyed by setting name.
+type ConfigOutput struct {
+	Settings map[string]string `json:"settings" yaml:"settings"`
+}
+
+// CancelOutput is reported by `gpupgrade cancel`, with one entry for each
+// pg_upgrade that was stopped.
+type CancelOutput struct {
+	Step      string                   `json:"step" yaml:"step"`
+	Processes []CancelledProcessOutput `json:"processes" yaml:"processes"`
+}
+
+type CancelledProcessOutput struct {
+	Hostname string `json:"hostname" yaml:"hostname"`
+	Content  int32  `json:"content" yaml:"content"` // -1 for the master
+	Pid      int32  `json:"pid" yaml:"pid"`
+}
+
+// DryRunOutput is reported instead of a command's usual output when the
+// command is given --dry-run. Commands that cover several steps, like run and
+// resume, report one entry per step.
+type DryRunOutput struct {
+	Steps []DryRunStepOutput `json:"steps" yaml:"steps"`
+}
+
+type DryRunStepOutput struct {
+	Step        string                 `json:"step,omitempty" yaml:"step,omitempty"` // empty for revert
+	Description string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Commands    []PlannedCommandOutput `json:"commands" yaml:"commands"` // in the order they would run
+	Files       []PlannedFileOutput    `json:"files" yaml:"files"`
+	Notes       []string               `json:"notes" yaml:"notes"`
+}
+
+type PlannedCommandOutput struct {
+	Hostname string `json:"hostname" yaml:"hostname"`
+	Command  string `json:"command" yaml:"command"`
+}
+
+type PlannedFileOutput struct {
+	Hostname string `json:"hostname" yaml:"hostname"`
+	Path     string `json:"path" yaml:"path"`
+	Contents string `json:"contents" yaml:"contents"`
+}
+
+// WriteOutput writes v to OutputWriter as a single JSON or YAML document. It
+// must not be called when OutputFormat is FormatText.
+func WriteOutput(v interface{}) error {
+	switch OutputFormat {
+	case FormatJSON:
+		return json.NewEncoder(OutputWriter).Encode(v)
+
+	case FormatYAML:
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(OutputWriter, "---\n%s", out)
+		return err
+
+	default:
+		return fmt.Errorf("cannot write structured output in %q format", OutputFormat)
+	}
+}
+
+// ReportError tells the user that a command failed, either through gplog or,
+// for structured formats, as an ErrorOutput document.
+func ReportError(err error) {
+	if OutputFormat == FormatText {
+		gplog.Error(err.Error())
+		return
+	}
+
+	writeErr := WriteOutput(ErrorOutput{
+		Error:      err.Error(),
+		ReturnCode: utils.GetExitCodeForError(err),
+	})
+	if writeErr != nil {
+		gplog.Error("%s (could not write %s output: %s)", err.Error(), OutputFormat, writeErr.Error())
+	}
+}
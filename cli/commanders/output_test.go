@@ -0,0 +1,306 @@
+package commanders_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("structured output", func() {
+	var (
+		client *mockpb.MockCliToHubClient
+		ctrl   *gomock.Controller
+		output *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+
+		output = gbytes.NewBuffer()
+		commanders.OutputWriter = output
+		commanders.OutputFormat = commanders.FormatJSON
+	})
+
+	AfterEach(func() {
+		commanders.OutputWriter = os.Stdout
+		commanders.OutputFormat = commanders.FormatText
+		ctrl.Finish()
+	})
+
+	Describe("ParseFormat", func() {
+		It("accepts the known formats", func() {
+			for _, name := range []string{"json", "yaml", "text"} {
+				format, err := commanders.ParseFormat(name)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(format)).To(Equal(name))
+			}
+		})
+
+		It("rejects anything else", func() {
+			_, err := commanders.ParseFormat("xml")
+			Expect(err).To(MatchError(ContainSubstring(`unknown output format "xml"`)))
+		})
+	})
+
+	Describe("WriteOutput", func() {
+		It("writes one JSON document per line", func() {
+			Expect(commanders.WriteOutput(commanders.VersionCheckOutput{Compatible: true})).To(Succeed())
+			Expect(commanders.WriteOutput(commanders.VersionCheckOutput{Compatible: false})).To(Succeed())
+
+			Expect(string(output.Contents())).To(Equal("{\"compatible\":true}\n{\"compatible\":false}\n"))
+		})
+
+		It("starts each YAML document with a separator", func() {
+			commanders.OutputFormat = commanders.FormatYAML
+
+			Expect(commanders.WriteOutput(commanders.ObjectCountOutput{
+				Databases: []commanders.DatabaseObjectCountOutput{{Name: "postgres", AOCount: 1, HeapCount: 2}},
+			})).To(Succeed())
+
+			Expect(string(output.Contents())).To(Equal("---\ndatabases:\n- name: postgres\n  aoCount: 1\n  heapCount: 2\n"))
+		})
+
+		It("refuses to write text", func() {
+			commanders.OutputFormat = commanders.FormatText
+			Expect(commanders.WriteOutput(commanders.VersionCheckOutput{})).ToNot(Succeed())
+		})
+	})
+
+	Describe("ReportError", func() {
+		It("writes the error and its return code", func() {
+			commanders.ReportError(utils.DatabaseConnectionError{Parent: errors.New("no route to host")})
+
+			Expect(output.Contents()).To(MatchJSON(`{
+				"error": "Database Connection Error: no route to host",
+				"returnCode": 65
+			}`))
+		})
+
+		It("uses a return code of 1 for ordinary errors", func() {
+			commanders.ReportError(errors.New("oops"))
+
+			Expect(output.Contents()).To(MatchJSON(`{"error": "oops", "returnCode": 1}`))
+		})
+	})
+
+	Describe("commanders", func() {
+		It("reports upgrade status", func() {
+			client.EXPECT().StatusUpgrade(gomock.Any(), &pb.StatusUpgradeRequest{}).Return(&pb.StatusUpgradeReply{
+				ListOfUpgradeStepStatuses: []*pb.UpgradeStepStatus{
+					{Step: pb.UpgradeSteps_CONFIG, Status: pb.StepStatus_COMPLETE},
+					{Step: pb.UpgradeSteps_SEGINSTALL, Status: pb.StepStatus_RUNNING},
+				},
+			}, nil)
+
+			err := commanders.NewReporter(client).OverallUpgradeStatus()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output.Contents()).To(MatchJSON(`{"steps": [
+				{"step": "CONFIG", "description": "Configuration Check", "status": "COMPLETE"},
+				{"step": "SEGINSTALL", "description": "Install binaries on segments", "status": "RUNNING"}
+			], "elapsedSeconds": 0, "remainingSeconds": 0}`))
+		})
+
+		It("reports why a step failed", func() {
+			client.EXPECT().StatusUpgrade(gomock.Any(), &pb.StatusUpgradeRequest{}).Return(&pb.StatusUpgradeReply{
+				ListOfUpgradeStepStatuses: []*pb.UpgradeStepStatus{
+					{Step: pb.UpgradeSteps_VALIDATE_START_CLUSTER, Status: pb.StepStatus_FAILED, Failure: &pb.StepFailure{
+						Error:    "exit status 1",
+						Command:  "gpstart -a",
+						ExitCode: 1,
+						LogFile:  "/home/gpadmin/gpAdminLogs/gpstart_20180301.log",
+					}},
+				},
+			}, nil)
+
+			err := commanders.NewReporter(client).OverallUpgradeStatus()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output.Contents()).To(MatchJSON(`{"steps": [{
+				"step": "VALIDATE_START_CLUSTER",
+				"description": "Validate the upgraded cluster can start up",
+				"status": "FAILED",
+				"failure": {
+					"error": "exit status 1",
+					"command": "gpstart -a",
+					"exitCode": 1,
+					"logFile": "/home/gpadmin/gpAdminLogs/gpstart_20180301.log"
+				}
+			}], "elapsedSeconds": 0, "remainingSeconds": 0}`))
+		})
+
+		It("reports how long the upgrade and each step has taken", func() {
+			start := time.Date(2018, 3, 1, 12, 0, 0, 0, time.Local)
+			client.EXPECT().StatusUpgrade(gomock.Any(), &pb.StatusUpgradeRequest{}).Return(&pb.StatusUpgradeReply{
+				ListOfUpgradeStepStatuses: []*pb.UpgradeStepStatus{
+					{Step: pb.UpgradeSteps_CONVERT_MASTER, Status: pb.StepStatus_COMPLETE, Timing: &pb.StepTiming{
+						StartTime:      start.Unix(),
+						EndTime:        start.Add(time.Minute).Unix(),
+						ElapsedSeconds: 60,
+					}},
+					{Step: pb.UpgradeSteps_CONVERT_PRIMARIES, Status: pb.StepStatus_RUNNING, Timing: &pb.StepTiming{
+						StartTime:        start.Add(time.Minute).Unix(),
+						ElapsedSeconds:   300,
+						RemainingSeconds: 900,
+						UsualSeconds:     1200,
+						EarlierRuns:      2,
+					}},
+				},
+				ElapsedSeconds:   360,
+				RemainingSeconds: 900,
+			}, nil)
+
+			err := commanders.NewReporter(client).OverallUpgradeStatus()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output.Contents()).To(MatchJSON(fmt.Sprintf(`{"steps": [{
+				"step": "CONVERT_MASTER",
+				"description": "Run pg_upgrade on master",
+				"status": "COMPLETE",
+				"timing": {
+					"startTime": %q,
+					"endTime": %q,
+					"elapsedSeconds": 60,
+					"remainingSeconds": 0,
+					"earlierRuns": 0
+				}
+			}, {
+				"step": "CONVERT_PRIMARIES",
+				"description": "Run pg_upgrade on primaries",
+				"status": "RUNNING",
+				"timing": {
+					"startTime": %q,
+					"elapsedSeconds": 300,
+					"remainingSeconds": 900,
+					"usualSeconds": 1200,
+					"earlierRuns": 2
+				}
+			}], "elapsedSeconds": 360, "remainingSeconds": 900}`,
+				start.Format(time.RFC3339),
+				start.Add(time.Minute).Format(time.RFC3339),
+				start.Add(time.Minute).Format(time.RFC3339))))
+		})
+
+		It("reports conversion status", func() {
+			client.EXPECT().StatusConversion(gomock.Any(), &pb.StatusConversionRequest{}).Return(&pb.StatusConversionReply{
+				ConversionStatuses: []*pb.ConversionStatus{{
+					Dbid:        3,
+					Content:     1,
+					Role:        pb.SegmentRole_PRIMARY,
+					Hostname:    "sdw1",
+					Status:      pb.StepStatus_RUNNING,
+					Phase:       "Copying user relation files",
+					PercentDone: 60,
+				}},
+			}, nil)
+
+			err := commanders.NewReporter(client).OverallConversionStatus()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output.Contents()).To(MatchJSON(`{"segments": [{
+				"dbid": 3,
+				"content": 1,
+				"role": "PRIMARY",
+				"hostname": "sdw1",
+				"status": "RUNNING",
+				"phase": "Copying user relation files",
+				"percentDone": 60
+			}]}`))
+		})
+
+		It("reports agent status", func() {
+			retry := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+			client.EXPECT().StatusAgents(gomock.Any(), &pb.StatusAgentsRequest{}).Return(&pb.StatusAgentsReply{
+				Agents: []*pb.AgentStatus{
+					{Hostname: "sdw1", Healthy: true, State: "READY"},
+					{Hostname: "sdw2", State: "IDLE", Failures: 1, LastError: "connection refused", RetryAt: retry.Unix()},
+				},
+			}, nil)
+
+			err := commanders.NewReporter(client).AgentStatus()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output.Contents()).To(MatchJSON(fmt.Sprintf(`{"agents": [
+				{"hostname": "sdw1", "healthy": true, "state": "READY", "failures": 0},
+				{"hostname": "sdw2", "healthy": false, "state": "IDLE", "failures": 1,
+				 "lastError": "connection refused", "retryAt": %q}
+			]}`, time.Unix(retry.Unix(), 0).Format(time.RFC3339))))
+		})
+
+		It("reports object counts", func() {
+			client.EXPECT().CheckObjectCount(gomock.Any(), &pb.CheckObjectCountRequest{}).Return(&pb.CheckObjectCountReply{
+				ListOfCounts: []*pb.CountPerDb{{DbName: "template1", AoCount: 1, HeapCount: 2}},
+			}, nil)
+
+			err := commanders.NewObjectCountChecker(client).Execute()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output.Contents()).To(MatchJSON(`{"databases": [{"name": "template1", "aoCount": 1, "heapCount": 2}]}`))
+		})
+
+		It("reports disk space", func() {
+			client.EXPECT().CheckDiskSpace(gomock.Any(), &pb.CheckDiskSpaceRequest{}).Return(&pb.CheckDiskSpaceReply{
+				HostDiskUsages: []*pb.HostDiskUsage{
+					{Hostname: "sdw1", ListOfFileSysUsage: []*pb.FileSysUsage{{Filesystem: "/data", Usage: 42.5}}},
+					{Hostname: "sdw2", Error: "connection refused"},
+				},
+				Segments: []*pb.SegmentDiskSpace{
+					{
+						Hostname: "sdw1", Content: 0, Role: "primary",
+						DataDir: "/data/seg0", TargetDataDir: "/data_upgrade/seg0", DataDirBytes: 100,
+						Filesystem: "/data", FreeBytes: 1000,
+						Copy: &pb.DiskSpaceNeed{Bytes: 100, FilesystemBytes: 200, Fits: true},
+						Link: &pb.DiskSpaceNeed{Bytes: 10, FilesystemBytes: 20, Fits: true},
+					},
+					{Hostname: "sdw2", Content: 1, Role: "primary", Error: "connection refused"},
+				},
+			}, nil)
+
+			err := commanders.NewDiskSpaceChecker(client).Execute()
+			Expect(err).To(HaveOccurred())
+
+			Expect(output.Contents()).To(MatchJSON(`{"hosts": [
+				{"hostname": "sdw1", "filesystems": [{"filesystem": "/data", "usagePercent": 42.5}]},
+				{"hostname": "sdw2", "filesystems": [], "error": "connection refused"}
+			], "segments": [
+				{"hostname": "sdw1", "content": 0, "role": "primary",
+				 "dataDir": "/data/seg0", "targetDataDir": "/data_upgrade/seg0", "dataDirBytes": 100,
+				 "filesystem": "/data", "freeBytes": 1000,
+				 "copy": {"bytes": 100, "filesystemBytes": 200, "fits": true},
+				 "link": {"bytes": 10, "filesystemBytes": 20, "fits": true}},
+				{"hostname": "sdw2", "content": 1, "role": "primary",
+				 "dataDir": "", "targetDataDir": "", "dataDirBytes": 0,
+				 "filesystem": "", "freeBytes": 0,
+				 "copy": {"bytes": 0, "filesystemBytes": 0, "fits": false},
+				 "link": {"bytes": 0, "filesystemBytes": 0, "fits": false},
+				 "error": "connection refused"}
+			]}`))
+		})
+
+		It("reports version compatibility", func() {
+			client.EXPECT().CheckVersion(gomock.Any(), &pb.CheckVersionRequest{}).Return(&pb.CheckVersionReply{
+				IsVersionCompatible: true,
+			}, nil)
+
+			err := commanders.NewVersionChecker(client).Execute()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output.Contents()).To(MatchJSON(`{"compatible": true}`))
+		})
+	})
+})
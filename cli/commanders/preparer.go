@@ -29,12 +29,18 @@ func NewPreparer(client pb.CliToHubClient) Preparer {
 
 var NumberOfConnectionAttempt = 100
 
-func (p Preparer) ShutdownClusters() error {
-	_, err := p.client.PrepareShutdownClusters(context.Background(),
-		&pb.PrepareShutdownClustersRequest{})
+func (p Preparer) ShutdownClusters(skipPrerequisites, dryRun bool) error {
+	reply, err := p.client.PrepareShutdownClusters(context.Background(),
+		&pb.PrepareShutdownClustersRequest{SkipPrerequisites: skipPrerequisites, DryRun: dryRun})
 	if err != nil {
 		gplog.Error(err.Error())
 	}
+	if dryRun {
+		if err != nil {
+			return err
+		}
+		return ReportPlans(stepPlan(pb.UpgradeSteps_SHUTDOWN_CLUSTERS, reply.GetPlan()))
+	}
 	gplog.Info("request to shutdown clusters sent to hub")
 	return nil
 }
@@ -65,12 +71,19 @@ func (p Preparer) StartHub() error {
 	return nil
 }
 
-func (p Preparer) InitCluster() error {
-	_, err := p.client.PrepareInitCluster(context.Background(), &pb.PrepareInitClusterRequest{})
+func (p Preparer) InitCluster(skipPrerequisites, dryRun bool) error {
+	reply, err := p.client.PrepareInitCluster(context.Background(), &pb.PrepareInitClusterRequest{
+		SkipPrerequisites: skipPrerequisites,
+		DryRun:            dryRun,
+	})
 	if err != nil {
 		return err
 	}
 
+	if dryRun {
+		return ReportPlans(stepPlan(pb.UpgradeSteps_INIT_CLUSTER, reply.GetPlan()))
+	}
+
 	gplog.Info("Gleaning the new cluster config")
 	return nil
 }
@@ -84,12 +97,19 @@ func (p Preparer) VerifyConnectivity(client pb.CliToHubClient) error {
 	return err
 }
 
-func (p Preparer) StartAgents() error {
-	_, err := p.client.PrepareStartAgents(context.Background(), &pb.PrepareStartAgentsRequest{})
+func (p Preparer) StartAgents(skipPrerequisites, dryRun bool) error {
+	reply, err := p.client.PrepareStartAgents(context.Background(), &pb.PrepareStartAgentsRequest{
+		SkipPrerequisites: skipPrerequisites,
+		DryRun:            dryRun,
+	})
 	if err != nil {
 		return err
 	}
 
+	if dryRun {
+		return ReportPlans(stepPlan(pb.UpgradeSteps_START_AGENTS, reply.GetPlan()))
+	}
+
 	gplog.Info("Started Agents in progress, check gpupgrade_agent logs for details")
 	return nil
 }
@@ -90,7 +90,7 @@ var _ = Describe("preparer", func() {
 				&pb.PrepareInitClusterRequest{},
 			).Return(&pb.PrepareInitClusterReply{}, nil)
 			preparer := commanders.NewPreparer(client)
-			err := preparer.InitCluster()
+			err := preparer.InitCluster(false, false)
 			Expect(err).To(BeNil())
 			Eventually(testStdout).Should(gbytes.Say("Gleaning the new cluster config"))
 		})
@@ -104,7 +104,7 @@ var _ = Describe("preparer", func() {
 				&pb.PrepareShutdownClustersRequest{},
 			).Return(&pb.PrepareShutdownClustersReply{}, nil)
 			preparer := commanders.NewPreparer(client)
-			err := preparer.ShutdownClusters()
+			err := preparer.ShutdownClusters(false, false)
 			Expect(err).To(BeNil())
 			Eventually(testStdout).Should(gbytes.Say("request to shutdown clusters sent to hub"))
 		})
@@ -118,7 +118,7 @@ var _ = Describe("preparer", func() {
 				&pb.PrepareStartAgentsRequest{},
 			).Return(&pb.PrepareStartAgentsReply{}, nil)
 			preparer := commanders.NewPreparer(client)
-			err := preparer.StartAgents()
+			err := preparer.StartAgents(false, false)
 			Expect(err).To(BeNil())
 			Eventually(testStdout).Should(gbytes.Say("Started Agents in progress, check gpupgrade_agent logs for details"))
 		})
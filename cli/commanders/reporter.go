@@ -3,6 +3,9 @@ package commanders
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
@@ -32,6 +35,8 @@ var UpgradeStepsMessage = map[pb.UpgradeSteps]string{
 	pb.UpgradeSteps_SHUTDOWN_CLUSTERS:      "- Shutdown clusters",
 	pb.UpgradeSteps_SHARE_OIDS:             "- Copy OID files from master to segments",
 	pb.UpgradeSteps_CONVERT_PRIMARIES:      "- Run pg_upgrade on primaries",
+	pb.UpgradeSteps_REBUILD_MIRRORS:        "- Copy upgraded primaries to their mirrors",
+	pb.UpgradeSteps_REBUILD_STANDBY:        "- Copy upgraded master to the standby",
 	pb.UpgradeSteps_VALIDATE_START_CLUSTER: "- Validate the upgraded cluster can start up",
 	pb.UpgradeSteps_RECONFIGURE_PORTS:      "- Adjust upgraded cluster ports",
 }
@@ -53,10 +58,173 @@ func (r *Reporter) OverallUpgradeStatus() error {
 		return errors.New("Received no list of upgrade statuses from hub")
 	}
 
+	if OutputFormat != FormatText {
+		output := UpgradeStatusOutput{
+			Steps:            []StepStatusOutput{},
+			ElapsedSeconds:   status.GetElapsedSeconds(),
+			RemainingSeconds: status.GetRemainingSeconds(),
+		}
+		for _, step := range status.GetListOfUpgradeStepStatuses() {
+			output.Steps = append(output.Steps, newStepStatusOutput(step))
+		}
+		return WriteOutput(output)
+	}
+
 	for _, step := range status.GetListOfUpgradeStepStatuses() {
-		reportString := fmt.Sprintf("%v %s", step.GetStatus(),
-			UpgradeStepsMessage[step.GetStep()])
-		gplog.Info(reportString)
+		reportStepStatus(step)
+	}
+
+	if status.GetElapsedSeconds() > 0 {
+		gplog.Info("Elapsed: %s", formatSeconds(status.GetElapsedSeconds()))
+		if status.GetRemainingSeconds() >= 0 {
+			gplog.Info("Estimated time remaining: %s", formatSeconds(status.GetRemainingSeconds()))
+		}
+	}
+
+	return nil
+}
+
+// reportStepStatus logs the step's status, followed by how it went on each
+// host, how long it has taken and why it failed, if it did.
+func reportStepStatus(step *pb.UpgradeStepStatus) {
+	reportString := fmt.Sprintf("%v %s", step.GetStatus(),
+		UpgradeStepsMessage[step.GetStep()])
+	gplog.Info(reportString)
+
+	for _, host := range step.GetHosts() {
+		hostString := fmt.Sprintf("    %v on %s", host.GetStatus(), host.GetHostname())
+		if host.GetError() != "" {
+			hostString += ": " + host.GetError()
+		}
+		gplog.Info(hostString)
+	}
+
+	if timing := step.GetTiming(); timing != nil {
+		gplog.Info("    %s", FormatStepTiming(step.GetStatus(), timing))
+	}
+
+	if failure := step.GetFailure(); failure != nil {
+		gplog.Info("    Error: %s", failure.GetError())
+		if failure.GetCommand() != "" {
+			gplog.Info("    Command: %s", failure.GetCommand())
+			gplog.Info("    Exit code: %d", failure.GetExitCode())
+		}
+		if failure.GetLogFile() != "" {
+			gplog.Info("    See %s for details", failure.GetLogFile())
+		}
+	}
+}
+
+func newStepStatusOutput(step *pb.UpgradeStepStatus) StepStatusOutput {
+	output := StepStatusOutput{
+		Step:        step.GetStep().String(),
+		Description: strings.TrimPrefix(UpgradeStepsMessage[step.GetStep()], "- "),
+		Status:      step.GetStatus().String(),
+	}
+	for _, host := range step.GetHosts() {
+		output.Hosts = append(output.Hosts, HostStatusOutput{
+			Hostname: host.GetHostname(),
+			Status:   host.GetStatus().String(),
+			Error:    host.GetError(),
+		})
+	}
+	if failure := step.GetFailure(); failure != nil {
+		output.Failure = &StepFailureOutput{
+			Error:    failure.GetError(),
+			Command:  failure.GetCommand(),
+			ExitCode: failure.GetExitCode(),
+			LogFile:  failure.GetLogFile(),
+		}
+	}
+	if timing := step.GetTiming(); timing != nil {
+		output.Timing = &StepTimingOutput{
+			StartTime:        time.Unix(timing.GetStartTime(), 0).Format(time.RFC3339),
+			ElapsedSeconds:   timing.GetElapsedSeconds(),
+			RemainingSeconds: timing.GetRemainingSeconds(),
+			UsualSeconds:     timing.GetUsualSeconds(),
+			EarlierRuns:      timing.GetEarlierRuns(),
+		}
+		if timing.GetEndTime() != 0 {
+			output.Timing.EndTime = time.Unix(timing.GetEndTime(), 0).Format(time.RFC3339)
+		}
+	}
+	return output
+}
+
+// FormatStepTiming renders how long a step took, or has taken so far, next to
+// how long it usually takes, e.g.
+//
+//	Running for 12m5s (20m on average in 3 earlier runs; about 7m55s left)
+func FormatStepTiming(status pb.StepStatus, timing *pb.StepTiming) string {
+	line := "Took " + formatSeconds(timing.GetElapsedSeconds())
+	if status == pb.StepStatus_RUNNING {
+		line = "Running for " + formatSeconds(timing.GetElapsedSeconds())
+	}
+
+	if timing.GetEarlierRuns() == 0 {
+		return line
+	}
+
+	runs := "runs"
+	if timing.GetEarlierRuns() == 1 {
+		runs = "run"
+	}
+	line += fmt.Sprintf(" (%s on average in %d earlier %s",
+		formatSeconds(timing.GetUsualSeconds()), timing.GetEarlierRuns(), runs)
+	if status == pb.StepStatus_RUNNING && timing.GetRemainingSeconds() >= 0 {
+		line += fmt.Sprintf("; about %s left", formatSeconds(timing.GetRemainingSeconds()))
+	}
+	return line + ")"
+}
+
+// formatSeconds renders a number of seconds as a duration like 1h2m3s,
+// dropping any trailing zero units.
+func formatSeconds(seconds int64) string {
+	s := (time.Duration(seconds) * time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// FollowUpgradeStatus prints each step's status as the hub reports it, and
+// keeps printing status changes until every step has completed or one fails.
+func (r *Reporter) FollowUpgradeStatus() error {
+	stream, err := r.client.WatchUpgrade(context.Background(), &pb.WatchUpgradeRequest{})
+	if err != nil {
+		return errors.New("Failed to watch status from hub: " + err.Error())
+	}
+
+	var failed []pb.UpgradeSteps
+	for {
+		reply, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.New("Lost connection to hub while watching status: " + err.Error())
+		}
+
+		step := reply.GetStepStatus()
+		if OutputFormat != FormatText {
+			err = WriteOutput(newStepStatusOutput(step))
+			if err != nil {
+				return err
+			}
+		} else {
+			reportStepStatus(step)
+		}
+
+		if step.GetStatus() == pb.StepStatus_FAILED {
+			failed = append(failed, step.GetStep())
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("upgrade step %v failed", failed[0])
 	}
 
 	return nil
@@ -72,9 +240,120 @@ func (r *Reporter) OverallConversionStatus() error {
 		return errors.New("Received no list of conversion statuses from hub")
 	}
 
+	if OutputFormat != FormatText {
+		output := ConversionStatusOutput{Segments: []SegmentConversionOutput{}}
+		for _, status := range conversionStatus.GetConversionStatuses() {
+			output.Segments = append(output.Segments, newSegmentConversionOutput(status))
+		}
+		return WriteOutput(output)
+	}
+
 	for _, status := range conversionStatus.GetConversionStatuses() {
-		gplog.Info(status)
+		gplog.Info(FormatConversionStatus(status))
 	}
 
 	return nil
 }
+
+func newSegmentConversionOutput(status *pb.ConversionStatus) SegmentConversionOutput {
+	output := SegmentConversionOutput{
+		Dbid:        status.GetDbid(),
+		Content:     status.GetContent(),
+		Role:        status.GetRole().String(),
+		Hostname:    status.GetHostname(),
+		Status:      status.GetStatus().String(),
+		Phase:       status.GetPhase(),
+		PercentDone: status.GetPercentDone(),
+		Error:       status.GetErrorMessage(),
+	}
+	if status.GetStartTime() != 0 {
+		output.StartTime = time.Unix(status.GetStartTime(), 0).Format(time.RFC3339)
+	}
+	if status.GetEndTime() != 0 {
+		output.EndTime = time.Unix(status.GetEndTime(), 0).Format(time.RFC3339)
+	}
+	return output
+}
+
+// FormatConversionStatus renders the status of a single segment's conversion
+// as a line of the form
+//
+//	RUNNING - DBID 3 - CONTENT ID 1 - PRIMARY - host1 - 60% - <phase> - started <time>
+func FormatConversionStatus(status *pb.ConversionStatus) string {
+	line := fmt.Sprintf("%s - DBID %d - CONTENT ID %d - %s - %s",
+		status.GetStatus(), status.GetDbid(), status.GetContent(), status.GetRole(), status.GetHostname())
+
+	if status.GetPhase() != "" {
+		line += fmt.Sprintf(" - %d%% - %s", status.GetPercentDone(), status.GetPhase())
+	}
+	if status.GetStartTime() != 0 {
+		line += " - started " + formatUnixTime(status.GetStartTime())
+	}
+	if status.GetEndTime() != 0 {
+		line += " - finished " + formatUnixTime(status.GetEndTime())
+	}
+	if status.GetErrorMessage() != "" {
+		line += " - " + status.GetErrorMessage()
+	}
+
+	return line
+}
+
+func (r *Reporter) AgentStatus() error {
+	reply, err := r.client.StatusAgents(context.Background(), &pb.StatusAgentsRequest{})
+	if err != nil {
+		return errors.New("hub returned an error when checking the status of the agents: " + err.Error())
+	}
+
+	if OutputFormat != FormatText {
+		output := AgentStatusOutput{Agents: []AgentOutput{}}
+		for _, agent := range reply.GetAgents() {
+			output.Agents = append(output.Agents, newAgentOutput(agent))
+		}
+		return WriteOutput(output)
+	}
+
+	for _, agent := range reply.GetAgents() {
+		gplog.Info(FormatAgentStatus(agent))
+	}
+
+	return nil
+}
+
+func newAgentOutput(agent *pb.AgentStatus) AgentOutput {
+	output := AgentOutput{
+		Hostname:  agent.GetHostname(),
+		Healthy:   agent.GetHealthy(),
+		State:     agent.GetState(),
+		Failures:  agent.GetFailures(),
+		LastError: agent.GetLastError(),
+	}
+	if agent.GetRetryAt() != 0 {
+		output.RetryAt = time.Unix(agent.GetRetryAt(), 0).Format(time.RFC3339)
+	}
+	return output
+}
+
+// FormatAgentStatus renders the hub's connection to a single agent as a line
+// of the form
+//
+//	host1 - TRANSIENT_FAILURE - 2 failed dials - not retrying before <time> - <error>
+func FormatAgentStatus(agent *pb.AgentStatus) string {
+	line := fmt.Sprintf("%s - %s", agent.GetHostname(), agent.GetState())
+
+	if agent.GetFailures() != 0 {
+		line += fmt.Sprintf(" - %d failed dials", agent.GetFailures())
+	}
+	if agent.GetRetryAt() != 0 {
+		line += " - not retrying before " + formatUnixTime(agent.GetRetryAt())
+	}
+	if agent.GetLastError() != "" {
+		line += " - " + agent.GetLastError()
+	}
+
+	return line
+}
+
+func formatUnixTime(seconds int64) string {
+	return time.Unix(seconds, 0).Format("2006-01-02 15:04:05")
+}
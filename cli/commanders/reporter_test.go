@@ -2,9 +2,12 @@ package commanders_test
 
 import (
 	"errors"
+	"io"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
 
 	"github.com/golang/mock/gomock"
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
@@ -38,16 +41,28 @@ var _ = Describe("Reporter", func() {
 
 	Describe("StatusConversion", func() {
 		It("prints cluster status returned from hub", func() {
-			status := []string{"cluster status"}
 			spyClient.statusConversionReply = &pb.StatusConversionReply{
-				ConversionStatuses: status,
+				ConversionStatuses: []*pb.ConversionStatus{{
+					Dbid:     1,
+					Content:  -1,
+					Role:     pb.SegmentRole_MASTER,
+					Hostname: "mdw",
+					Status:   pb.StepStatus_COMPLETE,
+				}, {
+					Dbid:     3,
+					Content:  1,
+					Role:     pb.SegmentRole_PRIMARY,
+					Hostname: "sdw1",
+					Status:   pb.StepStatus_PENDING,
+				}},
 			}
 
 			err := reporter.OverallConversionStatus()
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(spyClient.statusConversionCount).To(Equal(1))
-			Expect(testLogFile.Contents()).To(ContainSubstring("cluster status"))
+			Expect(testLogFile.Contents()).To(ContainSubstring("COMPLETE - DBID 1 - CONTENT ID -1 - MASTER - mdw"))
+			Expect(testLogFile.Contents()).To(ContainSubstring("PENDING - DBID 3 - CONTENT ID 1 - PRIMARY - sdw1"))
 		})
 
 		It("returns an error upon a failure", func() {
@@ -66,6 +81,67 @@ var _ = Describe("Reporter", func() {
 		})
 	})
 
+	Describe("FormatConversionStatus", func() {
+		It("includes the phase and start time of a running conversion", func() {
+			start := time.Date(2018, 6, 1, 12, 0, 0, 0, time.Local)
+			line := commanders.FormatConversionStatus(&pb.ConversionStatus{
+				Dbid:        3,
+				Content:     1,
+				Role:        pb.SegmentRole_PRIMARY,
+				Hostname:    "sdw1",
+				Status:      pb.StepStatus_RUNNING,
+				StartTime:   start.Unix(),
+				Phase:       "Copying user relation files",
+				PercentDone: 60,
+			})
+
+			Expect(line).To(Equal("RUNNING - DBID 3 - CONTENT ID 1 - PRIMARY - sdw1 - 60% - Copying user relation files - started 2018-06-01 12:00:00"))
+		})
+
+		It("includes the end time and error message of a failed conversion", func() {
+			start := time.Date(2018, 6, 1, 12, 0, 0, 0, time.Local)
+			line := commanders.FormatConversionStatus(&pb.ConversionStatus{
+				Dbid:         3,
+				Content:      1,
+				Role:         pb.SegmentRole_PRIMARY,
+				Hostname:     "sdw1",
+				Status:       pb.StepStatus_FAILED,
+				StartTime:    start.Unix(),
+				EndTime:      start.Add(time.Minute).Unix(),
+				ErrorMessage: "pg_upgrade failed",
+			})
+
+			Expect(line).To(Equal("FAILED - DBID 3 - CONTENT ID 1 - PRIMARY - sdw1 - started 2018-06-01 12:00:00 - finished 2018-06-01 12:01:00 - pg_upgrade failed"))
+		})
+	})
+
+	Describe("AgentStatus", func() {
+		It("prints the hub's connection to each agent", func() {
+			client := mockpb.NewMockCliToHubClient(ctrl)
+			retry := time.Date(2018, 6, 1, 12, 0, 0, 0, time.Local)
+			client.EXPECT().StatusAgents(gomock.Any(), &pb.StatusAgentsRequest{}).Return(&pb.StatusAgentsReply{
+				Agents: []*pb.AgentStatus{
+					{Hostname: "sdw1", Healthy: true, State: "READY"},
+					{Hostname: "sdw2", State: "IDLE", Failures: 2, LastError: "connection refused", RetryAt: retry.Unix()},
+				},
+			}, nil)
+
+			err := commanders.NewReporter(client).AgentStatus()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(testLogFile.Contents()).To(ContainSubstring("sdw1 - READY\n"))
+			Expect(testLogFile.Contents()).To(ContainSubstring("sdw2 - IDLE - 2 failed dials - not retrying before 2018-06-01 12:00:00 - connection refused"))
+		})
+
+		It("returns an error upon a failure", func() {
+			client := mockpb.NewMockCliToHubClient(ctrl)
+			client.EXPECT().StatusAgents(gomock.Any(), &pb.StatusAgentsRequest{}).Return(nil, errors.New("some error"))
+
+			err := commanders.NewReporter(client).AgentStatus()
+			Expect(err).To(MatchError(ContainSubstring("some error")))
+		})
+	})
+
 	Describe("StatusUpgrade", func() {
 		It("returns an error upon a failure", func() {
 			spyClient.err = errors.New("some error")
@@ -86,6 +162,94 @@ var _ = Describe("Reporter", func() {
 			Expect(testLogFile.Contents()).To(ContainSubstring("PENDING - Run pg_upgrade on master"))
 		})
 
+		It("reports how a step went on each host", func() {
+			spyClient.statusUpgradeReply = &pb.StatusUpgradeReply{
+				ListOfUpgradeStepStatuses: []*pb.UpgradeStepStatus{
+					{Step: pb.UpgradeSteps_SHARE_OIDS, Status: pb.StepStatus_FAILED, Hosts: []*pb.HostStepStatus{
+						{Hostname: "sdw1", Status: pb.StepStatus_COMPLETE},
+						{Hostname: "sdw2", Status: pb.StepStatus_FAILED, Error: "disk full"},
+					}},
+				},
+			}
+			err := reporter.OverallUpgradeStatus()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testLogFile).To(gbytes.Say("FAILED - Copy OID files from master to segments"))
+			Expect(testLogFile).To(gbytes.Say("    COMPLETE on sdw1\n"))
+			Expect(testLogFile).To(gbytes.Say("    FAILED on sdw2: disk full"))
+		})
+
+		It("explains why a step failed", func() {
+			spyClient.statusUpgradeReply = &pb.StatusUpgradeReply{
+				ListOfUpgradeStepStatuses: []*pb.UpgradeStepStatus{
+					{Step: pb.UpgradeSteps_INIT_CLUSTER, Status: pb.StepStatus_FAILED, Failure: &pb.StepFailure{
+						Error:    "gpinitsystem failed: exit status 2",
+						Command:  "gpinitsystem -a -I /home/gpadmin/.gpupgrade/gpinitsystem_config",
+						ExitCode: 2,
+						LogFile:  "/home/gpadmin/gpAdminLogs/gpinitsystem_20180301.log",
+					}},
+					{Step: pb.UpgradeSteps_CONFIG, Status: pb.StepStatus_FAILED, Failure: &pb.StepFailure{
+						Error: "could not save the source cluster configuration",
+					}},
+				},
+			}
+			err := reporter.OverallUpgradeStatus()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testLogFile).To(gbytes.Say("FAILED - Initialize upgrade target cluster"))
+			Expect(testLogFile).To(gbytes.Say("    Error: gpinitsystem failed: exit status 2"))
+			Expect(testLogFile).To(gbytes.Say("    Command: gpinitsystem -a -I /home/gpadmin/.gpupgrade/gpinitsystem_config"))
+			Expect(testLogFile).To(gbytes.Say("    Exit code: 2"))
+			Expect(testLogFile).To(gbytes.Say("    See /home/gpadmin/gpAdminLogs/gpinitsystem_20180301.log for details"))
+			Expect(testLogFile).To(gbytes.Say("FAILED - Configuration Check"))
+			Expect(testLogFile).To(gbytes.Say("    Error: could not save the source cluster configuration"))
+			Expect(testLogFile).ToNot(gbytes.Say("Command:"))
+		})
+
+		It("reports how long each step took, and how long is left", func() {
+			spyClient.statusUpgradeReply = &pb.StatusUpgradeReply{
+				ListOfUpgradeStepStatuses: []*pb.UpgradeStepStatus{
+					{Step: pb.UpgradeSteps_CONVERT_MASTER, Status: pb.StepStatus_COMPLETE, Timing: &pb.StepTiming{
+						ElapsedSeconds: 60,
+					}},
+					{Step: pb.UpgradeSteps_CONVERT_PRIMARIES, Status: pb.StepStatus_RUNNING, Timing: &pb.StepTiming{
+						ElapsedSeconds:   725,
+						RemainingSeconds: 475,
+						UsualSeconds:     1200,
+						EarlierRuns:      3,
+					}},
+					{Step: pb.UpgradeSteps_REBUILD_MIRRORS, Status: pb.StepStatus_PENDING},
+				},
+				ElapsedSeconds:   785,
+				RemainingSeconds: 1075,
+			}
+			err := reporter.OverallUpgradeStatus()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testLogFile).To(gbytes.Say("COMPLETE - Run pg_upgrade on master"))
+			Expect(testLogFile).To(gbytes.Say("    Took 1m\n"))
+			Expect(testLogFile).To(gbytes.Say("RUNNING - Run pg_upgrade on primaries"))
+			Expect(testLogFile).To(gbytes.Say(`    Running for 12m5s \(20m on average in 3 earlier runs; about 7m55s left\)`))
+			Expect(testLogFile).To(gbytes.Say("PENDING - Copy upgraded primaries to their mirrors"))
+			Expect(testLogFile).To(gbytes.Say("Elapsed: 13m5s"))
+			Expect(testLogFile).To(gbytes.Say("Estimated time remaining: 17m55s"))
+		})
+
+		It("does not estimate the time left when it cannot", func() {
+			spyClient.statusUpgradeReply = &pb.StatusUpgradeReply{
+				ListOfUpgradeStepStatuses: []*pb.UpgradeStepStatus{
+					{Step: pb.UpgradeSteps_CONVERT_MASTER, Status: pb.StepStatus_RUNNING, Timing: &pb.StepTiming{
+						ElapsedSeconds:   30,
+						RemainingSeconds: -1,
+					}},
+				},
+				ElapsedSeconds:   30,
+				RemainingSeconds: -1,
+			}
+			err := reporter.OverallUpgradeStatus()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testLogFile).To(gbytes.Say("    Running for 30s\n"))
+			Expect(testLogFile).To(gbytes.Say("Elapsed: 30s"))
+			Expect(testLogFile).ToNot(gbytes.Say("remaining"))
+		})
+
 		It("returns an error when the hub returns no error, but the reply has an empty list", func() {
 			By("having an empty status list")
 			spyClient.statusUpgradeReply = &pb.StatusUpgradeReply{}
@@ -112,7 +276,67 @@ var _ = Describe("Reporter", func() {
 			Entry("prepare init cluster", pb.UpgradeSteps_INIT_CLUSTER, pb.StepStatus_FAILED, "FAILED - Initialize upgrade target cluster"),
 			Entry("upgrade on master", pb.UpgradeSteps_CONVERT_MASTER, pb.StepStatus_PENDING, "PENDING - Run pg_upgrade on master"),
 			Entry("shutdown cluster", pb.UpgradeSteps_SHUTDOWN_CLUSTERS, pb.StepStatus_PENDING, "PENDING - Shutdown clusters"),
+			Entry("rebuild mirrors", pb.UpgradeSteps_REBUILD_MIRRORS, pb.StepStatus_RUNNING, "RUNNING - Copy upgraded primaries to their mirrors"),
+			Entry("rebuild standby", pb.UpgradeSteps_REBUILD_STANDBY, pb.StepStatus_PENDING, "PENDING - Copy upgraded master to the standby"),
 			Entry("reconfigure ports", pb.UpgradeSteps_RECONFIGURE_PORTS, pb.StepStatus_PENDING, "PENDING - Adjust upgraded cluster ports"),
 		)
 	})
+
+	Describe("FollowUpgradeStatus", func() {
+		var (
+			client *mockpb.MockCliToHubClient
+			stream *mockpb.MockCliToHub_WatchUpgradeClient
+		)
+
+		BeforeEach(func() {
+			client = mockpb.NewMockCliToHubClient(ctrl)
+			stream = mockpb.NewMockCliToHub_WatchUpgradeClient(ctrl)
+			reporter = commanders.NewReporter(client)
+
+			client.EXPECT().WatchUpgrade(
+				gomock.Any(),
+				&pb.WatchUpgradeRequest{},
+			).Return(stream, nil)
+		})
+
+		event := func(step pb.UpgradeSteps, status pb.StepStatus) *pb.WatchUpgradeReply {
+			return &pb.WatchUpgradeReply{
+				StepStatus: &pb.UpgradeStepStatus{Step: step, Status: status},
+			}
+		}
+
+		It("prints every status change until the hub ends the stream", func() {
+			gomock.InOrder(
+				stream.EXPECT().Recv().Return(event(pb.UpgradeSteps_CONFIG, pb.StepStatus_COMPLETE), nil),
+				stream.EXPECT().Recv().Return(event(pb.UpgradeSteps_SEGINSTALL, pb.StepStatus_RUNNING), nil),
+				stream.EXPECT().Recv().Return(event(pb.UpgradeSteps_SEGINSTALL, pb.StepStatus_COMPLETE), nil),
+				stream.EXPECT().Recv().Return(nil, io.EOF),
+			)
+
+			err := reporter.FollowUpgradeStatus()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(testLogFile).To(gbytes.Say("COMPLETE - Configuration Check"))
+			Expect(testLogFile).To(gbytes.Say("RUNNING - Install binaries on segments"))
+			Expect(testLogFile).To(gbytes.Say("COMPLETE - Install binaries on segments"))
+		})
+
+		It("returns an error if a step failed", func() {
+			gomock.InOrder(
+				stream.EXPECT().Recv().Return(event(pb.UpgradeSteps_CONFIG, pb.StepStatus_FAILED), nil),
+				stream.EXPECT().Recv().Return(nil, io.EOF),
+			)
+
+			err := reporter.FollowUpgradeStatus()
+			Expect(err).To(MatchError("upgrade step CONFIG failed"))
+			Expect(testLogFile).To(gbytes.Say("FAILED - Configuration Check"))
+		})
+
+		It("returns an error if the stream breaks", func() {
+			stream.EXPECT().Recv().Return(nil, errors.New("connection reset"))
+
+			err := reporter.FollowUpgradeStatus()
+			Expect(err).To(MatchError(ContainSubstring("connection reset")))
+		})
+	})
 })
@@ -0,0 +1,40 @@
+package commanders
+
+import (
+	"context"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+)
+
+type Reverter struct {
+	client pb.CliToHubClient
+}
+
+func NewReverter(client pb.CliToHubClient) *Reverter {
+	return &Reverter{client: client}
+}
+
+// Revert asks the hub to throw away the target cluster and bring the source
+// cluster back up.
+func (r *Reverter) Revert(dryRun bool) error {
+	if dryRun {
+		reply, err := r.client.Revert(context.Background(), &pb.RevertRequest{DryRun: true})
+		if err != nil {
+			return errors.Wrap(err, "hub returned an error while planning the revert")
+		}
+		return ReportPlans(reply.GetPlan())
+	}
+
+	gplog.Info("Reverting the upgrade. The target cluster and its data directories will be removed.")
+
+	_, err := r.client.Revert(context.Background(), &pb.RevertRequest{})
+	if err != nil {
+		return errors.Wrap(err, "hub returned an error while reverting the upgrade")
+	}
+
+	gplog.Info("Revert complete; the source cluster is running")
+	return nil
+}
@@ -0,0 +1,56 @@
+package commanders_test
+
+import (
+	"errors"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+
+	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("reverter", func() {
+	var (
+		client     *mockpb.MockCliToHubClient
+		ctrl       *gomock.Controller
+		testStdout *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		testStdout, _, _ = testhelper.SetupTestLogger()
+
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+	})
+
+	AfterEach(func() {
+		defer ctrl.Finish()
+	})
+
+	It("asks the hub to revert", func() {
+		client.EXPECT().Revert(
+			gomock.Any(),
+			&pb.RevertRequest{},
+		).Return(&pb.RevertReply{}, nil)
+
+		err := commanders.NewReverter(client).Revert(false)
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(testStdout).Should(gbytes.Say("Revert complete"))
+	})
+
+	It("returns an error when the hub returns an error", func() {
+		client.EXPECT().Revert(
+			gomock.Any(),
+			&pb.RevertRequest{},
+		).Return(nil, errors.New("hub error"))
+
+		err := commanders.NewReverter(client).Revert(false)
+		Expect(err).To(MatchError(ContainSubstring("hub error")))
+	})
+})
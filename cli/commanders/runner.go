@@ -0,0 +1,83 @@
+package commanders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+)
+
+type Runner struct {
+	client pb.CliToHubClient
+}
+
+func NewRunner(client pb.CliToHubClient) *Runner {
+	return &Runner{client: client}
+}
+
+// Run asks the hub to execute every upgrade step in order, and blocks until
+// the hub has either finished them all or stopped at a failed step.
+func (r *Runner) Run(dryRun bool) error {
+	if dryRun {
+		reply, err := r.client.Run(context.Background(), &pb.RunRequest{DryRun: true})
+		if err != nil {
+			return errors.Wrap(err, "hub returned an error while planning the upgrade")
+		}
+		return ReportPlans(reply.GetPlans()...)
+	}
+
+	gplog.Info("Running all upgrade steps. This may take a while; use \"gpupgrade status upgrade\" to follow along.")
+
+	reply, err := r.client.Run(context.Background(), &pb.RunRequest{})
+	if err != nil {
+		return errors.Wrap(err, "hub returned an error while running the upgrade")
+	}
+
+	if failed := reply.GetFailedStep(); failed != pb.UpgradeSteps_UNKNOWN_STEP {
+		return failedStepError(failed)
+	}
+
+	gplog.Info("All upgrade steps completed successfully")
+	return nil
+}
+
+// Resume asks the hub to pick the upgrade back up at the first step that has
+// not completed, and blocks like Run does.
+func (r *Runner) Resume(dryRun bool) error {
+	reply, err := r.client.Resume(context.Background(), &pb.ResumeRequest{DryRun: dryRun})
+	if err != nil {
+		return errors.Wrap(err, "hub returned an error while resuming the upgrade")
+	}
+
+	resumed := reply.GetResumedStep()
+	if resumed == pb.UpgradeSteps_UNKNOWN_STEP {
+		gplog.Info("All upgrade steps have already completed; there is nothing to resume")
+		return nil
+	}
+
+	if dryRun {
+		gplog.Info("The upgrade would resume at step %v (%s)", resumed, stepDescription(resumed))
+		return ReportPlans(reply.GetPlans()...)
+	}
+
+	gplog.Info("Resumed the upgrade at step %v (%s)", resumed, stepDescription(resumed))
+
+	if failed := reply.GetFailedStep(); failed != pb.UpgradeSteps_UNKNOWN_STEP {
+		return failedStepError(failed)
+	}
+
+	gplog.Info("All upgrade steps completed successfully")
+	return nil
+}
+
+func stepDescription(step pb.UpgradeSteps) string {
+	return strings.TrimPrefix(UpgradeStepsMessage[step], "- ")
+}
+
+func failedStepError(failed pb.UpgradeSteps) error {
+	return fmt.Errorf("upgrade stopped at failed step %v (%s). Fix the problem, then run \"gpupgrade resume\"", failed, stepDescription(failed))
+}
@@ -0,0 +1,115 @@
+package commanders_test
+
+import (
+	"errors"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+
+	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("runner", func() {
+	var (
+		client     *mockpb.MockCliToHubClient
+		ctrl       *gomock.Controller
+		testStdout *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		testStdout, _, _ = testhelper.SetupTestLogger()
+
+		ctrl = gomock.NewController(GinkgoT())
+		client = mockpb.NewMockCliToHubClient(ctrl)
+	})
+
+	AfterEach(func() {
+		defer ctrl.Finish()
+	})
+
+	It("reports success when every step completes", func() {
+		client.EXPECT().Run(
+			gomock.Any(),
+			&pb.RunRequest{},
+		).Return(&pb.RunReply{}, nil)
+
+		err := commanders.NewRunner(client).Run(false)
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(testStdout).Should(gbytes.Say("All upgrade steps completed successfully"))
+	})
+
+	It("names the step that failed", func() {
+		client.EXPECT().Run(
+			gomock.Any(),
+			&pb.RunRequest{},
+		).Return(&pb.RunReply{FailedStep: pb.UpgradeSteps_SHARE_OIDS}, nil)
+
+		err := commanders.NewRunner(client).Run(false)
+		Expect(err).To(MatchError(ContainSubstring("SHARE_OIDS")))
+		Expect(err).To(MatchError(ContainSubstring("Copy OID files from master to segments")))
+	})
+
+	It("returns an error when the hub returns an error", func() {
+		client.EXPECT().Run(
+			gomock.Any(),
+			&pb.RunRequest{},
+		).Return(nil, errors.New("hub error"))
+
+		err := commanders.NewRunner(client).Run(false)
+		Expect(err).To(MatchError(ContainSubstring("hub error")))
+	})
+
+	Describe("Resume", func() {
+		It("reports the step it resumed at", func() {
+			client.EXPECT().Resume(
+				gomock.Any(),
+				&pb.ResumeRequest{},
+			).Return(&pb.ResumeReply{ResumedStep: pb.UpgradeSteps_INIT_CLUSTER}, nil)
+
+			err := commanders.NewRunner(client).Resume(false)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(testStdout).Should(gbytes.Say("Resumed the upgrade at step INIT_CLUSTER"))
+			Eventually(testStdout).Should(gbytes.Say("All upgrade steps completed successfully"))
+		})
+
+		It("reports when there is nothing left to do", func() {
+			client.EXPECT().Resume(
+				gomock.Any(),
+				&pb.ResumeRequest{},
+			).Return(&pb.ResumeReply{}, nil)
+
+			err := commanders.NewRunner(client).Resume(false)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(testStdout).Should(gbytes.Say("nothing to resume"))
+		})
+
+		It("names the step that failed", func() {
+			client.EXPECT().Resume(
+				gomock.Any(),
+				&pb.ResumeRequest{},
+			).Return(&pb.ResumeReply{
+				ResumedStep: pb.UpgradeSteps_INIT_CLUSTER,
+				FailedStep:  pb.UpgradeSteps_CONVERT_MASTER,
+			}, nil)
+
+			err := commanders.NewRunner(client).Resume(false)
+			Expect(err).To(MatchError(ContainSubstring("CONVERT_MASTER")))
+		})
+
+		It("returns an error when the hub returns an error", func() {
+			client.EXPECT().Resume(
+				gomock.Any(),
+				&pb.ResumeRequest{},
+			).Return(nil, errors.New("hub error"))
+
+			err := commanders.NewRunner(client).Resume(false)
+			Expect(err).To(MatchError(ContainSubstring("hub error")))
+		})
+	})
+})
@@ -16,59 +16,130 @@ func NewUpgrader(client pb.CliToHubClient) *Upgrader {
 	return &Upgrader{client: client}
 }
 
-func (u *Upgrader) ConvertMaster() error {
-	_, err := u.client.UpgradeConvertMaster(context.Background(), &pb.UpgradeConvertMasterRequest{})
+func (u *Upgrader) ConvertMaster(skipPrerequisites, dryRun bool) error {
+	reply, err := u.client.UpgradeConvertMaster(context.Background(), &pb.UpgradeConvertMasterRequest{
+		SkipPrerequisites: skipPrerequisites,
+		DryRun:            dryRun,
+	})
 	if err != nil {
 		// TODO: Change the logging message?
 		gplog.Error("ERROR - Unable to connect to hub")
 		return err
 	}
 
+	if dryRun {
+		return ReportPlans(stepPlan(pb.UpgradeSteps_CONVERT_MASTER, reply.GetPlan()))
+	}
+
 	gplog.Info("Kicked off pg_upgrade request.")
 	return nil
 }
 
-func (u *Upgrader) ConvertPrimaries() error {
-	_, err := u.client.UpgradeConvertPrimaries(context.Background(), &pb.UpgradeConvertPrimariesRequest{})
+func (u *Upgrader) ConvertPrimaries(skipPrerequisites, dryRun bool) error {
+	reply, err := u.client.UpgradeConvertPrimaries(context.Background(), &pb.UpgradeConvertPrimariesRequest{
+		SkipPrerequisites: skipPrerequisites,
+		DryRun:            dryRun,
+	})
 	if err != nil {
 		// TODO: Change the logging message?
 		gplog.Error("Error when calling hub upgrade convert primaries: %v", err.Error())
 		return err
 	}
 
+	if dryRun {
+		return ReportPlans(stepPlan(pb.UpgradeSteps_CONVERT_PRIMARIES, reply.GetPlan()))
+	}
+
 	gplog.Info("Kicked off pg_upgrade request for primaries")
 	return nil
 }
 
-func (u *Upgrader) ShareOids() error {
-	_, err := u.client.UpgradeShareOids(context.Background(), &pb.UpgradeShareOidsRequest{})
+func (u *Upgrader) ShareOids(skipPrerequisites, dryRun bool) error {
+	reply, err := u.client.UpgradeShareOids(context.Background(), &pb.UpgradeShareOidsRequest{
+		SkipPrerequisites: skipPrerequisites,
+		DryRun:            dryRun,
+	})
 	if err != nil {
 		gplog.Error(err.Error())
 		return err
 	}
 
+	if dryRun {
+		return ReportPlans(stepPlan(pb.UpgradeSteps_SHARE_OIDS, reply.GetPlan()))
+	}
+
 	gplog.Info("Kicked off request to share oids")
 	return nil
 }
 
-func (u *Upgrader) ValidateStartCluster() error {
-	_, err := u.client.UpgradeValidateStartCluster(context.Background(), &pb.UpgradeValidateStartClusterRequest{})
+func (u *Upgrader) RebuildMirrors(skipPrerequisites, dryRun bool) error {
+	reply, err := u.client.UpgradeRebuildMirrors(context.Background(), &pb.UpgradeRebuildMirrorsRequest{
+		SkipPrerequisites: skipPrerequisites,
+		DryRun:            dryRun,
+	})
+	if err != nil {
+		gplog.Error(err.Error())
+		return err
+	}
+
+	if dryRun {
+		return ReportPlans(stepPlan(pb.UpgradeSteps_REBUILD_MIRRORS, reply.GetPlan()))
+	}
+
+	gplog.Info("Kicked off request to rebuild mirrors")
+	return nil
+}
+
+func (u *Upgrader) RebuildStandby(skipPrerequisites, dryRun bool) error {
+	reply, err := u.client.UpgradeRebuildStandby(context.Background(), &pb.UpgradeRebuildStandbyRequest{
+		SkipPrerequisites: skipPrerequisites,
+		DryRun:            dryRun,
+	})
 	if err != nil {
 		gplog.Error(err.Error())
 		return err
 	}
 
+	if dryRun {
+		return ReportPlans(stepPlan(pb.UpgradeSteps_REBUILD_STANDBY, reply.GetPlan()))
+	}
+
+	gplog.Info("Kicked off request to rebuild the standby master")
+	return nil
+}
+
+func (u *Upgrader) ValidateStartCluster(skipPrerequisites, dryRun bool) error {
+	reply, err := u.client.UpgradeValidateStartCluster(context.Background(), &pb.UpgradeValidateStartClusterRequest{
+		SkipPrerequisites: skipPrerequisites,
+		DryRun:            dryRun,
+	})
+	if err != nil {
+		gplog.Error(err.Error())
+		return err
+	}
+
+	if dryRun {
+		return ReportPlans(stepPlan(pb.UpgradeSteps_VALIDATE_START_CLUSTER, reply.GetPlan()))
+	}
+
 	gplog.Info("Kicked off request for validation of cluster startup")
 	return nil
 }
 
-func (u *Upgrader) ReconfigurePorts() error {
-	_, err := u.client.UpgradeReconfigurePorts(context.Background(), &pb.UpgradeReconfigurePortsRequest{})
+func (u *Upgrader) ReconfigurePorts(skipPrerequisites, dryRun bool) error {
+	reply, err := u.client.UpgradeReconfigurePorts(context.Background(), &pb.UpgradeReconfigurePortsRequest{
+		SkipPrerequisites: skipPrerequisites,
+		DryRun:            dryRun,
+	})
 	if err != nil {
 		gplog.Error(err.Error())
 		return err
 	}
 
+	if dryRun {
+		return ReportPlans(stepPlan(pb.UpgradeSteps_RECONFIGURE_PORTS, reply.GetPlan()))
+	}
+
 	gplog.Info("Request to reconfigure master port on upgraded cluster complete")
 	return nil
 }
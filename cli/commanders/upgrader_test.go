@@ -49,7 +49,7 @@ var _ = Describe("reporter", func() {
 				gomock.Any(),
 				&pb.UpgradeConvertMasterRequest{},
 			).Return(&pb.UpgradeConvertMasterReply{}, nil)
-			err := commanders.NewUpgrader(client).ConvertMaster()
+			err := commanders.NewUpgrader(client).ConvertMaster(false, false)
 			Expect(err).To(BeNil())
 			Eventually(testStdout).Should(gbytes.Say("Kicked off pg_upgrade request"))
 		})
@@ -59,7 +59,7 @@ var _ = Describe("reporter", func() {
 				gomock.Any(),
 				&pb.UpgradeConvertMasterRequest{},
 			).Return(&pb.UpgradeConvertMasterReply{}, errors.New("something bad happened"))
-			err := commanders.NewUpgrader(client).ConvertMaster()
+			err := commanders.NewUpgrader(client).ConvertMaster(false, false)
 			Expect(err).ToNot(BeNil())
 			Eventually(testStderr).Should(gbytes.Say("ERROR - Unable to connect to hub"))
 
@@ -68,37 +68,44 @@ var _ = Describe("reporter", func() {
 
 	Describe("ConvertPrimaries", func() {
 		It("returns no error when the hub returns no error", func() {
-			err := upgrader.ConvertPrimaries()
+			err := upgrader.ConvertPrimaries(false, false)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("returns an error when the hub returns an error", func() {
 			hubClient.Err = errors.New("hub error")
 
-			err := upgrader.ConvertPrimaries()
+			err := upgrader.ConvertPrimaries(false, false)
 			Expect(err).To(HaveOccurred())
 		})
 	})
 
 	Describe("ShareOids", func() {
 		It("returns no error when oids are shared successfully", func() {
-			err := upgrader.ShareOids()
+			err := upgrader.ShareOids(false, false)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(hubClient.UpgradeShareOidsRequest).To(Equal(&pb.UpgradeShareOidsRequest{}))
 		})
 
+		It("asks the hub to skip the prerequisite check when requested", func() {
+			err := upgrader.ShareOids(true, false)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(hubClient.UpgradeShareOidsRequest).To(Equal(&pb.UpgradeShareOidsRequest{SkipPrerequisites: true}))
+		})
+
 		It("returns an error when oids cannot be shared", func() {
 			hubClient.Err = errors.New("test share oids failed")
 
-			err := upgrader.ShareOids()
+			err := upgrader.ShareOids(false, false)
 			Expect(err).To(HaveOccurred())
 		})
 	})
 
 	Describe("ReconfigurePorts", func() {
 		It("returns nil error when ports are reconfigured successfully", func() {
-			err := upgrader.ReconfigurePorts()
+			err := upgrader.ReconfigurePorts(false, false)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(hubClient.UpgradeReconfigurePortsRequest).To(Equal(&pb.UpgradeReconfigurePortsRequest{}))
@@ -107,7 +114,7 @@ var _ = Describe("reporter", func() {
 		It("returns error when ports cannot be reconfigured", func() {
 			hubClient.Err = errors.New("reconfigure ports failed")
 
-			err := upgrader.ReconfigurePorts()
+			err := upgrader.ReconfigurePorts(false, false)
 			Expect(err).To(HaveOccurred())
 		})
 	})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
 	pb "github.com/greenplum-db/gpupgrade/idl"
@@ -16,7 +17,61 @@ import (
 	"google.golang.org/grpc"
 )
 
-var root = &cobra.Command{Use: "gpupgrade"}
+var root = &cobra.Command{
+	Use:               "gpupgrade",
+	PersistentPreRunE: setOutputFormat,
+}
+
+// outputFormat holds the global --format flag.
+var outputFormat string
+
+// setOutputFormat applies the global --format flag before any command runs.
+// Structured output is written to stdout, so gplog is kept from printing INFO
+// lines there, and cobra from printing errors and usage that would corrupt it.
+func setOutputFormat(cmd *cobra.Command, args []string) error {
+	format, err := commanders.ParseFormat(outputFormat)
+	if err != nil {
+		return err
+	}
+
+	commanders.OutputFormat = format
+	if format != commanders.FormatText {
+		gplog.SetVerbosity(gplog.LOGERROR)
+		cmd.Root().SilenceErrors = true
+		cmd.Root().SilenceUsage = true
+	}
+	return nil
+}
+
+// exitWithError reports err in the requested output format and exits.
+func exitWithError(err error) {
+	commanders.ReportError(err)
+	os.Exit(utils.GetExitCodeForError(err))
+}
+
+// skipPrerequisites is shared by every command that starts a checklist step.
+var skipPrerequisites bool
+
+// addSkipPrerequisitesFlag lets the given step commands bypass the hub's check
+// that the steps they depend on have completed.
+func addSkipPrerequisitesFlag(cmds ...*cobra.Command) {
+	for _, cmd := range cmds {
+		cmd.Flags().BoolVar(&skipPrerequisites, "skip-prerequisites", false,
+			"start the step even if the steps it depends on have not completed")
+	}
+}
+
+// dryRun is shared by every command that changes either cluster.
+var dryRun bool
+
+// addDryRunFlag lets the given commands report what they would do, and where,
+// instead of doing it.
+func addDryRunFlag(cmds ...*cobra.Command) {
+	for _, cmd := range cmds {
+		cmd.Flags().BoolVar(&dryRun, "dry-run", false,
+			"print the commands that would be run and the files that would be written, without changing anything")
+	}
+}
 
 var prepare = &cobra.Command{
 	Use:   "prepare",
@@ -57,6 +112,80 @@ var config = &cobra.Command{
 	Long:  "subcommands to set parameters for subsequent gpupgrade commands",
 }
 
+var run = &cobra.Command{
+	Use:   "run",
+	Short: "runs every upgrade step in order",
+	Long: "Runs each step of the upgrade in order, waiting for each one to complete before starting the next. " +
+		"Stops at the first step that fails and reports which step needs to be fixed before rerunning.",
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
+		if connConfigErr != nil {
+			exitWithError(connConfigErr)
+		}
+		client := pb.NewCliToHubClient(conn)
+		err := commanders.NewRunner(client).Run(dryRun)
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+var resume = &cobra.Command{
+	Use:   "resume",
+	Short: "resumes an interrupted upgrade",
+	Long: "Picks an interrupted or failed upgrade back up at the first step that has not completed, " +
+		"rerunning any completed step whose results are no longer in place, and then runs the remaining steps in order.",
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
+		if connConfigErr != nil {
+			exitWithError(connConfigErr)
+		}
+		client := pb.NewCliToHubClient(conn)
+		err := commanders.NewRunner(client).Resume(dryRun)
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+var revert = &cobra.Command{
+	Use:   "revert",
+	Short: "reverts to the source cluster",
+	Long: "Stops the target cluster, deletes its data directories on every host, restarts the source cluster, " +
+		"and resets the upgrade checklist so that the upgrade can be started over.",
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
+		if connConfigErr != nil {
+			exitWithError(connConfigErr)
+		}
+		client := pb.NewCliToHubClient(conn)
+		err := commanders.NewReverter(client).Revert(dryRun)
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+var cancel = &cobra.Command{
+	Use:   "cancel <step>",
+	Short: "cancels a running pg_upgrade step",
+	Long: "Stops the pg_upgrade processes of a running convert-master or convert-primaries step, " +
+		"along with any servers that they left running, and marks the step failed so that it can be " +
+		"run again or the upgrade reverted.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
+		if connConfigErr != nil {
+			exitWithError(connConfigErr)
+		}
+		client := pb.NewCliToHubClient(conn)
+		err := commanders.NewCanceller(client).Cancel(args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
 var subStartHub = &cobra.Command{
 	Use:   "start-hub",
 	Short: "starts the hub",
@@ -65,22 +194,18 @@ var subStartHub = &cobra.Command{
 		preparer := commanders.Preparer{}
 		err := preparer.StartHub()
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 		err = preparer.VerifyConnectivity(client)
 
 		if err != nil {
-			gplog.Error("gpupgrade is unable to connect via gRPC to the hub")
-			gplog.Error("%v", err)
-			os.Exit(1)
+			exitWithError(errors.Wrap(err, "gpupgrade is unable to connect via gRPC to the hub"))
 		}
 	},
 }
@@ -90,17 +215,15 @@ var subShutdownClusters = &cobra.Command{
 	Short: "shuts down both old and new cluster",
 	Long:  "Current assumptions is both clusters exist.",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 		preparer := commanders.NewPreparer(client)
-		err := preparer.ShutdownClusters()
+		err := preparer.ShutdownClusters(skipPrerequisites, dryRun)
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -110,17 +233,15 @@ var subStartAgents = &cobra.Command{
 	Short: "start agents on segment hosts",
 	Long:  "start agents on all segments",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 		preparer := commanders.NewPreparer(client)
-		err := preparer.StartAgents()
+		err := preparer.StartAgents(skipPrerequisites, dryRun)
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -130,17 +251,15 @@ var subInitCluster = &cobra.Command{
 	Short: "inits the cluster",
 	Long:  "Current assumptions is that the cluster already exists. And will only generate json config file for now.",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 		preparer := commanders.NewPreparer(client)
-		err := preparer.InitCluster()
+		err := preparer.InitCluster(skipPrerequisites, dryRun)
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -155,19 +274,35 @@ func createSetSubcommand() *cobra.Command {
 				return errors.New("the set command requires at least one flag to be specified")
 			}
 
-			conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+			conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
 			if connConfigErr != nil {
 				return connConfigErr
 			}
 			client := pb.NewCliToHubClient(conn)
 
 			var requests []*pb.SetConfigRequest
+			var pathErr error
 			cmd.Flags().Visit(func(flag *pflag.Flag) {
+				value := flag.Value.String()
+
+				// The hub reads the port map itself, from its own working
+				// directory.
+				if flag.Name == "target-port-map" && value != "" {
+					var err error
+					value, err = filepath.Abs(value)
+					if err != nil {
+						pathErr = err
+					}
+				}
+
 				requests = append(requests, &pb.SetConfigRequest{
 					Name:  flag.Name,
-					Value: flag.Value.String(),
+					Value: value,
 				})
 			})
+			if pathErr != nil {
+				return pathErr
+			}
 
 			for _, request := range requests {
 				_, err := client.SetConfig(context.Background(), request)
@@ -183,6 +318,10 @@ func createSetSubcommand() *cobra.Command {
 
 	subSet.Flags().String("old-bindir", "", "install directory for old gpdb version")
 	subSet.Flags().String("new-bindir", "", "install directory for new gpdb version")
+	subSet.Flags().String("target-port-range", "", `ports for the target cluster, as "base" or "base-max"; each host is given ports from base upwards`)
+	subSet.Flags().String("target-port-map", "", `file of "<host> <source port> <target port>" lines giving every target port explicitly`)
+	subSet.Flags().String("target-datadir-template", "", "target data directory for each segment, from {parent}, {base}, {content} and {dbid} of its source data directory (default \"{parent}_upgrade/{base}\")")
+	subSet.Flags().String("mode", "", `how pg_upgrade carries data over: "copy" (default) or "link", which hard-links user data instead of copying it, but can't be reverted once the upgrade has started converting the cluster`)
 
 	return subSet
 }
@@ -193,7 +332,7 @@ func createShowSubcommand() *cobra.Command {
 		Short: "show configuration settings",
 		Long:  "show configuration settings",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+			conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
 			if connConfigErr != nil {
 				return connConfigErr
 			}
@@ -218,13 +357,16 @@ func createShowSubcommand() *cobra.Command {
 			}
 
 			// Make the requests and print every response.
+			settings := make(map[string]string)
 			for _, request := range requests {
 				resp, err := client.GetConfig(context.Background(), request)
 				if err != nil {
 					return err
 				}
 
-				if cmd.Flags().NFlag() == 1 {
+				if commanders.OutputFormat != commanders.FormatText {
+					settings[request.Name] = resp.Value
+				} else if cmd.Flags().NFlag() == 1 {
 					// Don't prefix with the setting name if the user only asked for one.
 					fmt.Println(resp.Value)
 				} else {
@@ -232,12 +374,20 @@ func createShowSubcommand() *cobra.Command {
 				}
 			}
 
+			if commanders.OutputFormat != commanders.FormatText {
+				return commanders.WriteOutput(commanders.ConfigOutput{Settings: settings})
+			}
+
 			return nil
 		},
 	}
 
 	subShow.Flags().Bool("old-bindir", false, "show install directory for old gpdb version")
 	subShow.Flags().Bool("new-bindir", false, "show install directory for new gpdb version")
+	subShow.Flags().Bool("target-port-range", false, "show the port range for the target cluster")
+	subShow.Flags().Bool("target-port-map", false, "show the port map file for the target cluster")
+	subShow.Flags().Bool("target-datadir-template", false, "show the data directory template for the target cluster")
+	subShow.Flags().Bool("mode", false, "show the mode pg_upgrade runs in")
 
 	return subShow
 }
@@ -247,17 +397,21 @@ var subUpgrade = &cobra.Command{
 	Short: "the status of the upgrade",
 	Long:  "the status of the upgrade",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 		reporter := commanders.NewReporter(client)
-		err := reporter.OverallUpgradeStatus()
+
+		var err error
+		if follow, _ := cmd.Flags().GetBool("follow"); follow {
+			err = reporter.FollowUpgradeStatus()
+		} else {
+			err = reporter.OverallUpgradeStatus()
+		}
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -269,10 +423,9 @@ var subVersion = &cobra.Command{
 	Aliases: []string{"ver"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+			hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 		return commanders.NewVersionChecker(client).Execute()
@@ -286,27 +439,66 @@ var subObjectCount = &cobra.Command{
 	Aliases: []string{"oc"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+			hubTransport())
 		if connConfigErr != nil {
-			fmt.Println(connConfigErr)
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 		return commanders.NewObjectCountChecker(client).Execute()
 	},
 }
 
+var subCatalog = &cobra.Command{
+	Use:   "catalog",
+	Short: "check the catalog for objects that pg_upgrade cannot upgrade",
+	Long: `check every database in the source cluster for objects that pg_upgrade
+cannot upgrade, such as columns of unsupported types, and explain how to fix
+each one`,
+	Aliases: []string{"cat"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
+			hubTransport())
+		if connConfigErr != nil {
+			exitWithError(connConfigErr)
+		}
+		client := pb.NewCliToHubClient(conn)
+		return commanders.NewCatalogChecker(client).Execute()
+	},
+}
+
+var subPorts = &cobra.Command{
+	Use:   "ports",
+	Short: "check that the ports planned for the target cluster are free",
+	Long: `check on every host that nothing is listening on the ports planned for
+the target cluster, and report the process holding any port that is taken`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
+			hubTransport())
+		if connConfigErr != nil {
+			exitWithError(connConfigErr)
+		}
+		client := pb.NewCliToHubClient(conn)
+		return commanders.NewPortChecker(client).Execute()
+	},
+}
+
 var subDiskSpace = &cobra.Command{
-	Use:     "disk-space",
-	Short:   "check that disk space usage is less than 80% on all segments",
-	Long:    "check that disk space usage is less than 80% on all segments",
+	Use:   "disk-space",
+	Short: "check that every segment has disk space for its upgrade",
+	Long: `check that every segment has disk space for its upgrade
+
+For each segment, the size of its data directory is compared with the space
+free on the filesystem that will hold its target data directory. A copy-mode
+upgrade needs a full copy of the data directory; a link-mode upgrade only
+copies what isn't user data. Mirrors and the standby master always need a
+full copy. Segments whose target data directories share a filesystem must
+fit on it together.`,
 	Aliases: []string{"du"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+			hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 		return commanders.NewDiskSpaceChecker(client).Execute()
@@ -318,17 +510,33 @@ var subConversion = &cobra.Command{
 	Short: "the status of the conversion",
 	Long:  "the status of the conversion",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 		reporter := commanders.NewReporter(client)
 		err := reporter.OverallConversionStatus()
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
+		}
+	},
+}
+
+var subAgents = &cobra.Command{
+	Use:   "agents",
+	Short: "the status of the hub's connections to the agents",
+	Long:  "the status of the hub's connections to the agents",
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
+		if connConfigErr != nil {
+			exitWithError(connConfigErr)
+		}
+		client := pb.NewCliToHubClient(conn)
+		reporter := commanders.NewReporter(client)
+		err := reporter.AgentStatus()
+		if err != nil {
+			exitWithError(err)
 		}
 	},
 }
@@ -339,16 +547,14 @@ var subConfig = &cobra.Command{
 	Long:  "gather cluster configuration",
 	Run: func(cmd *cobra.Command, args []string) {
 		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+			hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 		err := commanders.NewConfigChecker(client).Execute()
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -359,21 +565,21 @@ var subSeginstall = &cobra.Command{
 	Long: "Running this command will validate that the new software is installed on all segments, " +
 		"and register successful or failed validation (available in `gpupgrade status upgrade`)",
 	Run: func(cmd *cobra.Command, args []string) {
-		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, grpc.WithInsecure())
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort, hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 		client := pb.NewCliToHubClient(conn)
 
-		err := commanders.NewSeginstallChecker(client).Execute()
+		err := commanders.NewSeginstallChecker(client).Execute(skipPrerequisites)
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 
-		fmt.Println("Seginstall is underway. Use command \"gpupgrade status upgrade\" " +
-			"to check its current status, and/or hub logs for possible errors.")
+		if commanders.OutputFormat == commanders.FormatText {
+			fmt.Println("Seginstall is underway. Use command \"gpupgrade status upgrade\" " +
+				"to check its current status, and/or hub logs for possible errors.")
+		}
 	},
 }
 
@@ -383,17 +589,15 @@ var subConvertMaster = &cobra.Command{
 	Long:  `start upgrade process on master`,
 	Run: func(cmd *cobra.Command, args []string) {
 		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+			hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 
 		client := pb.NewCliToHubClient(conn)
-		err := commanders.NewUpgrader(client).ConvertMaster()
+		err := commanders.NewUpgrader(client).ConvertMaster(skipPrerequisites, dryRun)
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -404,17 +608,15 @@ var subConvertPrimaries = &cobra.Command{
 	Long:  `start upgrade process on primary segments`,
 	Run: func(cmd *cobra.Command, args []string) {
 		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+			hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 
 		client := pb.NewCliToHubClient(conn)
-		err := commanders.NewUpgrader(client).ConvertPrimaries()
+		err := commanders.NewUpgrader(client).ConvertPrimaries(skipPrerequisites, dryRun)
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -425,17 +627,53 @@ var subShareOids = &cobra.Command{
 	Long:  `share oid files generated by pg_upgrade on master, across cluster`,
 	Run: func(cmd *cobra.Command, args []string) {
 		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+			hubTransport())
+		if connConfigErr != nil {
+			exitWithError(connConfigErr)
+		}
+
+		client := pb.NewCliToHubClient(conn)
+		err := commanders.NewUpgrader(client).ShareOids(skipPrerequisites, dryRun)
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+var subRebuildMirrors = &cobra.Command{
+	Use:   "rebuild-mirrors",
+	Short: "Copy upgraded primaries to their mirrors",
+	Long:  `Overwrite each mirror of the upgraded cluster with a copy of its upgraded primary. Does nothing if the cluster has no mirrors`,
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
+			hubTransport())
+		if connConfigErr != nil {
+			exitWithError(connConfigErr)
+		}
+
+		client := pb.NewCliToHubClient(conn)
+		err := commanders.NewUpgrader(client).RebuildMirrors(skipPrerequisites, dryRun)
+		if err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+var subRebuildStandby = &cobra.Command{
+	Use:   "rebuild-standby",
+	Short: "Copy upgraded master to the standby master",
+	Long:  `Overwrite the standby master of the upgraded cluster with a copy of the upgraded master. Does nothing if the cluster has no standby`,
+	Run: func(cmd *cobra.Command, args []string) {
+		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
+			hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 
 		client := pb.NewCliToHubClient(conn)
-		err := commanders.NewUpgrader(client).ShareOids()
+		err := commanders.NewUpgrader(client).RebuildStandby(skipPrerequisites, dryRun)
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -446,17 +684,15 @@ var subValidateStartCluster = &cobra.Command{
 	Long:  `Use gpstart in order to validate that the new cluster can successfully transition from a stopped to running state`,
 	Run: func(cmd *cobra.Command, args []string) {
 		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+			hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 
 		client := pb.NewCliToHubClient(conn)
-		err := commanders.NewUpgrader(client).ValidateStartCluster()
+		err := commanders.NewUpgrader(client).ValidateStartCluster(skipPrerequisites, dryRun)
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -467,17 +703,15 @@ var subReconfigurePorts = &cobra.Command{
 	Long:  `Set master port on upgraded cluster to the value from the older cluster`,
 	Run: func(cmd *cobra.Command, args []string) {
 		conn, connConfigErr := grpc.Dial("localhost:"+hubPort,
-			grpc.WithInsecure())
+			hubTransport())
 		if connConfigErr != nil {
-			gplog.Error(connConfigErr.Error())
-			os.Exit(1)
+			exitWithError(connConfigErr)
 		}
 
 		client := pb.NewCliToHubClient(conn)
-		err := commanders.NewUpgrader(client).ReconfigurePorts()
+		err := commanders.NewUpgrader(client).ReconfigurePorts(skipPrerequisites, dryRun)
 		if err != nil {
-			gplog.Error(err.Error())
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -5,26 +5,36 @@ import (
 	"log"
 	"os"
 	"runtime/debug"
+	"strconv"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/certs"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
 )
 
 var (
-	hubPort = "7527"
+	hubPort = strconv.Itoa(utils.DefaultHubPort)
+	useTLS  = false
 )
 
 func main() {
-	upgradePort := os.Getenv("GPUPGRADE_HUB_PORT")
-	if upgradePort != "" {
-		hubPort = upgradePort
-	}
-
 	setUpLogging()
 
+	// The hub reads the same settings, so the two agree on its port.
+	settings, err := utils.LoadSettings(utils.GetStateDir())
+	if err != nil {
+		exitWithError(err)
+	}
+	hubPort = strconv.Itoa(settings.HubPort)
+	useTLS = settings.TLS
+
 	confirmValidCommand()
 
-	root.AddCommand(prepare, config, status, check, version, upgrade)
+	root.AddCommand(prepare, config, status, check, version, upgrade, run, resume, revert, cancel)
 
 	subInit := createInitSubcommand()
 	prepare.AddCommand(subStartHub, subInitCluster, subShutdownClusters, subStartAgents, subInit)
@@ -33,21 +43,51 @@ func main() {
 	subShow := createShowSubcommand()
 	config.AddCommand(subSet, subShow)
 
-	status.AddCommand(subUpgrade, subConversion)
-	check.AddCommand(subVersion, subObjectCount, subDiskSpace, subConfig, subSeginstall)
-	upgrade.AddCommand(subConvertMaster, subConvertPrimaries, subShareOids, subValidateStartCluster, subReconfigurePorts)
+	status.AddCommand(subUpgrade, subConversion, subAgents)
+	subUpgrade.Flags().BoolP("follow", "f", false, "keep reporting status changes until the upgrade finishes or fails")
+	check.AddCommand(subVersion, subObjectCount, subCatalog, subPorts, subDiskSpace, subConfig, subSeginstall)
+	upgrade.AddCommand(subConvertMaster, subConvertPrimaries, subShareOids, subRebuildMirrors, subRebuildStandby,
+		subValidateStartCluster, subReconfigurePorts)
+
+	addSkipPrerequisitesFlag(subInitCluster, subShutdownClusters, subStartAgents, subSeginstall,
+		subConvertMaster, subConvertPrimaries, subShareOids, subRebuildMirrors, subRebuildStandby,
+		subValidateStartCluster, subReconfigurePorts)
+	addDryRunFlag(subInitCluster, subShutdownClusters, subStartAgents,
+		subConvertMaster, subConvertPrimaries, subShareOids, subRebuildMirrors, subRebuildStandby,
+		subValidateStartCluster, subReconfigurePorts, run, resume, revert)
 
-	err := root.Execute()
+	root.PersistentFlags().StringVar(&outputFormat, "format", "text", "output format: text, json or yaml")
+
+	err = root.Execute()
 	if err != nil {
+		if commanders.OutputFormat != commanders.FormatText {
+			exitWithError(err)
+		}
 		// Use v to print the stack trace of an object errors.
 		fmt.Printf("%+v\n", err)
 		os.Exit(1)
 	}
 }
 
+// hubTransport secures the connection to the hub. With TLS on, the CLI
+// presents the hub's own certificate, which it can read since the two share a
+// state directory. The certificate is only loaded when it's needed, since the
+// hub creates it when it first starts.
+func hubTransport() grpc.DialOption {
+	if !useTLS {
+		return grpc.WithInsecure()
+	}
+
+	creds, err := certs.ClientCredentials(certs.Dir(utils.GetStateDir()), certs.HUB_CERT, certs.HUB_KEY)
+	if err != nil {
+		exitWithError(err)
+	}
+	return grpc.WithTransportCredentials(creds)
+}
+
 func confirmValidCommand() {
 	if len(os.Args[1:]) < 1 {
-		log.Fatal("Please specify one command of: check, config, prepare, status, upgrade, or version")
+		log.Fatal("Please specify one command of: cancel, check, config, prepare, resume, revert, run, status, upgrade, or version")
 	}
 }
 
@@ -4,12 +4,13 @@ import (
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/greenplum-db/gpupgrade/hub/services"
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
-	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/certs"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
 	"github.com/greenplum-db/gpupgrade/utils/log"
 	"github.com/pkg/errors"
@@ -22,6 +23,9 @@ import (
 
 func main() {
 	var logdir string
+	var port, agentPort, parallelism int
+	var dialTimeout, agentTimeout time.Duration
+	var useTLS bool
 	var shouldDaemonize bool
 
 	var RootCmd = &cobra.Command{
@@ -29,16 +33,58 @@ func main() {
 		Short: "Start the gpupgrade_hub (blocks)",
 		Long:  `Start the gpupgrade_hub (blocks)`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			gplog.InitializeLogging("gpupgrade_hub", logdir)
+			stateDir := utils.GetStateDir()
+			settings, err := utils.LoadSettings(stateDir)
+			if err != nil {
+				return err
+			}
+
+			// Flags that were given explicitly win over the settings file
+			// and the environment.
+			flags := cmd.Flags()
+			if flags.Changed("port") {
+				settings.HubPort = port
+			}
+			if flags.Changed("agent-port") {
+				settings.AgentPort = agentPort
+			}
+			if flags.Changed("log-directory") {
+				settings.LogDir = logdir
+			}
+			if flags.Changed("dial-timeout") {
+				settings.DialTimeout = dialTimeout
+			}
+			if flags.Changed("parallelism") {
+				settings.Parallelism = parallelism
+			}
+			if flags.Changed("agent-timeout") {
+				settings.AgentTimeout = agentTimeout
+			}
+			if flags.Changed("tls") {
+				settings.TLS = useTLS
+			}
+			if err := settings.Validate(); err != nil {
+				return err
+			}
+
+			conf := services.NewHubConfig(stateDir, settings)
+
+			gplog.InitializeLogging("gpupgrade_hub", conf.LogDir)
 			debug.SetTraceback("all")
 			defer log.WritePanics()
 
-			conf := &services.HubConfig{
-				CliToHubPort:   7527,
-				HubToAgentPort: 6416,
-				StateDir:       utils.GetStateDir(),
-				LogDir:         logdir,
+			if conf.TLS {
+				// The CLI always connects to the hub through localhost.
+				names := []string{"localhost", "127.0.0.1", "::1"}
+				if hostname, err := utils.System.Hostname(); err == nil {
+					names = append(names, hostname)
+				}
+				_, err = certs.EnsureHubCerts(certs.Dir(conf.StateDir), names...)
+				if err != nil {
+					return errors.Wrap(err, "Unable to set up TLS certificates")
+				}
 			}
+
 			source := &utils.Cluster{ConfigPath: filepath.Join(conf.StateDir, utils.SOURCE_CONFIG_FILENAME)}
 			target := &utils.Cluster{ConfigPath: filepath.Join(conf.StateDir, utils.TARGET_CONFIG_FILENAME)}
 			cm := upgradestatus.NewChecklistManager(conf.StateDir)
@@ -57,45 +103,20 @@ func main() {
 
 			hub := services.NewHub(source, target, grpc.DialContext, conf, cm)
 
-			// Set up the checklist steps in order.
-			//
-			// TODO: make sure the implementations here, and the Checklist below, are
-			// fully exercised in end-to-end tests. It feels like we should be able to
-			// pull these into a Hub method or helper function, but currently the
-			// interfaces aren't well componentized.
-			cm.AddWritableStep(upgradestatus.CONFIG, pb.UpgradeSteps_CONFIG)
-			cm.AddWritableStep(upgradestatus.SEGINSTALL, pb.UpgradeSteps_SEGINSTALL)
-			cm.AddWritableStep(upgradestatus.INIT_CLUSTER, pb.UpgradeSteps_INIT_CLUSTER)
-
-			cm.AddReadOnlyStep(upgradestatus.SHUTDOWN_CLUSTERS, pb.UpgradeSteps_SHUTDOWN_CLUSTERS,
-				func(stepName string) pb.StepStatus {
-					stepdir := filepath.Join(conf.StateDir, stepName)
-					return upgradestatus.ClusterShutdownStatus(stepdir, source.Executor)
-				})
-
-			cm.AddReadOnlyStep(upgradestatus.CONVERT_MASTER, pb.UpgradeSteps_CONVERT_MASTER,
-				func(stepName string) pb.StepStatus {
-					convertMasterPath := filepath.Join(conf.StateDir, stepName)
-					sourceDataDir := source.MasterDataDir()
-					return upgradestatus.SegmentConversionStatus(convertMasterPath, sourceDataDir, source.Executor)
-				})
-
-			cm.AddWritableStep(upgradestatus.START_AGENTS, pb.UpgradeSteps_START_AGENTS)
-			cm.AddWritableStep(upgradestatus.SHARE_OIDS, pb.UpgradeSteps_SHARE_OIDS)
-
-			cm.AddReadOnlyStep(upgradestatus.CONVERT_PRIMARIES, pb.UpgradeSteps_CONVERT_PRIMARIES,
-				func(stepName string) pb.StepStatus {
-					return services.PrimaryConversionStatus(hub)
-				})
-
-			cm.AddWritableStep(upgradestatus.VALIDATE_START_CLUSTER, pb.UpgradeSteps_VALIDATE_START_CLUSTER)
-			cm.AddWritableStep(upgradestatus.RECONFIGURE_PORTS, pb.UpgradeSteps_RECONFIGURE_PORTS)
+			// TODO: make sure the steps are fully exercised in end-to-end
+			// tests.
+			hub.AddSteps(cm)
+
+			err = cm.ImportMarkerFiles()
+			if err != nil {
+				return err
+			}
 
 			if shouldDaemonize {
 				hub.MakeDaemon()
 			}
 
-			err := hub.Start()
+			err = hub.Start()
 			if err != nil {
 				return err
 			}
@@ -107,6 +128,12 @@ func main() {
 	}
 
 	RootCmd.PersistentFlags().StringVar(&logdir, "log-directory", "", "gpupgrade_hub log directory")
+	RootCmd.PersistentFlags().IntVar(&port, "port", utils.DefaultHubPort, "port to listen on for the gpupgrade CLI")
+	RootCmd.PersistentFlags().IntVar(&agentPort, "agent-port", utils.DefaultAgentPort, "port that the gpupgrade_agents listen on")
+	RootCmd.PersistentFlags().DurationVar(&dialTimeout, "dial-timeout", utils.DefaultDialTimeout, "how long to wait when connecting to a gpupgrade_agent")
+	RootCmd.PersistentFlags().IntVar(&parallelism, "parallelism", utils.DefaultParallelism, "how many hosts to work on at once")
+	RootCmd.PersistentFlags().DurationVar(&agentTimeout, "agent-timeout", utils.DefaultAgentTimeout, "how long to wait for a gpupgrade_agent to answer each call")
+	RootCmd.PersistentFlags().BoolVar(&useTLS, "tls", false, "authenticate the CLI and the gpupgrade_agents with certificates")
 
 	daemon.MakeDaemonizable(RootCmd, &shouldDaemonize)
 
@@ -6,27 +6,23 @@ import (
 	"net"
 	"os"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/certs"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
 	"github.com/greenplum-db/gpupgrade/utils/log"
 
 	"github.com/pkg/errors"
 
 	"github.com/greenplum-db/gp-common-go-libs/cluster"
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/reflection"
 )
 
-var DialTimeout = 3 * time.Second
-
 // Returned from Hub.Start() if Hub.Stop() has already been called.
 var ErrHubStopped = errors.New("hub is stopped")
 
@@ -35,11 +31,18 @@ type Dialer func(ctx context.Context, target string, opts ...grpc.DialOption) (*
 type Hub struct {
 	conf *HubConfig
 
-	agentConns []*Connection
+	agents     *AgentPool
 	source     *utils.Cluster
 	target     *utils.Cluster
 	grpcDialer Dialer
 	checklist  upgradestatus.Checklist
+	runs       *upgradestatus.Runs
+	tasks      *TaskSupervisor
+
+	// upgrading names the command, run, resume or revert, that is working
+	// through the checklist; only one of them may at a time.
+	upgradeMu sync.Mutex
+	upgrading string
 
 	mu     sync.Mutex
 	server *grpc.Server
@@ -69,9 +72,38 @@ type HubConfig struct {
 	HubToAgentPort int
 	StateDir       string
 	LogDir         string
+	DialTimeout    time.Duration // defaults to utils.DefaultDialTimeout
+	Parallelism    int           // defaults to utils.DefaultParallelism
+	AgentTimeout   time.Duration // defaults to utils.DefaultAgentTimeout
+	TLS            bool          // serve, and dial the agents, with the certificates in StateDir
+}
+
+// NewHubConfig fills in a HubConfig from the settings, which the hub
+// executable reads from the settings file, the environment and its flags.
+func NewHubConfig(stateDir string, settings utils.Settings) *HubConfig {
+	return &HubConfig{
+		CliToHubPort:   settings.HubPort,
+		HubToAgentPort: settings.AgentPort,
+		StateDir:       stateDir,
+		LogDir:         settings.LogDir,
+		DialTimeout:    settings.DialTimeout,
+		Parallelism:    settings.Parallelism,
+		AgentTimeout:   settings.AgentTimeout,
+		TLS:            settings.TLS,
+	}
 }
 
 func NewHub(sourceCluster *utils.Cluster, targetCluster *utils.Cluster, grpcDialer Dialer, conf *HubConfig, checklist upgradestatus.Checklist) *Hub {
+	if conf.DialTimeout == 0 {
+		conf.DialTimeout = utils.DefaultDialTimeout
+	}
+	if conf.Parallelism == 0 {
+		conf.Parallelism = utils.DefaultParallelism
+	}
+	if conf.AgentTimeout == 0 {
+		conf.AgentTimeout = utils.DefaultAgentTimeout
+	}
+
 	h := &Hub{
 		stopped:    make(chan struct{}, 1),
 		conf:       conf,
@@ -79,7 +111,10 @@ func NewHub(sourceCluster *utils.Cluster, targetCluster *utils.Cluster, grpcDial
 		target:     targetCluster,
 		grpcDialer: grpcDialer,
 		checklist:  checklist,
+		runs:       upgradestatus.NewRuns(conf.StateDir),
+		tasks:      NewTaskSupervisor(conf.StateDir),
 	}
+	h.agents = NewAgentPool(grpcDialer, conf.HubToAgentPort, conf.DialTimeout, h.AgentTransport)
 
 	return h
 }
@@ -102,7 +137,24 @@ func (h *Hub) Start() error {
 		defer log.WritePanics()
 		return handler(ctx, req)
 	}
-	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+	options := []grpc.ServerOption{grpc.UnaryInterceptor(interceptor)}
+	if h.conf.TLS {
+		creds, err := certs.ServerCredentials(certs.Dir(h.conf.StateDir), certs.HUB_CERT, certs.HUB_KEY)
+		if err != nil {
+			lis.Close()
+			return err
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	server := grpc.NewServer(options...)
+
+	// Holding the port means that no other hub is running, so any step still
+	// marked RUNNING that this one isn't running was interrupted.
+	err = h.FailInterruptedSteps()
+	if err != nil {
+		lis.Close()
+		return err
+	}
 
 	h.mu.Lock()
 	if h.stopped == nil {
@@ -134,11 +186,11 @@ func (h *Hub) Start() error {
 }
 
 func (h *Hub) Stop() {
+	h.agents.Close()
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	h.closeConns()
-
 	if h.server != nil {
 		h.server.Stop()
 		<-h.stopped // block until it is OK to stop
@@ -149,74 +201,32 @@ func (h *Hub) Stop() {
 	h.stopped = nil
 }
 
-func (h *Hub) AgentConns() ([]*Connection, error) {
-	// Lock the mutex to protect against races with Hub.Stop().
-	// XXX This is a *ridiculously* broad lock. Have fun waiting for the dial
-	// timeout when calling Stop() and AgentConns() at the same time, for
-	// instance. We should not lock around a network operation, but it seems
-	// like the AgentConns concept is not long for this world anyway.
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	if h.agentConns != nil {
-		err := EnsureConnsAreReady(h.agentConns)
-		if err != nil {
-			gplog.Error("ensureConnsAreReady failed: ", err)
-			return nil, err
-		}
-
-		return h.agentConns, nil
-	}
-
-	hostnames := h.source.GetHostnames()
-	for _, host := range hostnames {
-		ctx, cancelFunc := context.WithTimeout(context.Background(), DialTimeout)
-		conn, err := h.grpcDialer(ctx,
-			host+":"+strconv.Itoa(h.conf.HubToAgentPort),
-			grpc.WithInsecure(), grpc.WithBlock())
-		if err != nil {
-			err = errors.Errorf("grpcDialer failed: %s", err.Error())
-			gplog.Error(err.Error())
-			cancelFunc()
-			return nil, err
-		}
-		h.agentConns = append(h.agentConns, &Connection{
-			Conn:          conn,
-			AgentClient:   pb.NewAgentClient(conn),
-			Hostname:      host,
-			CancelContext: cancelFunc,
-		})
+// AgentConns returns ready connections to the agents on the given hosts, or
+// on every host in the source cluster if none are given.
+func (h *Hub) AgentConns(hostnames ...string) ([]*Connection, error) {
+	if len(hostnames) == 0 {
+		hostnames = h.source.GetHostnames()
 	}
-
-	return h.agentConns, nil
+	return h.agents.Conns(hostnames...)
 }
 
-func EnsureConnsAreReady(agentConns []*Connection) error {
-	hostnames := []string{}
-	for _, conn := range agentConns {
-		if conn.Conn.GetState() != connectivity.Ready {
-			hostnames = append(hostnames, conn.Hostname)
-		}
-	}
+// AgentHealth reports on the agent on every host in the source cluster.
+func (h *Hub) AgentHealth() []AgentHealth {
+	return h.agents.Health(sortedHostnames(h.source)...)
+}
 
-	if len(hostnames) > 0 {
-		return fmt.Errorf("the connections to the following hosts were not ready: %s", strings.Join(hostnames, ","))
+// AgentTransport is the DialOption that secures connections to the agents:
+// the hub's certificate when TLS is on, and nothing otherwise.
+func (h *Hub) AgentTransport() (grpc.DialOption, error) {
+	if !h.conf.TLS {
+		return grpc.WithInsecure(), nil
 	}
 
-	return nil
-}
-
-// Closes all h.agentConns. Callers must hold the Hub's mutex.
-func (h *Hub) closeConns() {
-	for _, conn := range h.agentConns {
-		defer conn.CancelContext()
-		currState := conn.Conn.GetState()
-		err := conn.Conn.Close()
-		if err != nil {
-			gplog.Info(fmt.Sprintf("Error closing hub to agent connection. host: %s, err: %s", conn.Hostname, err.Error()))
-		}
-		conn.Conn.WaitForStateChange(context.Background(), currState)
+	creds, err := certs.ClientCredentials(certs.Dir(h.conf.StateDir), certs.HUB_CERT, certs.HUB_KEY)
+	if err != nil {
+		return nil, err
 	}
+	return grpc.WithTransportCredentials(creds), nil
 }
 
 func (h *Hub) segmentsByHost() map[string][]cluster.SegConfig {
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Returned by AgentPool.Conns once AgentPool.Close has been called.
+var ErrAgentPoolClosed = errors.New("connections to the agents are closed")
+
+const (
+	DefaultMinBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// AgentPool keeps a connection to the agent on each host. A connection that
+// stops being ready is dropped and dialed again the next time it is asked
+// for. After a failed dial, the pool waits before dialing that host again,
+// twice as long after each consecutive failure.
+//
+// Dials happen outside the pool's lock, so Close never waits on them.
+type AgentPool struct {
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	dialer      Dialer
+	port        int
+	dialTimeout time.Duration
+	transport   func() (grpc.DialOption, error)
+
+	// Cancelled by Close, which abandons the dials in progress.
+	ctx    context.Context
+	cancel func()
+
+	mu     sync.Mutex
+	hosts  map[string]*agentHost
+	closed bool
+}
+
+type agentHost struct {
+	conn *Connection
+
+	// Closed when the dial in progress finishes; nil when there is none.
+	dialing chan struct{}
+
+	failures int
+	lastErr  error
+	retryAt  time.Time
+}
+
+// AgentHealth is what the pool knows about the agent on one host.
+type AgentHealth struct {
+	Hostname  string
+	State     connectivity.State // Idle if the pool has no connection to it
+	Failures  int                // consecutive failed dials
+	LastError error              // from the last failed dial
+	RetryAt   time.Time          // the next dial will not happen before this
+}
+
+func (a AgentHealth) Healthy() bool {
+	return a.State == connectivity.Ready
+}
+
+// NewAgentPool dials agents on port with dialer, securing each connection
+// with the option that transport returns at the time.
+func NewAgentPool(dialer Dialer, port int, dialTimeout time.Duration, transport func() (grpc.DialOption, error)) *AgentPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &AgentPool{
+		MinBackoff:  DefaultMinBackoff,
+		MaxBackoff:  DefaultMaxBackoff,
+		dialer:      dialer,
+		port:        port,
+		dialTimeout: dialTimeout,
+		transport:   transport,
+		ctx:         ctx,
+		cancel:      cancel,
+		hosts:       map[string]*agentHost{},
+	}
+}
+
+// Reachable returns a ready connection to the agent on each of the hosts that
+// can be reached, in the order of the hosts, dialing any that need it at the
+// same time. Each host that can't be reached has its error in the returned
+// HostResults instead, so that the caller can carry on with the others.
+func (p *AgentPool) Reachable(hostnames ...string) ([]*Connection, HostResults) {
+	results := HostResults{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, hostname := range hostnames {
+		wg.Add(1)
+		go func(hostname string) {
+			defer wg.Done()
+
+			conn, err := p.conn(hostname)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[hostname] = HostResult{Reply: conn, Err: err}
+		}(hostname)
+	}
+	wg.Wait()
+
+	var conns []*Connection
+	unreachable := HostResults{}
+	for _, hostname := range hostnames {
+		result := results[hostname]
+		if result.Err != nil {
+			unreachable[hostname] = result
+			continue
+		}
+		conns = append(conns, result.Reply.(*Connection))
+	}
+	return conns, unreachable
+}
+
+// Conns is Reachable for callers that need every one of the hosts: unless
+// they can all be reached, the error names each one that can't.
+func (p *AgentPool) Conns(hostnames ...string) ([]*Connection, error) {
+	conns, unreachable := p.Reachable(hostnames...)
+	if err := unreachable.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not connect to the agents")
+	}
+	return conns, nil
+}
+
+func (p *AgentPool) conn(hostname string) (*Connection, error) {
+	p.mu.Lock()
+	for {
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrAgentPoolClosed
+		}
+
+		host, ok := p.hosts[hostname]
+		if !ok {
+			host = &agentHost{}
+			p.hosts[hostname] = host
+		}
+
+		if host.dialing == nil {
+			break
+		}
+
+		// Someone else is dialing this host; see how it went.
+		dialing := host.dialing
+		p.mu.Unlock()
+		<-dialing
+		p.mu.Lock()
+	}
+	defer p.mu.Unlock()
+
+	host := p.hosts[hostname]
+	if host.conn != nil {
+		if host.conn.Conn.GetState() == connectivity.Ready {
+			return host.conn, nil
+		}
+
+		gplog.Info("connection to the agent on %s is %s; reconnecting", hostname, host.conn.Conn.GetState())
+		closeConn(host.conn)
+		host.conn = nil
+	}
+
+	if wait := time.Until(host.retryAt); wait > 0 {
+		return nil, errors.Wrapf(host.lastErr, "not retrying for %s", wait.Round(time.Millisecond))
+	}
+
+	host.dialing = make(chan struct{})
+	p.mu.Unlock()
+	conn, err := p.dial(hostname)
+	p.mu.Lock()
+	close(host.dialing)
+	host.dialing = nil
+
+	if err == nil && p.closed {
+		closeConn(conn)
+		err = ErrAgentPoolClosed
+	}
+	if err != nil {
+		host.failures++
+		host.lastErr = err
+		host.retryAt = time.Now().Add(p.backoff(host.failures))
+		gplog.Error("could not connect to the agent on %s: %s", hostname, err)
+		return nil, err
+	}
+
+	host.conn = conn
+	host.failures = 0
+	host.lastErr = nil
+	host.retryAt = time.Time{}
+	return conn, nil
+}
+
+func (p *AgentPool) dial(hostname string) (*Connection, error) {
+	transport, err := p.transport()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(p.ctx, p.dialTimeout)
+	conn, err := p.dialer(ctx, hostname+":"+strconv.Itoa(p.port), transport, grpc.WithBlock())
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &Connection{
+		Conn:          conn,
+		AgentClient:   pb.NewAgentClient(conn),
+		Hostname:      hostname,
+		CancelContext: cancel,
+	}, nil
+}
+
+// backoff is how long to wait after the given number of consecutive failures.
+func (p *AgentPool) backoff(failures int) time.Duration {
+	backoff := p.MinBackoff
+	for i := 1; i < failures && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
+
+// Health reports on the agents on the given hosts, in the same order.
+func (p *AgentPool) Health(hostnames ...string) []AgentHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var health []AgentHealth
+	for _, hostname := range hostnames {
+		status := AgentHealth{Hostname: hostname, State: connectivity.Idle}
+		if host, ok := p.hosts[hostname]; ok {
+			if host.conn != nil {
+				status.State = host.conn.Conn.GetState()
+			}
+			status.Failures = host.failures
+			status.LastError = host.lastErr
+			status.RetryAt = host.retryAt
+		}
+		health = append(health, status)
+	}
+	return health
+}
+
+// Close closes every connection and abandons any dials in progress. The pool
+// cannot be used afterwards.
+func (p *AgentPool) Close() {
+	p.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for _, host := range p.hosts {
+		if host.conn != nil {
+			closeConn(host.conn)
+			host.conn = nil
+		}
+	}
+}
+
+func closeConn(conn *Connection) {
+	defer conn.CancelContext()
+
+	err := conn.Conn.Close()
+	if err != nil {
+		gplog.Info("Error closing hub to agent connection. host: %s, err: %s", conn.Hostname, err.Error())
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// The reason given for a step that was stopped with Cancel.
+var ErrStepCancelled = errors.New("cancelled")
+
+// Cancel stops the pg_upgrade processes of a running conversion step, and any
+// servers that they left running, and marks the step FAILED so that it can be
+// retried, or the upgrade reverted.
+func (h *Hub) Cancel(ctx context.Context, in *pb.CancelRequest) (*pb.CancelReply, error) {
+	var name string
+	var cancel func() ([]*pb.CancelledProcess, error)
+	switch in.Step {
+	case pb.UpgradeSteps_CONVERT_MASTER:
+		name, cancel = upgradestatus.CONVERT_MASTER, h.cancelConvertMaster
+	case pb.UpgradeSteps_CONVERT_PRIMARIES:
+		name, cancel = upgradestatus.CONVERT_PRIMARIES, h.cancelConvertPrimaries
+	default:
+		return &pb.CancelReply{}, fmt.Errorf("%s cannot be cancelled; only %s and %s can",
+			in.Step, upgradestatus.CONVERT_MASTER, upgradestatus.CONVERT_PRIMARIES)
+	}
+
+	gplog.Info("Cancelling %s", name)
+
+	step := h.checklist.GetStepReader(name)
+	if status := step.Status(); status != pb.StepStatus_RUNNING {
+		return &pb.CancelReply{}, fmt.Errorf("%s cannot be cancelled: it is %s, not RUNNING", name, status)
+	}
+
+	processes, err := cancel()
+	if err != nil {
+		gplog.Error("Could not cancel %s: %s", name, err)
+		return &pb.CancelReply{Processes: processes}, err
+	}
+	if len(processes) == 0 {
+		// pg_upgrade exited between the status check and now; whatever it
+		// left behind says whether the step completed or failed.
+		return &pb.CancelReply{}, fmt.Errorf("%s has nothing to cancel: no pg_upgrade is running", name)
+	}
+
+	err = h.checklist.SetFailure(name, ErrStepCancelled)
+	if err != nil {
+		return &pb.CancelReply{Processes: processes}, errors.Wrapf(err, "could not record that %s was cancelled", name)
+	}
+
+	gplog.Info("Cancelled %s", name)
+	return &pb.CancelReply{Processes: processes}, nil
+}
+
+func (h *Hub) cancelConvertMaster() ([]*pb.CancelledProcess, error) {
+	pid, err := utils.StopPgUpgrade(h.source.Executor, h.pgUpgradeWorkingDir(), utils.DefaultTerminateTimeout,
+		utils.PgServer{BinDir: h.source.BinDir, DataDir: h.source.MasterDataDir()},
+		utils.PgServer{BinDir: h.target.BinDir, DataDir: h.target.MasterDataDir()},
+	)
+	if err != nil || pid == 0 {
+		return nil, err
+	}
+
+	return []*pb.CancelledProcess{{Hostname: h.source.MasterHostname(), Content: -1, Pid: int32(pid)}}, nil
+}
+
+func (h *Hub) cancelConvertPrimaries() ([]*pb.CancelledProcess, error) {
+	dataDirPairs, err := h.getDataDirPairs()
+	if err != nil {
+		return nil, err
+	}
+
+	var hosts []string
+	for host := range dataDirPairs {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	fanOut, conns := h.agentFanOut(hosts...)
+
+	results := fanOut.Call(Clients(conns), func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
+		return client.Client.CancelConvertPrimarySegments(ctx, &pb.CancelConvertPrimarySegmentsRequest{
+			OldBinDir:    h.source.BinDir,
+			NewBinDir:    h.target.BinDir,
+			DataDirPairs: dataDirPairs[client.Hostname],
+		})
+	})
+
+	var processes []*pb.CancelledProcess
+	for _, conn := range conns {
+		result := results[conn.Hostname]
+		reply, ok := result.Reply.(*pb.CancelConvertPrimarySegmentsReply)
+		if !ok {
+			continue
+		}
+		for _, process := range reply.GetProcesses() {
+			processes = append(processes, &pb.CancelledProcess{
+				Hostname: conn.Hostname,
+				Content:  process.Content,
+				Pid:      process.Pid,
+			})
+		}
+	}
+
+	err = results.Err()
+	if err != nil {
+		return processes, errors.Wrap(err, "agents failed to cancel pg_upgrade on the primaries")
+	}
+	return processes, nil
+}
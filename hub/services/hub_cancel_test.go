@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"errors"
+	"path/filepath"
+	"syscall"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cancel", func() {
+	const pid = 4242
+
+	var testExecutor *testhelper.TestExecutor
+
+	BeforeEach(func() {
+		testExecutor = &testhelper.TestExecutor{}
+		source.Executor = testExecutor
+
+		cm.AddStep(upgradestatus.CONVERT_MASTER, pb.UpgradeSteps_CONVERT_MASTER)
+		cm.AddStep(upgradestatus.CONVERT_PRIMARIES, pb.UpgradeSteps_CONVERT_PRIMARIES)
+
+		// pg_upgrade exits as soon as it is asked to.
+		exited := false
+		utils.System.Kill = func(p int, sig syscall.Signal) error {
+			if sig != 0 {
+				exited = true
+			}
+			if exited {
+				return syscall.ESRCH
+			}
+			return nil
+		}
+		utils.System.ProcessArgs = func(p int) ([]string, error) {
+			return []string{"bash", "-c", "pg_upgrade"}, nil
+		}
+	})
+
+	Context("convert-master", func() {
+		BeforeEach(func() {
+			workingDir := filepath.Join(dir, "pg_upgrade")
+			err := utils.System.MkdirAll(workingDir, 0700)
+			Expect(err).ToNot(HaveOccurred())
+			err = utils.WritePidFile(filepath.Join(workingDir, utils.PgUpgradePidFile), pid)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("stops pg_upgrade on the master and marks the step cancelled", func() {
+			cm.GetStepWriter(upgradestatus.CONVERT_MASTER).MarkInProgress()
+
+			reply, err := hub.Cancel(nil, &pb.CancelRequest{Step: pb.UpgradeSteps_CONVERT_MASTER})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reply.Processes).To(Equal([]*pb.CancelledProcess{
+				{Hostname: source.MasterHostname(), Content: -1, Pid: pid},
+			}))
+			Expect(testExecutor.LocalCommands).To(ContainElement(
+				"pgrep -F " + filepath.Join(source.MasterDataDir(), "postmaster.pid")))
+
+			Expect(cm.IsFailed(upgradestatus.CONVERT_MASTER)).To(BeTrue())
+			Expect(cm.FailureError(upgradestatus.CONVERT_MASTER)).To(Equal(services.ErrStepCancelled))
+		})
+
+		It("has nothing to cancel if pg_upgrade exits before it can be stopped", func() {
+			cm.GetStepWriter(upgradestatus.CONVERT_MASTER).MarkInProgress()
+			utils.System.Kill = func(p int, sig syscall.Signal) error {
+				return syscall.ESRCH
+			}
+
+			_, err := hub.Cancel(nil, &pb.CancelRequest{Step: pb.UpgradeSteps_CONVERT_MASTER})
+			Expect(err).To(MatchError(ContainSubstring("nothing to cancel")))
+
+			Expect(cm.IsInProgress(upgradestatus.CONVERT_MASTER)).To(BeTrue())
+		})
+
+		It("refuses to cancel a step that is not running", func() {
+			_, err := hub.Cancel(nil, &pb.CancelRequest{Step: pb.UpgradeSteps_CONVERT_MASTER})
+			Expect(err).To(MatchError(ContainSubstring("it is PENDING, not RUNNING")))
+
+			Expect(testExecutor.NumExecutions).To(Equal(0))
+			Expect(cm.IsPending(upgradestatus.CONVERT_MASTER)).To(BeTrue())
+		})
+	})
+
+	Context("convert-primaries", func() {
+		BeforeEach(func() {
+			cm.GetStepWriter(upgradestatus.CONVERT_PRIMARIES).MarkInProgress()
+		})
+
+		It("asks the agents to stop pg_upgrade on their primaries and marks the step cancelled", func() {
+			mockAgent.CancelConvertPrimarySegmentsResponse = &pb.CancelConvertPrimarySegmentsReply{
+				Processes: []*pb.SegmentProcess{{Content: 0, Pid: pid}},
+			}
+
+			reply, err := hub.Cancel(nil, &pb.CancelRequest{Step: pb.UpgradeSteps_CONVERT_PRIMARIES})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(mockAgent.CancelConvertPrimarySegmentsRequest.OldBinDir).To(Equal("/source/bindir"))
+			Expect(mockAgent.CancelConvertPrimarySegmentsRequest.NewBinDir).To(Equal("/target/bindir"))
+			Expect(mockAgent.CancelConvertPrimarySegmentsRequest.DataDirPairs).To(HaveLen(2))
+
+			Expect(reply.Processes).To(Equal([]*pb.CancelledProcess{
+				{Hostname: "localhost", Content: 0, Pid: pid},
+			}))
+			Expect(cm.IsFailed(upgradestatus.CONVERT_PRIMARIES)).To(BeTrue())
+			Expect(cm.FailureError(upgradestatus.CONVERT_PRIMARIES)).To(Equal(services.ErrStepCancelled))
+		})
+
+		It("leaves the step running if an agent cannot stop pg_upgrade", func() {
+			mockAgent.Err <- errors.New("permission denied")
+
+			_, err := hub.Cancel(nil, &pb.CancelRequest{Step: pb.UpgradeSteps_CONVERT_PRIMARIES})
+			Expect(err).To(MatchError(ContainSubstring("permission denied")))
+
+			Expect(cm.IsInProgress(upgradestatus.CONVERT_PRIMARIES)).To(BeTrue())
+		})
+	})
+
+	It("refuses to cancel any other step", func() {
+		_, err := hub.Cancel(nil, &pb.CancelRequest{Step: pb.UpgradeSteps_SHARE_OIDS})
+		Expect(err).To(MatchError(ContainSubstring("only convert-master and convert-primaries can")))
+
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
+	})
+})
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"github.com/greenplum-db/gpupgrade/db"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// A CatalogCheck looks for objects that pg_upgrade can't carry over to the
+// target cluster. Its Query is run in every database of the source cluster and
+// must return one row, with an "object" and a "detail" column, per offending
+// object; no rows means the database passes.
+type CatalogCheck struct {
+	Name        string
+	Description string
+	Query       string
+	Remediation string // what the user can do about an object that was found
+}
+
+// CatalogChecks are run, in order, by CheckCatalog. Add a check here to have
+// it run by `gpupgrade check catalog`.
+var CatalogChecks = []CatalogCheck{
+	{
+		Name:        "unsupported-column-types",
+		Description: "columns of types whose values pg_upgrade can't preserve",
+		Query:       UNSUPPORTED_COLUMN_TYPES_QUERY,
+		Remediation: "Drop the column, or change its type, for example to text or regclass, with ALTER TABLE ... ALTER COLUMN ... TYPE.",
+	},
+	{
+		Name:        "partition-dropped-columns",
+		Description: "partitions whose dropped columns don't match their parent table's",
+		Query:       PARTITION_DROPPED_COLUMNS_QUERY,
+		Remediation: "Recreate the partition with CREATE TABLE ... (LIKE parent), copy its data over, and swap it in with ALTER TABLE ... EXCHANGE PARTITION.",
+	},
+	{
+		Name:        "views-on-removed-catalog-columns",
+		Description: "views that use catalog columns removed in the target version",
+		Query:       VIEWS_ON_REMOVED_CATALOG_COLUMNS_QUERY,
+		Remediation: "Drop the view before upgrading and recreate it afterwards without the removed column.",
+	},
+	{
+		Name:        "external-tables-unsupported-protocols",
+		Description: "external tables that use a protocol the target version doesn't support",
+		Query:       EXTERNAL_TABLES_UNSUPPORTED_PROTOCOLS_QUERY,
+		Remediation: "Drop the external table before upgrading and recreate it afterwards using a supported protocol, such as PXF in place of gphdfs.",
+	},
+}
+
+func (h *Hub) CheckCatalog(ctx context.Context,
+	in *pb.CheckCatalogRequest) (*pb.CheckCatalogReply, error) {
+
+	gplog.Info("starting CheckCatalog")
+
+	masterPort := h.source.MasterPort()
+
+	dbConnector := db.NewDBConn("localhost", masterPort, "template1")
+	defer dbConnector.Close()
+	err := dbConnector.Connect(1)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckCatalogReply{}, utils.DatabaseConnectionError{Parent: err}
+	}
+	dbConnector.Version.Initialize(dbConnector)
+	names, err := dbconn.SelectStringSlice(dbConnector, GET_DATABASE_NAMES)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckCatalogReply{}, errors.New(err.Error())
+	}
+
+	reply := &pb.CheckCatalogReply{Databases: names}
+	for _, name := range names {
+		dbConnector = db.NewDBConn("localhost", masterPort, name)
+		defer dbConnector.Close()
+		err = dbConnector.Connect(1)
+		if err != nil {
+			gplog.Error(err.Error())
+			return &pb.CheckCatalogReply{}, utils.DatabaseConnectionError{Parent: err}
+		}
+		dbConnector.Version.Initialize(dbConnector)
+
+		issues, err := RunCatalogChecks(dbConnector, name, CatalogChecks)
+		if err != nil {
+			gplog.Error(err.Error())
+			return &pb.CheckCatalogReply{}, err
+		}
+		reply.Issues = append(reply.Issues, issues...)
+	}
+
+	gplog.Info("catalog check found %d objects that cannot be upgraded", len(reply.Issues))
+	return reply, nil
+}
+
+// RunCatalogChecks runs each check against the given database, which is named
+// by dbName in the issues that are returned.
+func RunCatalogChecks(dbConnector *dbconn.DBConn, dbName string, checks []CatalogCheck) ([]*pb.CatalogIssue, error) {
+	var issues []*pb.CatalogIssue
+
+	for _, check := range checks {
+		var results []struct {
+			Object string
+			Detail string
+		}
+		err := dbConnector.Select(&results, check.Query)
+		if err != nil {
+			return nil, errors.Wrapf(err, "catalog check %s failed in database %s", check.Name, dbName)
+		}
+
+		for _, result := range results {
+			issues = append(issues, &pb.CatalogIssue{
+				Check:       check.Name,
+				Database:    dbName,
+				Object:      result.Object,
+				Detail:      result.Detail,
+				Remediation: check.Remediation,
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+const (
+	// pg_upgrade can't keep the OIDs of procedures, operators, or text search
+	// objects the same across versions, so stored references to them would
+	// point at the wrong thing. regclass and regtype are safe, since those
+	// OIDs are preserved.
+	UNSUPPORTED_COLUMN_TYPES_QUERY = `
+	SELECT n.nspname || '.' || c.relname || '.' || a.attname AS object,
+	       t.typname AS detail
+	  FROM pg_attribute a
+	  JOIN pg_class c ON a.attrelid = c.oid
+	  JOIN pg_namespace n ON c.relnamespace = n.oid
+	  JOIN pg_type t ON a.atttypid = t.oid
+	WHERE t.typname IN ('regproc', 'regprocedure', 'regoper', 'regoperator', 'regconfig', 'regdictionary')
+	  AND t.typnamespace = (SELECT oid FROM pg_namespace WHERE nspname = 'pg_catalog')
+	  AND a.attnum > 0
+	  AND NOT a.attisdropped
+	  AND c.relkind = cast('r' as CHAR)
+	  AND c.oid >= 16384                                      -- No system tables
+	  AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'gp_toolkit')
+	ORDER BY 1;
+	`
+
+	// pg_upgrade expects every partition to have the same physical layout as
+	// its parent, which isn't the case once a column has been dropped from
+	// one but not the other.
+	PARTITION_DROPPED_COLUMNS_QUERY = `
+	SELECT pn.nspname || '.' || pc.relname AS object,
+	       'partition ' || cn.nspname || '.' || cc.relname || ' has ' || child.dropped ||
+	       ' dropped columns; its parent has ' || parent.dropped AS detail
+	  FROM pg_partition p
+	  JOIN pg_partition_rule r ON r.paroid = p.oid
+	  JOIN pg_class pc ON p.parrelid = pc.oid
+	  JOIN pg_namespace pn ON pc.relnamespace = pn.oid
+	  JOIN pg_class cc ON r.parchildrelid = cc.oid
+	  JOIN pg_namespace cn ON cc.relnamespace = cn.oid
+	  JOIN (SELECT attrelid, COUNT(CASE WHEN attisdropped THEN 1 END) AS dropped
+	          FROM pg_attribute WHERE attnum > 0 GROUP BY attrelid) parent ON parent.attrelid = p.parrelid
+	  JOIN (SELECT attrelid, COUNT(CASE WHEN attisdropped THEN 1 END) AS dropped
+	          FROM pg_attribute WHERE attnum > 0 GROUP BY attrelid) child ON child.attrelid = r.parchildrelid
+	WHERE parent.dropped <> child.dropped
+	ORDER BY 1, 2;
+	`
+
+	// Views are recreated from their definitions, which fail to restore if
+	// they use a catalog column that no longer exists.
+	VIEWS_ON_REMOVED_CATALOG_COLUMNS_QUERY = `
+	SELECT DISTINCT vn.nspname || '.' || v.relname AS object,
+	       'uses pg_catalog.' || t.relname || '.' || a.attname AS detail
+	  FROM pg_depend d
+	  JOIN pg_rewrite rw ON d.objid = rw.oid
+	  JOIN pg_class v ON rw.ev_class = v.oid
+	  JOIN pg_namespace vn ON v.relnamespace = vn.oid
+	  JOIN pg_class t ON d.refobjid = t.oid
+	  JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = d.refobjsubid
+	WHERE d.classid = (SELECT oid FROM pg_class WHERE relname = 'pg_rewrite')
+	  AND t.relnamespace = (SELECT oid FROM pg_namespace WHERE nspname = 'pg_catalog')
+	  AND v.relkind = cast('v' as CHAR)
+	  AND v.oid >= 16384                                      -- No system views
+	  AND (t.relname, a.attname) IN (
+	      ('pg_class', 'reltoastidxid'),
+	      ('pg_class', 'relukeys'),
+	      ('pg_class', 'relfkeys'),
+	      ('pg_class', 'relrefs'),
+	      ('pg_database', 'datconfig'),
+	      ('pg_authid', 'rolconfig'),
+	      ('pg_proc', 'proiswin'),
+	      ('pg_stat_activity', 'procpid'),
+	      ('pg_stat_activity', 'current_query'),
+	      ('pg_stat_activity', 'waiting'))
+	ORDER BY 1, 2;
+	`
+
+	EXTERNAL_TABLES_UNSUPPORTED_PROTOCOLS_QUERY = `
+	SELECT n.nspname || '.' || c.relname AS object,
+	       array_to_string(x.location, ', ') AS detail
+	  FROM pg_exttable x
+	  JOIN pg_class c ON x.reloid = c.oid
+	  JOIN pg_namespace n ON c.relnamespace = n.oid
+	WHERE array_to_string(x.location, ' ') LIKE '%gphdfs://%'
+	ORDER BY 1;
+	`
+)
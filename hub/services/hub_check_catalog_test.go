@@ -0,0 +1,53 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RunCatalogChecks", func() {
+	checks := []services.CatalogCheck{
+		{Name: "first", Query: "SELECT first", Remediation: "fix the first"},
+		{Name: "second", Query: "SELECT second", Remediation: "fix the second"},
+	}
+
+	It("reports each object that a check finds", func() {
+		mock.ExpectQuery("SELECT first").WillReturnRows(sqlmock.NewRows([]string{"object", "detail"}).
+			AddRow("public.t1.c", "regproc").
+			AddRow("public.t2.c", "regoper"))
+		mock.ExpectQuery("SELECT second").WillReturnRows(sqlmock.NewRows([]string{"object", "detail"}))
+
+		issues, err := services.RunCatalogChecks(dbConnector, "postgres", checks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(issues).To(Equal([]*pb.CatalogIssue{
+			{Check: "first", Database: "postgres", Object: "public.t1.c", Detail: "regproc", Remediation: "fix the first"},
+			{Check: "first", Database: "postgres", Object: "public.t2.c", Detail: "regoper", Remediation: "fix the first"},
+		}))
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("returns an error naming the check that failed", func() {
+		mock.ExpectQuery("SELECT first").WillReturnRows(sqlmock.NewRows([]string{"object", "detail"}))
+		mock.ExpectQuery("SELECT second").WillReturnError(errors.New("relation does not exist"))
+
+		_, err := services.RunCatalogChecks(dbConnector, "postgres", checks)
+		Expect(err).To(MatchError(ContainSubstring("catalog check second failed in database postgres")))
+	})
+
+	It("has a query, a remediation and a unique name for every registered check", func() {
+		names := make(map[string]bool)
+		for _, check := range services.CatalogChecks {
+			Expect(check.Query).ToNot(BeEmpty())
+			Expect(check.Remediation).ToNot(BeEmpty())
+			Expect(names).ToNot(HaveKey(check.Name))
+			names[check.Name] = true
+		}
+	})
+})
@@ -2,6 +2,7 @@ package services
 
 import (
 	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/greenplum-db/gpupgrade/db"
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
@@ -29,7 +30,7 @@ func (h *Hub) CheckConfig(ctx context.Context, _ *pb.CheckConfigRequest) (*pb.Ch
 
 	err = RetrieveAndSaveSourceConfig(h.source)
 	if err != nil {
-		step.MarkFailed()
+		step.MarkFailed(err)
 		gplog.Error(err.Error())
 		return &pb.CheckConfigReply{}, err
 	}
@@ -58,6 +59,52 @@ func RetrieveAndSaveSourceConfig(source *utils.Cluster) error {
 		return errors.Wrap(err, "Unable to get segment configuration for old cluster")
 	}
 
+	mirrorConfigs, err := GetMirrorConfiguration(dbConnector)
+	if err != nil {
+		return errors.Wrap(err, "Unable to get mirror configuration for old cluster")
+	}
+
 	source.Cluster = cluster.NewCluster(segConfigs)
+	source.SetMirrors(mirrorConfigs)
 	return source.Commit()
 }
+
+// GetMirrorConfiguration is the counterpart of
+// cluster.GetSegmentConfiguration, which only returns primaries: it returns
+// the mirror segments and the standby master, if there are any.
+func GetMirrorConfiguration(dbConnector *dbconn.DBConn) ([]cluster.SegConfig, error) {
+	query := GET_MIRROR_CONFIGURATION_6
+	if !dbConnector.Version.AtLeast("6") {
+		query = GET_MIRROR_CONFIGURATION_5
+	}
+
+	results := make([]cluster.SegConfig, 0)
+	err := dbConnector.Select(&results, query)
+	return results, err
+}
+
+const (
+	GET_MIRROR_CONFIGURATION_5 = `
+	SELECT s.dbid,
+	       s.content as contentid,
+	       s.port,
+	       s.hostname,
+	       e.fselocation as datadir
+	  FROM gp_segment_configuration s
+	  JOIN pg_filespace_entry e ON s.dbid = e.fsedbid
+	  JOIN pg_filespace f ON e.fsefsoid = f.oid
+	WHERE s.role = 'm' AND f.fsname = 'pg_system'
+	ORDER BY s.content;
+	`
+
+	GET_MIRROR_CONFIGURATION_6 = `
+	SELECT dbid,
+	       content as contentid,
+	       port,
+	       hostname,
+	       datadir
+	  FROM gp_segment_configuration
+	WHERE role = 'm'
+	ORDER BY content;
+	`
+)
@@ -1,7 +1,14 @@
 package services_test
 
 import (
+	"github.com/greenplum-db/gpupgrade/hub/services"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Hub check config", func() {
@@ -9,4 +16,34 @@ var _ = Describe("Hub check config", func() {
 	// pieces of the code base, so additional unit tests are not entirely
 	// useful. We'll test check config by ensuring that end-to-end discovery
 	// actually works in practice.
+
+	Describe("GetMirrorConfiguration", func() {
+		mirrorRows := func() *sqlmock.Rows {
+			return sqlmock.NewRows([]string{"dbid", "contentid", "port", "hostname", "datadir"}).
+				AddRow(4, -1, 15432, "smdw", "/standby/seg-1").
+				AddRow(5, 0, 35432, "sdw2", "/mirror/seg0")
+		}
+		expected := []cluster.SegConfig{
+			{DbID: 4, ContentID: -1, Port: 15432, Hostname: "smdw", DataDir: "/standby/seg-1"},
+			{DbID: 5, ContentID: 0, Port: 35432, Hostname: "sdw2", DataDir: "/mirror/seg0"},
+		}
+
+		It("reads mirrors from the filespace catalog for GPDB 5", func() {
+			testhelper.SetDBVersion(dbConnector, "5.10.0")
+			mock.ExpectQuery("SELECT .* JOIN pg_filespace_entry .* s.role = 'm'").WillReturnRows(mirrorRows())
+
+			mirrors, err := services.GetMirrorConfiguration(dbConnector)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mirrors).To(Equal(expected))
+		})
+
+		It("reads mirrors from gp_segment_configuration for GPDB 6", func() {
+			testhelper.SetDBVersion(dbConnector, "6.0.0")
+			mock.ExpectQuery("SELECT .* FROM gp_segment_configuration\\s+WHERE role = 'm'").WillReturnRows(mirrorRows())
+
+			mirrors, err := services.GetMirrorConfiguration(dbConnector)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mirrors).To(Equal(expected))
+		})
+	})
 })
@@ -1,65 +1,187 @@
 package services
 
 import (
-	"fmt"
-	"strconv"
+	"sort"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
-	"google.golang.org/grpc"
-)
-
-const (
-	// todo generalize to any host
-	diskUsageWarningLimit = 80
 )
 
+// CheckDiskSpace projects how much space each segment's upgrade will take, and
+// whether the filesystem holding its planned target data directory has room.
 func (h *Hub) CheckDiskSpace(ctx context.Context,
 	in *pb.CheckDiskSpaceRequest) (*pb.CheckDiskSpaceReply, error) {
 
 	gplog.Info("starting CheckDiskSpace")
-	var replyMessages []string
-	hostnames := h.source.GetHostnames()
-	var clients []ClientAndHostname
-	for i := 0; i < len(hostnames); i++ {
-		conn, err := grpc.Dial(hostnames[i]+":"+strconv.Itoa(h.conf.HubToAgentPort), grpc.WithInsecure())
-		if err == nil {
-			clients = append(clients, ClientAndHostname{Client: pb.NewAgentClient(conn), Hostname: hostnames[i]})
-			defer conn.Close()
-		} else {
-			gplog.Error(err.Error())
-			replyMessages = append(replyMessages, "ERROR: couldn't get gRPC conn to "+hostnames[i])
-		}
+
+	layout, err := h.targetLayout()
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckDiskSpaceReply{}, errors.Wrap(err, "Could not plan the target cluster")
 	}
-	replyMessages = append(replyMessages, GetDiskSpaceFromSegmentHosts(clients)...)
 
-	return &pb.CheckDiskSpaceReply{SegmentFileSysUsage: replyMessages}, nil
+	// A host whose agent can't be reached has that reported against it and
+	// its segments, and the rest are checked all the same.
+	fanOut, agentConns := h.agentFanOut(layout.Hostnames()...)
+
+	mode := h.target.Mode
+	if mode == "" {
+		mode = utils.COPY_MODE
+	}
+	segments, hosts := GetDiskSpaceFromSegmentHosts(fanOut, Clients(agentConns), h.source, layout)
+	return &pb.CheckDiskSpaceReply{
+		HostDiskUsages: hosts,
+		Segments:       segments,
+		Mode:           mode,
+	}, nil
+}
+
+// upgradedSegment pairs a source segment with its planned target.
+type upgradedSegment struct {
+	role   string
+	source cluster.SegConfig
+	target cluster.SegConfig
 }
 
-func GetDiskSpaceFromSegmentHosts(clients []ClientAndHostname) []string {
-	replyMessages := []string{}
-	for i := 0; i < len(clients); i++ {
-		reply, err := clients[i].Client.CheckDiskSpaceOnAgents(context.Background(),
-			&pb.CheckDiskSpaceRequestToAgent{})
-		if err != nil {
-			gplog.Error(err.Error())
-			replyMessages = append(replyMessages, "Could not get disk usage from: "+clients[i].Hostname)
+func upgradedSegments(source *utils.Cluster, layout *TargetLayout) []upgradedSegment {
+	segments := []upgradedSegment{{role: "master", source: source.Segments[-1], target: layout.Master}}
+	for _, primary := range layout.Primaries {
+		segments = append(segments, upgradedSegment{"primary", source.Segments[primary.ContentID], primary})
+	}
+	if standby, ok := source.Standby(); ok && layout.Standby != nil {
+		segments = append(segments, upgradedSegment{"standby", standby, *layout.Standby})
+	}
+	for _, mirror := range layout.Mirrors {
+		segments = append(segments, upgradedSegment{"mirror", source.Mirrors[mirror.ContentID], mirror})
+	}
+	return segments
+}
+
+// GetDiskSpaceFromSegmentHosts asks each agent how large the data directories
+// on its host are, and how much space is free where their target data
+// directories will go. It returns the projected need in copy and link mode for
+// each segment, in the order of layout.Segments(), along with how full each
+// filesystem on each host is, in hostname order.
+//
+// In copy mode pg_upgrade copies the whole data directory. In link mode it
+// hard-links user relation files instead, so only the rest is copied.
+func GetDiskSpaceFromSegmentHosts(fanOut FanOut, clients []ClientAndHostname, source *utils.Cluster, layout *TargetLayout) ([]*pb.SegmentDiskSpace, []*pb.HostDiskUsage) {
+	var results []*pb.SegmentDiskSpace
+	resultsByHost := map[string][]*pb.SegmentDiskSpace{}
+	requests := map[string]*pb.CheckDiskSpaceRequestToAgent{}
+	for _, segment := range upgradedSegments(source, layout) {
+		result := &pb.SegmentDiskSpace{
+			Hostname:      segment.target.Hostname,
+			Content:       int32(segment.target.ContentID),
+			Role:          segment.role,
+			DataDir:       segment.source.DataDir,
+			TargetDataDir: segment.target.DataDir,
+			Copy:          &pb.DiskSpaceNeed{},
+			Link:          &pb.DiskSpaceNeed{},
+		}
+		results = append(results, result)
+		resultsByHost[result.Hostname] = append(resultsByHost[result.Hostname], result)
+
+		request, ok := requests[result.Hostname]
+		if !ok {
+			request = &pb.CheckDiskSpaceRequestToAgent{}
+			requests[result.Hostname] = request
+		}
+		request.DataDirPairs = append(request.DataDirPairs, &pb.DataDirPair{
+			OldDataDir: segment.source.DataDir,
+			NewDataDir: segment.target.DataDir,
+			Content:    int32(segment.target.ContentID),
+		})
+	}
+
+	var asked []ClientAndHostname
+	for _, client := range clients {
+		if _, ok := requests[client.Hostname]; ok {
+			asked = append(asked, client)
+		}
+	}
+	replies := fanOut.Call(asked, func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
+		request := requests[client.Hostname]
+		reply, err := client.Client.CheckDiskSpaceOnAgents(ctx, request)
+		if err == nil && len(reply.Usages) != len(request.DataDirPairs) {
+			err = errors.Errorf("reported on %d data directories, but %d were asked about",
+				len(reply.Usages), len(request.DataDirPairs))
+		}
+		return reply, err
+	})
+
+	var hostnames []string
+	for hostname := range requests {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+
+	var hosts []*pb.HostDiskUsage
+	for _, hostname := range hostnames {
+		hostResults := resultsByHost[hostname]
+		host := &pb.HostDiskUsage{Hostname: hostname}
+		hosts = append(hosts, host)
+
+		reply, ok := replies[hostname]
+		if !ok {
+			host.Error = "no agent is available on this host"
+			for _, result := range hostResults {
+				result.Error = host.Error
+			}
 			continue
 		}
-		foundAnyTooFull := false
-		for _, line := range reply.ListOfFileSysUsage {
-			if line.Usage >= diskUsageWarningLimit {
-				replyMessages = append(replyMessages, fmt.Sprintf("diskspace check - %s - WARNING %s %.1f use",
-					clients[i].Hostname, line.Filesystem, line.Usage))
-				foundAnyTooFull = true
+		if reply.Err != nil {
+			gplog.Error("Could not get disk usage from %s: %s", hostname, reply.Err.Error())
+			host.Error = reply.Err.Error()
+			for _, result := range hostResults {
+				result.Error = host.Error
+			}
+			continue
+		}
+
+		agentReply := reply.Reply.(*pb.CheckDiskSpaceReplyFromAgent)
+		host.ListOfFileSysUsage = agentReply.ListOfFileSysUsage
+		for i, usage := range agentReply.Usages {
+			result := hostResults[i]
+			result.DataDirBytes = usage.TotalBytes
+			result.Filesystem = usage.TargetFilesystem
+			result.FreeBytes = usage.TargetFreeBytes
+			result.Copy.Bytes = usage.TotalBytes
+			result.Link.Bytes = usage.TotalBytes - usage.RelationBytes
+			if result.Role == "mirror" || result.Role == "standby" {
+				// These are copied from their upgraded primary, or
+				// master, rather than upgraded by pg_upgrade, so they
+				// need a full copy in either mode.
+				result.Link.Bytes = usage.TotalBytes
 			}
 		}
-		if !foundAnyTooFull {
-			replyMessages = append(replyMessages, fmt.Sprintf("diskspace check - %s - OK", clients[i].Hostname))
+	}
+
+	// Segments whose target data directories share a filesystem share its
+	// free space, so they fit only if all of them together do.
+	type filesystem struct{ hostname, mountPoint string }
+	copyTotals := map[filesystem]uint64{}
+	linkTotals := map[filesystem]uint64{}
+	for _, result := range results {
+		fs := filesystem{result.Hostname, result.Filesystem}
+		copyTotals[fs] += result.Copy.Bytes
+		linkTotals[fs] += result.Link.Bytes
+	}
+	for _, result := range results {
+		if result.Error != "" {
+			continue
 		}
+		fs := filesystem{result.Hostname, result.Filesystem}
+		result.Copy.FilesystemBytes = copyTotals[fs]
+		result.Copy.Fits = copyTotals[fs] <= result.FreeBytes
+		result.Link.FilesystemBytes = linkTotals[fs]
+		result.Link.Fits = linkTotals[fs] <= result.FreeBytes
 	}
 
-	return replyMessages
+	return results, hosts
 }
@@ -1,67 +1,215 @@
 package services_test
 
 import (
+	"path/filepath"
+	"time"
+
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	mockpb "github.com/greenplum-db/gpupgrade/mock_idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"golang.org/x/net/context"
 
 	"github.com/greenplum-db/gpupgrade/hub/services"
 
 	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
-var _ = Describe("object count tests", func() {
-	Describe("GetDiskUsageFromSegmentHosts", func() {
-		It("returns err msg when unable to call CheckDiskSpaceOnAgents on segment host", func() {
-			var clients []services.ClientAndHostname
+var _ = Describe("disk space check", func() {
+	var (
+		layout  *services.TargetLayout
+		clients []services.ClientAndHostname
+	)
+
+	BeforeEach(func() {
+		var err error
+		layout, err = services.PlanTargetLayout(source, utils.LayoutSettings{})
+		Expect(err).ToNot(HaveOccurred())
+
+		clients = []services.ClientAndHostname{{Client: client, Hostname: "localhost"}}
+	})
 
+	Describe("GetDiskSpaceFromSegmentHosts", func() {
+		It("projects the space needed by segments sharing a filesystem in each mode", func() {
 			client.EXPECT().CheckDiskSpaceOnAgents(
 				gomock.Any(),
-				&pb.CheckDiskSpaceRequestToAgent{},
-			).Return(&pb.CheckDiskSpaceReplyFromAgent{}, errors.New("couldn't connect to hub"))
-			clients = append(clients, services.ClientAndHostname{Client: client, Hostname: "doesnotexist"})
+				&pb.CheckDiskSpaceRequestToAgent{DataDirPairs: []*pb.DataDirPair{
+					{OldDataDir: filepath.Join(dir, "seg-1"), NewDataDir: filepath.Join(dir+"_upgrade", "seg-1"), Content: -1},
+					{OldDataDir: filepath.Join(dir, "seg1"), NewDataDir: filepath.Join(dir+"_upgrade", "seg1"), Content: 0},
+					{OldDataDir: filepath.Join(dir, "seg2"), NewDataDir: filepath.Join(dir+"_upgrade", "seg2"), Content: 1},
+				}},
+			).Return(&pb.CheckDiskSpaceReplyFromAgent{Usages: []*pb.DataDirDiskUsage{
+				{TotalBytes: 500, RelationBytes: 100, TargetFilesystem: "/", TargetFreeBytes: 2000},
+				{TotalBytes: 1000, RelationBytes: 800, TargetFilesystem: "/", TargetFreeBytes: 2000},
+				{TotalBytes: 1000, RelationBytes: 800, TargetFilesystem: "/", TargetFreeBytes: 2000},
+			}}, nil)
 
-			messages := services.GetDiskSpaceFromSegmentHosts(clients)
-			Expect(len(messages)).To(Equal(1))
-			Expect(messages[0]).To(ContainSubstring("Could not get disk usage from: "))
+			segments, _ := services.GetDiskSpaceFromSegmentHosts(services.FanOut{}, clients, source, layout)
+			Expect(segments).To(HaveLen(3))
+
+			Expect(segments[0]).To(Equal(&pb.SegmentDiskSpace{
+				Hostname:      "localhost",
+				Content:       -1,
+				Role:          "master",
+				DataDir:       filepath.Join(dir, "seg-1"),
+				TargetDataDir: filepath.Join(dir+"_upgrade", "seg-1"),
+				DataDirBytes:  500,
+				Filesystem:    "/",
+				FreeBytes:     2000,
+				Copy:          &pb.DiskSpaceNeed{Bytes: 500, FilesystemBytes: 2500, Fits: false},
+				Link:          &pb.DiskSpaceNeed{Bytes: 400, FilesystemBytes: 800, Fits: true},
+			}))
+			Expect(segments[1].Role).To(Equal("primary"))
+			Expect(segments[1].Copy).To(Equal(&pb.DiskSpaceNeed{Bytes: 1000, FilesystemBytes: 2500, Fits: false}))
+			Expect(segments[1].Link).To(Equal(&pb.DiskSpaceNeed{Bytes: 200, FilesystemBytes: 800, Fits: true}))
 		})
 
-		It("lists filesystems above usage threshold", func() {
-			var clients []services.ClientAndHostname
+		It("does not add up segments on different filesystems", func() {
+			client.EXPECT().CheckDiskSpaceOnAgents(gomock.Any(), gomock.Any()).Return(&pb.CheckDiskSpaceReplyFromAgent{Usages: []*pb.DataDirDiskUsage{
+				{TotalBytes: 500, TargetFilesystem: "/", TargetFreeBytes: 600},
+				{TotalBytes: 1000, TargetFilesystem: "/data1", TargetFreeBytes: 1000},
+				{TotalBytes: 1000, TargetFilesystem: "/data2", TargetFreeBytes: 999},
+			}}, nil)
+
+			segments, _ := services.GetDiskSpaceFromSegmentHosts(services.FanOut{}, clients, source, layout)
+			Expect(segments[0].Copy.Fits).To(BeTrue())
+			Expect(segments[1].Copy.Fits).To(BeTrue())
+			Expect(segments[2].Copy.Fits).To(BeFalse())
+		})
 
-			var expectedFilesystemsUsage []*pb.FileSysUsage
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, &pb.FileSysUsage{Filesystem: "first filesystem", Usage: 90.4})
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, &pb.FileSysUsage{Filesystem: "/second/filesystem", Usage: 24.2})
+		It("needs a full copy for mirrors and the standby, even in link mode", func() {
+			source.SetMirrors([]cluster.SegConfig{
+				{ContentID: -1, DbID: 4, Port: 16432, Hostname: "smdw", DataDir: "/standby/seg-1"},
+				{ContentID: 0, DbID: 5, Port: 35432, Hostname: "smdw", DataDir: "/mirror/seg1"},
+			})
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{})
+			Expect(err).ToNot(HaveOccurred())
 
+			client.EXPECT().CheckDiskSpaceOnAgents(gomock.Any(), gomock.Any()).Return(&pb.CheckDiskSpaceReplyFromAgent{Usages: []*pb.DataDirDiskUsage{
+				{TotalBytes: 500, RelationBytes: 100, TargetFilesystem: "/", TargetFreeBytes: 10000},
+				{TotalBytes: 1000, RelationBytes: 800, TargetFilesystem: "/", TargetFreeBytes: 10000},
+				{TotalBytes: 1000, RelationBytes: 800, TargetFilesystem: "/", TargetFreeBytes: 10000},
+			}}, nil)
+			mirrorClient := mockpb.NewMockAgentClient(ctrl)
+			mirrorClient.EXPECT().CheckDiskSpaceOnAgents(
+				gomock.Any(),
+				&pb.CheckDiskSpaceRequestToAgent{DataDirPairs: []*pb.DataDirPair{
+					{OldDataDir: "/standby/seg-1", NewDataDir: "/standby_upgrade/seg-1", Content: -1},
+					{OldDataDir: "/mirror/seg1", NewDataDir: "/mirror_upgrade/seg1", Content: 0},
+				}},
+			).Return(&pb.CheckDiskSpaceReplyFromAgent{Usages: []*pb.DataDirDiskUsage{
+				{TotalBytes: 500, RelationBytes: 100, TargetFilesystem: "/", TargetFreeBytes: 1400},
+				{TotalBytes: 1000, RelationBytes: 800, TargetFilesystem: "/", TargetFreeBytes: 1400},
+			}}, nil)
+			clients = append(clients, services.ClientAndHostname{Client: mirrorClient, Hostname: "smdw"})
+
+			segments, _ := services.GetDiskSpaceFromSegmentHosts(services.FanOut{}, clients, source, layout)
+			Expect(segments).To(HaveLen(5))
+
+			Expect(segments[3].Role).To(Equal("standby"))
+			Expect(segments[3].Link).To(Equal(&pb.DiskSpaceNeed{Bytes: 500, FilesystemBytes: 1500, Fits: false}))
+			Expect(segments[4].Role).To(Equal("mirror"))
+			Expect(segments[4].Link).To(Equal(&pb.DiskSpaceNeed{Bytes: 1000, FilesystemBytes: 1500, Fits: false}))
+		})
+
+		It("marks every segment on a host that could not be checked", func() {
 			client.EXPECT().CheckDiskSpaceOnAgents(
 				gomock.Any(),
-				&pb.CheckDiskSpaceRequestToAgent{},
-			).Return(&pb.CheckDiskSpaceReplyFromAgent{ListOfFileSysUsage: expectedFilesystemsUsage}, nil)
-			clients = append(clients, services.ClientAndHostname{Client: client, Hostname: "doesnotexist"})
+				gomock.Any(),
+			).Return(nil, errors.New("couldn't connect to agent"))
 
-			messages := services.GetDiskSpaceFromSegmentHosts(clients)
-			Expect(len(messages)).To(Equal(1))
-			Expect(messages[0]).To(ContainSubstring("diskspace check - doesnotexist - WARNING first filesystem 90.4 use"))
+			segments, _ := services.GetDiskSpaceFromSegmentHosts(services.FanOut{}, clients, source, layout)
+			Expect(segments).To(HaveLen(3))
+			for _, segment := range segments {
+				Expect(segment.Error).To(Equal("couldn't connect to agent"))
+				Expect(segment.Copy.Fits).To(BeFalse())
+				Expect(segment.Link.Fits).To(BeFalse())
+			}
 		})
 
-		It("lists hosts for which all filesystems are below usage threshold", func() {
-			var clients []services.ClientAndHostname
+		It("marks every segment on a host that left out data directories", func() {
+			client.EXPECT().CheckDiskSpaceOnAgents(gomock.Any(), gomock.Any()).Return(&pb.CheckDiskSpaceReplyFromAgent{Usages: []*pb.DataDirDiskUsage{
+				{TotalBytes: 500, TargetFilesystem: "/", TargetFreeBytes: 600},
+			}}, nil)
 
-			var expectedFilesystemsUsage []*pb.FileSysUsage
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, &pb.FileSysUsage{Filesystem: "first filesystem", Usage: 70.4})
-			expectedFilesystemsUsage = append(expectedFilesystemsUsage, &pb.FileSysUsage{Filesystem: "/second/filesystem", Usage: 24.2})
+			segments, _ := services.GetDiskSpaceFromSegmentHosts(services.FanOut{}, clients, source, layout)
+			Expect(segments[0].Error).To(Equal("reported on 1 data directories, but 3 were asked about"))
+		})
 
-			client.EXPECT().CheckDiskSpaceOnAgents(
-				gomock.Any(),
-				&pb.CheckDiskSpaceRequestToAgent{},
-			).Return(&pb.CheckDiskSpaceReplyFromAgent{ListOfFileSysUsage: expectedFilesystemsUsage}, nil)
-			clients = append(clients, services.ClientAndHostname{Client: client, Hostname: "doesnotexist"})
+		It("marks every segment on a host without an agent", func() {
+			segments, hosts := services.GetDiskSpaceFromSegmentHosts(services.FanOut{}, nil, source, layout)
+			Expect(segments[0].Error).To(Equal("no agent is available on this host"))
+			Expect(hosts).To(Equal([]*pb.HostDiskUsage{
+				{Hostname: "localhost", Error: "no agent is available on this host"},
+			}))
+		})
 
-			messages := services.GetDiskSpaceFromSegmentHosts(clients)
-			Expect(len(messages)).To(Equal(1))
-			Expect(messages[0]).To(ContainSubstring("diskspace check - doesnotexist - OK"))
+		It("marks every segment on a host whose agent could not be reached", func() {
+			fanOut := services.FanOut{Unreachable: services.HostResults{
+				"localhost": {Err: errors.New("could not connect to the agent: connection refused")},
+			}}
+
+			segments, hosts := services.GetDiskSpaceFromSegmentHosts(fanOut, nil, source, layout)
+			for _, segment := range segments {
+				Expect(segment.Error).To(Equal("could not connect to the agent: connection refused"))
+			}
+			Expect(hosts).To(Equal([]*pb.HostDiskUsage{
+				{Hostname: "localhost", Error: "could not connect to the agent: connection refused"},
+			}))
 		})
+
+		It("reports how full each filesystem on each host is", func() {
+			client.EXPECT().CheckDiskSpaceOnAgents(gomock.Any(), gomock.Any()).Return(&pb.CheckDiskSpaceReplyFromAgent{
+				ListOfFileSysUsage: []*pb.FileSysUsage{{Filesystem: "/", Usage: 42.5}},
+				Usages: []*pb.DataDirDiskUsage{
+					{TotalBytes: 500, TargetFilesystem: "/", TargetFreeBytes: 6000},
+					{TotalBytes: 500, TargetFilesystem: "/", TargetFreeBytes: 6000},
+					{TotalBytes: 500, TargetFilesystem: "/", TargetFreeBytes: 6000},
+				},
+			}, nil)
+
+			_, hosts := services.GetDiskSpaceFromSegmentHosts(services.FanOut{}, clients, source, layout)
+			Expect(hosts).To(Equal([]*pb.HostDiskUsage{{
+				Hostname:           "localhost",
+				ListOfFileSysUsage: []*pb.FileSysUsage{{Filesystem: "/", Usage: 42.5}},
+			}}))
+		})
+	})
+
+	It("checks the segments of the planned target cluster", func() {
+		target.Layout.DataDirTemplate = "/upgrade/{base}"
+		mockAgent.CheckDiskSpaceResponse = &pb.CheckDiskSpaceReplyFromAgent{Usages: []*pb.DataDirDiskUsage{
+			{TotalBytes: 1, TargetFilesystem: "/", TargetFreeBytes: 10},
+			{TotalBytes: 1, TargetFilesystem: "/", TargetFreeBytes: 10},
+			{TotalBytes: 1, TargetFilesystem: "/", TargetFreeBytes: 10},
+		}}
+
+		reply, err := hub.CheckDiskSpace(context.Background(), &pb.CheckDiskSpaceRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mockAgent.CheckDiskSpaceRequest.DataDirPairs[1].NewDataDir).To(Equal("/upgrade/seg1"))
+		Expect(reply.Segments).To(HaveLen(3))
+		Expect(reply.Segments[2].Copy.Fits).To(BeTrue())
+	})
+
+	It("reports a host whose agent cannot be reached instead of failing", func() {
+		mockAgent.Stop()
+		hubConf.DialTimeout = 100 * time.Millisecond
+		hub := services.NewHub(source, target, grpc.DialContext, hubConf, cm)
+		defer hub.Stop()
+
+		reply, err := hub.CheckDiskSpace(context.Background(), &pb.CheckDiskSpaceRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.HostDiskUsages).To(HaveLen(1))
+		Expect(reply.HostDiskUsages[0].Error).To(HavePrefix("could not connect to the agent: "))
+		for _, segment := range reply.Segments {
+			Expect(segment.Error).To(Equal(reply.HostDiskUsages[0].Error))
+		}
 	})
 })
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// CheckPorts finds out whether the ports planned for the target cluster are
+// already in use on their hosts.
+func (h *Hub) CheckPorts(ctx context.Context, in *pb.CheckPortsRequest) (*pb.CheckPortsReply, error) {
+	gplog.Info("starting CheckPorts")
+
+	layout, err := h.targetLayout()
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckPortsReply{}, errors.Wrap(err, "Could not plan the target cluster")
+	}
+
+	fanOut, agentConns := h.agentFanOut(layout.Hostnames()...)
+	ports, err := CheckTargetPorts(fanOut, agentConns, layout)
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.CheckPortsReply{}, err
+	}
+	return &pb.CheckPortsReply{Ports: ports}, nil
+}
+
+// CheckTargetPorts asks the agent on every host about the ports planned for
+// its segments, and returns the answers in the order of layout.Segments().
+func CheckTargetPorts(fanOut FanOut, agentConns []*Connection, layout *TargetLayout) ([]*pb.TargetPortStatus, error) {
+	segmentsByHost := map[string][]cluster.SegConfig{}
+	for _, segment := range layout.Segments() {
+		segmentsByHost[segment.Hostname] = append(segmentsByHost[segment.Hostname], segment)
+	}
+
+	var clients []ClientAndHostname
+	for _, client := range Clients(agentConns) {
+		if len(segmentsByHost[client.Hostname]) > 0 {
+			clients = append(clients, client)
+		}
+	}
+	results := fanOut.Call(clients, func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
+		request := &pb.CheckPortsRequestToAgent{}
+		for _, segment := range segmentsByHost[client.Hostname] {
+			request.Ports = append(request.Ports, int32(segment.Port))
+		}
+
+		reply, err := client.Client.CheckPorts(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		if len(reply.Ports) != len(request.Ports) {
+			return nil, errors.Errorf("reported on %d ports, but %d were asked about",
+				len(reply.Ports), len(request.Ports))
+		}
+		return reply.Ports, nil
+	})
+	if err := results.Err(); err != nil {
+		gplog.Error("Error checking ports: %s", err.Error())
+		return nil, errors.Wrap(err, "Could not check ports")
+	}
+
+	var missing []string
+	for host := range segmentsByHost {
+		if _, ok := results[host]; !ok {
+			missing = append(missing, host)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, errors.Errorf("no agent was available to check ports on hosts %s", strings.Join(missing, ", "))
+	}
+
+	var ports []*pb.TargetPortStatus
+	next := map[string]int{}
+	for _, segment := range layout.Segments() {
+		result := results[segment.Hostname]
+		ports = append(ports, &pb.TargetPortStatus{
+			Hostname: segment.Hostname,
+			Content:  int32(segment.ContentID),
+			Status:   result.Reply.([]*pb.PortStatus)[next[segment.Hostname]],
+		})
+		next[segment.Hostname]++
+	}
+	return ports, nil
+}
@@ -0,0 +1,73 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"golang.org/x/net/context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckPorts", func() {
+	It("asks the agents about the planned target ports and reports each one", func() {
+		mockAgent.CheckPortsResponse = &pb.CheckPortsReplyFromAgent{
+			Ports: []*pb.PortStatus{
+				{Port: 15433},
+				{Port: 27432, InUse: true, Pid: 99, Process: "java"},
+				{Port: 27433},
+			},
+		}
+
+		reply, err := hub.CheckPorts(context.Background(), &pb.CheckPortsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mockAgent.CheckPortsRequest.Ports).To(Equal([]int32{15433, 27432, 27433}))
+		Expect(reply.Ports).To(Equal([]*pb.TargetPortStatus{
+			{Hostname: "localhost", Content: -1, Status: &pb.PortStatus{Port: 15433}},
+			{Hostname: "localhost", Content: 0, Status: &pb.PortStatus{Port: 27432, InUse: true, Pid: 99, Process: "java"}},
+			{Hostname: "localhost", Content: 1, Status: &pb.PortStatus{Port: 27433}},
+		}))
+	})
+
+	It("uses the operator's port range", func() {
+		target.Layout.PortRange = "50000"
+		mockAgent.CheckPortsResponse = &pb.CheckPortsReplyFromAgent{
+			Ports: []*pb.PortStatus{{Port: 50000}, {Port: 50001}, {Port: 50002}},
+		}
+
+		_, err := hub.CheckPorts(context.Background(), &pb.CheckPortsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mockAgent.CheckPortsRequest.Ports).To(Equal([]int32{50000, 50001, 50002}))
+	})
+
+	It("returns an error if an agent cannot check its ports", func() {
+		mockAgent.Err <- errors.New("agent is down")
+
+		_, err := hub.CheckPorts(context.Background(), &pb.CheckPortsRequest{})
+		Expect(err).To(MatchError(ContainSubstring("Could not check ports: localhost: ")))
+	})
+
+	It("returns an error naming every host that has no agent", func() {
+		segment := source.Segments[1]
+		segment.Hostname = "sdw1"
+		source.Segments[1] = segment
+		layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = services.CheckTargetPorts(services.FanOut{}, nil, layout)
+		Expect(err).To(MatchError("no agent was available to check ports on hosts localhost, sdw1"))
+	})
+
+	It("returns an error if an agent leaves out ports", func() {
+		mockAgent.CheckPortsResponse = &pb.CheckPortsReplyFromAgent{
+			Ports: []*pb.PortStatus{{Port: 15433}},
+		}
+
+		_, err := hub.CheckPorts(context.Background(), &pb.CheckPortsRequest{})
+		Expect(err).To(MatchError("Could not check ports: localhost: reported on 1 ports, but 3 were asked about"))
+	})
+})
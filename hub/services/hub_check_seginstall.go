@@ -10,6 +10,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/pkg/errors"
 )
 
 // grpc generated function signature requires ctx and in params.
@@ -17,6 +18,11 @@ import (
 func (h *Hub) CheckSeginstall(ctx context.Context, in *idl.CheckSeginstallRequest) (*idl.CheckSeginstallReply, error) {
 	gplog.Info("Running CheckSeginstall()")
 
+	if err := h.checkPrerequisites(upgradestatus.SEGINSTALL, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
+		return &idl.CheckSeginstallReply{}, err
+	}
+
 	step := h.checklist.GetStepWriter(upgradestatus.SEGINSTALL)
 
 	err := step.ResetStateDir()
@@ -31,7 +37,9 @@ func (h *Hub) CheckSeginstall(ctx context.Context, in *idl.CheckSeginstallReques
 		return &idl.CheckSeginstallReply{}, err
 	}
 
-	go VerifyAgentsInstalled(h.source, step)
+	h.tasks.Go(upgradestatus.SEGINSTALL, func() {
+		VerifyAgentsInstalled(h.source, step)
+	})
 
 	return &idl.CheckSeginstallReply{}, nil
 }
@@ -41,8 +49,7 @@ func VerifyAgentsInstalled(source *utils.Cluster, step upgradestatus.StateWriter
 
 	// TODO: if this finds nothing, should we err out? do a fallback check based on $GPHOME?
 	logStr := "check gpupgrade_agent is installed in cluster's binary directory on master and hosts"
-	agentPath := filepath.Join(source.BinDir, "gpupgrade_agent")
-	returnLsCommand := func(contentID int) string { return "ls " + agentPath }
+	returnLsCommand := func(contentID int) string { return "ls " + agentPath(source) }
 	remoteOutput := source.GenerateAndExecuteCommand(logStr, returnLsCommand, cluster.ON_HOSTS_AND_MASTER)
 
 	errStr := "Failed to find all gpupgrade_agents"
@@ -52,11 +59,11 @@ func VerifyAgentsInstalled(source *utils.Cluster, step upgradestatus.StateWriter
 	source.CheckClusterError(remoteOutput, errStr, errMessage, true)
 
 	if remoteOutput.NumErrors > 0 {
-		err = step.MarkFailed()
+		err = step.MarkFailed(errors.New(errStr))
 		if err != nil {
 			gplog.Error(err.Error())
-			return
 		}
+		return
 	}
 
 	err = step.MarkComplete()
@@ -65,3 +72,16 @@ func VerifyAgentsInstalled(source *utils.Cluster, step upgradestatus.StateWriter
 		return
 	}
 }
+
+// planSeginstall lists the checks that VerifyAgentsInstalled would make.
+func (h *Hub) planSeginstall() (*idl.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+
+	return &idl.DryRunPlan{Commands: onEveryHost(h.source, "ls "+agentPath(h.source))}, nil
+}
+
+func agentPath(source *utils.Cluster) string {
+	return filepath.Join(source.BinDir, "gpupgrade_agent")
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// commandError explains that command failed with err, as returned by the
+// executor, and points at the log that says more.
+func commandError(command string, err error, logFile string) *upgradestatus.CommandError {
+	return &upgradestatus.CommandError{
+		Command:  command,
+		ExitCode: exitCode(err),
+		LogFile:  logFile,
+		Err:      err,
+	}
+}
+
+// exitCode is the exit status that the executor reports in err, or -1 if the
+// command did not exit normally.
+func exitCode(err error) int {
+	code := -1
+	fmt.Sscanf(err.Error(), "exit status %d", &code)
+	return code
+}
+
+// adminLogFile is today's log of a Greenplum utility, such as gpinitsystem or
+// gpstart, which always log to ~/gpAdminLogs.
+func adminLogFile(utility string) string {
+	return logFile(filepath.Join(utils.System.Getenv("HOME"), "gpAdminLogs"), utility)
+}
+
+// hubLogFile is today's log of the hub, where it records the output of the
+// commands that it runs itself.
+func (h *Hub) hubLogFile() string {
+	if h.conf.LogDir == "" {
+		return adminLogFile("gpupgrade_hub")
+	}
+	return logFile(h.conf.LogDir, "gpupgrade_hub")
+}
+
+func logFile(dir string, program string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s_%s.log", program, utils.System.Now().Format("20060102")))
+}
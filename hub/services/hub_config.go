@@ -1,7 +1,9 @@
 package services
 
 import (
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -11,15 +13,65 @@ import (
 )
 
 func (h *Hub) SetConfig(ctx context.Context, in *pb.SetConfigRequest) (*pb.SetConfigReply, error) {
+	layout := h.target.Layout
+
 	switch in.Name {
 	case "old-bindir":
 		h.source.BinDir = in.Value
 	case "new-bindir":
 		h.target.BinDir = in.Value
+	case "target-port-range":
+		layout.PortRange = in.Value
+		if in.Value != "" {
+			if _, _, err := ParsePortRange(in.Value); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+	case "target-port-map":
+		layout.PortMapFile = in.Value
+		if in.Value != "" {
+			if _, err := ReadPortMap(in.Value); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+	case "target-datadir-template":
+		layout.DataDirTemplate = in.Value
+		if in.Value != "" {
+			if err := ValidateDataDirTemplate(in.Value); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+	case "mode":
+		if err := utils.ValidateMode(in.Value); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		// pg_upgrade has to run in the same mode on every segment.
+		if in.Value != h.target.Mode && h.conversionStarted() {
+			return nil, status.Error(codes.FailedPrecondition, "the mode cannot be changed once the upgrade has started converting the cluster")
+		}
+		h.target.Mode = in.Value
 	default:
 		return nil, status.Errorf(codes.NotFound, "%s is not a valid configuration key", in.Name)
 	}
 
+	// init-cluster creates the target cluster, and its directories, where the
+	// layout settings say; from then on every later step, and revert, have to
+	// agree with it.
+	if layout != h.target.Layout &&
+		h.checklist.GetStepReader(upgradestatus.INIT_CLUSTER).Status() != pb.StepStatus_PENDING {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"%s cannot be changed once %s has started; revert the upgrade first", in.Name, upgradestatus.INIT_CLUSTER)
+	}
+
+	// Make sure that the new layout settings work for the source cluster, if
+	// we know it yet, rather than finding out at init-cluster.
+	if layout != h.target.Layout && h.source.Cluster != nil {
+		if _, err := PlanTargetLayout(h.source, layout); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	h.target.Layout = layout
+
 	// Persist.
 	err := h.source.Commit()
 	if err != nil {
@@ -42,6 +94,17 @@ func (h *Hub) GetConfig(ctx context.Context, in *pb.GetConfigRequest) (*pb.GetCo
 		resp.Value = h.source.BinDir
 	case "new-bindir":
 		resp.Value = h.target.BinDir
+	case "target-port-range":
+		resp.Value = h.target.Layout.PortRange
+	case "target-port-map":
+		resp.Value = h.target.Layout.PortMapFile
+	case "target-datadir-template":
+		resp.Value = h.target.Layout.DataDirTemplate
+	case "mode":
+		resp.Value = h.target.Mode
+		if resp.Value == "" {
+			resp.Value = utils.COPY_MODE
+		}
 	default:
 		return nil, status.Errorf(codes.NotFound, "%s is not a valid configuration key", in.Name)
 	}
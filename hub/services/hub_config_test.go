@@ -0,0 +1,89 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("mode configuration", func() {
+	BeforeEach(func() {
+		cm.AddStep(upgradestatus.CONVERT_MASTER, pb.UpgradeSteps_CONVERT_MASTER)
+		cm.AddStep(upgradestatus.CONVERT_PRIMARIES, pb.UpgradeSteps_CONVERT_PRIMARIES)
+	})
+
+	It("defaults to copy mode", func() {
+		reply, err := hub.GetConfig(context.Background(), &pb.GetConfigRequest{Name: "mode"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Value).To(Equal(utils.COPY_MODE))
+	})
+
+	It("saves link mode with the target cluster", func() {
+		_, err := hub.SetConfig(context.Background(), &pb.SetConfigRequest{Name: "mode", Value: "link"})
+		Expect(err).ToNot(HaveOccurred())
+
+		reply, err := hub.GetConfig(context.Background(), &pb.GetConfigRequest{Name: "mode"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Value).To(Equal(utils.LINK_MODE))
+
+		saved := &utils.Cluster{ConfigPath: target.ConfigPath}
+		Expect(saved.Load()).To(Succeed())
+		Expect(saved.Mode).To(Equal(utils.LINK_MODE))
+	})
+
+	It("rejects an unknown mode", func() {
+		_, err := hub.SetConfig(context.Background(), &pb.SetConfigRequest{Name: "mode", Value: "clone"})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(target.Mode).To(BeEmpty())
+	})
+
+	It("refuses to change the mode once conversion has started", func() {
+		cm.GetStepWriter(upgradestatus.CONVERT_MASTER).MarkInProgress()
+
+		_, err := hub.SetConfig(context.Background(), &pb.SetConfigRequest{Name: "mode", Value: "link"})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(target.Mode).To(BeEmpty())
+	})
+
+	It("refuses to change the mode once a link-mode pg_upgrade has started, even after its step is reset", func() {
+		target.Mode = utils.LINK_MODE
+		Expect(ioutil.WriteFile(filepath.Join(dir, "link-mode-started"), nil, 0644)).To(Succeed())
+
+		_, err := hub.SetConfig(context.Background(), &pb.SetConfigRequest{Name: "mode", Value: "copy"})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(target.Mode).To(Equal(utils.LINK_MODE))
+	})
+})
+
+var _ = Describe("layout configuration", func() {
+	BeforeEach(func() {
+		cm.AddStep(upgradestatus.INIT_CLUSTER, pb.UpgradeSteps_INIT_CLUSTER)
+	})
+
+	It("refuses to change the target layout once init-cluster has started", func() {
+		cm.GetStepWriter(upgradestatus.INIT_CLUSTER).MarkInProgress()
+		cm.GetStepWriter(upgradestatus.INIT_CLUSTER).MarkFailed(errors.New("gpinitsystem failed"))
+
+		_, err := hub.SetConfig(context.Background(), &pb.SetConfigRequest{Name: "target-datadir-template", Value: "{parent}_v6/{base}"})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(target.Layout.DataDirTemplate).To(BeEmpty())
+	})
+
+	It("still lets the target's bindir be changed once init-cluster has started", func() {
+		cm.GetStepWriter(upgradestatus.INIT_CLUSTER).MarkInProgress()
+
+		_, err := hub.SetConfig(context.Background(), &pb.SetConfigRequest{Name: "new-bindir", Value: "/usr/local/gpdb6/bin"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target.BinDir).To(Equal("/usr/local/gpdb6/bin"))
+	})
+})
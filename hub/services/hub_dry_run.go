@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// A StepPlanner describes what a step would do if it were started now, without
+// changing anything. It is what a step's RPC returns when dryRun is set.
+type StepPlanner func() (*pb.DryRunPlan, error)
+
+// PlanSteps collects the plan of each of the given steps, in order. A step
+// that can't be planned -- usually because it needs something that an earlier
+// step creates, such as the target cluster -- gets a note saying why instead.
+func PlanSteps(steps []upgradestatus.StateReader, planners map[string]StepPlanner) []*pb.DryRunPlan {
+	var plans []*pb.DryRunPlan
+	for _, step := range steps {
+		plan := &pb.DryRunPlan{}
+
+		planner, ok := planners[step.Name()]
+		if !ok {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("don't know how to plan step %s", step.Name()))
+		} else if stepPlan, err := planner(); err != nil {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("cannot plan this step yet: %s", err.Error()))
+		} else {
+			plan = stepPlan
+		}
+
+		plan.Step = step.Code()
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+// stepPlanners maps each checklist step to the planner behind its dryRun
+// option.
+func (h *Hub) stepPlanners() map[string]StepPlanner {
+	return map[string]StepPlanner{
+		upgradestatus.CONFIG: func() (*pb.DryRunPlan, error) {
+			return &pb.DryRunPlan{Notes: []string{
+				fmt.Sprintf("read the segment configuration of the source cluster and save it to %s", h.source.ConfigPath),
+			}}, nil
+		},
+		upgradestatus.SEGINSTALL:             h.planSeginstall,
+		upgradestatus.START_AGENTS:           h.planStartAgents,
+		upgradestatus.INIT_CLUSTER:           h.connectAndPlanInitCluster,
+		upgradestatus.SHUTDOWN_CLUSTERS:      h.planShutdownClusters,
+		upgradestatus.CONVERT_MASTER:         h.planConvertMaster,
+		upgradestatus.SHARE_OIDS:             h.planShareOids,
+		upgradestatus.CONVERT_PRIMARIES:      h.planConvertPrimaries,
+		upgradestatus.REBUILD_MIRRORS:        h.planRebuildMirrors,
+		upgradestatus.REBUILD_STANDBY:        h.planRebuildStandby,
+		upgradestatus.VALIDATE_START_CLUSTER: h.planValidateStartCluster,
+		upgradestatus.RECONFIGURE_PORTS:      h.planReconfigurePorts,
+	}
+}
+
+// localCommand plans a command that the hub runs itself, on the master host.
+func localCommand(c *utils.Cluster, command string) *pb.PlannedCommand {
+	return &pb.PlannedCommand{Hostname: c.MasterHostname(), Command: command}
+}
+
+// onHost fills in the hostname of each command and file in a plan made by an
+// agent, which doesn't know the name that the hub knows it by.
+func onHost(plan *pb.DryRunPlan, hostname string) *pb.DryRunPlan {
+	if plan == nil {
+		return nil
+	}
+	for _, command := range plan.Commands {
+		command.Hostname = hostname
+	}
+	for _, file := range plan.Files {
+		file.Hostname = hostname
+	}
+	return plan
+}
+
+// mergePlans combines plans from several hosts into one. Nil plans are
+// skipped.
+func mergePlans(plans []*pb.DryRunPlan) *pb.DryRunPlan {
+	merged := &pb.DryRunPlan{}
+	for _, plan := range plans {
+		if plan == nil {
+			continue
+		}
+		merged.Commands = append(merged.Commands, plan.Commands...)
+		merged.Files = append(merged.Files, plan.Files...)
+		merged.Notes = append(merged.Notes, plan.Notes...)
+	}
+	return merged
+}
+
+// onEveryHost plans the same command on each of the cluster's hosts.
+func onEveryHost(c *utils.Cluster, command string) []*pb.PlannedCommand {
+	var commands []*pb.PlannedCommand
+	for _, host := range sortedHostnames(c) {
+		commands = append(commands, &pb.PlannedCommand{Hostname: host, Command: command})
+	}
+	return commands
+}
+
+// sortedHostnames gives plans a stable order; GetHostnames makes no promises.
+func sortedHostnames(c *utils.Cluster) []string {
+	hostnames := c.GetHostnames()
+	sort.Strings(hostnames)
+	return hostnames
+}
+
+// requireSource and requireTarget return an error if the configuration of
+// the respective cluster hasn't been saved yet, since nothing that happens to
+// a cluster can be planned without it.
+func (h *Hub) requireSource() error {
+	if h.source.Cluster == nil {
+		return fmt.Errorf("the source cluster configuration has not been saved")
+	}
+	return nil
+}
+
+func (h *Hub) requireTarget() error {
+	if h.target.Cluster == nil {
+		return fmt.Errorf("the target cluster has not been initialized")
+	}
+	return nil
+}
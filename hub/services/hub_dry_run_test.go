@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("dry runs", func() {
+	var testExecutor *testhelper.TestExecutor
+
+	BeforeEach(func() {
+		testExecutor = &testhelper.TestExecutor{}
+		source.Executor = testExecutor
+		target.Executor = testExecutor
+	})
+
+	Describe("PlanSteps", func() {
+		BeforeEach(func() {
+			cm.AddStep(upgradestatus.CONFIG, pb.UpgradeSteps_CONFIG)
+			cm.AddStep(upgradestatus.SEGINSTALL, pb.UpgradeSteps_SEGINSTALL)
+			cm.AddStep(upgradestatus.INIT_CLUSTER, pb.UpgradeSteps_INIT_CLUSTER)
+		})
+
+		It("labels each step's plan with the step, in order", func() {
+			plans := services.PlanSteps(cm.AllSteps(), map[string]services.StepPlanner{
+				upgradestatus.CONFIG: func() (*pb.DryRunPlan, error) {
+					return &pb.DryRunPlan{Notes: []string{"config"}}, nil
+				},
+				upgradestatus.SEGINSTALL: func() (*pb.DryRunPlan, error) {
+					return &pb.DryRunPlan{Commands: []*pb.PlannedCommand{{Hostname: "mdw", Command: "ls"}}}, nil
+				},
+				upgradestatus.INIT_CLUSTER: func() (*pb.DryRunPlan, error) {
+					return &pb.DryRunPlan{}, nil
+				},
+			})
+
+			Expect(plans).To(Equal([]*pb.DryRunPlan{
+				{Notes: []string{"config"}, Step: pb.UpgradeSteps_CONFIG},
+				{Commands: []*pb.PlannedCommand{{Hostname: "mdw", Command: "ls"}}, Step: pb.UpgradeSteps_SEGINSTALL},
+				{Step: pb.UpgradeSteps_INIT_CLUSTER},
+			}))
+		})
+
+		It("notes why a step couldn't be planned and carries on", func() {
+			plans := services.PlanSteps(cm.AllSteps(), map[string]services.StepPlanner{
+				upgradestatus.CONFIG: func() (*pb.DryRunPlan, error) {
+					return nil, errors.New("no cluster")
+				},
+				upgradestatus.INIT_CLUSTER: func() (*pb.DryRunPlan, error) {
+					return &pb.DryRunPlan{Notes: []string{"init"}}, nil
+				},
+			})
+
+			Expect(plans).To(HaveLen(3))
+			Expect(plans[0].Notes).To(ConsistOf("cannot plan this step yet: no cluster"))
+			Expect(plans[1].Notes).To(ConsistOf(ContainSubstring("don't know how to plan step")))
+			Expect(plans[2].Notes).To(ConsistOf("init"))
+		})
+	})
+
+	It("plans reconfigure-ports without running anything", func() {
+		reply, err := hub.UpgradeReconfigurePorts(nil, &pb.UpgradeReconfigurePortsRequest{DryRun: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(testExecutor.NumExecutions).To(Equal(0))
+		Expect(reply.Plan.Commands).To(Equal([]*pb.PlannedCommand{{
+			Hostname: "localhost",
+			Command:  fmt.Sprintf(services.SedAndMvString, target.MasterPort(), source.MasterPort(), target.MasterDataDir()),
+		}}))
+	})
+
+	It("plans shutdown-clusters without running anything", func() {
+		reply, err := hub.PrepareShutdownClusters(nil, &pb.PrepareShutdownClustersRequest{DryRun: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(testExecutor.NumExecutions).To(Equal(0))
+		Expect(reply.Plan.Commands).To(HaveLen(2))
+		Expect(reply.Plan.Commands[0].Command).To(ContainSubstring("gpstop -a -d " + source.MasterDataDir()))
+		Expect(reply.Plan.Commands[1].Command).To(ContainSubstring("gpstop -a -d " + target.MasterDataDir()))
+	})
+
+	It("asks the agents to plan convert-primaries and fills in their hostnames", func() {
+		mockAgent.UpgradeConvertPrimarySegmentsResponse = &pb.UpgradeConvertPrimarySegmentsReply{
+			Plan: &pb.DryRunPlan{Commands: []*pb.PlannedCommand{{Command: "pg_upgrade"}}},
+		}
+
+		reply, err := hub.UpgradeConvertPrimaries(nil, &pb.UpgradeConvertPrimariesRequest{DryRun: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mockAgent.UpgradeConvertPrimarySegmentsRequest.DryRun).To(BeTrue())
+		Expect(reply.Plan.Commands).To(Equal([]*pb.PlannedCommand{{Hostname: "localhost", Command: "pg_upgrade"}}))
+	})
+
+	It("plans a revert without running anything", func() {
+		cm.AddStep(upgradestatus.CONFIG, pb.UpgradeSteps_CONFIG)
+
+		reply, err := hub.Revert(nil, &pb.RevertRequest{DryRun: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(testExecutor.NumExecutions).To(Equal(0))
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
+
+		var commands []string
+		for _, command := range reply.Plan.Commands {
+			commands = append(commands, command.Command)
+		}
+		Expect(commands[0]).To(ContainSubstring("gpstop"))
+		Expect(commands).To(ContainElement(ContainSubstring("gpstart -a -d " + source.MasterDataDir())))
+		Expect(commands[len(commands)-1]).To(ContainSubstring("gpstart"))
+		Expect(reply.Plan.Notes).To(ContainElement(ContainSubstring(filepath.Join(dir, "steps.json"))))
+	})
+})
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// FanOut makes the same call to the agents on many hosts at once.
+type FanOut struct {
+	Parallelism int           // how many hosts to call at once; 0 means all of them
+	Timeout     time.Duration // the deadline for each host's call; 0 means none
+
+	// Unreachable holds, for each host whose agent can't be called, why not.
+	// Call reports them among its results as failed.
+	Unreachable HostResults
+}
+
+// fanOut is how the hub calls its agents, as configured.
+func (h *Hub) fanOut() FanOut {
+	return FanOut{Parallelism: h.conf.Parallelism, Timeout: h.conf.AgentTimeout}
+}
+
+// agentFanOut connects to the agents on the given hosts. It returns the
+// connections to those that can be reached, and a FanOut that reports each of
+// the others as failed, with the reason it can't be reached, so that one
+// unreachable host doesn't stop the call to the rest.
+func (h *Hub) agentFanOut(hostnames ...string) (FanOut, []*Connection) {
+	conns, unreachable := h.agents.Reachable(hostnames...)
+	for host, result := range unreachable {
+		unreachable[host] = HostResult{Err: errors.Wrap(result.Err, "could not connect to the agent")}
+	}
+
+	fanOut := h.fanOut()
+	fanOut.Unreachable = unreachable
+	return fanOut, conns
+}
+
+// AgentCall is one host's part of a FanOut. Whatever it returns is recorded
+// in the HostResults under the client's hostname.
+type AgentCall func(ctx context.Context, client ClientAndHostname) (interface{}, error)
+
+// HostResult is how the call to one host went.
+type HostResult struct {
+	Reply interface{}
+	Err   error
+}
+
+// HostResults maps each hostname to how the call to it went.
+type HostResults map[string]HostResult
+
+// Call runs call against every client and waits for all of them to finish.
+func (f FanOut) Call(clients []ClientAndHostname, call AgentCall) HostResults {
+	parallelism := f.Parallelism
+	if parallelism <= 0 || parallelism > len(clients) {
+		parallelism = len(clients)
+	}
+
+	results := HostResults{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallelism)
+	for _, client := range clients {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(client ClientAndHostname) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			ctx := context.Background()
+			if f.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, f.Timeout)
+				defer cancel()
+			}
+
+			reply, err := call(ctx, client)
+			if err != nil && ctx.Err() == context.DeadlineExceeded {
+				err = errors.Wrapf(err, "no reply within %s", f.Timeout)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[client.Hostname] = HostResult{Reply: reply, Err: err}
+		}(client)
+	}
+	wg.Wait()
+
+	for host, result := range f.Unreachable {
+		if _, ok := results[host]; !ok {
+			results[host] = result
+		}
+	}
+	return results
+}
+
+// Failed lists the hosts whose calls failed, in order.
+func (r HostResults) Failed() []string {
+	var hosts []string
+	for host, result := range r {
+		if result.Err != nil {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// Err names each host whose call failed, with its error, or is nil if none
+// did.
+func (r HostResults) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	var problems []string
+	for _, host := range failed {
+		problems = append(problems, fmt.Sprintf("%s: %s", host, r[host].Err))
+	}
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// Clients pairs each connection's hostname with a client for its agent.
+func Clients(conns []*Connection) []ClientAndHostname {
+	var clients []ClientAndHostname
+	for _, conn := range conns {
+		client := conn.AgentClient
+		if client == nil {
+			client = pb.NewAgentClient(conn.Conn)
+		}
+		clients = append(clients, ClientAndHostname{Client: client, Hostname: conn.Hostname})
+	}
+	return clients
+}
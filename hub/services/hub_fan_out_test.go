@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("FanOut", func() {
+	hosts := func(names ...string) []services.ClientAndHostname {
+		var clients []services.ClientAndHostname
+		for _, name := range names {
+			clients = append(clients, services.ClientAndHostname{Hostname: name})
+		}
+		return clients
+	}
+
+	It("records each host's reply and error under its name", func() {
+		results := services.FanOut{}.Call(hosts("sdw1", "sdw2", "sdw3"), func(ctx context.Context, client services.ClientAndHostname) (interface{}, error) {
+			if client.Hostname == "sdw2" {
+				return nil, errors.New("agent is down")
+			}
+			return "hello from " + client.Hostname, nil
+		})
+
+		Expect(results).To(HaveLen(3))
+		Expect(results["sdw1"]).To(Equal(services.HostResult{Reply: "hello from sdw1"}))
+		Expect(results["sdw2"].Err).To(MatchError("agent is down"))
+		Expect(results["sdw3"].Reply).To(Equal("hello from sdw3"))
+		Expect(results.Failed()).To(Equal([]string{"sdw2"}))
+	})
+
+	It("names every host that failed, in order", func() {
+		results := services.FanOut{}.Call(hosts("sdw3", "sdw1", "sdw2"), func(ctx context.Context, client services.ClientAndHostname) (interface{}, error) {
+			if client.Hostname == "sdw2" {
+				return nil, nil
+			}
+			return nil, errors.New("no route to host")
+		})
+
+		Expect(results.Err()).To(MatchError("sdw1: no route to host; sdw3: no route to host"))
+	})
+
+	It("reports the hosts it could not reach as failed, and calls the rest", func() {
+		fanOut := services.FanOut{Unreachable: services.HostResults{
+			"sdw2": {Err: errors.New("could not connect to the agent: connection refused")},
+		}}
+		var called []string
+		results := fanOut.Call(hosts("sdw1"), func(ctx context.Context, client services.ClientAndHostname) (interface{}, error) {
+			called = append(called, client.Hostname)
+			return nil, nil
+		})
+
+		Expect(called).To(Equal([]string{"sdw1"}))
+		Expect(results).To(HaveLen(2))
+		Expect(results.Err()).To(MatchError("sdw2: could not connect to the agent: connection refused"))
+	})
+
+	It("has no error when every host succeeds", func() {
+		results := services.FanOut{}.Call(hosts("sdw1", "sdw2"), func(ctx context.Context, client services.ClientAndHostname) (interface{}, error) {
+			return nil, nil
+		})
+
+		Expect(results.Failed()).To(BeEmpty())
+		Expect(results.Err()).ToNot(HaveOccurred())
+	})
+
+	It("calls no more hosts at once than its parallelism", func() {
+		var mu sync.Mutex
+		running, most := 0, 0
+
+		fanOut := services.FanOut{Parallelism: 2}
+		results := fanOut.Call(hosts("sdw1", "sdw2", "sdw3", "sdw4", "sdw5"), func(ctx context.Context, client services.ClientAndHostname) (interface{}, error) {
+			mu.Lock()
+			running++
+			if running > most {
+				most = running
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil, nil
+		})
+
+		Expect(results).To(HaveLen(5))
+		Expect(most).To(Equal(2))
+	})
+
+	It("gives up on a host that does not answer in time", func() {
+		fanOut := services.FanOut{Timeout: 10 * time.Millisecond}
+		results := fanOut.Call(hosts("sdw1", "sdw2"), func(ctx context.Context, client services.ClientAndHostname) (interface{}, error) {
+			if client.Hostname == "sdw1" {
+				return nil, nil
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+		Expect(results.Failed()).To(Equal([]string{"sdw2"}))
+		Expect(results.Err()).To(MatchError("sdw2: no reply within 10ms: context deadline exceeded"))
+	})
+})
@@ -4,17 +4,13 @@ import (
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"google.golang.org/grpc"
 
+	"strconv"
 	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"golang.org/x/net/context"
 )
 
-const (
-	// todo generalize to any host
-	port = "6416"
-)
-
 type ClientAndHostname struct {
 	Client   pb.AgentClient
 	Hostname string
@@ -24,6 +20,7 @@ type PingerManager struct {
 	RPCClients       []ClientAndHostname
 	NumRetries       int
 	PauseBeforeRetry time.Duration
+	FanOut           FanOut
 }
 
 // nolint: unparam
@@ -31,15 +28,18 @@ type PingerManager struct {
 // and we're going to refactor it later and we don't want to change all the calls now.
 func NewPingerManager(stateDir string, t time.Duration) *PingerManager {
 	// TODO: Do this *after* the hub exists
-	//rpcClients := GetClients(pair.GetHostnames())
-	//return &PingerManager{rpcClients, 10, t}
-	return &PingerManager{[]ClientAndHostname{}, 10, t}
+	//transport, err := h.AgentTransport()
+	//rpcClients := GetClients(pair.GetHostnames(), h.conf.HubToAgentPort, transport)
+	//return &PingerManager{rpcClients, 10, t, h.fanOut()}
+	return &PingerManager{[]ClientAndHostname{}, 10, t, FanOut{}}
 }
 
-func GetClients(hostnames []string) []ClientAndHostname {
+// GetClients connects to the agents with the given transport, which comes from
+// Hub.AgentTransport.
+func GetClients(hostnames []string, port int, transport grpc.DialOption) []ClientAndHostname {
 	var clients []ClientAndHostname
 	for i := 0; i < len(hostnames); i++ {
-		conn, err := grpc.Dial(hostnames[i]+":"+port, grpc.WithInsecure())
+		conn, err := grpc.Dial(hostnames[i]+":"+strconv.Itoa(port), transport)
 		if err != nil {
 			gplog.Error(err.Error())
 		}
@@ -71,14 +71,13 @@ func (agent *PingerManager) PingPollAgents() error {
 }
 
 func (agent *PingerManager) PingAllAgents() error {
-	//TODO: ping all the agents in parallel?
-	for i := 0; i < len(agent.RPCClients); i++ {
-		_, err := agent.RPCClients[i].Client.PingAgents(context.Background(), &pb.PingAgentsRequest{})
-		if err != nil {
-			gplog.Error("Not all agents on the segment hosts are running.")
-			return err
-		}
-	}
+	results := agent.FanOut.Call(agent.RPCClients, func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
+		return client.Client.PingAgents(ctx, &pb.PingAgentsRequest{})
+	})
 
-	return nil
+	err := results.Err()
+	if err != nil {
+		gplog.Error("Not all agents on the segment hosts are running: %s", err)
+	}
+	return err
 }
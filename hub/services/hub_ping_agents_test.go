@@ -45,7 +45,7 @@ var _ = Describe("hub pings agents test", func() {
 			).Return(&pb.PingAgentsReply{}, errors.New("call to agent fail"))
 
 			err := pingerManager.PingAllAgents()
-			Expect(err).To(MatchError("call to agent fail"))
+			Expect(err).To(MatchError("doesnotexist: call to agent fail"))
 		})
 
 		It("grpc calls succeed, only one ping", func() {
@@ -67,7 +67,7 @@ var _ = Describe("hub pings agents test", func() {
 			}
 
 			err := pingerManager.PingPollAgents()
-			Expect(err).To(MatchError("call to agent fail"))
+			Expect(err).To(MatchError("doesnotexist: call to agent fail"))
 		})
 	})
 })
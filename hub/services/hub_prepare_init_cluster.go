@@ -6,8 +6,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
-	"sync"
 
 	"github.com/greenplum-db/gpupgrade/db"
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
@@ -42,19 +42,29 @@ func SaveTargetClusterConfig(target *utils.Cluster, dbConnector *dbconn.DBConn,
 
 func (h *Hub) PrepareInitCluster(ctx context.Context, in *pb.PrepareInitClusterRequest) (*pb.PrepareInitClusterReply, error) {
 	gplog.Info("Running PrepareInitCluster()")
+
+	if err := h.checkPrerequisites(upgradestatus.INIT_CLUSTER, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
+		return &pb.PrepareInitClusterReply{}, err
+	}
+	if in.DryRun {
+		plan, err := h.connectAndPlanInitCluster()
+		return &pb.PrepareInitClusterReply{Plan: plan}, err
+	}
+
 	dbConnector := db.NewDBConn("localhost", int(h.source.MasterPort()),
 		"template1")
 
-	go func() {
+	h.tasks.Go(upgradestatus.INIT_CLUSTER, func() {
 		step := h.checklist.GetStepWriter(upgradestatus.INIT_CLUSTER)
 		err := h.InitCluster(dbConnector)
 		if err != nil {
 			gplog.Error(err.Error())
-			step.MarkFailed()
+			step.MarkFailed(err)
 		} else {
 			step.MarkComplete()
 		}
-	}()
+	})
 	return &pb.PrepareInitClusterReply{}, nil
 }
 
@@ -62,9 +72,7 @@ func (h *Hub) InitCluster(dbConnector *dbconn.DBConn) error {
 	defer dbConnector.Close()
 
 	step := h.checklist.GetStepWriter(upgradestatus.INIT_CLUSTER)
-	segmentDataDirMap := map[string][]string{}
-	agentConns := []*Connection{}
-	gpinitsystemFilepath := filepath.Join(h.conf.StateDir, "gpinitsystem_config")
+	gpinitsystemFilepath := h.gpinitsystemFilepath()
 
 	err := initializeState(step)
 	if err != nil {
@@ -76,20 +84,20 @@ func (h *Hub) InitCluster(dbConnector *dbconn.DBConn) error {
 	}
 	dbConnector.Version.Initialize(dbConnector)
 
-	gpinitsystemConfig, err := h.CreateInitialInitsystemConfig()
+	layout, err := h.targetLayout()
 	if err != nil {
-		return err
+		return errors.Wrap(err, "Could not plan the target cluster")
 	}
-	gpinitsystemConfig, err = GetCheckpointSegmentsAndEncoding(gpinitsystemConfig, dbConnector)
+	gpinitsystemConfig, err := h.BuildInitsystemConfig(dbConnector, layout)
 	if err != nil {
 		return err
 	}
-	agentConns, err = h.AgentConns()
+	fanOut, agentConns := h.agentFanOut(layout.Hostnames()...)
+	err = CheckTargetLayout(fanOut, agentConns, h.source, layout, h.target.Mode)
 	if err != nil {
-		return errors.Wrap(err, "Could not get/create agents")
+		return err
 	}
-	gpinitsystemConfig, segmentDataDirMap = h.DeclareDataDirectories(gpinitsystemConfig)
-	err = h.CreateAllDataDirectories(agentConns, segmentDataDirMap)
+	err = h.CreateAllDataDirectories(fanOut, agentConns, layout)
 	if err != nil {
 		return err
 	}
@@ -97,10 +105,14 @@ func (h *Hub) InitCluster(dbConnector *dbconn.DBConn) error {
 	if err != nil {
 		return err
 	}
-	err = h.RunInitsystemForNewCluster(gpinitsystemFilepath)
+	err = h.RunInitsystemForNewCluster(gpinitsystemFilepath, layout)
 	if err != nil {
 		return err
 	}
+	// gpinitsystem makes neither the standby nor the mirrors; they are copied
+	// into the directories created above by rebuild-standby and
+	// rebuild-mirrors. Save where they go along with the target cluster.
+	h.target.SetMirrors(layout.StandbyAndMirrors())
 	err = SaveTargetClusterConfig(h.target, dbConnector, h.conf.StateDir)
 	if err != nil {
 		return errors.Wrap(err, "Could not save new cluster configuration")
@@ -108,6 +120,80 @@ func (h *Hub) InitCluster(dbConnector *dbconn.DBConn) error {
 	return nil
 }
 
+// BuildInitsystemConfig returns the contents of the gpinitsystem_config that
+// creates the target cluster with the given layout.
+func (h *Hub) BuildInitsystemConfig(dbConnector *dbconn.DBConn, layout *TargetLayout) ([]string, error) {
+	gpinitsystemConfig, err := h.CreateInitialInitsystemConfig()
+	if err != nil {
+		return nil, err
+	}
+	gpinitsystemConfig, err = GetCheckpointSegmentsAndEncoding(gpinitsystemConfig, dbConnector)
+	if err != nil {
+		return nil, err
+	}
+	return DeclareDataDirectories(gpinitsystemConfig, layout), nil
+}
+
+// PlanInitCluster describes what InitCluster would do, given a connection to
+// the source cluster.
+func (h *Hub) PlanInitCluster(dbConnector *dbconn.DBConn) (*pb.DryRunPlan, error) {
+	layout, err := h.targetLayout()
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not plan the target cluster")
+	}
+	gpinitsystemConfig, err := h.BuildInitsystemConfig(dbConnector, layout)
+	if err != nil {
+		return nil, err
+	}
+	segmentDataDirMap := layout.SegmentParentDirs()
+
+	plan := &pb.DryRunPlan{}
+	plan.Notes = append(plan.Notes, "check on every host that the target ports are free and the target data directories can be created")
+	plan.Commands = append(plan.Commands, localCommand(h.source, "mkdir -p "+layout.MasterParentDir()))
+
+	var hostnames []string
+	for hostname := range segmentDataDirMap {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+	for _, hostname := range hostnames {
+		for _, datadir := range segmentDataDirMap[hostname] {
+			plan.Commands = append(plan.Commands, &pb.PlannedCommand{Hostname: hostname, Command: "mkdir -p " + datadir})
+		}
+	}
+
+	plan.Files = append(plan.Files, &pb.PlannedFile{
+		Hostname: h.source.MasterHostname(),
+		Path:     h.gpinitsystemFilepath(),
+		Contents: initsystemFileContents(gpinitsystemConfig),
+	})
+	plan.Commands = append(plan.Commands, localCommand(h.source, initsystemCommand(h.gpinitsystemFilepath(), layout)))
+	plan.Notes = append(plan.Notes, "save the segment configuration of the new target cluster to "+h.target.ConfigPath)
+
+	return plan, nil
+}
+
+func (h *Hub) connectAndPlanInitCluster() (*pb.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+
+	dbConnector := db.NewDBConn("localhost", int(h.source.MasterPort()), "template1")
+	defer dbConnector.Close()
+
+	err := dbConnector.Connect(1)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not connect to database")
+	}
+	dbConnector.Version.Initialize(dbConnector)
+
+	return h.PlanInitCluster(dbConnector)
+}
+
+func (h *Hub) gpinitsystemFilepath() string {
+	return filepath.Join(h.conf.StateDir, "gpinitsystem_config")
+}
+
 func initializeState(step upgradestatus.StateWriter) error {
 	err := step.ResetStateDir()
 	if err != nil {
@@ -155,7 +241,7 @@ func (h *Hub) CreateInitialInitsystemConfig() ([]string, error) {
 }
 
 func WriteInitsystemFile(gpinitsystemConfig []string, gpinitsystemFilepath string) error {
-	gpinitsystemContents := []byte(strings.Join(gpinitsystemConfig, "\n"))
+	gpinitsystemContents := []byte(initsystemFileContents(gpinitsystemConfig))
 
 	err := ioutil.WriteFile(gpinitsystemFilepath, gpinitsystemContents, 0644)
 	if err != nil {
@@ -164,71 +250,82 @@ func WriteInitsystemFile(gpinitsystemConfig []string, gpinitsystemFilepath strin
 	return nil
 }
 
-func (h *Hub) DeclareDataDirectories(gpinitsystemConfig []string) ([]string, map[string][]string) {
+func initsystemFileContents(gpinitsystemConfig []string) string {
+	return strings.Join(gpinitsystemConfig, "\n")
+}
+
+// DeclareDataDirectories adds the segments of the target cluster to the
+// gpinitsystem_config. The standby master isn't declared here; gpinitsystem
+// takes it on the command line.
+func DeclareDataDirectories(gpinitsystemConfig []string, layout *TargetLayout) []string {
 	// declare master data directory
-	master := h.source.Segments[-1]
-	master.Port++
-	master.DataDir = fmt.Sprintf("%s_upgrade/%s", path.Dir(master.DataDir), path.Base(master.DataDir))
-	datadirDeclare := fmt.Sprintf("QD_PRIMARY_ARRAY=%s~%d~%s~%d~%d~0",
-		master.Hostname, master.Port, master.DataDir, master.DbID, master.ContentID)
+	datadirDeclare := fmt.Sprintf("QD_PRIMARY_ARRAY=%s", segmentDeclaration(layout.Master))
 	gpinitsystemConfig = append(gpinitsystemConfig, datadirDeclare)
+
 	// declare segment data directories
-	segmentDataDirMap := map[string][]string{}
 	segmentDeclarations := []string{}
-	for _, content := range h.source.ContentIDs {
-		if content != -1 {
-			segment := h.source.Segments[content]
-			// FIXME: Arbitrary assumption.	 Do something smarter later
-			segment.Port += 2000
-			datadir := fmt.Sprintf("%s_upgrade", path.Dir(segment.DataDir))
-			segment.DataDir = fmt.Sprintf("%s/%s", datadir, path.Base(segment.DataDir))
-			segmentDataDirMap[segment.Hostname] = append(segmentDataDirMap[segment.Hostname],
-				datadir)
-			declaration := fmt.Sprintf("\t%s~%d~%s~%d~%d~0",
-				segment.Hostname, segment.Port, segment.DataDir, segment.DbID, segment.ContentID)
-			segmentDeclarations = append(segmentDeclarations, declaration)
-		}
+	for _, segment := range layout.Primaries {
+		segmentDeclarations = append(segmentDeclarations, "\t"+segmentDeclaration(segment))
 	}
 	datadirDeclare = fmt.Sprintf("declare -a PRIMARY_ARRAY=(\n%s\n)", strings.Join(segmentDeclarations, "\n"))
 	gpinitsystemConfig = append(gpinitsystemConfig, datadirDeclare)
-	return gpinitsystemConfig, segmentDataDirMap
+
+	// declare mirror data directories, if the source cluster has mirrors
+	if len(layout.Mirrors) > 0 {
+		mirrorDeclarations := []string{}
+		for _, mirror := range layout.Mirrors {
+			mirrorDeclarations = append(mirrorDeclarations, "\t"+segmentDeclaration(mirror))
+		}
+		datadirDeclare = fmt.Sprintf("declare -a MIRROR_ARRAY=(\n%s\n)", strings.Join(mirrorDeclarations, "\n"))
+		gpinitsystemConfig = append(gpinitsystemConfig, datadirDeclare)
+	}
+
+	return gpinitsystemConfig
+}
+
+func segmentDeclaration(segment cluster.SegConfig) string {
+	return fmt.Sprintf("%s~%d~%s~%d~%d~0",
+		segment.Hostname, segment.Port, segment.DataDir, segment.DbID, segment.ContentID)
 }
 
-func (h *Hub) CreateAllDataDirectories(agentConns []*Connection, segmentDataDirMap map[string][]string) error {
+func (h *Hub) CreateAllDataDirectories(fanOut FanOut, agentConns []*Connection, layout *TargetLayout) error {
 	// create master data directory for gpinitsystem if it doesn't exist
-	targetDataDir := path.Dir(h.source.MasterDataDir()) + "_upgrade"
-	_, err := utils.System.Stat(targetDataDir)
-	if os.IsNotExist(err) {
-		err = utils.System.MkdirAll(targetDataDir, 0755)
-		if err != nil {
-			return errors.Wrap(err, "Could not create new directory")
-		}
-	} else if err != nil {
-		return errors.Wrapf(err, "Error statting new directory %s", targetDataDir)
+	targetDataDir := layout.MasterParentDir()
+	_, err := utils.CreateTargetDir(targetDataDir)
+	if err != nil {
+		return errors.Wrapf(err, "Could not create new directory %s", targetDataDir)
 	}
 	// create segment data directories for gpinitsystem if they don't exist
-	err = CreateSegmentDataDirectories(agentConns, segmentDataDirMap)
+	err = CreateSegmentDataDirectories(fanOut, agentConns, layout.SegmentParentDirs())
 	if err != nil {
 		return errors.Wrap(err, "Could not create segment data directories")
 	}
 	return nil
 }
 
-func (h *Hub) RunInitsystemForNewCluster(gpinitsystemFilepath string) error {
+func (h *Hub) RunInitsystemForNewCluster(gpinitsystemFilepath string, layout *TargetLayout) error {
 	// gpinitsystem the new cluster
-	cmdStr := fmt.Sprintf("gpinitsystem -a -I %s", gpinitsystemFilepath)
-	output, err := h.source.Executor.ExecuteLocalCommand(cmdStr)
+	command := initsystemCommand(gpinitsystemFilepath, layout)
+	output, err := h.source.Executor.ExecuteLocalCommand(command)
 	if err != nil {
 		// gpinitsystem has a return code of 1 for warnings, so we can ignore that return code
 		if err.Error() == "exit status 1" {
 			gplog.Warn("gpinitsystem completed with warnings")
 			return nil
 		}
-		return errors.Wrapf(err, "gpinitsystem failed: %s", output)
+		return errors.Wrapf(commandError(command, err, adminLogFile("gpinitsystem")), "gpinitsystem failed: %s", output)
 	}
 	return nil
 }
 
+func initsystemCommand(gpinitsystemFilepath string, layout *TargetLayout) string {
+	command := fmt.Sprintf("gpinitsystem -a -I %s", gpinitsystemFilepath)
+	if standby := layout.Standby; standby != nil {
+		command += fmt.Sprintf(" -s %s -P %d -S %s", standby.Hostname, standby.Port, standby.DataDir)
+	}
+	return command
+}
+
 func GetMasterSegPrefix(datadir string) (string, error) {
 	const masterContentID = "-1"
 
@@ -244,35 +341,17 @@ func GetMasterSegPrefix(datadir string) (string, error) {
 	return segPrefix, nil
 }
 
-func CreateSegmentDataDirectories(agentConns []*Connection, dataDirMap map[string][]string) error {
-	wg := sync.WaitGroup{}
-	errChan := make(chan error, len(agentConns))
-	for _, agentConn := range agentConns {
-		wg.Add(1)
-		go func(c *Connection) {
-			defer wg.Done()
-
-			client := pb.NewAgentClient(c.Conn)
-			_, err := client.CreateSegmentDataDirectories(context.Background(),
-				&pb.CreateSegmentDataDirRequest{
-					Datadirs: dataDirMap[c.Hostname],
-				})
-
-			if err != nil {
-				gplog.Error("Error creating segment data directories on host %s: %s",
-					c.Hostname, err.Error())
-				errChan <- err
-			}
-		}(agentConn)
-	}
-
-	wg.Wait()
-	close(errChan)
+func CreateSegmentDataDirectories(fanOut FanOut, agentConns []*Connection, dataDirMap map[string][]string) error {
+	results := fanOut.Call(Clients(agentConns), func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
+		return client.Client.CreateSegmentDataDirectories(ctx, &pb.CreateSegmentDataDirRequest{
+			Datadirs: dataDirMap[client.Hostname],
+		})
+	})
 
-	for err := range errChan {
-		if err != nil {
-			return errors.Wrap(err, "Error creating segment data directories")
-		}
+	err := results.Err()
+	if err != nil {
+		gplog.Error("Error creating segment data directories: %s", err.Error())
+		return errors.Wrap(err, "Error creating segment data directories")
 	}
 	return nil
 }
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"golang.org/x/net/context"
@@ -89,15 +91,54 @@ var _ = Describe("Hub prepare init-cluster", func() {
 	not_localhost~27432~%[1]s_upgrade/seg1~2~0~0
 	localhost~27433~%[1]s_upgrade/seg2~3~1~0
 )`, dir)}
-			resultConfig, resultMap := hub.DeclareDataDirectories([]string{})
-			Expect(resultMap).To(Equal(segDataDirMap))
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{})
+			Expect(err).ToNot(HaveOccurred())
+
+			resultConfig := services.DeclareDataDirectories([]string{}, layout)
+			Expect(layout.SegmentParentDirs()).To(Equal(segDataDirMap))
 			Expect(resultConfig).To(Equal(expectedConfig))
 		})
+
+		It("declares mirrors and makes room for the standby when the source cluster has them", func() {
+			source.SetMirrors([]cluster.SegConfig{
+				{ContentID: -1, DbID: 4, Port: 15432, Hostname: "smdw", DataDir: "/standby/seg-1"},
+				{ContentID: 0, DbID: 5, Port: 35432, Hostname: "localhost", DataDir: "/mirror/seg1"},
+				{ContentID: 1, DbID: 6, Port: 35433, Hostname: "not_localhost", DataDir: "/mirror/seg2"},
+			})
+
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{})
+			Expect(err).ToNot(HaveOccurred())
+
+			resultConfig := services.DeclareDataDirectories([]string{}, layout)
+			Expect(resultConfig).To(HaveLen(3))
+			Expect(resultConfig[2]).To(Equal(`declare -a MIRROR_ARRAY=(
+	localhost~37432~/mirror_upgrade/seg1~5~0~0
+	not_localhost~37433~/mirror_upgrade/seg2~6~1~0
+)`))
+			Expect(layout.SegmentParentDirs()).To(Equal(map[string][]string{
+				"localhost":     {fmt.Sprintf("%s_upgrade", dir), "/mirror_upgrade"},
+				"not_localhost": {fmt.Sprintf("%s_upgrade", dir), "/mirror_upgrade"},
+				"smdw":          {"/standby_upgrade"},
+			}))
+		})
 	})
 	Describe("CreateAllDataDirectories", func() {
+		var layout *services.TargetLayout
+
+		BeforeEach(func() {
+			var err error
+			layout, err = services.PlanTargetLayout(source, utils.LayoutSettings{})
+			Expect(err).ToNot(HaveOccurred())
+
+			utils.System.WriteFile = func(filename string, data []byte, perm os.FileMode) error {
+				return nil
+			}
+		})
+
 		It("successfully creates all directories", func() {
 			statCalls := []string{}
 			mkdirCalls := []string{}
+			markers := []string{}
 			utils.System.Stat = func(name string) (os.FileInfo, error) {
 				statCalls = append(statCalls, name)
 				return nil, os.ErrNotExist
@@ -106,19 +147,24 @@ var _ = Describe("Hub prepare init-cluster", func() {
 				mkdirCalls = append(mkdirCalls, path)
 				return nil
 			}
+			utils.System.WriteFile = func(filename string, data []byte, perm os.FileMode) error {
+				markers = append(markers, filename)
+				return nil
+			}
 			fakeConns := []*services.Connection{}
-			err := hub.CreateAllDataDirectories(fakeConns, segDataDirMap)
+			err := hub.CreateAllDataDirectories(services.FanOut{}, fakeConns, layout)
 			Expect(err).To(BeNil())
 			Expect(statCalls).To(Equal([]string{fmt.Sprintf("%s_upgrade", dir)}))
 			Expect(mkdirCalls).To(Equal([]string{fmt.Sprintf("%s_upgrade", dir)}))
+			Expect(markers).To(Equal([]string{filepath.Join(dir+"_upgrade", utils.TARGET_DIR_MARKER)}))
 		})
 		It("cannot stat the master data directory", func() {
 			utils.System.Stat = func(name string) (os.FileInfo, error) {
 				return nil, errors.New("permission denied")
 			}
 			fakeConns := []*services.Connection{}
-			expectedErr := errors.Errorf("Error statting new directory %s_upgrade: permission denied", dir)
-			err := hub.CreateAllDataDirectories(fakeConns, segDataDirMap)
+			expectedErr := errors.Errorf("Could not create new directory %s_upgrade: permission denied", dir)
+			err := hub.CreateAllDataDirectories(services.FanOut{}, fakeConns, layout)
 			Expect(err.Error()).To(Equal(expectedErr.Error()))
 		})
 		It("cannot create the master data directory", func() {
@@ -129,8 +175,8 @@ var _ = Describe("Hub prepare init-cluster", func() {
 				return errors.New("permission denied")
 			}
 			fakeConns := []*services.Connection{}
-			expectedErr := errors.New("Could not create new directory: permission denied")
-			err := hub.CreateAllDataDirectories(fakeConns, segDataDirMap)
+			expectedErr := errors.Errorf("Could not create new directory %s_upgrade: permission denied", dir)
+			err := hub.CreateAllDataDirectories(services.FanOut{}, fakeConns, layout)
 			Expect(err.Error()).To(Equal(expectedErr.Error()))
 		})
 		It("cannot create the segment data directories", func() {
@@ -142,7 +188,7 @@ var _ = Describe("Hub prepare init-cluster", func() {
 			}
 			badConnection, _ := grpc.DialContext(context.Background(), "localhost:6416", grpc.WithInsecure())
 			fakeConns := []*services.Connection{{badConnection, nil, "localhost", func() {}}}
-			err := hub.CreateAllDataDirectories(fakeConns, segDataDirMap)
+			err := hub.CreateAllDataDirectories(services.FanOut{}, fakeConns, layout)
 			Expect(err).To(HaveOccurred())
 		})
 	})
@@ -151,31 +197,55 @@ var _ = Describe("Hub prepare init-cluster", func() {
 		var (
 			testExecutor *testhelper.TestExecutor
 			stdout       *gbytes.Buffer
+			layout       *services.TargetLayout
 		)
 
 		BeforeEach(func() {
 			stdout, _, _ = testhelper.SetupTestLogger()
 			testExecutor = &testhelper.TestExecutor{}
 			source.Executor = testExecutor
+
+			var err error
+			layout, err = services.PlanTargetLayout(source, utils.LayoutSettings{})
+			Expect(err).ToNot(HaveOccurred())
 		})
 		It("successfully runs gpinitsystem", func() {
 			testExecutor.LocalError = errors.New("exit status 1")
-			err := hub.RunInitsystemForNewCluster("filepath")
+			err := hub.RunInitsystemForNewCluster("filepath", layout)
 			Expect(err).To(BeNil())
 			testhelper.ExpectRegexp(stdout, "[WARNING]:-gpinitsystem completed with warnings")
 		})
 		It("runs gpinitsystem and fails", func() {
 			testExecutor.LocalError = errors.New("exit status 2")
 			testExecutor.LocalOutput = "some output"
-			err := hub.RunInitsystemForNewCluster("filepath")
+			err := hub.RunInitsystemForNewCluster("filepath", layout)
 			Expect(err.Error()).To(Equal("gpinitsystem failed: some output: exit status 2"))
+
+			cmdErr, ok := errors.Cause(err).(*upgradestatus.CommandError)
+			Expect(ok).To(BeTrue())
+			Expect(cmdErr.Command).To(Equal("gpinitsystem -a -I filepath"))
+			Expect(cmdErr.ExitCode).To(Equal(2))
+			Expect(cmdErr.LogFile).To(MatchRegexp(`/gpAdminLogs/gpinitsystem_\d{8}\.log$`))
 		})
 		It("runs gpinitsystem and receives an interrupt", func() {
 			testExecutor.LocalError = errors.New("exit status 127")
 			testExecutor.LocalOutput = "some output"
-			err := hub.RunInitsystemForNewCluster("filepath")
+			err := hub.RunInitsystemForNewCluster("filepath", layout)
 			Expect(err.Error()).To(Equal("gpinitsystem failed: some output: exit status 127"))
 		})
+		It("adds the standby master to the gpinitsystem command line", func() {
+			source.SetMirrors([]cluster.SegConfig{
+				{ContentID: -1, DbID: 4, Port: 15432, Hostname: "smdw", DataDir: "/standby/seg-1"},
+			})
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = hub.RunInitsystemForNewCluster("filepath", layout)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testExecutor.LocalCommands).To(Equal([]string{
+				"gpinitsystem -a -I filepath -s smdw -P 15433 -S /standby_upgrade/seg-1",
+			}))
+		})
 	})
 	Describe("SaveTargetClusterConfig", func() {
 
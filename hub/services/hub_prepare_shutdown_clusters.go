@@ -10,12 +10,23 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
 )
 
 func (h *Hub) PrepareShutdownClusters(ctx context.Context, in *pb.PrepareShutdownClustersRequest) (*pb.PrepareShutdownClustersReply, error) {
 	gplog.Info("starting PrepareShutdownClusters()")
 
-	go h.ShutdownClusters()
+	if err := h.checkPrerequisites(upgradestatus.SHUTDOWN_CLUSTERS, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
+		return &pb.PrepareShutdownClustersReply{}, err
+	}
+
+	if in.DryRun {
+		plan, err := h.planShutdownClusters()
+		return &pb.PrepareShutdownClustersReply{Plan: plan}, err
+	}
+
+	h.tasks.Go(upgradestatus.SHUTDOWN_CLUSTERS, h.ShutdownClusters)
 
 	return &pb.PrepareShutdownClustersReply{}, nil
 }
@@ -39,7 +50,7 @@ func (h *Hub) ShutdownClusters() {
 	}
 
 	if errSource != nil || errTarget != nil {
-		step.MarkFailed()
+		step.MarkFailed(errors.New("could not stop the clusters"))
 		return
 	}
 
@@ -51,8 +62,7 @@ func StopCluster(c *utils.Cluster) error {
 		return nil
 	}
 
-	masterDataDir := c.MasterDataDir()
-	gpstopShellArgs := fmt.Sprintf("source %[1]s/../greenplum_path.sh; %[1]s/gpstop -a -d %[2]s", c.BinDir, masterDataDir)
+	gpstopShellArgs := stopClusterCommand(c)
 
 	gplog.Info("gpstop args: %+v", gpstopShellArgs)
 	_, err := c.ExecuteLocalCommand(gpstopShellArgs)
@@ -63,6 +73,29 @@ func StopCluster(c *utils.Cluster) error {
 	return nil
 }
 
+// planShutdownClusters lists the gpstop commands for both clusters. Each is
+// only run if that cluster is up at the time.
+func (h *Hub) planShutdownClusters() (*pb.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+	if err := h.requireTarget(); err != nil {
+		return nil, err
+	}
+
+	return &pb.DryRunPlan{
+		Commands: []*pb.PlannedCommand{
+			localCommand(h.source, stopClusterCommand(h.source)),
+			localCommand(h.target, stopClusterCommand(h.target)),
+		},
+		Notes: []string{"each cluster is only stopped if it is running"},
+	}, nil
+}
+
+func stopClusterCommand(c *utils.Cluster) string {
+	return fmt.Sprintf("source %[1]s/../greenplum_path.sh; %[1]s/gpstop -a -d %[2]s", c.BinDir, c.MasterDataDir())
+}
+
 func IsPostmasterRunning(c *utils.Cluster) bool {
 	masterDataDir := c.MasterDataDir()
 	checkPidCmd := fmt.Sprintf("pgrep -F %s/postmaster.pid", masterDataDir)
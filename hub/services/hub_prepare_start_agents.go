@@ -10,6 +10,8 @@ import (
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/certs"
+	"github.com/pkg/errors"
 )
 
 // grpc generated function signature requires ctx and in params.
@@ -17,6 +19,16 @@ import (
 func (h *Hub) PrepareStartAgents(ctx context.Context, in *idl.PrepareStartAgentsRequest) (*idl.PrepareStartAgentsReply, error) {
 	gplog.Info("Running PrepareStartAgents()")
 
+	if err := h.checkPrerequisites(upgradestatus.START_AGENTS, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
+		return &idl.PrepareStartAgentsReply{}, err
+	}
+
+	if in.DryRun {
+		plan, err := h.planStartAgents()
+		return &idl.PrepareStartAgentsReply{Plan: plan}, err
+	}
+
 	step := h.checklist.GetStepWriter(upgradestatus.START_AGENTS)
 
 	err := step.ResetStateDir()
@@ -31,18 +43,31 @@ func (h *Hub) PrepareStartAgents(ctx context.Context, in *idl.PrepareStartAgents
 		return &idl.PrepareStartAgentsReply{}, err
 	}
 
-	go StartAgents(h.source, step)
+	h.tasks.Go(upgradestatus.START_AGENTS, func() {
+		StartAgents(h.source, h.conf, step)
+	})
 
 	return &idl.PrepareStartAgentsReply{}, nil
 }
 
-func StartAgents(source *utils.Cluster, step upgradestatus.StateWriter) {
+func StartAgents(source *utils.Cluster, conf *HubConfig, step upgradestatus.StateWriter) {
 	var err error
 
+	if conf.TLS {
+		err = distributeAgentCerts(source, conf.StateDir)
+		if err != nil {
+			gplog.Error(err.Error())
+			failErr := step.MarkFailed(err)
+			if failErr != nil {
+				gplog.Error(failErr.Error())
+			}
+			return
+		}
+	}
+
 	// TODO: if this finds nothing, should we err out? do a fallback check based on $GPHOME?
 	logStr := "start agents on master and hosts"
-	agentPath := filepath.Join(source.BinDir, "gpupgrade_agent")
-	runAgentCmd := func(contentID int) string { return agentPath + " --daemonize" }
+	runAgentCmd := func(contentID int) string { return startAgentCommand(source, conf) }
 	remoteOutput := source.GenerateAndExecuteCommand(logStr, runAgentCmd, cluster.ON_HOSTS_AND_MASTER)
 
 	errStr := "Failed to start all gpupgrade_agents"
@@ -52,11 +77,11 @@ func StartAgents(source *utils.Cluster, step upgradestatus.StateWriter) {
 	source.CheckClusterError(remoteOutput, errStr, errMessage, true)
 
 	if remoteOutput.NumErrors > 0 {
-		err = step.MarkFailed()
+		err = step.MarkFailed(errors.New(errStr))
 		if err != nil {
 			gplog.Error(err.Error())
-			return
 		}
+		return
 	}
 
 	err = step.MarkComplete()
@@ -65,3 +90,65 @@ func StartAgents(source *utils.Cluster, step upgradestatus.StateWriter) {
 		return
 	}
 }
+
+func (h *Hub) planStartAgents() (*idl.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+
+	plan := &idl.DryRunPlan{}
+	if h.conf.TLS {
+		for _, host := range sortedHostnames(h.source) {
+			plan.Commands = append(plan.Commands, localCommand(h.source, copyAgentCertsCommand(h.conf.StateDir, host)))
+		}
+	}
+	plan.Commands = append(plan.Commands, onEveryHost(h.source, startAgentCommand(h.source, h.conf))...)
+	return plan, nil
+}
+
+// startAgentCommand passes the port and TLS setting along, rather than leaving
+// each agent to find them in its own settings, so that the agents listen
+// where, and how, the hub will dial them.
+func startAgentCommand(source *utils.Cluster, conf *HubConfig) string {
+	command := fmt.Sprintf("%s --daemonize --port=%d", agentPath(source), conf.HubToAgentPort)
+	if conf.TLS {
+		command += " --tls"
+	}
+	return command
+}
+
+// distributeAgentCerts issues each host a certificate from the authority that
+// the hub created when it started, and copies it into the state directory on
+// that host, where the agent will look for it.
+func distributeAgentCerts(source *utils.Cluster, stateDir string) error {
+	authority, err := certs.LoadAuthority(certs.Dir(stateDir))
+	if err != nil {
+		return err
+	}
+
+	for _, host := range sortedHostnames(source) {
+		err = authority.WriteAgentCerts(agentCertsDir(stateDir, host), host)
+		if err != nil {
+			return err
+		}
+
+		output, err := source.Executor.ExecuteLocalCommand(copyAgentCertsCommand(stateDir, host))
+		if err != nil {
+			return errors.Wrapf(err, "could not copy certificates to %s: %s", host, output)
+		}
+	}
+
+	return nil
+}
+
+// agentCertsDir is where the hub keeps the certificates it has issued to a
+// host.
+func agentCertsDir(stateDir, host string) string {
+	return filepath.Join(certs.Dir(stateDir), "hosts", host)
+}
+
+func copyAgentCertsCommand(stateDir, host string) string {
+	dir := certs.Dir(stateDir)
+	return fmt.Sprintf("ssh -o BatchMode=yes %s 'mkdir -p -m 0700 %s' && rsync -rpt %s/ %s:%s/",
+		host, dir, agentCertsDir(stateDir, host), host, dir)
+}
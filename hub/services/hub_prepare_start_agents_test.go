@@ -2,12 +2,16 @@ package services_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/greenplum-db/gp-common-go-libs/cluster"
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 
 	"github.com/greenplum-db/gpupgrade/hub/services"
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/certs"
 
 	"github.com/greenplum-db/gpupgrade/testutils"
 	. "github.com/onsi/ginkgo"
@@ -23,15 +27,72 @@ var _ = Describe("hub PrepareStartAgents", func() {
 
 		step := cm.GetStepWriter(upgradestatus.START_AGENTS)
 		step.MarkInProgress()
-		services.StartAgents(source, step)
+		services.StartAgents(source, &services.HubConfig{HubToAgentPort: 6416}, step)
 
 		Expect(testExecutor.NumExecutions).To(Equal(1))
 		Expect(cm.IsComplete(upgradestatus.START_AGENTS)).To(BeTrue())
 
-		startAgentsCmd := fmt.Sprintf("%s/gpupgrade_agent --daemonize", source.BinDir)
+		startAgentsCmd := fmt.Sprintf("%s/gpupgrade_agent --daemonize --port=6416", source.BinDir)
 		clusterCommands := testExecutor.ClusterCommands[0]
 		for _, command := range clusterCommands {
 			Expect(command).To(ContainElement(startAgentsCmd))
 		}
 	})
+
+	Context("with TLS", func() {
+		var testExecutor *testhelper.TestExecutor
+
+		BeforeEach(func() {
+			testExecutor = &testhelper.TestExecutor{}
+			testExecutor.ClusterOutput = &cluster.RemoteOutput{}
+			source.Executor = testExecutor
+
+			hubConf.TLS = true
+			_, err := certs.EnsureHubCerts(certs.Dir(dir), "localhost")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("issues the agents certificates and copies them over before starting the agents", func() {
+			step := cm.GetStepWriter(upgradestatus.START_AGENTS)
+			step.MarkInProgress()
+			services.StartAgents(source, hubConf, step)
+
+			Expect(cm.IsComplete(upgradestatus.START_AGENTS)).To(BeTrue())
+
+			hostDir := filepath.Join(certs.Dir(dir), "hosts", "localhost")
+			for _, file := range []string{certs.AGENT_CERT, certs.AGENT_KEY, certs.CA_CERT} {
+				_, err := os.Stat(filepath.Join(hostDir, file))
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			Expect(testExecutor.LocalCommands).To(Equal([]string{fmt.Sprintf(
+				"ssh -o BatchMode=yes localhost 'mkdir -p -m 0700 %s' && rsync -rpt %s/ localhost:%s/",
+				certs.Dir(dir), hostDir, certs.Dir(dir))}))
+
+			startAgentsCmd := fmt.Sprintf("%s/gpupgrade_agent --daemonize --port=%d --tls", source.BinDir, hubConf.HubToAgentPort)
+			for _, command := range testExecutor.ClusterCommands[0] {
+				Expect(command).To(ContainElement(startAgentsCmd))
+			}
+		})
+
+		It("fails the step, without starting the agents, if the certificates cannot be copied", func() {
+			testExecutor.LocalError = fmt.Errorf("permission denied")
+
+			step := cm.GetStepWriter(upgradestatus.START_AGENTS)
+			step.MarkInProgress()
+			services.StartAgents(source, hubConf, step)
+
+			Expect(cm.IsFailed(upgradestatus.START_AGENTS)).To(BeTrue())
+			Expect(testExecutor.ClusterCommands).To(BeEmpty())
+		})
+
+		It("plans the copies along with the agents", func() {
+			reply, err := hub.PrepareStartAgents(nil, &pb.PrepareStartAgentsRequest{DryRun: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reply.Plan.Commands).To(HaveLen(2))
+			Expect(reply.Plan.Commands[0].Command).To(HavePrefix("ssh -o BatchMode=yes localhost"))
+			Expect(reply.Plan.Commands[1].Command).To(HaveSuffix("--tls"))
+		})
+	})
 })
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkPrerequisites returns a FailedPrecondition error if any of the steps
+// that the given step depends on has not completed. Setting skip bypasses the
+// check, for operators who have finished those steps by other means.
+func (h *Hub) checkPrerequisites(step string, skip bool) error {
+	var unmet []string
+	for _, prereq := range h.checklist.GetPrerequisites(step) {
+		if prereq.Status() != pb.StepStatus_COMPLETE {
+			unmet = append(unmet, prereq.Name())
+		}
+	}
+
+	if len(unmet) == 0 {
+		return nil
+	}
+
+	if skip {
+		gplog.Warn("starting %s even though its prerequisites have not completed: %s",
+			step, strings.Join(unmet, ", "))
+		return nil
+	}
+
+	return status.Errorf(codes.FailedPrecondition,
+		"cannot start %s until these steps have completed: %s (pass --skip-prerequisites to override)",
+		step, strings.Join(unmet, ", "))
+}
+
+// beginUpgradeCommand claims the checklist for one of the commands that work
+// through it, run, resume or revert, returning a FailedPrecondition error if
+// another of them already has it. The returned func releases it again.
+func (h *Hub) beginUpgradeCommand(command string) (func(), error) {
+	h.upgradeMu.Lock()
+	defer h.upgradeMu.Unlock()
+
+	if h.upgrading != "" {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"cannot %s while %s is in progress", command, h.upgrading)
+	}
+	h.upgrading = command
+
+	return func() {
+		h.upgradeMu.Lock()
+		defer h.upgradeMu.Unlock()
+		h.upgrading = ""
+	}, nil
+}
+
+// runningSteps returns the steps that are RUNNING, whether in this hub or,
+// like pg_upgrade, outside of it.
+func (h *Hub) runningSteps() []string {
+	var running []string
+	for _, step := range h.checklist.AllSteps() {
+		if h.tasks.Running(step.Name()) || step.Status() == pb.StepStatus_RUNNING {
+			running = append(running, step.Name())
+		}
+	}
+	return running
+}
@@ -0,0 +1,62 @@
+package services_test
+
+import (
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("step prerequisites", func() {
+	BeforeEach(func() {
+		cm.AddStep(upgradestatus.CONVERT_MASTER, pb.UpgradeSteps_CONVERT_MASTER)
+		cm.AddStep(upgradestatus.SHARE_OIDS, pb.UpgradeSteps_SHARE_OIDS,
+			upgradestatus.CONVERT_MASTER)
+		cm.AddStep(upgradestatus.CONVERT_PRIMARIES, pb.UpgradeSteps_CONVERT_PRIMARIES,
+			upgradestatus.CONVERT_MASTER, upgradestatus.SHARE_OIDS)
+	})
+
+	It("refuses to start a step whose prerequisites are not complete", func() {
+		step := cm.GetStepWriter(upgradestatus.CONVERT_MASTER)
+		step.MarkInProgress()
+		step.MarkComplete()
+
+		_, err := hub.UpgradeConvertPrimaries(nil, &pb.UpgradeConvertPrimariesRequest{})
+		Expect(err).To(HaveOccurred())
+
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(err.Error()).To(ContainSubstring(upgradestatus.SHARE_OIDS))
+		Expect(err.Error()).ToNot(ContainSubstring(upgradestatus.CONVERT_MASTER + ","))
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
+	})
+
+	It("starts a step whose prerequisites are complete", func() {
+		for _, name := range []string{upgradestatus.CONVERT_MASTER, upgradestatus.SHARE_OIDS} {
+			step := cm.GetStepWriter(name)
+			step.MarkInProgress()
+			step.MarkComplete()
+		}
+
+		_, err := hub.UpgradeConvertPrimaries(nil, &pb.UpgradeConvertPrimariesRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mockAgent.NumberOfCalls()).To(Equal(1))
+	})
+
+	It("starts a step with unmet prerequisites when asked to skip the check", func() {
+		_, err := hub.UpgradeConvertPrimaries(nil, &pb.UpgradeConvertPrimariesRequest{
+			SkipPrerequisites: true,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mockAgent.NumberOfCalls()).To(Equal(1))
+	})
+
+	It("applies to steps that run in the background", func() {
+		_, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(cm.IsPending(upgradestatus.SHARE_OIDS)).To(BeTrue())
+	})
+})
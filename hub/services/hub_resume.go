@@ -0,0 +1,204 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A StepValidator double-checks that the work of a step which is marked
+// COMPLETE is still in place, returning an error if it is not.
+type StepValidator func() error
+
+func (h *Hub) Resume(ctx context.Context, in *pb.ResumeRequest) (*pb.ResumeReply, error) {
+	gplog.Info("starting Resume()")
+
+	if !in.DryRun {
+		done, err := h.beginUpgradeCommand("resume")
+		if err != nil {
+			return &pb.ResumeReply{}, err
+		}
+		defer done()
+
+		// The first step that isn't COMPLETE may be one that is still
+		// running, and resuming there would start a second copy of it.
+		if running := h.runningSteps(); len(running) > 0 {
+			return &pb.ResumeReply{}, status.Errorf(codes.FailedPrecondition,
+				"cannot resume the upgrade while these steps are running: %s; wait for them to finish",
+				strings.Join(running, ", "))
+		}
+	}
+
+	steps := h.checklist.AllSteps()
+	start := FindResumePoint(steps, h.StepValidators())
+	if start == len(steps) {
+		gplog.Info("all upgrade steps have already completed; nothing to resume")
+		return &pb.ResumeReply{}, nil
+	}
+
+	resumed := steps[start]
+	if in.DryRun {
+		return &pb.ResumeReply{
+			ResumedStep: resumed.Code(),
+			Plans:       PlanSteps(steps[start:], h.stepPlanners()),
+		}, nil
+	}
+
+	gplog.Info("resuming upgrade at step %s", resumed.Name())
+
+	failed, err := RunSteps(ctx, steps[start:], h.stepStarters())
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.ResumeReply{}, err
+	}
+
+	reply := &pb.ResumeReply{ResumedStep: resumed.Code()}
+	if failed != nil {
+		gplog.Error("upgrade stopped: step %s failed", failed.Name())
+		reply.FailedStep = failed.Code()
+		return reply, nil
+	}
+
+	gplog.Info("all upgrade steps have completed")
+	return reply, nil
+}
+
+// FindResumePoint returns the index of the first step that needs to be run
+// again: either the first step that isn't COMPLETE, or the first COMPLETE step
+// whose validator reports that its work has gone missing. If every step is
+// complete and valid, len(steps) is returned. The caller must make sure that
+// no step is RUNNING, since that step would be returned too.
+func FindResumePoint(steps []upgradestatus.StateReader, validators map[string]StepValidator) int {
+	for i, step := range steps {
+		if step.Status() != pb.StepStatus_COMPLETE {
+			return i
+		}
+
+		validate, ok := validators[step.Name()]
+		if !ok {
+			continue
+		}
+
+		err := validate()
+		if err != nil {
+			gplog.Warn("step %s is marked complete but must be rerun: %s", step.Name(), err.Error())
+			return i
+		}
+	}
+
+	return len(steps)
+}
+
+// StepValidators maps checklist steps that leave something behind, on the
+// hub's host or on the agents', to a check that it is still there.
+func (h *Hub) StepValidators() map[string]StepValidator {
+	return map[string]StepValidator{
+		upgradestatus.CONFIG: func() error {
+			_, err := utils.System.Stat(h.source.ConfigPath)
+			return errors.Wrap(err, "source cluster configuration is missing")
+		},
+		upgradestatus.START_AGENTS: func() error {
+			_, err := h.AgentConns()
+			return errors.Wrap(err, "agents are not running")
+		},
+		upgradestatus.INIT_CLUSTER: func() error {
+			err := h.validateTargetMasterDataDir()
+			if err != nil {
+				return err
+			}
+			return h.validateTargetSegmentDataDirs()
+		},
+		upgradestatus.CONVERT_MASTER: h.validateTargetMasterDataDir,
+		upgradestatus.SHARE_OIDS:     h.validateSharedOidFiles,
+	}
+}
+
+func (h *Hub) validateTargetMasterDataDir() error {
+	if h.target.Cluster == nil {
+		return errors.New("target cluster configuration is missing")
+	}
+
+	_, err := utils.System.Stat(h.target.MasterDataDir())
+	return errors.Wrap(err, "target master data directory is missing")
+}
+
+func (h *Hub) validateTargetSegmentDataDirs() error {
+	requests := make(map[string]*pb.CheckPathsRequest)
+	for _, content := range h.target.ContentIDs {
+		if content == -1 {
+			continue
+		}
+		segment := h.target.Segments[content]
+		request, ok := requests[segment.Hostname]
+		if !ok {
+			request = &pb.CheckPathsRequest{}
+			requests[segment.Hostname] = request
+		}
+		request.Datadirs = append(request.Datadirs, segment.DataDir)
+	}
+
+	return errors.Wrap(h.checkAgentPaths(requests), "target segment data directories are missing")
+}
+
+// validateSharedOidFiles makes sure that every agent still has the OID files
+// that share-oids gave it.
+func (h *Hub) validateSharedOidFiles() error {
+	files, err := h.oidFiles()
+	if err != nil {
+		return err
+	}
+
+	request := &pb.CheckPathsRequest{}
+	for _, file := range files {
+		request.StateFiles = append(request.StateFiles, file.Destination)
+	}
+
+	requests := make(map[string]*pb.CheckPathsRequest)
+	for _, host := range h.source.GetHostnames() {
+		requests[host] = request
+	}
+
+	return errors.Wrap(h.checkAgentPaths(requests), "shared OID files are missing")
+}
+
+// checkAgentPaths asks the agent on each host whether the paths in its request
+// are there, and returns an error naming every one that isn't.
+func (h *Hub) checkAgentPaths(requests map[string]*pb.CheckPathsRequest) error {
+	var hosts []string
+	for host := range requests {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	fanOut, conns := h.agentFanOut(hosts...)
+	results := fanOut.Call(Clients(conns), func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
+		return client.Client.CheckPaths(ctx, requests[client.Hostname])
+	})
+	err := results.Err()
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, host := range hosts {
+		reply := results[host].Reply.(*pb.CheckPathsReply)
+		for _, path := range reply.Missing {
+			missing = append(missing, fmt.Sprintf("%s on %s", path, host))
+		}
+	}
+
+	if len(missing) > 0 {
+		return errors.New(strings.Join(missing, ", "))
+	}
+	return nil
+}
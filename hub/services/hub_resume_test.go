@@ -0,0 +1,147 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resume", func() {
+	complete := func(name string) {
+		step := cm.GetStepWriter(name)
+		step.MarkInProgress()
+		step.MarkComplete()
+	}
+
+	BeforeEach(func() {
+		cm.AddStep(upgradestatus.CONFIG, pb.UpgradeSteps_CONFIG)
+		cm.AddStep(upgradestatus.SEGINSTALL, pb.UpgradeSteps_SEGINSTALL)
+		cm.AddStep(upgradestatus.INIT_CLUSTER, pb.UpgradeSteps_INIT_CLUSTER)
+	})
+
+	Describe("FindResumePoint", func() {
+		It("returns the first step that is not complete", func() {
+			complete(upgradestatus.CONFIG)
+
+			start := services.FindResumePoint(cm.AllSteps(), nil)
+			Expect(start).To(Equal(1))
+		})
+
+		It("returns a failed step so that it will be retried", func() {
+			complete(upgradestatus.CONFIG)
+			cm.GetStepWriter(upgradestatus.SEGINSTALL).MarkInProgress()
+			cm.GetStepWriter(upgradestatus.SEGINSTALL).MarkFailed(errors.New("it broke"))
+
+			start := services.FindResumePoint(cm.AllSteps(), nil)
+			Expect(start).To(Equal(1))
+		})
+
+		It("returns the number of steps if every step is complete", func() {
+			complete(upgradestatus.CONFIG)
+			complete(upgradestatus.SEGINSTALL)
+			complete(upgradestatus.INIT_CLUSTER)
+
+			start := services.FindResumePoint(cm.AllSteps(), nil)
+			Expect(start).To(Equal(3))
+		})
+
+		It("returns a complete step whose work is no longer valid", func() {
+			complete(upgradestatus.CONFIG)
+			complete(upgradestatus.SEGINSTALL)
+			complete(upgradestatus.INIT_CLUSTER)
+
+			validators := map[string]services.StepValidator{
+				upgradestatus.CONFIG:     func() error { return nil },
+				upgradestatus.SEGINSTALL: func() error { return errors.New("gone") },
+			}
+
+			start := services.FindResumePoint(cm.AllSteps(), validators)
+			Expect(start).To(Equal(1))
+		})
+	})
+
+	Describe("Hub.Resume", func() {
+		It("does nothing if every step is complete and still valid", func() {
+			complete(upgradestatus.CONFIG)
+			complete(upgradestatus.SEGINSTALL)
+			complete(upgradestatus.INIT_CLUSTER)
+
+			utils.System.Stat = func(name string) (os.FileInfo, error) {
+				return nil, nil
+			}
+
+			reply, err := hub.Resume(context.Background(), &pb.ResumeRequest{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reply.GetResumedStep()).To(Equal(pb.UpgradeSteps_UNKNOWN_STEP))
+			Expect(reply.GetFailedStep()).To(Equal(pb.UpgradeSteps_UNKNOWN_STEP))
+		})
+
+		It("refuses to resume while a step is still running", func() {
+			complete(upgradestatus.CONFIG)
+			cm.GetStepWriter(upgradestatus.SEGINSTALL).MarkInProgress()
+
+			_, err := hub.Resume(context.Background(), &pb.ResumeRequest{})
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+			Expect(err).To(MatchError(ContainSubstring("these steps are running: " + upgradestatus.SEGINSTALL)))
+
+			Expect(cm.IsInProgress(upgradestatus.SEGINSTALL)).To(BeTrue())
+		})
+
+		It("reruns init-cluster if the target master data directory is missing", func() {
+			complete(upgradestatus.CONFIG)
+			complete(upgradestatus.SEGINSTALL)
+			complete(upgradestatus.INIT_CLUSTER)
+
+			utils.System.Stat = func(name string) (os.FileInfo, error) {
+				if name == target.MasterDataDir() {
+					return nil, os.ErrNotExist
+				}
+				return nil, nil
+			}
+
+			start := services.FindResumePoint(cm.AllSteps(), hub.StepValidators())
+			Expect(start).To(Equal(2))
+		})
+
+		It("reruns init-cluster if an agent is missing a target segment data directory", func() {
+			complete(upgradestatus.CONFIG)
+			complete(upgradestatus.SEGINSTALL)
+			complete(upgradestatus.INIT_CLUSTER)
+
+			utils.System.Stat = func(name string) (os.FileInfo, error) {
+				return nil, nil
+			}
+			mockAgent.CheckPathsResponse = &pb.CheckPathsReply{Missing: []string{target.Segments[1].DataDir}}
+
+			start := services.FindResumePoint(cm.AllSteps(), hub.StepValidators())
+			Expect(start).To(Equal(2))
+			Expect(mockAgent.CheckPathsRequest.Datadirs).To(ConsistOf(
+				target.Segments[0].DataDir,
+				target.Segments[1].DataDir,
+			))
+		})
+
+		It("finds that share-oids must be rerun if an agent is missing the OID files", func() {
+			oidDir := filepath.Join(dir, "pg_upgrade")
+			Expect(os.MkdirAll(oidDir, 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(oidDir, "pg_upgrade_dump_1_oids.sql"), nil, 0600)).To(Succeed())
+			mockAgent.CheckPathsResponse = &pb.CheckPathsReply{Missing: []string{"pg_upgrade/pg_upgrade_dump_1_oids.sql"}}
+
+			err := hub.StepValidators()[upgradestatus.SHARE_OIDS]()
+			Expect(err).To(MatchError(ContainSubstring("pg_upgrade/pg_upgrade_dump_1_oids.sql on localhost")))
+			Expect(mockAgent.CheckPathsRequest.StateFiles).To(Equal([]string{"pg_upgrade/pg_upgrade_dump_1_oids.sql"}))
+		})
+	})
+})
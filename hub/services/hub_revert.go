@@ -0,0 +1,311 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus/file"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *Hub) Revert(ctx context.Context, in *pb.RevertRequest) (*pb.RevertReply, error) {
+	gplog.Info("starting Revert()")
+
+	if in.DryRun {
+		plan, err := h.planRevert()
+		return &pb.RevertReply{Plan: plan}, err
+	}
+
+	done, err := h.beginUpgradeCommand("revert")
+	if err != nil {
+		return &pb.RevertReply{}, err
+	}
+	defer done()
+
+	err = h.RevertUpgrade()
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.RevertReply{}, err
+	}
+
+	gplog.Info("revert succeeded; the source cluster is running")
+	return &pb.RevertReply{}, nil
+}
+
+// RevertUpgrade throws away the target cluster and puts the source cluster
+// back into service, so that the upgrade can be started over from scratch.
+func (h *Hub) RevertUpgrade() error {
+	if err := h.checkRevertable(); err != nil {
+		return err
+	}
+
+	if h.target.Cluster != nil {
+		err := StopCluster(h.target)
+		if err != nil {
+			return errors.Wrap(err, "Could not stop target cluster")
+		}
+
+		// Put the target's original port back before deleting anything, so
+		// that a target left behind by a failed deletion can't be started
+		// on the source cluster's port.
+		err = RestorePostgresqlConf(h.target)
+		if err != nil {
+			return errors.Wrap(err, "Could not restore target postgresql.conf")
+		}
+	}
+
+	layout, err := h.createdLayout()
+	if err != nil {
+		return errors.Wrap(err, "Could not plan the target cluster")
+	}
+	fanOut, agentConns := h.agentFanOut(layout.Hostnames()...)
+	err = h.DeleteAllDataDirectories(fanOut, agentConns, layout)
+	if err != nil {
+		return err
+	}
+
+	err = StartCluster(h.source)
+	if err != nil {
+		return errors.Wrap(err, "Could not start source cluster")
+	}
+
+	err = h.ResetChecklist()
+	if err != nil {
+		return errors.Wrap(err, "Could not reset upgrade state")
+	}
+
+	// The next upgrade is timed as a new run.
+	err = h.runs.Begin(h.segmentCount())
+	if err != nil {
+		return errors.Wrap(err, "Could not begin a new run of the upgrade")
+	}
+
+	return nil
+}
+
+// planRevert lists what RevertUpgrade would do, in the same order.
+func (h *Hub) planRevert() (*pb.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+	if err := h.checkRevertable(); err != nil {
+		return nil, err
+	}
+
+	plan := &pb.DryRunPlan{}
+	if h.target.Cluster != nil {
+		plan.Commands = append(plan.Commands, localCommand(h.source, stopClusterCommand(h.target)))
+		plan.Notes = append(plan.Notes, "the target cluster is only stopped if it is running")
+
+		if _, err := utils.System.Stat(filepath.Join(h.target.MasterDataDir(), "postgresql.conf.bak")); err == nil {
+			plan.Commands = append(plan.Commands, localCommand(h.source, restorePostgresqlConfCommand(h.target)))
+		}
+	}
+
+	layout, err := h.createdLayout()
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not plan the target cluster")
+	}
+	plan.Commands = append(plan.Commands, localCommand(h.source, "rm -rf "+layout.MasterParentDir()))
+	plan.Notes = append(plan.Notes, "target directories are only removed if they were created by gpupgrade")
+
+	segmentDataDirMap := layout.SegmentParentDirs()
+	for _, host := range sortedHostnames(h.source) {
+		for _, dataDir := range segmentDataDirMap[host] {
+			plan.Commands = append(plan.Commands, &pb.PlannedCommand{Hostname: host, Command: "rm -rf " + dataDir})
+		}
+	}
+
+	plan.Commands = append(plan.Commands, localCommand(h.source, startClusterCommand(h.source)))
+	plan.Notes = append(plan.Notes, "the source cluster is only started if it is not already running")
+
+	plan.Notes = append(plan.Notes, "return every step to PENDING in "+filepath.Join(h.conf.StateDir, file.Journal)+
+		", keeping the history of its earlier attempts")
+
+	return plan, nil
+}
+
+// checkRevertable refuses to revert while any step is running, since it may
+// still be writing to the target cluster, and refuses to revert a link-mode
+// upgrade once pg_upgrade has started on the cluster: from then on the source
+// cluster's data files are shared with the target cluster, and starting the
+// source could corrupt both.
+func (h *Hub) checkRevertable() error {
+	if running := h.runningSteps(); len(running) > 0 {
+		return status.Errorf(codes.FailedPrecondition,
+			"cannot revert while these steps are running: %s; stop pg_upgrade with `gpupgrade cancel <step>`, "+
+				"or wait for the other steps to finish", strings.Join(running, ", "))
+	}
+
+	if h.linkModeStarted() || h.target.LinkMode() && h.conversionStarted() {
+		return errors.New("cannot revert a link-mode upgrade once pg_upgrade has started, " +
+			"because the source cluster shares its data files with the target cluster; " +
+			"restore the source cluster from a backup instead")
+	}
+	return nil
+}
+
+// conversionStarted is true once pg_upgrade has been started on the master or
+// on any primary.
+func (h *Hub) conversionStarted() bool {
+	if h.linkModeStarted() {
+		return true
+	}
+	for _, step := range h.checklist.AllSteps() {
+		if step.Name() != upgradestatus.CONVERT_MASTER && step.Name() != upgradestatus.CONVERT_PRIMARIES {
+			continue
+		}
+		if step.Status() != pb.StepStatus_PENDING {
+			return true
+		}
+	}
+	return false
+}
+
+// linkModeStartedFile is left in the state directory when pg_upgrade is
+// first started in link mode, and never removed. The status of the conversion
+// steps can't stand in for it: they are reset when a step is retried, and read
+// as PENDING when their agents can't be reached.
+const linkModeStartedFile = "link-mode-started"
+
+func (h *Hub) linkModeStartedPath() string {
+	return filepath.Join(h.conf.StateDir, linkModeStartedFile)
+}
+
+// recordLinkModeStarted is called before pg_upgrade is started on any segment.
+// It does nothing in copy mode.
+func (h *Hub) recordLinkModeStarted() error {
+	if !h.target.LinkMode() {
+		return nil
+	}
+
+	err := utils.System.WriteFile(h.linkModeStartedPath(), []byte(time.Now().Format(time.RFC3339)+"\n"), 0644)
+	if err != nil {
+		return errors.Wrap(err, "could not record that a link-mode upgrade has started")
+	}
+	return nil
+}
+
+// linkModeStarted is true once pg_upgrade has been started in link mode. It
+// errs on the side of true if the state directory can't be read.
+func (h *Hub) linkModeStarted() bool {
+	_, err := utils.System.Stat(h.linkModeStartedPath())
+	return !os.IsNotExist(err)
+}
+
+// createdLayout is where init-cluster created the target cluster's
+// directories. Once it has saved the target cluster, that is the record of
+// them; before then, it can only have created those of the planned layout,
+// which can't be changed once init-cluster has started.
+func (h *Hub) createdLayout() (*TargetLayout, error) {
+	if h.target.Cluster != nil {
+		return ClusterLayout(h.target), nil
+	}
+	return h.targetLayout()
+}
+
+// RestorePostgresqlConf undoes reconfigure-ports by moving the saved
+// postgresql.conf.bak back into place. It does nothing if reconfigure-ports
+// never ran.
+func RestorePostgresqlConf(c *utils.Cluster) error {
+	masterDataDir := c.MasterDataDir()
+
+	_, err := utils.System.Stat(filepath.Join(masterDataDir, "postgresql.conf.bak"))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	output, err := c.ExecuteLocalCommand(restorePostgresqlConfCommand(c))
+	if err != nil {
+		return errors.Wrapf(err, "%s", output)
+	}
+
+	return nil
+}
+
+func restorePostgresqlConfCommand(c *utils.Cluster) string {
+	return fmt.Sprintf("mv %[1]s/postgresql.conf.bak %[1]s/postgresql.conf", c.MasterDataDir())
+}
+
+// DeleteAllDataDirectories removes the target data directories that
+// CreateAllDataDirectories created, on the master and on every segment host.
+func (h *Hub) DeleteAllDataDirectories(fanOut FanOut, agentConns []*Connection, layout *TargetLayout) error {
+	targetDataDir := layout.MasterParentDir()
+	_, err := utils.System.Stat(targetDataDir)
+	if err == nil {
+		if !utils.IsTargetDir(targetDataDir) {
+			return errors.Errorf("refusing to delete directory %s: it was not created by gpupgrade", targetDataDir)
+		}
+		err = utils.System.RemoveAll(targetDataDir)
+		if err != nil {
+			return errors.Wrapf(err, "Could not delete directory %s", targetDataDir)
+		}
+	} else if !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Could not delete directory %s", targetDataDir)
+	}
+
+	err = DeleteSegmentDataDirectories(fanOut, agentConns, layout.SegmentParentDirs())
+	if err != nil {
+		return errors.Wrap(err, "Could not delete segment data directories")
+	}
+	return nil
+}
+
+func DeleteSegmentDataDirectories(fanOut FanOut, agentConns []*Connection, dataDirMap map[string][]string) error {
+	results := fanOut.Call(Clients(agentConns), func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
+		return client.Client.DeleteSegmentDataDirectories(ctx, &pb.DeleteSegmentDataDirRequest{
+			Datadirs: dataDirMap[client.Hostname],
+		})
+	})
+
+	err := results.Err()
+	if err != nil {
+		gplog.Error("Error deleting segment data directories: %s", err.Error())
+		return errors.Wrap(err, "Error deleting segment data directories")
+	}
+	return nil
+}
+
+// ResetChecklist returns every step to PENDING, keeping its history.
+func (h *Hub) ResetChecklist() error {
+	for _, step := range h.checklist.AllSteps() {
+		err := h.checklist.ResetStep(step.Name())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func StartCluster(c *utils.Cluster) error {
+	if IsPostmasterRunning(c) {
+		return nil
+	}
+
+	gpstartShellArgs := startClusterCommand(c)
+
+	gplog.Info("gpstart args: %+v", gpstartShellArgs)
+	_, err := c.ExecuteLocalCommand(gpstartShellArgs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func startClusterCommand(c *utils.Cluster) string {
+	return fmt.Sprintf("source %[1]s/../greenplum_path.sh; %[1]s/gpstart -a -d %[2]s", c.BinDir, c.MasterDataDir())
+}
@@ -0,0 +1,201 @@
+package services_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Revert", func() {
+	var (
+		sourceExecutor *testhelper.TestExecutor
+		targetExecutor *testhelper.TestExecutor
+		removed        []string
+	)
+
+	BeforeEach(func() {
+		// The source cluster is down (pgrep fails) and the target is up.
+		sourceExecutor = &testhelper.TestExecutor{
+			LocalError:     errors.New("not running"),
+			ErrorOnExecNum: 1,
+		}
+		source.Executor = sourceExecutor
+		// init-cluster created the target cluster in the default layout.
+		target.Cluster = testutils.CreateMultinodeSampleCluster(dir + "_upgrade")
+		targetExecutor = &testhelper.TestExecutor{}
+		target.Executor = targetExecutor
+
+		removed = nil
+		utils.System.RemoveAll = func(name string) error {
+			removed = append(removed, name)
+			return nil
+		}
+		// The target master's directory was created by init-cluster.
+		utils.System.Stat = func(name string) (os.FileInfo, error) {
+			switch name {
+			case dir + "_upgrade", filepath.Join(dir+"_upgrade", utils.TARGET_DIR_MARKER):
+				return nil, nil
+			}
+			return nil, os.ErrNotExist
+		}
+
+		cm.AddStep(upgradestatus.CONFIG, pb.UpgradeSteps_CONFIG)
+		cm.AddStep(upgradestatus.INIT_CLUSTER, pb.UpgradeSteps_INIT_CLUSTER)
+	})
+
+	Context("in link mode", func() {
+		BeforeEach(func() {
+			target.Mode = utils.LINK_MODE
+			cm.AddStep(upgradestatus.CONVERT_MASTER, pb.UpgradeSteps_CONVERT_MASTER)
+			cm.AddStep(upgradestatus.CONVERT_PRIMARIES, pb.UpgradeSteps_CONVERT_PRIMARIES)
+		})
+
+		It("reverts if the cluster has not been converted yet", func() {
+			_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("refuses to revert once the cluster has started converting", func() {
+			cm.GetStepWriter(upgradestatus.CONVERT_PRIMARIES).MarkInProgress()
+			cm.GetStepWriter(upgradestatus.CONVERT_PRIMARIES).MarkFailed(errors.New("cancelled"))
+
+			_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+			Expect(err).To(MatchError(ContainSubstring("cannot revert a link-mode upgrade once pg_upgrade has started")))
+			Expect(targetExecutor.LocalCommands).To(BeEmpty())
+			Expect(removed).To(BeEmpty())
+
+			_, err = hub.Revert(context.Background(), &pb.RevertRequest{DryRun: true})
+			Expect(err).To(MatchError(ContainSubstring("cannot revert a link-mode upgrade")))
+		})
+
+		It("refuses to revert once pg_upgrade has started, even if the steps no longer say so", func() {
+			stat := utils.System.Stat
+			utils.System.Stat = func(name string) (os.FileInfo, error) {
+				if name == filepath.Join(dir, "link-mode-started") {
+					return nil, nil
+				}
+				return stat(name)
+			}
+
+			_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+			Expect(err).To(MatchError(ContainSubstring("cannot revert a link-mode upgrade once pg_upgrade has started")))
+			Expect(removed).To(BeEmpty())
+		})
+	})
+
+	It("stops the target, deletes its data directories, and starts the source", func() {
+		_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(targetExecutor.LocalCommands).To(HaveLen(2))
+		Expect(targetExecutor.LocalCommands[1]).To(ContainSubstring("gpstop"))
+
+		Expect(mockAgent.DeleteSegmentDataDirRequest.Datadirs).To(ConsistOf(dir + "_upgrade"))
+
+		Expect(sourceExecutor.LocalCommands).To(HaveLen(2))
+		Expect(sourceExecutor.LocalCommands[1]).To(ContainSubstring("gpstart"))
+
+		Expect(removed).To(Equal([]string{dir + "_upgrade"}))
+		Expect(cm.IsPending(upgradestatus.INIT_CLUSTER)).To(BeTrue())
+	})
+
+	It("deletes the directories of the target cluster that init-cluster created, whatever the layout settings are now", func() {
+		target.Layout.DataDirTemplate = "{parent}_v6/{base}"
+		target.SetMirrors([]cluster.SegConfig{
+			{ContentID: 0, DbID: 4, Port: 27432, Hostname: "localhost", DataDir: dir + "_mirror_upgrade/seg1"},
+		})
+
+		_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(removed).To(Equal([]string{dir + "_upgrade"}))
+		Expect(mockAgent.DeleteSegmentDataDirRequest.Datadirs).To(ConsistOf(dir+"_upgrade", dir+"_mirror_upgrade"))
+	})
+
+	It("deletes the directories of the planned layout if init-cluster did not save the target cluster", func() {
+		target.Cluster = nil
+
+		_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(removed).To(Equal([]string{dir + "_upgrade"}))
+		Expect(mockAgent.DeleteSegmentDataDirRequest.Datadirs).To(ConsistOf(dir + "_upgrade"))
+	})
+
+	It("refuses to revert while a step is running", func() {
+		cm.GetStepWriter(upgradestatus.INIT_CLUSTER).MarkInProgress()
+
+		_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(err).To(MatchError(ContainSubstring("gpupgrade cancel")))
+
+		Expect(targetExecutor.NumExecutions).To(Equal(0))
+		Expect(removed).To(BeEmpty())
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
+	})
+
+	It("refuses to delete a target master directory that gpupgrade did not create", func() {
+		utils.System.Stat = func(name string) (os.FileInfo, error) {
+			if name == dir+"_upgrade" {
+				return nil, nil
+			}
+			return nil, os.ErrNotExist
+		}
+
+		_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+		Expect(err).To(MatchError(ContainSubstring("refusing to delete directory " + dir + "_upgrade")))
+		Expect(removed).To(BeEmpty())
+	})
+
+	It("restores the target postgresql.conf if reconfigure-ports ran", func() {
+		utils.System.Stat = func(name string) (os.FileInfo, error) {
+			if filepath.Base(name) == "postgresql.conf.bak" {
+				return nil, nil
+			}
+			return os.Stat(name)
+		}
+
+		_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(targetExecutor.LocalCommands).To(HaveLen(3))
+		Expect(targetExecutor.LocalCommands[2]).To(Equal(
+			"mv " + target.MasterDataDir() + "/postgresql.conf.bak " + target.MasterDataDir() + "/postgresql.conf",
+		))
+	})
+
+	It("does not delete anything if the target cluster cannot be stopped", func() {
+		targetExecutor.LocalError = errors.New("gpstop failed")
+		targetExecutor.ErrorOnExecNum = 2
+
+		_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+		Expect(err).To(HaveOccurred())
+
+		Expect(removed).To(BeEmpty())
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
+		Expect(sourceExecutor.NumExecutions).To(Equal(0))
+	})
+
+	It("does not start the source cluster if an agent fails to delete directories", func() {
+		mockAgent.Err <- errors.New("permission denied")
+
+		_, err := hub.Revert(context.Background(), &pb.RevertRequest{})
+		Expect(err).To(HaveOccurred())
+
+		Expect(sourceExecutor.NumExecutions).To(Equal(0))
+	})
+})
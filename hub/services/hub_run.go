@@ -0,0 +1,193 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RunPollInterval is how long Run waits between status checks of a step that
+// has been started but has not yet finished.
+var RunPollInterval = 1 * time.Second
+
+// RunStartTimeout is how long a step that has been started may still be
+// PENDING before Run gives up on it. A step that never leaves PENDING was not
+// really started, or its status can't be read.
+var RunStartTimeout = 5 * time.Minute
+
+// A StepStarter kicks off a single checklist step. Steps that do their work
+// asynchronously return as soon as that work has been started; RunSteps then
+// polls the step's status until it finishes.
+type StepStarter func(ctx context.Context) error
+
+func (h *Hub) Run(ctx context.Context, in *pb.RunRequest) (*pb.RunReply, error) {
+	gplog.Info("starting Run()")
+
+	if in.DryRun {
+		return &pb.RunReply{Plans: PlanSteps(h.checklist.AllSteps(), h.stepPlanners())}, nil
+	}
+
+	done, err := h.beginUpgradeCommand("run")
+	if err != nil {
+		return &pb.RunReply{}, err
+	}
+	defer done()
+
+	// Run starts every step over, which would start a second copy of any
+	// that is still running.
+	if running := h.runningSteps(); len(running) > 0 {
+		return &pb.RunReply{}, status.Errorf(codes.FailedPrecondition,
+			"cannot run the upgrade while these steps are running: %s", strings.Join(running, ", "))
+	}
+
+	failed, err := RunSteps(ctx, h.checklist.AllSteps(), h.stepStarters())
+	if err != nil {
+		gplog.Error(err.Error())
+		return &pb.RunReply{}, err
+	}
+
+	if failed != nil {
+		gplog.Error("upgrade stopped: step %s failed", failed.Name())
+		return &pb.RunReply{FailedStep: failed.Code()}, nil
+	}
+
+	gplog.Info("all upgrade steps have completed")
+	return &pb.RunReply{}, nil
+}
+
+// RunSteps starts each of the given steps in order, waiting for every step to
+// reach COMPLETE before moving on to the next one. It stops at the first step
+// that cannot be started or that ends up FAILED, and returns that step. A nil
+// step and error mean that every step completed.
+func RunSteps(ctx context.Context, steps []upgradestatus.StateReader, starters map[string]StepStarter) (upgradestatus.StateReader, error) {
+	for _, step := range steps {
+		start, ok := starters[step.Name()]
+		if !ok {
+			return nil, errors.Errorf("don't know how to run step %s", step.Name())
+		}
+
+		gplog.Info("running step %s", step.Name())
+		err := start(ctx)
+		if err != nil {
+			gplog.Error("could not start step %s: %s", step.Name(), err.Error())
+			return step, nil
+		}
+
+		status, err := waitForStep(ctx, step)
+		if err != nil {
+			return nil, errors.Wrapf(err, "stopped waiting for step %s", step.Name())
+		}
+
+		if status == pb.StepStatus_FAILED {
+			return step, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// waitForStep polls the step's status until it is either COMPLETE or FAILED,
+// until it has been PENDING for longer than RunStartTimeout, or until the
+// context is done.
+func waitForStep(ctx context.Context, step upgradestatus.StateReader) (pb.StepStatus, error) {
+	pendingSince := time.Now()
+	for {
+		status := step.Status()
+		switch status {
+		case pb.StepStatus_COMPLETE, pb.StepStatus_FAILED:
+			return status, nil
+		case pb.StepStatus_PENDING:
+			if time.Since(pendingSince) > RunStartTimeout {
+				return status, errors.Errorf("step is still PENDING %s after it was started", RunStartTimeout)
+			}
+		default:
+			pendingSince = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(RunPollInterval):
+		}
+	}
+}
+
+// stepStarters maps each checklist step to the Hub call that kicks it off.
+func (h *Hub) stepStarters() map[string]StepStarter {
+	starters := map[string]StepStarter{
+		upgradestatus.CONFIG: func(ctx context.Context) error {
+			_, err := h.CheckConfig(ctx, &pb.CheckConfigRequest{})
+			return err
+		},
+		upgradestatus.SEGINSTALL: func(ctx context.Context) error {
+			_, err := h.CheckSeginstall(ctx, &pb.CheckSeginstallRequest{})
+			return err
+		},
+		upgradestatus.START_AGENTS: func(ctx context.Context) error {
+			_, err := h.PrepareStartAgents(ctx, &pb.PrepareStartAgentsRequest{})
+			return err
+		},
+		upgradestatus.INIT_CLUSTER: func(ctx context.Context) error {
+			_, err := h.PrepareInitCluster(ctx, &pb.PrepareInitClusterRequest{})
+			return err
+		},
+		upgradestatus.SHUTDOWN_CLUSTERS: func(ctx context.Context) error {
+			_, err := h.PrepareShutdownClusters(ctx, &pb.PrepareShutdownClustersRequest{})
+			return err
+		},
+		upgradestatus.CONVERT_MASTER: func(ctx context.Context) error {
+			_, err := h.UpgradeConvertMaster(ctx, &pb.UpgradeConvertMasterRequest{})
+			return err
+		},
+		upgradestatus.SHARE_OIDS: func(ctx context.Context) error {
+			_, err := h.UpgradeShareOids(ctx, &pb.UpgradeShareOidsRequest{})
+			return err
+		},
+		upgradestatus.CONVERT_PRIMARIES: func(ctx context.Context) error {
+			_, err := h.UpgradeConvertPrimaries(ctx, &pb.UpgradeConvertPrimariesRequest{})
+			return err
+		},
+		upgradestatus.REBUILD_MIRRORS: func(ctx context.Context) error {
+			_, err := h.UpgradeRebuildMirrors(ctx, &pb.UpgradeRebuildMirrorsRequest{})
+			return err
+		},
+		upgradestatus.REBUILD_STANDBY: func(ctx context.Context) error {
+			_, err := h.UpgradeRebuildStandby(ctx, &pb.UpgradeRebuildStandbyRequest{})
+			return err
+		},
+		upgradestatus.VALIDATE_START_CLUSTER: func(ctx context.Context) error {
+			_, err := h.UpgradeValidateStartCluster(ctx, &pb.UpgradeValidateStartClusterRequest{})
+			return err
+		},
+		upgradestatus.RECONFIGURE_PORTS: func(ctx context.Context) error {
+			_, err := h.UpgradeReconfigurePorts(ctx, &pb.UpgradeReconfigurePortsRequest{})
+			return err
+		},
+	}
+
+	// Most steps reset their state from a goroutine. Reset each one here
+	// first, so that the result of an earlier attempt can't be mistaken for
+	// the result of this one.
+	for name, start := range starters {
+		starters[name] = h.resetBefore(name, start)
+	}
+	return starters
+}
+
+func (h *Hub) resetBefore(name string, start StepStarter) StepStarter {
+	return func(ctx context.Context) error {
+		err := h.checklist.ResetStep(name)
+		if err != nil {
+			return errors.Wrapf(err, "could not reset step %s", name)
+		}
+		return start(ctx)
+	}
+}
@@ -0,0 +1,192 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Run", func() {
+	var (
+		started  []string
+		starters map[string]services.StepStarter
+	)
+
+	// completes returns a StepStarter that marks the given step as finished,
+	// either successfully or not, from a separate goroutine.
+	completes := func(name string, succeed bool) services.StepStarter {
+		return func(ctx context.Context) error {
+			started = append(started, name)
+
+			step := cm.GetStepWriter(name)
+			step.MarkInProgress()
+			go func() {
+				if succeed {
+					step.MarkComplete()
+				} else {
+					step.MarkFailed(errors.New("it broke"))
+				}
+			}()
+			return nil
+		}
+	}
+
+	BeforeEach(func() {
+		services.RunPollInterval = time.Millisecond
+
+		started = nil
+		cm.AddStep(upgradestatus.CONFIG, pb.UpgradeSteps_CONFIG)
+		cm.AddStep(upgradestatus.SEGINSTALL, pb.UpgradeSteps_SEGINSTALL)
+		cm.AddStep(upgradestatus.INIT_CLUSTER, pb.UpgradeSteps_INIT_CLUSTER)
+
+		starters = map[string]services.StepStarter{
+			upgradestatus.CONFIG:       completes(upgradestatus.CONFIG, true),
+			upgradestatus.SEGINSTALL:   completes(upgradestatus.SEGINSTALL, true),
+			upgradestatus.INIT_CLUSTER: completes(upgradestatus.INIT_CLUSTER, true),
+		}
+	})
+
+	AfterEach(func() {
+		services.RunPollInterval = 1 * time.Second
+	})
+
+	It("runs every step in order when they all complete", func() {
+		failed, err := services.RunSteps(context.Background(), cm.AllSteps(), starters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failed).To(BeNil())
+
+		Expect(started).To(Equal([]string{
+			upgradestatus.CONFIG,
+			upgradestatus.SEGINSTALL,
+			upgradestatus.INIT_CLUSTER,
+		}))
+		Expect(cm.IsComplete(upgradestatus.INIT_CLUSTER)).To(BeTrue())
+	})
+
+	It("stops at the first step that fails and reports it", func() {
+		starters[upgradestatus.SEGINSTALL] = completes(upgradestatus.SEGINSTALL, false)
+
+		failed, err := services.RunSteps(context.Background(), cm.AllSteps(), starters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failed.Name()).To(Equal(upgradestatus.SEGINSTALL))
+		Expect(failed.Code()).To(Equal(pb.UpgradeSteps_SEGINSTALL))
+
+		Expect(started).To(Equal([]string{upgradestatus.CONFIG, upgradestatus.SEGINSTALL}))
+		Expect(cm.IsPending(upgradestatus.INIT_CLUSTER)).To(BeTrue())
+	})
+
+	It("reports a step that cannot be started as failed", func() {
+		starters[upgradestatus.CONFIG] = func(ctx context.Context) error {
+			return errors.New("could not start")
+		}
+
+		failed, err := services.RunSteps(context.Background(), cm.AllSteps(), starters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failed.Name()).To(Equal(upgradestatus.CONFIG))
+		Expect(started).To(BeEmpty())
+	})
+
+	It("waits for a running step to finish before starting the next", func() {
+		finish := make(chan struct{})
+		starters[upgradestatus.CONFIG] = func(ctx context.Context) error {
+			started = append(started, upgradestatus.CONFIG)
+
+			step := cm.GetStepWriter(upgradestatus.CONFIG)
+			step.MarkInProgress()
+			go func() {
+				<-finish
+				step.MarkComplete()
+			}()
+			return nil
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+
+			failed, err := services.RunSteps(context.Background(), cm.AllSteps(), starters)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(failed).To(BeNil())
+		}()
+
+		Consistently(done).ShouldNot(BeClosed())
+		close(finish)
+		Eventually(done).Should(BeClosed())
+
+		Expect(started).To(HaveLen(3))
+	})
+
+	It("returns an error if the context is cancelled while waiting", func() {
+		starters[upgradestatus.CONFIG] = func(ctx context.Context) error {
+			cm.GetStepWriter(upgradestatus.CONFIG).MarkInProgress()
+			return nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		failed, err := services.RunSteps(ctx, cm.AllSteps(), starters)
+		Expect(err).To(HaveOccurred())
+		Expect(failed).To(BeNil())
+	})
+
+	It("returns an error if a step is still pending long after it was started", func() {
+		services.RunStartTimeout = 10 * time.Millisecond
+		defer func() { services.RunStartTimeout = 5 * time.Minute }()
+
+		starters[upgradestatus.CONFIG] = func(ctx context.Context) error {
+			return nil
+		}
+
+		failed, err := services.RunSteps(context.Background(), cm.AllSteps(), starters)
+		Expect(err).To(MatchError(ContainSubstring("step is still PENDING")))
+		Expect(failed).To(BeNil())
+	})
+
+	It("keeps waiting for a step that has left pending", func() {
+		services.RunStartTimeout = 10 * time.Millisecond
+		defer func() { services.RunStartTimeout = 5 * time.Minute }()
+
+		starters[upgradestatus.CONFIG] = func(ctx context.Context) error {
+			step := cm.GetStepWriter(upgradestatus.CONFIG)
+			step.MarkInProgress()
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				step.MarkComplete()
+			}()
+			return nil
+		}
+
+		failed, err := services.RunSteps(context.Background(), cm.AllSteps(), starters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(failed).To(BeNil())
+	})
+
+	It("refuses to start over while a step is still running", func() {
+		cm.GetStepWriter(upgradestatus.SEGINSTALL).MarkInProgress()
+
+		_, err := hub.Run(context.Background(), &pb.RunRequest{})
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(err).To(MatchError(ContainSubstring("these steps are running: " + upgradestatus.SEGINSTALL)))
+
+		Expect(cm.IsPending(upgradestatus.CONFIG)).To(BeTrue())
+		Expect(cm.IsInProgress(upgradestatus.SEGINSTALL)).To(BeTrue())
+	})
+
+	It("returns an error for a step it does not know how to run", func() {
+		delete(starters, upgradestatus.SEGINSTALL)
+
+		_, err := services.RunSteps(context.Background(), cm.AllSteps(), starters)
+		Expect(err).To(MatchError(ContainSubstring(upgradestatus.SEGINSTALL)))
+	})
+})
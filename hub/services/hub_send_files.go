@@ -0,0 +1,98 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// FileChunkSize is how much of a file SendFiles puts in each message.
+const FileChunkSize = 64 * 1024
+
+// A FileToSend is copied by SendFiles from Source, on the hub's host, to
+// Destination, relative to the agent's state directory.
+type FileToSend struct {
+	Source      string
+	Destination string
+}
+
+// SendFiles streams the files to an agent, which checks each one against its
+// checksum before moving it into place.
+func SendFiles(ctx context.Context, client pb.AgentClient, files []FileToSend) error {
+	stream, err := client.ReceiveFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		err = sendFile(stream, file)
+		if err == io.EOF {
+			// The agent gave up on the stream; its reason comes back from
+			// CloseAndRecv.
+			break
+		}
+		if err != nil {
+			stream.CloseSend()
+			return err
+		}
+	}
+
+	_, err = stream.CloseAndRecv()
+	return err
+}
+
+func sendFile(stream pb.Agent_ReceiveFilesClient, file FileToSend) error {
+	f, err := os.Open(file.Source)
+	if err != nil {
+		return errors.Wrapf(err, "could not open %s", file.Source)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "could not stat %s", file.Source)
+	}
+
+	// The checksum goes out ahead of the contents, so read the file twice.
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not read %s", file.Source)
+	}
+
+	err = stream.Send(&pb.ReceiveFilesRequest{
+		Path:   file.Destination,
+		Mode:   uint32(info.Mode().Perm()),
+		Size:   uint64(info.Size()),
+		Sha256: hex.EncodeToString(hash.Sum(nil)),
+	})
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, FileChunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			sendErr := stream.Send(&pb.ReceiveFilesRequest{Data: buf[:n]})
+			if sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not read %s", file.Source)
+		}
+	}
+}
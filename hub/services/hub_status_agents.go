@@ -0,0 +1,37 @@
+package services
+
+import (
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"golang.org/x/net/context"
+)
+
+// StatusAgents reports on the hub's connection to the agent on every host of
+// the source cluster. It tries to connect to any agent it isn't connected to
+// first, so that the report is current; a host that cannot be reached is
+// reported, not returned as an error.
+func (h *Hub) StatusAgents(ctx context.Context, in *pb.StatusAgentsRequest) (*pb.StatusAgentsReply, error) {
+	if err := h.requireSource(); err != nil {
+		return &pb.StatusAgentsReply{}, err
+	}
+
+	h.agents.Reachable(sortedHostnames(h.source)...)
+
+	reply := &pb.StatusAgentsReply{}
+	for _, health := range h.AgentHealth() {
+		agent := &pb.AgentStatus{
+			Hostname: health.Hostname,
+			Healthy:  health.Healthy(),
+			State:    health.State.String(),
+			Failures: int32(health.Failures),
+		}
+		if health.LastError != nil {
+			agent.LastError = health.LastError.Error()
+		}
+		if !health.RetryAt.IsZero() {
+			agent.RetryAt = health.RetryAt.Unix()
+		}
+		reply.Agents = append(reply.Agents, agent)
+	}
+	return reply, nil
+}
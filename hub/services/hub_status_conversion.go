@@ -2,42 +2,46 @@ package services
 
 import (
 	"fmt"
-	"strings"
+	"sort"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
 	"github.com/greenplum-db/gp-common-go-libs/cluster"
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
 
 func (h *Hub) StatusConversion(ctx context.Context, in *pb.StatusConversionRequest) (*pb.StatusConversionReply, error) {
-	agentConnections, err := h.AgentConns()
-	if err != nil {
-		return &pb.StatusConversionReply{}, err
-	}
 	segments := h.segmentsByHost()
+	var hosts []string
+	for host := range segments {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
 
-	statuses, err := GetConversionStatusFromPrimaries(agentConnections, segments)
+	fanOut, agentConnections := h.agentFanOut(hosts...)
+	statuses, err := GetConversionStatusFromPrimaries(fanOut, agentConnections, segments)
 	if err != nil {
 		err := fmt.Errorf("Could not get conversion status from primaries. Err: \"%v\"", err)
 		gplog.Error(err.Error())
 		return &pb.StatusConversionReply{}, err
 	}
+	h.recordConversions(statuses)
 
 	return &pb.StatusConversionReply{
 		ConversionStatuses: statuses,
 	}, nil
 }
 
-// Helper function to make grpc calls to all agents on primaries for their status
-// TODO: Check conversion statuses in parallel
-func GetConversionStatusFromPrimaries(conns []*Connection, segments map[string][]cluster.SegConfig) ([]string, error) {
-	var statuses []string
-	for _, conn := range conns {
+// GetConversionStatusFromPrimaries asks the agent on every host for the
+// conversion status of the segments there, and returns all of them in the
+// order of conns.
+func GetConversionStatusFromPrimaries(fanOut FanOut, conns []*Connection, segments map[string][]cluster.SegConfig) ([]*pb.ConversionStatus, error) {
+	results := fanOut.Call(Clients(conns), func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
 		// Build a list of segments on the host in which the agent resides on.
 		var agentSegments []*pb.SegmentInfo
-		for _, segment := range segments[conn.Hostname] {
+		for _, segment := range segments[client.Hostname] {
 			agentSegments = append(agentSegments, &pb.SegmentInfo{
 				Content: int32(segment.ContentID),
 				Dbid:    int32(segment.DbID),
@@ -45,15 +49,19 @@ func GetConversionStatusFromPrimaries(conns []*Connection, segments map[string][
 			})
 		}
 
-		status, err := conn.AgentClient.CheckConversionStatus(context.Background(), &pb.CheckConversionStatusRequest{
+		return client.Client.CheckConversionStatus(ctx, &pb.CheckConversionStatusRequest{
 			Segments: agentSegments,
-			Hostname: conn.Hostname,
+			Hostname: client.Hostname,
 		})
-		if err != nil {
-			return nil, fmt.Errorf("agent on host %s returned an error when checking conversion status: %s", conn.Hostname, err)
-		}
+	})
+	if err := results.Err(); err != nil {
+		return nil, errors.Wrap(err, "agents returned errors when checking conversion status")
+	}
 
-		statuses = append(statuses, status.GetStatuses()...)
+	var statuses []*pb.ConversionStatus
+	for _, conn := range conns {
+		reply := results[conn.Hostname].Reply.(*pb.CheckConversionStatusReply)
+		statuses = append(statuses, reply.GetStatuses()...)
 	}
 
 	return statuses, nil
@@ -69,13 +77,20 @@ func PrimaryConversionStatus(hub *Hub) pb.StepStatus {
 		return pb.StepStatus_PENDING
 	}
 
-	statuses := strings.Join(conversionStatus.GetConversionStatuses(), "\n")
+	seen := make(map[pb.StepStatus]bool)
+	for _, status := range conversionStatus.GetConversionStatuses() {
+		if status.GetRole() == pb.SegmentRole_MASTER {
+			continue
+		}
+		seen[status.GetStatus()] = true
+	}
+
 	switch {
-	case strings.Contains(statuses, "FAILED"):
+	case seen[pb.StepStatus_FAILED]:
 		return pb.StepStatus_FAILED
-	case strings.Contains(statuses, "RUNNING"):
+	case seen[pb.StepStatus_RUNNING]:
 		return pb.StepStatus_RUNNING
-	case strings.Contains(statuses, "COMPLETE"):
+	case seen[pb.StepStatus_COMPLETE]:
 		return pb.StepStatus_COMPLETE
 	default:
 		return pb.StepStatus_PENDING
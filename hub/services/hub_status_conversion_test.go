@@ -33,7 +33,10 @@ var _ = Describe("hub", func() {
 
 	Describe("GetConversionStatusFromPrimaries", func() {
 		It("receives conversion statuses from the agent and returns all as single message", func() {
-			statusMessages := []string{"status", "status"}
+			statusMessages := []*pb.ConversionStatus{
+				{Dbid: 2, Content: 0, Status: pb.StepStatus_RUNNING},
+				{Dbid: 3, Content: 1, Status: pb.StepStatus_COMPLETE},
+			}
 			segment1 := hostToSegmentsMap["host1"][0]
 			var agentSegments []*pb.SegmentInfo
 			agentSegments = append(
@@ -56,13 +59,13 @@ var _ = Describe("hub", func() {
 				nil,
 			).Times(1)
 
-			statuses, err := services.GetConversionStatusFromPrimaries(agentConnections, hostToSegmentsMap)
+			statuses, err := services.GetConversionStatusFromPrimaries(services.FanOut{}, agentConnections, hostToSegmentsMap)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(statuses).To(Equal([]string{"status", "status"}))
+			Expect(statuses).To(Equal(statusMessages))
 		})
 
 		It("returns an error when Agent server returns an error", func() {
-			statusMessages := []string{"status", "status"}
+			statusMessages := []*pb.ConversionStatus{{Status: pb.StepStatus_RUNNING}}
 			client.EXPECT().CheckConversionStatus(
 				gomock.Any(), // Context
 				gomock.Any(), // &pb.CheckConversionStatusRequest
@@ -71,7 +74,7 @@ var _ = Describe("hub", func() {
 				errors.New("agent err"),
 			)
 
-			_, err := services.GetConversionStatusFromPrimaries(agentConnections, hostToSegmentsMap)
+			_, err := services.GetConversionStatusFromPrimaries(services.FanOut{}, agentConnections, hostToSegmentsMap)
 			Expect(err).To(HaveOccurred())
 		})
 	})
@@ -79,7 +82,10 @@ var _ = Describe("hub", func() {
 	Describe("PrimaryConversionStatus", func() {
 		It("returns FAILED if any agents report failure", func() {
 			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-				Statuses: []string{"FAILED", "RUNNING"},
+				Statuses: []*pb.ConversionStatus{
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_FAILED},
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_RUNNING},
+				},
 			}
 
 			status := services.PrimaryConversionStatus(hub)
@@ -88,7 +94,10 @@ var _ = Describe("hub", func() {
 
 		It("returns RUNNING if any agents report progress", func() {
 			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-				Statuses: []string{"COMPLETE", "RUNNING"},
+				Statuses: []*pb.ConversionStatus{
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_COMPLETE},
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_RUNNING},
+				},
 			}
 
 			status := services.PrimaryConversionStatus(hub)
@@ -97,7 +106,10 @@ var _ = Describe("hub", func() {
 
 		It("returns COMPLETE if all agents report completion", func() {
 			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-				Statuses: []string{"COMPLETE", "COMPLETE"},
+				Statuses: []*pb.ConversionStatus{
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_COMPLETE},
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_COMPLETE},
+				},
 			}
 
 			status := services.PrimaryConversionStatus(hub)
@@ -106,7 +118,22 @@ var _ = Describe("hub", func() {
 
 		It("returns PENDING if no agents report any other state", func() {
 			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-				Statuses: []string{"PENDING", "PENDING"},
+				Statuses: []*pb.ConversionStatus{
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_PENDING},
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_PENDING},
+				},
+			}
+
+			status := services.PrimaryConversionStatus(hub)
+			Expect(status).To(Equal(pb.StepStatus_PENDING))
+		})
+
+		It("ignores the status of the master", func() {
+			mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+				Statuses: []*pb.ConversionStatus{
+					{Role: pb.SegmentRole_MASTER, Status: pb.StepStatus_COMPLETE},
+					{Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_PENDING},
+				},
 			}
 
 			status := services.PrimaryConversionStatus(hub)
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
@@ -15,10 +16,31 @@ func (h *Hub) StatusUpgrade(ctx context.Context, in *pb.StatusUpgradeRequest) (*
 
 	for i, step := range steps {
 		gplog.Info("Checking %s...", step.Name())
-		statuses[i] = &pb.UpgradeStepStatus{Step: step.Code(), Status: step.Status()}
+		statuses[i] = newUpgradeStepStatus(step, step.Status())
 	}
 
-	return &pb.StatusUpgradeReply{
+	reply := &pb.StatusUpgradeReply{
 		ListOfUpgradeStepStatuses: statuses,
-	}, nil
+	}
+	h.timeSteps(steps, reply)
+	return reply, nil
+}
+
+// newUpgradeStepStatus reports the step's status along with how it went on
+// each host, how it got there and why it failed, for the steps that keep track
+// of those.
+func newUpgradeStepStatus(step upgradestatus.StateReader, status pb.StepStatus) *pb.UpgradeStepStatus {
+	stepStatus := &pb.UpgradeStepStatus{Step: step.Code(), Status: status}
+	if hosts, ok := step.(upgradestatus.HostReader); ok {
+		stepStatus.Hosts = hosts.HostStatuses()
+	}
+	if history, ok := step.(upgradestatus.HistoryReader); ok {
+		stepStatus.History = history.History()
+	}
+	// A read-only step's status comes from the clusters, so a failure recorded
+	// for it only applies while the step is still failed.
+	if failure, ok := step.(upgradestatus.FailureReader); ok && status == pb.StepStatus_FAILED {
+		stepStatus.Failure = failure.Failure()
+	}
+	return stepStatus
 }
@@ -60,22 +60,45 @@ var _ = Describe("status upgrade", func() {
 
 		step := cm.GetStepWriter(upgradestatus.SHARE_OIDS)
 		step.MarkInProgress()
-		step.MarkFailed()
+		step.MarkHostComplete("sdw1")
+		step.MarkHostFailed("sdw2", "disk full")
+		step.MarkFailed(errors.New("it broke"))
+
+		failed := cm.GetStepWriter(upgradestatus.VALIDATE_START_CLUSTER)
+		failed.MarkInProgress()
+		failed.MarkFailed(&upgradestatus.CommandError{
+			Command:  "gpstart -a",
+			ExitCode: 2,
+			LogFile:  "/home/gpadmin/gpAdminLogs/gpstart_20180301.log",
+			Err:      errors.New("exit status 2"),
+		})
 
 		resp, err := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
 		Expect(err).To(BeNil())
 
+		// Timing has tests of its own.
+		for _, status := range resp.ListOfUpgradeStepStatuses {
+			Expect(status.Timing).ToNot(BeNil())
+			status.Timing = nil
+		}
+
+		completed := []*pb.StepTransition{
+			{Status: pb.StepStatus_RUNNING},
+			{Status: pb.StepStatus_COMPLETE},
+		}
 		Expect(resp.ListOfUpgradeStepStatuses).To(ConsistOf(
 			[]*pb.UpgradeStepStatus{
 				{
-					Step:   pb.UpgradeSteps_CONFIG,
-					Status: pb.StepStatus_COMPLETE,
+					Step:    pb.UpgradeSteps_CONFIG,
+					Status:  pb.StepStatus_COMPLETE,
+					History: completed,
 				}, {
 					Step:   pb.UpgradeSteps_INIT_CLUSTER,
 					Status: pb.StepStatus_PENDING,
 				}, {
-					Step:   pb.UpgradeSteps_SEGINSTALL,
-					Status: pb.StepStatus_COMPLETE,
+					Step:    pb.UpgradeSteps_SEGINSTALL,
+					Status:  pb.StepStatus_COMPLETE,
+					History: completed,
 				}, {
 					Step:   pb.UpgradeSteps_SHUTDOWN_CLUSTERS,
 					Status: pb.StepStatus_PENDING,
@@ -83,14 +106,36 @@ var _ = Describe("status upgrade", func() {
 					Step:   pb.UpgradeSteps_CONVERT_MASTER,
 					Status: pb.StepStatus_PENDING,
 				}, {
-					Step:   pb.UpgradeSteps_START_AGENTS,
-					Status: pb.StepStatus_COMPLETE,
+					Step:    pb.UpgradeSteps_START_AGENTS,
+					Status:  pb.StepStatus_COMPLETE,
+					History: completed,
 				}, {
 					Step:   pb.UpgradeSteps_SHARE_OIDS,
 					Status: pb.StepStatus_FAILED,
+					Hosts: []*pb.HostStepStatus{
+						{Hostname: "sdw1", Status: pb.StepStatus_COMPLETE},
+						{Hostname: "sdw2", Status: pb.StepStatus_FAILED, Error: "disk full"},
+					},
+					History: []*pb.StepTransition{
+						{Status: pb.StepStatus_RUNNING},
+						{Status: pb.StepStatus_COMPLETE, Hostname: "sdw1"},
+						{Status: pb.StepStatus_FAILED, Hostname: "sdw2", Error: "disk full"},
+						{Status: pb.StepStatus_FAILED, Error: "it broke"},
+					},
+					Failure: &pb.StepFailure{Error: "it broke"},
 				}, {
 					Step:   pb.UpgradeSteps_VALIDATE_START_CLUSTER,
-					Status: pb.StepStatus_PENDING,
+					Status: pb.StepStatus_FAILED,
+					History: []*pb.StepTransition{
+						{Status: pb.StepStatus_RUNNING},
+						{Status: pb.StepStatus_FAILED, Error: "exit status 2"},
+					},
+					Failure: &pb.StepFailure{
+						Error:    "exit status 2",
+						Command:  "gpstart -a",
+						ExitCode: 2,
+						LogFile:  "/home/gpadmin/gpAdminLogs/gpstart_20180301.log",
+					},
 				}, {
 					Step:   pb.UpgradeSteps_CONVERT_PRIMARIES,
 					Status: pb.StepStatus_PENDING,
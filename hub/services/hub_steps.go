@@ -0,0 +1,49 @@
+package services
+
+import (
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+)
+
+// AddSteps sets up the checklist steps in order. `gpupgrade run` executes them
+// in exactly this order, so e.g. the agents must be started before
+// init-cluster asks them to create data directories. The trailing arguments
+// name the steps that must be complete before a step may be started by hand.
+//
+// The hub records the status of most steps itself. The conversion steps are
+// read-only: their status comes from the state that pg_upgrade leaves behind
+// on the master and on each primary.
+func (h *Hub) AddSteps(cm *upgradestatus.ChecklistManager) {
+	cm.AddWritableStep(upgradestatus.CONFIG, pb.UpgradeSteps_CONFIG)
+	cm.AddWritableStep(upgradestatus.SEGINSTALL, pb.UpgradeSteps_SEGINSTALL,
+		upgradestatus.CONFIG)
+	cm.AddWritableStep(upgradestatus.START_AGENTS, pb.UpgradeSteps_START_AGENTS,
+		upgradestatus.SEGINSTALL)
+	cm.AddWritableStep(upgradestatus.INIT_CLUSTER, pb.UpgradeSteps_INIT_CLUSTER,
+		upgradestatus.CONFIG, upgradestatus.START_AGENTS)
+	cm.AddWritableStep(upgradestatus.SHUTDOWN_CLUSTERS, pb.UpgradeSteps_SHUTDOWN_CLUSTERS,
+		upgradestatus.INIT_CLUSTER)
+
+	cm.AddReadOnlyStep(upgradestatus.CONVERT_MASTER, pb.UpgradeSteps_CONVERT_MASTER,
+		func(string) pb.StepStatus {
+			return MasterConversionStatus(h)
+		}, upgradestatus.SHUTDOWN_CLUSTERS)
+
+	cm.AddWritableStep(upgradestatus.SHARE_OIDS, pb.UpgradeSteps_SHARE_OIDS,
+		upgradestatus.CONVERT_MASTER)
+
+	cm.AddReadOnlyStep(upgradestatus.CONVERT_PRIMARIES, pb.UpgradeSteps_CONVERT_PRIMARIES,
+		func(string) pb.StepStatus {
+			return PrimaryConversionStatus(h)
+		}, upgradestatus.SHARE_OIDS)
+
+	cm.AddWritableStep(upgradestatus.REBUILD_MIRRORS, pb.UpgradeSteps_REBUILD_MIRRORS,
+		upgradestatus.CONVERT_PRIMARIES)
+	cm.AddWritableStep(upgradestatus.REBUILD_STANDBY, pb.UpgradeSteps_REBUILD_STANDBY,
+		upgradestatus.CONVERT_MASTER)
+
+	cm.AddWritableStep(upgradestatus.VALIDATE_START_CLUSTER, pb.UpgradeSteps_VALIDATE_START_CLUSTER,
+		upgradestatus.CONVERT_PRIMARIES, upgradestatus.REBUILD_MIRRORS, upgradestatus.REBUILD_STANDBY)
+	cm.AddWritableStep(upgradestatus.RECONFIGURE_PORTS, pb.UpgradeSteps_RECONFIGURE_PORTS,
+		upgradestatus.VALIDATE_START_CLUSTER)
+}
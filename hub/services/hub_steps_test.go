@@ -0,0 +1,154 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("the hub's steps", func() {
+	var (
+		checklist    *upgradestatus.ChecklistManager
+		realHub      *services.Hub
+		testExecutor *testhelper.TestExecutor
+	)
+
+	BeforeEach(func() {
+		testExecutor = &testhelper.TestExecutor{}
+		source.Executor = testExecutor
+		target.Executor = testExecutor
+
+		checklist = upgradestatus.NewChecklistManager(dir)
+		realHub = services.NewHub(source, target, grpc.DialContext, hubConf, checklist)
+		realHub.AddSteps(checklist)
+	})
+
+	status := func(name string) pb.StepStatus {
+		return checklist.GetStepReader(name).Status()
+	}
+
+	Describe("run", func() {
+		BeforeEach(func() {
+			services.RunPollInterval = 0
+
+			// What resume checks is left behind by the completed steps.
+			Expect(source.Commit()).To(Succeed())
+			Expect(os.MkdirAll(target.MasterDataDir(), 0700)).To(Succeed())
+
+			for _, name := range []string{
+				upgradestatus.CONFIG,
+				upgradestatus.SEGINSTALL,
+				upgradestatus.START_AGENTS,
+				upgradestatus.INIT_CLUSTER,
+			} {
+				step := checklist.GetStepWriter(name)
+				Expect(step.MarkInProgress()).To(Succeed())
+				Expect(step.MarkComplete()).To(Succeed())
+			}
+
+			// Stop at convert-master, which would otherwise run pg_upgrade.
+			utils.System.RunCommandAsync = func(cmdStr, logFile string) (int, error) {
+				return 0, errors.New("no pg_upgrade here")
+			}
+		})
+
+		AfterEach(func() {
+			services.RunPollInterval = time.Second
+		})
+
+		It("shuts down the clusters and goes on to convert the master", func() {
+			reply, err := realHub.Resume(context.Background(), &pb.ResumeRequest{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reply.ResumedStep).To(Equal(pb.UpgradeSteps_SHUTDOWN_CLUSTERS))
+			Expect(reply.FailedStep).To(Equal(pb.UpgradeSteps_CONVERT_MASTER))
+			Expect(status(upgradestatus.SHUTDOWN_CLUSTERS)).To(Equal(pb.StepStatus_COMPLETE))
+			Expect(testExecutor.LocalCommands).To(ContainElement(ContainSubstring("gpstop -a")))
+		})
+
+		It("does not mistake the result of an earlier attempt for this one", func() {
+			step := checklist.GetStepWriter(upgradestatus.SHUTDOWN_CLUSTERS)
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(step.MarkFailed(errors.New("gpstop failed"))).To(Succeed())
+
+			reply, err := realHub.Resume(context.Background(), &pb.ResumeRequest{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reply.FailedStep).To(Equal(pb.UpgradeSteps_CONVERT_MASTER))
+			Expect(status(upgradestatus.SHUTDOWN_CLUSTERS)).To(Equal(pb.StepStatus_COMPLETE))
+		})
+	})
+
+	Describe("convert-master", func() {
+		var workingDir string
+
+		BeforeEach(func() {
+			workingDir = filepath.Join(dir, "pg_upgrade")
+			Expect(os.MkdirAll(workingDir, 0700)).To(Succeed())
+		})
+
+		It("is pending until pg_upgrade has been started", func() {
+			Expect(os.RemoveAll(workingDir)).To(Succeed())
+
+			Expect(status(upgradestatus.CONVERT_MASTER)).To(Equal(pb.StepStatus_PENDING))
+		})
+
+		It("is running while pg_upgrade works in the hub's working directory", func() {
+			Expect(ioutil.WriteFile(filepath.Join(workingDir, "1.inprogress"), nil, 0600)).To(Succeed())
+			testExecutor.LocalOutput = "4242"
+
+			Expect(status(upgradestatus.CONVERT_MASTER)).To(Equal(pb.StepStatus_RUNNING))
+			Expect(testExecutor.LocalCommands).To(Equal([]string{
+				"pgrep -f '[p]g_upgrade.*" + source.MasterDataDir() + "'",
+			}))
+		})
+
+		It("can be cancelled while it is running", func() {
+			Expect(ioutil.WriteFile(filepath.Join(workingDir, "1.inprogress"), nil, 0600)).To(Succeed())
+			Expect(utils.WritePidFile(filepath.Join(workingDir, utils.PgUpgradePidFile), 4242)).To(Succeed())
+			testExecutor.LocalOutput = "4242"
+
+			exited := false
+			utils.System.Kill = func(p int, sig syscall.Signal) error {
+				if sig != 0 {
+					exited = true
+				}
+				if exited {
+					return syscall.ESRCH
+				}
+				return nil
+			}
+			utils.System.ProcessArgs = func(p int) ([]string, error) {
+				return []string{"bash", "-c", "pg_upgrade"}, nil
+			}
+
+			reply, err := realHub.Cancel(context.Background(), &pb.CancelRequest{Step: pb.UpgradeSteps_CONVERT_MASTER})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reply.Processes).To(HaveLen(1))
+
+			Expect(status(upgradestatus.CONVERT_MASTER)).To(Equal(pb.StepStatus_FAILED))
+		})
+
+		It("is complete once pg_upgrade has said so", func() {
+			Expect(ioutil.WriteFile(filepath.Join(workingDir, "1.done"), []byte("Upgrade complete\n"), 0600)).To(Succeed())
+			testExecutor.LocalError = errors.New("exit status 1")
+
+			Expect(status(upgradestatus.CONVERT_MASTER)).To(Equal(pb.StepStatus_COMPLETE))
+		})
+	})
+})
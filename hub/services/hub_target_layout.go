@@ -0,0 +1,499 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// DefaultDataDirTemplate puts each target data directory into an "_upgrade"
+// sibling of the directory that holds the source one.
+const DefaultDataDirTemplate = "{parent}_upgrade/{base}"
+
+// The default port offsets, used when the operator hasn't given a port range
+// or a port map.
+const (
+	defaultMasterPortOffset  = 1
+	defaultSegmentPortOffset = 2000
+)
+
+var templatePlaceholder = regexp.MustCompile(`{[^}]*}`)
+
+// A TargetLayout is the planned placement, on the hosts of the source cluster,
+// of every segment of the target cluster.
+type TargetLayout struct {
+	Master    cluster.SegConfig
+	Primaries []cluster.SegConfig // in the order of the source's content IDs
+	Standby   *cluster.SegConfig  // nil if the source has no standby
+	Mirrors   []cluster.SegConfig // ordered by content ID
+}
+
+// Segments returns every planned segment: the master, the primaries, the
+// standby and then the mirrors.
+func (l *TargetLayout) Segments() []cluster.SegConfig {
+	segments := append([]cluster.SegConfig{l.Master}, l.Primaries...)
+	if l.Standby != nil {
+		segments = append(segments, *l.Standby)
+	}
+	return append(segments, l.Mirrors...)
+}
+
+// MasterParentDir is the directory that the target master's data directory is
+// created in.
+func (l *TargetLayout) MasterParentDir() string {
+	return path.Dir(l.Master.DataDir)
+}
+
+// SegmentParentDirs maps each host to the directories, other than the
+// master's, that have to exist there before gpinitsystem can create the data
+// directories inside them.
+func (l *TargetLayout) SegmentParentDirs() map[string][]string {
+	parentDirs := map[string][]string{}
+	seen := map[string]bool{}
+	for _, segment := range l.Segments()[1:] {
+		parentDir := path.Dir(segment.DataDir)
+		if seen[segment.Hostname+":"+parentDir] {
+			continue
+		}
+		seen[segment.Hostname+":"+parentDir] = true
+		parentDirs[segment.Hostname] = append(parentDirs[segment.Hostname], parentDir)
+	}
+	return parentDirs
+}
+
+// Hostnames returns every host that the layout places a segment on, sorted.
+func (l *TargetLayout) Hostnames() []string {
+	var hostnames []string
+	seen := map[string]bool{}
+	for _, segment := range l.Segments() {
+		if !seen[segment.Hostname] {
+			seen[segment.Hostname] = true
+			hostnames = append(hostnames, segment.Hostname)
+		}
+	}
+	sort.Strings(hostnames)
+	return hostnames
+}
+
+// StandbyAndMirrors returns the planned standby, if any, and mirrors.
+func (l *TargetLayout) StandbyAndMirrors() []cluster.SegConfig {
+	var segments []cluster.SegConfig
+	if l.Standby != nil {
+		segments = append(segments, *l.Standby)
+	}
+	return append(segments, l.Mirrors...)
+}
+
+// ClusterLayout returns the layout of a target cluster that init-cluster has
+// created, including the standby and mirrors that it has made room for.
+func ClusterLayout(c *utils.Cluster) *TargetLayout {
+	layout := &TargetLayout{Master: c.Segments[-1]}
+	for _, content := range c.ContentIDs {
+		if content != -1 {
+			layout.Primaries = append(layout.Primaries, c.Segments[content])
+		}
+	}
+
+	if standby, ok := c.Standby(); ok {
+		layout.Standby = &standby
+	}
+	for _, mirror := range c.MirrorConfigs() {
+		if mirror.ContentID != -1 {
+			layout.Mirrors = append(layout.Mirrors, mirror)
+		}
+	}
+	return layout
+}
+
+// targetLayout plans the target cluster using the layout settings that the
+// operator has stored with the target cluster.
+func (h *Hub) targetLayout() (*TargetLayout, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+	return PlanTargetLayout(h.source, h.target.Layout)
+}
+
+// PlanTargetLayout chooses a port and a data directory for every segment of
+// the target cluster, which mirrors the source cluster segment for segment.
+//
+// Ports come from the port map file if one is set, then from the port range,
+// which hands out ports on each host in turn while skipping those of the
+// source cluster; otherwise the master and standby get their source port plus
+// one and every other segment its source port plus 2000. Data directories are
+// made by expanding the data directory template for each source segment.
+func PlanTargetLayout(source *utils.Cluster, settings utils.LayoutSettings) (*TargetLayout, error) {
+	template := settings.DataDirTemplate
+	if template == "" {
+		template = DefaultDataDirTemplate
+	}
+
+	ports, err := newPortAllocator(source, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	place := func(segment cluster.SegConfig, defaultOffset int) (cluster.SegConfig, error) {
+		target := segment
+
+		target.DataDir, err = ExpandDataDirTemplate(template, segment)
+		if err != nil {
+			return target, err
+		}
+		target.Port, err = ports.allocate(segment, defaultOffset)
+		return target, err
+	}
+
+	layout := &TargetLayout{}
+	layout.Master, err = place(source.Segments[-1], defaultMasterPortOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, content := range source.ContentIDs {
+		if content == -1 {
+			continue
+		}
+		primary, err := place(source.Segments[content], defaultSegmentPortOffset)
+		if err != nil {
+			return nil, err
+		}
+		layout.Primaries = append(layout.Primaries, primary)
+	}
+
+	if standby, ok := source.Standby(); ok {
+		standby, err = place(standby, defaultMasterPortOffset)
+		if err != nil {
+			return nil, err
+		}
+		layout.Standby = &standby
+	}
+
+	for _, mirror := range source.MirrorConfigs() {
+		if mirror.ContentID == -1 {
+			continue
+		}
+		mirror, err = place(mirror, defaultSegmentPortOffset)
+		if err != nil {
+			return nil, err
+		}
+		layout.Mirrors = append(layout.Mirrors, mirror)
+	}
+
+	err = validateTargetLayout(source, layout)
+	if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// validateTargetLayout makes sure that no two target segments on a host share
+// a port or a data directory, and that every target data directory is created
+// in a directory of its own, apart from the source data directories. The
+// latter is what allows revert to remove that whole directory again.
+func validateTargetLayout(source *utils.Cluster, layout *TargetLayout) error {
+	var sourceSegments []cluster.SegConfig
+	for _, segment := range source.Segments {
+		sourceSegments = append(sourceSegments, segment)
+	}
+	sourceSegments = append(sourceSegments, source.MirrorConfigs()...)
+
+	ports := map[string]cluster.SegConfig{}
+	dataDirs := map[string]cluster.SegConfig{}
+	for _, segment := range layout.Segments() {
+		if segment.Port < 1 || segment.Port > 65535 {
+			return errors.Errorf("target port %d of content %d on host %s is not a valid port",
+				segment.Port, segment.ContentID, segment.Hostname)
+		}
+		portKey := fmt.Sprintf("%s:%d", segment.Hostname, segment.Port)
+		if other, ok := ports[portKey]; ok {
+			return errors.Errorf("target segments of content %d and %d would both use port %d on host %s",
+				other.ContentID, segment.ContentID, segment.Port, segment.Hostname)
+		}
+		ports[portKey] = segment
+
+		dirKey := segment.Hostname + ":" + segment.DataDir
+		if other, ok := dataDirs[dirKey]; ok {
+			return errors.Errorf("target segments of content %d and %d would both use data directory %s on host %s",
+				other.ContentID, segment.ContentID, segment.DataDir, segment.Hostname)
+		}
+		dataDirs[dirKey] = segment
+
+		parentDir := path.Dir(segment.DataDir)
+		for _, sourceSegment := range sourceSegments {
+			if sourceSegment.Hostname != segment.Hostname {
+				continue
+			}
+			if pathsOverlap(sourceSegment.DataDir, parentDir) {
+				return errors.Errorf("target data directory %s of content %d would be created in %s, "+
+					"which overlaps source data directory %s on host %s; "+
+					"target data directories need a parent directory of their own",
+					segment.DataDir, segment.ContentID, parentDir, sourceSegment.DataDir, segment.Hostname)
+			}
+		}
+	}
+	return nil
+}
+
+// pathsOverlap is true if a and b are the same directory, or one contains the
+// other.
+func pathsOverlap(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	return a == b ||
+		strings.HasPrefix(a, strings.TrimSuffix(b, "/")+"/") ||
+		strings.HasPrefix(b, strings.TrimSuffix(a, "/")+"/")
+}
+
+// ExpandDataDirTemplate returns the target data directory for the given source
+// segment. The template may use
+//
+//	{parent}   the directory that holds the source data directory
+//	{base}     the name of the source data directory
+//	{content}  the segment's content ID
+//	{dbid}     the segment's dbid
+//
+// and must expand to an absolute path.
+func ExpandDataDirTemplate(template string, segment cluster.SegConfig) (string, error) {
+	err := ValidateDataDirTemplate(template)
+	if err != nil {
+		return "", err
+	}
+
+	replacer := strings.NewReplacer(
+		"{parent}", path.Dir(segment.DataDir),
+		"{base}", path.Base(segment.DataDir),
+		"{content}", strconv.Itoa(segment.ContentID),
+		"{dbid}", strconv.Itoa(segment.DbID),
+	)
+	dataDir := path.Clean(replacer.Replace(template))
+	if !path.IsAbs(dataDir) {
+		return "", errors.Errorf("data directory template %q gives the relative path %s for %s",
+			template, dataDir, segment.DataDir)
+	}
+	return dataDir, nil
+}
+
+// ValidateDataDirTemplate checks that the template uses only the placeholders
+// that ExpandDataDirTemplate knows about.
+func ValidateDataDirTemplate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return errors.New("data directory template is empty")
+	}
+	for _, placeholder := range templatePlaceholder.FindAllString(template, -1) {
+		switch placeholder {
+		case "{parent}", "{base}", "{content}", "{dbid}":
+		default:
+			return errors.Errorf("data directory template %q uses unknown placeholder %s", template, placeholder)
+		}
+	}
+	return nil
+}
+
+// ParsePortRange parses a port range of the form "base" or "base-max". A max
+// of zero means that the range only ends at the highest port.
+func ParsePortRange(portRange string) (base int, max int, err error) {
+	parts := strings.SplitN(portRange, "-", 2)
+
+	base, err = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || base < 1 || base > 65535 {
+		return 0, 0, errors.Errorf("port range %q does not start with a valid port", portRange)
+	}
+	if len(parts) == 1 {
+		return base, 0, nil
+	}
+
+	max, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || max < base || max > 65535 {
+		return 0, 0, errors.Errorf("port range %q does not end with a valid port at or above %d", portRange, base)
+	}
+	return base, max, nil
+}
+
+// ParsePortMap parses the contents of a port map file. Each line gives a host,
+// the port of a source segment on that host and the port that its target
+// segment should use, separated by whitespace. Blank lines and lines starting
+// with # are ignored. The result is keyed by "host:source port".
+func ParsePortMap(contents []byte) (map[string]int, error) {
+	portMap := map[string]int{}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(contents)))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, errors.Errorf("line %d of the port map should be \"<host> <source port> <target port>\": %q", lineNumber, line)
+		}
+		sourcePort, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, errors.Errorf("line %d of the port map has an invalid source port %q", lineNumber, fields[1])
+		}
+		targetPort, err := strconv.Atoi(fields[2])
+		if err != nil || targetPort < 1 || targetPort > 65535 {
+			return nil, errors.Errorf("line %d of the port map has an invalid target port %q", lineNumber, fields[2])
+		}
+
+		key := fmt.Sprintf("%s:%d", fields[0], sourcePort)
+		if _, ok := portMap[key]; ok {
+			return nil, errors.Errorf("line %d of the port map repeats port %d of host %s", lineNumber, sourcePort, fields[0])
+		}
+		portMap[key] = targetPort
+	}
+	return portMap, scanner.Err()
+}
+
+// ReadPortMap reads and parses the port map file at the given path.
+func ReadPortMap(portMapFile string) (map[string]int, error) {
+	contents, err := utils.System.ReadFile(portMapFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read port map")
+	}
+	portMap, err := ParsePortMap(contents)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid port map %s", portMapFile)
+	}
+	return portMap, nil
+}
+
+type portAllocator struct {
+	portMapFile string
+	portMap     map[string]int // by "host:source port"
+
+	base, max int
+	used      map[string]bool // "host:port" of every source segment
+	next      map[string]int  // the next port to try, by host
+}
+
+func newPortAllocator(source *utils.Cluster, settings utils.LayoutSettings) (*portAllocator, error) {
+	a := &portAllocator{
+		portMapFile: settings.PortMapFile,
+		used:        map[string]bool{},
+		next:        map[string]int{},
+	}
+
+	var err error
+	if settings.PortMapFile != "" {
+		a.portMap, err = ReadPortMap(settings.PortMapFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if settings.PortRange != "" {
+		a.base, a.max, err = ParsePortRange(settings.PortRange)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, segment := range source.Segments {
+		a.used[fmt.Sprintf("%s:%d", segment.Hostname, segment.Port)] = true
+	}
+	for _, segment := range source.Mirrors {
+		a.used[fmt.Sprintf("%s:%d", segment.Hostname, segment.Port)] = true
+	}
+	return a, nil
+}
+
+func (a *portAllocator) allocate(segment cluster.SegConfig, defaultOffset int) (int, error) {
+	if a.portMap != nil {
+		port, ok := a.portMap[fmt.Sprintf("%s:%d", segment.Hostname, segment.Port)]
+		if !ok {
+			return 0, errors.Errorf("port map %s has no entry for port %d of host %s",
+				a.portMapFile, segment.Port, segment.Hostname)
+		}
+		return port, nil
+	}
+
+	if a.base == 0 {
+		return segment.Port + defaultOffset, nil
+	}
+
+	port := a.next[segment.Hostname]
+	if port < a.base {
+		port = a.base
+	}
+	for a.used[fmt.Sprintf("%s:%d", segment.Hostname, port)] {
+		port++
+	}
+	if (a.max != 0 && port > a.max) || port > 65535 {
+		return 0, errors.Errorf("port range %d-%d has no port left for content %d on host %s",
+			a.base, a.max, segment.ContentID, segment.Hostname)
+	}
+	a.next[segment.Hostname] = port + 1
+	return port, nil
+}
+
+// CheckTargetLayout asks the agent on every host whether the target ports
+// planned for that host are free, and whether the target data directories can
+// be created there. In link mode it also asks whether each target data
+// directory of the master and primaries will be on the same filesystem as its
+// source. All of the problems found, including every host in the layout that
+// has no agent to ask, are returned in one error.
+func CheckTargetLayout(fanOut FanOut, agentConns []*Connection, source *utils.Cluster, layout *TargetLayout, mode string) error {
+	ports := map[string][]int32{}
+	dataDirs := map[string][]string{}
+	for _, segment := range layout.Segments() {
+		ports[segment.Hostname] = append(ports[segment.Hostname], int32(segment.Port))
+		dataDirs[segment.Hostname] = append(dataDirs[segment.Hostname], segment.DataDir)
+	}
+
+	// pg_upgrade hard-links the master and the primaries in link mode; the
+	// standby and mirrors are copied from them afterwards.
+	linked := map[string][]*pb.DataDirPair{}
+	if mode == utils.LINK_MODE {
+		for _, segment := range append([]cluster.SegConfig{layout.Master}, layout.Primaries...) {
+			linked[segment.Hostname] = append(linked[segment.Hostname], &pb.DataDirPair{
+				OldDataDir: source.Segments[segment.ContentID].DataDir,
+				NewDataDir: segment.DataDir,
+				Content:    int32(segment.ContentID),
+			})
+		}
+	}
+
+	results := fanOut.Call(Clients(agentConns), func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
+		return client.Client.CheckTargetLayout(ctx, &pb.CheckTargetLayoutRequest{
+			Ports:          ports[client.Hostname],
+			Datadirs:       dataDirs[client.Hostname],
+			LinkedDatadirs: linked[client.Hostname],
+		})
+	})
+
+	var problems []string
+	for host := range ports {
+		if _, ok := results[host]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: could not be checked: no agent is available on this host", host))
+		}
+	}
+	for host, result := range results {
+		if result.Err != nil {
+			gplog.Error("Error checking the target layout on host %s: %s", host, result.Err.Error())
+			problems = append(problems, fmt.Sprintf("%s: could not be checked: %s", host, result.Err.Error()))
+			continue
+		}
+		for _, problem := range result.Reply.(*pb.CheckTargetLayoutReply).GetProblems() {
+			problems = append(problems, fmt.Sprintf("%s: %s", host, problem))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return errors.Errorf("the target cluster cannot be created as planned:\n\t%s", strings.Join(problems, "\n\t"))
+	}
+	return nil
+}
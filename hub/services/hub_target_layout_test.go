@@ -0,0 +1,246 @@
+package services_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"golang.org/x/net/context"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("target cluster layout", func() {
+	ports := func(layout *services.TargetLayout) []int {
+		var ports []int
+		for _, segment := range layout.Segments() {
+			ports = append(ports, segment.Port)
+		}
+		return ports
+	}
+	dataDirs := func(layout *services.TargetLayout) []string {
+		var dataDirs []string
+		for _, segment := range layout.Segments() {
+			dataDirs = append(dataDirs, segment.DataDir)
+		}
+		return dataDirs
+	}
+
+	Describe("PlanTargetLayout", func() {
+		It("offsets the source ports and uses _upgrade directories by default", func() {
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ports(layout)).To(Equal([]int{15433, 27432, 27433}))
+			Expect(dataDirs(layout)).To(Equal([]string{
+				dir + "_upgrade/seg-1", dir + "_upgrade/seg1", dir + "_upgrade/seg2",
+			}))
+			Expect(layout.MasterParentDir()).To(Equal(dir + "_upgrade"))
+		})
+
+		It("hands out ports from a range, skipping those of the source cluster", func() {
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{PortRange: "25432"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ports(layout)).To(Equal([]int{25434, 25435, 25436}))
+		})
+
+		It("gives each host its own run of ports from the range", func() {
+			seg1 := source.Segments[1]
+			seg1.Hostname = "sdw1"
+			source.Segments[1] = seg1
+			source.SetMirrors([]cluster.SegConfig{
+				{ContentID: 0, DbID: 4, Port: 35432, Hostname: "sdw1", DataDir: "/mirror/seg1"},
+			})
+
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{PortRange: "50000-50010"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ports(layout)).To(Equal([]int{50000, 50001, 50000, 50001}))
+		})
+
+		It("fails when the range runs out of ports", func() {
+			_, err := services.PlanTargetLayout(source, utils.LayoutSettings{PortRange: "25432-25435"})
+			Expect(err).To(MatchError("port range 25432-25435 has no port left for content 1 on host localhost"))
+		})
+
+		It("takes every port from the port map when there is one", func() {
+			portMap := filepath.Join(dir, "ports")
+			err := ioutil.WriteFile(portMap, []byte("# master\nlocalhost 15432 6000\n\nlocalhost 25432 6001\nlocalhost 25433 6002\n"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{PortMapFile: portMap, PortRange: "50000"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ports(layout)).To(Equal([]int{6000, 6001, 6002}))
+		})
+
+		It("fails when the port map misses a segment", func() {
+			portMap := filepath.Join(dir, "ports")
+			err := ioutil.WriteFile(portMap, []byte("localhost 15432 6000\n"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = services.PlanTargetLayout(source, utils.LayoutSettings{PortMapFile: portMap})
+			Expect(err).To(MatchError(ContainSubstring("has no entry for port 25432 of host localhost")))
+		})
+
+		It("fails when two target segments would share a port", func() {
+			portMap := filepath.Join(dir, "ports")
+			err := ioutil.WriteFile(portMap, []byte("localhost 15432 6000\nlocalhost 25432 6001\nlocalhost 25433 6001\n"), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = services.PlanTargetLayout(source, utils.LayoutSettings{PortMapFile: portMap})
+			Expect(err).To(MatchError("target segments of content 0 and 1 would both use port 6001 on host localhost"))
+		})
+
+		It("expands the data directory template for every segment", func() {
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{DataDirTemplate: "/data6/gpseg{content}_{dbid}"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(dataDirs(layout)).To(Equal([]string{"/data6/gpseg-1_1", "/data6/gpseg0_2", "/data6/gpseg1_3"}))
+		})
+
+		It("fails when target data directories would share the parent of a source data directory", func() {
+			_, err := services.PlanTargetLayout(source, utils.LayoutSettings{DataDirTemplate: "{parent}/{base}_v6"})
+			Expect(err).To(MatchError(ContainSubstring("target data directories need a parent directory of their own")))
+		})
+
+		It("fails when two target segments would share a data directory", func() {
+			_, err := services.PlanTargetLayout(source, utils.LayoutSettings{DataDirTemplate: "{parent}_v6/data"})
+			Expect(err).To(MatchError(ContainSubstring("would both use data directory " + dir + "_v6/data")))
+		})
+	})
+
+	DescribeTable("ParsePortRange",
+		func(portRange string, base, max int, valid bool) {
+			actualBase, actualMax, err := services.ParsePortRange(portRange)
+			if !valid {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actualBase).To(Equal(base))
+			Expect(actualMax).To(Equal(max))
+		},
+		Entry("a base port", "50432", 50432, 0, true),
+		Entry("a base and max port", "50432-50999", 50432, 50999, true),
+		Entry("a max below the base", "50432-50000", 0, 0, false),
+		Entry("a port out of range", "70000", 0, 0, false),
+		Entry("not a number", "high", 0, 0, false),
+	)
+
+	Describe("ParsePortMap", func() {
+		It("rejects a line without three fields", func() {
+			_, err := services.ParsePortMap([]byte("localhost 15432\n"))
+			Expect(err).To(MatchError(ContainSubstring("line 1 of the port map")))
+		})
+
+		It("rejects a source port given twice", func() {
+			_, err := services.ParsePortMap([]byte("localhost 15432 6000\nlocalhost 15432 6001\n"))
+			Expect(err).To(MatchError("line 2 of the port map repeats port 15432 of host localhost"))
+		})
+	})
+
+	Describe("ValidateDataDirTemplate", func() {
+		It("rejects unknown placeholders", func() {
+			err := services.ValidateDataDirTemplate("{parent}_v6/{name}")
+			Expect(err).To(MatchError(`data directory template "{parent}_v6/{name}" uses unknown placeholder {name}`))
+		})
+	})
+
+	Describe("CheckTargetLayout", func() {
+		var layout *services.TargetLayout
+
+		BeforeEach(func() {
+			var err error
+			layout, err = services.PlanTargetLayout(source, utils.LayoutSettings{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("asks each agent about the ports and data directories planned for its host", func() {
+			conns, err := hub.AgentConns()
+			Expect(err).ToNot(HaveOccurred())
+
+			err = services.CheckTargetLayout(services.FanOut{}, conns, source, layout, utils.COPY_MODE)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mockAgent.CheckTargetLayoutRequest.Ports).To(Equal([]int32{15433, 27432, 27433}))
+			Expect(mockAgent.CheckTargetLayoutRequest.Datadirs).To(Equal(dataDirs(layout)))
+			Expect(mockAgent.CheckTargetLayoutRequest.LinkedDatadirs).To(BeEmpty())
+		})
+
+		It("in link mode, asks whether the master and primaries will share a filesystem with their sources", func() {
+			source.SetMirrors([]cluster.SegConfig{
+				{ContentID: 0, DbID: 5, Port: 35432, Hostname: "localhost", DataDir: filepath.Join(dir, "mirror", "seg1")},
+			})
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{})
+			Expect(err).ToNot(HaveOccurred())
+			conns, err := hub.AgentConns()
+			Expect(err).ToNot(HaveOccurred())
+
+			err = services.CheckTargetLayout(services.FanOut{}, conns, source, layout, utils.LINK_MODE)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mockAgent.CheckTargetLayoutRequest.LinkedDatadirs).To(Equal([]*pb.DataDirPair{
+				{OldDataDir: source.Segments[-1].DataDir, NewDataDir: layout.Master.DataDir, Content: -1},
+				{OldDataDir: source.Segments[0].DataDir, NewDataDir: layout.Primaries[0].DataDir, Content: 0},
+				{OldDataDir: source.Segments[1].DataDir, NewDataDir: layout.Primaries[1].DataDir, Content: 1},
+			}))
+		})
+
+		It("reports every problem the agents find", func() {
+			mockAgent.CheckTargetLayoutResponse = &pb.CheckTargetLayoutReply{
+				Problems: []string{"port 15433 is not free: address already in use"},
+			}
+			conns, err := hub.AgentConns()
+			Expect(err).ToNot(HaveOccurred())
+
+			err = services.CheckTargetLayout(services.FanOut{}, conns, source, layout, utils.COPY_MODE)
+			Expect(err).To(MatchError("the target cluster cannot be created as planned:\n\tlocalhost: port 15433 is not free: address already in use"))
+		})
+
+		It("reports agents that cannot be reached", func() {
+			mockAgent.Err <- errors.New("agent is down")
+			conns, err := hub.AgentConns()
+			Expect(err).ToNot(HaveOccurred())
+
+			err = services.CheckTargetLayout(services.FanOut{}, conns, source, layout, utils.COPY_MODE)
+			Expect(err).To(MatchError(ContainSubstring("localhost: could not be checked")))
+		})
+
+		It("reports every host that has no agent", func() {
+			segment := source.Segments[1]
+			segment.Hostname = "sdw1"
+			source.Segments[1] = segment
+			layout, err := services.PlanTargetLayout(source, utils.LayoutSettings{})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = services.CheckTargetLayout(services.FanOut{}, nil, source, layout, utils.COPY_MODE)
+			Expect(err).To(MatchError("the target cluster cannot be created as planned:\n" +
+				"\tlocalhost: could not be checked: no agent is available on this host\n" +
+				"\tsdw1: could not be checked: no agent is available on this host"))
+		})
+	})
+
+	Describe("SetConfig", func() {
+		It("stores layout settings with the target cluster", func() {
+			_, err := hub.SetConfig(context.Background(), &pb.SetConfigRequest{Name: "target-port-range", Value: "50432-50999"})
+			Expect(err).ToNot(HaveOccurred())
+
+			reply, err := hub.GetConfig(context.Background(), &pb.GetConfigRequest{Name: "target-port-range"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reply.Value).To(Equal("50432-50999"))
+			Expect(target.Layout.PortRange).To(Equal("50432-50999"))
+		})
+
+		It("rejects settings that don't work for the source cluster", func() {
+			_, err := hub.SetConfig(context.Background(), &pb.SetConfigRequest{Name: "target-datadir-template", Value: "{parent}/{base}_v6"})
+			Expect(err).To(HaveOccurred())
+			Expect(target.Layout.DataDirTemplate).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus/file"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+)
+
+// The reason given for a step that was still running when the hub stopped.
+var ErrStepInterrupted = errors.New("interrupted")
+
+// A Task is a step that a hub is running in the background.
+type Task struct {
+	Step    string    `json:"step"`
+	Pid     int       `json:"pid"` // of the hub running it
+	Started time.Time `json:"started"`
+
+	// HubStarted is when the hub running the task started, in milliseconds
+	// since the epoch, which tells it apart from a later process that has
+	// been given the same pid. It is zero if it couldn't be found.
+	HubStarted uint64 `json:"hubStarted,omitempty"`
+}
+
+// TaskSupervisor runs the steps that outlast the requests that start them,
+// and keeps a record of them in the state directory. If the hub stops before
+// a step finishes, the step is left RUNNING with nothing to finish it; the
+// record is how the next hub tells that apart from a step that is still
+// running.
+type TaskSupervisor struct {
+	path string
+
+	mu      sync.Mutex
+	running []Task
+}
+
+func NewTaskSupervisor(stateDir string) *TaskSupervisor {
+	return &TaskSupervisor{path: filepath.Join(stateDir, file.Tasks)}
+}
+
+// Go runs task for the step in the background, recording it until it returns.
+func (s *TaskSupervisor) Go(step string, task func()) {
+	pid := utils.System.Getpid()
+	hubStarted, err := utils.System.ProcessStartTime(pid)
+	if err != nil {
+		gplog.Warn("Could not find when the hub started: %s", err)
+	}
+
+	s.mu.Lock()
+	s.running = append(s.running, Task{
+		Step:       step,
+		Pid:        pid,
+		Started:    utils.System.Now(),
+		HubStarted: hubStarted,
+	})
+	err = s.save()
+	s.mu.Unlock()
+
+	if err != nil {
+		// The step still runs; it just can't be told apart from an
+		// interrupted one should the hub stop.
+		gplog.Error("Could not record that %s is running: %s", step, err)
+	}
+
+	go func() {
+		defer s.finished(step)
+		defer log.WritePanics()
+
+		task()
+	}()
+}
+
+func (s *TaskSupervisor) finished(step string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, task := range s.running {
+		if task.Step == step {
+			s.running = append(s.running[:i], s.running[i+1:]...)
+			break
+		}
+	}
+
+	err := s.save()
+	if err != nil {
+		gplog.Error("Could not record that %s has finished: %s", step, err)
+	}
+}
+
+// Running reports whether this hub is running the step.
+func (s *TaskSupervisor) Running(step string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, task := range s.running {
+		if task.Step == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Recorded returns the tasks in the state directory, which before this hub
+// has started any are those of the hub that ran before it.
+func (s *TaskSupervisor) Recorded() ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents, err := utils.System.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read %s", s.path)
+	}
+
+	var tasks []Task
+	err = json.Unmarshal(contents, &tasks)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", s.path)
+	}
+	return tasks, nil
+}
+
+func (s *TaskSupervisor) save() error {
+	err := utils.System.MkdirAll(filepath.Dir(s.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	tasks := s.running
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	return utils.WriteJSONFile(s.path, tasks)
+}
+
+// FailInterruptedSteps marks FAILED each step that is recorded as RUNNING but
+// that no hub is running any longer, because the hub that ran it stopped
+// before it finished. Start calls it once the hub holds its port, and so
+// knows that no other hub is running, before it takes any requests.
+func (h *Hub) FailInterruptedSteps() error {
+	tasks, err := h.tasks.Recorded()
+	if err != nil {
+		return errors.Wrap(err, "could not tell which steps were interrupted")
+	}
+
+	live := map[string]bool{}
+	for _, task := range tasks {
+		if task.Pid != utils.System.Getpid() && hubAlive(task) {
+			live[task.Step] = true
+		}
+	}
+
+	for _, step := range h.checklist.WritableSteps() {
+		name := step.Name()
+		if step.Status() != pb.StepStatus_RUNNING || live[name] || h.tasks.Running(name) {
+			continue
+		}
+
+		gplog.Warn("%s was still running when the hub stopped; marking it failed", name)
+
+		err = h.checklist.GetStepWriter(name).MarkFailed(ErrStepInterrupted)
+		if err != nil {
+			return errors.Wrapf(err, "could not mark %s as interrupted", name)
+		}
+	}
+
+	return nil
+}
+
+// hubAlive reports whether the hub that recorded the task is still running:
+// whether there is a process with its pid, whether or not it can be
+// signalled, that started when the hub did.
+func hubAlive(task Task) bool {
+	err := utils.System.Kill(task.Pid, 0)
+	if err != nil && err != syscall.EPERM {
+		return false
+	}
+
+	if task.HubStarted == 0 {
+		// There's no telling the hub apart from a process that took its
+		// pid, so err on the side of leaving the step alone.
+		return true
+	}
+	started, err := utils.System.ProcessStartTime(task.Pid)
+	if err != nil {
+		return true
+	}
+	return started == task.HubStarted
+}
@@ -0,0 +1,159 @@
+package services_test
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus/file"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TaskSupervisor", func() {
+	var tasks *services.TaskSupervisor
+
+	BeforeEach(func() {
+		tasks = services.NewTaskSupervisor(dir)
+	})
+
+	It("records a task while it runs, and forgets it when it returns", func() {
+		done := make(chan struct{})
+		tasks.Go("my-step", func() {
+			<-done
+		})
+
+		Expect(tasks.Running("my-step")).To(BeTrue())
+		Expect(tasks.Running("other-step")).To(BeFalse())
+
+		recorded, err := tasks.Recorded()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded).To(HaveLen(1))
+		Expect(recorded[0].Step).To(Equal("my-step"))
+		Expect(recorded[0].Pid).To(Equal(os.Getpid()))
+		hubStarted, err := utils.System.ProcessStartTime(os.Getpid())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded[0].HubStarted).To(Equal(hubStarted))
+
+		close(done)
+		Eventually(func() bool { return tasks.Running("my-step") }).Should(BeFalse())
+
+		recorded, err = tasks.Recorded()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded).To(BeEmpty())
+	})
+
+	It("has no tasks to begin with", func() {
+		recorded, err := tasks.Recorded()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorded).To(BeEmpty())
+	})
+})
+
+var _ = Describe("FailInterruptedSteps", func() {
+	const livePid, deadPid = 4242, 4343
+	const liveHubStarted = 1500000000000
+
+	BeforeEach(func() {
+		utils.System.Kill = func(pid int, sig syscall.Signal) error {
+			Expect(sig).To(Equal(syscall.Signal(0)))
+			if pid == livePid {
+				return nil
+			}
+			return syscall.ESRCH
+		}
+		utils.System.ProcessStartTime = func(pid int) (uint64, error) {
+			Expect(pid).To(Equal(livePid))
+			return liveHubStarted, nil
+		}
+
+		for _, name := range []string{"step-a", "step-b", "step-c"} {
+			cm.AddStep(name, 0)
+		}
+	})
+
+	record := func(tasks ...services.Task) {
+		err := utils.WriteJSONFile(filepath.Join(dir, file.Tasks), tasks)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("marks a running step that no hub is running as interrupted", func() {
+		cm.GetStepWriter("step-a").MarkInProgress()
+		cm.GetStepWriter("step-b").MarkInProgress()
+		cm.GetStepWriter("step-b").MarkComplete()
+
+		err := hub.FailInterruptedSteps()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cm.IsFailed("step-a")).To(BeTrue())
+		Expect(cm.FailureError("step-a")).To(Equal(services.ErrStepInterrupted))
+		Expect(cm.IsComplete("step-b")).To(BeTrue())
+		Expect(cm.IsPending("step-c")).To(BeTrue())
+	})
+
+	It("marks a step interrupted when the hub that ran it has exited", func() {
+		record(services.Task{Step: "step-a", Pid: deadPid})
+		cm.GetStepWriter("step-a").MarkInProgress()
+
+		err := hub.FailInterruptedSteps()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cm.IsFailed("step-a")).To(BeTrue())
+	})
+
+	It("leaves a step alone while the hub that ran it is still alive", func() {
+		record(services.Task{Step: "step-a", Pid: livePid, HubStarted: liveHubStarted})
+		cm.GetStepWriter("step-a").MarkInProgress()
+
+		err := hub.FailInterruptedSteps()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cm.IsInProgress("step-a")).To(BeTrue())
+	})
+
+	It("marks a step interrupted when another process has since been given the hub's pid", func() {
+		record(services.Task{Step: "step-a", Pid: livePid, HubStarted: liveHubStarted - 1000})
+		cm.GetStepWriter("step-a").MarkInProgress()
+
+		err := hub.FailInterruptedSteps()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cm.IsFailed("step-a")).To(BeTrue())
+	})
+
+	It("leaves a step alone if it cannot tell when the hub that ran it started", func() {
+		record(services.Task{Step: "step-a", Pid: livePid})
+		cm.GetStepWriter("step-a").MarkInProgress()
+
+		err := hub.FailInterruptedSteps()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cm.IsInProgress("step-a")).To(BeTrue())
+	})
+
+	It("does not mistake itself for the hub that ran the step", func() {
+		utils.System.Getpid = func() int { return livePid }
+		record(services.Task{Step: "step-a", Pid: livePid})
+		cm.GetStepWriter("step-a").MarkInProgress()
+
+		err := hub.FailInterruptedSteps()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cm.IsFailed("step-a")).To(BeTrue())
+	})
+
+	It("returns an error if it cannot tell which tasks were running", func() {
+		utils.System.ReadFile = func(string) ([]byte, error) {
+			return nil, syscall.EACCES
+		}
+		cm.GetStepWriter("step-a").MarkInProgress()
+
+		err := hub.FailInterruptedSteps()
+		Expect(err).To(MatchError(ContainSubstring("permission denied")))
+
+		Expect(cm.IsInProgress("step-a")).To(BeTrue())
+	})
+})
@@ -2,11 +2,16 @@ package services_test
 
 import (
 	"errors"
+	"io/ioutil"
 	"net"
+	"os"
 	"strconv"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/services"
+	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils/certs"
 	"golang.org/x/net/context"
 
 	"google.golang.org/grpc"
@@ -41,7 +46,7 @@ var _ = Describe("Hub", func() {
 		hubConfig := &services.HubConfig{
 			CliToHubPort: cliToHubPort,
 		}
-		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, nil)
+		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, cm)
 
 		hub.Stop()
 		go func() {
@@ -66,7 +71,7 @@ var _ = Describe("Hub", func() {
 		hubConfig := &services.HubConfig{
 			CliToHubPort: cliToHubPort,
 		}
-		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, nil)
+		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, cm)
 
 		go func() {
 			err = hub.Start()
@@ -76,13 +81,84 @@ var _ = Describe("Hub", func() {
 		Expect(err.Error()).To(ContainSubstring("failed to listen"))
 	})
 
+	It("leaves the steps alone if another hub holds its port", func() {
+		listener, err := net.Listen("tcp", ":0")
+		Expect(err).NotTo(HaveOccurred())
+		defer listener.Close()
+
+		hubConfig := &services.HubConfig{
+			CliToHubPort: listener.Addr().(*net.TCPAddr).Port,
+		}
+		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, cm)
+		cm.AddStep("step-a", 0)
+		cm.GetStepWriter("step-a").MarkInProgress()
+
+		Expect(hub.Start()).To(MatchError(ContainSubstring("failed to listen")))
+		Expect(cm.IsInProgress("step-a")).To(BeTrue())
+	})
+
+	It("marks the steps that the hub before it left running as interrupted, once it holds its port", func() {
+		hubConfig := &services.HubConfig{
+			CliToHubPort: cliToHubPort,
+		}
+		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, cm)
+		cm.AddStep("step-a", 0)
+		cm.GetStepWriter("step-a").MarkInProgress()
+
+		go hub.Start()
+		defer hub.Stop()
+
+		Eventually(func() bool { return cm.IsFailed("step-a") }).Should(BeTrue())
+	})
+
+	It("serves only clients with a certificate from its authority when TLS is on", func() {
+		stateDir, err := ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(stateDir)
+
+		_, err = certs.EnsureHubCerts(certs.Dir(stateDir), "localhost")
+		Expect(err).ToNot(HaveOccurred())
+
+		cliToHubPort, err = testutils.GetOpenPort()
+		Expect(err).ToNot(HaveOccurred())
+
+		hubConfig := &services.HubConfig{
+			CliToHubPort: cliToHubPort,
+			StateDir:     stateDir,
+			TLS:          true,
+		}
+		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, cm)
+		go hub.Start()
+		defer hub.Stop()
+
+		// Only wait for the hub to be ready the first time; after that,
+		// waiting would turn a rejected handshake into a timeout.
+		ping := func(transport grpc.DialOption, waitForReady bool) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			conn, err := grpc.Dial("localhost:"+strconv.Itoa(cliToHubPort), transport)
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+
+			_, err = pb.NewCliToHubClient(conn).Ping(ctx, &pb.PingRequest{}, grpc.FailFast(!waitForReady))
+			return err
+		}
+
+		creds, err := certs.ClientCredentials(certs.Dir(stateDir), certs.HUB_CERT, certs.HUB_KEY)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ping(grpc.WithTransportCredentials(creds), true)).To(Succeed())
+
+		Expect(ping(grpc.WithInsecure(), false)).ToNot(Succeed())
+	})
+
 	// This is inherently testing a race. It will give false successes instead
 	// of false failures, so DO NOT ignore transient failures in this test!
 	It("will return from Start() if Stop is called concurrently", func() {
 		hubConfig := &services.HubConfig{
 			CliToHubPort: cliToHubPort,
 		}
-		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, nil)
+		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, cm)
 		done := make(chan bool, 1)
 
 		go func() {
@@ -98,7 +174,7 @@ var _ = Describe("Hub", func() {
 		hubConfig := &services.HubConfig{
 			HubToAgentPort: hubToAgentPort,
 		}
-		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, nil)
+		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, cm)
 		go hub.Start()
 
 		By("creating connections")
@@ -144,23 +220,152 @@ var _ = Describe("Hub", func() {
 		Expect(newConns[0]).To(Equal(savedConns[0]))
 	})
 
-	// XXX This test takes 1.5 seconds because of EnsureConnsAreReady(...)
-	It("returns an error if any connections have non-ready states", func() {
+	It("reconnects to an agent that has restarted", func() {
 		hubConfig := &services.HubConfig{
 			HubToAgentPort: hubToAgentPort,
 		}
 		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, nil)
+		defer hub.Stop()
 
 		conns, err := hub.AgentConns()
 		Expect(err).ToNot(HaveOccurred())
 		Expect(conns).To(HaveLen(1))
 
 		agentA.Stop()
+		Eventually(func() connectivity.State { return conns[0].Conn.GetState() }).ShouldNot(Equal(connectivity.Ready))
+		agentA = testutils.NewMockAgentServerOnPort(hubToAgentPort)
+
+		newConns, err := hub.AgentConns()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newConns).To(HaveLen(1))
+		Expect(newConns[0]).ToNot(Equal(conns[0]))
+		Expect(newConns[0].Conn.GetState()).To(Equal(connectivity.Ready))
+		Expect(hub.AgentHealth()[0].Healthy()).To(BeTrue())
+	})
 
-		Eventually(func() connectivity.State { return conns[0].Conn.GetState() }).Should(Equal(connectivity.TransientFailure))
+	It("names the agents it cannot reach, and waits before dialing them again", func() {
+		agentA.Stop()
+
+		var dials int
+		dialer := func(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+			dials++
+			return grpc.DialContext(ctx, target, opts...)
+		}
+		hubConfig := &services.HubConfig{
+			HubToAgentPort: hubToAgentPort,
+			DialTimeout:    100 * time.Millisecond,
+		}
+		hub := services.NewHub(source, target, dialer, hubConfig, nil)
+		defer hub.Stop()
+
+		_, err := hub.AgentConns()
+		Expect(err).To(MatchError(ContainSubstring("could not connect to the agents: localhost: ")))
 
 		_, err = hub.AgentConns()
-		Expect(err).To(HaveOccurred())
+		Expect(err).To(MatchError(ContainSubstring("localhost: not retrying for ")))
+		Expect(dials).To(Equal(1))
+
+		health := hub.AgentHealth()
+		Expect(health).To(HaveLen(1))
+		Expect(health[0].Hostname).To(Equal("localhost"))
+		Expect(health[0].Healthy()).To(BeFalse())
+		Expect(health[0].Failures).To(Equal(1))
+		Expect(health[0].LastError).To(HaveOccurred())
+	})
+
+	It("connects to only the hosts it is asked about", func() {
+		dialer := func(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+			if target == "nowhere:"+strconv.Itoa(hubToAgentPort) {
+				return nil, errors.New("no such host")
+			}
+			return grpc.DialContext(ctx, target, opts...)
+		}
+		hubConfig := &services.HubConfig{
+			HubToAgentPort: hubToAgentPort,
+		}
+		hub := services.NewHub(source, target, dialer, hubConfig, nil)
+		defer hub.Stop()
+
+		conns, err := hub.AgentConns("localhost")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conns).To(HaveLen(1))
+		Expect(conns[0].Hostname).To(Equal("localhost"))
+
+		_, err = hub.AgentConns("localhost", "nowhere")
+		Expect(err).To(MatchError("could not connect to the agents: nowhere: no such host"))
+	})
+
+	It("connects to the hosts it can reach, and names the others", func() {
+		dialer := func(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+			if target == "nowhere:"+strconv.Itoa(hubToAgentPort) {
+				return nil, errors.New("no such host")
+			}
+			return grpc.DialContext(ctx, target, opts...)
+		}
+		pool := services.NewAgentPool(dialer, hubToAgentPort, time.Second, func() (grpc.DialOption, error) {
+			return grpc.WithInsecure(), nil
+		})
+		defer pool.Close()
+
+		conns, unreachable := pool.Reachable("localhost", "nowhere")
+		Expect(conns).To(HaveLen(1))
+		Expect(conns[0].Hostname).To(Equal("localhost"))
+		Expect(unreachable).To(HaveLen(1))
+		Expect(unreachable["nowhere"].Err).To(MatchError("no such host"))
+	})
+
+	It("reports on the agent on every host of the source cluster", func() {
+		agentA.Stop()
+
+		hubConfig := &services.HubConfig{
+			HubToAgentPort: hubToAgentPort,
+			DialTimeout:    100 * time.Millisecond,
+		}
+		hub := services.NewHub(source, target, grpc.DialContext, hubConfig, nil)
+		defer hub.Stop()
+
+		reply, err := hub.StatusAgents(nil, &pb.StatusAgentsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Agents).To(HaveLen(1))
+
+		agent := reply.Agents[0]
+		Expect(agent.Hostname).To(Equal("localhost"))
+		Expect(agent.Healthy).To(BeFalse())
+		Expect(agent.Failures).To(Equal(int32(1)))
+		Expect(agent.LastError).ToNot(BeEmpty())
+		Expect(agent.RetryAt).ToNot(BeZero())
+	})
+
+	It("stops without waiting for dials in progress", func() {
+		dialing := make(chan struct{})
+		dialer := func(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+			close(dialing)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		hubConfig := &services.HubConfig{
+			HubToAgentPort: hubToAgentPort,
+			DialTimeout:    time.Minute,
+		}
+		hub := services.NewHub(source, target, dialer, hubConfig, nil)
+
+		dialErr := make(chan error, 1)
+		go func() {
+			_, err := hub.AgentConns()
+			dialErr <- err
+		}()
+		<-dialing
+
+		stopped := make(chan struct{})
+		go func() {
+			hub.Stop()
+			close(stopped)
+		}()
+		Eventually(stopped).Should(BeClosed())
+		Eventually(dialErr).Should(Receive(HaveOccurred()))
+
+		_, err := hub.AgentConns()
+		Expect(err).To(MatchError(ContainSubstring(services.ErrAgentPoolClosed.Error())))
 	})
 
 	It("returns an error if any connections have non-ready states when first dialing", func() {
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// segmentCount is how many primary segments the source cluster has, which is
+// what the durations of the steps mostly depend on.
+func (h *Hub) segmentCount() int {
+	if h.source == nil || h.source.Cluster == nil {
+		return 0
+	}
+
+	count := 0
+	for content := range h.source.Segments {
+		if content >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// stepStarted records when the hub started one of the steps that do not keep
+// their own timing.
+func (h *Hub) stepStarted(name string) {
+	_, err := h.runs.Update(h.segmentCount(), func(run *upgradestatus.Run) {
+		run.Steps[name] = &upgradestatus.StepTiming{Start: utils.System.Now()}
+	})
+	if err != nil {
+		gplog.Error("Could not record the start of %s: %s", name, err)
+	}
+}
+
+// recordConversions keeps the times that the agents report for the
+// conversions of the primaries. Convert-primaries runs from the start of the
+// first of them until the last one finishes.
+func (h *Hub) recordConversions(statuses []*pb.ConversionStatus) {
+	_, err := h.runs.Update(h.segmentCount(), func(run *upgradestatus.Run) {
+		if run.Conversions == nil {
+			run.Conversions = map[int32]*upgradestatus.StepTiming{}
+		}
+
+		for _, status := range statuses {
+			if status.GetRole() == pb.SegmentRole_MASTER || status.GetStartTime() == 0 {
+				continue
+			}
+
+			timing := &upgradestatus.StepTiming{
+				Start:    time.Unix(status.GetStartTime(), 0),
+				Complete: status.GetStatus() == pb.StepStatus_COMPLETE,
+			}
+			if status.GetEndTime() != 0 {
+				timing.End = time.Unix(status.GetEndTime(), 0)
+			}
+			run.Conversions[status.GetDbid()] = timing
+		}
+
+		if span := timingSpan(run.Conversions); span != nil {
+			run.Steps[upgradestatus.CONVERT_PRIMARIES] = span
+		}
+	})
+	if err != nil {
+		gplog.Error("Could not record the conversion times of the primaries: %s", err)
+	}
+}
+
+// timingSpan runs from the first start to the last end, once all have ended.
+func timingSpan(timings map[int32]*upgradestatus.StepTiming) *upgradestatus.StepTiming {
+	var span *upgradestatus.StepTiming
+	ended := true
+	for _, timing := range timings {
+		if span == nil {
+			span = &upgradestatus.StepTiming{Start: timing.Start, Complete: true}
+		}
+		if timing.Start.Before(span.Start) {
+			span.Start = timing.Start
+		}
+		if timing.End.IsZero() {
+			ended = false
+		} else if timing.End.After(span.End) {
+			span.End = timing.End
+		}
+		span.Complete = span.Complete && timing.Complete
+	}
+
+	if span != nil && !ended {
+		span.End = time.Time{}
+		span.Complete = false
+	}
+	return span
+}
+
+// timeSteps records in the current run when each step started and finished,
+// and adds to the reply how long each step, and the upgrade as a whole, has
+// taken and is likely to take. Timing is only informational, so a failure to
+// record it is logged and otherwise ignored.
+func (h *Hub) timeSteps(steps []upgradestatus.StateReader, reply *pb.StatusUpgradeReply) {
+	now := utils.System.Now()
+	runs, err := h.runs.Update(h.segmentCount(), func(run *upgradestatus.Run) {
+		for i, step := range steps {
+			observeStep(run, step, reply.ListOfUpgradeStepStatuses[i].GetStatus(), now)
+		}
+	})
+	if err != nil {
+		gplog.Error("Could not record how long the steps took: %s", err)
+		return
+	}
+	run := runs[len(runs)-1]
+
+	var first, last time.Time
+	finished := true
+	for i, step := range steps {
+		timing := run.Steps[step.Name()]
+		usual, earlierRuns := upgradestatus.UsualDuration(runs, step.Name())
+		stepTiming := newStepTiming(timing, usual, earlierRuns, now)
+		reply.ListOfUpgradeStepStatuses[i].Timing = stepTiming
+
+		if reply.RemainingSeconds >= 0 {
+			if stepTiming.RemainingSeconds < 0 {
+				reply.RemainingSeconds = -1
+			} else {
+				reply.RemainingSeconds += stepTiming.RemainingSeconds
+			}
+		}
+
+		if timing == nil {
+			finished = false
+			continue
+		}
+		if first.IsZero() || timing.Start.Before(first) {
+			first = timing.Start
+		}
+		if timing.End.IsZero() {
+			finished = false
+		} else if timing.End.After(last) {
+			last = timing.End
+		}
+	}
+
+	if first.IsZero() {
+		return
+	}
+	if !finished {
+		last = now
+	}
+	reply.ElapsedSeconds = seconds(last.Sub(first))
+}
+
+// observeStep updates the step's timing in run. Steps that keep their own
+// timing report it. For the others, the hub records when it started them, or
+// else when they were first seen running, and they are taken to have finished
+// when they are first seen to have done so.
+func observeStep(run *upgradestatus.Run, step upgradestatus.StateReader, status pb.StepStatus, now time.Time) {
+	name := step.Name()
+	if timer, ok := step.(upgradestatus.TimingReader); ok {
+		if timing := timer.Timing(); timing != nil {
+			run.Steps[name] = timing
+		} else {
+			delete(run.Steps, name)
+		}
+		return
+	}
+
+	timing := run.Steps[name]
+	switch status {
+	case pb.StepStatus_RUNNING:
+		if timing == nil || !timing.End.IsZero() {
+			run.Steps[name] = &upgradestatus.StepTiming{Start: now}
+		}
+	case pb.StepStatus_COMPLETE, pb.StepStatus_FAILED:
+		if timing != nil && timing.End.IsZero() {
+			timing.End = now
+			timing.Complete = status == pb.StepStatus_COMPLETE
+		}
+	}
+}
+
+// newStepTiming reports a step's timing. A step that has not completed is
+// expected to take as long as it usually does, less however long it has been
+// running.
+func newStepTiming(timing *upgradestatus.StepTiming, usual time.Duration, earlierRuns int, now time.Time) *pb.StepTiming {
+	stepTiming := &pb.StepTiming{
+		RemainingSeconds: -1,
+		UsualSeconds:     seconds(usual),
+		EarlierRuns:      int32(earlierRuns),
+	}
+
+	running := false
+	if timing != nil {
+		stepTiming.StartTime = timing.Start.Unix()
+		if !timing.End.IsZero() {
+			stepTiming.EndTime = timing.End.Unix()
+		}
+		stepTiming.ElapsedSeconds = seconds(timing.Elapsed(now))
+		running = timing.End.IsZero()
+	}
+
+	switch {
+	case timing != nil && timing.Complete:
+		stepTiming.RemainingSeconds = 0
+	case earlierRuns > 0:
+		remaining := usual
+		if running {
+			remaining -= timing.Elapsed(now)
+		}
+		if remaining < 0 {
+			remaining = 0
+		}
+		stepTiming.RemainingSeconds = seconds(remaining)
+	}
+	return stepTiming
+}
+
+func seconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
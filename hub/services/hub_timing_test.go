@@ -0,0 +1,182 @@
+package services_test
+
+import (
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+)
+
+var _ = Describe("step timing", func() {
+	var (
+		now      time.Time
+		runs     *upgradestatus.Runs
+		segments int
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+		utils.System.Now = func() time.Time { return now }
+
+		segments = 0
+		for content := range source.Segments {
+			if content >= 0 {
+				segments++
+			}
+		}
+		runs = upgradestatus.NewRuns(dir)
+
+		cm = testutils.NewMockChecklistManager()
+		cm.AddStep(upgradestatus.CONFIG, pb.UpgradeSteps_CONFIG)
+		cm.AddStep(upgradestatus.SEGINSTALL, pb.UpgradeSteps_SEGINSTALL)
+		hub = services.NewHub(source, target, nil, hubConf, cm)
+	})
+
+	// earlierRun records a run on a cluster with the given number of
+	// segments, in which each step took the given time, and begins another on
+	// the source cluster.
+	earlierRun := func(size int, durations map[string]time.Duration) {
+		_, err := runs.Update(size, func(run *upgradestatus.Run) {
+			run.Segments = size
+			start := now.Add(-24 * time.Hour)
+			for step, duration := range durations {
+				run.Steps[step] = &upgradestatus.StepTiming{Start: start, End: start.Add(duration), Complete: true}
+			}
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(runs.Begin(segments)).To(Succeed())
+	}
+
+	status := func() *pb.StatusUpgradeReply {
+		reply, err := hub.StatusUpgrade(nil, &pb.StatusUpgradeRequest{})
+		Expect(err).ToNot(HaveOccurred())
+		return reply
+	}
+
+	It("times each step and estimates what is left from earlier runs on a cluster of the same size", func() {
+		earlierRun(segments, map[string]time.Duration{
+			upgradestatus.CONFIG:     10 * time.Second,
+			upgradestatus.SEGINSTALL: 90 * time.Second,
+		})
+		earlierRun(segments, map[string]time.Duration{
+			upgradestatus.CONFIG: 20 * time.Second,
+		})
+		earlierRun(segments+1, map[string]time.Duration{
+			upgradestatus.CONFIG: time.Hour,
+		})
+		start := now
+
+		reply := status()
+		Expect(reply.ElapsedSeconds).To(BeZero())
+		Expect(reply.RemainingSeconds).To(Equal(int64(15 + 90)))
+		Expect(reply.ListOfUpgradeStepStatuses[0].Timing).To(Equal(&pb.StepTiming{
+			RemainingSeconds: 15,
+			UsualSeconds:     15,
+			EarlierRuns:      2,
+		}))
+
+		config := cm.GetStepWriter(upgradestatus.CONFIG)
+		config.MarkInProgress()
+		status()
+
+		now = now.Add(5 * time.Second)
+		reply = status()
+		Expect(reply.ElapsedSeconds).To(Equal(int64(5)))
+		Expect(reply.RemainingSeconds).To(Equal(int64(10 + 90)))
+		Expect(reply.ListOfUpgradeStepStatuses[0].Timing).To(Equal(&pb.StepTiming{
+			StartTime:        start.Unix(),
+			ElapsedSeconds:   5,
+			RemainingSeconds: 10,
+			UsualSeconds:     15,
+			EarlierRuns:      2,
+		}))
+
+		now = now.Add(25 * time.Second)
+		config.MarkComplete()
+		reply = status()
+		Expect(reply.ElapsedSeconds).To(Equal(int64(30)))
+		Expect(reply.RemainingSeconds).To(Equal(int64(90)))
+		Expect(reply.ListOfUpgradeStepStatuses[0].Timing).To(Equal(&pb.StepTiming{
+			StartTime:      start.Unix(),
+			EndTime:        now.Unix(),
+			ElapsedSeconds: 30,
+			UsualSeconds:   15,
+			EarlierRuns:    2,
+		}))
+		Expect(reply.ListOfUpgradeStepStatuses[1].Timing).To(Equal(&pb.StepTiming{
+			RemainingSeconds: 90,
+			UsualSeconds:     90,
+			EarlierRuns:      1,
+		}))
+
+		all, err := runs.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(all).To(HaveLen(4))
+		Expect(all[3].Steps[upgradestatus.CONFIG]).To(Equal(&upgradestatus.StepTiming{
+			Start:    start,
+			End:      now,
+			Complete: true,
+		}))
+	})
+
+	It("cannot estimate a step that has never completed on a cluster of the same size", func() {
+		earlierRun(segments+1, map[string]time.Duration{
+			upgradestatus.CONFIG:     10 * time.Second,
+			upgradestatus.SEGINSTALL: 90 * time.Second,
+		})
+
+		reply := status()
+		Expect(reply.RemainingSeconds).To(Equal(int64(-1)))
+		for _, step := range reply.ListOfUpgradeStepStatuses {
+			Expect(step.Timing.RemainingSeconds).To(Equal(int64(-1)))
+			Expect(step.Timing.EarlierRuns).To(BeZero())
+		}
+	})
+
+	It("times convert-primaries by the conversions that the agents report", func() {
+		start := now.Add(-time.Hour)
+		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
+			Statuses: []*pb.ConversionStatus{
+				{Dbid: 1, Role: pb.SegmentRole_MASTER, Status: pb.StepStatus_COMPLETE, StartTime: start.Add(-time.Hour).Unix()},
+				{Dbid: 2, Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_COMPLETE, StartTime: start.Unix(), EndTime: start.Add(time.Minute).Unix()},
+				{Dbid: 3, Role: pb.SegmentRole_PRIMARY, Status: pb.StepStatus_RUNNING, StartTime: start.Add(time.Second).Unix()},
+			},
+		}
+		hub = services.NewHub(source, target, grpc.DialContext, hubConf, cm)
+		defer hub.Stop()
+
+		_, err := hub.StatusConversion(nil, &pb.StatusConversionRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		all, err := runs.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(all).To(HaveLen(1))
+		Expect(all[0].Conversions).To(HaveLen(2))
+		Expect(all[0].Conversions[2]).To(Equal(&upgradestatus.StepTiming{
+			Start:    start,
+			End:      start.Add(time.Minute),
+			Complete: true,
+		}))
+		Expect(all[0].Steps[upgradestatus.CONVERT_PRIMARIES]).To(Equal(&upgradestatus.StepTiming{Start: start}))
+
+		mockAgent.StatusConversionResponse.Statuses[2].Status = pb.StepStatus_COMPLETE
+		mockAgent.StatusConversionResponse.Statuses[2].EndTime = start.Add(2 * time.Minute).Unix()
+		_, err = hub.StatusConversion(nil, &pb.StatusConversionRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		all, err = runs.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(all[0].Steps[upgradestatus.CONVERT_PRIMARIES]).To(Equal(&upgradestatus.StepTiming{
+			Start:    start,
+			End:      start.Add(2 * time.Minute),
+			Complete: true,
+		}))
+	})
+})
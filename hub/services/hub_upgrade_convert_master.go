@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
 
@@ -14,6 +15,16 @@ import (
 
 func (h *Hub) UpgradeConvertMaster(ctx context.Context, in *pb.UpgradeConvertMasterRequest) (*pb.UpgradeConvertMasterReply, error) {
 	gplog.Info("Starting master upgrade")
+
+	if err := h.checkPrerequisites(upgradestatus.CONVERT_MASTER, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeConvertMasterReply{}, err
+	}
+	if in.DryRun {
+		plan, err := h.planConvertMaster()
+		return &pb.UpgradeConvertMasterReply{Plan: plan}, err
+	}
+
 	//need to remember where we ran, i.e. pathToUpgradeWD, b/c pg_upgrade generates some files that need to be copied to QE nodes later
 	//this is also where the 1.done, 2.inprogress ... files will be written
 	err := h.ConvertMaster()
@@ -26,8 +37,15 @@ func (h *Hub) UpgradeConvertMaster(ctx context.Context, in *pb.UpgradeConvertMas
 }
 
 func (h *Hub) ConvertMaster() error {
-	upgradeFileName := "pg_upgrade"
-	pathToUpgradeWD := filepath.Join(h.conf.StateDir, upgradeFileName)
+	if h.target.LinkMode() {
+		err := utils.CheckSameFilesystem(h.source.MasterDataDir(), h.target.MasterDataDir())
+		if err != nil {
+			gplog.Error(err.Error())
+			return err
+		}
+	}
+
+	pathToUpgradeWD := h.pgUpgradeWorkingDir()
 	err := utils.System.MkdirAll(pathToUpgradeWD, 0700)
 	if err != nil {
 		errMsg := fmt.Sprintf("mkdir %s failed: %v. Is there an pg_upgrade in progress?", pathToUpgradeWD, err)
@@ -36,26 +54,94 @@ func (h *Hub) ConvertMaster() error {
 	}
 
 	pgUpgradeLog := filepath.Join(pathToUpgradeWD, "/pg_upgrade_master.log")
-	upgradeCmd := fmt.Sprintf("unset PGHOST; unset PGPORT; cd %s && nohup %s "+
-		"--old-bindir=%s --old-datadir=%s --old-port=%d "+
-		"--new-bindir=%s --new-datadir=%s --new-port=%d "+
-		"--dispatcher-mode --progress",
-		pathToUpgradeWD, filepath.Join(h.target.BinDir, "pg_upgrade"),
-		h.source.BinDir,
-		h.source.MasterDataDir(),
-		h.source.MasterPort(),
-		h.target.BinDir,
-		h.target.MasterDataDir(),
-		h.target.MasterPort())
+	upgradeCmd := h.convertMasterCommand()
 
 	gplog.Info("Convert Master upgrade command: %#v", upgradeCmd)
 
+	// Forget why an earlier attempt failed, such as that it was cancelled.
+	err = h.checklist.ResetStep(upgradestatus.CONVERT_MASTER)
+	if err != nil {
+		return errors.Wrap(err, "Could not reset the status of the master upgrade")
+	}
+	err = utils.RemoveProgressFiles(pathToUpgradeWD)
+	if err != nil {
+		return errors.Wrap(err, "Could not remove the progress files of an earlier master upgrade")
+	}
+
+	err = h.recordLinkModeStarted()
+	if err != nil {
+		gplog.Error(err.Error())
+		return err
+	}
+
 	//export ENV VARS instead of passing on cmd line?
-	err = utils.System.RunCommandAsync(upgradeCmd, pgUpgradeLog)
+	pid, err := utils.System.RunCommandAsync(upgradeCmd, pgUpgradeLog)
 	if err != nil {
 		gplog.Error("Error when starting the upgrade: %s", err)
 		return err
 	}
-	gplog.Info("Found no errors when starting the upgrade")
+	gplog.Info("Found no errors when starting the upgrade (pid %d)", pid)
+
+	err = utils.WritePidFile(filepath.Join(pathToUpgradeWD, utils.PgUpgradePidFile), pid)
+	if err != nil {
+		gplog.Error("Could not record the pid of pg_upgrade, so it cannot be cancelled: %s", err)
+	}
+	h.stepStarted(upgradestatus.CONVERT_MASTER)
 	return nil
 }
+
+func (h *Hub) planConvertMaster() (*pb.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+	if err := h.requireTarget(); err != nil {
+		return nil, err
+	}
+
+	pathToUpgradeWD := h.pgUpgradeWorkingDir()
+	pgUpgradeLog := filepath.Join(pathToUpgradeWD, "pg_upgrade_master.log")
+	plan := &pb.DryRunPlan{Commands: []*pb.PlannedCommand{
+		localCommand(h.source, "mkdir -p "+pathToUpgradeWD),
+		localCommand(h.source, utils.AsyncCommandString(h.convertMasterCommand(), pgUpgradeLog)),
+	}}
+	if h.target.LinkMode() {
+		plan.Notes = append(plan.Notes, "link mode: the source cluster cannot be used, or reverted to, once the target cluster has started")
+	}
+	return plan, nil
+}
+
+// MasterConversionStatus is the status of the CONVERT_MASTER step, which comes
+// from the progress files that pg_upgrade leaves in its working directory.
+func MasterConversionStatus(hub *Hub) pb.StepStatus {
+	return upgradestatus.SegmentConversionStatus(hub.pgUpgradeWorkingDir(), hub.source.MasterDataDir(), hub.source.Executor)
+}
+
+// pgUpgradeWorkingDir is where pg_upgrade runs on the master, and so where it
+// leaves its progress files and the OID files that are shared with segments.
+func (h *Hub) pgUpgradeWorkingDir() string {
+	return filepath.Join(h.conf.StateDir, "pg_upgrade")
+}
+
+func (h *Hub) convertMasterCommand() string {
+	return fmt.Sprintf("unset PGHOST; unset PGPORT; cd %s && nohup %s "+
+		"--old-bindir=%s --old-datadir=%s --old-port=%d "+
+		"--new-bindir=%s --new-datadir=%s --new-port=%d "+
+		"--dispatcher-mode --progress%s",
+		h.pgUpgradeWorkingDir(), filepath.Join(h.target.BinDir, "pg_upgrade"),
+		h.source.BinDir,
+		h.source.MasterDataDir(),
+		h.source.MasterPort(),
+		h.target.BinDir,
+		h.target.MasterDataDir(),
+		h.target.MasterPort(),
+		linkFlag(h.target.Mode))
+}
+
+// linkFlag is the pg_upgrade option, with a leading space, for the given
+// mode.
+func linkFlag(mode string) string {
+	if mode == utils.LINK_MODE {
+		return " --link"
+	}
+	return ""
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	"github.com/greenplum-db/gpupgrade/utils"
 
 	. "github.com/onsi/ginkgo"
@@ -18,9 +20,10 @@ var _ = Describe("ConvertMasterHub", func() {
 	)
 
 	BeforeEach(func() {
-		utils.System.RunCommandAsync = func(cmdStr string, logFile string) error {
+		actualCmdStr = ""
+		utils.System.RunCommandAsync = func(cmdStr string, logFile string) (int, error) {
 			actualCmdStr = cmdStr
-			return nil
+			return 4242, nil
 		}
 	})
 
@@ -33,11 +36,55 @@ var _ = Describe("ConvertMasterHub", func() {
 			fmt.Sprintf("--old-bindir=/source/bindir --old-datadir=%s/seg-1 --old-port=15432 ", dir) +
 			fmt.Sprintf("--new-bindir=/target/bindir --new-datadir=%s/seg-1 --new-port=15432 ", dir) +
 			"--dispatcher-mode --progress"))
+
+		pid, err := utils.ReadPidFile(filepath.Join(pgupgrade_dir, utils.PgUpgradePidFile))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pid).To(Equal(4242))
+	})
+
+	It("forgets why an earlier attempt failed", func() {
+		step := cm.GetStepWriter(upgradestatus.CONVERT_MASTER)
+		step.MarkInProgress()
+		step.MarkFailed(services.ErrStepCancelled)
+
+		err := hub.ConvertMaster()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cm.WasReset(upgradestatus.CONVERT_MASTER)).To(BeTrue())
+		Expect(cm.FailureError(upgradestatus.CONVERT_MASTER)).To(BeNil())
+	})
+
+	It("runs pg_upgrade in link mode", func() {
+		target.Mode = utils.LINK_MODE
+		Expect(os.Mkdir(filepath.Join(dir, "seg-1"), 0700)).To(Succeed())
+
+		err := hub.ConvertMaster()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actualCmdStr).To(HaveSuffix("--dispatcher-mode --progress --link"))
+		Expect(filepath.Join(dir, "link-mode-started")).To(BeAnExistingFile())
+	})
+
+	It("does not record a copy-mode upgrade as a link-mode one", func() {
+		err := hub.ConvertMaster()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filepath.Join(dir, "link-mode-started")).ToNot(BeAnExistingFile())
+	})
+
+	It("refuses link mode when the target master is on another filesystem", func() {
+		target.Mode = utils.LINK_MODE
+		Expect(os.Mkdir(filepath.Join(dir, "seg-1"), 0700)).To(Succeed())
+		master := target.Segments[-1]
+		master.DataDir = "/proc"
+		target.Segments[-1] = master
+
+		err := hub.ConvertMaster()
+		Expect(err).To(MatchError(fmt.Sprintf("link mode needs source data directory %s/seg-1 and target data directory /proc on the same filesystem", dir)))
+		Expect(actualCmdStr).To(BeEmpty())
 	})
 
 	It("returns an error when convert master fails", func() {
-		utils.System.RunCommandAsync = func(cmdStr string, logFile string) error {
-			return errors.New("upgrade failed")
+		utils.System.RunCommandAsync = func(cmdStr string, logFile string) (int, error) {
+			return 0, errors.New("upgrade failed")
 		}
 
 		err := hub.ConvertMaster()
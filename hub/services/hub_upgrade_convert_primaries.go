@@ -3,21 +3,20 @@ package services
 import (
 	"fmt"
 	"sort"
-	"sync"
 
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
 
 func (h *Hub) UpgradeConvertPrimaries(ctx context.Context, in *pb.UpgradeConvertPrimariesRequest) (*pb.UpgradeConvertPrimariesReply, error) {
-	conns, err := h.AgentConns()
-	if err != nil {
-		gplog.Error("Error connecting to the agents. Err: %v", err)
+	if err := h.checkPrerequisites(upgradestatus.CONVERT_PRIMARIES, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
 		return &pb.UpgradeConvertPrimariesReply{}, err
 	}
-	agentErrs := make(chan error, len(conns))
 
 	dataDirPair, err := h.getDataDirPairs()
 	if err != nil {
@@ -25,34 +24,80 @@ func (h *Hub) UpgradeConvertPrimaries(ctx context.Context, in *pb.UpgradeConvert
 		return &pb.UpgradeConvertPrimariesReply{}, err
 	}
 
-	wg := sync.WaitGroup{}
-	for _, conn := range conns {
-		wg.Add(1)
-		go func(c *Connection) {
-			defer wg.Done()
-
-			_, err := pb.NewAgentClient(c.Conn).UpgradeConvertPrimarySegments(context.Background(), &pb.UpgradeConvertPrimarySegmentsRequest{
-				OldBinDir:    h.source.BinDir,
-				NewBinDir:    h.target.BinDir,
-				DataDirPairs: dataDirPair[c.Hostname],
-			})
-
-			if err != nil {
-				gplog.Error("Hub Upgrade Convert Primaries failed to call agent %s with error: ", c.Hostname, err)
-				agentErrs <- err
-			}
-		}(conn)
+	var hosts []string
+	for host := range dataDirPair {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	fanOut, conns := h.agentFanOut(hosts...)
+
+	if !in.DryRun {
+		// Forget why an earlier attempt failed, such as that it was cancelled.
+		err = h.checklist.ResetStep(upgradestatus.CONVERT_PRIMARIES)
+		if err != nil {
+			return &pb.UpgradeConvertPrimariesReply{}, errors.Wrap(err, "could not reset the status of the primary upgrade")
+		}
+
+		err = h.recordLinkModeStarted()
+		if err != nil {
+			gplog.Error(err.Error())
+			return &pb.UpgradeConvertPrimariesReply{}, err
+		}
 	}
 
-	wg.Wait()
+	results := fanOut.Call(Clients(conns), func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
+		return client.Client.UpgradeConvertPrimarySegments(ctx, &pb.UpgradeConvertPrimarySegmentsRequest{
+			OldBinDir:    h.source.BinDir,
+			NewBinDir:    h.target.BinDir,
+			DataDirPairs: dataDirPair[client.Hostname],
+			DryRun:       in.DryRun,
+			Mode:         h.target.Mode,
+		})
+	})
 
-	if len(agentErrs) != 0 {
-		err = fmt.Errorf("%d agents failed to start pg_upgrade on the primaries. See logs for additional details", len(agentErrs))
+	err = results.Err()
+	if err != nil {
+		gplog.Error("Hub Upgrade Convert Primaries failed to call agents: %s", err)
+		err = errors.Wrap(err, "agents failed to start pg_upgrade on the primaries")
 	}
 
+	agentPlans := make([]*pb.DryRunPlan, len(conns))
+	for i, conn := range conns {
+		if result := results[conn.Hostname]; result.Err == nil {
+			reply := result.Reply.(*pb.UpgradeConvertPrimarySegmentsReply)
+			agentPlans[i] = onHost(reply.GetPlan(), conn.Hostname)
+			for _, process := range reply.GetProcesses() {
+				gplog.Info("Started pg_upgrade for segment %d on %s (pid %d)", process.Content, conn.Hostname, process.Pid)
+			}
+		}
+	}
+
+	if in.DryRun {
+		return &pb.UpgradeConvertPrimariesReply{Plan: mergePlans(agentPlans)}, err
+	}
+	if err == nil {
+		h.stepStarted(upgradestatus.CONVERT_PRIMARIES)
+	}
 	return &pb.UpgradeConvertPrimariesReply{}, err
 }
 
+// planConvertPrimaries asks every agent what it would run, like
+// UpgradeConvertPrimaries does for a dry run.
+func (h *Hub) planConvertPrimaries() (*pb.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+	if err := h.requireTarget(); err != nil {
+		return nil, err
+	}
+
+	reply, err := h.UpgradeConvertPrimaries(context.Background(), &pb.UpgradeConvertPrimariesRequest{
+		SkipPrerequisites: true,
+		DryRun:            true,
+	})
+	return reply.GetPlan(), err
+}
+
 func (h *Hub) getDataDirPairs() (map[string][]*pb.DataDirPair, error) {
 	dataDirPairMap := make(map[string][]*pb.DataDirPair)
 	oldContents := h.source.ContentIDs
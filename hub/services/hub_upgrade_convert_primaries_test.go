@@ -4,7 +4,10 @@ import (
 	"errors"
 	"path/filepath"
 
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/greenplum-db/gp-common-go-libs/cluster"
 
@@ -35,6 +38,24 @@ var _ = Describe("hub.UpgradeConvertPrimaries()", func() {
 		}))
 	})
 
+	It("passes the upgrade mode to the agents", func() {
+		target.Mode = utils.LINK_MODE
+
+		_, err := hub.UpgradeConvertPrimaries(nil, &pb.UpgradeConvertPrimariesRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mockAgent.UpgradeConvertPrimarySegmentsRequest.Mode).To(Equal(utils.LINK_MODE))
+		Expect(filepath.Join(dir, "link-mode-started")).To(BeAnExistingFile())
+	})
+
+	It("does not record a dry run as a link-mode upgrade", func() {
+		target.Mode = utils.LINK_MODE
+
+		_, err := hub.UpgradeConvertPrimaries(nil, &pb.UpgradeConvertPrimariesRequest{DryRun: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filepath.Join(dir, "link-mode-started")).ToNot(BeAnExistingFile())
+	})
+
 	It("returns an error if new config does not contain all the same content as the old config", func() {
 		target.Cluster = &cluster.Cluster{
 			ContentIDs: []int{0},
@@ -70,6 +91,18 @@ var _ = Describe("hub.UpgradeConvertPrimaries()", func() {
 
 		Expect(mockAgent.NumberOfCalls()).To(Equal(1))
 	})
+
+	It("forgets why an earlier attempt failed", func() {
+		step := cm.GetStepWriter(upgradestatus.CONVERT_PRIMARIES)
+		step.MarkInProgress()
+		step.MarkFailed(services.ErrStepCancelled)
+
+		_, err := hub.UpgradeConvertPrimaries(nil, &pb.UpgradeConvertPrimariesRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(cm.WasReset(upgradestatus.CONVERT_PRIMARIES)).To(BeTrue())
+		Expect(cm.FailureError(upgradestatus.CONVERT_PRIMARIES)).To(BeNil())
+	})
 })
 
 func newSegment(content int, hostname, dataDir string, port int) cluster.SegConfig {
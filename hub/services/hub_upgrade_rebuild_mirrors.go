@@ -0,0 +1,211 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// rebuildExcludes are the files in a data directory that belong to that
+// particular segment rather than to its contents, and so are kept on the
+// mirror or standby when it is overwritten with a copy of its primary.
+var rebuildExcludes = []string{
+	"postgresql.conf",
+	"postmaster.opts",
+	"postmaster.pid",
+	"internal.auto.conf",
+	"gp_dbid",
+	"recovery.conf",
+	"pg_log",
+}
+
+func (h *Hub) UpgradeRebuildMirrors(ctx context.Context, in *pb.UpgradeRebuildMirrorsRequest) (*pb.UpgradeRebuildMirrorsReply, error) {
+	gplog.Info("Started processing rebuild-mirrors request")
+
+	if err := h.checkPrerequisites(upgradestatus.REBUILD_MIRRORS, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeRebuildMirrorsReply{}, err
+	}
+
+	if in.DryRun {
+		plan, err := h.planRebuildMirrors()
+		return &pb.UpgradeRebuildMirrorsReply{Plan: plan}, err
+	}
+
+	h.tasks.Go(upgradestatus.REBUILD_MIRRORS, h.rebuildMirrors)
+
+	return &pb.UpgradeRebuildMirrorsReply{}, nil
+}
+
+// rebuildMirrors overwrites each mirror of the target cluster with a copy of
+// its upgraded primary. gpinitsystem created the mirrors alongside the
+// primaries, but pg_upgrade only touches the primaries, so until this step has
+// run the mirrors still hold the empty cluster that gpinitsystem made.
+func (h *Hub) rebuildMirrors() {
+	step := h.checklist.GetStepWriter(upgradestatus.REBUILD_MIRRORS)
+
+	err := initializeState(step)
+	if err != nil {
+		gplog.Error(err.Error())
+		return
+	}
+
+	rebuilds, err := h.mirrorRebuilds()
+	if err != nil {
+		gplog.Error(err.Error())
+		markFailed(step, upgradestatus.REBUILD_MIRRORS, err)
+		return
+	}
+	if len(rebuilds) == 0 {
+		gplog.Info("the source cluster has no mirrors; there is nothing to rebuild")
+	}
+
+	errs := h.runRebuilds(rebuilds)
+
+	// The step's failure is reported with the first command that failed.
+	var firstErr error
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		markFailed(step, upgradestatus.REBUILD_MIRRORS, errors.Wrapf(firstErr, "could not rebuild %d of %d mirrors", failed, len(rebuilds)))
+		return
+	}
+
+	err = step.MarkComplete()
+	if err != nil {
+		gplog.Error("failed to record completed for %s: %s", upgradestatus.REBUILD_MIRRORS, err)
+	}
+}
+
+func (h *Hub) planRebuildMirrors() (*pb.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+	if err := h.requireTarget(); err != nil {
+		return nil, err
+	}
+
+	commands, err := h.rebuildMirrorsCommands()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &pb.DryRunPlan{Commands: commands}
+	if len(commands) == 0 {
+		plan.Notes = append(plan.Notes, "the source cluster has no mirrors, so there is nothing to rebuild")
+	}
+	return plan, nil
+}
+
+func (h *Hub) rebuildMirrorsCommands() ([]*pb.PlannedCommand, error) {
+	rebuilds, err := h.mirrorRebuilds()
+	if err != nil {
+		return nil, err
+	}
+
+	var commands []*pb.PlannedCommand
+	for _, rebuild := range rebuilds {
+		commands = append(commands, rebuild.command)
+	}
+	return commands, nil
+}
+
+// A rebuild copies a data directory from one host to another.
+type rebuild struct {
+	fromHost string
+	command  *pb.PlannedCommand
+}
+
+// mirrorRebuilds returns one rebuild per target mirror, each of which copies
+// the mirror's upgraded primary over it. The commands are run on the master,
+// and ssh to the primary's host.
+func (h *Hub) mirrorRebuilds() ([]rebuild, error) {
+	layout, err := h.targetLayout()
+	if err != nil {
+		return nil, err
+	}
+
+	var rebuilds []rebuild
+	for _, mirror := range layout.Mirrors {
+		primary, ok := h.target.Segments[mirror.ContentID]
+		if !ok {
+			return nil, fmt.Errorf("target cluster has no primary for the mirror of content %d", mirror.ContentID)
+		}
+
+		rebuilds = append(rebuilds, rebuild{
+			fromHost: primary.Hostname,
+			command: localCommand(h.source,
+				rebuildCommand(primary.Hostname, primary.DataDir, mirror.Hostname, mirror.DataDir)),
+		})
+	}
+	return rebuilds, nil
+}
+
+// runRebuilds copies from every host at once, but from one data directory at
+// a time on each host, so that no host's disks are swamped. It returns the
+// error for each rebuild, in order, which is nil for those that succeeded.
+func (h *Hub) runRebuilds(rebuilds []rebuild) []error {
+	byHost := make(map[string][]int)
+	for i, rebuild := range rebuilds {
+		byHost[rebuild.fromHost] = append(byHost[rebuild.fromHost], i)
+	}
+
+	errs := make([]error, len(rebuilds))
+	var wg sync.WaitGroup
+	for _, indexes := range byHost {
+		wg.Add(1)
+		go func(indexes []int) {
+			defer wg.Done()
+
+			for _, i := range indexes {
+				command := rebuilds[i].command.Command
+				gplog.Info("rebuild command: %+v", command)
+
+				output, err := h.source.Executor.ExecuteLocalCommand(command)
+				if err != nil {
+					gplog.Error("rebuild from %s failed %s: %s", rebuilds[i].fromHost, output, err)
+					errs[i] = commandError(command, err, h.hubLogFile())
+				}
+			}
+		}(indexes)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// rebuildCommand copies a data directory on one host over a data directory on
+// another, leaving the rebuildExcludes of the destination alone. rsync runs on
+// the source host and copies over ssh, so it relies on the passwordless ssh
+// between all of the cluster's hosts that gpssh-exkeys sets up for Greenplum.
+func rebuildCommand(fromHost, fromDir, toHost, toDir string) string {
+	rsync := []string{"rsync", "-a", "--delete"}
+	for _, exclude := range rebuildExcludes {
+		rsync = append(rsync, "--exclude="+exclude)
+	}
+	rsync = append(rsync, fromDir+"/", toHost+":"+toDir+"/")
+
+	return fmt.Sprintf("ssh -o BatchMode=yes %s '%s'", fromHost, strings.Join(rsync, " "))
+}
+
+func markFailed(step upgradestatus.StateWriter, name string, failure error) {
+	err := step.MarkFailed(failure)
+	if err != nil {
+		gplog.Error("failed to record failed for %s: %s", name, err)
+	}
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// excludes are the rsync options that keep the destination's own files.
+const excludes = "--exclude=postgresql.conf --exclude=postmaster.opts --exclude=postmaster.pid " +
+	"--exclude=internal.auto.conf --exclude=gp_dbid --exclude=recovery.conf --exclude=pg_log"
+
+var _ = Describe("UpgradeRebuildMirrors", func() {
+	var testExecutor *testhelper.TestExecutor
+
+	BeforeEach(func() {
+		testExecutor = &testhelper.TestExecutor{}
+		source.Executor = testExecutor
+
+		source.SetMirrors([]cluster.SegConfig{
+			{ContentID: -1, DbID: 4, Port: 15432, Hostname: "smdw", DataDir: filepath.Join(dir, "standby", "seg-1")},
+			{ContentID: 0, DbID: 5, Port: 35432, Hostname: "sdw2", DataDir: filepath.Join(dir, "mirror", "seg1")},
+			{ContentID: 1, DbID: 6, Port: 35433, Hostname: "sdw1", DataDir: filepath.Join(dir, "mirror", "seg2")},
+		})
+	})
+
+	It("copies each upgraded primary over its mirror", func() {
+		_, err := hub.UpgradeRebuildMirrors(nil, &pb.UpgradeRebuildMirrorsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() bool { return cm.IsComplete(upgradestatus.REBUILD_MIRRORS) }).Should(BeTrue())
+		Expect(testExecutor.LocalCommands).To(Equal([]string{
+			fmt.Sprintf("ssh -o BatchMode=yes localhost 'rsync -a --delete %s %s/seg1/ sdw2:%s/mirror_upgrade/seg1/'", excludes, dir, dir),
+			fmt.Sprintf("ssh -o BatchMode=yes localhost 'rsync -a --delete %s %s/seg2/ sdw1:%s/mirror_upgrade/seg2/'", excludes, dir, dir),
+		}))
+	})
+
+	It("marks the step failed if any mirror could not be copied", func() {
+		testExecutor.LocalError = errors.New("connection refused")
+
+		_, err := hub.UpgradeRebuildMirrors(nil, &pb.UpgradeRebuildMirrorsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() bool { return cm.IsFailed(upgradestatus.REBUILD_MIRRORS) }).Should(BeTrue())
+		Expect(testExecutor.NumExecutions).To(Equal(2))
+	})
+
+	It("completes without doing anything when there are no mirrors", func() {
+		source.SetMirrors(nil)
+
+		_, err := hub.UpgradeRebuildMirrors(nil, &pb.UpgradeRebuildMirrorsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() bool { return cm.IsComplete(upgradestatus.REBUILD_MIRRORS) }).Should(BeTrue())
+		Expect(testExecutor.NumExecutions).To(Equal(0))
+	})
+
+	It("copies from every primary host at once", func() {
+		primary := target.Segments[1]
+		primary.Hostname = "sdw3"
+		target.Segments[1] = primary
+
+		executor := &blockingExecutor{release: make(chan struct{})}
+		source.Executor = executor
+		defer close(executor.release)
+
+		_, err := hub.UpgradeRebuildMirrors(nil, &pb.UpgradeRebuildMirrorsRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(executor.Commands).Should(ConsistOf(
+			fmt.Sprintf("ssh -o BatchMode=yes localhost 'rsync -a --delete %s %s/seg1/ sdw2:%s/mirror_upgrade/seg1/'", excludes, dir, dir),
+			fmt.Sprintf("ssh -o BatchMode=yes sdw3 'rsync -a --delete %s %s/seg2/ sdw1:%s/mirror_upgrade/seg2/'", excludes, dir, dir),
+		))
+	})
+})
+
+// blockingExecutor records each command that is started, and holds it until
+// release is closed.
+type blockingExecutor struct {
+	mu       sync.Mutex
+	commands []string
+	release  chan struct{}
+}
+
+func (e *blockingExecutor) ExecuteLocalCommand(command string) (string, error) {
+	e.mu.Lock()
+	e.commands = append(e.commands, command)
+	e.mu.Unlock()
+
+	<-e.release
+	return "", nil
+}
+
+func (e *blockingExecutor) ExecuteClusterCommand(scope int, commandList map[int][]string) *cluster.RemoteOutput {
+	return nil
+}
+
+func (e *blockingExecutor) Commands() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.commands...)
+}
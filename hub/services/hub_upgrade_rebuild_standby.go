@@ -0,0 +1,94 @@
+package services
+
+import (
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/net/context"
+)
+
+func (h *Hub) UpgradeRebuildStandby(ctx context.Context, in *pb.UpgradeRebuildStandbyRequest) (*pb.UpgradeRebuildStandbyReply, error) {
+	gplog.Info("Started processing rebuild-standby request")
+
+	if err := h.checkPrerequisites(upgradestatus.REBUILD_STANDBY, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeRebuildStandbyReply{}, err
+	}
+
+	if in.DryRun {
+		plan, err := h.planRebuildStandby()
+		return &pb.UpgradeRebuildStandbyReply{Plan: plan}, err
+	}
+
+	h.tasks.Go(upgradestatus.REBUILD_STANDBY, h.rebuildStandby)
+
+	return &pb.UpgradeRebuildStandbyReply{}, nil
+}
+
+// rebuildStandby overwrites the target cluster's standby master with a copy of
+// the upgraded master, in the same way that rebuildMirrors does for the
+// mirrors.
+func (h *Hub) rebuildStandby() {
+	step := h.checklist.GetStepWriter(upgradestatus.REBUILD_STANDBY)
+
+	err := initializeState(step)
+	if err != nil {
+		gplog.Error(err.Error())
+		return
+	}
+
+	command, ok, err := h.rebuildStandbyCommand()
+	if err != nil {
+		gplog.Error(err.Error())
+		markFailed(step, upgradestatus.REBUILD_STANDBY, err)
+		return
+	} else if !ok {
+		gplog.Info("the source cluster has no standby master; there is nothing to rebuild")
+	} else {
+		gplog.Info("rebuild standby command: %+v", command.Command)
+
+		output, err := h.source.Executor.ExecuteLocalCommand(command.Command)
+		if err != nil {
+			gplog.Error("rebuilding standby failed %s: %s", output, err)
+			markFailed(step, upgradestatus.REBUILD_STANDBY, commandError(command.Command, err, h.hubLogFile()))
+			return
+		}
+	}
+
+	err = step.MarkComplete()
+	if err != nil {
+		gplog.Error("failed to record completed for %s: %s", upgradestatus.REBUILD_STANDBY, err)
+	}
+}
+
+func (h *Hub) planRebuildStandby() (*pb.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+	if err := h.requireTarget(); err != nil {
+		return nil, err
+	}
+
+	command, ok, err := h.rebuildStandbyCommand()
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return &pb.DryRunPlan{Notes: []string{"the source cluster has no standby master, so there is nothing to rebuild"}}, nil
+	}
+	return &pb.DryRunPlan{Commands: []*pb.PlannedCommand{command}}, nil
+}
+
+func (h *Hub) rebuildStandbyCommand() (*pb.PlannedCommand, bool, error) {
+	layout, err := h.targetLayout()
+	if err != nil {
+		return nil, false, err
+	}
+	standby := layout.Standby
+	if standby == nil {
+		return nil, false, nil
+	}
+
+	return localCommand(h.source,
+		rebuildCommand(h.target.MasterHostname(), h.target.MasterDataDir(), standby.Hostname, standby.DataDir)), true, nil
+}
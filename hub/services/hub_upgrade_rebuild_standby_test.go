@@ -0,0 +1,79 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpgradeRebuildStandby", func() {
+	var testExecutor *testhelper.TestExecutor
+
+	BeforeEach(func() {
+		testExecutor = &testhelper.TestExecutor{}
+		source.Executor = testExecutor
+
+		source.SetMirrors([]cluster.SegConfig{
+			{ContentID: -1, DbID: 4, Port: 15432, Hostname: "smdw", DataDir: filepath.Join(dir, "standby", "seg-1")},
+		})
+	})
+
+	It("copies the upgraded master over the standby", func() {
+		_, err := hub.UpgradeRebuildStandby(nil, &pb.UpgradeRebuildStandbyRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() bool { return cm.IsComplete(upgradestatus.REBUILD_STANDBY) }).Should(BeTrue())
+		Expect(testExecutor.LocalCommands).To(Equal([]string{
+			fmt.Sprintf("ssh -o BatchMode=yes localhost 'rsync -a --delete %s %s/seg-1/ smdw:%s/standby_upgrade/seg-1/'", excludes, dir, dir),
+		}))
+	})
+
+	It("marks the step failed, with the command that failed, if the standby could not be copied", func() {
+		testExecutor.LocalError = errors.New("connection refused")
+
+		_, err := hub.UpgradeRebuildStandby(nil, &pb.UpgradeRebuildStandbyRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() bool { return cm.IsFailed(upgradestatus.REBUILD_STANDBY) }).Should(BeTrue())
+		failure := cm.GetStepReader(upgradestatus.REBUILD_STANDBY).(upgradestatus.FailureReader).Failure()
+		Expect(failure.Command).To(ContainSubstring("rsync"))
+	})
+
+	It("completes without doing anything when there is no standby", func() {
+		source.SetMirrors(nil)
+
+		_, err := hub.UpgradeRebuildStandby(nil, &pb.UpgradeRebuildStandbyRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() bool { return cm.IsComplete(upgradestatus.REBUILD_STANDBY) }).Should(BeTrue())
+		Expect(testExecutor.NumExecutions).To(Equal(0))
+	})
+
+	It("plans to copy the master over the standby", func() {
+		reply, err := hub.UpgradeRebuildStandby(nil, &pb.UpgradeRebuildStandbyRequest{DryRun: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reply.Plan.Commands).To(Equal([]*pb.PlannedCommand{{
+			Hostname: "localhost",
+			Command:  fmt.Sprintf("ssh -o BatchMode=yes localhost 'rsync -a --delete %s %s/seg-1/ smdw:%s/standby_upgrade/seg-1/'", excludes, dir, dir),
+		}}))
+		Expect(testExecutor.NumExecutions).To(Equal(0))
+	})
+
+	It("only notes that there is nothing to do for a dry run without a standby", func() {
+		source.SetMirrors(nil)
+
+		reply, err := hub.UpgradeRebuildStandby(nil, &pb.UpgradeRebuildStandbyRequest{DryRun: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reply.Plan.Commands).To(BeEmpty())
+		Expect(reply.Plan.Notes).To(ConsistOf(ContainSubstring("no standby master")))
+	})
+})
@@ -19,6 +19,16 @@ const (
 func (h *Hub) UpgradeReconfigurePorts(ctx context.Context, in *pb.UpgradeReconfigurePortsRequest) (*pb.UpgradeReconfigurePortsReply, error) {
 	gplog.Info("Started processing reconfigure-ports request")
 
+	if err := h.checkPrerequisites(upgradestatus.RECONFIGURE_PORTS, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeReconfigurePortsReply{}, err
+	}
+
+	if in.DryRun {
+		plan, err := h.planReconfigurePorts()
+		return &pb.UpgradeReconfigurePortsReply{Plan: plan}, err
+	}
+
 	step := h.checklist.GetStepWriter(upgradestatus.RECONFIGURE_PORTS)
 
 	err := step.ResetStateDir()
@@ -30,17 +40,14 @@ func (h *Hub) UpgradeReconfigurePorts(ctx context.Context, in *pb.UpgradeReconfi
 		gplog.Error("error from MarkInProgress " + err.Error())
 	}
 
-	sourcePort := h.source.MasterPort()
-	targetPort := h.target.MasterPort()
-	targetDataDir := h.target.MasterDataDir()
-	sedCommand := fmt.Sprintf(SedAndMvString, targetPort, sourcePort, targetDataDir)
+	sedCommand := h.reconfigurePortsCommand()
 	gplog.Info("reconfigure-ports sed command: %+v", sedCommand)
 
 	output, err := h.source.Executor.ExecuteLocalCommand(sedCommand)
 	if err != nil {
 		gplog.Error("reconfigure-ports failed %s: %s", output, err)
 
-		step.MarkFailed()
+		step.MarkFailed(commandError(sedCommand, err, h.hubLogFile()))
 		return nil, err
 	}
 
@@ -49,3 +56,22 @@ func (h *Hub) UpgradeReconfigurePorts(ctx context.Context, in *pb.UpgradeReconfi
 
 	return &pb.UpgradeReconfigurePortsReply{}, nil
 }
+
+func (h *Hub) planReconfigurePorts() (*pb.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+	if err := h.requireTarget(); err != nil {
+		return nil, err
+	}
+
+	return &pb.DryRunPlan{Commands: []*pb.PlannedCommand{
+		localCommand(h.source, h.reconfigurePortsCommand()),
+	}}, nil
+}
+
+// reconfigurePortsCommand moves the target master onto the source master's
+// port, keeping the original postgresql.conf so that revert can restore it.
+func (h *Hub) reconfigurePortsCommand() string {
+	return fmt.Sprintf(SedAndMvString, h.target.MasterPort(), h.source.MasterPort(), h.target.MasterDataDir())
+}
@@ -1,10 +1,12 @@
 package services
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 
@@ -15,7 +17,17 @@ import (
 func (h *Hub) UpgradeShareOids(ctx context.Context, in *pb.UpgradeShareOidsRequest) (*pb.UpgradeShareOidsReply, error) {
 	gplog.Info("Started processing share-oids request")
 
-	go h.shareOidFiles()
+	if err := h.checkPrerequisites(upgradestatus.SHARE_OIDS, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeShareOidsReply{}, err
+	}
+
+	if in.DryRun {
+		plan, err := h.planShareOids()
+		return &pb.UpgradeShareOidsReply{Plan: plan}, err
+	}
+
+	h.tasks.Go(upgradestatus.SHARE_OIDS, h.shareOidFiles)
 
 	return &pb.UpgradeShareOidsReply{}, nil
 }
@@ -34,35 +46,88 @@ func (h *Hub) shareOidFiles() {
 		return
 	}
 
-	hostnames := h.source.GetHostnames()
+	err = h.sendOidFiles(step)
+	if err != nil {
+		gplog.Error("share oids failed: %s", err)
+		failErr := step.MarkFailed(err)
+		if failErr != nil {
+			gplog.Error("error from MarkFailed " + failErr.Error())
+		}
+		return
+	}
 
-	user := "gpadmin"
-	rsyncFlags := "-rzpogt"
-	sourceDir := filepath.Join(h.conf.StateDir, "pg_upgrade")
+	err = step.MarkComplete()
+	if err != nil {
+		gplog.Error("error from MarkComplete " + err.Error())
+	}
+}
 
-	anyFailed := false
-	for _, host := range hostnames {
-		destinationDirectory := user + "@" + host + ":" + filepath.Join(h.conf.StateDir, "pg_upgrade")
+// sendOidFiles copies the OID files that pg_upgrade wrote on the master to
+// every host at once, through the agents, and records how it went on each.
+func (h *Hub) sendOidFiles(step upgradestatus.StateWriter) error {
+	files, err := h.oidFiles()
+	if err != nil {
+		return err
+	}
 
-		rsyncCommand := strings.Join([]string{"rsync", rsyncFlags, filepath.Join(sourceDir, "pg_upgrade_dump_*_oids.sql"), destinationDirectory}, " ")
-		gplog.Info("share oids command: %+v", rsyncCommand)
+	hosts := sortedHostnames(h.source)
+	fanOut, conns := h.agentFanOut(hosts...)
+	results := fanOut.Call(Clients(conns), func(ctx context.Context, client ClientAndHostname) (interface{}, error) {
+		return nil, SendFiles(ctx, client.Client, files)
+	})
 
-		output, err := h.source.Executor.ExecuteLocalCommand(rsyncCommand)
-		if err != nil {
-			gplog.Error("share oids failed %s: %s", output, err)
-			anyFailed = true
+	for _, host := range hosts {
+		if result := results[host]; result.Err != nil {
+			gplog.Error("could not copy the OID files to %s: %s", host, result.Err)
+			err = step.MarkHostFailed(host, result.Err.Error())
+		} else {
+			err = step.MarkHostComplete(host)
 		}
-	}
-	if anyFailed {
-		step.MarkFailed()
 		if err != nil {
-			gplog.Error("error from MarkFailed " + err.Error())
-		}
-	} else {
-		step.MarkComplete()
-		if err != nil {
-			gplog.Error("error from MarkComplete " + err.Error())
+			gplog.Error("could not record the result for %s: %s", host, err)
 		}
 	}
 
+	if failed := results.Failed(); len(failed) > 0 {
+		return fmt.Errorf("could not copy the OID files to %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// oidFiles are the files that pg_upgrade wrote on the master, destined for the
+// same place in each agent's state directory.
+func (h *Hub) oidFiles() ([]FileToSend, error) {
+	pattern := filepath.Join(h.conf.StateDir, "pg_upgrade", oidFilePattern)
+	paths, err := utils.System.FilePathGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no OID files match %s; has convert-master run?", pattern)
+	}
+
+	var files []FileToSend
+	for _, path := range paths {
+		files = append(files, FileToSend{
+			Source:      path,
+			Destination: filepath.Join("pg_upgrade", filepath.Base(path)),
+		})
+	}
+	return files, nil
+}
+
+const oidFilePattern = "pg_upgrade_dump_*_oids.sql"
+
+func (h *Hub) planShareOids() (*pb.DryRunPlan, error) {
+	if err := h.requireSource(); err != nil {
+		return nil, err
+	}
+
+	plan := &pb.DryRunPlan{}
+	sourceDir := filepath.Join(h.conf.StateDir, "pg_upgrade")
+	for _, host := range sortedHostnames(h.source) {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("send %s to the agent on %s, which writes them to %s",
+			filepath.Join(sourceDir, oidFilePattern), host, sourceDir))
+	}
+	return plan, nil
 }
@@ -3,8 +3,11 @@ package services_test
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
-	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
 	. "github.com/onsi/ginkgo"
@@ -12,42 +15,63 @@ import (
 )
 
 var _ = Describe("UpgradeShareOids", func() {
-	var (
-		testExecutor *testhelper.TestExecutor
-	)
-
 	BeforeEach(func() {
-		testExecutor = &testhelper.TestExecutor{}
-		source.Executor = testExecutor
+		oidDir := filepath.Join(dir, "pg_upgrade")
+		Expect(os.MkdirAll(oidDir, 0700)).To(Succeed())
+		for _, dbid := range []int{1, 2} {
+			path := filepath.Join(oidDir, fmt.Sprintf("pg_upgrade_dump_%d_oids.sql", dbid))
+			Expect(ioutil.WriteFile(path, []byte(fmt.Sprintf("oids for %d", dbid)), 0600)).To(Succeed())
+		}
+		// pg_upgrade leaves other files behind, which stay on the master.
+		Expect(ioutil.WriteFile(filepath.Join(oidDir, "pg_upgrade_internal.log"), []byte("log"), 0600)).To(Succeed())
 	})
 
-	It("copies files to each host", func() {
-		seg1 := source.Segments[1]
-		seg1.Hostname = "not_localhost"
-		source.Segments[1] = seg1
+	It("sends the OID files to the agent on each host", func() {
 		_, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{})
 		Expect(err).ToNot(HaveOccurred())
 
-		hostnames := source.GetHostnames()
+		Eventually(func() bool { return cm.IsComplete(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
+
+		Expect(mockAgent.ReceivedFiles).To(Equal(map[string][]byte{
+			"pg_upgrade/pg_upgrade_dump_1_oids.sql": []byte("oids for 1"),
+			"pg_upgrade/pg_upgrade_dump_2_oids.sql": []byte("oids for 2"),
+		}))
+		Expect(cm.HostStatuses(upgradestatus.SHARE_OIDS)).To(Equal([]*pb.HostStepStatus{
+			{Hostname: "localhost", Status: pb.StepStatus_COMPLETE},
+		}))
+	})
+
+	It("fails the step, and records why, if an agent cannot receive the files", func() {
+		mockAgent.Err <- errors.New("disk full")
+
+		_, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{})
 		Expect(err).ToNot(HaveOccurred())
 
-		Eventually(func() int { return testExecutor.NumExecutions }).Should(Equal(len(hostnames)))
+		Eventually(func() bool { return cm.IsFailed(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
 
-		Expect(testExecutor.LocalCommands).To(ConsistOf([]string{
-			fmt.Sprintf("rsync -rzpogt %s/pg_upgrade/pg_upgrade_dump_*_oids.sql gpadmin@localhost:%s/pg_upgrade", dir, dir),
-			fmt.Sprintf("rsync -rzpogt %s/pg_upgrade/pg_upgrade_dump_*_oids.sql gpadmin@not_localhost:%s/pg_upgrade", dir, dir),
-		}))
+		hosts := cm.HostStatuses(upgradestatus.SHARE_OIDS)
+		Expect(hosts).To(HaveLen(1))
+		Expect(hosts[0].Hostname).To(Equal("localhost"))
+		Expect(hosts[0].Status).To(Equal(pb.StepStatus_FAILED))
+		Expect(hosts[0].Error).To(ContainSubstring("disk full"))
 	})
 
-	It("copies all files even if rsync fails for a host", func() {
-		testExecutor.LocalError = errors.New("failure")
+	It("fails the step if convert-master left no OID files", func() {
+		Expect(os.RemoveAll(filepath.Join(dir, "pg_upgrade"))).To(Succeed())
 
 		_, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{})
 		Expect(err).ToNot(HaveOccurred())
 
-		hostnames := source.GetHostnames()
+		Eventually(func() bool { return cm.IsFailed(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
+		Expect(mockAgent.NumberOfCalls()).To(Equal(0))
+	})
+
+	It("plans to send the files to each host", func() {
+		reply, err := hub.UpgradeShareOids(nil, &pb.UpgradeShareOidsRequest{DryRun: true})
 		Expect(err).ToNot(HaveOccurred())
 
-		Eventually(func() int { return testExecutor.NumExecutions }).Should(Equal(len(hostnames)))
+		Expect(reply.Plan.Commands).To(BeEmpty())
+		Expect(reply.Plan.Notes).To(Equal([]string{fmt.Sprintf(
+			"send %s/pg_upgrade/pg_upgrade_dump_*_oids.sql to the agent on localhost, which writes them to %s/pg_upgrade", dir, dir)}))
 	})
 })
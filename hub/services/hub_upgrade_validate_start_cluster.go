@@ -1,8 +1,6 @@
 package services
 
 import (
-	"fmt"
-
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 
@@ -13,7 +11,17 @@ import (
 func (h *Hub) UpgradeValidateStartCluster(ctx context.Context, in *pb.UpgradeValidateStartClusterRequest) (*pb.UpgradeValidateStartClusterReply, error) {
 	gplog.Info("Started processing validate-start-cluster request")
 
-	go h.startNewCluster()
+	if err := h.checkPrerequisites(upgradestatus.VALIDATE_START_CLUSTER, in.SkipPrerequisites); err != nil {
+		gplog.Error(err.Error())
+		return &pb.UpgradeValidateStartClusterReply{}, err
+	}
+
+	if in.DryRun {
+		plan, err := h.planValidateStartCluster()
+		return &pb.UpgradeValidateStartClusterReply{Plan: plan}, err
+	}
+
+	h.tasks.Go(upgradestatus.VALIDATE_START_CLUSTER, h.startNewCluster)
 
 	return &pb.UpgradeValidateStartClusterReply{}, nil
 }
@@ -35,12 +43,11 @@ func (h *Hub) startNewCluster() {
 		return
 	}
 
-	targetBinDir := h.target.BinDir
-	targetDataDir := h.target.MasterDataDir()
-	_, err = h.target.ExecuteLocalCommand(fmt.Sprintf("source %s/../greenplum_path.sh; %s/gpstart -a -d %s", targetBinDir, targetBinDir, targetDataDir))
+	command := startClusterCommand(h.target)
+	_, err = h.target.ExecuteLocalCommand(command)
 	if err != nil {
 		gplog.Error(err.Error())
-		cmErr := step.MarkFailed()
+		cmErr := step.MarkFailed(commandError(command, err, adminLogFile("gpstart")))
 		if cmErr != nil {
 			gplog.Error("failed to record failed for validate-start-cluster")
 		}
@@ -56,3 +63,13 @@ func (h *Hub) startNewCluster() {
 
 	return
 }
+
+func (h *Hub) planValidateStartCluster() (*pb.DryRunPlan, error) {
+	if err := h.requireTarget(); err != nil {
+		return nil, err
+	}
+
+	return &pb.DryRunPlan{Commands: []*pb.PlannedCommand{
+		localCommand(h.target, startClusterCommand(h.target)),
+	}}, nil
+}
@@ -3,10 +3,12 @@ package services_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -42,4 +44,26 @@ var _ = Describe("upgrade validate start cluster", func() {
 
 		Eventually(func() bool { return cm.IsFailed(upgradestatus.VALIDATE_START_CLUSTER) }).Should(BeTrue())
 	})
+
+	It("records gpstart's command line, exit code and log along with the failure", func() {
+		testExecutor.LocalError = errors.New("exit status 2")
+		utils.System.Getenv = func(name string) string {
+			Expect(name).To(Equal("HOME"))
+			return "/home/gpadmin"
+		}
+		utils.System.Now = func() time.Time {
+			return time.Date(2018, time.March, 1, 12, 0, 0, 0, time.Local)
+		}
+
+		_, err := hub.UpgradeValidateStartCluster(nil, &pb.UpgradeValidateStartClusterRequest{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() bool { return cm.IsFailed(upgradestatus.VALIDATE_START_CLUSTER) }).Should(BeTrue())
+		Expect(cm.FailureError(upgradestatus.VALIDATE_START_CLUSTER)).To(Equal(&upgradestatus.CommandError{
+			Command:  testExecutor.LocalCommands[0],
+			ExitCode: 2,
+			LogFile:  "/home/gpadmin/gpAdminLogs/gpstart_20180301.log",
+			Err:      testExecutor.LocalError,
+		}))
+	})
 })
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/net/context"
+)
+
+// WatchPollInterval is how often WatchUpgrade rechecks the status of each step.
+// Step status is derived from state directories and running processes, so
+// there is nothing to subscribe to; polling is the only option.
+var WatchPollInterval = 1 * time.Second
+
+func (h *Hub) WatchUpgrade(in *pb.WatchUpgradeRequest, stream pb.CliToHub_WatchUpgradeServer) error {
+	gplog.Info("starting WatchUpgrade")
+
+	err := WatchSteps(stream.Context(), h.checklist.AllSteps(), stream.Send)
+	if err != nil {
+		gplog.Error("stopped watching upgrade: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// WatchSteps sends the current status of every step, and then sends a step's
+// status again each time it changes. It returns once every step is COMPLETE or
+// any step has FAILED, or when the context is done.
+func WatchSteps(ctx context.Context, steps []upgradestatus.StateReader, send func(*pb.WatchUpgradeReply) error) error {
+	last := make(map[string]pb.StepStatus, len(steps))
+
+	for {
+		finished, failed := true, false
+
+		for _, step := range steps {
+			status := step.Status()
+
+			if prev, ok := last[step.Name()]; !ok || prev != status {
+				last[step.Name()] = status
+
+				err := send(&pb.WatchUpgradeReply{
+					StepStatus: newUpgradeStepStatus(step, status),
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			switch status {
+			case pb.StepStatus_COMPLETE:
+			case pb.StepStatus_FAILED:
+				failed = true
+			default:
+				finished = false
+			}
+		}
+
+		if finished || failed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(WatchPollInterval):
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/services"
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"golang.org/x/net/context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WatchUpgrade", func() {
+	var (
+		mu     sync.Mutex
+		events []*pb.UpgradeStepStatus
+		send   func(*pb.WatchUpgradeReply) error
+	)
+
+	received := func() []*pb.UpgradeStepStatus {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*pb.UpgradeStepStatus(nil), events...)
+	}
+
+	BeforeEach(func() {
+		services.WatchPollInterval = time.Millisecond
+
+		events = nil
+		send = func(reply *pb.WatchUpgradeReply) error {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, reply.GetStepStatus())
+			return nil
+		}
+
+		cm.AddStep(upgradestatus.CONFIG, pb.UpgradeSteps_CONFIG)
+		cm.AddStep(upgradestatus.SEGINSTALL, pb.UpgradeSteps_SEGINSTALL)
+	})
+
+	AfterEach(func() {
+		services.WatchPollInterval = 1 * time.Second
+	})
+
+	It("sends every step's status, then each change, until all steps complete", func() {
+		config := &changingStep{name: upgradestatus.CONFIG, code: pb.UpgradeSteps_CONFIG, status: pb.StepStatus_COMPLETE}
+		seginstall := &changingStep{name: upgradestatus.SEGINSTALL, code: pb.UpgradeSteps_SEGINSTALL, status: pb.StepStatus_PENDING}
+		steps := []upgradestatus.StateReader{config, seginstall}
+
+		done := make(chan error)
+		go func() {
+			done <- services.WatchSteps(context.Background(), steps, send)
+		}()
+
+		Eventually(received).Should(HaveLen(2))
+		Consistently(done).ShouldNot(Receive())
+
+		seginstall.Set(pb.StepStatus_RUNNING)
+		Eventually(received).Should(HaveLen(3))
+		seginstall.Set(pb.StepStatus_COMPLETE)
+
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(received()).To(Equal([]*pb.UpgradeStepStatus{
+			{Step: pb.UpgradeSteps_CONFIG, Status: pb.StepStatus_COMPLETE},
+			{Step: pb.UpgradeSteps_SEGINSTALL, Status: pb.StepStatus_PENDING},
+			{Step: pb.UpgradeSteps_SEGINSTALL, Status: pb.StepStatus_RUNNING},
+			{Step: pb.UpgradeSteps_SEGINSTALL, Status: pb.StepStatus_COMPLETE},
+		}))
+	})
+
+	It("stops once a step has failed", func() {
+		config := cm.GetStepWriter(upgradestatus.CONFIG)
+		config.MarkInProgress()
+		config.MarkFailed(errors.New("it broke"))
+
+		err := services.WatchSteps(context.Background(), cm.AllSteps(), send)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(received()).To(Equal([]*pb.UpgradeStepStatus{
+			{Step: pb.UpgradeSteps_CONFIG, Status: pb.StepStatus_FAILED, History: []*pb.StepTransition{
+				{Status: pb.StepStatus_RUNNING},
+				{Status: pb.StepStatus_FAILED, Error: "it broke"},
+			}, Failure: &pb.StepFailure{Error: "it broke"}},
+			{Step: pb.UpgradeSteps_SEGINSTALL, Status: pb.StepStatus_PENDING},
+		}))
+	})
+
+	It("stops when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := services.WatchSteps(ctx, cm.AllSteps(), send)
+		Expect(err).To(Equal(context.Canceled))
+	})
+
+	It("returns an error if an event cannot be sent", func() {
+		err := services.WatchSteps(context.Background(), cm.AllSteps(), func(*pb.WatchUpgradeReply) error {
+			return errors.New("stream closed")
+		})
+		Expect(err).To(MatchError("stream closed"))
+	})
+})
+
+// changingStep is a StateReader whose status can be changed while it is being
+// watched from another goroutine.
+type changingStep struct {
+	name string
+	code pb.UpgradeSteps
+
+	mu     sync.Mutex
+	status pb.StepStatus
+}
+
+func (s *changingStep) Name() string {
+	return s.name
+}
+
+func (s *changingStep) Code() pb.UpgradeSteps {
+	return s.code
+}
+
+func (s *changingStep) Status() pb.StepStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *changingStep) Set(status pb.StepStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
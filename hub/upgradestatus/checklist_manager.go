@@ -3,12 +3,17 @@ package upgradestatus
 import (
 	"fmt"
 	"os"
-	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus/file"
 	pb "github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -20,6 +25,8 @@ const (
 	CONVERT_MASTER         = "convert-master"
 	SHARE_OIDS             = "share-oids"
 	CONVERT_PRIMARIES      = "convert-primaries"
+	REBUILD_MIRRORS        = "rebuild-mirrors"
+	REBUILD_STANDBY        = "rebuild-standby"
 	VALIDATE_START_CLUSTER = "validate-start-cluster"
 	RECONFIGURE_PORTS      = "reconfigure-ports"
 )
@@ -28,6 +35,20 @@ type Checklist interface {
 	AllSteps() []StateReader
 	GetStepReader(step string) StateReader
 	GetStepWriter(step string) StateWriter
+	GetPrerequisites(step string) []StateReader
+
+	// WritableSteps are the steps whose status the hub records itself, rather
+	// than working out from the state of the clusters.
+	WritableSteps() []StateReader
+
+	// ResetStep returns a writable step to PENDING. Read-only steps have no
+	// status of their own to reset, but any failure recorded for them with
+	// SetFailure is forgotten.
+	ResetStep(step string) error
+
+	// SetFailure records why a read-only step failed, when the hub is what
+	// made it fail; its status still comes from the state of the clusters.
+	SetFailure(step string, err error) error
 }
 
 type StateReader interface {
@@ -39,15 +60,47 @@ type StateReader interface {
 type StateWriter interface {
 	MarkInProgress() error
 	ResetStateDir() error
-	MarkFailed() error
+	MarkFailed(err error) error
 	MarkComplete() error
+
+	// Steps that do their work host by host record how it went on each one,
+	// along with the status of the step as a whole.
+	MarkHostComplete(host string) error
+	MarkHostFailed(host string, reason string) error
+}
+
+// A HostReader reports how a step went on each host, for the steps that keep
+// track of it.
+type HostReader interface {
+	HostStatuses() []*pb.HostStepStatus
+}
+
+// A FailureReader explains why a step failed, for the steps that keep track of
+// it. It returns nil unless the step has failed.
+type FailureReader interface {
+	Failure() *pb.StepFailure
+}
+
+// A TimingReader reports when the latest attempt at a step started and
+// finished, for the steps that keep track of it. It returns nil if the step
+// has not been started.
+type TimingReader interface {
+	Timing() *StepTiming
+}
+
+// A HistoryReader reports every change in the status of a step, oldest first,
+// for the steps that keep track of it.
+type HistoryReader interface {
+	History() []*pb.StepTransition
 }
 
 type ChecklistManager struct {
 	stateDir string                 // the directory where writable step status is kept
+	journal  *Journal               // the status of the writable steps
 	steps    []StateReader          // backing slice for AllSteps()
 	stepmap  map[string]StateReader // maps step name to StateReader implementation
 	readOnly map[string]bool        // value is true iff step was added via AddReadOnlyStep()
+	prereqs  map[string][]string    // maps step name to the names of the steps it depends on
 }
 
 // A StatusFunc returns a StepStatus for a read-only step. It is passed the name
@@ -55,9 +108,16 @@ type ChecklistManager struct {
 type StatusFunc func(name string) pb.StepStatus
 
 type step struct {
-	name   string
-	code   pb.UpgradeSteps
-	status StatusFunc
+	name    string
+	code    pb.UpgradeSteps
+	status  StatusFunc
+	journal *Journal // where writable steps record their status, and read-only ones their failures
+}
+
+// A writableStep's status comes from the journal, so the journal also tells
+// when it started and finished. Read-only steps are timed by the hub instead.
+type writableStep struct {
+	step
 }
 
 func (s step) Name() string {
@@ -72,39 +132,113 @@ func (s step) Status() pb.StepStatus {
 	return s.status(s.name)
 }
 
+func (s step) HostStatuses() []*pb.HostStepStatus {
+	var statuses []*pb.HostStepStatus
+	for _, t := range hostResults(s.history()) {
+		statuses = append(statuses, &pb.HostStepStatus{Hostname: t.Host, Status: t.Status, Error: t.Error})
+	}
+	return statuses
+}
+
+func (s step) History() []*pb.StepTransition {
+	var history []*pb.StepTransition
+	for _, t := range s.history() {
+		history = append(history, &pb.StepTransition{
+			Status:    t.Status,
+			Timestamp: t.Time.UnixNano() / int64(time.Millisecond),
+			Hostname:  t.Host,
+			Error:     t.Error,
+			Attempt:   int32(t.Attempt),
+		})
+	}
+	return history
+}
+
+func (s step) Failure() *pb.StepFailure {
+	latest := current(s.history())
+	if latest.Status != pb.StepStatus_FAILED {
+		return nil
+	}
+
+	return &pb.StepFailure{
+		Error:    latest.Error,
+		Command:  latest.Command,
+		ExitCode: int32(latest.ExitCode),
+		LogFile:  latest.LogFile,
+	}
+}
+
+func (s writableStep) Timing() *StepTiming {
+	history := s.history()
+	latest := current(history)
+	if latest.Status == pb.StepStatus_PENDING {
+		return nil
+	}
+
+	timing := &StepTiming{}
+	for _, t := range history {
+		if t.Host == "" && t.Status == pb.StepStatus_RUNNING {
+			timing.Start = t.Time
+		}
+	}
+	if latest.Status != pb.StepStatus_RUNNING {
+		timing.End = latest.Time
+		timing.Complete = latest.Status == pb.StepStatus_COMPLETE
+	}
+	return timing
+}
+
+func (s step) history() []Transition {
+	if s.journal == nil {
+		return nil
+	}
+
+	history, err := s.journal.Transitions(s.name)
+	if err != nil {
+		gplog.Error("Couldn't read the history of %s: %s", s.name, err.Error())
+	}
+	return history
+}
+
 func NewChecklistManager(stateDirPath string) *ChecklistManager {
 	return &ChecklistManager{
 		stateDir: stateDirPath,
+		journal:  NewJournal(stateDirPath),
 		stepmap:  map[string]StateReader{},
 		readOnly: map[string]bool{},
+		prereqs:  map[string][]string{},
 	}
 }
 
-// AddWritableStep creates a step with a writable status that is backed by the
-// filesystem. The given name must be filesystem-friendly, since it will be used
-// in the backing path.
-func (c *ChecklistManager) AddWritableStep(name string, code pb.UpgradeSteps) {
+// AddWritableStep creates a step with a writable status that is recorded in the
+// journal in the state directory. The given name must be filesystem-friendly,
+// since the step's directory, which earlier versions kept its status in, is
+// named after it. Any prerequisites name steps that must be COMPLETE before
+// this one may start; they must already have been added.
+func (c *ChecklistManager) AddWritableStep(name string, code pb.UpgradeSteps, prereqs ...string) {
 	statusFunc := func(name string) pb.StepStatus {
-		checker := StateCheck{
-			Path: filepath.Join(c.stateDir, name),
-			Step: code,
+		// As with StateCheck, a status that can't be read is PENDING, on the
+		// assumption that rerunning the step will clear it up.
+		history, err := c.journal.Transitions(name)
+		if err != nil {
+			gplog.Error("Couldn't read the status of %s: %s", name, err.Error())
+			return pb.StepStatus_PENDING
 		}
-		return checker.GetStatus()
+		return current(history).Status
 	}
 
-	c.addStep(name, code, statusFunc)
+	c.addStep(name, writableStep{step{name, code, statusFunc, c.journal}}, prereqs)
 }
 
 // AddReadOnlyStep creates a step with a custom status retrieval mechanism, as
-// determined by the given StatusFunc.
-func (c *ChecklistManager) AddReadOnlyStep(name string, code pb.UpgradeSteps, status StatusFunc) {
-	c.addStep(name, code, status)
+// determined by the given StatusFunc. Prerequisites work as for
+// AddWritableStep().
+func (c *ChecklistManager) AddReadOnlyStep(name string, code pb.UpgradeSteps, status StatusFunc, prereqs ...string) {
+	c.addStep(name, step{name, code, status, c.journal}, prereqs)
 	c.readOnly[name] = true
 }
 
-func (c *ChecklistManager) addStep(name string, code pb.UpgradeSteps, status StatusFunc) {
-	s := step{name, code, status}
-
+func (c *ChecklistManager) addStep(name string, s StateReader, prereqs []string) {
 	// Since checklist setup isn't influenced by the user, it's always a
 	// programmer error for a step to be added twice. Panic instead of making
 	// all callers check for an error that should never happen.
@@ -112,8 +246,15 @@ func (c *ChecklistManager) addStep(name string, code pb.UpgradeSteps, status Sta
 		panic(fmt.Sprintf(`step "%s" has already been added`, name))
 	}
 
+	for _, prereq := range prereqs {
+		if _, ok := c.stepmap[prereq]; !ok {
+			panic(fmt.Sprintf(`step "%s" depends on unknown step "%s"`, name, prereq))
+		}
+	}
+
 	c.steps = append(c.steps, s)
 	c.stepmap[name] = s
+	c.prereqs[name] = prereqs
 }
 
 func (c *ChecklistManager) GetStepReader(step string) StateReader {
@@ -124,6 +265,26 @@ func (c *ChecklistManager) AllSteps() []StateReader {
 	return c.steps
 }
 
+func (c *ChecklistManager) WritableSteps() []StateReader {
+	var steps []StateReader
+	for _, step := range c.steps {
+		if !c.readOnly[step.Name()] {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// GetPrerequisites returns the steps that must be COMPLETE before the given
+// step may start.
+func (c *ChecklistManager) GetPrerequisites(step string) []StateReader {
+	var prereqs []StateReader
+	for _, name := range c.prereqs[step] {
+		prereqs = append(prereqs, c.stepmap[name])
+	}
+	return prereqs
+}
+
 func (c *ChecklistManager) GetStepWriter(step string) StateWriter {
 	if c.readOnly[step] {
 		// This is always a programmer error: we shouldn't ever write to a
@@ -131,63 +292,256 @@ func (c *ChecklistManager) GetStepWriter(step string) StateWriter {
 		panic(fmt.Sprintf(`attempted to write to read-only step "%s"`, step))
 	}
 
-	stepdir := filepath.Join(c.stateDir, step)
-	return StepWriter{stepdir: stepdir}
+	return StepWriter{
+		step:     step,
+		journal:  c.journal,
+		stepdir:  filepath.Join(c.stateDir, step),
+		hostsdir: hostsDir(c.stateDir, step),
+	}
+}
+
+func (c *ChecklistManager) ResetStep(step string) error {
+	if c.readOnly[step] {
+		return c.journal.record(step, func(history []Transition) ([]Transition, error) {
+			latest := current(history)
+			if latest.Status == pb.StepStatus_PENDING {
+				return nil, nil
+			}
+			return []Transition{{Status: pb.StepStatus_PENDING, Attempt: latest.Attempt}}, nil
+		})
+	}
+	return c.GetStepWriter(step).ResetStateDir()
+}
+
+func (c *ChecklistManager) SetFailure(step string, err error) error {
+	if !c.readOnly[step] {
+		// As with GetStepWriter, this is always a programmer error: writable
+		// steps record their failures with MarkFailed.
+		panic(fmt.Sprintf(`attempted to set the failure of writable step "%s"`, step))
+	}
+
+	return c.journal.record(step, func(history []Transition) ([]Transition, error) {
+		return []Transition{{Status: pb.StepStatus_FAILED, Error: err.Error(), Attempt: current(history).Attempt}}, nil
+	})
 }
 
+func hostsDir(stateDir, step string) string {
+	return filepath.Join(stateDir, file.Hosts, step)
+}
+
+// StepWriter moves a step from one status to another in the journal. A step
+// must be started before it can complete or fail, and starting a step that is
+// not already running begins its next attempt.
 type StepWriter struct {
-	stepdir string // path to step-specific state directory
+	step    string
+	journal *Journal
+
+	// Where earlier versions kept the step's status, and how it went on
+	// each host.
+	stepdir  string
+	hostsdir string
 }
 
-// FIXME: none of these operations are atomic on the FS; just move the progress
-// file from name to name instead
-func (sw StepWriter) MarkFailed() error {
-	err := utils.System.Remove(filepath.Join(sw.stepdir, file.InProgress))
-	if err != nil {
-		return err
+func (sw StepWriter) MarkInProgress() error {
+	return sw.journal.record(sw.step, func(history []Transition) ([]Transition, error) {
+		latest := current(history)
+		if latest.Status == pb.StepStatus_RUNNING {
+			return nil, nil
+		}
+		return []Transition{{Status: pb.StepStatus_RUNNING, Attempt: latest.Attempt + 1}}, nil
+	})
+}
+
+func (sw StepWriter) MarkComplete() error {
+	return sw.finish(Transition{Status: pb.StepStatus_COMPLETE})
+}
+
+// MarkFailed keeps the error, which is reported along with the step's status,
+// as are the details of a CommandError.
+func (sw StepWriter) MarkFailed(err error) error {
+	failure := Transition{Status: pb.StepStatus_FAILED, Error: err.Error()}
+	if cmdErr, ok := errors.Cause(err).(*CommandError); ok {
+		failure.Command = cmdErr.Command
+		failure.ExitCode = cmdErr.ExitCode
+		failure.LogFile = cmdErr.LogFile
 	}
 
-	_, err = utils.System.OpenFile(path.Join(sw.stepdir, file.Failed), os.O_CREATE, 0700)
-	if err != nil {
-		return err
+	return sw.finish(failure)
+}
+
+func (sw StepWriter) finish(t Transition) error {
+	return sw.journal.record(sw.step, func(history []Transition) ([]Transition, error) {
+		latest := current(history)
+		if latest.Status != pb.StepStatus_RUNNING {
+			return nil, transitionError(sw.step, latest.Status, t.Status)
+		}
+		t.Attempt = latest.Attempt
+		return []Transition{t}, nil
+	})
+}
+
+// ResetStateDir returns the step to PENDING, forgetting how it went on each
+// host. Its history is kept.
+func (sw StepWriter) ResetStateDir() error {
+	for _, dir := range []string{sw.stepdir, sw.hostsdir} {
+		err := utils.System.RemoveAll(dir)
+		if err != nil {
+			return err
+		}
 	}
 
+	return sw.journal.record(sw.step, func(history []Transition) ([]Transition, error) {
+		latest := current(history)
+		if latest.Status == pb.StepStatus_PENDING && len(hostResults(history)) == 0 {
+			return nil, nil
+		}
+		return []Transition{{Status: pb.StepStatus_PENDING, Attempt: latest.Attempt}}, nil
+	})
+}
+
+func (sw StepWriter) MarkHostComplete(host string) error {
+	return sw.markHost(host, pb.StepStatus_COMPLETE, "")
+}
+
+// MarkHostFailed keeps the reason, which is reported along with the host's
+// status.
+func (sw StepWriter) MarkHostFailed(host string, reason string) error {
+	return sw.markHost(host, pb.StepStatus_FAILED, reason)
+}
+
+func (sw StepWriter) markHost(host string, status pb.StepStatus, reason string) error {
+	return sw.journal.record(sw.step, func(history []Transition) ([]Transition, error) {
+		return []Transition{{Host: host, Status: status, Error: reason, Attempt: current(history).Attempt}}, nil
+	})
+}
+
+// ImportMarkerFiles moves the status of each writable step, and how it went on
+// each host, from the marker files that earlier versions kept into the
+// journal. A step that the journal already knows about is left alone, as are
+// the directories of the read-only steps, which hold the state of the
+// utilities they watch.
+func (c *ChecklistManager) ImportMarkerFiles() error {
+	for _, s := range c.steps {
+		if c.readOnly[s.Name()] {
+			continue
+		}
+
+		err := c.importMarkerFiles(s.Name())
+		if err != nil {
+			return errors.Wrapf(err, "could not import the status of %s", s.Name())
+		}
+	}
 	return nil
 }
 
-func (sw StepWriter) MarkComplete() error {
-	err := utils.System.Remove(filepath.Join(sw.stepdir, file.InProgress))
-	if err != nil {
-		return err
+func (c *ChecklistManager) importMarkerFiles(name string) error {
+	stepdir := filepath.Join(c.stateDir, name)
+	hostsdir := hostsDir(c.stateDir, name)
+
+	status := StateCheck{Path: stepdir}.GetStatus()
+	hosts := ReadHostStatuses(hostsdir)
+	if status == pb.StepStatus_PENDING && len(hosts) == 0 {
+		return nil
 	}
 
-	_, err = utils.System.OpenFile(path.Join(sw.stepdir, file.Complete), os.O_CREATE, 0700)
+	err := c.journal.record(name, func(history []Transition) ([]Transition, error) {
+		if len(history) > 0 {
+			return nil, nil
+		}
+
+		// The markers only say where the step ended up, on its one known
+		// attempt, so that is all that can be recorded; their modification
+		// times are the best guess at when it got there.
+		var transitions []Transition
+		if status != pb.StepStatus_PENDING {
+			transitions = append(transitions, Transition{
+				Status:  status,
+				Time:    markerTime(stepdir, status),
+				Attempt: 1,
+			})
+		}
+		for _, host := range hosts {
+			transitions = append(transitions, Transition{
+				Host:    host.Hostname,
+				Status:  host.Status,
+				Time:    markerTime(hostsdir, host.Status, host.Hostname),
+				Error:   host.Error,
+				Attempt: 1,
+			})
+		}
+		return transitions, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	return nil
+	gplog.Info("Imported the status of %s into %s", name, c.journal.path)
+	for _, marker := range []string{file.InProgress, file.Complete, file.Failed} {
+		err = utils.System.Remove(filepath.Join(stepdir, marker))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return utils.System.RemoveAll(hostsdir)
 }
 
-func (sw StepWriter) MarkInProgress() error {
-	_, err := utils.System.OpenFile(path.Join(sw.stepdir, file.InProgress), os.O_CREATE, 0700)
-	if err != nil {
-		return err
+// markerTime is when the marker for status was written, or the zero time,
+// which the journal replaces with the current one, if that can't be told.
+func markerTime(dir string, status pb.StepStatus, host ...string) time.Time {
+	name := markerName(status)
+	if len(host) > 0 {
+		name = host[0] + "." + name
 	}
 
-	return nil
+	info, err := utils.System.Stat(filepath.Join(dir, name))
+	if err != nil || info == nil {
+		return time.Time{}
+	}
+	return info.ModTime()
 }
 
-func (sw StepWriter) ResetStateDir() error {
-	err := utils.System.RemoveAll(sw.stepdir)
-	if err != nil {
-		return err
+func markerName(status pb.StepStatus) string {
+	switch status {
+	case pb.StepStatus_RUNNING:
+		return file.InProgress
+	case pb.StepStatus_COMPLETE:
+		return file.Complete
+	default:
+		return file.Failed
 	}
+}
 
-	err = utils.System.MkdirAll(sw.stepdir, 0700)
+// ReadHostStatuses returns the results that earlier versions recorded in dir,
+// ordered by host.
+func ReadHostStatuses(dir string) []*pb.HostStepStatus {
+	paths, err := utils.System.FilePathGlob(filepath.Join(dir, "*"))
 	if err != nil {
-		return err
+		gplog.Error("Couldn't search host status directory %s: %s", dir, err.Error())
+		return nil
 	}
+	sort.Strings(paths)
+
+	var statuses []*pb.HostStepStatus
+	for _, path := range paths {
+		name := filepath.Base(path)
+		ext := filepath.Ext(name)
+		status := &pb.HostStepStatus{Hostname: strings.TrimSuffix(name, ext)}
+
+		switch ext {
+		case "." + file.Complete:
+			status.Status = pb.StepStatus_COMPLETE
+		case "." + file.Failed:
+			status.Status = pb.StepStatus_FAILED
+			reason, err := utils.System.ReadFile(path)
+			if err != nil {
+				gplog.Error("Couldn't read why the step failed on %s: %s", status.Hostname, err.Error())
+			}
+			status.Error = string(reason)
+		default:
+			continue
+		}
 
-	return nil
+		statuses = append(statuses, status)
+	}
+	return statuses
 }
@@ -1,10 +1,12 @@
 package upgradestatus_test
 
 import (
-	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus/file"
@@ -13,6 +15,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 )
 
 var _ = Describe("upgradestatus/ChecklistManager", func() {
@@ -20,10 +23,11 @@ var _ = Describe("upgradestatus/ChecklistManager", func() {
 		utils.System = utils.InitializeSystemFunctions()
 	})
 
-	Describe("MarkInProgress", func() {
+	Describe("StepWriter", func() {
 		var (
 			tempdir string
 			cm      *upgradestatus.ChecklistManager
+			step    upgradestatus.StateWriter
 		)
 
 		BeforeEach(func() {
@@ -32,150 +36,304 @@ var _ = Describe("upgradestatus/ChecklistManager", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			cm = upgradestatus.NewChecklistManager(filepath.Join(tempdir, ".gpupgrade"))
+			cm.AddWritableStep("fancy_step", pb.UpgradeSteps_CONFIG)
+			step = cm.GetStepWriter("fancy_step")
 		})
 
 		AfterEach(func() {
 			os.RemoveAll(tempdir)
 		})
 
-		It("Leaves an in-progress file in the state dir", func() {
-			step := cm.GetStepWriter("fancy_step")
-			step.ResetStateDir()
-			err := step.MarkInProgress()
-			Expect(err).ToNot(HaveOccurred())
-			expectedFile := filepath.Join(tempdir, ".gpupgrade", "fancy_step", file.InProgress)
-			_, err = os.Stat(expectedFile)
-			Expect(err).ToNot(HaveOccurred())
+		status := func() pb.StepStatus {
+			return cm.GetStepReader("fancy_step").Status()
+		}
+
+		history := func() []*pb.StepTransition {
+			return cm.GetStepReader("fancy_step").(upgradestatus.HistoryReader).History()
+		}
+
+		It("starts out pending", func() {
+			Expect(status()).To(Equal(pb.StepStatus_PENDING))
+			Expect(history()).To(BeEmpty())
 		})
 
-		It("still succeeds if file already exists", func() {
-			step := cm.GetStepWriter("fancy_step")
-			step.ResetStateDir()
-			step.MarkInProgress() // lay the file down once
-			err := step.MarkInProgress()
-			Expect(err).ToNot(HaveOccurred())
-			expectedFile := filepath.Join(tempdir, ".gpupgrade", "fancy_step", file.InProgress)
-			_, err = os.Stat(expectedFile)
-			Expect(err).ToNot(HaveOccurred())
+		It("records each transition with its time, host, error and attempt", func() {
+			now := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+			utils.System.Now = func() time.Time { return now }
+
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(step.MarkHostFailed("sdw1", "disk full")).To(Succeed())
+			Expect(step.MarkFailed(errors.New("sdw1 failed"))).To(Succeed())
+
+			now = now.Add(time.Minute)
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(step.MarkComplete()).To(Succeed())
+
+			ms := now.UnixNano() / int64(time.Millisecond)
+			Expect(history()).To(Equal([]*pb.StepTransition{
+				{Status: pb.StepStatus_RUNNING, Timestamp: ms - 60000, Attempt: 1},
+				{Status: pb.StepStatus_FAILED, Timestamp: ms - 60000, Hostname: "sdw1", Error: "disk full", Attempt: 1},
+				{Status: pb.StepStatus_FAILED, Timestamp: ms - 60000, Error: "sdw1 failed", Attempt: 1},
+				{Status: pb.StepStatus_RUNNING, Timestamp: ms, Attempt: 2},
+				{Status: pb.StepStatus_COMPLETE, Timestamp: ms, Attempt: 2},
+			}))
+			Expect(status()).To(Equal(pb.StepStatus_COMPLETE))
 		})
 
-		It("errors if file opening fails, e.g. disk full", func() {
-			utils.System.OpenFile = func(_ string, _ int, _ os.FileMode) (*os.File, error) {
-				return nil, errors.New("Disk full or something")
+		It("explains why the step failed, with the details of a failed command", func() {
+			failure := func() *pb.StepFailure {
+				return cm.GetStepReader("fancy_step").(upgradestatus.FailureReader).Failure()
 			}
 
-			step := cm.GetStepWriter("fancy_step")
-			step.ResetStateDir()
-			err := step.MarkInProgress()
-			Expect(err).To(HaveOccurred())
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(failure()).To(BeNil())
+
+			cmdErr := &upgradestatus.CommandError{
+				Command:  "gpinitsystem -a -I gpinitsystem_config",
+				ExitCode: 2,
+				LogFile:  "/home/gpadmin/gpAdminLogs/gpinitsystem_20180301.log",
+				Err:      errors.New("exit status 2"),
+			}
+			Expect(step.MarkFailed(errors.Wrap(cmdErr, "gpinitsystem failed"))).To(Succeed())
+
+			Expect(failure()).To(Equal(&pb.StepFailure{
+				Error:    "gpinitsystem failed: exit status 2",
+				Command:  "gpinitsystem -a -I gpinitsystem_config",
+				ExitCode: 2,
+				LogFile:  "/home/gpadmin/gpAdminLogs/gpinitsystem_20180301.log",
+			}))
+
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(failure()).To(BeNil())
 		})
-	})
 
-	Describe("ResetStateDir", func() {
-		It("errors if existing files cant be deleted", func() {
-			utils.System.RemoveAll = func(name string) error {
-				return errors.New("cant remove all")
+		It("times the latest attempt at the step", func() {
+			timing := func() *upgradestatus.StepTiming {
+				return cm.GetStepReader("fancy_step").(upgradestatus.TimingReader).Timing()
 			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			step := cm.GetStepWriter("fancy_step")
-			err := step.ResetStateDir()
-			Expect(err).To(HaveOccurred())
+			now := time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+			utils.System.Now = func() time.Time { return now }
+
+			Expect(timing()).To(BeNil())
+
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(step.MarkFailed(errors.New("it broke"))).To(Succeed())
+
+			now = now.Add(time.Hour)
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(timing()).To(Equal(&upgradestatus.StepTiming{Start: now}))
+
+			Expect(step.MarkHostComplete("sdw1")).To(Succeed())
+			Expect(timing()).To(Equal(&upgradestatus.StepTiming{Start: now}))
+
+			now = now.Add(time.Minute)
+			Expect(step.MarkComplete()).To(Succeed())
+			Expect(timing()).To(Equal(&upgradestatus.StepTiming{
+				Start:    now.Add(-time.Minute),
+				End:      now,
+				Complete: true,
+			}))
+
+			Expect(step.ResetStateDir()).To(Succeed())
+			Expect(timing()).To(BeNil())
 		})
 
-		It("errors if making the directory fails", func() {
-			utils.System.RemoveAll = func(name string) error {
-				return nil
+		It("does not start an attempt for a step that is already running", func() {
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(step.MarkInProgress()).To(Succeed())
+
+			Expect(history()).To(HaveLen(1))
+			Expect(status()).To(Equal(pb.StepStatus_RUNNING))
+		})
+
+		It("refuses to finish a step that is not running", func() {
+			err := step.MarkComplete()
+			Expect(err).To(MatchError("step fancy_step cannot become COMPLETE: it is PENDING"))
+
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(step.MarkFailed(errors.New("it broke"))).To(Succeed())
+
+			err = step.MarkFailed(errors.New("it broke again"))
+			Expect(err).To(MatchError("step fancy_step cannot become FAILED: it is FAILED"))
+			Expect(history()).To(HaveLen(2))
+		})
+
+		It("returns the step to pending when it is reset, keeping its history", func() {
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(step.MarkComplete()).To(Succeed())
+			Expect(step.ResetStateDir()).To(Succeed())
+
+			Expect(status()).To(Equal(pb.StepStatus_PENDING))
+			Expect(history()).To(HaveLen(3))
+			Expect(history()[2].Attempt).To(Equal(int32(1)))
+		})
+
+		It("records nothing when a pending step is reset", func() {
+			Expect(step.ResetStateDir()).To(Succeed())
+
+			_, err := os.Stat(filepath.Join(tempdir, ".gpupgrade", file.Journal))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("keeps every step in one journal that people can read", func() {
+			cm.AddWritableStep("other_step", pb.UpgradeSteps_SEGINSTALL)
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(cm.GetStepWriter("other_step").MarkInProgress()).To(Succeed())
+
+			contents, err := ioutil.ReadFile(filepath.Join(tempdir, ".gpupgrade", file.Journal))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring(`"step":"fancy_step"`))
+			Expect(string(contents)).To(ContainSubstring(`"step":"other_step"`))
+			Expect(string(contents)).To(ContainSubstring(`"status":"RUNNING"`))
+		})
+
+		It("shares the journal with other managers of the same state directory", func() {
+			other := upgradestatus.NewChecklistManager(filepath.Join(tempdir, ".gpupgrade"))
+			Expect(other.GetStepWriter("fancy_step").MarkInProgress()).To(Succeed())
+
+			Expect(status()).To(Equal(pb.StepStatus_RUNNING))
+		})
+
+		It("loses no transitions when they are recorded at the same time", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					defer GinkgoRecover()
+
+					w := upgradestatus.NewChecklistManager(filepath.Join(tempdir, ".gpupgrade")).GetStepWriter("fancy_step")
+					Expect(w.MarkHostComplete(fmt.Sprintf("sdw%d", i))).To(Succeed())
+				}(i)
 			}
+			wg.Wait()
+
+			Expect(history()).To(HaveLen(20))
+		})
+
+		It("leaves the step alone if the journal cannot be written", func() {
 			utils.System.MkdirAll = func(string, os.FileMode) error {
 				return errors.New("cant make dir")
 			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			step := cm.GetStepWriter("fancy_step")
-			err := step.ResetStateDir()
-			Expect(err).To(HaveOccurred())
+
+			err := step.MarkInProgress()
+			Expect(err).To(MatchError("cant make dir"))
+			Expect(status()).To(Equal(pb.StepStatus_PENDING))
 		})
-		It("succeeds as long as we assume the file system calls do their job", func() {
-			utils.System.RemoveAll = func(name string) error {
-				return nil
+
+		It("reports a journal that cannot be read as pending", func() {
+			Expect(step.MarkInProgress()).To(Succeed())
+			utils.System.ReadFile = func(string) ([]byte, error) {
+				return nil, errors.New("permission denied")
 			}
-			utils.System.MkdirAll = func(string, os.FileMode) error {
-				return nil
+
+			Expect(status()).To(Equal(pb.StepStatus_PENDING))
+			Expect(step.MarkComplete()).To(MatchError(ContainSubstring("permission denied")))
+		})
+
+		It("errors if existing files cant be deleted on reset", func() {
+			utils.System.RemoveAll = func(name string) error {
+				return errors.New("cant remove all")
 			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			step := cm.GetStepWriter("fancy_step")
-			err := step.ResetStateDir()
-			Expect(err).ToNot(HaveOccurred())
+
+			Expect(step.ResetStateDir()).To(MatchError("cant remove all"))
 		})
 	})
 
-	Describe("MarkFailed", func() {
-		It("errors if in.progress file can't be removed", func() {
-			utils.System.Remove = func(string) error {
-				return errors.New("remove failed")
-			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			step := cm.GetStepWriter("step")
-			err := step.MarkFailed()
-			Expect(err.Error()).To(ContainSubstring("remove failed"))
-		})
-		It("errors if failed file can't be created", func() {
-			utils.System.Remove = func(string) error {
-				return nil
-			}
-			utils.System.OpenFile = func(string, int, os.FileMode) (*os.File, error) {
-				return nil, errors.New("open file failed")
-			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			step := cm.GetStepWriter("step")
-			err := step.MarkFailed()
-			Expect(err.Error()).To(ContainSubstring("open file failed"))
-		})
-		It("returns nil if nothing fails", func() {
-			utils.System.Remove = func(string) error {
-				return nil
-			}
-			utils.System.OpenFile = func(string, int, os.FileMode) (*os.File, error) {
-				return nil, nil
+	Describe("ImportMarkerFiles", func() {
+		var (
+			stateDir string
+			cm       *upgradestatus.ChecklistManager
+		)
+
+		BeforeEach(func() {
+			var err error
+			stateDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			cm = upgradestatus.NewChecklistManager(stateDir)
+			cm.AddWritableStep("fancy_step", pb.UpgradeSteps_SHARE_OIDS)
+			cm.AddWritableStep("untouched_step", pb.UpgradeSteps_CONFIG)
+			cm.AddReadOnlyStep("read_only_step", pb.UpgradeSteps_CONVERT_MASTER, func(string) pb.StepStatus {
+				return pb.StepStatus_PENDING
+			})
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(stateDir)
+		})
+
+		writeMarker := func(contents string, path ...string) {
+			path = append([]string{stateDir}, path...)
+			Expect(os.MkdirAll(filepath.Join(path[:len(path)-1]...), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(path...), []byte(contents), 0600)).To(Succeed())
+		}
+
+		It("moves the status of each step, and of each host, into the journal", func() {
+			writeMarker("", "fancy_step", file.Failed)
+			writeMarker("", file.Hosts, "fancy_step", "sdw1."+file.Complete)
+			writeMarker("disk full", file.Hosts, "fancy_step", "sdw2."+file.Failed)
+			writeMarker("", "read_only_step", file.InProgress)
+
+			Expect(cm.ImportMarkerFiles()).To(Succeed())
+
+			reader := cm.GetStepReader("fancy_step")
+			Expect(reader.Status()).To(Equal(pb.StepStatus_FAILED))
+			Expect(reader.(upgradestatus.HostReader).HostStatuses()).To(Equal([]*pb.HostStepStatus{
+				{Hostname: "sdw1", Status: pb.StepStatus_COMPLETE},
+				{Hostname: "sdw2", Status: pb.StepStatus_FAILED, Error: "disk full"},
+			}))
+
+			history := reader.(upgradestatus.HistoryReader).History()
+			Expect(history).To(HaveLen(3))
+			for _, transition := range history {
+				Expect(transition.Attempt).To(Equal(int32(1)))
+				Expect(transition.Timestamp).ToNot(BeZero())
 			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			step := cm.GetStepWriter("step")
-			err := step.MarkFailed()
+
+			Expect(cm.GetStepReader("untouched_step").Status()).To(Equal(pb.StepStatus_PENDING))
+
+			_, err := os.Stat(filepath.Join(stateDir, "fancy_step", file.Failed))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+			_, err = os.Stat(filepath.Join(stateDir, file.Hosts, "fancy_step"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+
+			// The read-only steps' directories belong to the utilities they watch.
+			_, err = os.Stat(filepath.Join(stateDir, "read_only_step", file.InProgress))
 			Expect(err).ToNot(HaveOccurred())
 		})
-	})
 
-	Describe("MarkComplete", func() {
-		It("errors if in.progress file can't be removed", func() {
-			utils.System.Remove = func(string) error {
-				return errors.New("remove failed")
-			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			step := cm.GetStepWriter("step")
-			err := step.MarkFailed()
-			Expect(err.Error()).To(ContainSubstring("remove failed"))
-		})
-		It("errors if completed file can't be created", func() {
-			utils.System.Remove = func(string) error {
-				return nil
-			}
-			utils.System.OpenFile = func(string, int, os.FileMode) (*os.File, error) {
-				return nil, errors.New("open file failed")
-			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			step := cm.GetStepWriter("step")
-			err := step.MarkComplete()
-			Expect(err.Error()).To(ContainSubstring("open file failed"))
+		It("leaves alone a step that the journal already knows about", func() {
+			step := cm.GetStepWriter("fancy_step")
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(step.MarkComplete()).To(Succeed())
+			writeMarker("", "fancy_step", file.Failed)
+
+			Expect(cm.ImportMarkerFiles()).To(Succeed())
+
+			Expect(cm.GetStepReader("fancy_step").Status()).To(Equal(pb.StepStatus_COMPLETE))
 		})
-		It("returns nil if nothing fails", func() {
-			utils.System.Remove = func(string) error {
-				return nil
-			}
-			utils.System.OpenFile = func(string, int, os.FileMode) (*os.File, error) {
-				return nil, nil
+
+		It("does nothing the second time", func() {
+			writeMarker("", "fancy_step", file.Complete)
+
+			Expect(cm.ImportMarkerFiles()).To(Succeed())
+			Expect(cm.ImportMarkerFiles()).To(Succeed())
+
+			history := cm.GetStepReader("fancy_step").(upgradestatus.HistoryReader).History()
+			Expect(history).To(HaveLen(1))
+		})
+
+		It("names the step whose status could not be imported", func() {
+			writeMarker("", "fancy_step", file.Complete)
+			utils.System.MkdirAll = func(string, os.FileMode) error {
+				return errors.New("cant make dir")
 			}
-			cm := upgradestatus.NewChecklistManager("/some/random/dir")
-			step := cm.GetStepWriter("step")
-			err := step.MarkComplete()
+
+			err := cm.ImportMarkerFiles()
+			Expect(err).To(MatchError("could not import the status of fancy_step: cant make dir"))
+
+			_, err = os.Stat(filepath.Join(stateDir, "fancy_step", file.Complete))
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
@@ -250,6 +408,16 @@ var _ = Describe("upgradestatus/ChecklistManager", func() {
 			Expect(allSteps[0].Name()).To(Equal("my-step"))
 		})
 
+		It("adds a step that leaves its timing to the hub", func() {
+			cm := upgradestatus.NewChecklistManager("some/random/dir")
+			cm.AddReadOnlyStep("my-step", 0, func(string) pb.StepStatus {
+				return pb.StepStatus_RUNNING
+			})
+
+			_, ok := cm.GetStepReader("my-step").(upgradestatus.TimingReader)
+			Expect(ok).To(BeFalse())
+		})
+
 		It("panics if a step with the same name has already been added", func() {
 			cm := upgradestatus.NewChecklistManager("some/random/dir")
 			statusFunc := func(string) pb.StepStatus {
@@ -260,4 +428,174 @@ var _ = Describe("upgradestatus/ChecklistManager", func() {
 			Expect(func() { cm.AddReadOnlyStep("my-step", 0, statusFunc) }).To(Panic())
 		})
 	})
+
+	Describe("WritableSteps", func() {
+		It("returns the writable steps, in order, without the read-only ones", func() {
+			cm := upgradestatus.NewChecklistManager("some/random/dir")
+			cm.AddWritableStep("first", 1)
+			cm.AddReadOnlyStep("second", 2, func(string) pb.StepStatus {
+				return pb.StepStatus_COMPLETE
+			})
+			cm.AddWritableStep("third", 3)
+
+			steps := cm.WritableSteps()
+			Expect(steps).To(HaveLen(2))
+			Expect(steps[0].Name()).To(Equal("first"))
+			Expect(steps[1].Name()).To(Equal("third"))
+		})
+	})
+
+	Describe("SetFailure", func() {
+		var (
+			stateDir string
+			cm       *upgradestatus.ChecklistManager
+			status   pb.StepStatus
+		)
+
+		BeforeEach(func() {
+			var err error
+			stateDir, err = ioutil.TempDir("", "")
+			Expect(err).ToNot(HaveOccurred())
+
+			status = pb.StepStatus_FAILED
+			cm = upgradestatus.NewChecklistManager(stateDir)
+			cm.AddWritableStep("writable_step", 0)
+			cm.AddReadOnlyStep("read_only_step", 0, func(string) pb.StepStatus {
+				return status
+			})
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(stateDir)
+		})
+
+		failure := func() *pb.StepFailure {
+			return cm.GetStepReader("read_only_step").(upgradestatus.FailureReader).Failure()
+		}
+
+		It("explains why a read-only step failed, leaving its status to the status func", func() {
+			Expect(cm.SetFailure("read_only_step", errors.New("cancelled"))).To(Succeed())
+
+			Expect(failure()).To(Equal(&pb.StepFailure{Error: "cancelled"}))
+			Expect(cm.GetStepReader("read_only_step").Status()).To(Equal(pb.StepStatus_FAILED))
+		})
+
+		It("forgets the failure when the step is reset", func() {
+			Expect(cm.SetFailure("read_only_step", errors.New("cancelled"))).To(Succeed())
+			Expect(cm.ResetStep("read_only_step")).To(Succeed())
+
+			Expect(failure()).To(BeNil())
+		})
+
+		It("records nothing when a read-only step without a failure is reset", func() {
+			Expect(cm.ResetStep("read_only_step")).To(Succeed())
+
+			history := cm.GetStepReader("read_only_step").(upgradestatus.HistoryReader).History()
+			Expect(history).To(BeEmpty())
+		})
+
+		It("panics for a writable step", func() {
+			Expect(func() { cm.SetFailure("writable_step", errors.New("cancelled")) }).To(Panic())
+		})
+	})
+
+	Describe("GetPrerequisites", func() {
+		It("returns the steps that were declared as prerequisites, in order", func() {
+			cm := upgradestatus.NewChecklistManager("some/random/dir")
+			cm.AddWritableStep("first", 1)
+			cm.AddReadOnlyStep("second", 2, func(string) pb.StepStatus {
+				return pb.StepStatus_COMPLETE
+			})
+			cm.AddWritableStep("third", 3, "first", "second")
+
+			prereqs := cm.GetPrerequisites("third")
+			Expect(prereqs).To(HaveLen(2))
+			Expect(prereqs[0].Name()).To(Equal("first"))
+			Expect(prereqs[1].Name()).To(Equal("second"))
+			Expect(prereqs[1].Status()).To(Equal(pb.StepStatus_COMPLETE))
+		})
+
+		It("returns nothing for a step without prerequisites", func() {
+			cm := upgradestatus.NewChecklistManager("some/random/dir")
+			cm.AddWritableStep("my-step", 0)
+
+			Expect(cm.GetPrerequisites("my-step")).To(BeEmpty())
+		})
+
+		It("panics if a prerequisite has not been added yet", func() {
+			cm := upgradestatus.NewChecklistManager("some/random/dir")
+			Expect(func() { cm.AddWritableStep("my-step", 0, "later-step") }).To(Panic())
+			Expect(func() {
+				cm.AddReadOnlyStep("other-step", 0, func(string) pb.StepStatus {
+					return pb.StepStatus_PENDING
+				}, "later-step")
+			}).To(Panic())
+		})
+	})
+
+	Describe("per-host results", func() {
+		var (
+			tempdir string
+			cm      *upgradestatus.ChecklistManager
+		)
+
+		BeforeEach(func() {
+			var err error
+			tempdir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			cm = upgradestatus.NewChecklistManager(tempdir)
+			cm.AddWritableStep("fancy_step", pb.UpgradeSteps_SHARE_OIDS)
+			cm.AddReadOnlyStep("read_only_step", pb.UpgradeSteps_CONVERT_PRIMARIES, func(string) pb.StepStatus {
+				return pb.StepStatus_PENDING
+			})
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tempdir)
+		})
+
+		hostStatuses := func(name string) []*pb.HostStepStatus {
+			return cm.GetStepReader(name).(upgradestatus.HostReader).HostStatuses()
+		}
+
+		It("reports each host's result, ordered by host, with the reason for any failure", func() {
+			step := cm.GetStepWriter("fancy_step")
+			Expect(step.ResetStateDir()).To(Succeed())
+			Expect(step.MarkInProgress()).To(Succeed())
+			Expect(step.MarkHostComplete("sdw2.example.com")).To(Succeed())
+			Expect(step.MarkHostFailed("sdw1", "disk full")).To(Succeed())
+			Expect(step.MarkFailed(errors.New("sdw1 failed"))).To(Succeed())
+
+			Expect(hostStatuses("fancy_step")).To(Equal([]*pb.HostStepStatus{
+				{Hostname: "sdw1", Status: pb.StepStatus_FAILED, Error: "disk full"},
+				{Hostname: "sdw2.example.com", Status: pb.StepStatus_COMPLETE},
+			}))
+
+			// The results are kept apart from the step's own status.
+			Expect(cm.GetStepReader("fancy_step").Status()).To(Equal(pb.StepStatus_FAILED))
+		})
+
+		It("keeps only the latest result for a host", func() {
+			step := cm.GetStepWriter("fancy_step")
+			Expect(step.MarkHostFailed("sdw1", "disk full")).To(Succeed())
+			Expect(step.MarkHostComplete("sdw1")).To(Succeed())
+
+			Expect(hostStatuses("fancy_step")).To(Equal([]*pb.HostStepStatus{
+				{Hostname: "sdw1", Status: pb.StepStatus_COMPLETE},
+			}))
+		})
+
+		It("forgets the results when the step is reset", func() {
+			step := cm.GetStepWriter("fancy_step")
+			Expect(step.MarkHostComplete("sdw1")).To(Succeed())
+			Expect(step.ResetStateDir()).To(Succeed())
+
+			Expect(hostStatuses("fancy_step")).To(BeEmpty())
+		})
+
+		It("reports nothing for read-only steps", func() {
+			Expect(hostStatuses("read_only_step")).To(BeEmpty())
+		})
+	})
 })
@@ -0,0 +1,15 @@
+package upgradestatus
+
+// A CommandError is returned by a step that failed because a command did. When
+// a step is marked failed with one, even wrapped, the command, its exit code
+// and its log file are reported along with the step's status.
+type CommandError struct {
+	Command  string
+	ExitCode int
+	LogFile  string // where the command logged what went wrong
+	Err      error
+}
+
+func (e *CommandError) Error() string {
+	return e.Err.Error()
+}
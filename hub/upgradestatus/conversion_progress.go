@@ -0,0 +1,180 @@
+package upgradestatus
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+)
+
+// ConversionProgress describes how far a single pg_upgrade run has gotten.
+type ConversionProgress struct {
+	Phase   string // the phase pg_upgrade is working on, or the last one it finished
+	Percent int    // a rough estimate; pg_upgrade doesn't report one itself
+
+	// Started is when pg_upgrade wrote its first progress file, and Updated
+	// is the last time it wrote anything at all. Both are zero if pg_upgrade
+	// hasn't left any trace yet.
+	Started time.Time
+	Updated time.Time
+}
+
+// pgUpgradeMilestones maps the start of the longer-running pg_upgrade phases
+// to roughly how much of the total work has been done by then. The figures
+// are weighted towards the schema restore and relation file transfer, which
+// dominate the runtime for any real cluster.
+var pgUpgradeMilestones = []struct {
+	prefix  string
+	percent int
+}{
+	{"Performing Consistency Checks", 2},
+	{"Creating dump of global objects", 10},
+	{"Creating dump of database schemas", 12},
+	{"Analyzing all rows in the new cluster", 25},
+	{"Freezing all rows on the new cluster", 28},
+	{"Deleting files from new pg_clog", 30},
+	{"Copying old pg_clog to new server", 31},
+	{"Restoring global objects in the new cluster", 35},
+	{"Restoring database schemas in the new cluster", 40},
+	{"Copying user relation files", 60},
+	{"Linking user relation files", 60},
+	{"Setting next OID for new cluster", 90},
+	{"Creating script to analyze new cluster", 95},
+	{"Creating script to delete old cluster", 97},
+	{"Upgrade complete", 100},
+}
+
+/*
+ * SegmentConversionProgress looks at the *.inprogress and *.done files that
+ * `pg_upgrade --progress` leaves in its working directory, and at the tail of
+ * the given log file in that directory, to work out the current phase of the
+ * upgrade. Missing or unreadable files just result in less information.
+ */
+func SegmentConversionProgress(pgUpgradePath, logFileName string) ConversionProgress {
+	progress := ConversionProgress{}
+
+	var latestDone string
+	var latestDoneModTime time.Time
+	doneFiles, _ := utils.System.FilePathGlob(filepath.Join(pgUpgradePath, "*.done"))
+	for _, doneFile := range doneFiles {
+		phase := readPhase(doneFile)
+		progress.Percent = maxInt(progress.Percent, phasePercent(phase))
+
+		modTime := progress.noteModTime(doneFile, true)
+		if modTime.After(latestDoneModTime) {
+			latestDone = phase
+			latestDoneModTime = modTime
+		}
+	}
+
+	inProgressFiles, _ := utils.System.FilePathGlob(filepath.Join(pgUpgradePath, "*.inprogress"))
+	for _, inProgressFile := range inProgressFiles {
+		progress.noteModTime(inProgressFile, true)
+		if phase := readPhase(inProgressFile); phase != "" {
+			progress.Phase = phase
+			progress.Percent = maxInt(progress.Percent, phasePercent(phase))
+		}
+	}
+
+	progress.noteModTime(filepath.Join(pgUpgradePath, logFileName), false)
+
+	if progress.Phase == "" {
+		progress.Phase = tailPhase(filepath.Join(pgUpgradePath, logFileName))
+		progress.Percent = maxInt(progress.Percent, phasePercent(progress.Phase))
+	}
+	if progress.Phase == "" {
+		progress.Phase = latestDone
+	}
+
+	return progress
+}
+
+// noteModTime folds the modification time of the given file into Updated and,
+// for progress files, Started. It returns the zero time if the file can't be
+// stat'd.
+func (p *ConversionProgress) noteModTime(path string, isProgressFile bool) time.Time {
+	fi, err := utils.System.Stat(path)
+	if err != nil || fi == nil {
+		return time.Time{}
+	}
+
+	modTime := fi.ModTime()
+	if isProgressFile && (p.Started.IsZero() || modTime.Before(p.Started)) {
+		p.Started = modTime
+	}
+	if modTime.After(p.Updated) {
+		p.Updated = modTime
+	}
+	return modTime
+}
+
+func phasePercent(phase string) int {
+	for _, milestone := range pgUpgradeMilestones {
+		if strings.HasPrefix(phase, milestone.prefix) {
+			return milestone.percent
+		}
+	}
+	return 0
+}
+
+// readPhase returns the first line of a pg_upgrade progress file, which names
+// the phase that the file tracks.
+func readPhase(path string) string {
+	contents, err := utils.System.ReadFile(path)
+	if err != nil {
+		gplog.Debug("Could not read pg_upgrade progress file %s: %s", path, err)
+		return ""
+	}
+
+	lines := strings.SplitN(string(contents), "\n", 2)
+	return strings.TrimSpace(lines[0])
+}
+
+// logTailBytes is how much of the end of the pg_upgrade log tailPhase reads,
+// which is plenty for its last status line however large the log has grown.
+const logTailBytes = 4096
+
+// tailPhase returns the last status line that pg_upgrade wrote to its log,
+// minus the "ok" it appends once that phase is done.
+func tailPhase(logPath string) string {
+	file, err := utils.System.Open(logPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		return ""
+	}
+
+	offset := fi.Size() - logTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return ""
+	}
+
+	contents, err := ioutil.ReadAll(file)
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	return strings.TrimSpace(strings.TrimSuffix(last, "ok"))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
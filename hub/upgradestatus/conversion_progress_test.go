@@ -0,0 +1,121 @@
+package upgradestatus_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SegmentConversionProgress", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(name, contents string) {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("reports nothing when pg_upgrade has not written anything", func() {
+		progress := upgradestatus.SegmentConversionProgress(dir, "pg_upgrade.log")
+		Expect(progress).To(Equal(upgradestatus.ConversionProgress{}))
+	})
+
+	It("reports the phase named by the inprogress file", func() {
+		writeFile("1.done", "Performing Consistency Checks\n")
+		writeFile("2.done", "Creating dump of global objects\n")
+		writeFile("3.inprogress", "Restoring database schemas in the new cluster\nsome other detail\n")
+
+		progress := upgradestatus.SegmentConversionProgress(dir, "pg_upgrade.log")
+		Expect(progress.Phase).To(Equal("Restoring database schemas in the new cluster"))
+		Expect(progress.Percent).To(Equal(40))
+	})
+
+	It("never reports less progress than a phase that has already finished", func() {
+		writeFile("1.done", "Linking user relation files\n")
+		writeFile("2.inprogress", "Some phase we don't know about\n")
+
+		progress := upgradestatus.SegmentConversionProgress(dir, "pg_upgrade.log")
+		Expect(progress.Phase).To(Equal("Some phase we don't know about"))
+		Expect(progress.Percent).To(Equal(60))
+	})
+
+	It("falls back to the last line of the log when the inprogress file is empty", func() {
+		writeFile("1.inprogress", "")
+		writeFile("pg_upgrade.log", "Performing Consistency Checks\nChecking database user is a superuser ok\nCopying user relation files\n")
+
+		progress := upgradestatus.SegmentConversionProgress(dir, "pg_upgrade.log")
+		Expect(progress.Phase).To(Equal("Copying user relation files"))
+		Expect(progress.Percent).To(Equal(60))
+	})
+
+	It("strips the trailing ok from a finished phase in the log", func() {
+		writeFile("pg_upgrade.log", "Setting next OID for new cluster                            ok\n")
+
+		progress := upgradestatus.SegmentConversionProgress(dir, "pg_upgrade.log")
+		Expect(progress.Phase).To(Equal("Setting next OID for new cluster"))
+		Expect(progress.Percent).To(Equal(90))
+	})
+
+	It("only needs the end of a large log", func() {
+		writeFile("pg_upgrade.log", strings.Repeat("Checking for presence of required libraries ok\n", 10000)+
+			"Restoring database schemas in the new cluster\n")
+
+		progress := upgradestatus.SegmentConversionProgress(dir, "pg_upgrade.log")
+		Expect(progress.Phase).To(Equal("Restoring database schemas in the new cluster"))
+		Expect(progress.Percent).To(Equal(40))
+	})
+
+	It("falls back to the most recently finished phase when there is nothing else", func() {
+		writeFile("1.done", "Creating dump of global objects\n")
+		writeFile("2.done", "Freezing all rows on the new cluster\n")
+
+		old := time.Now().Add(-time.Hour)
+		err := os.Chtimes(filepath.Join(dir, "1.done"), old, old)
+		Expect(err).ToNot(HaveOccurred())
+
+		progress := upgradestatus.SegmentConversionProgress(dir, "pg_upgrade.log")
+		Expect(progress.Phase).To(Equal("Freezing all rows on the new cluster"))
+		Expect(progress.Percent).To(Equal(28))
+	})
+
+	It("reports when pg_upgrade started and when it last wrote anything", func() {
+		writeFile("1.done", "Creating dump of global objects\n")
+		writeFile("2.inprogress", "Freezing all rows on the new cluster\n")
+		writeFile("pg_upgrade.log", "Freezing all rows on the new cluster\n")
+
+		start := time.Unix(1500000000, 0)
+		middle := start.Add(time.Minute)
+		end := start.Add(time.Hour)
+		Expect(os.Chtimes(filepath.Join(dir, "1.done"), start, start)).To(Succeed())
+		Expect(os.Chtimes(filepath.Join(dir, "2.inprogress"), middle, middle)).To(Succeed())
+		Expect(os.Chtimes(filepath.Join(dir, "pg_upgrade.log"), end, end)).To(Succeed())
+
+		progress := upgradestatus.SegmentConversionProgress(dir, "pg_upgrade.log")
+		Expect(progress.Started).To(BeTemporally("==", start))
+		Expect(progress.Updated).To(BeTemporally("==", end))
+	})
+
+	It("reports 100 percent once the upgrade is complete", func() {
+		writeFile("1.done", "Upgrade complete\n")
+
+		progress := upgradestatus.SegmentConversionProgress(dir, "pg_upgrade.log")
+		Expect(progress.Phase).To(Equal("Upgrade complete"))
+		Expect(progress.Percent).To(Equal(100))
+	})
+})
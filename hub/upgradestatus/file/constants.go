@@ -4,8 +4,29 @@ monitoring.
 */
 package file
 
+// Journal, in the state directory, records every change in the status of the
+// steps that the hub runs itself.
+const Journal = "steps.json"
+
+// Runs, in the state directory, records how long each step took in every run
+// of the upgrade.
+const Runs = "runs.json"
+
+// Tasks, in the state directory, records the steps that the hub is running in
+// the background, so that a hub that is restarted can tell which of them were
+// interrupted.
+const Tasks = "tasks.json"
+
+// Before the journal, each of those steps kept its status as one of these
+// marker files in its own directory. The hub imports them into the journal
+// when it starts.
 const (
 	InProgress = "in.progress" // started, but not yet completed or failed
 	Complete   = "completed"   // finished successfully
 	Failed     = "failed"      // stopped with an error
 )
+
+// Hosts is the directory, next to the step directories, that held how each
+// step went on each host, as <step>/<host>.completed or <step>/<host>.failed,
+// before the journal.
+const Hosts = "hosts"
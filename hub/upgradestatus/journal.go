@@ -0,0 +1,228 @@
+package upgradestatus
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus/file"
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/pkg/errors"
+)
+
+// A Transition is one change in the status of a step, or of the step on one
+// host.
+type Transition struct {
+	Step    string
+	Host    string // empty for the step as a whole
+	Status  pb.StepStatus
+	Time    time.Time
+	Error   string // why the step failed
+	Attempt int    // how many times the step had been started
+
+	// When the step failed because a command did.
+	Command  string
+	ExitCode int
+	LogFile  string
+}
+
+// transitionFile is the form that a Transition takes in the journal, which
+// spells out statuses so that it can be read by people as well as the hub.
+type transitionFile struct {
+	Step    string    `json:"step"`
+	Host    string    `json:"host,omitempty"`
+	Status  string    `json:"status"`
+	Time    time.Time `json:"time"`
+	Error   string    `json:"error,omitempty"`
+	Attempt int       `json:"attempt"`
+
+	Command  string `json:"command,omitempty"`
+	ExitCode int    `json:"exitCode,omitempty"`
+	LogFile  string `json:"logFile,omitempty"`
+}
+
+// Journal records every Transition of the steps that the hub runs itself, in
+// a single file in the state directory. Each Transition rewrites the whole
+// file and moves it into place, so a reader sees a step either before the
+// transition or after it, never in between.
+type Journal struct {
+	path string
+}
+
+func NewJournal(stateDir string) *Journal {
+	return &Journal{path: filepath.Join(stateDir, file.Journal)}
+}
+
+// Transitions are read, checked and written under a lock that is shared by
+// every Journal for the same file, since the hub makes ChecklistManagers as
+// it needs them.
+func (j *Journal) lock() func() {
+	return lockFile(j.path)
+}
+
+var fileLocks = struct {
+	sync.Mutex
+	byPath map[string]*sync.Mutex
+}{byPath: map[string]*sync.Mutex{}}
+
+// lockFile takes the lock for the file at path, and returns the function that
+// releases it.
+func lockFile(path string) func() {
+	fileLocks.Lock()
+	mu, ok := fileLocks.byPath[path]
+	if !ok {
+		mu = &sync.Mutex{}
+		fileLocks.byPath[path] = mu
+	}
+	fileLocks.Unlock()
+
+	mu.Lock()
+	return mu.Unlock
+}
+
+// Transitions returns everything that has been recorded for the step, oldest
+// first.
+func (j *Journal) Transitions(step string) ([]Transition, error) {
+	defer j.lock()()
+
+	all, err := j.read()
+	if err != nil {
+		return nil, err
+	}
+	return forStep(all, step), nil
+}
+
+func (j *Journal) read() ([]Transition, error) {
+	contents, err := utils.System.ReadFile(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read %s", j.path)
+	}
+
+	var entries []transitionFile
+	err = json.Unmarshal(contents, &entries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", j.path)
+	}
+
+	transitions := make([]Transition, len(entries))
+	for i, entry := range entries {
+		transitions[i] = Transition{
+			Step:     entry.Step,
+			Host:     entry.Host,
+			Status:   pb.StepStatus(pb.StepStatus_value[entry.Status]),
+			Time:     entry.Time,
+			Error:    entry.Error,
+			Attempt:  entry.Attempt,
+			Command:  entry.Command,
+			ExitCode: entry.ExitCode,
+			LogFile:  entry.LogFile,
+		}
+	}
+	return transitions, nil
+}
+
+func (j *Journal) write(transitions []Transition) error {
+	entries := make([]transitionFile, len(transitions))
+	for i, t := range transitions {
+		entries[i] = transitionFile{
+			Step:     t.Step,
+			Host:     t.Host,
+			Status:   t.Status.String(),
+			Time:     t.Time,
+			Error:    t.Error,
+			Attempt:  t.Attempt,
+			Command:  t.Command,
+			ExitCode: t.ExitCode,
+			LogFile:  t.LogFile,
+		}
+	}
+
+	err := utils.System.MkdirAll(filepath.Dir(j.path), 0700)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSONFile(j.path, entries)
+}
+
+// record works out the transitions to make from the step's history so far,
+// and writes them. Nothing is written if next returns none.
+func (j *Journal) record(step string, next func(history []Transition) ([]Transition, error)) error {
+	defer j.lock()()
+
+	all, err := j.read()
+	if err != nil {
+		return err
+	}
+
+	transitions, err := next(forStep(all, step))
+	if err != nil || len(transitions) == 0 {
+		return err
+	}
+
+	now := utils.System.Now()
+	for i := range transitions {
+		transitions[i].Step = step
+		if transitions[i].Time.IsZero() {
+			transitions[i].Time = now
+		}
+	}
+	return j.write(append(all, transitions...))
+}
+
+func forStep(all []Transition, step string) []Transition {
+	var transitions []Transition
+	for _, t := range all {
+		if t.Step == step {
+			transitions = append(transitions, t)
+		}
+	}
+	return transitions
+}
+
+// current is the latest transition of the step as a whole, or a PENDING one if
+// there has not been any.
+func current(history []Transition) Transition {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Host == "" {
+			return history[i]
+		}
+	}
+	return Transition{Status: pb.StepStatus_PENDING}
+}
+
+// hostResults are the latest transitions of each host since the step was last
+// reset or started, ordered by host.
+func hostResults(history []Transition) []Transition {
+	latest := map[string]Transition{}
+	for _, t := range history {
+		switch {
+		case t.Host != "":
+			latest[t.Host] = t
+		case t.Status == pb.StepStatus_PENDING || t.Status == pb.StepStatus_RUNNING:
+			latest = map[string]Transition{}
+		}
+	}
+
+	var results []Transition
+	for _, t := range latest {
+		results = append(results, t)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Host < results[j].Host
+	})
+	return results
+}
+
+// transitionError explains why a step cannot go from one status to another.
+func transitionError(step string, from, to pb.StepStatus) error {
+	return fmt.Errorf("step %s cannot become %s: it is %s", step, to, from)
+}
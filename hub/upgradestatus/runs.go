@@ -0,0 +1,155 @@
+package upgradestatus
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus/file"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/pkg/errors"
+)
+
+// A Run is one pass of the upgrade through its steps, from the first step
+// until the upgrade is reverted. How long the steps take depends mostly on how
+// many primary segments the cluster has, so a Run records that too.
+type Run struct {
+	Started     time.Time              `json:"started"`
+	Segments    int                    `json:"segments"`
+	Steps       map[string]*StepTiming `json:"steps"`
+	Conversions map[int32]*StepTiming  `json:"conversions,omitempty"` // the primaries', by dbid
+}
+
+// StepTiming is when a step, or the conversion of one segment, started and
+// finished.
+type StepTiming struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`      // zero until it has finished
+	Complete bool      `json:"complete"` // false if it failed, or has not finished
+}
+
+// Elapsed is how long it took, or has taken so far.
+func (t StepTiming) Elapsed(now time.Time) time.Duration {
+	if !t.End.IsZero() {
+		now = t.End
+	}
+	return now.Sub(t.Start)
+}
+
+// Runs keeps every Run of the upgrade in a single file in the state
+// directory, the last of which is the current one. Earlier runs on a cluster
+// of the same size tell how long the current one is likely to take.
+type Runs struct {
+	path string
+}
+
+func NewRuns(stateDir string) *Runs {
+	return &Runs{path: filepath.Join(stateDir, file.Runs)}
+}
+
+// All returns every run, oldest first.
+func (r *Runs) All() ([]Run, error) {
+	defer lockFile(r.path)()
+	return r.read()
+}
+
+// Update changes the current run, first beginning one on a cluster with the
+// given number of segments if there is none, and returns every run.
+func (r *Runs) Update(segments int, change func(run *Run)) ([]Run, error) {
+	defer lockFile(r.path)()
+
+	runs, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		runs = append(runs, newRun(segments))
+	}
+
+	change(&runs[len(runs)-1])
+	return runs, r.write(runs)
+}
+
+// Begin starts a new run on a cluster with the given number of segments,
+// unless the current one has not started any steps yet.
+func (r *Runs) Begin(segments int) error {
+	defer lockFile(r.path)()
+
+	runs, err := r.read()
+	if err != nil {
+		return err
+	}
+	if len(runs) > 0 && len(runs[len(runs)-1].Steps) == 0 {
+		runs = runs[:len(runs)-1]
+	}
+
+	return r.write(append(runs, newRun(segments)))
+}
+
+func newRun(segments int) Run {
+	return Run{
+		Started:  utils.System.Now(),
+		Segments: segments,
+		Steps:    map[string]*StepTiming{},
+	}
+}
+
+func (r *Runs) read() ([]Run, error) {
+	contents, err := utils.System.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read %s", r.path)
+	}
+
+	var runs []Run
+	err = json.Unmarshal(contents, &runs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", r.path)
+	}
+
+	for i := range runs {
+		if runs[i].Steps == nil {
+			runs[i].Steps = map[string]*StepTiming{}
+		}
+	}
+	return runs, nil
+}
+
+func (r *Runs) write(runs []Run) error {
+	err := utils.System.MkdirAll(filepath.Dir(r.path), 0700)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSONFile(r.path, runs)
+}
+
+// UsualDuration is how long the step took on average in the earlier runs
+// that it completed in, on a cluster with as many segments as the current
+// run's, and how many of those runs there were.
+func UsualDuration(runs []Run, step string) (time.Duration, int) {
+	if len(runs) == 0 {
+		return 0, 0
+	}
+
+	current := runs[len(runs)-1]
+	var total time.Duration
+	count := 0
+	for _, run := range runs[:len(runs)-1] {
+		timing, ok := run.Steps[step]
+		if !ok || !timing.Complete || run.Segments != current.Segments {
+			continue
+		}
+
+		total += timing.Elapsed(timing.End)
+		count++
+	}
+
+	if count == 0 {
+		return 0, 0
+	}
+	return total / time.Duration(count), count
+}
@@ -0,0 +1,131 @@
+package upgradestatus_test
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Runs", func() {
+	var (
+		stateDir string
+		runs     *upgradestatus.Runs
+		now      time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		runs = upgradestatus.NewRuns(stateDir)
+		now = time.Date(2018, time.March, 1, 12, 0, 0, 0, time.UTC)
+		utils.System.Now = func() time.Time { return now }
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(stateDir)
+	})
+
+	took := func(duration time.Duration) *upgradestatus.StepTiming {
+		return &upgradestatus.StepTiming{Start: now, End: now.Add(duration), Complete: true}
+	}
+
+	It("has none to begin with", func() {
+		all, err := runs.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(all).To(BeEmpty())
+	})
+
+	It("begins the first run when it is first updated", func() {
+		all, err := runs.Update(3, func(run *upgradestatus.Run) {
+			run.Steps["my-step"] = took(time.Minute)
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(all).To(Equal([]upgradestatus.Run{{
+			Started:  now,
+			Segments: 3,
+			Steps:    map[string]*upgradestatus.StepTiming{"my-step": took(time.Minute)},
+		}}))
+
+		saved, err := runs.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(saved).To(Equal(all))
+	})
+
+	It("updates only the current run", func() {
+		_, err := runs.Update(3, func(run *upgradestatus.Run) {
+			run.Steps["my-step"] = took(time.Minute)
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(runs.Begin(4)).To(Succeed())
+
+		all, err := runs.Update(3, func(run *upgradestatus.Run) {
+			run.Steps["my-step"] = took(time.Hour)
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(all).To(HaveLen(2))
+		Expect(all[0].Steps["my-step"]).To(Equal(took(time.Minute)))
+		Expect(all[1].Segments).To(Equal(4))
+		Expect(all[1].Steps["my-step"]).To(Equal(took(time.Hour)))
+	})
+
+	It("does not keep a run in which nothing happened", func() {
+		Expect(runs.Begin(3)).To(Succeed())
+		Expect(runs.Begin(4)).To(Succeed())
+
+		all, err := runs.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(all).To(HaveLen(1))
+		Expect(all[0].Segments).To(Equal(4))
+	})
+
+	It("returns an error if the runs cannot be read", func() {
+		utils.System.ReadFile = func(string) ([]byte, error) {
+			return nil, errors.New("permission denied")
+		}
+
+		_, err := runs.Update(3, func(*upgradestatus.Run) {
+			Fail("updated a run that could not be read")
+		})
+		Expect(err).To(MatchError(ContainSubstring("permission denied")))
+	})
+
+	Describe("UsualDuration", func() {
+		It("averages the earlier runs in which the step completed on a cluster of the same size", func() {
+			failed := took(time.Second)
+			failed.Complete = false
+
+			all := []upgradestatus.Run{
+				{Segments: 3, Steps: map[string]*upgradestatus.StepTiming{"my-step": took(time.Minute)}},
+				{Segments: 3, Steps: map[string]*upgradestatus.StepTiming{"my-step": failed}},
+				{Segments: 8, Steps: map[string]*upgradestatus.StepTiming{"my-step": took(time.Hour)}},
+				{Segments: 3, Steps: map[string]*upgradestatus.StepTiming{"my-step": took(3 * time.Minute)}},
+				{Segments: 3, Steps: map[string]*upgradestatus.StepTiming{}},
+				{Segments: 3, Steps: map[string]*upgradestatus.StepTiming{"my-step": took(time.Hour)}},
+			}
+
+			usual, count := upgradestatus.UsualDuration(all, "my-step")
+			Expect(usual).To(Equal(2 * time.Minute))
+			Expect(count).To(Equal(2))
+		})
+
+		It("has nothing to go on without earlier runs", func() {
+			usual, count := upgradestatus.UsualDuration([]upgradestatus.Run{
+				{Segments: 3, Steps: map[string]*upgradestatus.StepTiming{"my-step": took(time.Minute)}},
+			}, "my-step")
+			Expect(usual).To(BeZero())
+			Expect(count).To(BeZero())
+		})
+	})
+})
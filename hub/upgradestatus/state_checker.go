@@ -10,6 +10,8 @@ import (
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 )
 
+// StateCheck reads the status of a step from the marker files that earlier
+// versions of the hub kept, for ChecklistManager.ImportMarkerFiles.
 type StateCheck struct {
 	Path string
 	Step pb.UpgradeSteps
@@ -36,9 +38,8 @@ func (c StateCheck) GetStatus() pb.StepStatus {
 		gplog.Error("Couldn't search status directory %s: %s", c.Path, err.Error())
 	}
 
-	// FIXME: there's a race here: we delete the status file and then recreate
-	// it in the ChecklistManager, which means we can go from RUNNING to PENDING
-	// to COMPLETE/FAILED.
+	// Earlier versions deleted one marker before creating the next, so a hub
+	// that died in between may have left none, or more than one.
 	if len(files) > 1 {
 		gplog.Error("Status directory %s has more than one file", c.Path)
 		return pb.StepStatus_PENDING
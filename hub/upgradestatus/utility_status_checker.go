@@ -54,13 +54,16 @@ func GetUtilityStatus(binaryName, utilityStatePath, dataDir, progressFilePattern
 /*
  * We call external binaries with an external data directory, so passing in the
  * data directory allows finding that particular invocation if other processes
- * using the same binary are running concurrently.
+ * using the same binary are running concurrently. pgrep matches the whole
+ * command line against the pattern, whose first letter is bracketed so that
+ * it doesn't match the shell that runs pgrep.
  */
 func isBinaryRunning(binaryName, dataDir string, executor cluster.Executor) bool {
-	command := fmt.Sprintf("pgrep -f %s", binaryName)
+	pattern := fmt.Sprintf("[%s]%s", binaryName[:1], binaryName[1:])
 	if dataDir != "" {
-		command = fmt.Sprintf("%s | grep %s", command, dataDir)
+		pattern = fmt.Sprintf("%s.*%s", pattern, dataDir)
 	}
+	command := fmt.Sprintf("pgrep -f '%s'", pattern)
 	binaryPids, err := executor.ExecuteLocalCommand(command)
 	if err == nil && len(binaryPids) != 0 {
 		return true
@@ -95,7 +95,7 @@ var _ = Describe("utility status checker", func() {
 		testhelper.MockFileContents("Upgrade complete")
 		defer operating.InitializeSystemFunctions()
 		status := upgradestatus.SegmentConversionStatus("/tmp", "/data/dir", testExecutor)
-		Expect(testExecutor.LocalCommands).To(Equal([]string{"pgrep -f pg_upgrade | grep /data/dir"}))
+		Expect(testExecutor.LocalCommands).To(Equal([]string{"pgrep -f '[p]g_upgrade.*/data/dir'"}))
 		Expect(status).To(Equal(pb.StepStatus_COMPLETE))
 	})
 
@@ -23,6 +23,32 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type SegmentRole int32
+
+const (
+	SegmentRole_UNKNOWN_ROLE SegmentRole = 0
+	SegmentRole_MASTER       SegmentRole = 1
+	SegmentRole_PRIMARY      SegmentRole = 2
+)
+
+var SegmentRole_name = map[int32]string{
+	0: "UNKNOWN_ROLE",
+	1: "MASTER",
+	2: "PRIMARY",
+}
+var SegmentRole_value = map[string]int32{
+	"UNKNOWN_ROLE": 0,
+	"MASTER":       1,
+	"PRIMARY":      2,
+}
+
+func (x SegmentRole) String() string {
+	return proto.EnumName(SegmentRole_name, int32(x))
+}
+func (SegmentRole) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{0}
+}
+
 type UpgradeSteps int32
 
 const (
@@ -37,6 +63,8 @@ const (
 	UpgradeSteps_CONVERT_PRIMARIES      UpgradeSteps = 8
 	UpgradeSteps_VALIDATE_START_CLUSTER UpgradeSteps = 9
 	UpgradeSteps_RECONFIGURE_PORTS      UpgradeSteps = 10
+	UpgradeSteps_REBUILD_MIRRORS        UpgradeSteps = 11
+	UpgradeSteps_REBUILD_STANDBY        UpgradeSteps = 12
 )
 
 var UpgradeSteps_name = map[int32]string{
@@ -51,6 +79,8 @@ var UpgradeSteps_name = map[int32]string{
 	8:  "CONVERT_PRIMARIES",
 	9:  "VALIDATE_START_CLUSTER",
 	10: "RECONFIGURE_PORTS",
+	11: "REBUILD_MIRRORS",
+	12: "REBUILD_STANDBY",
 }
 var UpgradeSteps_value = map[string]int32{
 	"UNKNOWN_STEP":           0,
@@ -64,13 +94,15 @@ var UpgradeSteps_value = map[string]int32{
 	"CONVERT_PRIMARIES":      8,
 	"VALIDATE_START_CLUSTER": 9,
 	"RECONFIGURE_PORTS":      10,
+	"REBUILD_MIRRORS":        11,
+	"REBUILD_STANDBY":        12,
 }
 
 func (x UpgradeSteps) String() string {
 	return proto.EnumName(UpgradeSteps_name, int32(x))
 }
 func (UpgradeSteps) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{0}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{1}
 }
 
 type StepStatus int32
@@ -102,10 +134,12 @@ func (x StepStatus) String() string {
 	return proto.EnumName(StepStatus_name, int32(x))
 }
 func (StepStatus) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{1}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{2}
 }
 
 type UpgradeReconfigurePortsRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -135,10 +169,25 @@ func (m *UpgradeReconfigurePortsRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeReconfigurePortsRequest proto.InternalMessageInfo
 
+func (m *UpgradeReconfigurePortsRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
+}
+
+func (m *UpgradeReconfigurePortsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 type UpgradeReconfigurePortsReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *UpgradeReconfigurePortsReply) Reset()         { *m = UpgradeReconfigurePortsReply{} }
@@ -165,7 +214,184 @@ func (m *UpgradeReconfigurePortsReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeReconfigurePortsReply proto.InternalMessageInfo
 
+func (m *UpgradeReconfigurePortsReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
+type UpgradeRebuildMirrorsRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpgradeRebuildMirrorsRequest) Reset()         { *m = UpgradeRebuildMirrorsRequest{} }
+func (m *UpgradeRebuildMirrorsRequest) String() string { return proto.CompactTextString(m) }
+func (*UpgradeRebuildMirrorsRequest) ProtoMessage()    {}
+func (*UpgradeRebuildMirrorsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{2}
+}
+func (m *UpgradeRebuildMirrorsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradeRebuildMirrorsRequest.Unmarshal(m, b)
+}
+func (m *UpgradeRebuildMirrorsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradeRebuildMirrorsRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpgradeRebuildMirrorsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradeRebuildMirrorsRequest.Merge(dst, src)
+}
+func (m *UpgradeRebuildMirrorsRequest) XXX_Size() int {
+	return xxx_messageInfo_UpgradeRebuildMirrorsRequest.Size(m)
+}
+func (m *UpgradeRebuildMirrorsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradeRebuildMirrorsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpgradeRebuildMirrorsRequest proto.InternalMessageInfo
+
+func (m *UpgradeRebuildMirrorsRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
+}
+
+func (m *UpgradeRebuildMirrorsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type UpgradeRebuildMirrorsReply struct {
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *UpgradeRebuildMirrorsReply) Reset()         { *m = UpgradeRebuildMirrorsReply{} }
+func (m *UpgradeRebuildMirrorsReply) String() string { return proto.CompactTextString(m) }
+func (*UpgradeRebuildMirrorsReply) ProtoMessage()    {}
+func (*UpgradeRebuildMirrorsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{3}
+}
+func (m *UpgradeRebuildMirrorsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradeRebuildMirrorsReply.Unmarshal(m, b)
+}
+func (m *UpgradeRebuildMirrorsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradeRebuildMirrorsReply.Marshal(b, m, deterministic)
+}
+func (dst *UpgradeRebuildMirrorsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradeRebuildMirrorsReply.Merge(dst, src)
+}
+func (m *UpgradeRebuildMirrorsReply) XXX_Size() int {
+	return xxx_messageInfo_UpgradeRebuildMirrorsReply.Size(m)
+}
+func (m *UpgradeRebuildMirrorsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradeRebuildMirrorsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpgradeRebuildMirrorsReply proto.InternalMessageInfo
+
+func (m *UpgradeRebuildMirrorsReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
+type UpgradeRebuildStandbyRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpgradeRebuildStandbyRequest) Reset()         { *m = UpgradeRebuildStandbyRequest{} }
+func (m *UpgradeRebuildStandbyRequest) String() string { return proto.CompactTextString(m) }
+func (*UpgradeRebuildStandbyRequest) ProtoMessage()    {}
+func (*UpgradeRebuildStandbyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{4}
+}
+func (m *UpgradeRebuildStandbyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradeRebuildStandbyRequest.Unmarshal(m, b)
+}
+func (m *UpgradeRebuildStandbyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradeRebuildStandbyRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpgradeRebuildStandbyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradeRebuildStandbyRequest.Merge(dst, src)
+}
+func (m *UpgradeRebuildStandbyRequest) XXX_Size() int {
+	return xxx_messageInfo_UpgradeRebuildStandbyRequest.Size(m)
+}
+func (m *UpgradeRebuildStandbyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradeRebuildStandbyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpgradeRebuildStandbyRequest proto.InternalMessageInfo
+
+func (m *UpgradeRebuildStandbyRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
+}
+
+func (m *UpgradeRebuildStandbyRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type UpgradeRebuildStandbyReply struct {
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *UpgradeRebuildStandbyReply) Reset()         { *m = UpgradeRebuildStandbyReply{} }
+func (m *UpgradeRebuildStandbyReply) String() string { return proto.CompactTextString(m) }
+func (*UpgradeRebuildStandbyReply) ProtoMessage()    {}
+func (*UpgradeRebuildStandbyReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{5}
+}
+func (m *UpgradeRebuildStandbyReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradeRebuildStandbyReply.Unmarshal(m, b)
+}
+func (m *UpgradeRebuildStandbyReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradeRebuildStandbyReply.Marshal(b, m, deterministic)
+}
+func (dst *UpgradeRebuildStandbyReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradeRebuildStandbyReply.Merge(dst, src)
+}
+func (m *UpgradeRebuildStandbyReply) XXX_Size() int {
+	return xxx_messageInfo_UpgradeRebuildStandbyReply.Size(m)
+}
+func (m *UpgradeRebuildStandbyReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradeRebuildStandbyReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpgradeRebuildStandbyReply proto.InternalMessageInfo
+
+func (m *UpgradeRebuildStandbyReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
 type UpgradeConvertPrimariesRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -175,7 +401,7 @@ func (m *UpgradeConvertPrimariesRequest) Reset()         { *m = UpgradeConvertPr
 func (m *UpgradeConvertPrimariesRequest) String() string { return proto.CompactTextString(m) }
 func (*UpgradeConvertPrimariesRequest) ProtoMessage()    {}
 func (*UpgradeConvertPrimariesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{2}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{6}
 }
 func (m *UpgradeConvertPrimariesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeConvertPrimariesRequest.Unmarshal(m, b)
@@ -195,17 +421,32 @@ func (m *UpgradeConvertPrimariesRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeConvertPrimariesRequest proto.InternalMessageInfo
 
+func (m *UpgradeConvertPrimariesRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
+}
+
+func (m *UpgradeConvertPrimariesRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 type UpgradeConvertPrimariesReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *UpgradeConvertPrimariesReply) Reset()         { *m = UpgradeConvertPrimariesReply{} }
 func (m *UpgradeConvertPrimariesReply) String() string { return proto.CompactTextString(m) }
 func (*UpgradeConvertPrimariesReply) ProtoMessage()    {}
 func (*UpgradeConvertPrimariesReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{3}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{7}
 }
 func (m *UpgradeConvertPrimariesReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeConvertPrimariesReply.Unmarshal(m, b)
@@ -225,7 +466,16 @@ func (m *UpgradeConvertPrimariesReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeConvertPrimariesReply proto.InternalMessageInfo
 
+func (m *UpgradeConvertPrimariesReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
 type UpgradeShareOidsRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -235,7 +485,7 @@ func (m *UpgradeShareOidsRequest) Reset()         { *m = UpgradeShareOidsRequest
 func (m *UpgradeShareOidsRequest) String() string { return proto.CompactTextString(m) }
 func (*UpgradeShareOidsRequest) ProtoMessage()    {}
 func (*UpgradeShareOidsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{4}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{8}
 }
 func (m *UpgradeShareOidsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeShareOidsRequest.Unmarshal(m, b)
@@ -255,17 +505,32 @@ func (m *UpgradeShareOidsRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeShareOidsRequest proto.InternalMessageInfo
 
+func (m *UpgradeShareOidsRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
+}
+
+func (m *UpgradeShareOidsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 type UpgradeShareOidsReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *UpgradeShareOidsReply) Reset()         { *m = UpgradeShareOidsReply{} }
 func (m *UpgradeShareOidsReply) String() string { return proto.CompactTextString(m) }
 func (*UpgradeShareOidsReply) ProtoMessage()    {}
 func (*UpgradeShareOidsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{5}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{9}
 }
 func (m *UpgradeShareOidsReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeShareOidsReply.Unmarshal(m, b)
@@ -285,7 +550,16 @@ func (m *UpgradeShareOidsReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeShareOidsReply proto.InternalMessageInfo
 
+func (m *UpgradeShareOidsReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
 type UpgradeValidateStartClusterRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -295,7 +569,7 @@ func (m *UpgradeValidateStartClusterRequest) Reset()         { *m = UpgradeValid
 func (m *UpgradeValidateStartClusterRequest) String() string { return proto.CompactTextString(m) }
 func (*UpgradeValidateStartClusterRequest) ProtoMessage()    {}
 func (*UpgradeValidateStartClusterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{6}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{10}
 }
 func (m *UpgradeValidateStartClusterRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeValidateStartClusterRequest.Unmarshal(m, b)
@@ -315,17 +589,32 @@ func (m *UpgradeValidateStartClusterRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeValidateStartClusterRequest proto.InternalMessageInfo
 
+func (m *UpgradeValidateStartClusterRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
+}
+
+func (m *UpgradeValidateStartClusterRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 type UpgradeValidateStartClusterReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *UpgradeValidateStartClusterReply) Reset()         { *m = UpgradeValidateStartClusterReply{} }
 func (m *UpgradeValidateStartClusterReply) String() string { return proto.CompactTextString(m) }
 func (*UpgradeValidateStartClusterReply) ProtoMessage()    {}
 func (*UpgradeValidateStartClusterReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{7}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{11}
 }
 func (m *UpgradeValidateStartClusterReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UpgradeValidateStartClusterReply.Unmarshal(m, b)
@@ -345,6 +634,13 @@ func (m *UpgradeValidateStartClusterReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeValidateStartClusterReply proto.InternalMessageInfo
 
+func (m *UpgradeValidateStartClusterReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
 type PingRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -355,7 +651,7 @@ func (m *PingRequest) Reset()         { *m = PingRequest{} }
 func (m *PingRequest) String() string { return proto.CompactTextString(m) }
 func (*PingRequest) ProtoMessage()    {}
 func (*PingRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{8}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{12}
 }
 func (m *PingRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PingRequest.Unmarshal(m, b)
@@ -385,7 +681,7 @@ func (m *PingReply) Reset()         { *m = PingReply{} }
 func (m *PingReply) String() string { return proto.CompactTextString(m) }
 func (*PingReply) ProtoMessage()    {}
 func (*PingReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{9}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{13}
 }
 func (m *PingReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PingReply.Unmarshal(m, b)
@@ -415,7 +711,7 @@ func (m *StatusConversionRequest) Reset()         { *m = StatusConversionRequest
 func (m *StatusConversionRequest) String() string { return proto.CompactTextString(m) }
 func (*StatusConversionRequest) ProtoMessage()    {}
 func (*StatusConversionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{10}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{14}
 }
 func (m *StatusConversionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatusConversionRequest.Unmarshal(m, b)
@@ -436,17 +732,17 @@ func (m *StatusConversionRequest) XXX_DiscardUnknown() {
 var xxx_messageInfo_StatusConversionRequest proto.InternalMessageInfo
 
 type StatusConversionReply struct {
-	ConversionStatuses   []string `protobuf:"bytes,1,rep,name=conversionStatuses,proto3" json:"conversionStatuses,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ConversionStatuses   []*ConversionStatus `protobuf:"bytes,2,rep,name=conversionStatuses,proto3" json:"conversionStatuses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *StatusConversionReply) Reset()         { *m = StatusConversionReply{} }
 func (m *StatusConversionReply) String() string { return proto.CompactTextString(m) }
 func (*StatusConversionReply) ProtoMessage()    {}
 func (*StatusConversionReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{11}
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{15}
 }
 func (m *StatusConversionReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatusConversionReply.Unmarshal(m, b)
@@ -466,902 +762,2829 @@ func (m *StatusConversionReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StatusConversionReply proto.InternalMessageInfo
 
-func (m *StatusConversionReply) GetConversionStatuses() []string {
+func (m *StatusConversionReply) GetConversionStatuses() []*ConversionStatus {
 	if m != nil {
 		return m.ConversionStatuses
 	}
 	return nil
 }
 
-type StatusUpgradeRequest struct {
+type StatusAgentsRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *StatusUpgradeRequest) Reset()         { *m = StatusUpgradeRequest{} }
-func (m *StatusUpgradeRequest) String() string { return proto.CompactTextString(m) }
-func (*StatusUpgradeRequest) ProtoMessage()    {}
-func (*StatusUpgradeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{12}
+func (m *StatusAgentsRequest) Reset()         { *m = StatusAgentsRequest{} }
+func (m *StatusAgentsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatusAgentsRequest) ProtoMessage()    {}
+func (*StatusAgentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{16}
 }
-func (m *StatusUpgradeRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StatusUpgradeRequest.Unmarshal(m, b)
+func (m *StatusAgentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusAgentsRequest.Unmarshal(m, b)
 }
-func (m *StatusUpgradeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StatusUpgradeRequest.Marshal(b, m, deterministic)
+func (m *StatusAgentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusAgentsRequest.Marshal(b, m, deterministic)
 }
-func (dst *StatusUpgradeRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StatusUpgradeRequest.Merge(dst, src)
+func (dst *StatusAgentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusAgentsRequest.Merge(dst, src)
 }
-func (m *StatusUpgradeRequest) XXX_Size() int {
-	return xxx_messageInfo_StatusUpgradeRequest.Size(m)
+func (m *StatusAgentsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatusAgentsRequest.Size(m)
 }
-func (m *StatusUpgradeRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_StatusUpgradeRequest.DiscardUnknown(m)
+func (m *StatusAgentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusAgentsRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_StatusUpgradeRequest proto.InternalMessageInfo
+var xxx_messageInfo_StatusAgentsRequest proto.InternalMessageInfo
 
-type StatusUpgradeReply struct {
-	ListOfUpgradeStepStatuses []*UpgradeStepStatus `protobuf:"bytes,1,rep,name=listOfUpgradeStepStatuses,proto3" json:"listOfUpgradeStepStatuses,omitempty"`
-	XXX_NoUnkeyedLiteral      struct{}             `json:"-"`
-	XXX_unrecognized          []byte               `json:"-"`
-	XXX_sizecache             int32                `json:"-"`
+type StatusAgentsReply struct {
+	Agents               []*AgentStatus `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
-func (m *StatusUpgradeReply) Reset()         { *m = StatusUpgradeReply{} }
-func (m *StatusUpgradeReply) String() string { return proto.CompactTextString(m) }
-func (*StatusUpgradeReply) ProtoMessage()    {}
-func (*StatusUpgradeReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{13}
+func (m *StatusAgentsReply) Reset()         { *m = StatusAgentsReply{} }
+func (m *StatusAgentsReply) String() string { return proto.CompactTextString(m) }
+func (*StatusAgentsReply) ProtoMessage()    {}
+func (*StatusAgentsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{17}
 }
-func (m *StatusUpgradeReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StatusUpgradeReply.Unmarshal(m, b)
+func (m *StatusAgentsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusAgentsReply.Unmarshal(m, b)
 }
-func (m *StatusUpgradeReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StatusUpgradeReply.Marshal(b, m, deterministic)
+func (m *StatusAgentsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusAgentsReply.Marshal(b, m, deterministic)
 }
-func (dst *StatusUpgradeReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StatusUpgradeReply.Merge(dst, src)
+func (dst *StatusAgentsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusAgentsReply.Merge(dst, src)
 }
-func (m *StatusUpgradeReply) XXX_Size() int {
-	return xxx_messageInfo_StatusUpgradeReply.Size(m)
+func (m *StatusAgentsReply) XXX_Size() int {
+	return xxx_messageInfo_StatusAgentsReply.Size(m)
 }
-func (m *StatusUpgradeReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_StatusUpgradeReply.DiscardUnknown(m)
+func (m *StatusAgentsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusAgentsReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_StatusUpgradeReply proto.InternalMessageInfo
+var xxx_messageInfo_StatusAgentsReply proto.InternalMessageInfo
 
-func (m *StatusUpgradeReply) GetListOfUpgradeStepStatuses() []*UpgradeStepStatus {
+func (m *StatusAgentsReply) GetAgents() []*AgentStatus {
 	if m != nil {
-		return m.ListOfUpgradeStepStatuses
+		return m.Agents
 	}
 	return nil
 }
 
-type UpgradeStepStatus struct {
-	Step                 UpgradeSteps `protobuf:"varint,1,opt,name=step,proto3,enum=idl.UpgradeSteps" json:"step,omitempty"`
-	Status               StepStatus   `protobuf:"varint,2,opt,name=status,proto3,enum=idl.StepStatus" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+// AgentStatus is what the hub knows about its connection to the agent on one
+// host.
+type AgentStatus struct {
+	Hostname             string   `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Healthy              bool     `protobuf:"varint,2,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	State                string   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Failures             int32    `protobuf:"varint,4,opt,name=failures,proto3" json:"failures,omitempty"`
+	LastError            string   `protobuf:"bytes,5,opt,name=lastError,proto3" json:"lastError,omitempty"`
+	RetryAt              int64    `protobuf:"varint,6,opt,name=retryAt,proto3" json:"retryAt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpgradeStepStatus) Reset()         { *m = UpgradeStepStatus{} }
-func (m *UpgradeStepStatus) String() string { return proto.CompactTextString(m) }
-func (*UpgradeStepStatus) ProtoMessage()    {}
-func (*UpgradeStepStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{14}
+func (m *AgentStatus) Reset()         { *m = AgentStatus{} }
+func (m *AgentStatus) String() string { return proto.CompactTextString(m) }
+func (*AgentStatus) ProtoMessage()    {}
+func (*AgentStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{18}
 }
-func (m *UpgradeStepStatus) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpgradeStepStatus.Unmarshal(m, b)
+func (m *AgentStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentStatus.Unmarshal(m, b)
 }
-func (m *UpgradeStepStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpgradeStepStatus.Marshal(b, m, deterministic)
+func (m *AgentStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentStatus.Marshal(b, m, deterministic)
 }
-func (dst *UpgradeStepStatus) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpgradeStepStatus.Merge(dst, src)
+func (dst *AgentStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentStatus.Merge(dst, src)
 }
-func (m *UpgradeStepStatus) XXX_Size() int {
-	return xxx_messageInfo_UpgradeStepStatus.Size(m)
+func (m *AgentStatus) XXX_Size() int {
+	return xxx_messageInfo_AgentStatus.Size(m)
 }
-func (m *UpgradeStepStatus) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpgradeStepStatus.DiscardUnknown(m)
+func (m *AgentStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentStatus proto.InternalMessageInfo
+
+func (m *AgentStatus) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *AgentStatus) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *AgentStatus) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *AgentStatus) GetFailures() int32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *AgentStatus) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *AgentStatus) GetRetryAt() int64 {
+	if m != nil {
+		return m.RetryAt
+	}
+	return 0
+}
+
+// ConversionStatus describes the pg_upgrade run for a single segment.
+type ConversionStatus struct {
+	Dbid                 int32       `protobuf:"varint,1,opt,name=dbid,proto3" json:"dbid,omitempty"`
+	Content              int32       `protobuf:"varint,2,opt,name=content,proto3" json:"content,omitempty"`
+	Role                 SegmentRole `protobuf:"varint,3,opt,name=role,proto3,enum=idl.SegmentRole" json:"role,omitempty"`
+	Hostname             string      `protobuf:"bytes,4,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Status               StepStatus  `protobuf:"varint,5,opt,name=status,proto3,enum=idl.StepStatus" json:"status,omitempty"`
+	StartTime            int64       `protobuf:"varint,6,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime              int64       `protobuf:"varint,7,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	ErrorMessage         string      `protobuf:"bytes,8,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	Phase                string      `protobuf:"bytes,9,opt,name=phase,proto3" json:"phase,omitempty"`
+	PercentDone          int32       `protobuf:"varint,10,opt,name=percentDone,proto3" json:"percentDone,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ConversionStatus) Reset()         { *m = ConversionStatus{} }
+func (m *ConversionStatus) String() string { return proto.CompactTextString(m) }
+func (*ConversionStatus) ProtoMessage()    {}
+func (*ConversionStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{19}
+}
+func (m *ConversionStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConversionStatus.Unmarshal(m, b)
+}
+func (m *ConversionStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConversionStatus.Marshal(b, m, deterministic)
+}
+func (dst *ConversionStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConversionStatus.Merge(dst, src)
+}
+func (m *ConversionStatus) XXX_Size() int {
+	return xxx_messageInfo_ConversionStatus.Size(m)
+}
+func (m *ConversionStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConversionStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConversionStatus proto.InternalMessageInfo
+
+func (m *ConversionStatus) GetDbid() int32 {
+	if m != nil {
+		return m.Dbid
+	}
+	return 0
+}
+
+func (m *ConversionStatus) GetContent() int32 {
+	if m != nil {
+		return m.Content
+	}
+	return 0
+}
+
+func (m *ConversionStatus) GetRole() SegmentRole {
+	if m != nil {
+		return m.Role
+	}
+	return SegmentRole_UNKNOWN_ROLE
+}
+
+func (m *ConversionStatus) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *ConversionStatus) GetStatus() StepStatus {
+	if m != nil {
+		return m.Status
+	}
+	return StepStatus_UNKNOWN_STATUS
+}
+
+func (m *ConversionStatus) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *ConversionStatus) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+func (m *ConversionStatus) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
+func (m *ConversionStatus) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *ConversionStatus) GetPercentDone() int32 {
+	if m != nil {
+		return m.PercentDone
+	}
+	return 0
+}
+
+type StatusUpgradeRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatusUpgradeRequest) Reset()         { *m = StatusUpgradeRequest{} }
+func (m *StatusUpgradeRequest) String() string { return proto.CompactTextString(m) }
+func (*StatusUpgradeRequest) ProtoMessage()    {}
+func (*StatusUpgradeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{20}
+}
+func (m *StatusUpgradeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusUpgradeRequest.Unmarshal(m, b)
+}
+func (m *StatusUpgradeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusUpgradeRequest.Marshal(b, m, deterministic)
+}
+func (dst *StatusUpgradeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusUpgradeRequest.Merge(dst, src)
+}
+func (m *StatusUpgradeRequest) XXX_Size() int {
+	return xxx_messageInfo_StatusUpgradeRequest.Size(m)
+}
+func (m *StatusUpgradeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusUpgradeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusUpgradeRequest proto.InternalMessageInfo
+
+type StatusUpgradeReply struct {
+	ListOfUpgradeStepStatuses []*UpgradeStepStatus `protobuf:"bytes,1,rep,name=listOfUpgradeStepStatuses,proto3" json:"listOfUpgradeStepStatuses,omitempty"`
+	ElapsedSeconds            int64                `protobuf:"varint,2,opt,name=elapsedSeconds,proto3" json:"elapsedSeconds,omitempty"`
+	RemainingSeconds          int64                `protobuf:"varint,3,opt,name=remainingSeconds,proto3" json:"remainingSeconds,omitempty"`
+	XXX_NoUnkeyedLiteral      struct{}             `json:"-"`
+	XXX_unrecognized          []byte               `json:"-"`
+	XXX_sizecache             int32                `json:"-"`
+}
+
+func (m *StatusUpgradeReply) Reset()         { *m = StatusUpgradeReply{} }
+func (m *StatusUpgradeReply) String() string { return proto.CompactTextString(m) }
+func (*StatusUpgradeReply) ProtoMessage()    {}
+func (*StatusUpgradeReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{21}
+}
+func (m *StatusUpgradeReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusUpgradeReply.Unmarshal(m, b)
+}
+func (m *StatusUpgradeReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusUpgradeReply.Marshal(b, m, deterministic)
+}
+func (dst *StatusUpgradeReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusUpgradeReply.Merge(dst, src)
+}
+func (m *StatusUpgradeReply) XXX_Size() int {
+	return xxx_messageInfo_StatusUpgradeReply.Size(m)
+}
+func (m *StatusUpgradeReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusUpgradeReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusUpgradeReply proto.InternalMessageInfo
+
+func (m *StatusUpgradeReply) GetListOfUpgradeStepStatuses() []*UpgradeStepStatus {
+	if m != nil {
+		return m.ListOfUpgradeStepStatuses
+	}
+	return nil
+}
+
+func (m *StatusUpgradeReply) GetElapsedSeconds() int64 {
+	if m != nil {
+		return m.ElapsedSeconds
+	}
+	return 0
+}
+
+func (m *StatusUpgradeReply) GetRemainingSeconds() int64 {
+	if m != nil {
+		return m.RemainingSeconds
+	}
+	return 0
+}
+
+type WatchUpgradeRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchUpgradeRequest) Reset()         { *m = WatchUpgradeRequest{} }
+func (m *WatchUpgradeRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchUpgradeRequest) ProtoMessage()    {}
+func (*WatchUpgradeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{22}
+}
+func (m *WatchUpgradeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchUpgradeRequest.Unmarshal(m, b)
+}
+func (m *WatchUpgradeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchUpgradeRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchUpgradeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchUpgradeRequest.Merge(dst, src)
+}
+func (m *WatchUpgradeRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchUpgradeRequest.Size(m)
+}
+func (m *WatchUpgradeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchUpgradeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchUpgradeRequest proto.InternalMessageInfo
+
+// WatchUpgradeReply is streamed once for each step when the watch starts, and
+// again every time a step's status changes.
+type WatchUpgradeReply struct {
+	StepStatus           *UpgradeStepStatus `protobuf:"bytes,1,opt,name=stepStatus,proto3" json:"stepStatus,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *WatchUpgradeReply) Reset()         { *m = WatchUpgradeReply{} }
+func (m *WatchUpgradeReply) String() string { return proto.CompactTextString(m) }
+func (*WatchUpgradeReply) ProtoMessage()    {}
+func (*WatchUpgradeReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{23}
+}
+func (m *WatchUpgradeReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchUpgradeReply.Unmarshal(m, b)
+}
+func (m *WatchUpgradeReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchUpgradeReply.Marshal(b, m, deterministic)
+}
+func (dst *WatchUpgradeReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchUpgradeReply.Merge(dst, src)
+}
+func (m *WatchUpgradeReply) XXX_Size() int {
+	return xxx_messageInfo_WatchUpgradeReply.Size(m)
+}
+func (m *WatchUpgradeReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchUpgradeReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchUpgradeReply proto.InternalMessageInfo
+
+func (m *WatchUpgradeReply) GetStepStatus() *UpgradeStepStatus {
+	if m != nil {
+		return m.StepStatus
+	}
+	return nil
+}
+
+type UpgradeStepStatus struct {
+	Step                 UpgradeSteps      `protobuf:"varint,1,opt,name=step,proto3,enum=idl.UpgradeSteps" json:"step,omitempty"`
+	Status               StepStatus        `protobuf:"varint,2,opt,name=status,proto3,enum=idl.StepStatus" json:"status,omitempty"`
+	Hosts                []*HostStepStatus `protobuf:"bytes,3,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	History              []*StepTransition `protobuf:"bytes,4,rep,name=history,proto3" json:"history,omitempty"`
+	Failure              *StepFailure      `protobuf:"bytes,5,opt,name=failure,proto3" json:"failure,omitempty"`
+	Timing               *StepTiming       `protobuf:"bytes,6,opt,name=timing,proto3" json:"timing,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *UpgradeStepStatus) Reset()         { *m = UpgradeStepStatus{} }
+func (m *UpgradeStepStatus) String() string { return proto.CompactTextString(m) }
+func (*UpgradeStepStatus) ProtoMessage()    {}
+func (*UpgradeStepStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{24}
+}
+func (m *UpgradeStepStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradeStepStatus.Unmarshal(m, b)
+}
+func (m *UpgradeStepStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradeStepStatus.Marshal(b, m, deterministic)
+}
+func (dst *UpgradeStepStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradeStepStatus.Merge(dst, src)
+}
+func (m *UpgradeStepStatus) XXX_Size() int {
+	return xxx_messageInfo_UpgradeStepStatus.Size(m)
+}
+func (m *UpgradeStepStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradeStepStatus.DiscardUnknown(m)
 }
 
 var xxx_messageInfo_UpgradeStepStatus proto.InternalMessageInfo
 
-func (m *UpgradeStepStatus) GetStep() UpgradeSteps {
+func (m *UpgradeStepStatus) GetStep() UpgradeSteps {
+	if m != nil {
+		return m.Step
+	}
+	return UpgradeSteps_UNKNOWN_STEP
+}
+
+func (m *UpgradeStepStatus) GetStatus() StepStatus {
+	if m != nil {
+		return m.Status
+	}
+	return StepStatus_UNKNOWN_STATUS
+}
+
+func (m *UpgradeStepStatus) GetHosts() []*HostStepStatus {
+	if m != nil {
+		return m.Hosts
+	}
+	return nil
+}
+
+func (m *UpgradeStepStatus) GetHistory() []*StepTransition {
+	if m != nil {
+		return m.History
+	}
+	return nil
+}
+
+func (m *UpgradeStepStatus) GetFailure() *StepFailure {
+	if m != nil {
+		return m.Failure
+	}
+	return nil
+}
+
+func (m *UpgradeStepStatus) GetTiming() *StepTiming {
+	if m != nil {
+		return m.Timing
+	}
+	return nil
+}
+
+// StepTiming is how long the latest attempt at a step took, or has taken so
+// far, and how long it usually takes. The estimates come from earlier runs of
+// the upgrade on a cluster with as many segments.
+type StepTiming struct {
+	StartTime            int64    `protobuf:"varint,1,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime              int64    `protobuf:"varint,2,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	ElapsedSeconds       int64    `protobuf:"varint,3,opt,name=elapsedSeconds,proto3" json:"elapsedSeconds,omitempty"`
+	RemainingSeconds     int64    `protobuf:"varint,4,opt,name=remainingSeconds,proto3" json:"remainingSeconds,omitempty"`
+	UsualSeconds         int64    `protobuf:"varint,5,opt,name=usualSeconds,proto3" json:"usualSeconds,omitempty"`
+	EarlierRuns          int32    `protobuf:"varint,6,opt,name=earlierRuns,proto3" json:"earlierRuns,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StepTiming) Reset()         { *m = StepTiming{} }
+func (m *StepTiming) String() string { return proto.CompactTextString(m) }
+func (*StepTiming) ProtoMessage()    {}
+func (*StepTiming) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{25}
+}
+func (m *StepTiming) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StepTiming.Unmarshal(m, b)
+}
+func (m *StepTiming) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StepTiming.Marshal(b, m, deterministic)
+}
+func (dst *StepTiming) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StepTiming.Merge(dst, src)
+}
+func (m *StepTiming) XXX_Size() int {
+	return xxx_messageInfo_StepTiming.Size(m)
+}
+func (m *StepTiming) XXX_DiscardUnknown() {
+	xxx_messageInfo_StepTiming.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StepTiming proto.InternalMessageInfo
+
+func (m *StepTiming) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *StepTiming) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+func (m *StepTiming) GetElapsedSeconds() int64 {
+	if m != nil {
+		return m.ElapsedSeconds
+	}
+	return 0
+}
+
+func (m *StepTiming) GetRemainingSeconds() int64 {
+	if m != nil {
+		return m.RemainingSeconds
+	}
+	return 0
+}
+
+func (m *StepTiming) GetUsualSeconds() int64 {
+	if m != nil {
+		return m.UsualSeconds
+	}
+	return 0
+}
+
+func (m *StepTiming) GetEarlierRuns() int32 {
+	if m != nil {
+		return m.EarlierRuns
+	}
+	return 0
+}
+
+// StepFailure explains why a step failed. The command, its exit code and the
+// log file are only given when the step failed because a command did.
+type StepFailure struct {
+	Error                string   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Command              string   `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	ExitCode             int32    `protobuf:"varint,3,opt,name=exitCode,proto3" json:"exitCode,omitempty"`
+	LogFile              string   `protobuf:"bytes,4,opt,name=logFile,proto3" json:"logFile,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StepFailure) Reset()         { *m = StepFailure{} }
+func (m *StepFailure) String() string { return proto.CompactTextString(m) }
+func (*StepFailure) ProtoMessage()    {}
+func (*StepFailure) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{26}
+}
+func (m *StepFailure) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StepFailure.Unmarshal(m, b)
+}
+func (m *StepFailure) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StepFailure.Marshal(b, m, deterministic)
+}
+func (dst *StepFailure) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StepFailure.Merge(dst, src)
+}
+func (m *StepFailure) XXX_Size() int {
+	return xxx_messageInfo_StepFailure.Size(m)
+}
+func (m *StepFailure) XXX_DiscardUnknown() {
+	xxx_messageInfo_StepFailure.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StepFailure proto.InternalMessageInfo
+
+func (m *StepFailure) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *StepFailure) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
+func (m *StepFailure) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
+}
+
+func (m *StepFailure) GetLogFile() string {
+	if m != nil {
+		return m.LogFile
+	}
+	return ""
+}
+
+// StepTransition is one change in the status of a step, or of the step on one
+// of the hosts.
+type StepTransition struct {
+	Status               StepStatus `protobuf:"varint,1,opt,name=status,proto3,enum=idl.StepStatus" json:"status,omitempty"`
+	Timestamp            int64      `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Hostname             string     `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Error                string     `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Attempt              int32      `protobuf:"varint,5,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *StepTransition) Reset()         { *m = StepTransition{} }
+func (m *StepTransition) String() string { return proto.CompactTextString(m) }
+func (*StepTransition) ProtoMessage()    {}
+func (*StepTransition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{27}
+}
+func (m *StepTransition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StepTransition.Unmarshal(m, b)
+}
+func (m *StepTransition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StepTransition.Marshal(b, m, deterministic)
+}
+func (dst *StepTransition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StepTransition.Merge(dst, src)
+}
+func (m *StepTransition) XXX_Size() int {
+	return xxx_messageInfo_StepTransition.Size(m)
+}
+func (m *StepTransition) XXX_DiscardUnknown() {
+	xxx_messageInfo_StepTransition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StepTransition proto.InternalMessageInfo
+
+func (m *StepTransition) GetStatus() StepStatus {
+	if m != nil {
+		return m.Status
+	}
+	return StepStatus_UNKNOWN_STATUS
+}
+
+func (m *StepTransition) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *StepTransition) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *StepTransition) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *StepTransition) GetAttempt() int32 {
+	if m != nil {
+		return m.Attempt
+	}
+	return 0
+}
+
+type HostStepStatus struct {
+	Hostname             string     `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Status               StepStatus `protobuf:"varint,2,opt,name=status,proto3,enum=idl.StepStatus" json:"status,omitempty"`
+	Error                string     `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *HostStepStatus) Reset()         { *m = HostStepStatus{} }
+func (m *HostStepStatus) String() string { return proto.CompactTextString(m) }
+func (*HostStepStatus) ProtoMessage()    {}
+func (*HostStepStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{28}
+}
+func (m *HostStepStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HostStepStatus.Unmarshal(m, b)
+}
+func (m *HostStepStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HostStepStatus.Marshal(b, m, deterministic)
+}
+func (dst *HostStepStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HostStepStatus.Merge(dst, src)
+}
+func (m *HostStepStatus) XXX_Size() int {
+	return xxx_messageInfo_HostStepStatus.Size(m)
+}
+func (m *HostStepStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_HostStepStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HostStepStatus proto.InternalMessageInfo
+
+func (m *HostStepStatus) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *HostStepStatus) GetStatus() StepStatus {
+	if m != nil {
+		return m.Status
+	}
+	return StepStatus_UNKNOWN_STATUS
+}
+
+func (m *HostStepStatus) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type CheckConfigRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckConfigRequest) Reset()         { *m = CheckConfigRequest{} }
+func (m *CheckConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckConfigRequest) ProtoMessage()    {}
+func (*CheckConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{29}
+}
+func (m *CheckConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckConfigRequest.Unmarshal(m, b)
+}
+func (m *CheckConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckConfigRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckConfigRequest.Merge(dst, src)
+}
+func (m *CheckConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckConfigRequest.Size(m)
+}
+func (m *CheckConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckConfigRequest proto.InternalMessageInfo
+
+// Consider removing the status as errors are/should be put on the error field.
+type CheckConfigReply struct {
+	ConfigStatus         string   `protobuf:"bytes,1,opt,name=ConfigStatus,proto3" json:"ConfigStatus,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckConfigReply) Reset()         { *m = CheckConfigReply{} }
+func (m *CheckConfigReply) String() string { return proto.CompactTextString(m) }
+func (*CheckConfigReply) ProtoMessage()    {}
+func (*CheckConfigReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{30}
+}
+func (m *CheckConfigReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckConfigReply.Unmarshal(m, b)
+}
+func (m *CheckConfigReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckConfigReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckConfigReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckConfigReply.Merge(dst, src)
+}
+func (m *CheckConfigReply) XXX_Size() int {
+	return xxx_messageInfo_CheckConfigReply.Size(m)
+}
+func (m *CheckConfigReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckConfigReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckConfigReply proto.InternalMessageInfo
+
+func (m *CheckConfigReply) GetConfigStatus() string {
+	if m != nil {
+		return m.ConfigStatus
+	}
+	return ""
+}
+
+type CheckSeginstallRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckSeginstallRequest) Reset()         { *m = CheckSeginstallRequest{} }
+func (m *CheckSeginstallRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckSeginstallRequest) ProtoMessage()    {}
+func (*CheckSeginstallRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{31}
+}
+func (m *CheckSeginstallRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckSeginstallRequest.Unmarshal(m, b)
+}
+func (m *CheckSeginstallRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckSeginstallRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckSeginstallRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckSeginstallRequest.Merge(dst, src)
+}
+func (m *CheckSeginstallRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckSeginstallRequest.Size(m)
+}
+func (m *CheckSeginstallRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckSeginstallRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckSeginstallRequest proto.InternalMessageInfo
+
+func (m *CheckSeginstallRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
+}
+
+type CheckSeginstallReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckSeginstallReply) Reset()         { *m = CheckSeginstallReply{} }
+func (m *CheckSeginstallReply) String() string { return proto.CompactTextString(m) }
+func (*CheckSeginstallReply) ProtoMessage()    {}
+func (*CheckSeginstallReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{32}
+}
+func (m *CheckSeginstallReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckSeginstallReply.Unmarshal(m, b)
+}
+func (m *CheckSeginstallReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckSeginstallReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckSeginstallReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckSeginstallReply.Merge(dst, src)
+}
+func (m *CheckSeginstallReply) XXX_Size() int {
+	return xxx_messageInfo_CheckSeginstallReply.Size(m)
+}
+func (m *CheckSeginstallReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckSeginstallReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckSeginstallReply proto.InternalMessageInfo
+
+type PrepareStartAgentsRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrepareStartAgentsRequest) Reset()         { *m = PrepareStartAgentsRequest{} }
+func (m *PrepareStartAgentsRequest) String() string { return proto.CompactTextString(m) }
+func (*PrepareStartAgentsRequest) ProtoMessage()    {}
+func (*PrepareStartAgentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{33}
+}
+func (m *PrepareStartAgentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareStartAgentsRequest.Unmarshal(m, b)
+}
+func (m *PrepareStartAgentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareStartAgentsRequest.Marshal(b, m, deterministic)
+}
+func (dst *PrepareStartAgentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareStartAgentsRequest.Merge(dst, src)
+}
+func (m *PrepareStartAgentsRequest) XXX_Size() int {
+	return xxx_messageInfo_PrepareStartAgentsRequest.Size(m)
+}
+func (m *PrepareStartAgentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareStartAgentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrepareStartAgentsRequest proto.InternalMessageInfo
+
+func (m *PrepareStartAgentsRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
+}
+
+func (m *PrepareStartAgentsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type PrepareStartAgentsReply struct {
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *PrepareStartAgentsReply) Reset()         { *m = PrepareStartAgentsReply{} }
+func (m *PrepareStartAgentsReply) String() string { return proto.CompactTextString(m) }
+func (*PrepareStartAgentsReply) ProtoMessage()    {}
+func (*PrepareStartAgentsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{34}
+}
+func (m *PrepareStartAgentsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareStartAgentsReply.Unmarshal(m, b)
+}
+func (m *PrepareStartAgentsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareStartAgentsReply.Marshal(b, m, deterministic)
+}
+func (dst *PrepareStartAgentsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareStartAgentsReply.Merge(dst, src)
+}
+func (m *PrepareStartAgentsReply) XXX_Size() int {
+	return xxx_messageInfo_PrepareStartAgentsReply.Size(m)
+}
+func (m *PrepareStartAgentsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareStartAgentsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrepareStartAgentsReply proto.InternalMessageInfo
+
+func (m *PrepareStartAgentsReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
+type CountPerDb struct {
+	DbName               string   `protobuf:"bytes,1,opt,name=DbName,proto3" json:"DbName,omitempty"`
+	AoCount              int32    `protobuf:"varint,2,opt,name=AoCount,proto3" json:"AoCount,omitempty"`
+	HeapCount            int32    `protobuf:"varint,3,opt,name=HeapCount,proto3" json:"HeapCount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CountPerDb) Reset()         { *m = CountPerDb{} }
+func (m *CountPerDb) String() string { return proto.CompactTextString(m) }
+func (*CountPerDb) ProtoMessage()    {}
+func (*CountPerDb) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{35}
+}
+func (m *CountPerDb) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CountPerDb.Unmarshal(m, b)
+}
+func (m *CountPerDb) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CountPerDb.Marshal(b, m, deterministic)
+}
+func (dst *CountPerDb) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CountPerDb.Merge(dst, src)
+}
+func (m *CountPerDb) XXX_Size() int {
+	return xxx_messageInfo_CountPerDb.Size(m)
+}
+func (m *CountPerDb) XXX_DiscardUnknown() {
+	xxx_messageInfo_CountPerDb.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CountPerDb proto.InternalMessageInfo
+
+func (m *CountPerDb) GetDbName() string {
+	if m != nil {
+		return m.DbName
+	}
+	return ""
+}
+
+func (m *CountPerDb) GetAoCount() int32 {
+	if m != nil {
+		return m.AoCount
+	}
+	return 0
+}
+
+func (m *CountPerDb) GetHeapCount() int32 {
+	if m != nil {
+		return m.HeapCount
+	}
+	return 0
+}
+
+type CheckObjectCountRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckObjectCountRequest) Reset()         { *m = CheckObjectCountRequest{} }
+func (m *CheckObjectCountRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckObjectCountRequest) ProtoMessage()    {}
+func (*CheckObjectCountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{36}
+}
+func (m *CheckObjectCountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckObjectCountRequest.Unmarshal(m, b)
+}
+func (m *CheckObjectCountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckObjectCountRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckObjectCountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckObjectCountRequest.Merge(dst, src)
+}
+func (m *CheckObjectCountRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckObjectCountRequest.Size(m)
+}
+func (m *CheckObjectCountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckObjectCountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckObjectCountRequest proto.InternalMessageInfo
+
+type CheckObjectCountReply struct {
+	ListOfCounts         []*CountPerDb `protobuf:"bytes,1,rep,name=ListOfCounts,proto3" json:"ListOfCounts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *CheckObjectCountReply) Reset()         { *m = CheckObjectCountReply{} }
+func (m *CheckObjectCountReply) String() string { return proto.CompactTextString(m) }
+func (*CheckObjectCountReply) ProtoMessage()    {}
+func (*CheckObjectCountReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{37}
+}
+func (m *CheckObjectCountReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckObjectCountReply.Unmarshal(m, b)
+}
+func (m *CheckObjectCountReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckObjectCountReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckObjectCountReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckObjectCountReply.Merge(dst, src)
+}
+func (m *CheckObjectCountReply) XXX_Size() int {
+	return xxx_messageInfo_CheckObjectCountReply.Size(m)
+}
+func (m *CheckObjectCountReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckObjectCountReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckObjectCountReply proto.InternalMessageInfo
+
+func (m *CheckObjectCountReply) GetListOfCounts() []*CountPerDb {
+	if m != nil {
+		return m.ListOfCounts
+	}
+	return nil
+}
+
+type CheckCatalogRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckCatalogRequest) Reset()         { *m = CheckCatalogRequest{} }
+func (m *CheckCatalogRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckCatalogRequest) ProtoMessage()    {}
+func (*CheckCatalogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{38}
+}
+func (m *CheckCatalogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckCatalogRequest.Unmarshal(m, b)
+}
+func (m *CheckCatalogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckCatalogRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckCatalogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckCatalogRequest.Merge(dst, src)
+}
+func (m *CheckCatalogRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckCatalogRequest.Size(m)
+}
+func (m *CheckCatalogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckCatalogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckCatalogRequest proto.InternalMessageInfo
+
+type CheckCatalogReply struct {
+	Databases            []string        `protobuf:"bytes,1,rep,name=Databases,proto3" json:"Databases,omitempty"`
+	Issues               []*CatalogIssue `protobuf:"bytes,2,rep,name=Issues,proto3" json:"Issues,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CheckCatalogReply) Reset()         { *m = CheckCatalogReply{} }
+func (m *CheckCatalogReply) String() string { return proto.CompactTextString(m) }
+func (*CheckCatalogReply) ProtoMessage()    {}
+func (*CheckCatalogReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{39}
+}
+func (m *CheckCatalogReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckCatalogReply.Unmarshal(m, b)
+}
+func (m *CheckCatalogReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckCatalogReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckCatalogReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckCatalogReply.Merge(dst, src)
+}
+func (m *CheckCatalogReply) XXX_Size() int {
+	return xxx_messageInfo_CheckCatalogReply.Size(m)
+}
+func (m *CheckCatalogReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckCatalogReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckCatalogReply proto.InternalMessageInfo
+
+func (m *CheckCatalogReply) GetDatabases() []string {
+	if m != nil {
+		return m.Databases
+	}
+	return nil
+}
+
+func (m *CheckCatalogReply) GetIssues() []*CatalogIssue {
+	if m != nil {
+		return m.Issues
+	}
+	return nil
+}
+
+// CatalogIssue is an object in the source cluster that pg_upgrade can't
+// upgrade, found by the catalog check named by Check.
+type CatalogIssue struct {
+	Check                string   `protobuf:"bytes,1,opt,name=Check,proto3" json:"Check,omitempty"`
+	Database             string   `protobuf:"bytes,2,opt,name=Database,proto3" json:"Database,omitempty"`
+	Object               string   `protobuf:"bytes,3,opt,name=Object,proto3" json:"Object,omitempty"`
+	Detail               string   `protobuf:"bytes,4,opt,name=Detail,proto3" json:"Detail,omitempty"`
+	Remediation          string   `protobuf:"bytes,5,opt,name=Remediation,proto3" json:"Remediation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CatalogIssue) Reset()         { *m = CatalogIssue{} }
+func (m *CatalogIssue) String() string { return proto.CompactTextString(m) }
+func (*CatalogIssue) ProtoMessage()    {}
+func (*CatalogIssue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{40}
+}
+func (m *CatalogIssue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CatalogIssue.Unmarshal(m, b)
+}
+func (m *CatalogIssue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CatalogIssue.Marshal(b, m, deterministic)
+}
+func (dst *CatalogIssue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CatalogIssue.Merge(dst, src)
+}
+func (m *CatalogIssue) XXX_Size() int {
+	return xxx_messageInfo_CatalogIssue.Size(m)
+}
+func (m *CatalogIssue) XXX_DiscardUnknown() {
+	xxx_messageInfo_CatalogIssue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CatalogIssue proto.InternalMessageInfo
+
+func (m *CatalogIssue) GetCheck() string {
+	if m != nil {
+		return m.Check
+	}
+	return ""
+}
+
+func (m *CatalogIssue) GetDatabase() string {
+	if m != nil {
+		return m.Database
+	}
+	return ""
+}
+
+func (m *CatalogIssue) GetObject() string {
+	if m != nil {
+		return m.Object
+	}
+	return ""
+}
+
+func (m *CatalogIssue) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
+func (m *CatalogIssue) GetRemediation() string {
+	if m != nil {
+		return m.Remediation
+	}
+	return ""
+}
+
+type CheckPortsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckPortsRequest) Reset()         { *m = CheckPortsRequest{} }
+func (m *CheckPortsRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckPortsRequest) ProtoMessage()    {}
+func (*CheckPortsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{41}
+}
+func (m *CheckPortsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPortsRequest.Unmarshal(m, b)
+}
+func (m *CheckPortsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPortsRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckPortsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPortsRequest.Merge(dst, src)
+}
+func (m *CheckPortsRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckPortsRequest.Size(m)
+}
+func (m *CheckPortsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPortsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPortsRequest proto.InternalMessageInfo
+
+type CheckPortsReply struct {
+	Ports                []*TargetPortStatus `protobuf:"bytes,1,rep,name=Ports,proto3" json:"Ports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *CheckPortsReply) Reset()         { *m = CheckPortsReply{} }
+func (m *CheckPortsReply) String() string { return proto.CompactTextString(m) }
+func (*CheckPortsReply) ProtoMessage()    {}
+func (*CheckPortsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{42}
+}
+func (m *CheckPortsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPortsReply.Unmarshal(m, b)
+}
+func (m *CheckPortsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPortsReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckPortsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPortsReply.Merge(dst, src)
+}
+func (m *CheckPortsReply) XXX_Size() int {
+	return xxx_messageInfo_CheckPortsReply.Size(m)
+}
+func (m *CheckPortsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPortsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPortsReply proto.InternalMessageInfo
+
+func (m *CheckPortsReply) GetPorts() []*TargetPortStatus {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+type TargetPortStatus struct {
+	Hostname             string      `protobuf:"bytes,1,opt,name=Hostname,proto3" json:"Hostname,omitempty"`
+	Content              int32       `protobuf:"varint,2,opt,name=Content,proto3" json:"Content,omitempty"`
+	Status               *PortStatus `protobuf:"bytes,3,opt,name=Status,proto3" json:"Status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *TargetPortStatus) Reset()         { *m = TargetPortStatus{} }
+func (m *TargetPortStatus) String() string { return proto.CompactTextString(m) }
+func (*TargetPortStatus) ProtoMessage()    {}
+func (*TargetPortStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{43}
+}
+func (m *TargetPortStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TargetPortStatus.Unmarshal(m, b)
+}
+func (m *TargetPortStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TargetPortStatus.Marshal(b, m, deterministic)
+}
+func (dst *TargetPortStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TargetPortStatus.Merge(dst, src)
+}
+func (m *TargetPortStatus) XXX_Size() int {
+	return xxx_messageInfo_TargetPortStatus.Size(m)
+}
+func (m *TargetPortStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_TargetPortStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TargetPortStatus proto.InternalMessageInfo
+
+func (m *TargetPortStatus) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *TargetPortStatus) GetContent() int32 {
+	if m != nil {
+		return m.Content
+	}
+	return 0
+}
+
+func (m *TargetPortStatus) GetStatus() *PortStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+// PortStatus tells whether a port is already bound on a host, and if so, by
+// which process, when that can be found out.
+type PortStatus struct {
+	Port                 int32    `protobuf:"varint,1,opt,name=Port,proto3" json:"Port,omitempty"`
+	InUse                bool     `protobuf:"varint,2,opt,name=InUse,proto3" json:"InUse,omitempty"`
+	Pid                  int32    `protobuf:"varint,3,opt,name=Pid,proto3" json:"Pid,omitempty"`
+	Process              string   `protobuf:"bytes,4,opt,name=Process,proto3" json:"Process,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PortStatus) Reset()         { *m = PortStatus{} }
+func (m *PortStatus) String() string { return proto.CompactTextString(m) }
+func (*PortStatus) ProtoMessage()    {}
+func (*PortStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{44}
+}
+func (m *PortStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PortStatus.Unmarshal(m, b)
+}
+func (m *PortStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PortStatus.Marshal(b, m, deterministic)
+}
+func (dst *PortStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PortStatus.Merge(dst, src)
+}
+func (m *PortStatus) XXX_Size() int {
+	return xxx_messageInfo_PortStatus.Size(m)
+}
+func (m *PortStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_PortStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PortStatus proto.InternalMessageInfo
+
+func (m *PortStatus) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *PortStatus) GetInUse() bool {
+	if m != nil {
+		return m.InUse
+	}
+	return false
+}
+
+func (m *PortStatus) GetPid() int32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+func (m *PortStatus) GetProcess() string {
+	if m != nil {
+		return m.Process
+	}
+	return ""
+}
+
+type CheckVersionRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckVersionRequest) Reset()         { *m = CheckVersionRequest{} }
+func (m *CheckVersionRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckVersionRequest) ProtoMessage()    {}
+func (*CheckVersionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{45}
+}
+func (m *CheckVersionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckVersionRequest.Unmarshal(m, b)
+}
+func (m *CheckVersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckVersionRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckVersionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckVersionRequest.Merge(dst, src)
+}
+func (m *CheckVersionRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckVersionRequest.Size(m)
+}
+func (m *CheckVersionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckVersionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckVersionRequest proto.InternalMessageInfo
+
+type CheckVersionReply struct {
+	IsVersionCompatible  bool     `protobuf:"varint,1,opt,name=IsVersionCompatible,proto3" json:"IsVersionCompatible,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckVersionReply) Reset()         { *m = CheckVersionReply{} }
+func (m *CheckVersionReply) String() string { return proto.CompactTextString(m) }
+func (*CheckVersionReply) ProtoMessage()    {}
+func (*CheckVersionReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{46}
+}
+func (m *CheckVersionReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckVersionReply.Unmarshal(m, b)
+}
+func (m *CheckVersionReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckVersionReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckVersionReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckVersionReply.Merge(dst, src)
+}
+func (m *CheckVersionReply) XXX_Size() int {
+	return xxx_messageInfo_CheckVersionReply.Size(m)
+}
+func (m *CheckVersionReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckVersionReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckVersionReply proto.InternalMessageInfo
+
+func (m *CheckVersionReply) GetIsVersionCompatible() bool {
+	if m != nil {
+		return m.IsVersionCompatible
+	}
+	return false
+}
+
+type CheckDiskSpaceRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckDiskSpaceRequest) Reset()         { *m = CheckDiskSpaceRequest{} }
+func (m *CheckDiskSpaceRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckDiskSpaceRequest) ProtoMessage()    {}
+func (*CheckDiskSpaceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{47}
+}
+func (m *CheckDiskSpaceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckDiskSpaceRequest.Unmarshal(m, b)
+}
+func (m *CheckDiskSpaceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckDiskSpaceRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckDiskSpaceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckDiskSpaceRequest.Merge(dst, src)
+}
+func (m *CheckDiskSpaceRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckDiskSpaceRequest.Size(m)
+}
+func (m *CheckDiskSpaceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckDiskSpaceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckDiskSpaceRequest proto.InternalMessageInfo
+
+type CheckDiskSpaceReply struct {
+	HostDiskUsages       []*HostDiskUsage    `protobuf:"bytes,2,rep,name=HostDiskUsages,proto3" json:"HostDiskUsages,omitempty"`
+	Segments             []*SegmentDiskSpace `protobuf:"bytes,3,rep,name=Segments,proto3" json:"Segments,omitempty"`
+	Mode                 string              `protobuf:"bytes,4,opt,name=Mode,proto3" json:"Mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *CheckDiskSpaceReply) Reset()         { *m = CheckDiskSpaceReply{} }
+func (m *CheckDiskSpaceReply) String() string { return proto.CompactTextString(m) }
+func (*CheckDiskSpaceReply) ProtoMessage()    {}
+func (*CheckDiskSpaceReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{48}
+}
+func (m *CheckDiskSpaceReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckDiskSpaceReply.Unmarshal(m, b)
+}
+func (m *CheckDiskSpaceReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckDiskSpaceReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckDiskSpaceReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckDiskSpaceReply.Merge(dst, src)
+}
+func (m *CheckDiskSpaceReply) XXX_Size() int {
+	return xxx_messageInfo_CheckDiskSpaceReply.Size(m)
+}
+func (m *CheckDiskSpaceReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckDiskSpaceReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckDiskSpaceReply proto.InternalMessageInfo
+
+func (m *CheckDiskSpaceReply) GetHostDiskUsages() []*HostDiskUsage {
+	if m != nil {
+		return m.HostDiskUsages
+	}
+	return nil
+}
+
+func (m *CheckDiskSpaceReply) GetSegments() []*SegmentDiskSpace {
+	if m != nil {
+		return m.Segments
+	}
+	return nil
+}
+
+func (m *CheckDiskSpaceReply) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+type FileSysUsage struct {
+	Filesystem           string   `protobuf:"bytes,1,opt,name=Filesystem,proto3" json:"Filesystem,omitempty"`
+	Usage                float64  `protobuf:"fixed64,2,opt,name=Usage,proto3" json:"Usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FileSysUsage) Reset()         { *m = FileSysUsage{} }
+func (m *FileSysUsage) String() string { return proto.CompactTextString(m) }
+func (*FileSysUsage) ProtoMessage()    {}
+func (*FileSysUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{49}
+}
+func (m *FileSysUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FileSysUsage.Unmarshal(m, b)
+}
+func (m *FileSysUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FileSysUsage.Marshal(b, m, deterministic)
+}
+func (dst *FileSysUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileSysUsage.Merge(dst, src)
+}
+func (m *FileSysUsage) XXX_Size() int {
+	return xxx_messageInfo_FileSysUsage.Size(m)
+}
+func (m *FileSysUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileSysUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileSysUsage proto.InternalMessageInfo
+
+func (m *FileSysUsage) GetFilesystem() string {
+	if m != nil {
+		return m.Filesystem
+	}
+	return ""
+}
+
+func (m *FileSysUsage) GetUsage() float64 {
+	if m != nil {
+		return m.Usage
+	}
+	return 0
+}
+
+// HostDiskUsage is how full each filesystem on a host is.
+type HostDiskUsage struct {
+	Hostname             string          `protobuf:"bytes,1,opt,name=Hostname,proto3" json:"Hostname,omitempty"`
+	ListOfFileSysUsage   []*FileSysUsage `protobuf:"bytes,2,rep,name=ListOfFileSysUsage,proto3" json:"ListOfFileSysUsage,omitempty"`
+	Error                string          `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *HostDiskUsage) Reset()         { *m = HostDiskUsage{} }
+func (m *HostDiskUsage) String() string { return proto.CompactTextString(m) }
+func (*HostDiskUsage) ProtoMessage()    {}
+func (*HostDiskUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{50}
+}
+func (m *HostDiskUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HostDiskUsage.Unmarshal(m, b)
+}
+func (m *HostDiskUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HostDiskUsage.Marshal(b, m, deterministic)
+}
+func (dst *HostDiskUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HostDiskUsage.Merge(dst, src)
+}
+func (m *HostDiskUsage) XXX_Size() int {
+	return xxx_messageInfo_HostDiskUsage.Size(m)
+}
+func (m *HostDiskUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_HostDiskUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HostDiskUsage proto.InternalMessageInfo
+
+func (m *HostDiskUsage) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *HostDiskUsage) GetListOfFileSysUsage() []*FileSysUsage {
+	if m != nil {
+		return m.ListOfFileSysUsage
+	}
+	return nil
+}
+
+func (m *HostDiskUsage) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// SegmentDiskSpace compares the space a segment's upgrade is projected to
+// need, in each upgrade mode, with the space left on the filesystem that will
+// hold its target data directory. Segments sharing a filesystem share its free
+// space, so a segment fits only if the total for that filesystem does.
+type SegmentDiskSpace struct {
+	Hostname             string         `protobuf:"bytes,1,opt,name=Hostname,proto3" json:"Hostname,omitempty"`
+	Content              int32          `protobuf:"varint,2,opt,name=Content,proto3" json:"Content,omitempty"`
+	Role                 string         `protobuf:"bytes,3,opt,name=Role,proto3" json:"Role,omitempty"`
+	DataDir              string         `protobuf:"bytes,4,opt,name=DataDir,proto3" json:"DataDir,omitempty"`
+	TargetDataDir        string         `protobuf:"bytes,5,opt,name=TargetDataDir,proto3" json:"TargetDataDir,omitempty"`
+	DataDirBytes         uint64         `protobuf:"varint,6,opt,name=DataDirBytes,proto3" json:"DataDirBytes,omitempty"`
+	Filesystem           string         `protobuf:"bytes,7,opt,name=Filesystem,proto3" json:"Filesystem,omitempty"`
+	FreeBytes            uint64         `protobuf:"varint,8,opt,name=FreeBytes,proto3" json:"FreeBytes,omitempty"`
+	Copy                 *DiskSpaceNeed `protobuf:"bytes,9,opt,name=Copy,proto3" json:"Copy,omitempty"`
+	Link                 *DiskSpaceNeed `protobuf:"bytes,10,opt,name=Link,proto3" json:"Link,omitempty"`
+	Error                string         `protobuf:"bytes,11,opt,name=Error,proto3" json:"Error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SegmentDiskSpace) Reset()         { *m = SegmentDiskSpace{} }
+func (m *SegmentDiskSpace) String() string { return proto.CompactTextString(m) }
+func (*SegmentDiskSpace) ProtoMessage()    {}
+func (*SegmentDiskSpace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{51}
+}
+func (m *SegmentDiskSpace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentDiskSpace.Unmarshal(m, b)
+}
+func (m *SegmentDiskSpace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentDiskSpace.Marshal(b, m, deterministic)
+}
+func (dst *SegmentDiskSpace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentDiskSpace.Merge(dst, src)
+}
+func (m *SegmentDiskSpace) XXX_Size() int {
+	return xxx_messageInfo_SegmentDiskSpace.Size(m)
+}
+func (m *SegmentDiskSpace) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentDiskSpace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentDiskSpace proto.InternalMessageInfo
+
+func (m *SegmentDiskSpace) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *SegmentDiskSpace) GetContent() int32 {
+	if m != nil {
+		return m.Content
+	}
+	return 0
+}
+
+func (m *SegmentDiskSpace) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *SegmentDiskSpace) GetDataDir() string {
+	if m != nil {
+		return m.DataDir
+	}
+	return ""
+}
+
+func (m *SegmentDiskSpace) GetTargetDataDir() string {
+	if m != nil {
+		return m.TargetDataDir
+	}
+	return ""
+}
+
+func (m *SegmentDiskSpace) GetDataDirBytes() uint64 {
+	if m != nil {
+		return m.DataDirBytes
+	}
+	return 0
+}
+
+func (m *SegmentDiskSpace) GetFilesystem() string {
+	if m != nil {
+		return m.Filesystem
+	}
+	return ""
+}
+
+func (m *SegmentDiskSpace) GetFreeBytes() uint64 {
+	if m != nil {
+		return m.FreeBytes
+	}
+	return 0
+}
+
+func (m *SegmentDiskSpace) GetCopy() *DiskSpaceNeed {
+	if m != nil {
+		return m.Copy
+	}
+	return nil
+}
+
+func (m *SegmentDiskSpace) GetLink() *DiskSpaceNeed {
+	if m != nil {
+		return m.Link
+	}
+	return nil
+}
+
+func (m *SegmentDiskSpace) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type DiskSpaceNeed struct {
+	Bytes                uint64   `protobuf:"varint,1,opt,name=Bytes,proto3" json:"Bytes,omitempty"`
+	FilesystemBytes      uint64   `protobuf:"varint,2,opt,name=FilesystemBytes,proto3" json:"FilesystemBytes,omitempty"`
+	Fits                 bool     `protobuf:"varint,3,opt,name=Fits,proto3" json:"Fits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiskSpaceNeed) Reset()         { *m = DiskSpaceNeed{} }
+func (m *DiskSpaceNeed) String() string { return proto.CompactTextString(m) }
+func (*DiskSpaceNeed) ProtoMessage()    {}
+func (*DiskSpaceNeed) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{52}
+}
+func (m *DiskSpaceNeed) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiskSpaceNeed.Unmarshal(m, b)
+}
+func (m *DiskSpaceNeed) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiskSpaceNeed.Marshal(b, m, deterministic)
+}
+func (dst *DiskSpaceNeed) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiskSpaceNeed.Merge(dst, src)
+}
+func (m *DiskSpaceNeed) XXX_Size() int {
+	return xxx_messageInfo_DiskSpaceNeed.Size(m)
+}
+func (m *DiskSpaceNeed) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiskSpaceNeed.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiskSpaceNeed proto.InternalMessageInfo
+
+func (m *DiskSpaceNeed) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *DiskSpaceNeed) GetFilesystemBytes() uint64 {
 	if m != nil {
-		return m.Step
+		return m.FilesystemBytes
 	}
-	return UpgradeSteps_UNKNOWN_STEP
+	return 0
 }
 
-func (m *UpgradeStepStatus) GetStatus() StepStatus {
+func (m *DiskSpaceNeed) GetFits() bool {
 	if m != nil {
-		return m.Status
+		return m.Fits
 	}
-	return StepStatus_UNKNOWN_STATUS
+	return false
 }
 
-type CheckConfigRequest struct {
+type PrepareShutdownClustersRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CheckConfigRequest) Reset()         { *m = CheckConfigRequest{} }
-func (m *CheckConfigRequest) String() string { return proto.CompactTextString(m) }
-func (*CheckConfigRequest) ProtoMessage()    {}
-func (*CheckConfigRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{15}
+func (m *PrepareShutdownClustersRequest) Reset()         { *m = PrepareShutdownClustersRequest{} }
+func (m *PrepareShutdownClustersRequest) String() string { return proto.CompactTextString(m) }
+func (*PrepareShutdownClustersRequest) ProtoMessage()    {}
+func (*PrepareShutdownClustersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{53}
 }
-func (m *CheckConfigRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckConfigRequest.Unmarshal(m, b)
+func (m *PrepareShutdownClustersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareShutdownClustersRequest.Unmarshal(m, b)
 }
-func (m *CheckConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckConfigRequest.Marshal(b, m, deterministic)
+func (m *PrepareShutdownClustersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareShutdownClustersRequest.Marshal(b, m, deterministic)
 }
-func (dst *CheckConfigRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckConfigRequest.Merge(dst, src)
+func (dst *PrepareShutdownClustersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareShutdownClustersRequest.Merge(dst, src)
 }
-func (m *CheckConfigRequest) XXX_Size() int {
-	return xxx_messageInfo_CheckConfigRequest.Size(m)
+func (m *PrepareShutdownClustersRequest) XXX_Size() int {
+	return xxx_messageInfo_PrepareShutdownClustersRequest.Size(m)
 }
-func (m *CheckConfigRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckConfigRequest.DiscardUnknown(m)
+func (m *PrepareShutdownClustersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareShutdownClustersRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckConfigRequest proto.InternalMessageInfo
+var xxx_messageInfo_PrepareShutdownClustersRequest proto.InternalMessageInfo
 
-// Consider removing the status as errors are/should be put on the error field.
-type CheckConfigReply struct {
-	ConfigStatus         string   `protobuf:"bytes,1,opt,name=ConfigStatus,proto3" json:"ConfigStatus,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *PrepareShutdownClustersRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
 }
 
-func (m *CheckConfigReply) Reset()         { *m = CheckConfigReply{} }
-func (m *CheckConfigReply) String() string { return proto.CompactTextString(m) }
-func (*CheckConfigReply) ProtoMessage()    {}
-func (*CheckConfigReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{16}
+func (m *PrepareShutdownClustersRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
 }
-func (m *CheckConfigReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckConfigReply.Unmarshal(m, b)
+
+type PrepareShutdownClustersReply struct {
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
-func (m *CheckConfigReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckConfigReply.Marshal(b, m, deterministic)
+
+func (m *PrepareShutdownClustersReply) Reset()         { *m = PrepareShutdownClustersReply{} }
+func (m *PrepareShutdownClustersReply) String() string { return proto.CompactTextString(m) }
+func (*PrepareShutdownClustersReply) ProtoMessage()    {}
+func (*PrepareShutdownClustersReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{54}
 }
-func (dst *CheckConfigReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckConfigReply.Merge(dst, src)
+func (m *PrepareShutdownClustersReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareShutdownClustersReply.Unmarshal(m, b)
 }
-func (m *CheckConfigReply) XXX_Size() int {
-	return xxx_messageInfo_CheckConfigReply.Size(m)
+func (m *PrepareShutdownClustersReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareShutdownClustersReply.Marshal(b, m, deterministic)
 }
-func (m *CheckConfigReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckConfigReply.DiscardUnknown(m)
+func (dst *PrepareShutdownClustersReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareShutdownClustersReply.Merge(dst, src)
+}
+func (m *PrepareShutdownClustersReply) XXX_Size() int {
+	return xxx_messageInfo_PrepareShutdownClustersReply.Size(m)
+}
+func (m *PrepareShutdownClustersReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareShutdownClustersReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckConfigReply proto.InternalMessageInfo
+var xxx_messageInfo_PrepareShutdownClustersReply proto.InternalMessageInfo
 
-func (m *CheckConfigReply) GetConfigStatus() string {
+func (m *PrepareShutdownClustersReply) GetPlan() *DryRunPlan {
 	if m != nil {
-		return m.ConfigStatus
+		return m.Plan
 	}
-	return ""
+	return nil
 }
 
-type CheckSeginstallRequest struct {
+type PrepareInitClusterRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CheckSeginstallRequest) Reset()         { *m = CheckSeginstallRequest{} }
-func (m *CheckSeginstallRequest) String() string { return proto.CompactTextString(m) }
-func (*CheckSeginstallRequest) ProtoMessage()    {}
-func (*CheckSeginstallRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{17}
+func (m *PrepareInitClusterRequest) Reset()         { *m = PrepareInitClusterRequest{} }
+func (m *PrepareInitClusterRequest) String() string { return proto.CompactTextString(m) }
+func (*PrepareInitClusterRequest) ProtoMessage()    {}
+func (*PrepareInitClusterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{55}
 }
-func (m *CheckSeginstallRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckSeginstallRequest.Unmarshal(m, b)
+func (m *PrepareInitClusterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareInitClusterRequest.Unmarshal(m, b)
 }
-func (m *CheckSeginstallRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckSeginstallRequest.Marshal(b, m, deterministic)
+func (m *PrepareInitClusterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareInitClusterRequest.Marshal(b, m, deterministic)
 }
-func (dst *CheckSeginstallRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckSeginstallRequest.Merge(dst, src)
+func (dst *PrepareInitClusterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareInitClusterRequest.Merge(dst, src)
 }
-func (m *CheckSeginstallRequest) XXX_Size() int {
-	return xxx_messageInfo_CheckSeginstallRequest.Size(m)
+func (m *PrepareInitClusterRequest) XXX_Size() int {
+	return xxx_messageInfo_PrepareInitClusterRequest.Size(m)
 }
-func (m *CheckSeginstallRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckSeginstallRequest.DiscardUnknown(m)
+func (m *PrepareInitClusterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareInitClusterRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckSeginstallRequest proto.InternalMessageInfo
+var xxx_messageInfo_PrepareInitClusterRequest proto.InternalMessageInfo
 
-type CheckSeginstallReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *PrepareInitClusterRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
 }
 
-func (m *CheckSeginstallReply) Reset()         { *m = CheckSeginstallReply{} }
-func (m *CheckSeginstallReply) String() string { return proto.CompactTextString(m) }
-func (*CheckSeginstallReply) ProtoMessage()    {}
-func (*CheckSeginstallReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{18}
+func (m *PrepareInitClusterRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
 }
-func (m *CheckSeginstallReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckSeginstallReply.Unmarshal(m, b)
+
+type PrepareInitClusterReply struct {
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
-func (m *CheckSeginstallReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckSeginstallReply.Marshal(b, m, deterministic)
+
+func (m *PrepareInitClusterReply) Reset()         { *m = PrepareInitClusterReply{} }
+func (m *PrepareInitClusterReply) String() string { return proto.CompactTextString(m) }
+func (*PrepareInitClusterReply) ProtoMessage()    {}
+func (*PrepareInitClusterReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{56}
 }
-func (dst *CheckSeginstallReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckSeginstallReply.Merge(dst, src)
+func (m *PrepareInitClusterReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareInitClusterReply.Unmarshal(m, b)
 }
-func (m *CheckSeginstallReply) XXX_Size() int {
-	return xxx_messageInfo_CheckSeginstallReply.Size(m)
+func (m *PrepareInitClusterReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareInitClusterReply.Marshal(b, m, deterministic)
 }
-func (m *CheckSeginstallReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckSeginstallReply.DiscardUnknown(m)
+func (dst *PrepareInitClusterReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareInitClusterReply.Merge(dst, src)
+}
+func (m *PrepareInitClusterReply) XXX_Size() int {
+	return xxx_messageInfo_PrepareInitClusterReply.Size(m)
+}
+func (m *PrepareInitClusterReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareInitClusterReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckSeginstallReply proto.InternalMessageInfo
+var xxx_messageInfo_PrepareInitClusterReply proto.InternalMessageInfo
 
-type PrepareStartAgentsRequest struct {
+func (m *PrepareInitClusterReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
+type UpgradeConvertMasterRequest struct {
+	SkipPrerequisites    bool     `protobuf:"varint,1,opt,name=skipPrerequisites,proto3" json:"skipPrerequisites,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PrepareStartAgentsRequest) Reset()         { *m = PrepareStartAgentsRequest{} }
-func (m *PrepareStartAgentsRequest) String() string { return proto.CompactTextString(m) }
-func (*PrepareStartAgentsRequest) ProtoMessage()    {}
-func (*PrepareStartAgentsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{19}
+func (m *UpgradeConvertMasterRequest) Reset()         { *m = UpgradeConvertMasterRequest{} }
+func (m *UpgradeConvertMasterRequest) String() string { return proto.CompactTextString(m) }
+func (*UpgradeConvertMasterRequest) ProtoMessage()    {}
+func (*UpgradeConvertMasterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{57}
 }
-func (m *PrepareStartAgentsRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PrepareStartAgentsRequest.Unmarshal(m, b)
+func (m *UpgradeConvertMasterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradeConvertMasterRequest.Unmarshal(m, b)
 }
-func (m *PrepareStartAgentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PrepareStartAgentsRequest.Marshal(b, m, deterministic)
+func (m *UpgradeConvertMasterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradeConvertMasterRequest.Marshal(b, m, deterministic)
 }
-func (dst *PrepareStartAgentsRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PrepareStartAgentsRequest.Merge(dst, src)
+func (dst *UpgradeConvertMasterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradeConvertMasterRequest.Merge(dst, src)
 }
-func (m *PrepareStartAgentsRequest) XXX_Size() int {
-	return xxx_messageInfo_PrepareStartAgentsRequest.Size(m)
+func (m *UpgradeConvertMasterRequest) XXX_Size() int {
+	return xxx_messageInfo_UpgradeConvertMasterRequest.Size(m)
 }
-func (m *PrepareStartAgentsRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_PrepareStartAgentsRequest.DiscardUnknown(m)
+func (m *UpgradeConvertMasterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradeConvertMasterRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PrepareStartAgentsRequest proto.InternalMessageInfo
+var xxx_messageInfo_UpgradeConvertMasterRequest proto.InternalMessageInfo
 
-type PrepareStartAgentsReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *UpgradeConvertMasterRequest) GetSkipPrerequisites() bool {
+	if m != nil {
+		return m.SkipPrerequisites
+	}
+	return false
 }
 
-func (m *PrepareStartAgentsReply) Reset()         { *m = PrepareStartAgentsReply{} }
-func (m *PrepareStartAgentsReply) String() string { return proto.CompactTextString(m) }
-func (*PrepareStartAgentsReply) ProtoMessage()    {}
-func (*PrepareStartAgentsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{20}
+func (m *UpgradeConvertMasterRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
 }
-func (m *PrepareStartAgentsReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PrepareStartAgentsReply.Unmarshal(m, b)
+
+type UpgradeConvertMasterReply struct {
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
-func (m *PrepareStartAgentsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PrepareStartAgentsReply.Marshal(b, m, deterministic)
+
+func (m *UpgradeConvertMasterReply) Reset()         { *m = UpgradeConvertMasterReply{} }
+func (m *UpgradeConvertMasterReply) String() string { return proto.CompactTextString(m) }
+func (*UpgradeConvertMasterReply) ProtoMessage()    {}
+func (*UpgradeConvertMasterReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{58}
 }
-func (dst *PrepareStartAgentsReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PrepareStartAgentsReply.Merge(dst, src)
+func (m *UpgradeConvertMasterReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpgradeConvertMasterReply.Unmarshal(m, b)
 }
-func (m *PrepareStartAgentsReply) XXX_Size() int {
-	return xxx_messageInfo_PrepareStartAgentsReply.Size(m)
+func (m *UpgradeConvertMasterReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpgradeConvertMasterReply.Marshal(b, m, deterministic)
 }
-func (m *PrepareStartAgentsReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_PrepareStartAgentsReply.DiscardUnknown(m)
+func (dst *UpgradeConvertMasterReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpgradeConvertMasterReply.Merge(dst, src)
+}
+func (m *UpgradeConvertMasterReply) XXX_Size() int {
+	return xxx_messageInfo_UpgradeConvertMasterReply.Size(m)
+}
+func (m *UpgradeConvertMasterReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpgradeConvertMasterReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PrepareStartAgentsReply proto.InternalMessageInfo
+var xxx_messageInfo_UpgradeConvertMasterReply proto.InternalMessageInfo
 
-type CountPerDb struct {
-	DbName               string   `protobuf:"bytes,1,opt,name=DbName,proto3" json:"DbName,omitempty"`
-	AoCount              int32    `protobuf:"varint,2,opt,name=AoCount,proto3" json:"AoCount,omitempty"`
-	HeapCount            int32    `protobuf:"varint,3,opt,name=HeapCount,proto3" json:"HeapCount,omitempty"`
+func (m *UpgradeConvertMasterReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
+type SetConfigRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CountPerDb) Reset()         { *m = CountPerDb{} }
-func (m *CountPerDb) String() string { return proto.CompactTextString(m) }
-func (*CountPerDb) ProtoMessage()    {}
-func (*CountPerDb) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{21}
+func (m *SetConfigRequest) Reset()         { *m = SetConfigRequest{} }
+func (m *SetConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*SetConfigRequest) ProtoMessage()    {}
+func (*SetConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{59}
 }
-func (m *CountPerDb) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CountPerDb.Unmarshal(m, b)
+func (m *SetConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetConfigRequest.Unmarshal(m, b)
 }
-func (m *CountPerDb) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CountPerDb.Marshal(b, m, deterministic)
+func (m *SetConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetConfigRequest.Marshal(b, m, deterministic)
 }
-func (dst *CountPerDb) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CountPerDb.Merge(dst, src)
+func (dst *SetConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetConfigRequest.Merge(dst, src)
 }
-func (m *CountPerDb) XXX_Size() int {
-	return xxx_messageInfo_CountPerDb.Size(m)
+func (m *SetConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_SetConfigRequest.Size(m)
 }
-func (m *CountPerDb) XXX_DiscardUnknown() {
-	xxx_messageInfo_CountPerDb.DiscardUnknown(m)
+func (m *SetConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetConfigRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CountPerDb proto.InternalMessageInfo
+var xxx_messageInfo_SetConfigRequest proto.InternalMessageInfo
 
-func (m *CountPerDb) GetDbName() string {
+func (m *SetConfigRequest) GetName() string {
 	if m != nil {
-		return m.DbName
+		return m.Name
 	}
 	return ""
 }
 
-func (m *CountPerDb) GetAoCount() int32 {
-	if m != nil {
-		return m.AoCount
-	}
-	return 0
-}
-
-func (m *CountPerDb) GetHeapCount() int32 {
+func (m *SetConfigRequest) GetValue() string {
 	if m != nil {
-		return m.HeapCount
+		return m.Value
 	}
-	return 0
+	return ""
 }
 
-type CheckObjectCountRequest struct {
+type SetConfigReply struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CheckObjectCountRequest) Reset()         { *m = CheckObjectCountRequest{} }
-func (m *CheckObjectCountRequest) String() string { return proto.CompactTextString(m) }
-func (*CheckObjectCountRequest) ProtoMessage()    {}
-func (*CheckObjectCountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{22}
+func (m *SetConfigReply) Reset()         { *m = SetConfigReply{} }
+func (m *SetConfigReply) String() string { return proto.CompactTextString(m) }
+func (*SetConfigReply) ProtoMessage()    {}
+func (*SetConfigReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{60}
 }
-func (m *CheckObjectCountRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckObjectCountRequest.Unmarshal(m, b)
+func (m *SetConfigReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetConfigReply.Unmarshal(m, b)
 }
-func (m *CheckObjectCountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckObjectCountRequest.Marshal(b, m, deterministic)
+func (m *SetConfigReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetConfigReply.Marshal(b, m, deterministic)
 }
-func (dst *CheckObjectCountRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckObjectCountRequest.Merge(dst, src)
+func (dst *SetConfigReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetConfigReply.Merge(dst, src)
 }
-func (m *CheckObjectCountRequest) XXX_Size() int {
-	return xxx_messageInfo_CheckObjectCountRequest.Size(m)
+func (m *SetConfigReply) XXX_Size() int {
+	return xxx_messageInfo_SetConfigReply.Size(m)
 }
-func (m *CheckObjectCountRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckObjectCountRequest.DiscardUnknown(m)
+func (m *SetConfigReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetConfigReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckObjectCountRequest proto.InternalMessageInfo
+var xxx_messageInfo_SetConfigReply proto.InternalMessageInfo
 
-type CheckObjectCountReply struct {
-	ListOfCounts         []*CountPerDb `protobuf:"bytes,1,rep,name=ListOfCounts,proto3" json:"ListOfCounts,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+type GetConfigRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CheckObjectCountReply) Reset()         { *m = CheckObjectCountReply{} }
-func (m *CheckObjectCountReply) String() string { return proto.CompactTextString(m) }
-func (*CheckObjectCountReply) ProtoMessage()    {}
-func (*CheckObjectCountReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{23}
+func (m *GetConfigRequest) Reset()         { *m = GetConfigRequest{} }
+func (m *GetConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*GetConfigRequest) ProtoMessage()    {}
+func (*GetConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{61}
 }
-func (m *CheckObjectCountReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckObjectCountReply.Unmarshal(m, b)
+func (m *GetConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetConfigRequest.Unmarshal(m, b)
 }
-func (m *CheckObjectCountReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckObjectCountReply.Marshal(b, m, deterministic)
+func (m *GetConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetConfigRequest.Marshal(b, m, deterministic)
 }
-func (dst *CheckObjectCountReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckObjectCountReply.Merge(dst, src)
+func (dst *GetConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetConfigRequest.Merge(dst, src)
 }
-func (m *CheckObjectCountReply) XXX_Size() int {
-	return xxx_messageInfo_CheckObjectCountReply.Size(m)
+func (m *GetConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_GetConfigRequest.Size(m)
 }
-func (m *CheckObjectCountReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckObjectCountReply.DiscardUnknown(m)
+func (m *GetConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetConfigRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckObjectCountReply proto.InternalMessageInfo
+var xxx_messageInfo_GetConfigRequest proto.InternalMessageInfo
 
-func (m *CheckObjectCountReply) GetListOfCounts() []*CountPerDb {
+func (m *GetConfigRequest) GetName() string {
 	if m != nil {
-		return m.ListOfCounts
+		return m.Name
 	}
-	return nil
+	return ""
 }
 
-type CheckVersionRequest struct {
+type GetConfigReply struct {
+	Value                string   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CheckVersionRequest) Reset()         { *m = CheckVersionRequest{} }
-func (m *CheckVersionRequest) String() string { return proto.CompactTextString(m) }
-func (*CheckVersionRequest) ProtoMessage()    {}
-func (*CheckVersionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{24}
+func (m *GetConfigReply) Reset()         { *m = GetConfigReply{} }
+func (m *GetConfigReply) String() string { return proto.CompactTextString(m) }
+func (*GetConfigReply) ProtoMessage()    {}
+func (*GetConfigReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{62}
 }
-func (m *CheckVersionRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckVersionRequest.Unmarshal(m, b)
+func (m *GetConfigReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetConfigReply.Unmarshal(m, b)
 }
-func (m *CheckVersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckVersionRequest.Marshal(b, m, deterministic)
+func (m *GetConfigReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetConfigReply.Marshal(b, m, deterministic)
 }
-func (dst *CheckVersionRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckVersionRequest.Merge(dst, src)
+func (dst *GetConfigReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetConfigReply.Merge(dst, src)
 }
-func (m *CheckVersionRequest) XXX_Size() int {
-	return xxx_messageInfo_CheckVersionRequest.Size(m)
+func (m *GetConfigReply) XXX_Size() int {
+	return xxx_messageInfo_GetConfigReply.Size(m)
 }
-func (m *CheckVersionRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckVersionRequest.DiscardUnknown(m)
+func (m *GetConfigReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetConfigReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckVersionRequest proto.InternalMessageInfo
+var xxx_messageInfo_GetConfigReply proto.InternalMessageInfo
 
-type CheckVersionReply struct {
-	IsVersionCompatible  bool     `protobuf:"varint,1,opt,name=IsVersionCompatible,proto3" json:"IsVersionCompatible,omitempty"`
+func (m *GetConfigReply) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type RunRequest struct {
+	DryRun               bool     `protobuf:"varint,1,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CheckVersionReply) Reset()         { *m = CheckVersionReply{} }
-func (m *CheckVersionReply) String() string { return proto.CompactTextString(m) }
-func (*CheckVersionReply) ProtoMessage()    {}
-func (*CheckVersionReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{25}
+func (m *RunRequest) Reset()         { *m = RunRequest{} }
+func (m *RunRequest) String() string { return proto.CompactTextString(m) }
+func (*RunRequest) ProtoMessage()    {}
+func (*RunRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{63}
 }
-func (m *CheckVersionReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckVersionReply.Unmarshal(m, b)
+func (m *RunRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunRequest.Unmarshal(m, b)
 }
-func (m *CheckVersionReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckVersionReply.Marshal(b, m, deterministic)
+func (m *RunRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunRequest.Marshal(b, m, deterministic)
 }
-func (dst *CheckVersionReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckVersionReply.Merge(dst, src)
+func (dst *RunRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunRequest.Merge(dst, src)
 }
-func (m *CheckVersionReply) XXX_Size() int {
-	return xxx_messageInfo_CheckVersionReply.Size(m)
+func (m *RunRequest) XXX_Size() int {
+	return xxx_messageInfo_RunRequest.Size(m)
 }
-func (m *CheckVersionReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckVersionReply.DiscardUnknown(m)
+func (m *RunRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckVersionReply proto.InternalMessageInfo
+var xxx_messageInfo_RunRequest proto.InternalMessageInfo
 
-func (m *CheckVersionReply) GetIsVersionCompatible() bool {
+func (m *RunRequest) GetDryRun() bool {
 	if m != nil {
-		return m.IsVersionCompatible
+		return m.DryRun
 	}
 	return false
 }
 
-type CheckDiskSpaceRequest struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *CheckDiskSpaceRequest) Reset()         { *m = CheckDiskSpaceRequest{} }
-func (m *CheckDiskSpaceRequest) String() string { return proto.CompactTextString(m) }
-func (*CheckDiskSpaceRequest) ProtoMessage()    {}
-func (*CheckDiskSpaceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{26}
-}
-func (m *CheckDiskSpaceRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckDiskSpaceRequest.Unmarshal(m, b)
-}
-func (m *CheckDiskSpaceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckDiskSpaceRequest.Marshal(b, m, deterministic)
-}
-func (dst *CheckDiskSpaceRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckDiskSpaceRequest.Merge(dst, src)
-}
-func (m *CheckDiskSpaceRequest) XXX_Size() int {
-	return xxx_messageInfo_CheckDiskSpaceRequest.Size(m)
-}
-func (m *CheckDiskSpaceRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckDiskSpaceRequest.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_CheckDiskSpaceRequest proto.InternalMessageInfo
-
-type CheckDiskSpaceReply struct {
-	SegmentFileSysUsage  []string `protobuf:"bytes,1,rep,name=SegmentFileSysUsage,proto3" json:"SegmentFileSysUsage,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type RunReply struct {
+	FailedStep           UpgradeSteps  `protobuf:"varint,1,opt,name=failedStep,proto3,enum=idl.UpgradeSteps" json:"failedStep,omitempty"`
+	Plans                []*DryRunPlan `protobuf:"bytes,2,rep,name=plans,proto3" json:"plans,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
-func (m *CheckDiskSpaceReply) Reset()         { *m = CheckDiskSpaceReply{} }
-func (m *CheckDiskSpaceReply) String() string { return proto.CompactTextString(m) }
-func (*CheckDiskSpaceReply) ProtoMessage()    {}
-func (*CheckDiskSpaceReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{27}
+func (m *RunReply) Reset()         { *m = RunReply{} }
+func (m *RunReply) String() string { return proto.CompactTextString(m) }
+func (*RunReply) ProtoMessage()    {}
+func (*RunReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{64}
 }
-func (m *CheckDiskSpaceReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CheckDiskSpaceReply.Unmarshal(m, b)
+func (m *RunReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunReply.Unmarshal(m, b)
 }
-func (m *CheckDiskSpaceReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CheckDiskSpaceReply.Marshal(b, m, deterministic)
+func (m *RunReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunReply.Marshal(b, m, deterministic)
 }
-func (dst *CheckDiskSpaceReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CheckDiskSpaceReply.Merge(dst, src)
+func (dst *RunReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunReply.Merge(dst, src)
 }
-func (m *CheckDiskSpaceReply) XXX_Size() int {
-	return xxx_messageInfo_CheckDiskSpaceReply.Size(m)
+func (m *RunReply) XXX_Size() int {
+	return xxx_messageInfo_RunReply.Size(m)
 }
-func (m *CheckDiskSpaceReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_CheckDiskSpaceReply.DiscardUnknown(m)
+func (m *RunReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CheckDiskSpaceReply proto.InternalMessageInfo
+var xxx_messageInfo_RunReply proto.InternalMessageInfo
+
+func (m *RunReply) GetFailedStep() UpgradeSteps {
+	if m != nil {
+		return m.FailedStep
+	}
+	return UpgradeSteps_UNKNOWN_STEP
+}
 
-func (m *CheckDiskSpaceReply) GetSegmentFileSysUsage() []string {
+func (m *RunReply) GetPlans() []*DryRunPlan {
 	if m != nil {
-		return m.SegmentFileSysUsage
+		return m.Plans
 	}
 	return nil
 }
 
-type PrepareShutdownClustersRequest struct {
+type ResumeRequest struct {
+	DryRun               bool     `protobuf:"varint,1,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PrepareShutdownClustersRequest) Reset()         { *m = PrepareShutdownClustersRequest{} }
-func (m *PrepareShutdownClustersRequest) String() string { return proto.CompactTextString(m) }
-func (*PrepareShutdownClustersRequest) ProtoMessage()    {}
-func (*PrepareShutdownClustersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{28}
+func (m *ResumeRequest) Reset()         { *m = ResumeRequest{} }
+func (m *ResumeRequest) String() string { return proto.CompactTextString(m) }
+func (*ResumeRequest) ProtoMessage()    {}
+func (*ResumeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{65}
 }
-func (m *PrepareShutdownClustersRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PrepareShutdownClustersRequest.Unmarshal(m, b)
+func (m *ResumeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeRequest.Unmarshal(m, b)
 }
-func (m *PrepareShutdownClustersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PrepareShutdownClustersRequest.Marshal(b, m, deterministic)
+func (m *ResumeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeRequest.Marshal(b, m, deterministic)
 }
-func (dst *PrepareShutdownClustersRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PrepareShutdownClustersRequest.Merge(dst, src)
+func (dst *ResumeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeRequest.Merge(dst, src)
 }
-func (m *PrepareShutdownClustersRequest) XXX_Size() int {
-	return xxx_messageInfo_PrepareShutdownClustersRequest.Size(m)
+func (m *ResumeRequest) XXX_Size() int {
+	return xxx_messageInfo_ResumeRequest.Size(m)
 }
-func (m *PrepareShutdownClustersRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_PrepareShutdownClustersRequest.DiscardUnknown(m)
+func (m *ResumeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PrepareShutdownClustersRequest proto.InternalMessageInfo
+var xxx_messageInfo_ResumeRequest proto.InternalMessageInfo
 
-type PrepareShutdownClustersReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *ResumeRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
 }
 
-func (m *PrepareShutdownClustersReply) Reset()         { *m = PrepareShutdownClustersReply{} }
-func (m *PrepareShutdownClustersReply) String() string { return proto.CompactTextString(m) }
-func (*PrepareShutdownClustersReply) ProtoMessage()    {}
-func (*PrepareShutdownClustersReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{29}
+type ResumeReply struct {
+	ResumedStep          UpgradeSteps  `protobuf:"varint,1,opt,name=resumedStep,proto3,enum=idl.UpgradeSteps" json:"resumedStep,omitempty"`
+	FailedStep           UpgradeSteps  `protobuf:"varint,2,opt,name=failedStep,proto3,enum=idl.UpgradeSteps" json:"failedStep,omitempty"`
+	Plans                []*DryRunPlan `protobuf:"bytes,3,rep,name=plans,proto3" json:"plans,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
-func (m *PrepareShutdownClustersReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PrepareShutdownClustersReply.Unmarshal(m, b)
+
+func (m *ResumeReply) Reset()         { *m = ResumeReply{} }
+func (m *ResumeReply) String() string { return proto.CompactTextString(m) }
+func (*ResumeReply) ProtoMessage()    {}
+func (*ResumeReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{66}
 }
-func (m *PrepareShutdownClustersReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PrepareShutdownClustersReply.Marshal(b, m, deterministic)
+func (m *ResumeReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeReply.Unmarshal(m, b)
 }
-func (dst *PrepareShutdownClustersReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PrepareShutdownClustersReply.Merge(dst, src)
+func (m *ResumeReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeReply.Marshal(b, m, deterministic)
 }
-func (m *PrepareShutdownClustersReply) XXX_Size() int {
-	return xxx_messageInfo_PrepareShutdownClustersReply.Size(m)
+func (dst *ResumeReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeReply.Merge(dst, src)
 }
-func (m *PrepareShutdownClustersReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_PrepareShutdownClustersReply.DiscardUnknown(m)
+func (m *ResumeReply) XXX_Size() int {
+	return xxx_messageInfo_ResumeReply.Size(m)
+}
+func (m *ResumeReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PrepareShutdownClustersReply proto.InternalMessageInfo
+var xxx_messageInfo_ResumeReply proto.InternalMessageInfo
 
-type PrepareInitClusterRequest struct {
+func (m *ResumeReply) GetResumedStep() UpgradeSteps {
+	if m != nil {
+		return m.ResumedStep
+	}
+	return UpgradeSteps_UNKNOWN_STEP
+}
+
+func (m *ResumeReply) GetFailedStep() UpgradeSteps {
+	if m != nil {
+		return m.FailedStep
+	}
+	return UpgradeSteps_UNKNOWN_STEP
+}
+
+func (m *ResumeReply) GetPlans() []*DryRunPlan {
+	if m != nil {
+		return m.Plans
+	}
+	return nil
+}
+
+type RevertRequest struct {
+	DryRun               bool     `protobuf:"varint,1,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PrepareInitClusterRequest) Reset()         { *m = PrepareInitClusterRequest{} }
-func (m *PrepareInitClusterRequest) String() string { return proto.CompactTextString(m) }
-func (*PrepareInitClusterRequest) ProtoMessage()    {}
-func (*PrepareInitClusterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{30}
+func (m *RevertRequest) Reset()         { *m = RevertRequest{} }
+func (m *RevertRequest) String() string { return proto.CompactTextString(m) }
+func (*RevertRequest) ProtoMessage()    {}
+func (*RevertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{67}
 }
-func (m *PrepareInitClusterRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PrepareInitClusterRequest.Unmarshal(m, b)
+func (m *RevertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevertRequest.Unmarshal(m, b)
 }
-func (m *PrepareInitClusterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PrepareInitClusterRequest.Marshal(b, m, deterministic)
+func (m *RevertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevertRequest.Marshal(b, m, deterministic)
 }
-func (dst *PrepareInitClusterRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PrepareInitClusterRequest.Merge(dst, src)
+func (dst *RevertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevertRequest.Merge(dst, src)
 }
-func (m *PrepareInitClusterRequest) XXX_Size() int {
-	return xxx_messageInfo_PrepareInitClusterRequest.Size(m)
+func (m *RevertRequest) XXX_Size() int {
+	return xxx_messageInfo_RevertRequest.Size(m)
 }
-func (m *PrepareInitClusterRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_PrepareInitClusterRequest.DiscardUnknown(m)
+func (m *RevertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevertRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PrepareInitClusterRequest proto.InternalMessageInfo
+var xxx_messageInfo_RevertRequest proto.InternalMessageInfo
 
-type PrepareInitClusterReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *RevertRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
 }
 
-func (m *PrepareInitClusterReply) Reset()         { *m = PrepareInitClusterReply{} }
-func (m *PrepareInitClusterReply) String() string { return proto.CompactTextString(m) }
-func (*PrepareInitClusterReply) ProtoMessage()    {}
-func (*PrepareInitClusterReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{31}
+type RevertReply struct {
+	Plan                 *DryRunPlan `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
-func (m *PrepareInitClusterReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PrepareInitClusterReply.Unmarshal(m, b)
+
+func (m *RevertReply) Reset()         { *m = RevertReply{} }
+func (m *RevertReply) String() string { return proto.CompactTextString(m) }
+func (*RevertReply) ProtoMessage()    {}
+func (*RevertReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{68}
 }
-func (m *PrepareInitClusterReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PrepareInitClusterReply.Marshal(b, m, deterministic)
+func (m *RevertReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevertReply.Unmarshal(m, b)
 }
-func (dst *PrepareInitClusterReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PrepareInitClusterReply.Merge(dst, src)
+func (m *RevertReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevertReply.Marshal(b, m, deterministic)
 }
-func (m *PrepareInitClusterReply) XXX_Size() int {
-	return xxx_messageInfo_PrepareInitClusterReply.Size(m)
+func (dst *RevertReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevertReply.Merge(dst, src)
 }
-func (m *PrepareInitClusterReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_PrepareInitClusterReply.DiscardUnknown(m)
+func (m *RevertReply) XXX_Size() int {
+	return xxx_messageInfo_RevertReply.Size(m)
+}
+func (m *RevertReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevertReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PrepareInitClusterReply proto.InternalMessageInfo
+var xxx_messageInfo_RevertReply proto.InternalMessageInfo
 
-type UpgradeConvertMasterRequest struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *RevertReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
 }
 
-func (m *UpgradeConvertMasterRequest) Reset()         { *m = UpgradeConvertMasterRequest{} }
-func (m *UpgradeConvertMasterRequest) String() string { return proto.CompactTextString(m) }
-func (*UpgradeConvertMasterRequest) ProtoMessage()    {}
-func (*UpgradeConvertMasterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{32}
+// CancelRequest stops a step that runs pg_upgrade, which is then FAILED with
+// the reason "cancelled".
+type CancelRequest struct {
+	Step                 UpgradeSteps `protobuf:"varint,1,opt,name=step,proto3,enum=idl.UpgradeSteps" json:"step,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
-func (m *UpgradeConvertMasterRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpgradeConvertMasterRequest.Unmarshal(m, b)
+
+func (m *CancelRequest) Reset()         { *m = CancelRequest{} }
+func (m *CancelRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelRequest) ProtoMessage()    {}
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{69}
 }
-func (m *UpgradeConvertMasterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpgradeConvertMasterRequest.Marshal(b, m, deterministic)
+func (m *CancelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelRequest.Unmarshal(m, b)
 }
-func (dst *UpgradeConvertMasterRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpgradeConvertMasterRequest.Merge(dst, src)
+func (m *CancelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelRequest.Marshal(b, m, deterministic)
 }
-func (m *UpgradeConvertMasterRequest) XXX_Size() int {
-	return xxx_messageInfo_UpgradeConvertMasterRequest.Size(m)
+func (dst *CancelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelRequest.Merge(dst, src)
 }
-func (m *UpgradeConvertMasterRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpgradeConvertMasterRequest.DiscardUnknown(m)
+func (m *CancelRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelRequest.Size(m)
+}
+func (m *CancelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UpgradeConvertMasterRequest proto.InternalMessageInfo
+var xxx_messageInfo_CancelRequest proto.InternalMessageInfo
 
-type UpgradeConvertMasterReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *CancelRequest) GetStep() UpgradeSteps {
+	if m != nil {
+		return m.Step
+	}
+	return UpgradeSteps_UNKNOWN_STEP
 }
 
-func (m *UpgradeConvertMasterReply) Reset()         { *m = UpgradeConvertMasterReply{} }
-func (m *UpgradeConvertMasterReply) String() string { return proto.CompactTextString(m) }
-func (*UpgradeConvertMasterReply) ProtoMessage()    {}
-func (*UpgradeConvertMasterReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{33}
+type CancelReply struct {
+	Processes            []*CancelledProcess `protobuf:"bytes,1,rep,name=processes,proto3" json:"processes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
-func (m *UpgradeConvertMasterReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpgradeConvertMasterReply.Unmarshal(m, b)
+
+func (m *CancelReply) Reset()         { *m = CancelReply{} }
+func (m *CancelReply) String() string { return proto.CompactTextString(m) }
+func (*CancelReply) ProtoMessage()    {}
+func (*CancelReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{70}
 }
-func (m *UpgradeConvertMasterReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpgradeConvertMasterReply.Marshal(b, m, deterministic)
+func (m *CancelReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelReply.Unmarshal(m, b)
 }
-func (dst *UpgradeConvertMasterReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpgradeConvertMasterReply.Merge(dst, src)
+func (m *CancelReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelReply.Marshal(b, m, deterministic)
 }
-func (m *UpgradeConvertMasterReply) XXX_Size() int {
-	return xxx_messageInfo_UpgradeConvertMasterReply.Size(m)
+func (dst *CancelReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelReply.Merge(dst, src)
 }
-func (m *UpgradeConvertMasterReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpgradeConvertMasterReply.DiscardUnknown(m)
+func (m *CancelReply) XXX_Size() int {
+	return xxx_messageInfo_CancelReply.Size(m)
+}
+func (m *CancelReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelReply.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UpgradeConvertMasterReply proto.InternalMessageInfo
+var xxx_messageInfo_CancelReply proto.InternalMessageInfo
 
-type SetConfigRequest struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+func (m *CancelReply) GetProcesses() []*CancelledProcess {
+	if m != nil {
+		return m.Processes
+	}
+	return nil
+}
+
+type CancelledProcess struct {
+	Hostname             string   `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Content              int32    `protobuf:"varint,2,opt,name=content,proto3" json:"content,omitempty"`
+	Pid                  int32    `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SetConfigRequest) Reset()         { *m = SetConfigRequest{} }
-func (m *SetConfigRequest) String() string { return proto.CompactTextString(m) }
-func (*SetConfigRequest) ProtoMessage()    {}
-func (*SetConfigRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{34}
+func (m *CancelledProcess) Reset()         { *m = CancelledProcess{} }
+func (m *CancelledProcess) String() string { return proto.CompactTextString(m) }
+func (*CancelledProcess) ProtoMessage()    {}
+func (*CancelledProcess) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{71}
 }
-func (m *SetConfigRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetConfigRequest.Unmarshal(m, b)
+func (m *CancelledProcess) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelledProcess.Unmarshal(m, b)
 }
-func (m *SetConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetConfigRequest.Marshal(b, m, deterministic)
+func (m *CancelledProcess) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelledProcess.Marshal(b, m, deterministic)
 }
-func (dst *SetConfigRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetConfigRequest.Merge(dst, src)
+func (dst *CancelledProcess) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelledProcess.Merge(dst, src)
 }
-func (m *SetConfigRequest) XXX_Size() int {
-	return xxx_messageInfo_SetConfigRequest.Size(m)
+func (m *CancelledProcess) XXX_Size() int {
+	return xxx_messageInfo_CancelledProcess.Size(m)
 }
-func (m *SetConfigRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetConfigRequest.DiscardUnknown(m)
+func (m *CancelledProcess) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelledProcess.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetConfigRequest proto.InternalMessageInfo
+var xxx_messageInfo_CancelledProcess proto.InternalMessageInfo
 
-func (m *SetConfigRequest) GetName() string {
+func (m *CancelledProcess) GetHostname() string {
 	if m != nil {
-		return m.Name
+		return m.Hostname
 	}
 	return ""
 }
 
-func (m *SetConfigRequest) GetValue() string {
+func (m *CancelledProcess) GetContent() int32 {
 	if m != nil {
-		return m.Value
+		return m.Content
 	}
-	return ""
+	return 0
 }
 
-type SetConfigReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *CancelledProcess) GetPid() int32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
 }
 
-func (m *SetConfigReply) Reset()         { *m = SetConfigReply{} }
-func (m *SetConfigReply) String() string { return proto.CompactTextString(m) }
-func (*SetConfigReply) ProtoMessage()    {}
-func (*SetConfigReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{35}
+// DryRunPlan is returned instead of doing any work when a request has dryRun
+// set. It lists everything the request would have done, in order.
+type DryRunPlan struct {
+	Commands             []*PlannedCommand `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
+	Files                []*PlannedFile    `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty"`
+	Notes                []string          `protobuf:"bytes,3,rep,name=notes,proto3" json:"notes,omitempty"`
+	Step                 UpgradeSteps      `protobuf:"varint,4,opt,name=step,proto3,enum=idl.UpgradeSteps" json:"step,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
-func (m *SetConfigReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetConfigReply.Unmarshal(m, b)
+
+func (m *DryRunPlan) Reset()         { *m = DryRunPlan{} }
+func (m *DryRunPlan) String() string { return proto.CompactTextString(m) }
+func (*DryRunPlan) ProtoMessage()    {}
+func (*DryRunPlan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{72}
 }
-func (m *SetConfigReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetConfigReply.Marshal(b, m, deterministic)
+func (m *DryRunPlan) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DryRunPlan.Unmarshal(m, b)
 }
-func (dst *SetConfigReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetConfigReply.Merge(dst, src)
+func (m *DryRunPlan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DryRunPlan.Marshal(b, m, deterministic)
 }
-func (m *SetConfigReply) XXX_Size() int {
-	return xxx_messageInfo_SetConfigReply.Size(m)
+func (dst *DryRunPlan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DryRunPlan.Merge(dst, src)
 }
-func (m *SetConfigReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetConfigReply.DiscardUnknown(m)
+func (m *DryRunPlan) XXX_Size() int {
+	return xxx_messageInfo_DryRunPlan.Size(m)
+}
+func (m *DryRunPlan) XXX_DiscardUnknown() {
+	xxx_messageInfo_DryRunPlan.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetConfigReply proto.InternalMessageInfo
+var xxx_messageInfo_DryRunPlan proto.InternalMessageInfo
 
-type GetConfigRequest struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+func (m *DryRunPlan) GetCommands() []*PlannedCommand {
+	if m != nil {
+		return m.Commands
+	}
+	return nil
+}
+
+func (m *DryRunPlan) GetFiles() []*PlannedFile {
+	if m != nil {
+		return m.Files
+	}
+	return nil
+}
+
+func (m *DryRunPlan) GetNotes() []string {
+	if m != nil {
+		return m.Notes
+	}
+	return nil
+}
+
+func (m *DryRunPlan) GetStep() UpgradeSteps {
+	if m != nil {
+		return m.Step
+	}
+	return UpgradeSteps_UNKNOWN_STEP
+}
+
+type PlannedCommand struct {
+	Hostname             string   `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Command              string   `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetConfigRequest) Reset()         { *m = GetConfigRequest{} }
-func (m *GetConfigRequest) String() string { return proto.CompactTextString(m) }
-func (*GetConfigRequest) ProtoMessage()    {}
-func (*GetConfigRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{36}
+func (m *PlannedCommand) Reset()         { *m = PlannedCommand{} }
+func (m *PlannedCommand) String() string { return proto.CompactTextString(m) }
+func (*PlannedCommand) ProtoMessage()    {}
+func (*PlannedCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{73}
 }
-func (m *GetConfigRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetConfigRequest.Unmarshal(m, b)
+func (m *PlannedCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlannedCommand.Unmarshal(m, b)
 }
-func (m *GetConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetConfigRequest.Marshal(b, m, deterministic)
+func (m *PlannedCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlannedCommand.Marshal(b, m, deterministic)
 }
-func (dst *GetConfigRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetConfigRequest.Merge(dst, src)
+func (dst *PlannedCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlannedCommand.Merge(dst, src)
 }
-func (m *GetConfigRequest) XXX_Size() int {
-	return xxx_messageInfo_GetConfigRequest.Size(m)
+func (m *PlannedCommand) XXX_Size() int {
+	return xxx_messageInfo_PlannedCommand.Size(m)
 }
-func (m *GetConfigRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetConfigRequest.DiscardUnknown(m)
+func (m *PlannedCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlannedCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetConfigRequest proto.InternalMessageInfo
+var xxx_messageInfo_PlannedCommand proto.InternalMessageInfo
 
-func (m *GetConfigRequest) GetName() string {
+func (m *PlannedCommand) GetHostname() string {
 	if m != nil {
-		return m.Name
+		return m.Hostname
 	}
 	return ""
 }
 
-type GetConfigReply struct {
-	Value                string   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+func (m *PlannedCommand) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
+type PlannedFile struct {
+	Hostname             string   `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Contents             string   `protobuf:"bytes,3,opt,name=contents,proto3" json:"contents,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetConfigReply) Reset()         { *m = GetConfigReply{} }
-func (m *GetConfigReply) String() string { return proto.CompactTextString(m) }
-func (*GetConfigReply) ProtoMessage()    {}
-func (*GetConfigReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{37}
+func (m *PlannedFile) Reset()         { *m = PlannedFile{} }
+func (m *PlannedFile) String() string { return proto.CompactTextString(m) }
+func (*PlannedFile) ProtoMessage()    {}
+func (*PlannedFile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cli_to_hub_d73ff696b1e4c0fa, []int{74}
 }
-func (m *GetConfigReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetConfigReply.Unmarshal(m, b)
+func (m *PlannedFile) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlannedFile.Unmarshal(m, b)
 }
-func (m *GetConfigReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetConfigReply.Marshal(b, m, deterministic)
+func (m *PlannedFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlannedFile.Marshal(b, m, deterministic)
 }
-func (dst *GetConfigReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetConfigReply.Merge(dst, src)
+func (dst *PlannedFile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlannedFile.Merge(dst, src)
 }
-func (m *GetConfigReply) XXX_Size() int {
-	return xxx_messageInfo_GetConfigReply.Size(m)
+func (m *PlannedFile) XXX_Size() int {
+	return xxx_messageInfo_PlannedFile.Size(m)
 }
-func (m *GetConfigReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetConfigReply.DiscardUnknown(m)
+func (m *PlannedFile) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlannedFile.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetConfigReply proto.InternalMessageInfo
+var xxx_messageInfo_PlannedFile proto.InternalMessageInfo
 
-func (m *GetConfigReply) GetValue() string {
+func (m *PlannedFile) GetHostname() string {
 	if m != nil {
-		return m.Value
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *PlannedFile) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *PlannedFile) GetContents() string {
+	if m != nil {
+		return m.Contents
 	}
 	return ""
 }
@@ -1369,6 +3592,10 @@ func (m *GetConfigReply) GetValue() string {
 func init() {
 	proto.RegisterType((*UpgradeReconfigurePortsRequest)(nil), "idl.UpgradeReconfigurePortsRequest")
 	proto.RegisterType((*UpgradeReconfigurePortsReply)(nil), "idl.UpgradeReconfigurePortsReply")
+	proto.RegisterType((*UpgradeRebuildMirrorsRequest)(nil), "idl.UpgradeRebuildMirrorsRequest")
+	proto.RegisterType((*UpgradeRebuildMirrorsReply)(nil), "idl.UpgradeRebuildMirrorsReply")
+	proto.RegisterType((*UpgradeRebuildStandbyRequest)(nil), "idl.UpgradeRebuildStandbyRequest")
+	proto.RegisterType((*UpgradeRebuildStandbyReply)(nil), "idl.UpgradeRebuildStandbyReply")
 	proto.RegisterType((*UpgradeConvertPrimariesRequest)(nil), "idl.UpgradeConvertPrimariesRequest")
 	proto.RegisterType((*UpgradeConvertPrimariesReply)(nil), "idl.UpgradeConvertPrimariesReply")
 	proto.RegisterType((*UpgradeShareOidsRequest)(nil), "idl.UpgradeShareOidsRequest")
@@ -1379,9 +3606,19 @@ func init() {
 	proto.RegisterType((*PingReply)(nil), "idl.PingReply")
 	proto.RegisterType((*StatusConversionRequest)(nil), "idl.StatusConversionRequest")
 	proto.RegisterType((*StatusConversionReply)(nil), "idl.StatusConversionReply")
+	proto.RegisterType((*StatusAgentsRequest)(nil), "idl.StatusAgentsRequest")
+	proto.RegisterType((*StatusAgentsReply)(nil), "idl.StatusAgentsReply")
+	proto.RegisterType((*AgentStatus)(nil), "idl.AgentStatus")
+	proto.RegisterType((*ConversionStatus)(nil), "idl.ConversionStatus")
 	proto.RegisterType((*StatusUpgradeRequest)(nil), "idl.StatusUpgradeRequest")
 	proto.RegisterType((*StatusUpgradeReply)(nil), "idl.StatusUpgradeReply")
+	proto.RegisterType((*WatchUpgradeRequest)(nil), "idl.WatchUpgradeRequest")
+	proto.RegisterType((*WatchUpgradeReply)(nil), "idl.WatchUpgradeReply")
 	proto.RegisterType((*UpgradeStepStatus)(nil), "idl.UpgradeStepStatus")
+	proto.RegisterType((*StepTiming)(nil), "idl.StepTiming")
+	proto.RegisterType((*StepFailure)(nil), "idl.StepFailure")
+	proto.RegisterType((*StepTransition)(nil), "idl.StepTransition")
+	proto.RegisterType((*HostStepStatus)(nil), "idl.HostStepStatus")
 	proto.RegisterType((*CheckConfigRequest)(nil), "idl.CheckConfigRequest")
 	proto.RegisterType((*CheckConfigReply)(nil), "idl.CheckConfigReply")
 	proto.RegisterType((*CheckSeginstallRequest)(nil), "idl.CheckSeginstallRequest")
@@ -1391,10 +3628,21 @@ func init() {
 	proto.RegisterType((*CountPerDb)(nil), "idl.CountPerDb")
 	proto.RegisterType((*CheckObjectCountRequest)(nil), "idl.CheckObjectCountRequest")
 	proto.RegisterType((*CheckObjectCountReply)(nil), "idl.CheckObjectCountReply")
+	proto.RegisterType((*CheckCatalogRequest)(nil), "idl.CheckCatalogRequest")
+	proto.RegisterType((*CheckCatalogReply)(nil), "idl.CheckCatalogReply")
+	proto.RegisterType((*CatalogIssue)(nil), "idl.CatalogIssue")
+	proto.RegisterType((*CheckPortsRequest)(nil), "idl.CheckPortsRequest")
+	proto.RegisterType((*CheckPortsReply)(nil), "idl.CheckPortsReply")
+	proto.RegisterType((*TargetPortStatus)(nil), "idl.TargetPortStatus")
+	proto.RegisterType((*PortStatus)(nil), "idl.PortStatus")
 	proto.RegisterType((*CheckVersionRequest)(nil), "idl.CheckVersionRequest")
 	proto.RegisterType((*CheckVersionReply)(nil), "idl.CheckVersionReply")
 	proto.RegisterType((*CheckDiskSpaceRequest)(nil), "idl.CheckDiskSpaceRequest")
 	proto.RegisterType((*CheckDiskSpaceReply)(nil), "idl.CheckDiskSpaceReply")
+	proto.RegisterType((*FileSysUsage)(nil), "idl.FileSysUsage")
+	proto.RegisterType((*HostDiskUsage)(nil), "idl.HostDiskUsage")
+	proto.RegisterType((*SegmentDiskSpace)(nil), "idl.SegmentDiskSpace")
+	proto.RegisterType((*DiskSpaceNeed)(nil), "idl.DiskSpaceNeed")
 	proto.RegisterType((*PrepareShutdownClustersRequest)(nil), "idl.PrepareShutdownClustersRequest")
 	proto.RegisterType((*PrepareShutdownClustersReply)(nil), "idl.PrepareShutdownClustersReply")
 	proto.RegisterType((*PrepareInitClusterRequest)(nil), "idl.PrepareInitClusterRequest")
@@ -1405,6 +3653,19 @@ func init() {
 	proto.RegisterType((*SetConfigReply)(nil), "idl.SetConfigReply")
 	proto.RegisterType((*GetConfigRequest)(nil), "idl.GetConfigRequest")
 	proto.RegisterType((*GetConfigReply)(nil), "idl.GetConfigReply")
+	proto.RegisterType((*RunRequest)(nil), "idl.RunRequest")
+	proto.RegisterType((*RunReply)(nil), "idl.RunReply")
+	proto.RegisterType((*ResumeRequest)(nil), "idl.ResumeRequest")
+	proto.RegisterType((*ResumeReply)(nil), "idl.ResumeReply")
+	proto.RegisterType((*RevertRequest)(nil), "idl.RevertRequest")
+	proto.RegisterType((*RevertReply)(nil), "idl.RevertReply")
+	proto.RegisterType((*CancelRequest)(nil), "idl.CancelRequest")
+	proto.RegisterType((*CancelReply)(nil), "idl.CancelReply")
+	proto.RegisterType((*CancelledProcess)(nil), "idl.CancelledProcess")
+	proto.RegisterType((*DryRunPlan)(nil), "idl.DryRunPlan")
+	proto.RegisterType((*PlannedCommand)(nil), "idl.PlannedCommand")
+	proto.RegisterType((*PlannedFile)(nil), "idl.PlannedFile")
+	proto.RegisterEnum("idl.SegmentRole", SegmentRole_name, SegmentRole_value)
 	proto.RegisterEnum("idl.UpgradeSteps", UpgradeSteps_name, UpgradeSteps_value)
 	proto.RegisterEnum("idl.StepStatus", StepStatus_name, StepStatus_value)
 }
@@ -1423,12 +3684,16 @@ const _ = grpc.SupportPackageIsVersion4
 type CliToHubClient interface {
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingReply, error)
 	StatusUpgrade(ctx context.Context, in *StatusUpgradeRequest, opts ...grpc.CallOption) (*StatusUpgradeReply, error)
+	WatchUpgrade(ctx context.Context, in *WatchUpgradeRequest, opts ...grpc.CallOption) (CliToHub_WatchUpgradeClient, error)
 	StatusConversion(ctx context.Context, in *StatusConversionRequest, opts ...grpc.CallOption) (*StatusConversionReply, error)
+	StatusAgents(ctx context.Context, in *StatusAgentsRequest, opts ...grpc.CallOption) (*StatusAgentsReply, error)
 	CheckConfig(ctx context.Context, in *CheckConfigRequest, opts ...grpc.CallOption) (*CheckConfigReply, error)
 	CheckSeginstall(ctx context.Context, in *CheckSeginstallRequest, opts ...grpc.CallOption) (*CheckSeginstallReply, error)
 	CheckObjectCount(ctx context.Context, in *CheckObjectCountRequest, opts ...grpc.CallOption) (*CheckObjectCountReply, error)
+	CheckCatalog(ctx context.Context, in *CheckCatalogRequest, opts ...grpc.CallOption) (*CheckCatalogReply, error)
 	CheckVersion(ctx context.Context, in *CheckVersionRequest, opts ...grpc.CallOption) (*CheckVersionReply, error)
 	CheckDiskSpace(ctx context.Context, in *CheckDiskSpaceRequest, opts ...grpc.CallOption) (*CheckDiskSpaceReply, error)
+	CheckPorts(ctx context.Context, in *CheckPortsRequest, opts ...grpc.CallOption) (*CheckPortsReply, error)
 	PrepareInitCluster(ctx context.Context, in *PrepareInitClusterRequest, opts ...grpc.CallOption) (*PrepareInitClusterReply, error)
 	PrepareShutdownClusters(ctx context.Context, in *PrepareShutdownClustersRequest, opts ...grpc.CallOption) (*PrepareShutdownClustersReply, error)
 	UpgradeConvertMaster(ctx context.Context, in *UpgradeConvertMasterRequest, opts ...grpc.CallOption) (*UpgradeConvertMasterReply, error)
@@ -1436,9 +3701,15 @@ type CliToHubClient interface {
 	UpgradeShareOids(ctx context.Context, in *UpgradeShareOidsRequest, opts ...grpc.CallOption) (*UpgradeShareOidsReply, error)
 	UpgradeValidateStartCluster(ctx context.Context, in *UpgradeValidateStartClusterRequest, opts ...grpc.CallOption) (*UpgradeValidateStartClusterReply, error)
 	UpgradeConvertPrimaries(ctx context.Context, in *UpgradeConvertPrimariesRequest, opts ...grpc.CallOption) (*UpgradeConvertPrimariesReply, error)
+	UpgradeRebuildMirrors(ctx context.Context, in *UpgradeRebuildMirrorsRequest, opts ...grpc.CallOption) (*UpgradeRebuildMirrorsReply, error)
+	UpgradeRebuildStandby(ctx context.Context, in *UpgradeRebuildStandbyRequest, opts ...grpc.CallOption) (*UpgradeRebuildStandbyReply, error)
 	UpgradeReconfigurePorts(ctx context.Context, in *UpgradeReconfigurePortsRequest, opts ...grpc.CallOption) (*UpgradeReconfigurePortsReply, error)
 	SetConfig(ctx context.Context, in *SetConfigRequest, opts ...grpc.CallOption) (*SetConfigReply, error)
 	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigReply, error)
+	Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (*RunReply, error)
+	Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeReply, error)
+	Revert(ctx context.Context, in *RevertRequest, opts ...grpc.CallOption) (*RevertReply, error)
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelReply, error)
 }
 
 type cliToHubClient struct {
@@ -1467,6 +3738,38 @@ func (c *cliToHubClient) StatusUpgrade(ctx context.Context, in *StatusUpgradeReq
 	return out, nil
 }
 
+func (c *cliToHubClient) WatchUpgrade(ctx context.Context, in *WatchUpgradeRequest, opts ...grpc.CallOption) (CliToHub_WatchUpgradeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CliToHub_serviceDesc.Streams[0], "/idl.CliToHub/WatchUpgrade", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cliToHubWatchUpgradeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CliToHub_WatchUpgradeClient interface {
+	Recv() (*WatchUpgradeReply, error)
+	grpc.ClientStream
+}
+
+type cliToHubWatchUpgradeClient struct {
+	grpc.ClientStream
+}
+
+func (x *cliToHubWatchUpgradeClient) Recv() (*WatchUpgradeReply, error) {
+	m := new(WatchUpgradeReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *cliToHubClient) StatusConversion(ctx context.Context, in *StatusConversionRequest, opts ...grpc.CallOption) (*StatusConversionReply, error) {
 	out := new(StatusConversionReply)
 	err := c.cc.Invoke(ctx, "/idl.CliToHub/StatusConversion", in, out, opts...)
@@ -1476,6 +3779,15 @@ func (c *cliToHubClient) StatusConversion(ctx context.Context, in *StatusConvers
 	return out, nil
 }
 
+func (c *cliToHubClient) StatusAgents(ctx context.Context, in *StatusAgentsRequest, opts ...grpc.CallOption) (*StatusAgentsReply, error) {
+	out := new(StatusAgentsReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/StatusAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cliToHubClient) CheckConfig(ctx context.Context, in *CheckConfigRequest, opts ...grpc.CallOption) (*CheckConfigReply, error) {
 	out := new(CheckConfigReply)
 	err := c.cc.Invoke(ctx, "/idl.CliToHub/CheckConfig", in, out, opts...)
@@ -1503,6 +3815,15 @@ func (c *cliToHubClient) CheckObjectCount(ctx context.Context, in *CheckObjectCo
 	return out, nil
 }
 
+func (c *cliToHubClient) CheckCatalog(ctx context.Context, in *CheckCatalogRequest, opts ...grpc.CallOption) (*CheckCatalogReply, error) {
+	out := new(CheckCatalogReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/CheckCatalog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cliToHubClient) CheckVersion(ctx context.Context, in *CheckVersionRequest, opts ...grpc.CallOption) (*CheckVersionReply, error) {
 	out := new(CheckVersionReply)
 	err := c.cc.Invoke(ctx, "/idl.CliToHub/CheckVersion", in, out, opts...)
@@ -1521,6 +3842,15 @@ func (c *cliToHubClient) CheckDiskSpace(ctx context.Context, in *CheckDiskSpaceR
 	return out, nil
 }
 
+func (c *cliToHubClient) CheckPorts(ctx context.Context, in *CheckPortsRequest, opts ...grpc.CallOption) (*CheckPortsReply, error) {
+	out := new(CheckPortsReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/CheckPorts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cliToHubClient) PrepareInitCluster(ctx context.Context, in *PrepareInitClusterRequest, opts ...grpc.CallOption) (*PrepareInitClusterReply, error) {
 	out := new(PrepareInitClusterReply)
 	err := c.cc.Invoke(ctx, "/idl.CliToHub/PrepareInitCluster", in, out, opts...)
@@ -1584,6 +3914,24 @@ func (c *cliToHubClient) UpgradeConvertPrimaries(ctx context.Context, in *Upgrad
 	return out, nil
 }
 
+func (c *cliToHubClient) UpgradeRebuildMirrors(ctx context.Context, in *UpgradeRebuildMirrorsRequest, opts ...grpc.CallOption) (*UpgradeRebuildMirrorsReply, error) {
+	out := new(UpgradeRebuildMirrorsReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/UpgradeRebuildMirrors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) UpgradeRebuildStandby(ctx context.Context, in *UpgradeRebuildStandbyRequest, opts ...grpc.CallOption) (*UpgradeRebuildStandbyReply, error) {
+	out := new(UpgradeRebuildStandbyReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/UpgradeRebuildStandby", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cliToHubClient) UpgradeReconfigurePorts(ctx context.Context, in *UpgradeReconfigurePortsRequest, opts ...grpc.CallOption) (*UpgradeReconfigurePortsReply, error) {
 	out := new(UpgradeReconfigurePortsReply)
 	err := c.cc.Invoke(ctx, "/idl.CliToHub/UpgradeReconfigurePorts", in, out, opts...)
@@ -1611,16 +3959,56 @@ func (c *cliToHubClient) GetConfig(ctx context.Context, in *GetConfigRequest, op
 	return out, nil
 }
 
+func (c *cliToHubClient) Run(ctx context.Context, in *RunRequest, opts ...grpc.CallOption) (*RunReply, error) {
+	out := new(RunReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/Run", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeReply, error) {
+	out := new(ResumeReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/Resume", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) Revert(ctx context.Context, in *RevertRequest, opts ...grpc.CallOption) (*RevertReply, error) {
+	out := new(RevertReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/Revert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelReply, error) {
+	out := new(CancelReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CliToHubServer is the server API for CliToHub service.
 type CliToHubServer interface {
 	Ping(context.Context, *PingRequest) (*PingReply, error)
 	StatusUpgrade(context.Context, *StatusUpgradeRequest) (*StatusUpgradeReply, error)
+	WatchUpgrade(*WatchUpgradeRequest, CliToHub_WatchUpgradeServer) error
 	StatusConversion(context.Context, *StatusConversionRequest) (*StatusConversionReply, error)
+	StatusAgents(context.Context, *StatusAgentsRequest) (*StatusAgentsReply, error)
 	CheckConfig(context.Context, *CheckConfigRequest) (*CheckConfigReply, error)
 	CheckSeginstall(context.Context, *CheckSeginstallRequest) (*CheckSeginstallReply, error)
 	CheckObjectCount(context.Context, *CheckObjectCountRequest) (*CheckObjectCountReply, error)
+	CheckCatalog(context.Context, *CheckCatalogRequest) (*CheckCatalogReply, error)
 	CheckVersion(context.Context, *CheckVersionRequest) (*CheckVersionReply, error)
 	CheckDiskSpace(context.Context, *CheckDiskSpaceRequest) (*CheckDiskSpaceReply, error)
+	CheckPorts(context.Context, *CheckPortsRequest) (*CheckPortsReply, error)
 	PrepareInitCluster(context.Context, *PrepareInitClusterRequest) (*PrepareInitClusterReply, error)
 	PrepareShutdownClusters(context.Context, *PrepareShutdownClustersRequest) (*PrepareShutdownClustersReply, error)
 	UpgradeConvertMaster(context.Context, *UpgradeConvertMasterRequest) (*UpgradeConvertMasterReply, error)
@@ -1628,9 +4016,15 @@ type CliToHubServer interface {
 	UpgradeShareOids(context.Context, *UpgradeShareOidsRequest) (*UpgradeShareOidsReply, error)
 	UpgradeValidateStartCluster(context.Context, *UpgradeValidateStartClusterRequest) (*UpgradeValidateStartClusterReply, error)
 	UpgradeConvertPrimaries(context.Context, *UpgradeConvertPrimariesRequest) (*UpgradeConvertPrimariesReply, error)
+	UpgradeRebuildMirrors(context.Context, *UpgradeRebuildMirrorsRequest) (*UpgradeRebuildMirrorsReply, error)
+	UpgradeRebuildStandby(context.Context, *UpgradeRebuildStandbyRequest) (*UpgradeRebuildStandbyReply, error)
 	UpgradeReconfigurePorts(context.Context, *UpgradeReconfigurePortsRequest) (*UpgradeReconfigurePortsReply, error)
 	SetConfig(context.Context, *SetConfigRequest) (*SetConfigReply, error)
 	GetConfig(context.Context, *GetConfigRequest) (*GetConfigReply, error)
+	Run(context.Context, *RunRequest) (*RunReply, error)
+	Resume(context.Context, *ResumeRequest) (*ResumeReply, error)
+	Revert(context.Context, *RevertRequest) (*RevertReply, error)
+	Cancel(context.Context, *CancelRequest) (*CancelReply, error)
 }
 
 func RegisterCliToHubServer(s *grpc.Server, srv CliToHubServer) {
@@ -1673,6 +4067,27 @@ func _CliToHub_StatusUpgrade_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_WatchUpgrade_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUpgradeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CliToHubServer).WatchUpgrade(m, &cliToHubWatchUpgradeServer{stream})
+}
+
+type CliToHub_WatchUpgradeServer interface {
+	Send(*WatchUpgradeReply) error
+	grpc.ServerStream
+}
+
+type cliToHubWatchUpgradeServer struct {
+	grpc.ServerStream
+}
+
+func (x *cliToHubWatchUpgradeServer) Send(m *WatchUpgradeReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _CliToHub_StatusConversion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StatusConversionRequest)
 	if err := dec(in); err != nil {
@@ -1691,6 +4106,24 @@ func _CliToHub_StatusConversion_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_StatusAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).StatusAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/StatusAgents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).StatusAgents(ctx, req.(*StatusAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CliToHub_CheckConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CheckConfigRequest)
 	if err := dec(in); err != nil {
@@ -1745,6 +4178,24 @@ func _CliToHub_CheckObjectCount_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_CheckCatalog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckCatalogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).CheckCatalog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/CheckCatalog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).CheckCatalog(ctx, req.(*CheckCatalogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CliToHub_CheckVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CheckVersionRequest)
 	if err := dec(in); err != nil {
@@ -1781,6 +4232,24 @@ func _CliToHub_CheckDiskSpace_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_CheckPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPortsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).CheckPorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/CheckPorts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).CheckPorts(ctx, req.(*CheckPortsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CliToHub_PrepareInitCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PrepareInitClusterRequest)
 	if err := dec(in); err != nil {
@@ -1907,6 +4376,42 @@ func _CliToHub_UpgradeConvertPrimaries_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_UpgradeRebuildMirrors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpgradeRebuildMirrorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).UpgradeRebuildMirrors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/UpgradeRebuildMirrors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).UpgradeRebuildMirrors(ctx, req.(*UpgradeRebuildMirrorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_UpgradeRebuildStandby_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpgradeRebuildStandbyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).UpgradeRebuildStandby(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/UpgradeRebuildStandby",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).UpgradeRebuildStandby(ctx, req.(*UpgradeRebuildStandbyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CliToHub_UpgradeReconfigurePorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpgradeReconfigurePortsRequest)
 	if err := dec(in); err != nil {
@@ -1961,6 +4466,78 @@ func _CliToHub_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_Run_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).Run(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/Run",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).Run(ctx, req.(*RunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/Resume",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).Resume(ctx, req.(*ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_Revert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).Revert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/Revert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).Revert(ctx, req.(*RevertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CliToHub_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "idl.CliToHub",
 	HandlerType: (*CliToHubServer)(nil),
@@ -1977,6 +4554,10 @@ var _CliToHub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "StatusConversion",
 			Handler:    _CliToHub_StatusConversion_Handler,
 		},
+		{
+			MethodName: "StatusAgents",
+			Handler:    _CliToHub_StatusAgents_Handler,
+		},
 		{
 			MethodName: "CheckConfig",
 			Handler:    _CliToHub_CheckConfig_Handler,
@@ -1989,6 +4570,10 @@ var _CliToHub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CheckObjectCount",
 			Handler:    _CliToHub_CheckObjectCount_Handler,
 		},
+		{
+			MethodName: "CheckCatalog",
+			Handler:    _CliToHub_CheckCatalog_Handler,
+		},
 		{
 			MethodName: "CheckVersion",
 			Handler:    _CliToHub_CheckVersion_Handler,
@@ -1997,6 +4582,10 @@ var _CliToHub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CheckDiskSpace",
 			Handler:    _CliToHub_CheckDiskSpace_Handler,
 		},
+		{
+			MethodName: "CheckPorts",
+			Handler:    _CliToHub_CheckPorts_Handler,
+		},
 		{
 			MethodName: "PrepareInitCluster",
 			Handler:    _CliToHub_PrepareInitCluster_Handler,
@@ -2025,6 +4614,14 @@ var _CliToHub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UpgradeConvertPrimaries",
 			Handler:    _CliToHub_UpgradeConvertPrimaries_Handler,
 		},
+		{
+			MethodName: "UpgradeRebuildMirrors",
+			Handler:    _CliToHub_UpgradeRebuildMirrors_Handler,
+		},
+		{
+			MethodName: "UpgradeRebuildStandby",
+			Handler:    _CliToHub_UpgradeRebuildStandby_Handler,
+		},
 		{
 			MethodName: "UpgradeReconfigurePorts",
 			Handler:    _CliToHub_UpgradeReconfigurePorts_Handler,
@@ -2037,85 +4634,205 @@ var _CliToHub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetConfig",
 			Handler:    _CliToHub_GetConfig_Handler,
 		},
+		{
+			MethodName: "Run",
+			Handler:    _CliToHub_Run_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _CliToHub_Resume_Handler,
+		},
+		{
+			MethodName: "Revert",
+			Handler:    _CliToHub_Revert_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _CliToHub_Cancel_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUpgrade",
+			Handler:       _CliToHub_WatchUpgrade_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "cli_to_hub.proto",
 }
 
 func init() { proto.RegisterFile("cli_to_hub.proto", fileDescriptor_cli_to_hub_d73ff696b1e4c0fa) }
 
 var fileDescriptor_cli_to_hub_d73ff696b1e4c0fa = []byte{
-	// 1145 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x57, 0x6d, 0x6f, 0xe2, 0x46,
-	0x10, 0x86, 0xbc, 0x33, 0x70, 0x9c, 0xb3, 0x09, 0x04, 0x9c, 0x34, 0x4a, 0xdd, 0xde, 0x8b, 0xf2,
-	0x21, 0xaa, 0x72, 0x52, 0xa5, 0x4a, 0x95, 0x2a, 0x17, 0x1c, 0x62, 0x1d, 0x31, 0xc8, 0x36, 0xa9,
-	0x54, 0x55, 0x42, 0x06, 0xf6, 0x88, 0xef, 0x1c, 0x9b, 0xda, 0xcb, 0x55, 0xf9, 0x45, 0xfd, 0x65,
-	0xfd, 0x1f, 0xd5, 0xee, 0xda, 0xf8, 0x9d, 0xf6, 0x9b, 0x77, 0x9e, 0x99, 0x67, 0xf6, 0x65, 0xf6,
-	0x99, 0x35, 0x08, 0x73, 0xc7, 0x9e, 0x12, 0x6f, 0xfa, 0xb4, 0x9e, 0xdd, 0xac, 0x7c, 0x8f, 0x78,
-	0x68, 0xd7, 0x5e, 0x38, 0xd2, 0x15, 0x5c, 0x4e, 0x56, 0x4b, 0xdf, 0x5a, 0x60, 0x1d, 0xcf, 0x3d,
-	0xf7, 0x93, 0xbd, 0x5c, 0xfb, 0x78, 0xec, 0xf9, 0x24, 0xd0, 0xf1, 0x9f, 0x6b, 0x1c, 0x10, 0xe9,
-	0x12, 0x2e, 0x4a, 0x3d, 0x56, 0xce, 0x4b, 0x82, 0xa1, 0xe7, 0xb9, 0x5f, 0xb1, 0x4f, 0xc6, 0xbe,
-	0xfd, 0x6c, 0xf9, 0x36, 0x2e, 0x60, 0xc8, 0x7b, 0x50, 0x86, 0x2e, 0x9c, 0x85, 0xb8, 0xf1, 0x64,
-	0xf9, 0x78, 0x64, 0x2f, 0x36, 0xa1, 0x67, 0xd0, 0xca, 0x43, 0x34, 0xe6, 0x7b, 0x90, 0x42, 0xe0,
-	0xd1, 0x72, 0xec, 0x85, 0x45, 0xb0, 0x41, 0x2c, 0x9f, 0xf4, 0x9c, 0x75, 0x40, 0xb0, 0x1f, 0x85,
-	0x4b, 0x70, 0xb5, 0xd5, 0x8b, 0x32, 0xbd, 0x82, 0xfa, 0xd8, 0x76, 0x97, 0x51, 0x48, 0x1d, 0x6a,
-	0x7c, 0x18, 0xce, 0xcc, 0x20, 0x16, 0x59, 0x07, 0x7c, 0xe2, 0x81, 0xed, 0xb9, 0x91, 0xdf, 0x00,
-	0x5a, 0x79, 0x68, 0xe5, 0xbc, 0xa0, 0x1b, 0x40, 0xf3, 0x8d, 0x89, 0xbb, 0xe0, 0xa0, 0x53, 0xbd,
-	0xda, 0x7d, 0x5f, 0xd3, 0x0b, 0x10, 0xa9, 0x0d, 0xa7, 0xfc, 0x7b, 0xb3, 0xcb, 0x3c, 0xc1, 0x67,
-	0x40, 0x19, 0x3b, 0x65, 0x37, 0xa1, 0xeb, 0xd8, 0x01, 0x19, 0x7d, 0x8a, 0xb6, 0x85, 0xe0, 0x55,
-	0x2a, 0x49, 0xfd, 0xb6, 0x7d, 0x63, 0x2f, 0x9c, 0x9b, 0x1c, 0xae, 0x97, 0x07, 0x4a, 0x73, 0x38,
-	0xce, 0x99, 0xd1, 0x1b, 0xd8, 0x0b, 0x08, 0x5e, 0x75, 0xaa, 0x57, 0xd5, 0xf7, 0xcd, 0xdb, 0xe3,
-	0x2c, 0x6b, 0xa0, 0x33, 0x18, 0xbd, 0x83, 0x83, 0x80, 0x05, 0x74, 0x76, 0x98, 0xe3, 0x6b, 0xe6,
-	0x98, 0xc8, 0x1b, 0xc2, 0xd2, 0x29, 0xa0, 0xde, 0x13, 0x9e, 0x7f, 0xe9, 0xb1, 0x22, 0x8a, 0x96,
-	0xf9, 0x23, 0x08, 0x29, 0x2b, 0x5d, 0xa4, 0x04, 0x0d, 0x3e, 0xe4, 0x0c, 0x6c, 0x06, 0x35, 0x3d,
-	0x65, 0x93, 0x3a, 0xd0, 0x66, 0x71, 0x06, 0x5e, 0xda, 0x6e, 0x40, 0x2c, 0xc7, 0x89, 0x18, 0xdb,
-	0x70, 0x9a, 0x43, 0xe8, 0x61, 0x9e, 0x43, 0x77, 0xec, 0xe3, 0x95, 0xe5, 0xf3, 0x22, 0x90, 0x97,
-	0xd8, 0x8d, 0xab, 0xbc, 0x0b, 0x67, 0x45, 0x20, 0x8d, 0xfb, 0x03, 0xa0, 0xe7, 0xad, 0x5d, 0x32,
-	0xc6, 0x7e, 0x7f, 0x86, 0xda, 0x70, 0xd0, 0x9f, 0x69, 0xd6, 0x33, 0x0e, 0x67, 0x15, 0x8e, 0x50,
-	0x07, 0x0e, 0x65, 0x8f, 0xf9, 0xb1, 0x7d, 0xd8, 0xd7, 0xa3, 0x21, 0xba, 0x80, 0xda, 0x3d, 0xb6,
-	0x56, 0x1c, 0xdb, 0x65, 0x58, 0x6c, 0xa0, 0x89, 0xd9, 0x6c, 0x47, 0xb3, 0xcf, 0x78, 0x4e, 0x98,
-	0x2d, 0x9a, 0xd3, 0x10, 0x5a, 0x79, 0x88, 0xee, 0xcf, 0x07, 0x68, 0x0c, 0xd9, 0x59, 0x32, 0x5b,
-	0x74, 0xee, 0x7c, 0xe3, 0xe3, 0xa9, 0xea, 0x29, 0x27, 0xa9, 0x05, 0x27, 0x8c, 0xed, 0x31, 0x5d,
-	0xc7, 0x0a, 0x1c, 0xa7, 0xcd, 0x34, 0xc1, 0x0f, 0x70, 0xa2, 0x06, 0xa1, 0xa5, 0xe7, 0x3d, 0xaf,
-	0x2c, 0x62, 0xcf, 0x1c, 0xbe, 0xe2, 0x23, 0xbd, 0x08, 0xa2, 0x17, 0x95, 0xd1, 0xf4, 0xed, 0xe0,
-	0x8b, 0xb1, 0xb2, 0xe6, 0x38, 0xbe, 0x27, 0x27, 0x59, 0x20, 0xcc, 0x60, 0xe0, 0xe5, 0x33, 0x76,
-	0xc9, 0x9d, 0xed, 0x60, 0xe3, 0x25, 0x98, 0x04, 0xd6, 0x12, 0x87, 0xd7, 0xa4, 0x08, 0xa2, 0x3a,
-	0x13, 0x9d, 0xd0, 0xd3, 0x9a, 0x2c, 0xbc, 0xbf, 0xdc, 0xf0, 0x1a, 0x27, 0x75, 0xa6, 0xd4, 0x23,
-	0x5d, 0x00, 0xaa, 0x6b, 0x67, 0xa5, 0x22, 0x2e, 0x80, 0x14, 0x48, 0xe3, 0xbe, 0x81, 0xf3, 0xb4,
-	0x7e, 0x3d, 0x58, 0xc9, 0xc8, 0x73, 0xe8, 0x16, 0xc3, 0x34, 0xf6, 0x67, 0x10, 0x0c, 0x4c, 0x52,
-	0x25, 0x8f, 0x10, 0xec, 0xb9, 0x71, 0x01, 0xb1, 0x6f, 0x74, 0x0a, 0xfb, 0x5f, 0x2d, 0x67, 0x8d,
-	0x59, 0xf1, 0xd4, 0x74, 0x3e, 0x90, 0x04, 0x68, 0x26, 0xa2, 0x29, 0xdf, 0x5b, 0x10, 0x06, 0xff,
-	0x83, 0x4f, 0x7a, 0x0b, 0xcd, 0x41, 0x2a, 0x32, 0xce, 0x50, 0x4d, 0x64, 0xb8, 0xfe, 0xa7, 0x0a,
-	0x8d, 0xe4, 0xa5, 0x46, 0x02, 0x34, 0x26, 0xda, 0x47, 0x6d, 0xf4, 0x9b, 0x36, 0x35, 0x4c, 0x65,
-	0x2c, 0x54, 0x10, 0xc0, 0x41, 0x6f, 0xa4, 0xdd, 0xa9, 0x03, 0xa1, 0x8a, 0x9a, 0x00, 0x86, 0x32,
-	0x50, 0x35, 0xc3, 0x94, 0x87, 0x43, 0x61, 0x87, 0x7a, 0xab, 0x9a, 0x6a, 0x4e, 0x7b, 0xc3, 0x89,
-	0x61, 0x2a, 0xba, 0xb0, 0x8b, 0x5a, 0x70, 0x6c, 0xdc, 0x4f, 0xcc, 0x3e, 0x25, 0x08, 0xad, 0x86,
-	0xb0, 0x87, 0x10, 0x34, 0x7b, 0x23, 0xed, 0x51, 0xd1, 0xcd, 0xe9, 0x83, 0xcc, 0x5c, 0xf7, 0x69,
-	0xb0, 0x61, 0xca, 0xba, 0x39, 0x95, 0x07, 0x8a, 0x66, 0x1a, 0xc2, 0x01, 0xa3, 0xbf, 0x97, 0x75,
-	0x65, 0x3a, 0x52, 0xfb, 0x86, 0x70, 0x48, 0xc9, 0xa2, 0xa8, 0xb1, 0xae, 0x3e, 0xc8, 0xba, 0xaa,
-	0x18, 0xc2, 0x11, 0x12, 0xa1, 0xfd, 0x28, 0x0f, 0xd5, 0xbe, 0x6c, 0x2a, 0x53, 0xce, 0x10, 0xe5,
-	0xaf, 0xd1, 0x10, 0x5d, 0xe1, 0xf3, 0x9d, 0xe8, 0xca, 0x74, 0x3c, 0xd2, 0x4d, 0x43, 0x80, 0x6b,
-	0x13, 0x20, 0x21, 0x6d, 0x08, 0x9a, 0xf1, 0x22, 0x65, 0x73, 0x62, 0x08, 0x15, 0x54, 0x87, 0xc3,
-	0xb1, 0xa2, 0xf5, 0x55, 0x8d, 0xae, 0xb3, 0x0e, 0x87, 0xfa, 0x44, 0xd3, 0xe8, 0x60, 0x07, 0x35,
-	0xe0, 0xa8, 0x37, 0x7a, 0x18, 0x0f, 0x15, 0x53, 0x11, 0x76, 0xe9, 0x76, 0xdc, 0xc9, 0xea, 0x50,
-	0xe9, 0x0b, 0x7b, 0xb7, 0x7f, 0xd7, 0xe1, 0xa8, 0xe7, 0xd8, 0xa6, 0x77, 0xbf, 0x9e, 0xa1, 0x6b,
-	0xd8, 0xa3, 0x9d, 0x03, 0x09, 0xec, 0x1e, 0x26, 0x7a, 0x8a, 0xd8, 0x4c, 0x58, 0xe8, 0x21, 0x56,
-	0x90, 0x02, 0xaf, 0x52, 0xe2, 0x8e, 0xba, 0xa1, 0x6a, 0xe6, 0x1b, 0x81, 0x78, 0x56, 0x04, 0x71,
-	0x1a, 0x0d, 0x84, 0x6c, 0x13, 0x42, 0x17, 0x09, 0xf7, 0x5c, 0xdb, 0x12, 0xc5, 0x12, 0x94, 0xf3,
-	0xfd, 0x02, 0xf5, 0x84, 0x18, 0x23, 0x9e, 0x39, 0x2f, 0xda, 0x62, 0x2b, 0x0f, 0x70, 0x82, 0x8f,
-	0xf0, 0x3a, 0xa3, 0xbd, 0xe8, 0x3c, 0xf6, 0xcd, 0x69, 0xb5, 0xd8, 0x2d, 0x06, 0x37, 0xab, 0xcb,
-	0xea, 0x5f, 0xb8, 0xba, 0x12, 0xc5, 0x0c, 0x57, 0x57, 0x28, 0x9a, 0x52, 0x05, 0xfd, 0x0a, 0x8d,
-	0xa4, 0xd4, 0xa1, 0x4e, 0xec, 0x9d, 0x16, 0x45, 0xb1, 0x5d, 0x80, 0x70, 0x8e, 0x7b, 0x68, 0xa6,
-	0xe5, 0x0c, 0x25, 0x72, 0x66, 0xc5, 0x4f, 0xec, 0x14, 0x62, 0x9c, 0xc9, 0x04, 0x94, 0x17, 0x1c,
-	0x74, 0xc9, 0x4b, 0xa5, 0x4c, 0xa6, 0xc4, 0x8b, 0x52, 0x9c, 0xb3, 0xce, 0xe3, 0x3e, 0x96, 0xd1,
-	0x40, 0xf4, 0x5d, 0x32, 0xb4, 0x44, 0x43, 0xc5, 0x6f, 0xb7, 0x3b, 0xf1, 0x24, 0xbf, 0xc3, 0x69,
-	0x91, 0xe2, 0xa1, 0xab, 0xe4, 0x1b, 0xa1, 0x48, 0x2b, 0xc5, 0xcb, 0x2d, 0x1e, 0xd9, 0x6d, 0x49,
-	0x34, 0xe2, 0xf4, 0xb6, 0xe4, 0xdb, 0x77, 0x7a, 0x5b, 0x72, 0x1d, 0x9c, 0x95, 0x52, 0xf6, 0x1d,
-	0x19, 0x96, 0x52, 0xc9, 0xcb, 0x33, 0x2c, 0xa5, 0xe2, 0xc7, 0x67, 0x05, 0x79, 0x9b, 0x96, 0x50,
-	0xf4, 0xb0, 0x44, 0xef, 0x92, 0xc1, 0x5b, 0x1e, 0xa8, 0xe2, 0x9b, 0xff, 0x76, 0xdc, 0x9c, 0x6b,
-	0xc9, 0x1b, 0x3a, 0x3c, 0xd7, 0xed, 0x6f, 0xf0, 0xf0, 0x5c, 0xb7, 0x3e, 0xc3, 0x93, 0x49, 0xb2,
-	0x4f, 0xfd, 0x74, 0x92, 0x92, 0x5f, 0x85, 0x74, 0x92, 0xe2, 0xbf, 0x85, 0x0a, 0xfa, 0x09, 0x6a,
-	0x9b, 0x9e, 0x86, 0xb8, 0x90, 0x64, 0x3b, 0xa4, 0x78, 0x92, 0x35, 0x6f, 0x42, 0x07, 0x99, 0xd0,
-	0x41, 0x71, 0xe8, 0x20, 0x13, 0x3a, 0x3b, 0x60, 0xff, 0x3c, 0x1f, 0xfe, 0x0d, 0x00, 0x00, 0xff,
-	0xff, 0x63, 0x94, 0x3a, 0xd5, 0x07, 0x0d, 0x00, 0x00,
+	// 2710 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x1a, 0x4d, 0x6f, 0xe3, 0xc6,
+	0x35, 0xd4, 0x87, 0x2d, 0x3d, 0xd9, 0x5a, 0x7a, 0xbc, 0xb6, 0x65, 0xc6, 0xd8, 0x38, 0x4c, 0xb2,
+	0xeb, 0xb8, 0x6d, 0x9a, 0x6c, 0x80, 0xa0, 0x2d, 0xd2, 0xb4, 0x5a, 0x49, 0xb6, 0xd5, 0xd8, 0xb2,
+	0x30, 0x94, 0x37, 0x48, 0x90, 0xc2, 0xa0, 0xc4, 0x59, 0x9b, 0x59, 0x8a, 0x54, 0xc8, 0x51, 0x5a,
+	0xdf, 0x7b, 0x2f, 0x90, 0x73, 0x4f, 0xed, 0xb9, 0x40, 0x81, 0xde, 0xdb, 0x7f, 0xd2, 0x53, 0x7f,
+	0x48, 0x31, 0x1f, 0x24, 0x87, 0xd4, 0x87, 0xd5, 0xc0, 0xb9, 0xe9, 0x7d, 0xbf, 0x79, 0xf3, 0xe6,
+	0xcd, 0x9b, 0x47, 0x81, 0x3e, 0xf2, 0xdc, 0x6b, 0x1a, 0x5c, 0xdf, 0x4e, 0x87, 0x1f, 0x4c, 0xc2,
+	0x80, 0x06, 0xa8, 0xe8, 0x3a, 0x9e, 0xf9, 0x0a, 0x9e, 0x5c, 0x4d, 0x6e, 0x42, 0xdb, 0x21, 0x98,
+	0x8c, 0x02, 0xff, 0x95, 0x7b, 0x33, 0x0d, 0x49, 0x3f, 0x08, 0x69, 0x84, 0xc9, 0xb7, 0x53, 0x12,
+	0x51, 0xf4, 0x53, 0xd8, 0x8a, 0x5e, 0xbb, 0x93, 0x7e, 0x48, 0x42, 0xf2, 0xed, 0xd4, 0x8d, 0x5c,
+	0x4a, 0xa2, 0x86, 0x76, 0xa8, 0x1d, 0x55, 0xf0, 0x2c, 0x01, 0xed, 0xc2, 0x9a, 0x13, 0xde, 0xe1,
+	0xa9, 0xdf, 0x28, 0x70, 0x16, 0x09, 0x99, 0x2d, 0x38, 0x58, 0x68, 0x67, 0xe2, 0xdd, 0xa1, 0x77,
+	0xa0, 0x34, 0xf1, 0x6c, 0x9f, 0x2b, 0xae, 0x3d, 0x7f, 0xf4, 0x81, 0xeb, 0x78, 0x1f, 0xb4, 0xb9,
+	0x68, 0xdf, 0xb3, 0x7d, 0xcc, 0x89, 0xa6, 0xa3, 0x28, 0x19, 0x4e, 0x5d, 0xcf, 0xb9, 0x70, 0xc3,
+	0x30, 0x08, 0x1f, 0xd8, 0xd5, 0x26, 0x18, 0x0b, 0xac, 0xfc, 0x70, 0x47, 0x2d, 0x6a, 0xfb, 0xce,
+	0xf0, 0xee, 0x47, 0x76, 0x34, 0xb1, 0xb2, 0xb2, 0xa3, 0xe9, 0xf6, 0xb7, 0x02, 0xff, 0x3b, 0x12,
+	0xd2, 0x7e, 0xe8, 0x8e, 0xed, 0xd0, 0x25, 0x3f, 0xda, 0xf6, 0xcf, 0xda, 0x59, 0xd9, 0xd9, 0x6b,
+	0xd8, 0x93, 0x4a, 0xac, 0x5b, 0x3b, 0x24, 0x97, 0xae, 0xf3, 0xc0, 0x5e, 0x7e, 0x0a, 0x3b, 0xb3,
+	0x06, 0x56, 0x76, 0xef, 0x1b, 0x30, 0xa5, 0xf4, 0x4b, 0xdb, 0x73, 0x1d, 0x9b, 0x12, 0x8b, 0xda,
+	0x21, 0x6d, 0x79, 0xd3, 0x88, 0x92, 0xf0, 0x61, 0x3d, 0x3d, 0x85, 0xc3, 0xa5, 0xb6, 0x56, 0x76,
+	0x7a, 0x13, 0x6a, 0x7d, 0xd7, 0xbf, 0x91, 0xde, 0x99, 0x35, 0xa8, 0x0a, 0x70, 0xe2, 0xdd, 0x99,
+	0xfb, 0xb0, 0x67, 0x51, 0x9b, 0x4e, 0x23, 0xb1, 0x67, 0x91, 0x1b, 0xf8, 0x31, 0x9f, 0x03, 0x3b,
+	0xb3, 0x24, 0x66, 0xb4, 0x03, 0x68, 0x94, 0xa0, 0x04, 0x0b, 0x89, 0x1a, 0x85, 0xc3, 0xe2, 0x51,
+	0xed, 0xf9, 0x0e, 0x77, 0xa1, 0x95, 0x23, 0xe3, 0x39, 0x02, 0xbf, 0x2b, 0x55, 0x34, 0xbd, 0x60,
+	0xee, 0xc0, 0xb6, 0xc0, 0x34, 0x6f, 0x88, 0x9f, 0x54, 0x24, 0xf3, 0xd7, 0xb0, 0x95, 0x45, 0x33,
+	0xc3, 0x47, 0xb0, 0x66, 0x73, 0xb0, 0xa1, 0x71, 0x63, 0x3a, 0x37, 0xc6, 0x39, 0xa4, 0x1d, 0x49,
+	0x37, 0xff, 0xae, 0x41, 0x4d, 0xc1, 0x23, 0x03, 0x2a, 0xb7, 0x41, 0x44, 0x7d, 0x7b, 0x4c, 0x78,
+	0xac, 0xaa, 0x38, 0x81, 0x51, 0x03, 0xd6, 0x6f, 0x89, 0xed, 0xd1, 0xdb, 0x3b, 0xb9, 0x01, 0x31,
+	0x88, 0x1e, 0x43, 0x39, 0xa2, 0x36, 0x25, 0x8d, 0x22, 0x17, 0x11, 0x00, 0xd3, 0xf5, 0xca, 0x76,
+	0xbd, 0x69, 0x48, 0xa2, 0x46, 0xe9, 0x50, 0x3b, 0x2a, 0xe3, 0x04, 0x46, 0x07, 0x50, 0xf5, 0xec,
+	0x88, 0x76, 0x58, 0x31, 0x69, 0x94, 0xb9, 0x54, 0x8a, 0x60, 0x96, 0x42, 0x42, 0xc3, 0xbb, 0x26,
+	0x6d, 0xac, 0x1d, 0x6a, 0x47, 0x45, 0x1c, 0x83, 0xe6, 0xbf, 0x0b, 0xa0, 0xe7, 0x83, 0x86, 0x10,
+	0x94, 0x9c, 0xa1, 0xeb, 0x70, 0x87, 0xcb, 0x98, 0xff, 0x66, 0x2a, 0x46, 0x81, 0x4f, 0x89, 0x4f,
+	0xb9, 0xb3, 0x65, 0x1c, 0x83, 0xe8, 0x5d, 0x28, 0x85, 0x81, 0x27, 0x7c, 0xad, 0xcb, 0xd0, 0x58,
+	0xe4, 0x66, 0x4c, 0x7c, 0x8a, 0x03, 0x8f, 0x60, 0x4e, 0xcd, 0x04, 0xa2, 0x94, 0x0b, 0xc4, 0x33,
+	0x58, 0x8b, 0xb8, 0x65, 0xee, 0x79, 0x5d, 0xa6, 0x93, 0x45, 0xc9, 0x24, 0x8e, 0xae, 0x20, 0xb3,
+	0x55, 0x46, 0x2c, 0x15, 0x07, 0xee, 0x98, 0xc8, 0x95, 0xa4, 0x08, 0xe6, 0x22, 0xf1, 0x1d, 0x4e,
+	0x5b, 0x17, 0xab, 0x94, 0x20, 0x32, 0x61, 0x83, 0xb0, 0x40, 0x5c, 0x90, 0x28, 0xb2, 0x6f, 0x48,
+	0xa3, 0xc2, 0x1d, 0xc8, 0xe0, 0x58, 0xcc, 0x27, 0xb7, 0x76, 0x44, 0x1a, 0x55, 0x11, 0x73, 0x0e,
+	0xa0, 0x43, 0xa8, 0x4d, 0x48, 0x38, 0x22, 0x3e, 0x6d, 0x07, 0x3e, 0x69, 0x00, 0x5f, 0xba, 0x8a,
+	0x32, 0x77, 0xe1, 0xb1, 0xf0, 0x32, 0x29, 0x97, 0x22, 0x91, 0xfe, 0xa5, 0x01, 0xca, 0x11, 0x58,
+	0x2a, 0x0d, 0x60, 0xdf, 0x73, 0x23, 0x7a, 0xf9, 0x4a, 0x62, 0xd3, 0x55, 0x92, 0x38, 0xbb, 0x76,
+	0xf9, 0xf2, 0x67, 0xe8, 0x78, 0xb1, 0x20, 0x7a, 0x0a, 0x75, 0xe2, 0xd9, 0x93, 0x88, 0x38, 0x16,
+	0xbb, 0x01, 0x9d, 0x88, 0x6f, 0x52, 0x11, 0xe7, 0xb0, 0xe8, 0x18, 0xf4, 0x90, 0x8c, 0x6d, 0xd7,
+	0x77, 0xfd, 0x9b, 0x98, 0xb3, 0xc8, 0x39, 0x67, 0xf0, 0xec, 0x80, 0x7c, 0x61, 0xd3, 0xd1, 0x6d,
+	0x6e, 0x5d, 0x9f, 0xc3, 0x56, 0x16, 0xcd, 0x56, 0xf5, 0x09, 0x40, 0x94, 0xf8, 0x23, 0x8b, 0xc2,
+	0xa2, 0x65, 0x28, 0x9c, 0xe6, 0xf7, 0x05, 0xd8, 0x9a, 0xe1, 0x40, 0xef, 0x41, 0x89, 0xf1, 0x70,
+	0x3d, 0xf5, 0xe7, 0x5b, 0x79, 0x3d, 0x11, 0xe6, 0x64, 0x25, 0x6d, 0x0a, 0xcb, 0xd3, 0xe6, 0x7d,
+	0x28, 0xb3, 0x5c, 0x63, 0x4b, 0x65, 0xf1, 0xdd, 0xe6, 0x7c, 0x67, 0x41, 0x44, 0x15, 0x5e, 0xc1,
+	0x81, 0x7e, 0x06, 0xeb, 0xb7, 0x6e, 0x44, 0x83, 0xf0, 0xae, 0x51, 0x52, 0x98, 0x19, 0xe3, 0x20,
+	0xb4, 0xfd, 0xc8, 0xa5, 0xac, 0x1a, 0xc5, 0x3c, 0xe8, 0x18, 0xd6, 0xe5, 0x11, 0xe4, 0xa9, 0x1b,
+	0x57, 0x06, 0xc6, 0x7e, 0x22, 0xf0, 0x38, 0x66, 0x60, 0xee, 0x52, 0x77, 0xec, 0xfa, 0x37, 0x8d,
+	0x35, 0xa5, 0x68, 0x72, 0xcd, 0x1c, 0x8d, 0x25, 0xd9, 0xfc, 0x8f, 0x06, 0x90, 0xa2, 0xb3, 0x49,
+	0xaf, 0x2d, 0x49, 0xfa, 0x42, 0x36, 0xe9, 0x67, 0x73, 0xa2, 0xb8, 0x72, 0x4e, 0x94, 0xe6, 0xe7,
+	0x04, 0x3b, 0x48, 0xd3, 0x68, 0x6a, 0x7b, 0x31, 0x5f, 0x99, 0xf3, 0x65, 0x70, 0xec, 0xc8, 0x10,
+	0x3b, 0xf4, 0x5c, 0x12, 0xe2, 0xa9, 0x1f, 0xf1, 0xc5, 0x96, 0xb1, 0x8a, 0x32, 0x23, 0xa8, 0x29,
+	0x11, 0x62, 0x27, 0x8f, 0x9f, 0x44, 0x59, 0x20, 0x05, 0x20, 0x0a, 0xce, 0x78, 0x6c, 0xfb, 0x0e,
+	0x5f, 0x58, 0x15, 0xc7, 0x20, 0x2b, 0x25, 0xe4, 0x8f, 0x2e, 0x6d, 0x05, 0x8e, 0x28, 0x3a, 0x65,
+	0x9c, 0xc0, 0x4c, 0xca, 0x0b, 0x6e, 0x4e, 0x5c, 0x2f, 0xae, 0x32, 0x31, 0x68, 0xfe, 0x4d, 0x83,
+	0x7a, 0x76, 0x1b, 0x95, 0x04, 0xd2, 0xee, 0xad, 0x3b, 0xd4, 0x1d, 0x93, 0x88, 0xda, 0xe3, 0x89,
+	0x0c, 0x73, 0x8a, 0xc8, 0x94, 0xb6, 0x62, 0xae, 0xb4, 0x25, 0x6b, 0x2b, 0xe5, 0xd6, 0x66, 0x53,
+	0x4a, 0xc6, 0x13, 0xca, 0x23, 0x58, 0xc6, 0x31, 0x68, 0xbe, 0x86, 0x7a, 0x36, 0x31, 0x97, 0xde,
+	0x20, 0x2b, 0x9f, 0x80, 0xc4, 0x8d, 0xa2, 0xe2, 0x86, 0xf9, 0x18, 0x50, 0xeb, 0x96, 0x8c, 0x5e,
+	0xb7, 0x78, 0xcf, 0x1c, 0x1f, 0xf0, 0x4f, 0x40, 0xcf, 0x60, 0xd9, 0xf9, 0x36, 0x61, 0x43, 0x80,
+	0xca, 0x09, 0xaf, 0xe2, 0x0c, 0xce, 0x3c, 0x81, 0x5d, 0x2e, 0x67, 0x91, 0x1b, 0xd7, 0x8f, 0xa8,
+	0xed, 0x79, 0x3f, 0xa8, 0x2d, 0x61, 0x05, 0x75, 0x46, 0x0f, 0xeb, 0x18, 0x6c, 0xd8, 0xef, 0x87,
+	0x64, 0x62, 0x87, 0xa2, 0x1d, 0xc9, 0x5c, 0xdb, 0x0f, 0xd4, 0xf9, 0x7c, 0x06, 0x7b, 0xf3, 0x4c,
+	0xac, 0xdc, 0xf0, 0x7c, 0x0d, 0xd0, 0x0a, 0xa6, 0x3e, 0xed, 0x93, 0xb0, 0x3d, 0x64, 0x56, 0xda,
+	0xc3, 0x5e, 0xba, 0x6f, 0x12, 0x62, 0xbb, 0xdf, 0x0c, 0x38, 0x5f, 0x7c, 0x95, 0x4a, 0x90, 0xe5,
+	0xd9, 0x19, 0xb1, 0x27, 0x82, 0x26, 0x52, 0x3b, 0x45, 0xb0, 0x96, 0x89, 0x07, 0xe6, 0x72, 0xf8,
+	0x0d, 0x19, 0x51, 0x8e, 0x8b, 0xf7, 0xec, 0x1c, 0x76, 0x66, 0x49, 0xcc, 0xed, 0x8f, 0x61, 0xe3,
+	0x9c, 0xdf, 0x1a, 0x1c, 0x17, 0xdf, 0x30, 0x8f, 0x64, 0xb3, 0x14, 0xbb, 0x8a, 0x33, 0x4c, 0xac,
+	0xf2, 0x8b, 0x0c, 0xb0, 0xa9, 0xed, 0x05, 0x49, 0x62, 0x7c, 0x0d, 0x5b, 0x59, 0x34, 0x33, 0x70,
+	0x00, 0xd5, 0xb6, 0x4d, 0xed, 0xa1, 0x1d, 0xdf, 0x5f, 0x55, 0x9c, 0x22, 0xd0, 0xfb, 0xb0, 0xd6,
+	0x8d, 0xa2, 0x69, 0xd2, 0xa5, 0x89, 0x5a, 0x2e, 0x15, 0x70, 0x0a, 0x96, 0x0c, 0xe6, 0xf7, 0x1a,
+	0x6c, 0xa8, 0x04, 0x96, 0xb3, 0xdc, 0x5c, 0x5c, 0x16, 0x38, 0xc0, 0x8e, 0x43, 0xac, 0x5e, 0xd6,
+	0x85, 0x04, 0x66, 0x01, 0x17, 0x01, 0x90, 0x69, 0x2e, 0x21, 0xbe, 0x11, 0x84, 0xda, 0xae, 0x27,
+	0x4f, 0xa1, 0x84, 0x58, 0xa5, 0xc2, 0x64, 0x4c, 0x1c, 0xd7, 0x66, 0xe5, 0x40, 0xb6, 0x4d, 0x2a,
+	0xca, 0xdc, 0x96, 0x4b, 0x56, 0x1f, 0xad, 0xe6, 0x67, 0xf0, 0x48, 0x45, 0xb2, 0x28, 0xfc, 0x04,
+	0xca, 0x1c, 0x6a, 0x68, 0x4a, 0x33, 0x3a, 0xb0, 0xc3, 0x1b, 0x42, 0x19, 0x3e, 0xbe, 0x63, 0x38,
+	0x8f, 0xf9, 0x2d, 0xe8, 0x79, 0x12, 0x5b, 0xd6, 0x59, 0xee, 0x94, 0x9f, 0x29, 0x7d, 0x62, 0x2b,
+	0xdb, 0x7a, 0x49, 0x90, 0x9d, 0x7f, 0x79, 0x20, 0x8b, 0x4a, 0x5a, 0x2a, 0x16, 0x25, 0xd9, 0x1c,
+	0x02, 0x28, 0xc6, 0x10, 0x94, 0x18, 0x14, 0xf7, 0x77, 0xec, 0x37, 0x8b, 0x76, 0xd7, 0xbf, 0x92,
+	0x41, 0xad, 0x60, 0x01, 0x20, 0x1d, 0x8a, 0x7d, 0xd7, 0x91, 0xa9, 0xc8, 0x7e, 0x32, 0x67, 0xfa,
+	0x61, 0x30, 0x22, 0x51, 0x14, 0x17, 0x58, 0x09, 0x26, 0x59, 0xf3, 0x32, 0xdb, 0xcd, 0x77, 0x60,
+	0x2b, 0x8b, 0x66, 0xf1, 0xfa, 0x10, 0xb6, 0xbb, 0x91, 0xc4, 0xb4, 0x82, 0xf1, 0xc4, 0xa6, 0xee,
+	0xd0, 0x23, 0xf2, 0xc0, 0xce, 0x23, 0x99, 0x7b, 0x32, 0xc3, 0xdb, 0x6e, 0xf4, 0xda, 0x9a, 0xd8,
+	0xa3, 0xa4, 0x1f, 0xf9, 0xa7, 0x06, 0xdb, 0x79, 0x0a, 0x33, 0xf1, 0x2b, 0x51, 0x49, 0x19, 0xf6,
+	0x8a, 0x35, 0x78, 0x71, 0x0a, 0xa2, 0xe4, 0xf6, 0x4f, 0x48, 0x38, 0xc7, 0x89, 0x3e, 0x82, 0x8a,
+	0xec, 0x60, 0xe3, 0x9e, 0x61, 0x47, 0x6d, 0x6b, 0x53, 0x4b, 0x09, 0x1b, 0x8b, 0xe9, 0x05, 0xbb,
+	0x90, 0x44, 0x50, 0xf8, 0x6f, 0xf1, 0xd0, 0xc0, 0xdb, 0x92, 0x87, 0xdd, 0x42, 0xd6, 0x5d, 0xc4,
+	0x4d, 0x98, 0x6d, 0xd8, 0x50, 0x61, 0xf4, 0x04, 0x80, 0xc1, 0xd1, 0x5d, 0x44, 0xc9, 0x58, 0x66,
+	0x80, 0x82, 0x61, 0xdb, 0xc3, 0x19, 0xf9, 0xf6, 0x68, 0x58, 0x00, 0xe6, 0x9f, 0x34, 0xd8, 0xcc,
+	0xb8, 0xbe, 0x34, 0x8f, 0x9a, 0x80, 0xc4, 0x31, 0x57, 0x2d, 0x67, 0x0e, 0xa6, 0x4a, 0xc0, 0x73,
+	0x98, 0x99, 0x1b, 0x1d, 0xf5, 0x1e, 0xe1, 0x80, 0xf9, 0xdf, 0x02, 0xe8, 0xf9, 0xd0, 0xfc, 0xc0,
+	0x8c, 0x46, 0x50, 0xc2, 0xf1, 0x63, 0xa2, 0x8a, 0xf9, 0x6f, 0xc6, 0xcd, 0x8e, 0x78, 0xdb, 0x8d,
+	0x6f, 0xd1, 0x18, 0x44, 0xef, 0xc2, 0xa6, 0x38, 0x49, 0x31, 0x5d, 0x1c, 0xe1, 0x2c, 0x92, 0x5d,
+	0x5e, 0xf2, 0xe7, 0x8b, 0x3b, 0x4a, 0x44, 0x47, 0x52, 0xc2, 0x19, 0x5c, 0x2e, 0xfe, 0xeb, 0x33,
+	0xf1, 0x3f, 0x80, 0xea, 0x49, 0x48, 0x88, 0x50, 0x50, 0xe1, 0x0a, 0x52, 0x04, 0x7a, 0x0a, 0xa5,
+	0x56, 0x30, 0xb9, 0xe3, 0x4f, 0x87, 0x38, 0xc3, 0x92, 0x48, 0xf4, 0x08, 0x71, 0x30, 0xa7, 0x33,
+	0xbe, 0x73, 0xd7, 0x7f, 0xdd, 0x80, 0xc5, 0x7c, 0x8c, 0x9e, 0x86, 0xb9, 0xa6, 0x86, 0x79, 0x04,
+	0x9b, 0x19, 0x66, 0xc6, 0x26, 0x1c, 0xd2, 0xb8, 0x43, 0x02, 0x40, 0x47, 0xf0, 0x28, 0x75, 0x5c,
+	0xd0, 0x0b, 0x9c, 0x9e, 0x47, 0xb3, 0x60, 0x9f, 0xb8, 0x54, 0x14, 0x8f, 0x0a, 0xe6, 0xbf, 0xd9,
+	0xd0, 0x26, 0xbe, 0x02, 0x6f, 0xa7, 0xd4, 0x09, 0xfe, 0xe0, 0xcb, 0x77, 0xff, 0xc3, 0x0f, 0x6d,
+	0x16, 0xda, 0x59, 0xf9, 0xbe, 0x4d, 0x5b, 0x82, 0xae, 0xef, 0xfe, 0x38, 0xc3, 0x90, 0xb4, 0x25,
+	0xc8, 0x98, 0x58, 0xd9, 0xc5, 0x11, 0xbc, 0x99, 0x1d, 0x4e, 0x5d, 0xd8, 0x0f, 0xef, 0xe4, 0x6f,
+	0x61, 0x7f, 0xbe, 0x91, 0x95, 0xdd, 0xfc, 0x94, 0x9d, 0x60, 0x9a, 0x69, 0x04, 0x59, 0x7a, 0x28,
+	0xa7, 0xb7, 0x14, 0xf7, 0xb3, 0xdf, 0xd9, 0xde, 0x34, 0xbe, 0x7b, 0x05, 0x60, 0xea, 0x50, 0x57,
+	0xa4, 0x59, 0xb3, 0xf6, 0x14, 0xf4, 0xd3, 0x15, 0xf4, 0x99, 0x4f, 0xa1, 0x7e, 0x9a, 0x91, 0x4c,
+	0x2d, 0x68, 0xaa, 0x85, 0x77, 0x01, 0xf0, 0x34, 0xbe, 0x53, 0x94, 0x38, 0x68, 0x99, 0x38, 0x38,
+	0x50, 0xe1, 0x5c, 0x4c, 0xcf, 0x47, 0x00, 0xec, 0xe5, 0x45, 0x1c, 0x6b, 0xe9, 0x53, 0x52, 0x61,
+	0x42, 0xef, 0x41, 0x99, 0x05, 0x23, 0xbe, 0x29, 0x66, 0x42, 0x25, 0xa8, 0xe6, 0x33, 0xd8, 0xc4,
+	0x24, 0x9a, 0x8e, 0xc9, 0x7d, 0xee, 0xfc, 0x45, 0x83, 0x5a, 0xcc, 0x29, 0x9a, 0xb1, 0x5a, 0xc8,
+	0xc1, 0x7b, 0x7c, 0x52, 0xb9, 0x72, 0xeb, 0x28, 0xfc, 0x5f, 0xeb, 0x28, 0xde, 0xbf, 0x0e, 0x96,
+	0x2d, 0xf7, 0xad, 0xe3, 0x39, 0xd4, 0x62, 0xc6, 0x95, 0x13, 0xea, 0x13, 0xd8, 0x6c, 0xd9, 0xfe,
+	0x88, 0x24, 0x8f, 0x80, 0xd5, 0x1e, 0xf5, 0xe6, 0x0b, 0xa8, 0xc5, 0x72, 0x22, 0x64, 0xd5, 0x89,
+	0xe8, 0x2f, 0x48, 0xb6, 0xb9, 0x12, 0x4c, 0x1e, 0x71, 0x64, 0xfb, 0x81, 0x53, 0x3e, 0xf3, 0x2b,
+	0xd0, 0xf3, 0xe4, 0xfb, 0x06, 0x71, 0x0b, 0x66, 0x5b, 0x3a, 0x14, 0x27, 0x69, 0xff, 0x33, 0x71,
+	0x1d, 0xf3, 0xaf, 0x1a, 0x40, 0xba, 0x58, 0xf4, 0x73, 0xa8, 0xc8, 0x67, 0x69, 0xec, 0x9e, 0x18,
+	0x18, 0x30, 0xa2, 0x4f, 0x9c, 0x96, 0xa0, 0xe1, 0x84, 0x09, 0x3d, 0x85, 0xf2, 0x2b, 0x56, 0x86,
+	0x65, 0x8e, 0xe9, 0x2a, 0x37, 0xab, 0xcf, 0x58, 0x90, 0xd9, 0x31, 0xf0, 0x03, 0x4a, 0xc4, 0x1e,
+	0x56, 0xb1, 0x00, 0x92, 0x20, 0x96, 0x96, 0x07, 0xf1, 0x04, 0xea, 0x59, 0x07, 0xee, 0x5f, 0xfe,
+	0xbc, 0x97, 0xb6, 0xf9, 0x25, 0xd4, 0x14, 0xd7, 0x96, 0x2a, 0x41, 0x50, 0x9a, 0xd8, 0xf4, 0x56,
+	0x6a, 0xe0, 0xbf, 0x19, 0xbf, 0x0c, 0x64, 0x14, 0x3f, 0x8c, 0x63, 0xf8, 0xf8, 0x17, 0x50, 0x53,
+	0x86, 0x84, 0x48, 0x87, 0x8d, 0xab, 0xde, 0xe7, 0xbd, 0xcb, 0x2f, 0x7a, 0xd7, 0xf8, 0xf2, 0xbc,
+	0xa3, 0xbf, 0x81, 0x00, 0xd6, 0x2e, 0x9a, 0xd6, 0xa0, 0x83, 0x75, 0x0d, 0xd5, 0x60, 0xbd, 0x8f,
+	0xbb, 0x17, 0x4d, 0xfc, 0xa5, 0x5e, 0x38, 0xfe, 0x73, 0x01, 0x36, 0xd4, 0x35, 0xab, 0xb2, 0xd6,
+	0xa0, 0xd3, 0x17, 0xb2, 0xad, 0xcb, 0xde, 0x49, 0xf7, 0x54, 0xd7, 0x50, 0x1d, 0xc0, 0xea, 0x9c,
+	0x76, 0x7b, 0xd6, 0xa0, 0x79, 0x7e, 0xae, 0x17, 0x18, 0x77, 0xb7, 0xd7, 0x1d, 0x5c, 0xb7, 0xce,
+	0xaf, 0xb8, 0xf6, 0x22, 0xda, 0x81, 0x2d, 0xeb, 0xec, 0x6a, 0xd0, 0x66, 0x0a, 0x24, 0xd6, 0xd2,
+	0x4b, 0x08, 0x41, 0xbd, 0x75, 0xd9, 0x7b, 0xd9, 0xc1, 0x83, 0x6b, 0xe9, 0x48, 0x99, 0x09, 0x5b,
+	0x83, 0x26, 0x1e, 0x5c, 0x37, 0x4f, 0x3b, 0xbd, 0x81, 0xa5, 0xaf, 0x71, 0xf5, 0x67, 0x4d, 0xdc,
+	0xb9, 0xbe, 0xec, 0xb6, 0x2d, 0x7d, 0x9d, 0x29, 0x8b, 0xa5, 0x84, 0xcb, 0xdd, 0x8e, 0xa5, 0x57,
+	0x90, 0x01, 0xbb, 0x2f, 0x9b, 0xe7, 0xdd, 0x76, 0x73, 0xd0, 0xb9, 0x16, 0x1a, 0x62, 0xfb, 0x55,
+	0x26, 0x82, 0x3b, 0xc2, 0xdf, 0x2b, 0xdc, 0xb9, 0xee, 0x5f, 0xe2, 0x81, 0xa5, 0x03, 0xda, 0x86,
+	0x47, 0xb8, 0xf3, 0xe2, 0xaa, 0x7b, 0xde, 0xbe, 0xbe, 0xe8, 0x62, 0x7c, 0x89, 0x2d, 0xbd, 0xa6,
+	0x22, 0xad, 0x41, 0xb3, 0xd7, 0x7e, 0xf1, 0xa5, 0xbe, 0x71, 0x3c, 0x10, 0xf3, 0xa2, 0xa4, 0xbb,
+	0xaf, 0xa7, 0xe1, 0x68, 0x0e, 0xae, 0x2c, 0xfd, 0x0d, 0x1e, 0xc0, 0x4e, 0xaf, 0xdd, 0xed, 0x9d,
+	0x8a, 0x68, 0xe2, 0xab, 0x5e, 0x8f, 0x01, 0x05, 0xb4, 0x01, 0x95, 0xd6, 0xe5, 0x45, 0xff, 0xbc,
+	0x33, 0xe8, 0xe8, 0x45, 0x16, 0xb8, 0x93, 0x66, 0xf7, 0xbc, 0xd3, 0xd6, 0x4b, 0xcf, 0xff, 0xa1,
+	0x43, 0xa5, 0xe5, 0xb9, 0x83, 0xe0, 0x6c, 0x3a, 0x44, 0xc7, 0x50, 0x62, 0xb3, 0x7b, 0x24, 0xf3,
+	0x35, 0x9d, 0xea, 0x1b, 0x75, 0x05, 0xc3, 0x2a, 0xff, 0x1b, 0xa8, 0x03, 0x9b, 0x99, 0xc1, 0x27,
+	0xda, 0x97, 0x63, 0x89, 0xd9, 0x29, 0xa9, 0xb1, 0x37, 0x8f, 0x24, 0xd4, 0xb4, 0x61, 0x43, 0x1d,
+	0x34, 0xa2, 0x06, 0x67, 0x9d, 0x33, 0x92, 0x34, 0x76, 0xe7, 0x50, 0xb8, 0x8e, 0x0f, 0x35, 0xd4,
+	0x03, 0x3d, 0xff, 0x31, 0x01, 0x1d, 0x28, 0x46, 0x67, 0x3e, 0x3f, 0x18, 0xc6, 0x02, 0xaa, 0xf0,
+	0xea, 0x05, 0x6c, 0xa8, 0xdf, 0x07, 0xa4, 0x57, 0x73, 0xbe, 0x24, 0x18, 0xbb, 0x73, 0x28, 0x42,
+	0xc7, 0x6f, 0xa0, 0xa6, 0x4c, 0x58, 0x90, 0x88, 0xc1, 0xec, 0x24, 0xc6, 0xd8, 0x99, 0x25, 0x08,
+	0x05, 0x9f, 0xc3, 0xa3, 0xdc, 0x88, 0x04, 0xbd, 0x99, 0xf2, 0xce, 0x0c, 0x60, 0x8c, 0xfd, 0xf9,
+	0x44, 0xa1, 0xac, 0x27, 0xe7, 0x3d, 0xca, 0xec, 0x40, 0x46, 0x68, 0xc1, 0xb4, 0xc1, 0x30, 0x16,
+	0x50, 0x93, 0x08, 0xa9, 0x63, 0x02, 0x19, 0xa1, 0x39, 0x03, 0x05, 0x63, 0x77, 0x0e, 0x25, 0xab,
+	0x43, 0xbe, 0x03, 0x55, 0x1d, 0xd9, 0xe7, 0xa5, 0xb1, 0x3b, 0x87, 0x22, 0x74, 0x9c, 0x41, 0x3d,
+	0xfb, 0x2e, 0x44, 0x8a, 0xdf, 0xf9, 0x67, 0xa4, 0xd1, 0x98, 0x4b, 0x13, 0x9a, 0x3e, 0x05, 0x48,
+	0x1f, 0xfc, 0x48, 0xb1, 0xa8, 0x8e, 0x05, 0x8c, 0xc7, 0x33, 0x78, 0x21, 0x3d, 0x00, 0x34, 0xdb,
+	0x41, 0xa2, 0x27, 0xe2, 0xd8, 0x2c, 0xea, 0x5e, 0x8d, 0x83, 0x85, 0x74, 0xa1, 0x75, 0x04, 0x7b,
+	0x0b, 0xfa, 0x67, 0xf4, 0x8e, 0x2a, 0xba, 0xa0, 0x8b, 0x37, 0xde, 0x5e, 0xce, 0x24, 0x8c, 0x7c,
+	0x05, 0x8f, 0xe7, 0xf5, 0x95, 0xe8, 0x50, 0xbd, 0x78, 0xe6, 0xf5, 0xb5, 0xc6, 0x93, 0x25, 0x1c,
+	0xf9, 0xb0, 0x28, 0xb3, 0xb6, 0x6c, 0x58, 0x66, 0xe7, 0x7c, 0xc6, 0xc1, 0x42, 0x7a, 0x92, 0xcc,
+	0xf9, 0xaf, 0xac, 0x32, 0x99, 0x17, 0x7c, 0xdd, 0x35, 0x8c, 0x05, 0x54, 0xa1, 0x2f, 0x80, 0x37,
+	0x97, 0x7c, 0x0b, 0x45, 0xcf, 0x54, 0xe1, 0x25, 0x5f, 0x66, 0x8d, 0xf7, 0xee, 0x67, 0x4c, 0xf6,
+	0x75, 0xc1, 0xc7, 0x6c, 0xb9, 0xaf, 0xcb, 0x3f, 0xa9, 0x1b, 0x6f, 0x2f, 0x67, 0x12, 0x46, 0x7e,
+	0x0f, 0x3b, 0xd9, 0x8f, 0xfb, 0xf2, 0x5f, 0x08, 0x28, 0x23, 0x3d, 0xf7, 0x7f, 0x10, 0xc6, 0x5b,
+	0xcb, 0x58, 0x16, 0xa8, 0x97, 0xff, 0x1d, 0x98, 0xab, 0x3e, 0xfb, 0xef, 0x05, 0xe3, 0xad, 0x65,
+	0x2c, 0xf9, 0x10, 0xe5, 0xff, 0xee, 0x91, 0x0d, 0xd1, 0x82, 0x3f, 0x9d, 0x18, 0x6f, 0x2f, 0x67,
+	0x12, 0x46, 0x7e, 0x09, 0xd5, 0xe4, 0x49, 0x83, 0xe2, 0xe9, 0x4f, 0xf6, 0x41, 0x63, 0x6c, 0xe7,
+	0xd1, 0x89, 0xe8, 0x69, 0x4e, 0xf4, 0x74, 0xbe, 0xe8, 0x69, 0x5e, 0xf4, 0x19, 0x14, 0xf1, 0xd4,
+	0x47, 0xa2, 0xa7, 0x4e, 0x1f, 0x3c, 0xc6, 0x66, 0x8a, 0x10, 0x8c, 0x1f, 0xc2, 0x9a, 0x78, 0x59,
+	0x20, 0x31, 0x45, 0xc8, 0x3c, 0x48, 0x0c, 0x3d, 0x83, 0x53, 0x24, 0x58, 0x36, 0x24, 0x12, 0x4a,
+	0xeb, 0x6f, 0xe8, 0x19, 0x5c, 0x22, 0x21, 0xda, 0x68, 0x29, 0x91, 0xe9, 0xe7, 0x0d, 0x3d, 0x83,
+	0xe3, 0x12, 0xc3, 0x35, 0xfe, 0xe7, 0x9f, 0x8f, 0xff, 0x17, 0x00, 0x00, 0xff, 0xff, 0x2f, 0xa7,
+	0xb0, 0xbb, 0x10, 0x24, 0x00, 0x00,
 }
@@ -27,6 +27,8 @@ type UpgradeConvertPrimarySegmentsRequest struct {
 	OldBinDir            string         `protobuf:"bytes,1,opt,name=OldBinDir,proto3" json:"OldBinDir,omitempty"`
 	NewBinDir            string         `protobuf:"bytes,2,opt,name=NewBinDir,proto3" json:"NewBinDir,omitempty"`
 	DataDirPairs         []*DataDirPair `protobuf:"bytes,3,rep,name=DataDirPairs,proto3" json:"DataDirPairs,omitempty"`
+	DryRun               bool           `protobuf:"varint,4,opt,name=DryRun,proto3" json:"DryRun,omitempty"`
+	Mode                 string         `protobuf:"bytes,5,opt,name=Mode,proto3" json:"Mode,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -77,6 +79,20 @@ func (m *UpgradeConvertPrimarySegmentsRequest) GetDataDirPairs() []*DataDirPair
 	return nil
 }
 
+func (m *UpgradeConvertPrimarySegmentsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *UpgradeConvertPrimarySegmentsRequest) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
 type DataDirPair struct {
 	OldDataDir           string   `protobuf:"bytes,1,opt,name=OldDataDir,proto3" json:"OldDataDir,omitempty"`
 	NewDataDir           string   `protobuf:"bytes,2,opt,name=NewDataDir,proto3" json:"NewDataDir,omitempty"`
@@ -148,9 +164,11 @@ func (m *DataDirPair) GetContent() int32 {
 }
 
 type UpgradeConvertPrimarySegmentsReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Plan                 *DryRunPlan       `protobuf:"bytes,1,opt,name=Plan,proto3" json:"Plan,omitempty"`
+	Processes            []*SegmentProcess `protobuf:"bytes,2,rep,name=Processes,proto3" json:"Processes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *UpgradeConvertPrimarySegmentsReply) Reset()         { *m = UpgradeConvertPrimarySegmentsReply{} }
@@ -177,6 +195,160 @@ func (m *UpgradeConvertPrimarySegmentsReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpgradeConvertPrimarySegmentsReply proto.InternalMessageInfo
 
+func (m *UpgradeConvertPrimarySegmentsReply) GetPlan() *DryRunPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
+func (m *UpgradeConvertPrimarySegmentsReply) GetProcesses() []*SegmentProcess {
+	if m != nil {
+		return m.Processes
+	}
+	return nil
+}
+
+type SegmentProcess struct {
+	Content              int32    `protobuf:"varint,1,opt,name=Content,proto3" json:"Content,omitempty"`
+	Pid                  int32    `protobuf:"varint,2,opt,name=Pid,proto3" json:"Pid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SegmentProcess) Reset()         { *m = SegmentProcess{} }
+func (m *SegmentProcess) String() string { return proto.CompactTextString(m) }
+func (*SegmentProcess) ProtoMessage()    {}
+func (*SegmentProcess) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{3}
+}
+func (m *SegmentProcess) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentProcess.Unmarshal(m, b)
+}
+func (m *SegmentProcess) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentProcess.Marshal(b, m, deterministic)
+}
+func (dst *SegmentProcess) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentProcess.Merge(dst, src)
+}
+func (m *SegmentProcess) XXX_Size() int {
+	return xxx_messageInfo_SegmentProcess.Size(m)
+}
+func (m *SegmentProcess) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentProcess.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentProcess proto.InternalMessageInfo
+
+func (m *SegmentProcess) GetContent() int32 {
+	if m != nil {
+		return m.Content
+	}
+	return 0
+}
+
+func (m *SegmentProcess) GetPid() int32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+// CancelConvertPrimarySegmentsRequest stops the pg_upgrade of each segment that
+// is still running, and cleans up after it.
+type CancelConvertPrimarySegmentsRequest struct {
+	OldBinDir            string         `protobuf:"bytes,1,opt,name=OldBinDir,proto3" json:"OldBinDir,omitempty"`
+	NewBinDir            string         `protobuf:"bytes,2,opt,name=NewBinDir,proto3" json:"NewBinDir,omitempty"`
+	DataDirPairs         []*DataDirPair `protobuf:"bytes,3,rep,name=DataDirPairs,proto3" json:"DataDirPairs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CancelConvertPrimarySegmentsRequest) Reset()         { *m = CancelConvertPrimarySegmentsRequest{} }
+func (m *CancelConvertPrimarySegmentsRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelConvertPrimarySegmentsRequest) ProtoMessage()    {}
+func (*CancelConvertPrimarySegmentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{4}
+}
+func (m *CancelConvertPrimarySegmentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelConvertPrimarySegmentsRequest.Unmarshal(m, b)
+}
+func (m *CancelConvertPrimarySegmentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelConvertPrimarySegmentsRequest.Marshal(b, m, deterministic)
+}
+func (dst *CancelConvertPrimarySegmentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelConvertPrimarySegmentsRequest.Merge(dst, src)
+}
+func (m *CancelConvertPrimarySegmentsRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelConvertPrimarySegmentsRequest.Size(m)
+}
+func (m *CancelConvertPrimarySegmentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelConvertPrimarySegmentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelConvertPrimarySegmentsRequest proto.InternalMessageInfo
+
+func (m *CancelConvertPrimarySegmentsRequest) GetOldBinDir() string {
+	if m != nil {
+		return m.OldBinDir
+	}
+	return ""
+}
+
+func (m *CancelConvertPrimarySegmentsRequest) GetNewBinDir() string {
+	if m != nil {
+		return m.NewBinDir
+	}
+	return ""
+}
+
+func (m *CancelConvertPrimarySegmentsRequest) GetDataDirPairs() []*DataDirPair {
+	if m != nil {
+		return m.DataDirPairs
+	}
+	return nil
+}
+
+type CancelConvertPrimarySegmentsReply struct {
+	Processes            []*SegmentProcess `protobuf:"bytes,1,rep,name=Processes,proto3" json:"Processes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *CancelConvertPrimarySegmentsReply) Reset()         { *m = CancelConvertPrimarySegmentsReply{} }
+func (m *CancelConvertPrimarySegmentsReply) String() string { return proto.CompactTextString(m) }
+func (*CancelConvertPrimarySegmentsReply) ProtoMessage()    {}
+func (*CancelConvertPrimarySegmentsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{5}
+}
+func (m *CancelConvertPrimarySegmentsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelConvertPrimarySegmentsReply.Unmarshal(m, b)
+}
+func (m *CancelConvertPrimarySegmentsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelConvertPrimarySegmentsReply.Marshal(b, m, deterministic)
+}
+func (dst *CancelConvertPrimarySegmentsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelConvertPrimarySegmentsReply.Merge(dst, src)
+}
+func (m *CancelConvertPrimarySegmentsReply) XXX_Size() int {
+	return xxx_messageInfo_CancelConvertPrimarySegmentsReply.Size(m)
+}
+func (m *CancelConvertPrimarySegmentsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelConvertPrimarySegmentsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelConvertPrimarySegmentsReply proto.InternalMessageInfo
+
+func (m *CancelConvertPrimarySegmentsReply) GetProcesses() []*SegmentProcess {
+	if m != nil {
+		return m.Processes
+	}
+	return nil
+}
+
 type PingAgentsRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -187,7 +359,7 @@ func (m *PingAgentsRequest) Reset()         { *m = PingAgentsRequest{} }
 func (m *PingAgentsRequest) String() string { return proto.CompactTextString(m) }
 func (*PingAgentsRequest) ProtoMessage()    {}
 func (*PingAgentsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{3}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{6}
 }
 func (m *PingAgentsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PingAgentsRequest.Unmarshal(m, b)
@@ -217,7 +389,7 @@ func (m *PingAgentsReply) Reset()         { *m = PingAgentsReply{} }
 func (m *PingAgentsReply) String() string { return proto.CompactTextString(m) }
 func (*PingAgentsReply) ProtoMessage()    {}
 func (*PingAgentsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{4}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{7}
 }
 func (m *PingAgentsReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PingAgentsReply.Unmarshal(m, b)
@@ -247,7 +419,7 @@ func (m *CheckUpgradeStatusRequest) Reset()         { *m = CheckUpgradeStatusReq
 func (m *CheckUpgradeStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckUpgradeStatusRequest) ProtoMessage()    {}
 func (*CheckUpgradeStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{5}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{8}
 }
 func (m *CheckUpgradeStatusRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckUpgradeStatusRequest.Unmarshal(m, b)
@@ -278,7 +450,7 @@ func (m *CheckUpgradeStatusReply) Reset()         { *m = CheckUpgradeStatusReply
 func (m *CheckUpgradeStatusReply) String() string { return proto.CompactTextString(m) }
 func (*CheckUpgradeStatusReply) ProtoMessage()    {}
 func (*CheckUpgradeStatusReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{6}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{9}
 }
 func (m *CheckUpgradeStatusReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckUpgradeStatusReply.Unmarshal(m, b)
@@ -317,7 +489,7 @@ func (m *CheckConversionStatusRequest) Reset()         { *m = CheckConversionSta
 func (m *CheckConversionStatusRequest) String() string { return proto.CompactTextString(m) }
 func (*CheckConversionStatusRequest) ProtoMessage()    {}
 func (*CheckConversionStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{7}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{10}
 }
 func (m *CheckConversionStatusRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckConversionStatusRequest.Unmarshal(m, b)
@@ -364,7 +536,7 @@ func (m *SegmentInfo) Reset()         { *m = SegmentInfo{} }
 func (m *SegmentInfo) String() string { return proto.CompactTextString(m) }
 func (*SegmentInfo) ProtoMessage()    {}
 func (*SegmentInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{8}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{11}
 }
 func (m *SegmentInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SegmentInfo.Unmarshal(m, b)
@@ -406,17 +578,17 @@ func (m *SegmentInfo) GetDataDir() string {
 }
 
 type CheckConversionStatusReply struct {
-	Statuses             []string `protobuf:"bytes,1,rep,name=Statuses,proto3" json:"Statuses,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Statuses             []*ConversionStatus `protobuf:"bytes,2,rep,name=Statuses,proto3" json:"Statuses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *CheckConversionStatusReply) Reset()         { *m = CheckConversionStatusReply{} }
 func (m *CheckConversionStatusReply) String() string { return proto.CompactTextString(m) }
 func (*CheckConversionStatusReply) ProtoMessage()    {}
 func (*CheckConversionStatusReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{9}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{12}
 }
 func (m *CheckConversionStatusReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckConversionStatusReply.Unmarshal(m, b)
@@ -436,70 +608,25 @@ func (m *CheckConversionStatusReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CheckConversionStatusReply proto.InternalMessageInfo
 
-func (m *CheckConversionStatusReply) GetStatuses() []string {
+func (m *CheckConversionStatusReply) GetStatuses() []*ConversionStatus {
 	if m != nil {
 		return m.Statuses
 	}
 	return nil
 }
 
-type FileSysUsage struct {
-	Filesystem           string   `protobuf:"bytes,1,opt,name=Filesystem,proto3" json:"Filesystem,omitempty"`
-	Usage                float64  `protobuf:"fixed64,2,opt,name=Usage,proto3" json:"Usage,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *FileSysUsage) Reset()         { *m = FileSysUsage{} }
-func (m *FileSysUsage) String() string { return proto.CompactTextString(m) }
-func (*FileSysUsage) ProtoMessage()    {}
-func (*FileSysUsage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{10}
-}
-func (m *FileSysUsage) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_FileSysUsage.Unmarshal(m, b)
-}
-func (m *FileSysUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_FileSysUsage.Marshal(b, m, deterministic)
-}
-func (dst *FileSysUsage) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_FileSysUsage.Merge(dst, src)
-}
-func (m *FileSysUsage) XXX_Size() int {
-	return xxx_messageInfo_FileSysUsage.Size(m)
-}
-func (m *FileSysUsage) XXX_DiscardUnknown() {
-	xxx_messageInfo_FileSysUsage.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_FileSysUsage proto.InternalMessageInfo
-
-func (m *FileSysUsage) GetFilesystem() string {
-	if m != nil {
-		return m.Filesystem
-	}
-	return ""
-}
-
-func (m *FileSysUsage) GetUsage() float64 {
-	if m != nil {
-		return m.Usage
-	}
-	return 0
-}
-
 type CheckDiskSpaceRequestToAgent struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	DataDirPairs         []*DataDirPair `protobuf:"bytes,1,rep,name=DataDirPairs,proto3" json:"DataDirPairs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *CheckDiskSpaceRequestToAgent) Reset()         { *m = CheckDiskSpaceRequestToAgent{} }
 func (m *CheckDiskSpaceRequestToAgent) String() string { return proto.CompactTextString(m) }
 func (*CheckDiskSpaceRequestToAgent) ProtoMessage()    {}
 func (*CheckDiskSpaceRequestToAgent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{11}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{13}
 }
 func (m *CheckDiskSpaceRequestToAgent) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckDiskSpaceRequestToAgent.Unmarshal(m, b)
@@ -519,18 +646,28 @@ func (m *CheckDiskSpaceRequestToAgent) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CheckDiskSpaceRequestToAgent proto.InternalMessageInfo
 
+func (m *CheckDiskSpaceRequestToAgent) GetDataDirPairs() []*DataDirPair {
+	if m != nil {
+		return m.DataDirPairs
+	}
+	return nil
+}
+
+// CheckDiskSpaceReplyFromAgent has how full each filesystem on the host is, and
+// one DataDirDiskUsage for each DataDirPair in the request, in the same order.
 type CheckDiskSpaceReplyFromAgent struct {
-	ListOfFileSysUsage   []*FileSysUsage `protobuf:"bytes,1,rep,name=ListOfFileSysUsage,proto3" json:"ListOfFileSysUsage,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	ListOfFileSysUsage   []*FileSysUsage     `protobuf:"bytes,1,rep,name=ListOfFileSysUsage,proto3" json:"ListOfFileSysUsage,omitempty"`
+	Usages               []*DataDirDiskUsage `protobuf:"bytes,2,rep,name=Usages,proto3" json:"Usages,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *CheckDiskSpaceReplyFromAgent) Reset()         { *m = CheckDiskSpaceReplyFromAgent{} }
 func (m *CheckDiskSpaceReplyFromAgent) String() string { return proto.CompactTextString(m) }
 func (*CheckDiskSpaceReplyFromAgent) ProtoMessage()    {}
 func (*CheckDiskSpaceReplyFromAgent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{12}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{14}
 }
 func (m *CheckDiskSpaceReplyFromAgent) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckDiskSpaceReplyFromAgent.Unmarshal(m, b)
@@ -557,6 +694,75 @@ func (m *CheckDiskSpaceReplyFromAgent) GetListOfFileSysUsage() []*FileSysUsage {
 	return nil
 }
 
+func (m *CheckDiskSpaceReplyFromAgent) GetUsages() []*DataDirDiskUsage {
+	if m != nil {
+		return m.Usages
+	}
+	return nil
+}
+
+type DataDirDiskUsage struct {
+	TotalBytes           uint64   `protobuf:"varint,1,opt,name=TotalBytes,proto3" json:"TotalBytes,omitempty"`
+	RelationBytes        uint64   `protobuf:"varint,2,opt,name=RelationBytes,proto3" json:"RelationBytes,omitempty"`
+	TargetFilesystem     string   `protobuf:"bytes,3,opt,name=TargetFilesystem,proto3" json:"TargetFilesystem,omitempty"`
+	TargetFreeBytes      uint64   `protobuf:"varint,4,opt,name=TargetFreeBytes,proto3" json:"TargetFreeBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DataDirDiskUsage) Reset()         { *m = DataDirDiskUsage{} }
+func (m *DataDirDiskUsage) String() string { return proto.CompactTextString(m) }
+func (*DataDirDiskUsage) ProtoMessage()    {}
+func (*DataDirDiskUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{15}
+}
+func (m *DataDirDiskUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DataDirDiskUsage.Unmarshal(m, b)
+}
+func (m *DataDirDiskUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DataDirDiskUsage.Marshal(b, m, deterministic)
+}
+func (dst *DataDirDiskUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DataDirDiskUsage.Merge(dst, src)
+}
+func (m *DataDirDiskUsage) XXX_Size() int {
+	return xxx_messageInfo_DataDirDiskUsage.Size(m)
+}
+func (m *DataDirDiskUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_DataDirDiskUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DataDirDiskUsage proto.InternalMessageInfo
+
+func (m *DataDirDiskUsage) GetTotalBytes() uint64 {
+	if m != nil {
+		return m.TotalBytes
+	}
+	return 0
+}
+
+func (m *DataDirDiskUsage) GetRelationBytes() uint64 {
+	if m != nil {
+		return m.RelationBytes
+	}
+	return 0
+}
+
+func (m *DataDirDiskUsage) GetTargetFilesystem() string {
+	if m != nil {
+		return m.TargetFilesystem
+	}
+	return ""
+}
+
+func (m *DataDirDiskUsage) GetTargetFreeBytes() uint64 {
+	if m != nil {
+		return m.TargetFreeBytes
+	}
+	return 0
+}
+
 type CreateSegmentDataDirRequest struct {
 	Datadirs             []string `protobuf:"bytes,1,rep,name=datadirs,proto3" json:"datadirs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -568,7 +774,7 @@ func (m *CreateSegmentDataDirRequest) Reset()         { *m = CreateSegmentDataDi
 func (m *CreateSegmentDataDirRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateSegmentDataDirRequest) ProtoMessage()    {}
 func (*CreateSegmentDataDirRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{13}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{16}
 }
 func (m *CreateSegmentDataDirRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateSegmentDataDirRequest.Unmarshal(m, b)
@@ -605,7 +811,7 @@ func (m *CreateSegmentDataDirReply) Reset()         { *m = CreateSegmentDataDirR
 func (m *CreateSegmentDataDirReply) String() string { return proto.CompactTextString(m) }
 func (*CreateSegmentDataDirReply) ProtoMessage()    {}
 func (*CreateSegmentDataDirReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{14}
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{17}
 }
 func (m *CreateSegmentDataDirReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateSegmentDataDirReply.Unmarshal(m, b)
@@ -625,59 +831,512 @@ func (m *CreateSegmentDataDirReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CreateSegmentDataDirReply proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterType((*UpgradeConvertPrimarySegmentsRequest)(nil), "idl.UpgradeConvertPrimarySegmentsRequest")
-	proto.RegisterType((*DataDirPair)(nil), "idl.DataDirPair")
-	proto.RegisterType((*UpgradeConvertPrimarySegmentsReply)(nil), "idl.UpgradeConvertPrimarySegmentsReply")
-	proto.RegisterType((*PingAgentsRequest)(nil), "idl.PingAgentsRequest")
-	proto.RegisterType((*PingAgentsReply)(nil), "idl.PingAgentsReply")
-	proto.RegisterType((*CheckUpgradeStatusRequest)(nil), "idl.CheckUpgradeStatusRequest")
-	proto.RegisterType((*CheckUpgradeStatusReply)(nil), "idl.CheckUpgradeStatusReply")
-	proto.RegisterType((*CheckConversionStatusRequest)(nil), "idl.CheckConversionStatusRequest")
-	proto.RegisterType((*SegmentInfo)(nil), "idl.SegmentInfo")
-	proto.RegisterType((*CheckConversionStatusReply)(nil), "idl.CheckConversionStatusReply")
-	proto.RegisterType((*FileSysUsage)(nil), "idl.FileSysUsage")
-	proto.RegisterType((*CheckDiskSpaceRequestToAgent)(nil), "idl.CheckDiskSpaceRequestToAgent")
-	proto.RegisterType((*CheckDiskSpaceReplyFromAgent)(nil), "idl.CheckDiskSpaceReplyFromAgent")
-	proto.RegisterType((*CreateSegmentDataDirRequest)(nil), "idl.CreateSegmentDataDirRequest")
-	proto.RegisterType((*CreateSegmentDataDirReply)(nil), "idl.CreateSegmentDataDirReply")
+type DeleteSegmentDataDirRequest struct {
+	Datadirs             []string `protobuf:"bytes,1,rep,name=datadirs,proto3" json:"datadirs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *DeleteSegmentDataDirRequest) Reset()         { *m = DeleteSegmentDataDirRequest{} }
+func (m *DeleteSegmentDataDirRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteSegmentDataDirRequest) ProtoMessage()    {}
+func (*DeleteSegmentDataDirRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{18}
+}
+func (m *DeleteSegmentDataDirRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSegmentDataDirRequest.Unmarshal(m, b)
+}
+func (m *DeleteSegmentDataDirRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSegmentDataDirRequest.Marshal(b, m, deterministic)
+}
+func (dst *DeleteSegmentDataDirRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSegmentDataDirRequest.Merge(dst, src)
+}
+func (m *DeleteSegmentDataDirRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteSegmentDataDirRequest.Size(m)
+}
+func (m *DeleteSegmentDataDirRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSegmentDataDirRequest.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_DeleteSegmentDataDirRequest proto.InternalMessageInfo
 
-// AgentClient is the client API for Agent service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AgentClient interface {
-	CheckUpgradeStatus(ctx context.Context, in *CheckUpgradeStatusRequest, opts ...grpc.CallOption) (*CheckUpgradeStatusReply, error)
-	CheckConversionStatus(ctx context.Context, in *CheckConversionStatusRequest, opts ...grpc.CallOption) (*CheckConversionStatusReply, error)
-	CheckDiskSpaceOnAgents(ctx context.Context, in *CheckDiskSpaceRequestToAgent, opts ...grpc.CallOption) (*CheckDiskSpaceReplyFromAgent, error)
-	PingAgents(ctx context.Context, in *PingAgentsRequest, opts ...grpc.CallOption) (*PingAgentsReply, error)
-	UpgradeConvertPrimarySegments(ctx context.Context, in *UpgradeConvertPrimarySegmentsRequest, opts ...grpc.CallOption) (*UpgradeConvertPrimarySegmentsReply, error)
-	CreateSegmentDataDirectories(ctx context.Context, in *CreateSegmentDataDirRequest, opts ...grpc.CallOption) (*CreateSegmentDataDirReply, error)
+func (m *DeleteSegmentDataDirRequest) GetDatadirs() []string {
+	if m != nil {
+		return m.Datadirs
+	}
+	return nil
 }
 
-type agentClient struct {
-	cc *grpc.ClientConn
+type DeleteSegmentDataDirReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func NewAgentClient(cc *grpc.ClientConn) AgentClient {
-	return &agentClient{cc}
+func (m *DeleteSegmentDataDirReply) Reset()         { *m = DeleteSegmentDataDirReply{} }
+func (m *DeleteSegmentDataDirReply) String() string { return proto.CompactTextString(m) }
+func (*DeleteSegmentDataDirReply) ProtoMessage()    {}
+func (*DeleteSegmentDataDirReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{19}
 }
-
-func (c *agentClient) CheckUpgradeStatus(ctx context.Context, in *CheckUpgradeStatusRequest, opts ...grpc.CallOption) (*CheckUpgradeStatusReply, error) {
-	out := new(CheckUpgradeStatusReply)
-	err := c.cc.Invoke(ctx, "/idl.Agent/CheckUpgradeStatus", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *DeleteSegmentDataDirReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSegmentDataDirReply.Unmarshal(m, b)
+}
+func (m *DeleteSegmentDataDirReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSegmentDataDirReply.Marshal(b, m, deterministic)
+}
+func (dst *DeleteSegmentDataDirReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSegmentDataDirReply.Merge(dst, src)
+}
+func (m *DeleteSegmentDataDirReply) XXX_Size() int {
+	return xxx_messageInfo_DeleteSegmentDataDirReply.Size(m)
+}
+func (m *DeleteSegmentDataDirReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSegmentDataDirReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSegmentDataDirReply proto.InternalMessageInfo
+
+type CheckTargetLayoutRequest struct {
+	Ports                []int32        `protobuf:"varint,1,rep,packed,name=ports,proto3" json:"ports,omitempty"`
+	Datadirs             []string       `protobuf:"bytes,2,rep,name=datadirs,proto3" json:"datadirs,omitempty"`
+	LinkedDatadirs       []*DataDirPair `protobuf:"bytes,3,rep,name=linkedDatadirs,proto3" json:"linkedDatadirs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CheckTargetLayoutRequest) Reset()         { *m = CheckTargetLayoutRequest{} }
+func (m *CheckTargetLayoutRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckTargetLayoutRequest) ProtoMessage()    {}
+func (*CheckTargetLayoutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{20}
+}
+func (m *CheckTargetLayoutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckTargetLayoutRequest.Unmarshal(m, b)
+}
+func (m *CheckTargetLayoutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckTargetLayoutRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckTargetLayoutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckTargetLayoutRequest.Merge(dst, src)
+}
+func (m *CheckTargetLayoutRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckTargetLayoutRequest.Size(m)
+}
+func (m *CheckTargetLayoutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckTargetLayoutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckTargetLayoutRequest proto.InternalMessageInfo
+
+func (m *CheckTargetLayoutRequest) GetPorts() []int32 {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+func (m *CheckTargetLayoutRequest) GetDatadirs() []string {
+	if m != nil {
+		return m.Datadirs
+	}
+	return nil
+}
+
+func (m *CheckTargetLayoutRequest) GetLinkedDatadirs() []*DataDirPair {
+	if m != nil {
+		return m.LinkedDatadirs
+	}
+	return nil
+}
+
+type CheckTargetLayoutReply struct {
+	Problems             []string `protobuf:"bytes,1,rep,name=problems,proto3" json:"problems,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckTargetLayoutReply) Reset()         { *m = CheckTargetLayoutReply{} }
+func (m *CheckTargetLayoutReply) String() string { return proto.CompactTextString(m) }
+func (*CheckTargetLayoutReply) ProtoMessage()    {}
+func (*CheckTargetLayoutReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{21}
+}
+func (m *CheckTargetLayoutReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckTargetLayoutReply.Unmarshal(m, b)
+}
+func (m *CheckTargetLayoutReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckTargetLayoutReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckTargetLayoutReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckTargetLayoutReply.Merge(dst, src)
+}
+func (m *CheckTargetLayoutReply) XXX_Size() int {
+	return xxx_messageInfo_CheckTargetLayoutReply.Size(m)
+}
+func (m *CheckTargetLayoutReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckTargetLayoutReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckTargetLayoutReply proto.InternalMessageInfo
+
+func (m *CheckTargetLayoutReply) GetProblems() []string {
+	if m != nil {
+		return m.Problems
+	}
+	return nil
+}
+
+type CheckPortsRequestToAgent struct {
+	Ports                []int32  `protobuf:"varint,1,rep,packed,name=ports,proto3" json:"ports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckPortsRequestToAgent) Reset()         { *m = CheckPortsRequestToAgent{} }
+func (m *CheckPortsRequestToAgent) String() string { return proto.CompactTextString(m) }
+func (*CheckPortsRequestToAgent) ProtoMessage()    {}
+func (*CheckPortsRequestToAgent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{22}
+}
+func (m *CheckPortsRequestToAgent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPortsRequestToAgent.Unmarshal(m, b)
+}
+func (m *CheckPortsRequestToAgent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPortsRequestToAgent.Marshal(b, m, deterministic)
+}
+func (dst *CheckPortsRequestToAgent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPortsRequestToAgent.Merge(dst, src)
+}
+func (m *CheckPortsRequestToAgent) XXX_Size() int {
+	return xxx_messageInfo_CheckPortsRequestToAgent.Size(m)
+}
+func (m *CheckPortsRequestToAgent) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPortsRequestToAgent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPortsRequestToAgent proto.InternalMessageInfo
+
+func (m *CheckPortsRequestToAgent) GetPorts() []int32 {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+type CheckPortsReplyFromAgent struct {
+	Ports                []*PortStatus `protobuf:"bytes,1,rep,name=ports,proto3" json:"ports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *CheckPortsReplyFromAgent) Reset()         { *m = CheckPortsReplyFromAgent{} }
+func (m *CheckPortsReplyFromAgent) String() string { return proto.CompactTextString(m) }
+func (*CheckPortsReplyFromAgent) ProtoMessage()    {}
+func (*CheckPortsReplyFromAgent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{23}
+}
+func (m *CheckPortsReplyFromAgent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPortsReplyFromAgent.Unmarshal(m, b)
+}
+func (m *CheckPortsReplyFromAgent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPortsReplyFromAgent.Marshal(b, m, deterministic)
+}
+func (dst *CheckPortsReplyFromAgent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPortsReplyFromAgent.Merge(dst, src)
+}
+func (m *CheckPortsReplyFromAgent) XXX_Size() int {
+	return xxx_messageInfo_CheckPortsReplyFromAgent.Size(m)
+}
+func (m *CheckPortsReplyFromAgent) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPortsReplyFromAgent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPortsReplyFromAgent proto.InternalMessageInfo
+
+func (m *CheckPortsReplyFromAgent) GetPorts() []*PortStatus {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+// ReceiveFilesRequest is streamed to the agent once to start each file, with
+// Path set, and then as many times as it takes to send the file's Data. The
+// agent writes the file into its state directory, and only replaces any
+// existing file once the whole of it has arrived and matches its checksum.
+type ReceiveFilesRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=Path,proto3" json:"Path,omitempty"`
+	Mode                 uint32   `protobuf:"varint,2,opt,name=Mode,proto3" json:"Mode,omitempty"`
+	Size                 uint64   `protobuf:"varint,3,opt,name=Size,proto3" json:"Size,omitempty"`
+	Sha256               string   `protobuf:"bytes,4,opt,name=Sha256,proto3" json:"Sha256,omitempty"`
+	Data                 []byte   `protobuf:"bytes,5,opt,name=Data,proto3" json:"Data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReceiveFilesRequest) Reset()         { *m = ReceiveFilesRequest{} }
+func (m *ReceiveFilesRequest) String() string { return proto.CompactTextString(m) }
+func (*ReceiveFilesRequest) ProtoMessage()    {}
+func (*ReceiveFilesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{24}
+}
+func (m *ReceiveFilesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReceiveFilesRequest.Unmarshal(m, b)
+}
+func (m *ReceiveFilesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReceiveFilesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ReceiveFilesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReceiveFilesRequest.Merge(dst, src)
+}
+func (m *ReceiveFilesRequest) XXX_Size() int {
+	return xxx_messageInfo_ReceiveFilesRequest.Size(m)
+}
+func (m *ReceiveFilesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReceiveFilesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReceiveFilesRequest proto.InternalMessageInfo
+
+func (m *ReceiveFilesRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ReceiveFilesRequest) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *ReceiveFilesRequest) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *ReceiveFilesRequest) GetSha256() string {
+	if m != nil {
+		return m.Sha256
+	}
+	return ""
+}
+
+func (m *ReceiveFilesRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type ReceiveFilesReply struct {
+	Paths                []string `protobuf:"bytes,1,rep,name=Paths,proto3" json:"Paths,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReceiveFilesReply) Reset()         { *m = ReceiveFilesReply{} }
+func (m *ReceiveFilesReply) String() string { return proto.CompactTextString(m) }
+func (*ReceiveFilesReply) ProtoMessage()    {}
+func (*ReceiveFilesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{25}
+}
+func (m *ReceiveFilesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReceiveFilesReply.Unmarshal(m, b)
+}
+func (m *ReceiveFilesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReceiveFilesReply.Marshal(b, m, deterministic)
+}
+func (dst *ReceiveFilesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReceiveFilesReply.Merge(dst, src)
+}
+func (m *ReceiveFilesReply) XXX_Size() int {
+	return xxx_messageInfo_ReceiveFilesReply.Size(m)
+}
+func (m *ReceiveFilesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReceiveFilesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReceiveFilesReply proto.InternalMessageInfo
+
+func (m *ReceiveFilesReply) GetPaths() []string {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
+// CheckPathsRequest names what a completed step left behind on an agent's
+// host, so that resume can tell whether the step has to be run again.
+type CheckPathsRequest struct {
+	Datadirs             []string `protobuf:"bytes,1,rep,name=datadirs,proto3" json:"datadirs,omitempty"`
+	StateFiles           []string `protobuf:"bytes,2,rep,name=stateFiles,proto3" json:"stateFiles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckPathsRequest) Reset()         { *m = CheckPathsRequest{} }
+func (m *CheckPathsRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckPathsRequest) ProtoMessage()    {}
+func (*CheckPathsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{26}
+}
+func (m *CheckPathsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPathsRequest.Unmarshal(m, b)
+}
+func (m *CheckPathsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPathsRequest.Marshal(b, m, deterministic)
+}
+func (dst *CheckPathsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPathsRequest.Merge(dst, src)
+}
+func (m *CheckPathsRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckPathsRequest.Size(m)
+}
+func (m *CheckPathsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPathsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPathsRequest proto.InternalMessageInfo
+
+func (m *CheckPathsRequest) GetDatadirs() []string {
+	if m != nil {
+		return m.Datadirs
+	}
+	return nil
+}
+
+func (m *CheckPathsRequest) GetStateFiles() []string {
+	if m != nil {
+		return m.StateFiles
+	}
+	return nil
+}
+
+type CheckPathsReply struct {
+	Missing              []string `protobuf:"bytes,1,rep,name=missing,proto3" json:"missing,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckPathsReply) Reset()         { *m = CheckPathsReply{} }
+func (m *CheckPathsReply) String() string { return proto.CompactTextString(m) }
+func (*CheckPathsReply) ProtoMessage()    {}
+func (*CheckPathsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_hub_to_agent_43aae2cab82b618c, []int{27}
+}
+func (m *CheckPathsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckPathsReply.Unmarshal(m, b)
+}
+func (m *CheckPathsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckPathsReply.Marshal(b, m, deterministic)
+}
+func (dst *CheckPathsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckPathsReply.Merge(dst, src)
+}
+func (m *CheckPathsReply) XXX_Size() int {
+	return xxx_messageInfo_CheckPathsReply.Size(m)
+}
+func (m *CheckPathsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckPathsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckPathsReply proto.InternalMessageInfo
+
+func (m *CheckPathsReply) GetMissing() []string {
+	if m != nil {
+		return m.Missing
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*UpgradeConvertPrimarySegmentsRequest)(nil), "idl.UpgradeConvertPrimarySegmentsRequest")
+	proto.RegisterType((*DataDirPair)(nil), "idl.DataDirPair")
+	proto.RegisterType((*UpgradeConvertPrimarySegmentsReply)(nil), "idl.UpgradeConvertPrimarySegmentsReply")
+	proto.RegisterType((*SegmentProcess)(nil), "idl.SegmentProcess")
+	proto.RegisterType((*CancelConvertPrimarySegmentsRequest)(nil), "idl.CancelConvertPrimarySegmentsRequest")
+	proto.RegisterType((*CancelConvertPrimarySegmentsReply)(nil), "idl.CancelConvertPrimarySegmentsReply")
+	proto.RegisterType((*PingAgentsRequest)(nil), "idl.PingAgentsRequest")
+	proto.RegisterType((*PingAgentsReply)(nil), "idl.PingAgentsReply")
+	proto.RegisterType((*CheckUpgradeStatusRequest)(nil), "idl.CheckUpgradeStatusRequest")
+	proto.RegisterType((*CheckUpgradeStatusReply)(nil), "idl.CheckUpgradeStatusReply")
+	proto.RegisterType((*CheckConversionStatusRequest)(nil), "idl.CheckConversionStatusRequest")
+	proto.RegisterType((*SegmentInfo)(nil), "idl.SegmentInfo")
+	proto.RegisterType((*CheckConversionStatusReply)(nil), "idl.CheckConversionStatusReply")
+	proto.RegisterType((*CheckDiskSpaceRequestToAgent)(nil), "idl.CheckDiskSpaceRequestToAgent")
+	proto.RegisterType((*CheckDiskSpaceReplyFromAgent)(nil), "idl.CheckDiskSpaceReplyFromAgent")
+	proto.RegisterType((*DataDirDiskUsage)(nil), "idl.DataDirDiskUsage")
+	proto.RegisterType((*CreateSegmentDataDirRequest)(nil), "idl.CreateSegmentDataDirRequest")
+	proto.RegisterType((*CreateSegmentDataDirReply)(nil), "idl.CreateSegmentDataDirReply")
+	proto.RegisterType((*DeleteSegmentDataDirRequest)(nil), "idl.DeleteSegmentDataDirRequest")
+	proto.RegisterType((*DeleteSegmentDataDirReply)(nil), "idl.DeleteSegmentDataDirReply")
+	proto.RegisterType((*CheckTargetLayoutRequest)(nil), "idl.CheckTargetLayoutRequest")
+	proto.RegisterType((*CheckTargetLayoutReply)(nil), "idl.CheckTargetLayoutReply")
+	proto.RegisterType((*CheckPortsRequestToAgent)(nil), "idl.CheckPortsRequestToAgent")
+	proto.RegisterType((*CheckPortsReplyFromAgent)(nil), "idl.CheckPortsReplyFromAgent")
+	proto.RegisterType((*ReceiveFilesRequest)(nil), "idl.ReceiveFilesRequest")
+	proto.RegisterType((*ReceiveFilesReply)(nil), "idl.ReceiveFilesReply")
+	proto.RegisterType((*CheckPathsRequest)(nil), "idl.CheckPathsRequest")
+	proto.RegisterType((*CheckPathsReply)(nil), "idl.CheckPathsReply")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AgentClient is the client API for Agent service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AgentClient interface {
+	CheckUpgradeStatus(ctx context.Context, in *CheckUpgradeStatusRequest, opts ...grpc.CallOption) (*CheckUpgradeStatusReply, error)
+	CheckConversionStatus(ctx context.Context, in *CheckConversionStatusRequest, opts ...grpc.CallOption) (*CheckConversionStatusReply, error)
+	CheckDiskSpaceOnAgents(ctx context.Context, in *CheckDiskSpaceRequestToAgent, opts ...grpc.CallOption) (*CheckDiskSpaceReplyFromAgent, error)
+	PingAgents(ctx context.Context, in *PingAgentsRequest, opts ...grpc.CallOption) (*PingAgentsReply, error)
+	UpgradeConvertPrimarySegments(ctx context.Context, in *UpgradeConvertPrimarySegmentsRequest, opts ...grpc.CallOption) (*UpgradeConvertPrimarySegmentsReply, error)
+	CreateSegmentDataDirectories(ctx context.Context, in *CreateSegmentDataDirRequest, opts ...grpc.CallOption) (*CreateSegmentDataDirReply, error)
+	DeleteSegmentDataDirectories(ctx context.Context, in *DeleteSegmentDataDirRequest, opts ...grpc.CallOption) (*DeleteSegmentDataDirReply, error)
+	CheckTargetLayout(ctx context.Context, in *CheckTargetLayoutRequest, opts ...grpc.CallOption) (*CheckTargetLayoutReply, error)
+	CheckPorts(ctx context.Context, in *CheckPortsRequestToAgent, opts ...grpc.CallOption) (*CheckPortsReplyFromAgent, error)
+	ReceiveFiles(ctx context.Context, opts ...grpc.CallOption) (Agent_ReceiveFilesClient, error)
+	CancelConvertPrimarySegments(ctx context.Context, in *CancelConvertPrimarySegmentsRequest, opts ...grpc.CallOption) (*CancelConvertPrimarySegmentsReply, error)
+	CheckPaths(ctx context.Context, in *CheckPathsRequest, opts ...grpc.CallOption) (*CheckPathsReply, error)
+}
+
+type agentClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAgentClient(cc *grpc.ClientConn) AgentClient {
+	return &agentClient{cc}
+}
+
+func (c *agentClient) CheckUpgradeStatus(ctx context.Context, in *CheckUpgradeStatusRequest, opts ...grpc.CallOption) (*CheckUpgradeStatusReply, error) {
+	out := new(CheckUpgradeStatusReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/CheckUpgradeStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 func (c *agentClient) CheckConversionStatus(ctx context.Context, in *CheckConversionStatusRequest, opts ...grpc.CallOption) (*CheckConversionStatusReply, error) {
@@ -725,6 +1384,85 @@ func (c *agentClient) CreateSegmentDataDirectories(ctx context.Context, in *Crea
 	return out, nil
 }
 
+func (c *agentClient) DeleteSegmentDataDirectories(ctx context.Context, in *DeleteSegmentDataDirRequest, opts ...grpc.CallOption) (*DeleteSegmentDataDirReply, error) {
+	out := new(DeleteSegmentDataDirReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/DeleteSegmentDataDirectories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) CheckTargetLayout(ctx context.Context, in *CheckTargetLayoutRequest, opts ...grpc.CallOption) (*CheckTargetLayoutReply, error) {
+	out := new(CheckTargetLayoutReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/CheckTargetLayout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) CheckPorts(ctx context.Context, in *CheckPortsRequestToAgent, opts ...grpc.CallOption) (*CheckPortsReplyFromAgent, error) {
+	out := new(CheckPortsReplyFromAgent)
+	err := c.cc.Invoke(ctx, "/idl.Agent/CheckPorts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ReceiveFiles(ctx context.Context, opts ...grpc.CallOption) (Agent_ReceiveFilesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Agent_serviceDesc.Streams[0], "/idl.Agent/ReceiveFiles", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentReceiveFilesClient{stream}
+	return x, nil
+}
+
+type Agent_ReceiveFilesClient interface {
+	Send(*ReceiveFilesRequest) error
+	CloseAndRecv() (*ReceiveFilesReply, error)
+	grpc.ClientStream
+}
+
+type agentReceiveFilesClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentReceiveFilesClient) Send(m *ReceiveFilesRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *agentReceiveFilesClient) CloseAndRecv() (*ReceiveFilesReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ReceiveFilesReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *agentClient) CancelConvertPrimarySegments(ctx context.Context, in *CancelConvertPrimarySegmentsRequest, opts ...grpc.CallOption) (*CancelConvertPrimarySegmentsReply, error) {
+	out := new(CancelConvertPrimarySegmentsReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/CancelConvertPrimarySegments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) CheckPaths(ctx context.Context, in *CheckPathsRequest, opts ...grpc.CallOption) (*CheckPathsReply, error) {
+	out := new(CheckPathsReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/CheckPaths", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServer is the server API for Agent service.
 type AgentServer interface {
 	CheckUpgradeStatus(context.Context, *CheckUpgradeStatusRequest) (*CheckUpgradeStatusReply, error)
@@ -733,6 +1471,12 @@ type AgentServer interface {
 	PingAgents(context.Context, *PingAgentsRequest) (*PingAgentsReply, error)
 	UpgradeConvertPrimarySegments(context.Context, *UpgradeConvertPrimarySegmentsRequest) (*UpgradeConvertPrimarySegmentsReply, error)
 	CreateSegmentDataDirectories(context.Context, *CreateSegmentDataDirRequest) (*CreateSegmentDataDirReply, error)
+	DeleteSegmentDataDirectories(context.Context, *DeleteSegmentDataDirRequest) (*DeleteSegmentDataDirReply, error)
+	CheckTargetLayout(context.Context, *CheckTargetLayoutRequest) (*CheckTargetLayoutReply, error)
+	CheckPorts(context.Context, *CheckPortsRequestToAgent) (*CheckPortsReplyFromAgent, error)
+	ReceiveFiles(Agent_ReceiveFilesServer) error
+	CancelConvertPrimarySegments(context.Context, *CancelConvertPrimarySegmentsRequest) (*CancelConvertPrimarySegmentsReply, error)
+	CheckPaths(context.Context, *CheckPathsRequest) (*CheckPathsReply, error)
 }
 
 func RegisterAgentServer(s *grpc.Server, srv AgentServer) {
@@ -847,6 +1591,122 @@ func _Agent_CreateSegmentDataDirectories_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_DeleteSegmentDataDirectories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSegmentDataDirRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).DeleteSegmentDataDirectories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/DeleteSegmentDataDirectories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).DeleteSegmentDataDirectories(ctx, req.(*DeleteSegmentDataDirRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_CheckTargetLayout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckTargetLayoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).CheckTargetLayout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/CheckTargetLayout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).CheckTargetLayout(ctx, req.(*CheckTargetLayoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_CheckPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPortsRequestToAgent)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).CheckPorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/CheckPorts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).CheckPorts(ctx, req.(*CheckPortsRequestToAgent))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ReceiveFiles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentServer).ReceiveFiles(&agentReceiveFilesServer{stream})
+}
+
+type Agent_ReceiveFilesServer interface {
+	SendAndClose(*ReceiveFilesReply) error
+	Recv() (*ReceiveFilesRequest, error)
+	grpc.ServerStream
+}
+
+type agentReceiveFilesServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentReceiveFilesServer) SendAndClose(m *ReceiveFilesReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *agentReceiveFilesServer) Recv() (*ReceiveFilesRequest, error) {
+	m := new(ReceiveFilesRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Agent_CancelConvertPrimarySegments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelConvertPrimarySegmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).CancelConvertPrimarySegments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/CancelConvertPrimarySegments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).CancelConvertPrimarySegments(ctx, req.(*CancelConvertPrimarySegmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_CheckPaths_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPathsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).CheckPaths(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/CheckPaths",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).CheckPaths(ctx, req.(*CheckPathsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Agent_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "idl.Agent",
 	HandlerType: (*AgentServer)(nil),
@@ -875,53 +1735,111 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CreateSegmentDataDirectories",
 			Handler:    _Agent_CreateSegmentDataDirectories_Handler,
 		},
+		{
+			MethodName: "DeleteSegmentDataDirectories",
+			Handler:    _Agent_DeleteSegmentDataDirectories_Handler,
+		},
+		{
+			MethodName: "CheckTargetLayout",
+			Handler:    _Agent_CheckTargetLayout_Handler,
+		},
+		{
+			MethodName: "CheckPorts",
+			Handler:    _Agent_CheckPorts_Handler,
+		},
+		{
+			MethodName: "CancelConvertPrimarySegments",
+			Handler:    _Agent_CancelConvertPrimarySegments_Handler,
+		},
+		{
+			MethodName: "CheckPaths",
+			Handler:    _Agent_CheckPaths_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReceiveFiles",
+			Handler:       _Agent_ReceiveFiles_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "hub_to_agent.proto",
 }
 
 func init() { proto.RegisterFile("hub_to_agent.proto", fileDescriptor_hub_to_agent_43aae2cab82b618c) }
 
 var fileDescriptor_hub_to_agent_43aae2cab82b618c = []byte{
-	// 629 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0x5d, 0x6f, 0x12, 0x41,
-	0x14, 0xed, 0x4a, 0x51, 0x7a, 0x69, 0xa2, 0x1d, 0x6b, 0xc5, 0x2d, 0x22, 0x4e, 0x9a, 0x58, 0x13,
-	0xd3, 0x87, 0xea, 0x83, 0x46, 0x5f, 0x2a, 0xa4, 0xd1, 0xc4, 0x14, 0xb2, 0xb4, 0x8f, 0xa6, 0x0e,
-	0xec, 0x94, 0x4e, 0xba, 0xec, 0xac, 0x33, 0x83, 0xcd, 0xfe, 0x13, 0x13, 0x7f, 0x88, 0x7f, 0xcf,
-	0xcc, 0xc7, 0xc2, 0x10, 0x58, 0xec, 0x1b, 0xf7, 0x9c, 0x33, 0x77, 0xcf, 0x9e, 0x7b, 0x67, 0x01,
-	0x74, 0x3d, 0x1d, 0x5e, 0x2a, 0x7e, 0x49, 0xc6, 0x34, 0x55, 0x47, 0x99, 0xe0, 0x8a, 0xa3, 0x0a,
-	0x8b, 0x13, 0xfc, 0x3b, 0x80, 0x83, 0x8b, 0x6c, 0x2c, 0x48, 0x4c, 0x3b, 0x3c, 0xfd, 0x45, 0x85,
-	0xea, 0x0b, 0x36, 0x21, 0x22, 0x1f, 0xd0, 0xf1, 0x84, 0xa6, 0x4a, 0x46, 0xf4, 0xe7, 0x94, 0x4a,
-	0x85, 0x9a, 0xb0, 0xd5, 0x4b, 0xe2, 0xcf, 0x2c, 0xed, 0x32, 0xd1, 0x08, 0xda, 0xc1, 0xe1, 0x56,
-	0x34, 0x07, 0x34, 0x7b, 0x46, 0x6f, 0x1d, 0x7b, 0xcf, 0xb2, 0x33, 0x00, 0xbd, 0x83, 0xed, 0x2e,
-	0x51, 0xa4, 0xcb, 0x44, 0x9f, 0x30, 0x21, 0x1b, 0x95, 0x76, 0xe5, 0xb0, 0x7e, 0xfc, 0xe8, 0x88,
-	0xc5, 0xc9, 0x91, 0x47, 0x44, 0x0b, 0x2a, 0xfc, 0x27, 0x80, 0xba, 0x07, 0xa0, 0x16, 0x40, 0x2f,
-	0x89, 0x1d, 0xe2, 0x2c, 0x78, 0x88, 0xe6, 0xcf, 0xe8, 0x6d, 0xc1, 0x5b, 0x13, 0x1e, 0x82, 0x1a,
-	0xf0, 0xa0, 0x97, 0xc4, 0x7d, 0x2e, 0x54, 0xa3, 0xd2, 0x0e, 0x0e, 0xab, 0x51, 0x51, 0x6a, 0xe6,
-	0x8c, 0xde, 0x1a, 0x66, 0xd3, 0x32, 0xae, 0xd4, 0x4c, 0x87, 0xa7, 0x8a, 0xa6, 0xaa, 0x51, 0xb5,
-	0x8c, 0x2b, 0xf1, 0x01, 0xe0, 0xff, 0xe4, 0x96, 0x25, 0x39, 0x7e, 0x0c, 0x3b, 0x7d, 0x96, 0x8e,
-	0x4f, 0xc6, 0x5e, 0x94, 0x78, 0x07, 0x1e, 0xfa, 0xa0, 0xd6, 0xed, 0xc3, 0xb3, 0xce, 0x35, 0x1d,
-	0xdd, 0xb8, 0x96, 0x03, 0x45, 0xd4, 0x74, 0xa6, 0xff, 0x08, 0x4f, 0x57, 0x91, 0x59, 0x92, 0xa3,
-	0x36, 0xd4, 0xfb, 0x82, 0x8f, 0xa8, 0x94, 0xdf, 0x98, 0x54, 0x2e, 0x14, 0x1f, 0xc2, 0xd7, 0xd0,
-	0x34, 0x87, 0xad, 0x4b, 0xc9, 0x78, 0xba, 0xd0, 0x1c, 0xbd, 0x81, 0x5a, 0x61, 0xb9, 0x11, 0x78,
-	0x73, 0x71, 0xe0, 0xd7, 0xf4, 0x8a, 0x47, 0x33, 0x05, 0x0a, 0xa1, 0xf6, 0x85, 0x4b, 0x95, 0x92,
-	0x09, 0x75, 0x09, 0xcf, 0x6a, 0x7c, 0x01, 0x75, 0xef, 0x90, 0x1f, 0x5d, 0xb0, 0x10, 0x1d, 0x42,
-	0xb0, 0xd9, 0x1d, 0xb2, 0xd8, 0x34, 0xa8, 0x46, 0xe6, 0xb7, 0x56, 0x17, 0x93, 0xab, 0x98, 0xbe,
-	0x45, 0x89, 0xdf, 0x43, 0x58, 0xf2, 0x02, 0x3a, 0x80, 0x10, 0x6a, 0xb6, 0xa4, 0xd6, 0xfe, 0x56,
-	0x34, 0xab, 0x71, 0x17, 0xb6, 0x4f, 0x59, 0x42, 0x07, 0xb9, 0xbc, 0x90, 0x64, 0x4c, 0xf5, 0x82,
-	0xe8, 0x5a, 0xe6, 0x52, 0xd1, 0x49, 0xb1, 0x40, 0x73, 0x04, 0xed, 0x42, 0xd5, 0x08, 0x8d, 0xb1,
-	0x20, 0xb2, 0x05, 0x6e, 0xb9, 0x00, 0xbb, 0x4c, 0xde, 0x0c, 0x32, 0x32, 0xa2, 0x2e, 0xb9, 0x73,
-	0x6e, 0x06, 0x88, 0xc9, 0x32, 0x9f, 0x25, 0xf9, 0xa9, 0xe0, 0x13, 0xc3, 0xa3, 0x13, 0x40, 0x7a,
-	0x10, 0xbd, 0x2b, 0xdf, 0x8b, 0x8b, 0x7a, 0xc7, 0x44, 0xed, 0x13, 0xd1, 0x0a, 0x31, 0xfe, 0x00,
-	0xfb, 0x1d, 0x41, 0x89, 0xa2, 0x2e, 0x5f, 0x17, 0x4d, 0x31, 0xc2, 0x10, 0x6a, 0x31, 0x51, 0x24,
-	0xd6, 0x57, 0xcb, 0x65, 0x50, 0xd4, 0x66, 0xb1, 0x56, 0x1e, 0xcd, 0x92, 0xfc, 0xf8, 0xef, 0x26,
-	0x54, 0xad, 0xc9, 0x73, 0x40, 0xcb, 0x2b, 0x86, 0x5a, 0xc6, 0x5e, 0xe9, 0x62, 0x86, 0xcd, 0x52,
-	0x5e, 0xef, 0xf4, 0x06, 0xfa, 0x0e, 0x4f, 0x56, 0x8e, 0x0e, 0xbd, 0x9c, 0x1f, 0x2c, 0xd9, 0xcb,
-	0xf0, 0xc5, 0x3a, 0x89, 0x6d, 0xff, 0x03, 0xf6, 0x16, 0x93, 0xef, 0xa5, 0xf6, 0x4e, 0xf9, 0xfd,
-	0x4b, 0xc6, 0x16, 0xae, 0x96, 0xf8, 0x93, 0xc3, 0x1b, 0xe8, 0x13, 0xc0, 0xfc, 0xa6, 0xa2, 0x3d,
-	0x73, 0x64, 0xe9, 0x3e, 0x87, 0xbb, 0x4b, 0xb8, 0xf5, 0x37, 0x85, 0xe7, 0x6b, 0x3f, 0x11, 0xe8,
-	0xb5, 0x39, 0x78, 0x97, 0xcf, 0x6f, 0xf8, 0xea, 0x2e, 0x52, 0xfb, 0xd8, 0x21, 0x34, 0x57, 0x8d,
-	0x9c, 0x8e, 0x14, 0x17, 0x8c, 0x4a, 0xd4, 0xb6, 0x6f, 0x5e, 0xbe, 0x50, 0x61, 0x6b, 0x8d, 0xc2,
-	0x3c, 0x63, 0x78, 0xdf, 0xfc, 0x85, 0xbc, 0xfd, 0x17, 0x00, 0x00, 0xff, 0xff, 0xbf, 0xc3, 0x1f,
-	0xcb, 0x58, 0x06, 0x00, 0x00,
+	// 1147 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x57, 0xcb, 0x73, 0xdb, 0x44,
+	0x18, 0xaf, 0x62, 0x3b, 0x75, 0xbe, 0xa4, 0x8d, 0xbd, 0x49, 0x83, 0x50, 0xd2, 0xe0, 0x6e, 0x0b,
+	0xb8, 0x3c, 0x32, 0x10, 0x0a, 0x43, 0x87, 0x5c, 0xd2, 0x78, 0x3a, 0xc0, 0x84, 0xd8, 0xac, 0x13,
+	0x6e, 0x4c, 0x59, 0x5b, 0x5b, 0x7b, 0x27, 0xb2, 0x64, 0xa4, 0x75, 0x33, 0x66, 0xe0, 0xca, 0x99,
+	0x1b, 0x07, 0xfe, 0x07, 0x4e, 0xfc, 0x09, 0xfc, 0x61, 0xcc, 0x3e, 0x24, 0x4b, 0x96, 0xec, 0x7a,
+	0x38, 0xf5, 0xb6, 0xdf, 0xfb, 0xb1, 0xbf, 0xfd, 0x3e, 0x09, 0xd0, 0x70, 0xd2, 0x7b, 0x21, 0x82,
+	0x17, 0x74, 0xc0, 0x7c, 0x71, 0x34, 0x0e, 0x03, 0x11, 0xa0, 0x12, 0x77, 0x3d, 0xa7, 0xd6, 0xf7,
+	0xb8, 0x14, 0x0c, 0x27, 0x3d, 0xcd, 0xc6, 0xff, 0x5a, 0xf0, 0xe8, 0x6a, 0x3c, 0x08, 0xa9, 0xcb,
+	0xce, 0x02, 0xff, 0x15, 0x0b, 0x45, 0x27, 0xe4, 0x23, 0x1a, 0x4e, 0xbb, 0x6c, 0x30, 0x62, 0xbe,
+	0x88, 0x08, 0xfb, 0x79, 0xc2, 0x22, 0x81, 0x0e, 0x60, 0xa3, 0xed, 0xb9, 0xcf, 0xb8, 0xdf, 0xe2,
+	0xa1, 0x6d, 0x35, 0xac, 0xe6, 0x06, 0x99, 0x31, 0xa4, 0xf4, 0x82, 0xdd, 0x18, 0xe9, 0x9a, 0x96,
+	0x26, 0x0c, 0xf4, 0x04, 0xb6, 0x5a, 0x54, 0xd0, 0x16, 0x0f, 0x3b, 0x94, 0x87, 0x91, 0x5d, 0x6a,
+	0x94, 0x9a, 0x9b, 0xc7, 0xb5, 0x23, 0xee, 0x7a, 0x47, 0x29, 0x01, 0xc9, 0x68, 0xa1, 0x3d, 0x58,
+	0x6f, 0x85, 0x53, 0x32, 0xf1, 0xed, 0x72, 0xc3, 0x6a, 0x56, 0x89, 0xa1, 0x10, 0x82, 0xf2, 0x77,
+	0x81, 0xcb, 0xec, 0x8a, 0x0a, 0xa3, 0xce, 0xf8, 0x2f, 0x0b, 0x36, 0x53, 0xc6, 0xe8, 0x10, 0xa0,
+	0xed, 0xb9, 0x86, 0x63, 0xd2, 0x4d, 0x71, 0xa4, 0xfc, 0x82, 0xdd, 0xc4, 0x72, 0x9d, 0x70, 0x8a,
+	0x83, 0x6c, 0xb8, 0xdd, 0xf6, 0xdc, 0x4e, 0x10, 0x0a, 0xbb, 0xd4, 0xb0, 0x9a, 0x15, 0x12, 0x93,
+	0x52, 0x72, 0xc1, 0x6e, 0x94, 0xa4, 0xac, 0x25, 0x86, 0x94, 0x92, 0xb3, 0xc0, 0x17, 0xcc, 0x17,
+	0x2a, 0xb5, 0x0a, 0x89, 0x49, 0xfc, 0x2b, 0xe0, 0xd7, 0xf4, 0x78, 0xec, 0x4d, 0xd1, 0x43, 0x28,
+	0x77, 0x3c, 0xea, 0xab, 0x6c, 0x37, 0x8f, 0xb7, 0x75, 0x77, 0x54, 0xc9, 0x92, 0x4d, 0x94, 0x10,
+	0x7d, 0x0a, 0x1b, 0x9d, 0x30, 0xe8, 0xb3, 0x28, 0x62, 0x91, 0xbd, 0xa6, 0xfa, 0xb8, 0xa3, 0x34,
+	0x8d, 0x2f, 0x23, 0x24, 0x33, 0x2d, 0x7c, 0x02, 0x77, 0xb3, 0xc2, 0x74, 0xa6, 0x56, 0x26, 0x53,
+	0x54, 0x83, 0x52, 0x87, 0xbb, 0xaa, 0x21, 0x15, 0x22, 0x8f, 0xf8, 0x4f, 0x0b, 0x1e, 0x9e, 0x51,
+	0xbf, 0xcf, 0xbc, 0x37, 0x0c, 0x1f, 0xf8, 0x07, 0x78, 0xb0, 0x3c, 0x31, 0xd9, 0xd4, 0x4c, 0xbf,
+	0xac, 0x95, 0xfa, 0xb5, 0x03, 0xf5, 0x0e, 0xf7, 0x07, 0xa7, 0x83, 0x54, 0x79, 0xb8, 0x0e, 0xdb,
+	0x69, 0xe6, 0xd8, 0x9b, 0xe2, 0x7d, 0x78, 0xfb, 0x6c, 0xc8, 0xfa, 0xd7, 0xe6, 0x6a, 0xbb, 0x82,
+	0x8a, 0x49, 0xa2, 0xff, 0x15, 0xbc, 0x55, 0x24, 0x94, 0x29, 0x35, 0x60, 0xd3, 0x04, 0x3b, 0xe7,
+	0x91, 0x30, 0xbd, 0x4a, 0xb3, 0xf0, 0x10, 0x0e, 0x94, 0xb1, 0x2e, 0x2c, 0xe2, 0x81, 0x9f, 0x71,
+	0x8e, 0x3e, 0x82, 0x6a, 0x5c, 0xa5, 0x6d, 0xa5, 0x7a, 0x65, 0x98, 0xdf, 0xf8, 0x2f, 0x03, 0x92,
+	0x68, 0x20, 0x07, 0xaa, 0x5f, 0x07, 0x91, 0xf0, 0xe9, 0x88, 0x99, 0xd6, 0x27, 0x34, 0xbe, 0x82,
+	0xcd, 0x94, 0xd1, 0x12, 0x60, 0x20, 0x28, 0xb7, 0x7a, 0x09, 0x32, 0xd4, 0x59, 0x6a, 0xc7, 0x2f,
+	0xa8, 0xa4, 0xfc, 0xc6, 0x24, 0xbe, 0x02, 0x67, 0x41, 0x01, 0xfa, 0x4e, 0xaa, 0x9a, 0x4c, 0x20,
+	0x7c, 0x4f, 0xa5, 0x9f, 0xd3, 0x4e, 0xd4, 0xbe, 0x2d, 0x57, 0xad, 0xda, 0x1a, 0xbe, 0x34, 0x7d,
+	0x69, 0xf1, 0xe8, 0xba, 0x3b, 0xa6, 0x7d, 0x66, 0x1a, 0x72, 0x19, 0xa8, 0x7b, 0xc9, 0xe1, 0xc8,
+	0x5a, 0x09, 0x47, 0x7f, 0x58, 0x79, 0xb7, 0x63, 0x6f, 0xfa, 0x3c, 0x0c, 0x46, 0xda, 0xed, 0x29,
+	0x20, 0x79, 0x2d, 0xed, 0x97, 0xcf, 0xb9, 0xc7, 0xba, 0xd3, 0xe8, 0x2a, 0xa2, 0x03, 0x66, 0x9c,
+	0xd7, 0x95, 0xf3, 0xb4, 0x80, 0x14, 0x28, 0xa3, 0x8f, 0x61, 0x5d, 0x1d, 0xb2, 0x05, 0x9b, 0x34,
+	0x64, 0x5c, 0x6d, 0x6a, 0x94, 0xf0, 0xdf, 0x16, 0xd4, 0xe6, 0x85, 0x72, 0x66, 0x5d, 0x06, 0x82,
+	0x7a, 0xcf, 0xa6, 0x42, 0x61, 0xd9, 0x6a, 0x96, 0x49, 0x8a, 0x83, 0x1e, 0xc1, 0x1d, 0xc2, 0x3c,
+	0x2a, 0x78, 0xe0, 0x6b, 0x95, 0x35, 0xa5, 0x92, 0x65, 0xa2, 0x0f, 0xa0, 0x76, 0x49, 0xc3, 0x01,
+	0x13, 0x32, 0xbf, 0x68, 0x1a, 0x09, 0x36, 0x32, 0xb7, 0x97, 0xe3, 0xa3, 0x26, 0x6c, 0x1b, 0x5e,
+	0xc8, 0x98, 0xf6, 0x59, 0x56, 0x3e, 0xe7, 0xd9, 0xf8, 0x29, 0xec, 0x9f, 0x85, 0x8c, 0x0a, 0x66,
+	0xd0, 0x64, 0x92, 0x8f, 0x01, 0xeb, 0x40, 0xd5, 0xa5, 0x82, 0xba, 0xf1, 0xa5, 0x6c, 0x90, 0x84,
+	0x56, 0xcf, 0xa8, 0xd0, 0x54, 0xbe, 0xb1, 0xa7, 0xb0, 0xdf, 0x62, 0x1e, 0xfb, 0x9f, 0x7e, 0x8b,
+	0x4d, 0xa5, 0xdf, 0xdf, 0x2d, 0xb0, 0xd5, 0x9d, 0xeb, 0x42, 0xce, 0xe9, 0x34, 0x98, 0x88, 0xd8,
+	0xeb, 0x2e, 0x54, 0xc6, 0x41, 0x68, 0xde, 0x56, 0x85, 0x68, 0x22, 0x13, 0x6b, 0x2d, 0x1b, 0x0b,
+	0x7d, 0x09, 0x77, 0x3d, 0xee, 0x5f, 0x33, 0xb7, 0x65, 0x38, 0x0b, 0x47, 0xd8, 0x9c, 0x1e, 0x7e,
+	0x02, 0x7b, 0x05, 0x79, 0xc8, 0x57, 0xe2, 0x40, 0x75, 0x1c, 0x06, 0x3d, 0x8f, 0x8d, 0x92, 0xda,
+	0x62, 0x1a, 0x7f, 0x62, 0xb2, 0x97, 0x7b, 0x27, 0x9a, 0x7b, 0x04, 0x85, 0xd9, 0xe3, 0xd3, 0xac,
+	0x45, 0x06, 0xdf, 0xef, 0xa6, 0x2d, 0xe2, 0xcd, 0x23, 0x15, 0xcd, 0x33, 0x34, 0x2e, 0x7e, 0x83,
+	0x1d, 0xc2, 0xfa, 0x8c, 0xbf, 0x62, 0x0a, 0x22, 0x71, 0xb7, 0x10, 0x94, 0x3b, 0x54, 0x0c, 0xcd,
+	0x1c, 0x53, 0xe7, 0x64, 0x45, 0x4b, 0x04, 0xde, 0xd1, 0x2b, 0x5a, 0xf2, 0xba, 0xfc, 0x17, 0xa6,
+	0xc0, 0x56, 0x26, 0xea, 0x2c, 0x57, 0x7c, 0x77, 0x48, 0x8f, 0x3f, 0xff, 0x42, 0xe1, 0x6a, 0x83,
+	0x18, 0x4a, 0xea, 0xca, 0x0e, 0xa9, 0x3d, 0xba, 0x45, 0xd4, 0x19, 0x3f, 0x86, 0x7a, 0x36, 0xbc,
+	0x6c, 0xd2, 0x2e, 0x54, 0x64, 0xc0, 0xb8, 0x43, 0x9a, 0xc0, 0x6d, 0xa8, 0xeb, 0x62, 0x25, 0xb5,
+	0x02, 0x56, 0xe4, 0xd3, 0x8a, 0x04, 0x15, 0xda, 0xb3, 0xb9, 0xdd, 0x14, 0x07, 0x7f, 0x08, 0xdb,
+	0x69, 0x87, 0x32, 0xb2, 0x0d, 0xb7, 0x47, 0x3c, 0x8a, 0xb8, 0x3f, 0x30, 0xde, 0x62, 0xf2, 0xf8,
+	0x9f, 0x2a, 0x54, 0x74, 0x63, 0x2f, 0x01, 0xe5, 0x97, 0x00, 0x3a, 0xd4, 0xc3, 0x6e, 0xd1, 0xea,
+	0x70, 0x0e, 0x16, 0xca, 0x25, 0x72, 0x6f, 0xa1, 0x1f, 0xe1, 0x5e, 0xe1, 0x70, 0x45, 0x0f, 0x66,
+	0x86, 0x0b, 0x36, 0x87, 0xf3, 0xce, 0x32, 0x15, 0xed, 0xfe, 0x27, 0x83, 0xc8, 0x64, 0x1a, 0xb6,
+	0x7d, 0xbd, 0xf5, 0xd2, 0xfe, 0x17, 0x4c, 0x60, 0xa7, 0x58, 0x25, 0x8d, 0x36, 0x7c, 0x0b, 0x9d,
+	0x00, 0xcc, 0x76, 0x29, 0xda, 0xd3, 0x70, 0x9b, 0xdf, 0xb8, 0xce, 0x6e, 0x8e, 0xaf, 0xf3, 0x9b,
+	0xc0, 0xfd, 0xa5, 0x1f, 0x53, 0xe8, 0xb1, 0x32, 0x5c, 0xe5, 0xa3, 0xd6, 0x79, 0x7f, 0x15, 0x55,
+	0x1d, 0xb6, 0x07, 0x07, 0x45, 0x63, 0x8a, 0xf5, 0x45, 0x10, 0x72, 0x16, 0xa1, 0x86, 0xae, 0x7c,
+	0xf1, 0x10, 0x74, 0x0e, 0x97, 0x68, 0x24, 0x31, 0x8a, 0x46, 0xd6, 0x5c, 0x8c, 0x25, 0x03, 0xd1,
+	0x39, 0x5c, 0xa2, 0xa1, 0x63, 0x7c, 0x0f, 0xf5, 0xdc, 0xc0, 0x41, 0xf7, 0x67, 0xd7, 0x56, 0x30,
+	0x10, 0x9d, 0xfd, 0x45, 0x62, 0xed, 0xf2, 0x1c, 0x60, 0x36, 0x5b, 0xd2, 0xbe, 0x0a, 0xc6, 0x93,
+	0x93, 0x17, 0xcf, 0xa1, 0xa3, 0x05, 0x5b, 0xe9, 0x77, 0x8e, 0x6c, 0x65, 0x50, 0x30, 0x79, 0x9c,
+	0xbd, 0x02, 0x89, 0xca, 0xa8, 0x69, 0xa1, 0x10, 0x0e, 0x96, 0x7d, 0x1c, 0xa2, 0xa6, 0x4e, 0xe3,
+	0xf5, 0x1f, 0xb6, 0xce, 0x7b, 0x2b, 0x68, 0xea, 0x3e, 0x9c, 0x00, 0xcc, 0xa6, 0x84, 0xc1, 0x75,
+	0x6e, 0x0e, 0x39, 0xbb, 0x39, 0xbe, 0xb2, 0xee, 0xad, 0xab, 0x1f, 0xb2, 0xcf, 0xfe, 0x0b, 0x00,
+	0x00, 0xff, 0xff, 0x2e, 0x99, 0x51, 0x59, 0xbd, 0x0d, 0x00, 0x00,
 }
@@ -34,7 +34,7 @@ var _ = Describe("prepare shutdown-clusters", func() {
 
 	It("updates status PENDING, RUNNING then COMPLETE if successful", func() {
 		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-			Statuses: []string{},
+			Statuses: []*pb.ConversionStatus{},
 		}
 
 		Expect(cm.IsPending(upgradestatus.SHUTDOWN_CLUSTERS)).To(BeTrue())
@@ -55,7 +55,7 @@ var _ = Describe("prepare shutdown-clusters", func() {
 
 	It("updates status to FAILED if it fails to run", func() {
 		mockAgent.StatusConversionResponse = &pb.CheckConversionStatusReply{
-			Statuses: []string{},
+			Statuses: []*pb.ConversionStatus{},
 		}
 
 		Expect(cm.IsPending(upgradestatus.SHUTDOWN_CLUSTERS)).To(BeTrue())
@@ -38,9 +38,9 @@ var _ = Describe("upgrade convert primaries", func() {
 
 	// Move this elsewhere; it's not testing what's useful anymore.
 	XIt("updates status PENDING to RUNNING then to COMPLETE if successful", func() {
-		utils.System.RunCommandAsync = func(cmdStr string, logFile string) error {
+		utils.System.RunCommandAsync = func(cmdStr string, logFile string) (int, error) {
 			_, err := agentExecutor.ExecuteLocalCommand(cmdStr)
-			return err
+			return 0, err
 		}
 
 		cm.AddStep(upgradestatus.CONVERT_PRIMARIES, pb.UpgradeSteps_CONVERT_PRIMARIES)
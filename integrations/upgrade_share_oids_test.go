@@ -1,7 +1,9 @@
 package integrations_test
 
 import (
-	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 
@@ -15,24 +17,32 @@ var _ = Describe("upgrade share oids", func() {
 		go agent.Start()
 	})
 	It("updates status PENDING to RUNNING then to COMPLETE if successful", func() {
+		oidDir := filepath.Join(testStateDir, "pg_upgrade")
+		Expect(os.MkdirAll(oidDir, 0700)).To(Succeed())
+		oidFile := filepath.Join(oidDir, "pg_upgrade_dump_1_oids.sql")
+		Expect(ioutil.WriteFile(oidFile, []byte("oids"), 0600)).To(Succeed())
 
 		Expect(cm.IsPending(upgradestatus.SHARE_OIDS)).To(BeTrue())
 
 		upgradeShareOidsSession := runCommand("upgrade", "share-oids")
 		Eventually(upgradeShareOidsSession).Should(Exit(0))
 
-		Expect(testExecutor.LocalCommands[0]).To(ContainSubstring("rsync"))
-		Expect(cm.IsComplete(upgradestatus.SHARE_OIDS)).To(BeTrue())
+		Eventually(func() bool { return cm.IsComplete(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
 
+		// The hub and the agent share a state directory here, so the agent
+		// has written the file back over itself.
+		contents, err := ioutil.ReadFile(oidFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("oids"))
 	})
 
 	It("updates status to FAILED if it fails to run", func() {
+		Expect(os.RemoveAll(filepath.Join(testStateDir, "pg_upgrade"))).To(Succeed())
 
 		Expect(cm.IsPending(upgradestatus.SHARE_OIDS)).To(BeTrue())
-		testExecutor.LocalError = errors.New("fake test error, share oid failed to send files")
 
 		upgradeShareOidsSession := runCommand("upgrade", "share-oids")
 		Eventually(upgradeShareOidsSession).Should(Exit(0))
-		Expect(cm.IsFailed(upgradestatus.SHARE_OIDS)).To(BeTrue())
+		Eventually(func() bool { return cm.IsFailed(upgradestatus.SHARE_OIDS) }).Should(BeTrue())
 	})
 })
@@ -11,6 +11,7 @@ import (
 	idl "github.com/greenplum-db/gpupgrade/idl"
 	context "golang.org/x/net/context"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
 // MockCliToHubClient is a mock of CliToHubClient interface
@@ -72,6 +73,24 @@ func (mr *MockCliToHubClientMockRecorder) StatusUpgrade(ctx, in interface{}, opt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusUpgrade", reflect.TypeOf((*MockCliToHubClient)(nil).StatusUpgrade), varargs...)
 }
 
+// WatchUpgrade mocks base method
+func (m *MockCliToHubClient) WatchUpgrade(ctx context.Context, in *idl.WatchUpgradeRequest, opts ...grpc.CallOption) (idl.CliToHub_WatchUpgradeClient, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchUpgrade", varargs...)
+	ret0, _ := ret[0].(idl.CliToHub_WatchUpgradeClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchUpgrade indicates an expected call of WatchUpgrade
+func (mr *MockCliToHubClientMockRecorder) WatchUpgrade(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchUpgrade", reflect.TypeOf((*MockCliToHubClient)(nil).WatchUpgrade), varargs...)
+}
+
 // StatusConversion mocks base method
 func (m *MockCliToHubClient) StatusConversion(ctx context.Context, in *idl.StatusConversionRequest, opts ...grpc.CallOption) (*idl.StatusConversionReply, error) {
 	varargs := []interface{}{ctx, in}
@@ -90,6 +109,24 @@ func (mr *MockCliToHubClientMockRecorder) StatusConversion(ctx, in interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusConversion", reflect.TypeOf((*MockCliToHubClient)(nil).StatusConversion), varargs...)
 }
 
+// StatusAgents mocks base method
+func (m *MockCliToHubClient) StatusAgents(ctx context.Context, in *idl.StatusAgentsRequest, opts ...grpc.CallOption) (*idl.StatusAgentsReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StatusAgents", varargs...)
+	ret0, _ := ret[0].(*idl.StatusAgentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatusAgents indicates an expected call of StatusAgents
+func (mr *MockCliToHubClientMockRecorder) StatusAgents(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusAgents", reflect.TypeOf((*MockCliToHubClient)(nil).StatusAgents), varargs...)
+}
+
 // CheckConfig mocks base method
 func (m *MockCliToHubClient) CheckConfig(ctx context.Context, in *idl.CheckConfigRequest, opts ...grpc.CallOption) (*idl.CheckConfigReply, error) {
 	varargs := []interface{}{ctx, in}
@@ -144,6 +181,24 @@ func (mr *MockCliToHubClientMockRecorder) CheckObjectCount(ctx, in interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckObjectCount", reflect.TypeOf((*MockCliToHubClient)(nil).CheckObjectCount), varargs...)
 }
 
+// CheckCatalog mocks base method
+func (m *MockCliToHubClient) CheckCatalog(ctx context.Context, in *idl.CheckCatalogRequest, opts ...grpc.CallOption) (*idl.CheckCatalogReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckCatalog", varargs...)
+	ret0, _ := ret[0].(*idl.CheckCatalogReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckCatalog indicates an expected call of CheckCatalog
+func (mr *MockCliToHubClientMockRecorder) CheckCatalog(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckCatalog", reflect.TypeOf((*MockCliToHubClient)(nil).CheckCatalog), varargs...)
+}
+
 // CheckVersion mocks base method
 func (m *MockCliToHubClient) CheckVersion(ctx context.Context, in *idl.CheckVersionRequest, opts ...grpc.CallOption) (*idl.CheckVersionReply, error) {
 	varargs := []interface{}{ctx, in}
@@ -180,6 +235,24 @@ func (mr *MockCliToHubClientMockRecorder) CheckDiskSpace(ctx, in interface{}, op
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDiskSpace", reflect.TypeOf((*MockCliToHubClient)(nil).CheckDiskSpace), varargs...)
 }
 
+// CheckPorts mocks base method
+func (m *MockCliToHubClient) CheckPorts(ctx context.Context, in *idl.CheckPortsRequest, opts ...grpc.CallOption) (*idl.CheckPortsReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckPorts", varargs...)
+	ret0, _ := ret[0].(*idl.CheckPortsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPorts indicates an expected call of CheckPorts
+func (mr *MockCliToHubClientMockRecorder) CheckPorts(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPorts", reflect.TypeOf((*MockCliToHubClient)(nil).CheckPorts), varargs...)
+}
+
 // PrepareInitCluster mocks base method
 func (m *MockCliToHubClient) PrepareInitCluster(ctx context.Context, in *idl.PrepareInitClusterRequest, opts ...grpc.CallOption) (*idl.PrepareInitClusterReply, error) {
 	varargs := []interface{}{ctx, in}
@@ -306,6 +379,42 @@ func (mr *MockCliToHubClientMockRecorder) UpgradeConvertPrimaries(ctx, in interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeConvertPrimaries", reflect.TypeOf((*MockCliToHubClient)(nil).UpgradeConvertPrimaries), varargs...)
 }
 
+// UpgradeRebuildMirrors mocks base method
+func (m *MockCliToHubClient) UpgradeRebuildMirrors(ctx context.Context, in *idl.UpgradeRebuildMirrorsRequest, opts ...grpc.CallOption) (*idl.UpgradeRebuildMirrorsReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpgradeRebuildMirrors", varargs...)
+	ret0, _ := ret[0].(*idl.UpgradeRebuildMirrorsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeRebuildMirrors indicates an expected call of UpgradeRebuildMirrors
+func (mr *MockCliToHubClientMockRecorder) UpgradeRebuildMirrors(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeRebuildMirrors", reflect.TypeOf((*MockCliToHubClient)(nil).UpgradeRebuildMirrors), varargs...)
+}
+
+// UpgradeRebuildStandby mocks base method
+func (m *MockCliToHubClient) UpgradeRebuildStandby(ctx context.Context, in *idl.UpgradeRebuildStandbyRequest, opts ...grpc.CallOption) (*idl.UpgradeRebuildStandbyReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpgradeRebuildStandby", varargs...)
+	ret0, _ := ret[0].(*idl.UpgradeRebuildStandbyReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeRebuildStandby indicates an expected call of UpgradeRebuildStandby
+func (mr *MockCliToHubClientMockRecorder) UpgradeRebuildStandby(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeRebuildStandby", reflect.TypeOf((*MockCliToHubClient)(nil).UpgradeRebuildStandby), varargs...)
+}
+
 // UpgradeReconfigurePorts mocks base method
 func (m *MockCliToHubClient) UpgradeReconfigurePorts(ctx context.Context, in *idl.UpgradeReconfigurePortsRequest, opts ...grpc.CallOption) (*idl.UpgradeReconfigurePortsReply, error) {
 	varargs := []interface{}{ctx, in}
@@ -360,6 +469,189 @@ func (mr *MockCliToHubClientMockRecorder) GetConfig(ctx, in interface{}, opts ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockCliToHubClient)(nil).GetConfig), varargs...)
 }
 
+// Run mocks base method
+func (m *MockCliToHubClient) Run(ctx context.Context, in *idl.RunRequest, opts ...grpc.CallOption) (*idl.RunReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Run", varargs...)
+	ret0, _ := ret[0].(*idl.RunReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run
+func (mr *MockCliToHubClientMockRecorder) Run(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockCliToHubClient)(nil).Run), varargs...)
+}
+
+// Resume mocks base method
+func (m *MockCliToHubClient) Resume(ctx context.Context, in *idl.ResumeRequest, opts ...grpc.CallOption) (*idl.ResumeReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Resume", varargs...)
+	ret0, _ := ret[0].(*idl.ResumeReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resume indicates an expected call of Resume
+func (mr *MockCliToHubClientMockRecorder) Resume(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockCliToHubClient)(nil).Resume), varargs...)
+}
+
+// Revert mocks base method
+func (m *MockCliToHubClient) Revert(ctx context.Context, in *idl.RevertRequest, opts ...grpc.CallOption) (*idl.RevertReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Revert", varargs...)
+	ret0, _ := ret[0].(*idl.RevertReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert
+func (mr *MockCliToHubClientMockRecorder) Revert(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockCliToHubClient)(nil).Revert), varargs...)
+}
+
+// Cancel mocks base method
+func (m *MockCliToHubClient) Cancel(ctx context.Context, in *idl.CancelRequest, opts ...grpc.CallOption) (*idl.CancelReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Cancel", varargs...)
+	ret0, _ := ret[0].(*idl.CancelReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockCliToHubClientMockRecorder) Cancel(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockCliToHubClient)(nil).Cancel), varargs...)
+}
+
+// MockCliToHub_WatchUpgradeClient is a mock of CliToHub_WatchUpgradeClient interface
+type MockCliToHub_WatchUpgradeClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockCliToHub_WatchUpgradeClientMockRecorder
+}
+
+// MockCliToHub_WatchUpgradeClientMockRecorder is the mock recorder for MockCliToHub_WatchUpgradeClient
+type MockCliToHub_WatchUpgradeClientMockRecorder struct {
+	mock *MockCliToHub_WatchUpgradeClient
+}
+
+// NewMockCliToHub_WatchUpgradeClient creates a new mock instance
+func NewMockCliToHub_WatchUpgradeClient(ctrl *gomock.Controller) *MockCliToHub_WatchUpgradeClient {
+	mock := &MockCliToHub_WatchUpgradeClient{ctrl: ctrl}
+	mock.recorder = &MockCliToHub_WatchUpgradeClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCliToHub_WatchUpgradeClient) EXPECT() *MockCliToHub_WatchUpgradeClientMockRecorder {
+	return m.recorder
+}
+
+// Recv mocks base method
+func (m *MockCliToHub_WatchUpgradeClient) Recv() (*idl.WatchUpgradeReply, error) {
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*idl.WatchUpgradeReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockCliToHub_WatchUpgradeClientMockRecorder) Recv() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockCliToHub_WatchUpgradeClient)(nil).Recv))
+}
+
+// CloseSend mocks base method
+func (m *MockCliToHub_WatchUpgradeClient) CloseSend() error {
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockCliToHub_WatchUpgradeClientMockRecorder) CloseSend() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockCliToHub_WatchUpgradeClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockCliToHub_WatchUpgradeClient) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockCliToHub_WatchUpgradeClientMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockCliToHub_WatchUpgradeClient)(nil).Context))
+}
+
+// Header mocks base method
+func (m *MockCliToHub_WatchUpgradeClient) Header() (metadata.MD, error) {
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockCliToHub_WatchUpgradeClientMockRecorder) Header() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockCliToHub_WatchUpgradeClient)(nil).Header))
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockCliToHub_WatchUpgradeClient) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockCliToHub_WatchUpgradeClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockCliToHub_WatchUpgradeClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method
+func (m_2 *MockCliToHub_WatchUpgradeClient) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockCliToHub_WatchUpgradeClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockCliToHub_WatchUpgradeClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method
+func (m *MockCliToHub_WatchUpgradeClient) Trailer() metadata.MD {
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockCliToHub_WatchUpgradeClientMockRecorder) Trailer() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockCliToHub_WatchUpgradeClient)(nil).Trailer))
+}
+
 // MockCliToHubServer is a mock of CliToHubServer interface
 type MockCliToHubServer struct {
 	ctrl     *gomock.Controller
@@ -409,6 +701,18 @@ func (mr *MockCliToHubServerMockRecorder) StatusUpgrade(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusUpgrade", reflect.TypeOf((*MockCliToHubServer)(nil).StatusUpgrade), arg0, arg1)
 }
 
+// WatchUpgrade mocks base method
+func (m *MockCliToHubServer) WatchUpgrade(arg0 *idl.WatchUpgradeRequest, arg1 idl.CliToHub_WatchUpgradeServer) error {
+	ret := m.ctrl.Call(m, "WatchUpgrade", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchUpgrade indicates an expected call of WatchUpgrade
+func (mr *MockCliToHubServerMockRecorder) WatchUpgrade(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchUpgrade", reflect.TypeOf((*MockCliToHubServer)(nil).WatchUpgrade), arg0, arg1)
+}
+
 // StatusConversion mocks base method
 func (m *MockCliToHubServer) StatusConversion(arg0 context.Context, arg1 *idl.StatusConversionRequest) (*idl.StatusConversionReply, error) {
 	ret := m.ctrl.Call(m, "StatusConversion", arg0, arg1)
@@ -422,6 +726,19 @@ func (mr *MockCliToHubServerMockRecorder) StatusConversion(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusConversion", reflect.TypeOf((*MockCliToHubServer)(nil).StatusConversion), arg0, arg1)
 }
 
+// StatusAgents mocks base method
+func (m *MockCliToHubServer) StatusAgents(arg0 context.Context, arg1 *idl.StatusAgentsRequest) (*idl.StatusAgentsReply, error) {
+	ret := m.ctrl.Call(m, "StatusAgents", arg0, arg1)
+	ret0, _ := ret[0].(*idl.StatusAgentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatusAgents indicates an expected call of StatusAgents
+func (mr *MockCliToHubServerMockRecorder) StatusAgents(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusAgents", reflect.TypeOf((*MockCliToHubServer)(nil).StatusAgents), arg0, arg1)
+}
+
 // CheckConfig mocks base method
 func (m *MockCliToHubServer) CheckConfig(arg0 context.Context, arg1 *idl.CheckConfigRequest) (*idl.CheckConfigReply, error) {
 	ret := m.ctrl.Call(m, "CheckConfig", arg0, arg1)
@@ -461,6 +778,19 @@ func (mr *MockCliToHubServerMockRecorder) CheckObjectCount(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckObjectCount", reflect.TypeOf((*MockCliToHubServer)(nil).CheckObjectCount), arg0, arg1)
 }
 
+// CheckCatalog mocks base method
+func (m *MockCliToHubServer) CheckCatalog(arg0 context.Context, arg1 *idl.CheckCatalogRequest) (*idl.CheckCatalogReply, error) {
+	ret := m.ctrl.Call(m, "CheckCatalog", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CheckCatalogReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckCatalog indicates an expected call of CheckCatalog
+func (mr *MockCliToHubServerMockRecorder) CheckCatalog(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckCatalog", reflect.TypeOf((*MockCliToHubServer)(nil).CheckCatalog), arg0, arg1)
+}
+
 // CheckVersion mocks base method
 func (m *MockCliToHubServer) CheckVersion(arg0 context.Context, arg1 *idl.CheckVersionRequest) (*idl.CheckVersionReply, error) {
 	ret := m.ctrl.Call(m, "CheckVersion", arg0, arg1)
@@ -487,6 +817,19 @@ func (mr *MockCliToHubServerMockRecorder) CheckDiskSpace(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDiskSpace", reflect.TypeOf((*MockCliToHubServer)(nil).CheckDiskSpace), arg0, arg1)
 }
 
+// CheckPorts mocks base method
+func (m *MockCliToHubServer) CheckPorts(arg0 context.Context, arg1 *idl.CheckPortsRequest) (*idl.CheckPortsReply, error) {
+	ret := m.ctrl.Call(m, "CheckPorts", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CheckPortsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPorts indicates an expected call of CheckPorts
+func (mr *MockCliToHubServerMockRecorder) CheckPorts(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPorts", reflect.TypeOf((*MockCliToHubServer)(nil).CheckPorts), arg0, arg1)
+}
+
 // PrepareInitCluster mocks base method
 func (m *MockCliToHubServer) PrepareInitCluster(arg0 context.Context, arg1 *idl.PrepareInitClusterRequest) (*idl.PrepareInitClusterReply, error) {
 	ret := m.ctrl.Call(m, "PrepareInitCluster", arg0, arg1)
@@ -578,6 +921,32 @@ func (mr *MockCliToHubServerMockRecorder) UpgradeConvertPrimaries(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeConvertPrimaries", reflect.TypeOf((*MockCliToHubServer)(nil).UpgradeConvertPrimaries), arg0, arg1)
 }
 
+// UpgradeRebuildMirrors mocks base method
+func (m *MockCliToHubServer) UpgradeRebuildMirrors(arg0 context.Context, arg1 *idl.UpgradeRebuildMirrorsRequest) (*idl.UpgradeRebuildMirrorsReply, error) {
+	ret := m.ctrl.Call(m, "UpgradeRebuildMirrors", arg0, arg1)
+	ret0, _ := ret[0].(*idl.UpgradeRebuildMirrorsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeRebuildMirrors indicates an expected call of UpgradeRebuildMirrors
+func (mr *MockCliToHubServerMockRecorder) UpgradeRebuildMirrors(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeRebuildMirrors", reflect.TypeOf((*MockCliToHubServer)(nil).UpgradeRebuildMirrors), arg0, arg1)
+}
+
+// UpgradeRebuildStandby mocks base method
+func (m *MockCliToHubServer) UpgradeRebuildStandby(arg0 context.Context, arg1 *idl.UpgradeRebuildStandbyRequest) (*idl.UpgradeRebuildStandbyReply, error) {
+	ret := m.ctrl.Call(m, "UpgradeRebuildStandby", arg0, arg1)
+	ret0, _ := ret[0].(*idl.UpgradeRebuildStandbyReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpgradeRebuildStandby indicates an expected call of UpgradeRebuildStandby
+func (mr *MockCliToHubServerMockRecorder) UpgradeRebuildStandby(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeRebuildStandby", reflect.TypeOf((*MockCliToHubServer)(nil).UpgradeRebuildStandby), arg0, arg1)
+}
+
 // UpgradeReconfigurePorts mocks base method
 func (m *MockCliToHubServer) UpgradeReconfigurePorts(arg0 context.Context, arg1 *idl.UpgradeReconfigurePortsRequest) (*idl.UpgradeReconfigurePortsReply, error) {
 	ret := m.ctrl.Call(m, "UpgradeReconfigurePorts", arg0, arg1)
@@ -616,3 +985,162 @@ func (m *MockCliToHubServer) GetConfig(arg0 context.Context, arg1 *idl.GetConfig
 func (mr *MockCliToHubServerMockRecorder) GetConfig(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockCliToHubServer)(nil).GetConfig), arg0, arg1)
 }
+
+// Run mocks base method
+func (m *MockCliToHubServer) Run(arg0 context.Context, arg1 *idl.RunRequest) (*idl.RunReply, error) {
+	ret := m.ctrl.Call(m, "Run", arg0, arg1)
+	ret0, _ := ret[0].(*idl.RunReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run
+func (mr *MockCliToHubServerMockRecorder) Run(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockCliToHubServer)(nil).Run), arg0, arg1)
+}
+
+// Resume mocks base method
+func (m *MockCliToHubServer) Resume(arg0 context.Context, arg1 *idl.ResumeRequest) (*idl.ResumeReply, error) {
+	ret := m.ctrl.Call(m, "Resume", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ResumeReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resume indicates an expected call of Resume
+func (mr *MockCliToHubServerMockRecorder) Resume(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockCliToHubServer)(nil).Resume), arg0, arg1)
+}
+
+// Revert mocks base method
+func (m *MockCliToHubServer) Revert(arg0 context.Context, arg1 *idl.RevertRequest) (*idl.RevertReply, error) {
+	ret := m.ctrl.Call(m, "Revert", arg0, arg1)
+	ret0, _ := ret[0].(*idl.RevertReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert
+func (mr *MockCliToHubServerMockRecorder) Revert(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockCliToHubServer)(nil).Revert), arg0, arg1)
+}
+
+// Cancel mocks base method
+func (m *MockCliToHubServer) Cancel(arg0 context.Context, arg1 *idl.CancelRequest) (*idl.CancelReply, error) {
+	ret := m.ctrl.Call(m, "Cancel", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CancelReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockCliToHubServerMockRecorder) Cancel(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockCliToHubServer)(nil).Cancel), arg0, arg1)
+}
+
+// MockCliToHub_WatchUpgradeServer is a mock of CliToHub_WatchUpgradeServer interface
+type MockCliToHub_WatchUpgradeServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCliToHub_WatchUpgradeServerMockRecorder
+}
+
+// MockCliToHub_WatchUpgradeServerMockRecorder is the mock recorder for MockCliToHub_WatchUpgradeServer
+type MockCliToHub_WatchUpgradeServerMockRecorder struct {
+	mock *MockCliToHub_WatchUpgradeServer
+}
+
+// NewMockCliToHub_WatchUpgradeServer creates a new mock instance
+func NewMockCliToHub_WatchUpgradeServer(ctrl *gomock.Controller) *MockCliToHub_WatchUpgradeServer {
+	mock := &MockCliToHub_WatchUpgradeServer{ctrl: ctrl}
+	mock.recorder = &MockCliToHub_WatchUpgradeServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCliToHub_WatchUpgradeServer) EXPECT() *MockCliToHub_WatchUpgradeServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method
+func (m *MockCliToHub_WatchUpgradeServer) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockCliToHub_WatchUpgradeServerMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockCliToHub_WatchUpgradeServer)(nil).Context))
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockCliToHub_WatchUpgradeServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send mocks base method
+func (m *MockCliToHub_WatchUpgradeServer) Send(arg0 *idl.WatchUpgradeReply) error {
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockCliToHub_WatchUpgradeServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockCliToHub_WatchUpgradeServer)(nil).Send), arg0)
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockCliToHub_WatchUpgradeServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockCliToHub_WatchUpgradeServer)(nil).RecvMsg), m)
+}
+
+// SendHeader mocks base method
+func (m *MockCliToHub_WatchUpgradeServer) SendHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader
+func (mr *MockCliToHub_WatchUpgradeServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockCliToHub_WatchUpgradeServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method
+func (m_2 *MockCliToHub_WatchUpgradeServer) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockCliToHub_WatchUpgradeServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockCliToHub_WatchUpgradeServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method
+func (m *MockCliToHub_WatchUpgradeServer) SetHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader
+func (mr *MockCliToHub_WatchUpgradeServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockCliToHub_WatchUpgradeServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method
+func (m *MockCliToHub_WatchUpgradeServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer
+func (mr *MockCliToHub_WatchUpgradeServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockCliToHub_WatchUpgradeServer)(nil).SetTrailer), arg0)
+}
@@ -11,6 +11,7 @@ import (
 	idl "github.com/greenplum-db/gpupgrade/idl"
 	context "golang.org/x/net/context"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
 // MockAgentClient is a mock of AgentClient interface
@@ -144,6 +145,237 @@ func (mr *MockAgentClientMockRecorder) CreateSegmentDataDirectories(ctx, in inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSegmentDataDirectories", reflect.TypeOf((*MockAgentClient)(nil).CreateSegmentDataDirectories), varargs...)
 }
 
+// DeleteSegmentDataDirectories mocks base method
+func (m *MockAgentClient) DeleteSegmentDataDirectories(ctx context.Context, in *idl.DeleteSegmentDataDirRequest, opts ...grpc.CallOption) (*idl.DeleteSegmentDataDirReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteSegmentDataDirectories", varargs...)
+	ret0, _ := ret[0].(*idl.DeleteSegmentDataDirReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSegmentDataDirectories indicates an expected call of DeleteSegmentDataDirectories
+func (mr *MockAgentClientMockRecorder) DeleteSegmentDataDirectories(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSegmentDataDirectories", reflect.TypeOf((*MockAgentClient)(nil).DeleteSegmentDataDirectories), varargs...)
+}
+
+// CheckTargetLayout mocks base method
+func (m *MockAgentClient) CheckTargetLayout(ctx context.Context, in *idl.CheckTargetLayoutRequest, opts ...grpc.CallOption) (*idl.CheckTargetLayoutReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckTargetLayout", varargs...)
+	ret0, _ := ret[0].(*idl.CheckTargetLayoutReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckTargetLayout indicates an expected call of CheckTargetLayout
+func (mr *MockAgentClientMockRecorder) CheckTargetLayout(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTargetLayout", reflect.TypeOf((*MockAgentClient)(nil).CheckTargetLayout), varargs...)
+}
+
+// CheckPorts mocks base method
+func (m *MockAgentClient) CheckPorts(ctx context.Context, in *idl.CheckPortsRequestToAgent, opts ...grpc.CallOption) (*idl.CheckPortsReplyFromAgent, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckPorts", varargs...)
+	ret0, _ := ret[0].(*idl.CheckPortsReplyFromAgent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPorts indicates an expected call of CheckPorts
+func (mr *MockAgentClientMockRecorder) CheckPorts(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPorts", reflect.TypeOf((*MockAgentClient)(nil).CheckPorts), varargs...)
+}
+
+// ReceiveFiles mocks base method
+func (m *MockAgentClient) ReceiveFiles(ctx context.Context, opts ...grpc.CallOption) (idl.Agent_ReceiveFilesClient, error) {
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReceiveFiles", varargs...)
+	ret0, _ := ret[0].(idl.Agent_ReceiveFilesClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReceiveFiles indicates an expected call of ReceiveFiles
+func (mr *MockAgentClientMockRecorder) ReceiveFiles(ctx interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveFiles", reflect.TypeOf((*MockAgentClient)(nil).ReceiveFiles), varargs...)
+}
+
+// CancelConvertPrimarySegments mocks base method
+func (m *MockAgentClient) CancelConvertPrimarySegments(ctx context.Context, in *idl.CancelConvertPrimarySegmentsRequest, opts ...grpc.CallOption) (*idl.CancelConvertPrimarySegmentsReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CancelConvertPrimarySegments", varargs...)
+	ret0, _ := ret[0].(*idl.CancelConvertPrimarySegmentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelConvertPrimarySegments indicates an expected call of CancelConvertPrimarySegments
+func (mr *MockAgentClientMockRecorder) CancelConvertPrimarySegments(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelConvertPrimarySegments", reflect.TypeOf((*MockAgentClient)(nil).CancelConvertPrimarySegments), varargs...)
+}
+
+// CheckPaths mocks base method
+func (m *MockAgentClient) CheckPaths(ctx context.Context, in *idl.CheckPathsRequest, opts ...grpc.CallOption) (*idl.CheckPathsReply, error) {
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckPaths", varargs...)
+	ret0, _ := ret[0].(*idl.CheckPathsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPaths indicates an expected call of CheckPaths
+func (mr *MockAgentClientMockRecorder) CheckPaths(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPaths", reflect.TypeOf((*MockAgentClient)(nil).CheckPaths), varargs...)
+}
+
+// MockAgent_ReceiveFilesClient is a mock of Agent_ReceiveFilesClient interface
+type MockAgent_ReceiveFilesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_ReceiveFilesClientMockRecorder
+}
+
+// MockAgent_ReceiveFilesClientMockRecorder is the mock recorder for MockAgent_ReceiveFilesClient
+type MockAgent_ReceiveFilesClientMockRecorder struct {
+	mock *MockAgent_ReceiveFilesClient
+}
+
+// NewMockAgent_ReceiveFilesClient creates a new mock instance
+func NewMockAgent_ReceiveFilesClient(ctrl *gomock.Controller) *MockAgent_ReceiveFilesClient {
+	mock := &MockAgent_ReceiveFilesClient{ctrl: ctrl}
+	mock.recorder = &MockAgent_ReceiveFilesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAgent_ReceiveFilesClient) EXPECT() *MockAgent_ReceiveFilesClientMockRecorder {
+	return m.recorder
+}
+
+// CloseAndRecv mocks base method
+func (m *MockAgent_ReceiveFilesClient) CloseAndRecv() (*idl.ReceiveFilesReply, error) {
+	ret := m.ctrl.Call(m, "CloseAndRecv")
+	ret0, _ := ret[0].(*idl.ReceiveFilesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAndRecv indicates an expected call of CloseAndRecv
+func (mr *MockAgent_ReceiveFilesClientMockRecorder) CloseAndRecv() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAndRecv", reflect.TypeOf((*MockAgent_ReceiveFilesClient)(nil).CloseAndRecv))
+}
+
+// CloseSend mocks base method
+func (m *MockAgent_ReceiveFilesClient) CloseSend() error {
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend
+func (mr *MockAgent_ReceiveFilesClientMockRecorder) CloseSend() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockAgent_ReceiveFilesClient)(nil).CloseSend))
+}
+
+// Context mocks base method
+func (m *MockAgent_ReceiveFilesClient) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockAgent_ReceiveFilesClientMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_ReceiveFilesClient)(nil).Context))
+}
+
+// Header mocks base method
+func (m *MockAgent_ReceiveFilesClient) Header() (metadata.MD, error) {
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header
+func (mr *MockAgent_ReceiveFilesClientMockRecorder) Header() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockAgent_ReceiveFilesClient)(nil).Header))
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockAgent_ReceiveFilesClient) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send mocks base method
+func (m *MockAgent_ReceiveFilesClient) Send(arg0 *idl.ReceiveFilesRequest) error {
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockAgent_ReceiveFilesClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockAgent_ReceiveFilesClient)(nil).Send), arg0)
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockAgent_ReceiveFilesClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_ReceiveFilesClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method
+func (m_2 *MockAgent_ReceiveFilesClient) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockAgent_ReceiveFilesClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_ReceiveFilesClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method
+func (m *MockAgent_ReceiveFilesClient) Trailer() metadata.MD {
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer
+func (mr *MockAgent_ReceiveFilesClientMockRecorder) Trailer() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockAgent_ReceiveFilesClient)(nil).Trailer))
+}
+
 // MockAgentServer is a mock of AgentServer interface
 type MockAgentServer struct {
 	ctrl     *gomock.Controller
@@ -244,3 +476,200 @@ func (m *MockAgentServer) CreateSegmentDataDirectories(arg0 context.Context, arg
 func (mr *MockAgentServerMockRecorder) CreateSegmentDataDirectories(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSegmentDataDirectories", reflect.TypeOf((*MockAgentServer)(nil).CreateSegmentDataDirectories), arg0, arg1)
 }
+
+// DeleteSegmentDataDirectories mocks base method
+func (m *MockAgentServer) DeleteSegmentDataDirectories(arg0 context.Context, arg1 *idl.DeleteSegmentDataDirRequest) (*idl.DeleteSegmentDataDirReply, error) {
+	ret := m.ctrl.Call(m, "DeleteSegmentDataDirectories", arg0, arg1)
+	ret0, _ := ret[0].(*idl.DeleteSegmentDataDirReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSegmentDataDirectories indicates an expected call of DeleteSegmentDataDirectories
+func (mr *MockAgentServerMockRecorder) DeleteSegmentDataDirectories(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSegmentDataDirectories", reflect.TypeOf((*MockAgentServer)(nil).DeleteSegmentDataDirectories), arg0, arg1)
+}
+
+// CheckTargetLayout mocks base method
+func (m *MockAgentServer) CheckTargetLayout(arg0 context.Context, arg1 *idl.CheckTargetLayoutRequest) (*idl.CheckTargetLayoutReply, error) {
+	ret := m.ctrl.Call(m, "CheckTargetLayout", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CheckTargetLayoutReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckTargetLayout indicates an expected call of CheckTargetLayout
+func (mr *MockAgentServerMockRecorder) CheckTargetLayout(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTargetLayout", reflect.TypeOf((*MockAgentServer)(nil).CheckTargetLayout), arg0, arg1)
+}
+
+// CheckPorts mocks base method
+func (m *MockAgentServer) CheckPorts(arg0 context.Context, arg1 *idl.CheckPortsRequestToAgent) (*idl.CheckPortsReplyFromAgent, error) {
+	ret := m.ctrl.Call(m, "CheckPorts", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CheckPortsReplyFromAgent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPorts indicates an expected call of CheckPorts
+func (mr *MockAgentServerMockRecorder) CheckPorts(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPorts", reflect.TypeOf((*MockAgentServer)(nil).CheckPorts), arg0, arg1)
+}
+
+// ReceiveFiles mocks base method
+func (m *MockAgentServer) ReceiveFiles(arg0 idl.Agent_ReceiveFilesServer) error {
+	ret := m.ctrl.Call(m, "ReceiveFiles", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReceiveFiles indicates an expected call of ReceiveFiles
+func (mr *MockAgentServerMockRecorder) ReceiveFiles(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveFiles", reflect.TypeOf((*MockAgentServer)(nil).ReceiveFiles), arg0)
+}
+
+// CancelConvertPrimarySegments mocks base method
+func (m *MockAgentServer) CancelConvertPrimarySegments(arg0 context.Context, arg1 *idl.CancelConvertPrimarySegmentsRequest) (*idl.CancelConvertPrimarySegmentsReply, error) {
+	ret := m.ctrl.Call(m, "CancelConvertPrimarySegments", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CancelConvertPrimarySegmentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelConvertPrimarySegments indicates an expected call of CancelConvertPrimarySegments
+func (mr *MockAgentServerMockRecorder) CancelConvertPrimarySegments(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelConvertPrimarySegments", reflect.TypeOf((*MockAgentServer)(nil).CancelConvertPrimarySegments), arg0, arg1)
+}
+
+// CheckPaths mocks base method
+func (m *MockAgentServer) CheckPaths(arg0 context.Context, arg1 *idl.CheckPathsRequest) (*idl.CheckPathsReply, error) {
+	ret := m.ctrl.Call(m, "CheckPaths", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CheckPathsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPaths indicates an expected call of CheckPaths
+func (mr *MockAgentServerMockRecorder) CheckPaths(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPaths", reflect.TypeOf((*MockAgentServer)(nil).CheckPaths), arg0, arg1)
+}
+
+// MockAgent_ReceiveFilesServer is a mock of Agent_ReceiveFilesServer interface
+type MockAgent_ReceiveFilesServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_ReceiveFilesServerMockRecorder
+}
+
+// MockAgent_ReceiveFilesServerMockRecorder is the mock recorder for MockAgent_ReceiveFilesServer
+type MockAgent_ReceiveFilesServerMockRecorder struct {
+	mock *MockAgent_ReceiveFilesServer
+}
+
+// NewMockAgent_ReceiveFilesServer creates a new mock instance
+func NewMockAgent_ReceiveFilesServer(ctrl *gomock.Controller) *MockAgent_ReceiveFilesServer {
+	mock := &MockAgent_ReceiveFilesServer{ctrl: ctrl}
+	mock.recorder = &MockAgent_ReceiveFilesServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAgent_ReceiveFilesServer) EXPECT() *MockAgent_ReceiveFilesServerMockRecorder {
+	return m.recorder
+}
+
+// Recv mocks base method
+func (m *MockAgent_ReceiveFilesServer) Recv() (*idl.ReceiveFilesRequest, error) {
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*idl.ReceiveFilesRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv
+func (mr *MockAgent_ReceiveFilesServerMockRecorder) Recv() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockAgent_ReceiveFilesServer)(nil).Recv))
+}
+
+// Context mocks base method
+func (m *MockAgent_ReceiveFilesServer) Context() context.Context {
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context
+func (mr *MockAgent_ReceiveFilesServerMockRecorder) Context() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_ReceiveFilesServer)(nil).Context))
+}
+
+// RecvMsg mocks base method
+func (m_2 *MockAgent_ReceiveFilesServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAndClose mocks base method
+func (m *MockAgent_ReceiveFilesServer) SendAndClose(arg0 *idl.ReceiveFilesReply) error {
+	ret := m.ctrl.Call(m, "SendAndClose", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAndClose indicates an expected call of SendAndClose
+func (mr *MockAgent_ReceiveFilesServerMockRecorder) SendAndClose(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAndClose", reflect.TypeOf((*MockAgent_ReceiveFilesServer)(nil).SendAndClose), arg0)
+}
+
+// RecvMsg indicates an expected call of RecvMsg
+func (mr *MockAgent_ReceiveFilesServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_ReceiveFilesServer)(nil).RecvMsg), m)
+}
+
+// SendHeader mocks base method
+func (m *MockAgent_ReceiveFilesServer) SendHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader
+func (mr *MockAgent_ReceiveFilesServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockAgent_ReceiveFilesServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method
+func (m_2 *MockAgent_ReceiveFilesServer) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg
+func (mr *MockAgent_ReceiveFilesServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_ReceiveFilesServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method
+func (m *MockAgent_ReceiveFilesServer) SetHeader(arg0 metadata.MD) error {
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader
+func (mr *MockAgent_ReceiveFilesServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockAgent_ReceiveFilesServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method
+func (m *MockAgent_ReceiveFilesServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer
+func (mr *MockAgent_ReceiveFilesServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockAgent_ReceiveFilesServer)(nil).SetTrailer), arg0)
+}
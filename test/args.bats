@@ -4,7 +4,7 @@
     run gpupgrade
 
     [ "$status" -eq 1 ]
-    [[ "$output" = *"Please specify one command of: check, config, prepare, status, upgrade, or version"* ]]
+    [[ "$output" = *"Please specify one command of: check, config, prepare, resume, revert, run, status, upgrade, or version"* ]]
 }
 
 @test "gpupgrade subcommands fail when passed insufficient arguments" {
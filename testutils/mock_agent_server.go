@@ -2,7 +2,9 @@ package testutils
 
 import (
 	"context"
+	"io"
 	"net"
+	"strconv"
 	"sync"
 
 	pb "github.com/greenplum-db/gpupgrade/idl"
@@ -16,16 +18,36 @@ type MockAgentServer struct {
 	numCalls   int
 	mu         sync.Mutex
 
-	StatusConversionRequest              *pb.CheckConversionStatusRequest
-	StatusConversionResponse             *pb.CheckConversionStatusReply
-	UpgradeConvertPrimarySegmentsRequest *pb.UpgradeConvertPrimarySegmentsRequest
-	CreateSegmentDataDirRequest          *pb.CreateSegmentDataDirRequest
+	StatusConversionRequest               *pb.CheckConversionStatusRequest
+	StatusConversionResponse              *pb.CheckConversionStatusReply
+	UpgradeConvertPrimarySegmentsRequest  *pb.UpgradeConvertPrimarySegmentsRequest
+	UpgradeConvertPrimarySegmentsResponse *pb.UpgradeConvertPrimarySegmentsReply
+	CancelConvertPrimarySegmentsRequest   *pb.CancelConvertPrimarySegmentsRequest
+	CancelConvertPrimarySegmentsResponse  *pb.CancelConvertPrimarySegmentsReply
+	CreateSegmentDataDirRequest           *pb.CreateSegmentDataDirRequest
+	DeleteSegmentDataDirRequest           *pb.DeleteSegmentDataDirRequest
+	CheckTargetLayoutRequest              *pb.CheckTargetLayoutRequest
+	CheckTargetLayoutResponse             *pb.CheckTargetLayoutReply
+	CheckPortsRequest                     *pb.CheckPortsRequestToAgent
+	CheckPortsResponse                    *pb.CheckPortsReplyFromAgent
+	CheckDiskSpaceRequest                 *pb.CheckDiskSpaceRequestToAgent
+	CheckDiskSpaceResponse                *pb.CheckDiskSpaceReplyFromAgent
+	CheckPathsRequest                     *pb.CheckPathsRequest
+	CheckPathsResponse                    *pb.CheckPathsReply
+	ReceivedFiles                         map[string][]byte // contents by path, from ReceiveFiles
 
 	Err chan error
 }
 
 func NewMockAgentServer() (*MockAgentServer, int) {
-	lis, err := net.Listen("tcp", "localhost:0")
+	mockServer := NewMockAgentServerOnPort(0)
+	return mockServer, mockServer.addr.(*net.TCPAddr).Port
+}
+
+// NewMockAgentServerOnPort starts a mock agent on a particular port, such as
+// the one a stopped mock agent was using, so that it appears to restart.
+func NewMockAgentServerOnPort(port int) *MockAgentServer {
+	lis, err := net.Listen("tcp", "localhost:"+strconv.Itoa(port))
 	if err != nil {
 		panic(err)
 	}
@@ -42,7 +64,7 @@ func NewMockAgentServer() (*MockAgentServer, int) {
 		mockServer.grpcServer.Serve(lis)
 	}()
 
-	return mockServer, lis.Addr().(*net.TCPAddr).Port
+	return mockServer
 }
 
 func (m *MockAgentServer) CheckUpgradeStatus(context.Context, *pb.CheckUpgradeStatusRequest) (*pb.CheckUpgradeStatusReply, error) {
@@ -64,10 +86,22 @@ func (m *MockAgentServer) CheckConversionStatus(ctx context.Context, in *pb.Chec
 	return m.StatusConversionResponse, err
 }
 
-func (m *MockAgentServer) CheckDiskSpaceOnAgents(context.Context, *pb.CheckDiskSpaceRequestToAgent) (*pb.CheckDiskSpaceReplyFromAgent, error) {
+func (m *MockAgentServer) CheckDiskSpaceOnAgents(ctx context.Context, in *pb.CheckDiskSpaceRequestToAgent) (*pb.CheckDiskSpaceReplyFromAgent, error) {
 	m.increaseCalls()
 
-	return &pb.CheckDiskSpaceReplyFromAgent{}, nil
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CheckDiskSpaceRequest = in
+
+	var err error
+	if len(m.Err) != 0 {
+		err = <-m.Err
+	}
+
+	if m.CheckDiskSpaceResponse != nil {
+		return m.CheckDiskSpaceResponse, err
+	}
+	return &pb.CheckDiskSpaceReplyFromAgent{}, err
 }
 
 func (m *MockAgentServer) PingAgents(context.Context, *pb.PingAgentsRequest) (*pb.PingAgentsReply, error) {
@@ -88,9 +122,30 @@ func (m *MockAgentServer) UpgradeConvertPrimarySegments(ctx context.Context, in
 		err = <-m.Err
 	}
 
+	if m.UpgradeConvertPrimarySegmentsResponse != nil {
+		return m.UpgradeConvertPrimarySegmentsResponse, err
+	}
 	return &pb.UpgradeConvertPrimarySegmentsReply{}, err
 }
 
+func (m *MockAgentServer) CancelConvertPrimarySegments(ctx context.Context, in *pb.CancelConvertPrimarySegmentsRequest) (*pb.CancelConvertPrimarySegmentsReply, error) {
+	m.increaseCalls()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CancelConvertPrimarySegmentsRequest = in
+
+	var err error
+	if len(m.Err) != 0 {
+		err = <-m.Err
+	}
+
+	if m.CancelConvertPrimarySegmentsResponse != nil {
+		return m.CancelConvertPrimarySegmentsResponse, err
+	}
+	return &pb.CancelConvertPrimarySegmentsReply{}, err
+}
+
 func (m *MockAgentServer) CreateSegmentDataDirectories(ctx context.Context, in *pb.CreateSegmentDataDirRequest) (*pb.CreateSegmentDataDirReply, error) {
 	m.increaseCalls()
 
@@ -106,6 +161,110 @@ func (m *MockAgentServer) CreateSegmentDataDirectories(ctx context.Context, in *
 	return &pb.CreateSegmentDataDirReply{}, err
 }
 
+func (m *MockAgentServer) DeleteSegmentDataDirectories(ctx context.Context, in *pb.DeleteSegmentDataDirRequest) (*pb.DeleteSegmentDataDirReply, error) {
+	m.increaseCalls()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.DeleteSegmentDataDirRequest = in
+
+	var err error
+	if len(m.Err) != 0 {
+		err = <-m.Err
+	}
+
+	return &pb.DeleteSegmentDataDirReply{}, err
+}
+
+func (m *MockAgentServer) CheckTargetLayout(ctx context.Context, in *pb.CheckTargetLayoutRequest) (*pb.CheckTargetLayoutReply, error) {
+	m.increaseCalls()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CheckTargetLayoutRequest = in
+
+	var err error
+	if len(m.Err) != 0 {
+		err = <-m.Err
+	}
+
+	if m.CheckTargetLayoutResponse != nil {
+		return m.CheckTargetLayoutResponse, err
+	}
+	return &pb.CheckTargetLayoutReply{}, err
+}
+
+func (m *MockAgentServer) CheckPaths(ctx context.Context, in *pb.CheckPathsRequest) (*pb.CheckPathsReply, error) {
+	m.increaseCalls()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CheckPathsRequest = in
+
+	var err error
+	if len(m.Err) != 0 {
+		err = <-m.Err
+	}
+
+	if m.CheckPathsResponse != nil {
+		return m.CheckPathsResponse, err
+	}
+	return &pb.CheckPathsReply{}, err
+}
+
+func (m *MockAgentServer) CheckPorts(ctx context.Context, in *pb.CheckPortsRequestToAgent) (*pb.CheckPortsReplyFromAgent, error) {
+	m.increaseCalls()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CheckPortsRequest = in
+
+	var err error
+	if len(m.Err) != 0 {
+		err = <-m.Err
+	}
+
+	if m.CheckPortsResponse != nil {
+		return m.CheckPortsResponse, err
+	}
+	return &pb.CheckPortsReplyFromAgent{}, err
+}
+
+func (m *MockAgentServer) ReceiveFiles(stream pb.Agent_ReceiveFilesServer) error {
+	m.increaseCalls()
+
+	var path string
+	files := map[string][]byte{}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if chunk.Path != "" {
+			path = chunk.Path
+			files[path] = []byte{}
+		}
+		files[path] = append(files[path], chunk.Data...)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ReceivedFiles = files
+
+	if len(m.Err) != 0 {
+		return <-m.Err
+	}
+
+	reply := &pb.ReceiveFilesReply{}
+	for path := range files {
+		reply.Paths = append(reply.Paths, path)
+	}
+	return stream.SendAndClose(reply)
+}
+
 func (m *MockAgentServer) Stop() {
 	m.grpcServer.Stop()
 }
@@ -1,8 +1,12 @@
 package testutils
 
 import (
+	"sort"
+
 	"github.com/greenplum-db/gpupgrade/hub/upgradestatus"
 	pb "github.com/greenplum-db/gpupgrade/idl"
+
+	"github.com/pkg/errors"
 )
 
 type MockChecklistManager struct {
@@ -10,8 +14,12 @@ type MockChecklistManager struct {
 	mapFailed     map[string]bool
 	mapInProgress map[string]bool
 	mapReset      map[string]bool
+	mapHosts      map[string]map[string]*pb.HostStepStatus
+	mapErrors     map[string]error
+	mapHistory    map[string][]*pb.StepTransition
 	loadedNames   []string
 	loadedCodes   map[string]pb.UpgradeSteps
+	loadedPrereqs map[string][]string
 }
 
 func NewMockChecklistManager() *MockChecklistManager {
@@ -20,8 +28,12 @@ func NewMockChecklistManager() *MockChecklistManager {
 		mapFailed:     make(map[string]bool, 0),
 		mapInProgress: make(map[string]bool, 0),
 		mapReset:      make(map[string]bool, 0),
+		mapHosts:      make(map[string]map[string]*pb.HostStepStatus, 0),
+		mapErrors:     make(map[string]error, 0),
+		mapHistory:    make(map[string][]*pb.StepTransition, 0),
 		loadedNames:   make([]string, 0),
 		loadedCodes:   make(map[string]pb.UpgradeSteps, 0),
+		loadedPrereqs: make(map[string][]string, 0),
 	}
 }
 
@@ -29,9 +41,10 @@ func (cm *MockChecklistManager) GetStepReader(step string) upgradestatus.StateRe
 	return MockStepReader{step: step, code: cm.loadedCodes[step], manager: cm}
 }
 
-func (cm *MockChecklistManager) AddStep(name string, code pb.UpgradeSteps) {
+func (cm *MockChecklistManager) AddStep(name string, code pb.UpgradeSteps, prereqs ...string) {
 	cm.loadedNames = append(cm.loadedNames, name)
 	cm.loadedCodes[name] = code
+	cm.loadedPrereqs[name] = prereqs
 }
 
 // Use AddStep() to store the list of steps that this mock should return from
@@ -44,10 +57,33 @@ func (cm *MockChecklistManager) AllSteps() []upgradestatus.StateReader {
 	return steps
 }
 
+// The mock has no read-only steps, so every step is writable.
+func (cm *MockChecklistManager) WritableSteps() []upgradestatus.StateReader {
+	return cm.AllSteps()
+}
+
+func (cm *MockChecklistManager) GetPrerequisites(step string) []upgradestatus.StateReader {
+	var prereqs []upgradestatus.StateReader
+	for _, name := range cm.loadedPrereqs[step] {
+		prereqs = append(prereqs, cm.GetStepReader(name))
+	}
+	return prereqs
+}
+
 func (cm *MockChecklistManager) GetStepWriter(step string) upgradestatus.StateWriter {
 	return MockStepWriter{step: step, manager: cm}
 }
 
+func (cm *MockChecklistManager) ResetStep(step string) error {
+	return cm.GetStepWriter(step).ResetStateDir()
+}
+
+// The mock's steps are all writable, so it records the failure as MarkFailed
+// would; the step must be in progress for IsFailed to report it.
+func (cm *MockChecklistManager) SetFailure(step string, err error) error {
+	return cm.GetStepWriter(step).MarkFailed(err)
+}
+
 type MockStepReader struct {
 	step    string
 	code    pb.UpgradeSteps
@@ -69,6 +105,29 @@ func (r MockStepReader) Status() pb.StepStatus {
 	}
 }
 
+func (r MockStepReader) HostStatuses() []*pb.HostStepStatus {
+	return r.manager.HostStatuses(r.step)
+}
+
+func (r MockStepReader) Failure() *pb.StepFailure {
+	if !r.manager.IsFailed(r.step) {
+		return nil
+	}
+
+	err := r.manager.mapErrors[r.step]
+	failure := &pb.StepFailure{Error: err.Error()}
+	if cmdErr, ok := errors.Cause(err).(*upgradestatus.CommandError); ok {
+		failure.Command = cmdErr.Command
+		failure.ExitCode = int32(cmdErr.ExitCode)
+		failure.LogFile = cmdErr.LogFile
+	}
+	return failure
+}
+
+func (r MockStepReader) History() []*pb.StepTransition {
+	return r.manager.mapHistory[r.step]
+}
+
 func (r MockStepReader) Name() string {
 	return r.step
 }
@@ -84,24 +143,52 @@ type MockStepWriter struct {
 
 func (w MockStepWriter) MarkComplete() error {
 	w.manager.mapComplete[w.step] = true
+	w.record(&pb.StepTransition{Status: pb.StepStatus_COMPLETE})
 	return nil
 }
 
 func (w MockStepWriter) MarkInProgress() error {
 	w.manager.mapInProgress[w.step] = true
+	w.record(&pb.StepTransition{Status: pb.StepStatus_RUNNING})
 	return nil
 }
 
-func (w MockStepWriter) MarkFailed() error {
+func (w MockStepWriter) MarkFailed(err error) error {
 	w.manager.mapFailed[w.step] = true
+	w.manager.mapErrors[w.step] = err
+	w.record(&pb.StepTransition{Status: pb.StepStatus_FAILED, Error: err.Error()})
+	return nil
+}
+
+func (w MockStepWriter) MarkHostComplete(host string) error {
+	w.markHost(&pb.HostStepStatus{Hostname: host, Status: pb.StepStatus_COMPLETE})
 	return nil
 }
 
+func (w MockStepWriter) MarkHostFailed(host string, reason string) error {
+	w.markHost(&pb.HostStepStatus{Hostname: host, Status: pb.StepStatus_FAILED, Error: reason})
+	return nil
+}
+
+func (w MockStepWriter) markHost(status *pb.HostStepStatus) {
+	if w.manager.mapHosts[w.step] == nil {
+		w.manager.mapHosts[w.step] = make(map[string]*pb.HostStepStatus)
+	}
+	w.manager.mapHosts[w.step][status.Hostname] = status
+	w.record(&pb.StepTransition{Status: status.Status, Hostname: status.Hostname, Error: status.Error})
+}
+
+func (w MockStepWriter) record(transition *pb.StepTransition) {
+	w.manager.mapHistory[w.step] = append(w.manager.mapHistory[w.step], transition)
+}
+
 func (w MockStepWriter) ResetStateDir() error {
 	w.manager.mapReset[w.step] = true
+	delete(w.manager.mapHosts, w.step)
 	w.manager.mapComplete[w.step] = false
 	w.manager.mapFailed[w.step] = false
 	w.manager.mapInProgress[w.step] = false
+	delete(w.manager.mapErrors, w.step)
 	return nil
 }
 
@@ -129,3 +216,21 @@ func (cm *MockChecklistManager) IsInProgress(step string) bool {
 func (cm *MockChecklistManager) WasReset(step string) bool {
 	return cm.mapReset[step]
 }
+
+// FailureError returns the error the step was last marked failed with.
+func (cm *MockChecklistManager) FailureError(step string) error {
+	return cm.mapErrors[step]
+}
+
+// HostStatuses returns what was recorded for each host by the step, ordered by
+// host.
+func (cm *MockChecklistManager) HostStatuses(step string) []*pb.HostStepStatus {
+	var statuses []*pb.HostStepStatus
+	for _, status := range cm.mapHosts[step] {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Hostname < statuses[j].Hostname
+	})
+	return statuses
+}
@@ -33,6 +33,10 @@ func (m *MockHubClient) StatusConversion(ctx context.Context, in *pb.StatusConve
 	return nil, nil
 }
 
+func (m *MockHubClient) StatusAgents(ctx context.Context, in *pb.StatusAgentsRequest, opts ...grpc.CallOption) (*pb.StatusAgentsReply, error) {
+	return nil, nil
+}
+
 func (m *MockHubClient) CheckConfig(ctx context.Context, in *pb.CheckConfigRequest, opts ...grpc.CallOption) (*pb.CheckConfigReply, error) {
 	return nil, nil
 }
@@ -45,6 +49,14 @@ func (m *MockHubClient) CheckObjectCount(ctx context.Context, in *pb.CheckObject
 	return nil, nil
 }
 
+func (m *MockHubClient) CheckCatalog(ctx context.Context, in *pb.CheckCatalogRequest, opts ...grpc.CallOption) (*pb.CheckCatalogReply, error) {
+	return nil, nil
+}
+
+func (m *MockHubClient) CheckPorts(ctx context.Context, in *pb.CheckPortsRequest, opts ...grpc.CallOption) (*pb.CheckPortsReply, error) {
+	return nil, nil
+}
+
 func (m *MockHubClient) CheckVersion(ctx context.Context, in *pb.CheckVersionRequest, opts ...grpc.CallOption) (*pb.CheckVersionReply, error) {
 	return nil, nil
 }
@@ -85,6 +97,14 @@ func (m *MockHubClient) UpgradeConvertPrimaries(ctx context.Context, in *pb.Upgr
 	return m.UpgradeConvertPrimariesResponse, m.Err
 }
 
+func (m *MockHubClient) UpgradeRebuildMirrors(ctx context.Context, in *pb.UpgradeRebuildMirrorsRequest, opts ...grpc.CallOption) (*pb.UpgradeRebuildMirrorsReply, error) {
+	return nil, nil
+}
+
+func (m *MockHubClient) UpgradeRebuildStandby(ctx context.Context, in *pb.UpgradeRebuildStandbyRequest, opts ...grpc.CallOption) (*pb.UpgradeRebuildStandbyReply, error) {
+	return nil, nil
+}
+
 func (m *MockHubClient) UpgradeReconfigurePorts(ctx context.Context, in *pb.UpgradeReconfigurePortsRequest, opts ...grpc.CallOption) (*pb.UpgradeReconfigurePortsReply, error) {
 	m.UpgradeReconfigurePortsRequest = in
 
@@ -98,3 +118,23 @@ func (m *MockHubClient) SetConfig(ctx context.Context, in *pb.SetConfigRequest,
 func (m *MockHubClient) GetConfig(ctx context.Context, in *pb.GetConfigRequest, opts ...grpc.CallOption) (*pb.GetConfigReply, error) {
 	return nil, m.Err
 }
+
+func (m *MockHubClient) Run(ctx context.Context, in *pb.RunRequest, opts ...grpc.CallOption) (*pb.RunReply, error) {
+	return nil, m.Err
+}
+
+func (m *MockHubClient) Resume(ctx context.Context, in *pb.ResumeRequest, opts ...grpc.CallOption) (*pb.ResumeReply, error) {
+	return nil, m.Err
+}
+
+func (m *MockHubClient) Revert(ctx context.Context, in *pb.RevertRequest, opts ...grpc.CallOption) (*pb.RevertReply, error) {
+	return nil, m.Err
+}
+
+func (m *MockHubClient) Cancel(ctx context.Context, in *pb.CancelRequest, opts ...grpc.CallOption) (*pb.CancelReply, error) {
+	return nil, m.Err
+}
+
+func (m *MockHubClient) WatchUpgrade(ctx context.Context, in *pb.WatchUpgradeRequest, opts ...grpc.CallOption) (pb.CliToHub_WatchUpgradeClient, error) {
+	return nil, m.Err
+}
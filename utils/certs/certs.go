@@ -0,0 +1,295 @@
+// Package certs manages the certificate authority that gpupgrade uses to
+// authenticate its own processes to each other when TLS is enabled.
+//
+// The hub creates the authority, and a certificate for itself, in its state
+// directory when it starts. During prepare start-agents it issues a
+// certificate for each host and copies it to that host's state directory,
+// along with the authority's certificate (but never its key). Every server
+// then requires clients to present a certificate signed by the authority, so
+// only the hub can talk to the agents, and only the CLI, which shares the
+// hub's state directory, can talk to the hub.
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// DIRNAME is the subdirectory of the state directory that holds the
+// certificates.
+const DIRNAME = "certs"
+
+const (
+	CA_CERT    = "ca.crt"
+	CA_KEY     = "ca.key" // only ever on the hub's host
+	HUB_CERT   = "hub.crt"
+	HUB_KEY    = "hub.key"
+	AGENT_CERT = "agent.crt"
+	AGENT_KEY  = "agent.key"
+)
+
+// Certificates last long enough for any upgrade; a new authority is made for
+// each one.
+const validity = 365 * 24 * time.Hour
+
+// Dir returns the directory that holds the certificates for stateDir.
+func Dir(stateDir string) string {
+	return filepath.Join(stateDir, DIRNAME)
+}
+
+type Authority struct {
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+}
+
+// NewAuthority generates a new certificate authority.
+func NewAuthority() (*Authority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate a key for the certificate authority")
+	}
+
+	template, err := newTemplate("gpupgrade certificate authority")
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create the certificate authority")
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse the certificate authority")
+	}
+
+	return &Authority{Cert: cert, Key: key}, nil
+}
+
+// LoadAuthority reads the certificate authority that Write saved to dir.
+func LoadAuthority(dir string) (*Authority, error) {
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, CA_CERT), filepath.Join(dir, CA_KEY))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not load the certificate authority from %s", dir)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse the certificate authority in %s", dir)
+	}
+	key, ok := pair.PrivateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("the certificate authority in %s does not have an ECDSA key", dir)
+	}
+
+	return &Authority{Cert: cert, Key: key}, nil
+}
+
+// Write saves the certificate authority, key and all, to dir.
+func (a *Authority) Write(dir string) error {
+	key, err := encodeKey(a.Key)
+	if err != nil {
+		return err
+	}
+	return writePair(dir, CA_CERT, CA_KEY, a.CertPEM(), key)
+}
+
+// CertPEM returns the authority's certificate, which is all that anyone who
+// verifies the certificates it issues needs.
+func (a *Authority) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: a.Cert.Raw})
+}
+
+// Issue creates a certificate, and its key, for the given host names and IP
+// addresses. Certificates for clients can also be used to serve; those for
+// servers alone cannot be used to connect to anything.
+func (a *Authority) Issue(client bool, names ...string) (cert, key []byte, err error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not generate a key")
+	}
+
+	template, err := newTemplate(names[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	if client {
+		template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+	}
+	for _, name := range names {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.Cert, &privateKey.PublicKey, a.Key)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "could not create a certificate for %s", names[0])
+	}
+	key, err = encodeKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), key, nil
+}
+
+// EnsureHubCerts creates the certificate authority and the hub's certificate
+// in dir, unless they are already there, so that restarting the hub keeps
+// the agents' certificates valid.
+func EnsureHubCerts(dir string, names ...string) (*Authority, error) {
+	_, err := os.Stat(filepath.Join(dir, CA_KEY))
+	if err == nil {
+		return LoadAuthority(dir)
+	}
+	if !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "could not find the certificate authority in %s", dir)
+	}
+
+	authority, err := NewAuthority()
+	if err != nil {
+		return nil, err
+	}
+	err = authority.Write(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, key, err := authority.Issue(true, names...)
+	if err != nil {
+		return nil, err
+	}
+	err = writePair(dir, HUB_CERT, HUB_KEY, cert, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return authority, nil
+}
+
+// WriteAgentCerts puts the files that an agent needs into dir, ready to be
+// copied to the agent's host.
+func (a *Authority) WriteAgentCerts(dir string, host string) error {
+	cert, key, err := a.Issue(false, host)
+	if err != nil {
+		return err
+	}
+	err = writePair(dir, AGENT_CERT, AGENT_KEY, cert, key)
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, CA_CERT), a.CertPEM(), 0644)
+}
+
+// ServerCredentials serve with the named certificate from dir, and accept
+// only clients with a certificate from the authority.
+func ServerCredentials(dir, certFile, keyFile string) (credentials.TransportCredentials, error) {
+	pair, pool, err := load(dir, certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{pair},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// ClientCredentials connect with the named certificate from dir, and accept
+// only servers with a certificate from the authority.
+func ClientCredentials(dir, certFile, keyFile string) (credentials.TransportCredentials, error) {
+	pair, pool, err := load(dir, certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{pair},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+func load(dir, certFile, keyFile string) (tls.Certificate, *x509.CertPool, error) {
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, certFile), filepath.Join(dir, keyFile))
+	if err != nil {
+		return tls.Certificate{}, nil, errors.Wrapf(err, "could not load the certificate %s", filepath.Join(dir, certFile))
+	}
+
+	path := filepath.Join(dir, CA_CERT)
+	ca, err := ioutil.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, nil, errors.Wrap(err, "could not read the certificate authority")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return tls.Certificate{}, nil, errors.Errorf("no certificates found in %s", path)
+	}
+
+	return pair, pool, nil
+}
+
+func newTemplate(commonName string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate a serial number")
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"gpupgrade"}, CommonName: commonName},
+		NotBefore:    now.Add(-time.Hour), // allow for clock skew between hosts
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode a key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func writePair(dir, certFile, keyFile string, cert, key []byte) error {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return errors.Wrapf(err, "could not create %s", dir)
+	}
+	err = writeFile(filepath.Join(dir, certFile), cert, 0644)
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, keyFile), key, 0600)
+}
+
+func writeFile(path string, contents []byte, mode os.FileMode) error {
+	err := ioutil.WriteFile(path, contents, mode)
+	if err != nil {
+		return errors.Wrapf(err, "could not write %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package certs_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	pb "github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/certs"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// pinger answers PingAgents, which is all that these tests need of an agent.
+type pinger struct {
+	pb.AgentServer
+}
+
+func (p *pinger) PingAgents(context.Context, *pb.PingAgentsRequest) (*pb.PingAgentsReply, error) {
+	return &pb.PingAgentsReply{}, nil
+}
+
+var _ = Describe("certs", func() {
+	var (
+		hubDir   string
+		agentDir string
+		server   *grpc.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		hubDir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+		agentDir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		if server != nil {
+			server.Stop()
+			server = nil
+		}
+		os.RemoveAll(hubDir)
+		os.RemoveAll(agentDir)
+	})
+
+	// serve starts an agent that requires clients to be authenticated with
+	// the certificates in agentDir, and returns its address.
+	serve := func() string {
+		creds, err := certs.ServerCredentials(agentDir, certs.AGENT_CERT, certs.AGENT_KEY)
+		Expect(err).ToNot(HaveOccurred())
+
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+
+		server = grpc.NewServer(grpc.Creds(creds))
+		pb.RegisterAgentServer(server, &pinger{})
+		go server.Serve(lis)
+
+		return lis.Addr().String()
+	}
+
+	ping := func(address string, transport grpc.DialOption) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		conn, err := grpc.Dial(address, transport)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		_, err = pb.NewAgentClient(conn).PingAgents(ctx, &pb.PingAgentsRequest{})
+		return err
+	}
+
+	hubTransport := func(dir string) grpc.DialOption {
+		creds, err := certs.ClientCredentials(dir, certs.HUB_CERT, certs.HUB_KEY)
+		Expect(err).ToNot(HaveOccurred())
+		return grpc.WithTransportCredentials(creds)
+	}
+
+	It("lets the hub reach an agent with a certificate from the same authority", func() {
+		authority, err := certs.EnsureHubCerts(hubDir, "localhost")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(authority.WriteAgentCerts(agentDir, "127.0.0.1")).To(Succeed())
+
+		Expect(ping(serve(), hubTransport(hubDir))).To(Succeed())
+	})
+
+	It("keeps the authority and the hub's certificate across restarts", func() {
+		first, err := certs.EnsureHubCerts(hubDir, "localhost")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first.WriteAgentCerts(agentDir, "127.0.0.1")).To(Succeed())
+
+		second, err := certs.EnsureHubCerts(hubDir, "localhost")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second.Cert.Equal(first.Cert)).To(BeTrue())
+
+		Expect(ping(serve(), hubTransport(hubDir))).To(Succeed())
+	})
+
+	It("keeps the authority's key out of the agent's files", func() {
+		authority, err := certs.NewAuthority()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(authority.WriteAgentCerts(agentDir, "127.0.0.1")).To(Succeed())
+
+		_, err = os.Stat(filepath.Join(agentDir, certs.CA_KEY))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		info, err := os.Stat(filepath.Join(agentDir, certs.AGENT_KEY))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("rejects a client with a certificate from a different authority", func() {
+		authority, err := certs.EnsureHubCerts(hubDir, "localhost")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(authority.WriteAgentCerts(agentDir, "127.0.0.1")).To(Succeed())
+		address := serve()
+
+		// The impostor trusts the real agent, but has its own authority.
+		impostorDir := filepath.Join(hubDir, "impostor")
+		_, err = certs.EnsureHubCerts(impostorDir, "localhost")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(impostorDir, certs.CA_CERT), authority.CertPEM(), 0644)).To(Succeed())
+
+		Expect(ping(address, hubTransport(impostorDir))).ToNot(Succeed())
+	})
+
+	It("rejects a client without a certificate", func() {
+		authority, err := certs.EnsureHubCerts(hubDir, "localhost")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(authority.WriteAgentCerts(agentDir, "127.0.0.1")).To(Succeed())
+
+		Expect(ping(serve(), grpc.WithInsecure())).ToNot(Succeed())
+	})
+
+	It("does not let one agent use its certificate to reach another", func() {
+		authority, err := certs.EnsureHubCerts(hubDir, "localhost")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(authority.WriteAgentCerts(agentDir, "127.0.0.1")).To(Succeed())
+		address := serve()
+
+		creds, err := certs.ClientCredentials(agentDir, certs.AGENT_CERT, certs.AGENT_KEY)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(ping(address, grpc.WithTransportCredentials(creds))).ToNot(Succeed())
+	})
+
+	It("does not trust an agent whose certificate is for another host", func() {
+		authority, err := certs.EnsureHubCerts(hubDir, "localhost")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(authority.WriteAgentCerts(agentDir, "sdw1")).To(Succeed())
+
+		Expect(ping(serve(), hubTransport(hubDir))).ToNot(Succeed())
+	})
+})
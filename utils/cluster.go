@@ -2,6 +2,8 @@ package utils
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 
 	"github.com/greenplum-db/gp-common-go-libs/cluster"
 )
@@ -11,10 +13,42 @@ const (
 	TARGET_CONFIG_FILENAME = "target_cluster_config.json"
 )
 
+// The modes pg_upgrade can run in. In copy mode the source cluster's files are
+// copied into the target cluster and left alone. In link mode user relation
+// files are hard-linked instead, which needs no room for a second copy of the
+// data, but leaves the source cluster unusable once the target has started.
+const (
+	COPY_MODE = "copy"
+	LINK_MODE = "link"
+)
+
 type Cluster struct {
 	*cluster.Cluster
 	BinDir     string
 	ConfigPath string
+
+	// Mirrors maps a content ID to the mirror of that content's primary, and
+	// -1 to the standby master. cluster.Cluster only has room for one segment
+	// per content, so the primaries and master live there and the mirrors
+	// live here. It is nil for a cluster without mirrors or a standby.
+	Mirrors map[int]cluster.SegConfig
+
+	// Layout is only set for the target cluster, where it records how the
+	// operator wants the target segments to be placed.
+	Layout LayoutSettings
+
+	// Mode is only set for the target cluster, where it records the mode
+	// that pg_upgrade is to be run in. Empty means COPY_MODE.
+	Mode string
+}
+
+// LayoutSettings choose the ports and data directories of the target cluster.
+// Any that are left empty fall back to the defaults of the hub's layout
+// planner.
+type LayoutSettings struct {
+	PortRange       string `json:",omitempty"` // "base" or "base-max"
+	PortMapFile     string `json:",omitempty"` // path, on the master host, to explicit port assignments
+	DataDirTemplate string `json:",omitempty"` // e.g. "{parent}_v6/{base}"
 }
 
 /*
@@ -22,8 +56,11 @@ type Cluster struct {
  * present in cluster.Cluster
  */
 type ClusterConfig struct {
-	SegConfigs []cluster.SegConfig
-	BinDir     string
+	SegConfigs    []cluster.SegConfig
+	MirrorConfigs []cluster.SegConfig `json:",omitempty"`
+	BinDir        string
+	Layout        *LayoutSettings `json:",omitempty"`
+	Mode          string          `json:",omitempty"`
 }
 
 func (c *Cluster) Load() error {
@@ -37,23 +74,88 @@ func (c *Cluster) Load() error {
 		return err
 	}
 	c.Cluster = cluster.NewCluster(clusterConfig.SegConfigs)
+	c.SetMirrors(clusterConfig.MirrorConfigs)
 	c.BinDir = clusterConfig.BinDir
+	c.Layout = LayoutSettings{}
+	if clusterConfig.Layout != nil {
+		c.Layout = *clusterConfig.Layout
+	}
+	c.Mode = clusterConfig.Mode
 	return nil
 }
 
 func (c *Cluster) Commit() error {
 	segConfigs := make([]cluster.SegConfig, 0)
-	clusterConfig := &ClusterConfig{BinDir: c.BinDir}
+	clusterConfig := &ClusterConfig{BinDir: c.BinDir, Mode: c.Mode}
 
 	for _, contentID := range c.Cluster.ContentIDs {
 		segConfigs = append(segConfigs, c.Segments[contentID])
 	}
 
 	clusterConfig.SegConfigs = segConfigs
+	clusterConfig.MirrorConfigs = c.MirrorConfigs()
+	if c.Layout != (LayoutSettings{}) {
+		clusterConfig.Layout = &c.Layout
+	}
 
 	return WriteJSONFile(c.ConfigPath, clusterConfig)
 }
 
+// SetMirrors replaces the cluster's mirrors and standby with the given ones.
+func (c *Cluster) SetMirrors(mirrors []cluster.SegConfig) {
+	c.Mirrors = nil
+	for _, mirror := range mirrors {
+		if c.Mirrors == nil {
+			c.Mirrors = make(map[int]cluster.SegConfig)
+		}
+		c.Mirrors[mirror.ContentID] = mirror
+	}
+}
+
+// MirrorConfigs returns the mirrors, including the standby, ordered by
+// content ID.
+func (c *Cluster) MirrorConfigs() []cluster.SegConfig {
+	var mirrors []cluster.SegConfig
+	for _, mirror := range c.Mirrors {
+		mirrors = append(mirrors, mirror)
+	}
+	sort.Slice(mirrors, func(i, j int) bool {
+		return mirrors[i].ContentID < mirrors[j].ContentID
+	})
+	return mirrors
+}
+
+// HasMirrors is true if any primary segment has a mirror.
+func (c *Cluster) HasMirrors() bool {
+	for content := range c.Mirrors {
+		if content != -1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Standby returns the standby master, if there is one.
+func (c *Cluster) Standby() (cluster.SegConfig, bool) {
+	standby, ok := c.Mirrors[-1]
+	return standby, ok
+}
+
+// LinkMode is true if pg_upgrade is to hard-link this cluster's files.
+func (c *Cluster) LinkMode() bool {
+	return c.Mode == LINK_MODE
+}
+
+// ValidateMode makes sure that mode is one that pg_upgrade can run in. Empty
+// is allowed, and means COPY_MODE.
+func ValidateMode(mode string) error {
+	switch mode {
+	case "", COPY_MODE, LINK_MODE:
+		return nil
+	}
+	return fmt.Errorf(`mode must be "%s" or "%s", not "%s"`, COPY_MODE, LINK_MODE, mode)
+}
+
 func (c *Cluster) MasterDataDir() string {
 	return c.GetDirForContent(-1)
 }
@@ -62,11 +164,18 @@ func (c *Cluster) MasterPort() int {
 	return c.GetPortForContent(-1)
 }
 
+func (c *Cluster) MasterHostname() string {
+	return c.GetHostForContent(-1)
+}
+
 func (c *Cluster) GetHostnames() []string {
 	hostnameMap := make(map[string]bool, 0)
 	for _, seg := range c.Segments {
 		hostnameMap[seg.Hostname] = true
 	}
+	for _, seg := range c.Mirrors {
+		hostnameMap[seg.Hostname] = true
+	}
 	hostnames := make([]string, 0)
 	for host := range hostnameMap {
 		hostnames = append(hostnames, host)
@@ -8,6 +8,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/utils"
 
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -51,5 +52,64 @@ var _ = Describe("Cluster", func() {
 
 			Expect(expectedCluster).To(Equal(givenCluster))
 		})
+
+		It("saves and loads mirrors and the standby", func() {
+			expectedCluster.SetMirrors([]cluster.SegConfig{
+				{ContentID: 1, DbID: 6, Port: 35433, Hostname: "sdw1", DataDir: "/mirror/seg2"},
+				{ContentID: -1, DbID: 4, Port: 15432, Hostname: "smdw", DataDir: "/standby/seg-1"},
+			})
+			err := expectedCluster.Commit()
+			Expect(err).ToNot(HaveOccurred())
+			givenCluster := &utils.Cluster{
+				ConfigPath: path.Join(testStateDir, "cluster_config.json"),
+			}
+			err = givenCluster.Load()
+			Expect(err).ToNot(HaveOccurred())
+
+			givenCluster.Executor = expectedCluster.Executor
+
+			Expect(expectedCluster).To(Equal(givenCluster))
+			Expect(givenCluster.HasMirrors()).To(BeTrue())
+			standby, ok := givenCluster.Standby()
+			Expect(ok).To(BeTrue())
+			Expect(standby.Hostname).To(Equal("smdw"))
+		})
+	})
+
+	Describe("Mode", func() {
+		It("is saved and loaded with the cluster", func() {
+			expectedCluster.Mode = utils.LINK_MODE
+			err := expectedCluster.Commit()
+			Expect(err).ToNot(HaveOccurred())
+			givenCluster := &utils.Cluster{
+				ConfigPath: path.Join(testStateDir, "cluster_config.json"),
+			}
+			err = givenCluster.Load()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(givenCluster.Mode).To(Equal(utils.LINK_MODE))
+			Expect(givenCluster.LinkMode()).To(BeTrue())
+		})
+
+		It("defaults to copy mode", func() {
+			Expect(expectedCluster.LinkMode()).To(BeFalse())
+		})
+
+		It("accepts only copy and link", func() {
+			Expect(utils.ValidateMode("")).To(Succeed())
+			Expect(utils.ValidateMode("copy")).To(Succeed())
+			Expect(utils.ValidateMode("link")).To(Succeed())
+			Expect(utils.ValidateMode("clone")).To(MatchError(`mode must be "copy" or "link", not "clone"`))
+		})
+	})
+
+	Describe("GetHostnames", func() {
+		It("includes the hosts of mirrors and the standby", func() {
+			expectedCluster.SetMirrors([]cluster.SegConfig{
+				{ContentID: -1, Hostname: "smdw"},
+				{ContentID: 0, Hostname: "sdw1"},
+			})
+			Expect(expectedCluster.GetHostnames()).To(ConsistOf("localhost", "smdw", "sdw1"))
+		})
 	})
 })
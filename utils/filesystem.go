@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// SameFilesystem returns whether two existing paths are on the same
+// filesystem, which hard links, and so pg_upgrade's link mode, need.
+func SameFilesystem(a, b string) (bool, error) {
+	aDev, err := device(a)
+	if err != nil {
+		return false, err
+	}
+	bDev, err := device(b)
+	if err != nil {
+		return false, err
+	}
+	return aDev == bDev, nil
+}
+
+func device(path string) (uint64, error) {
+	info, err := System.Stat(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not find the filesystem of %s", path)
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("could not find the filesystem of %s", path)
+	}
+	return uint64(stat.Dev), nil
+}
+
+// CheckSameFilesystem returns an error, suitable for the user, unless a
+// source data directory and its target are on the same filesystem.
+func CheckSameFilesystem(sourceDataDir, targetDataDir string) error {
+	same, err := SameFilesystem(sourceDataDir, targetDataDir)
+	if err != nil {
+		return err
+	}
+	if !same {
+		return fmt.Errorf("link mode needs source data directory %s and target data directory %s on the same filesystem", sourceDataDir, targetDataDir)
+	}
+	return nil
+}
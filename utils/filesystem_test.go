@@ -0,0 +1,45 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("filesystems", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("finds directories on the same filesystem", func() {
+		source := filepath.Join(dir, "seg1")
+		target := filepath.Join(dir, "seg1_upgrade")
+		Expect(os.Mkdir(source, 0700)).To(Succeed())
+		Expect(os.Mkdir(target, 0700)).To(Succeed())
+
+		Expect(utils.CheckSameFilesystem(source, target)).To(Succeed())
+	})
+
+	It("finds directories on different filesystems", func() {
+		Expect(utils.CheckSameFilesystem(dir, "/proc")).To(MatchError(
+			"link mode needs source data directory " + dir + " and target data directory /proc on the same filesystem"))
+	})
+
+	It("returns an error for a directory that does not exist", func() {
+		_, err := utils.SameFilesystem(dir, filepath.Join(dir, "missing"))
+		Expect(err).To(MatchError(ContainSubstring("could not find the filesystem of " + filepath.Join(dir, "missing"))))
+	})
+})
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/cluster"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/pkg/errors"
+)
+
+// PgUpgradePidFile, in the directory that pg_upgrade runs in, holds the pid of
+// the process group that RunCommandAsync started it in.
+const PgUpgradePidFile = "pg_upgrade.pid"
+
+// How long StopPgUpgrade waits for pg_upgrade to exit after asking it to,
+// before it kills it.
+const DefaultTerminateTimeout = 30 * time.Second
+
+// A PgServer is a server that pg_upgrade starts while it works: the old or the
+// new master, or segment, on this host.
+type PgServer struct {
+	BinDir  string
+	DataDir string
+}
+
+func WritePidFile(path string, pid int) error {
+	return System.WriteFile(path, []byte(strconv.Itoa(pid)+"\n"), 0644)
+}
+
+func ReadPidFile(path string) (int, error) {
+	contents, err := System.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not parse %s", path)
+	}
+	return pid, nil
+}
+
+// StopPgUpgrade stops the pg_upgrade that was started in workingDir, if it is
+// still running, and cleans up after it: any of the servers that it left
+// running are stopped, and its progress files are removed, so that it is not
+// taken to be running any longer. It returns the pid of the pg_upgrade that
+// was stopped, or zero if none was running.
+func StopPgUpgrade(executor cluster.Executor, workingDir string, timeout time.Duration, servers ...PgServer) (int, error) {
+	pidFile := filepath.Join(workingDir, PgUpgradePidFile)
+	pid, err := ReadPidFile(pidFile)
+	if System.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !processGroupExists(pid) || !isPgUpgrade(pid) {
+		// The pid file was left behind by a pg_upgrade that has exited.
+		err = System.Remove(pidFile)
+		if err != nil && !System.IsNotExist(err) {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	err = TerminateProcessGroup(pid, timeout)
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not stop pg_upgrade (pid %d)", pid)
+	}
+
+	for _, server := range servers {
+		err = stopServer(executor, server)
+		if err != nil {
+			return pid, err
+		}
+	}
+
+	inProgress, err := System.FilePathGlob(filepath.Join(workingDir, "*.inprogress"))
+	if err != nil {
+		return pid, err
+	}
+	for _, path := range append(inProgress, pidFile) {
+		err = System.Remove(path)
+		if err != nil && !System.IsNotExist(err) {
+			return pid, err
+		}
+	}
+
+	return pid, nil
+}
+
+// RemoveProgressFiles removes the progress files that an earlier pg_upgrade
+// left in workingDir. pg_upgrade removes them itself as it starts, but until
+// then they would report the earlier attempt's result as this one's.
+func RemoveProgressFiles(workingDir string) error {
+	for _, pattern := range []string{"*.done", "*.inprogress"} {
+		paths, err := System.FilePathGlob(filepath.Join(workingDir, pattern))
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			err = System.Remove(path)
+			if err != nil && !System.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TerminateProcessGroup sends SIGTERM to every process in the group, and
+// SIGKILL to whatever is left of it once the timeout has passed.
+func TerminateProcessGroup(pgid int, timeout time.Duration) error {
+	err := System.Kill(-pgid, syscall.SIGTERM)
+	if err == syscall.ESRCH {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	deadline := System.Now().Add(timeout)
+	for System.Now().Before(deadline) {
+		if !processGroupExists(pgid) {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	gplog.Warn("Process group %d did not exit within %s; killing it", pgid, timeout)
+	err = System.Kill(-pgid, syscall.SIGKILL)
+	if err != nil && err != syscall.ESRCH {
+		return err
+	}
+	return nil
+}
+
+// isPgUpgrade makes sure that the process group that RunCommandAsync started
+// pg_upgrade in hasn't since exited and had its id reused by another group:
+// it is led by a process that runs pg_upgrade. If the leader has exited, the
+// rest of its group is what's left of pg_upgrade, since no other group can
+// take the id while the group exists.
+func isPgUpgrade(pgid int) bool {
+	args, err := System.ProcessArgs(pgid)
+	if err != nil {
+		return true
+	}
+	return strings.Contains(strings.Join(args, " "), "pg_upgrade")
+}
+
+func processGroupExists(pgid int) bool {
+	err := System.Kill(-pgid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// stopServer does a fast shutdown of the server, if it is running.
+func stopServer(executor cluster.Executor, server PgServer) error {
+	_, err := executor.ExecuteLocalCommand(fmt.Sprintf("pgrep -F %s", filepath.Join(server.DataDir, "postmaster.pid")))
+	if err != nil {
+		return nil
+	}
+
+	gplog.Info("Stopping the server in %s, which pg_upgrade left running", server.DataDir)
+	output, err := executor.ExecuteLocalCommand(stopServerCommand(server))
+	if err != nil {
+		return errors.Wrapf(err, "could not stop the server in %s: %s", server.DataDir, output)
+	}
+	return nil
+}
+
+func stopServerCommand(server PgServer) string {
+	return fmt.Sprintf("source %[1]s/../greenplum_path.sh; %[1]s/pg_ctl -D %[2]s -m fast -w stop", server.BinDir, server.DataDir)
+}
@@ -0,0 +1,174 @@
+package utils_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StopPgUpgrade", func() {
+	const pid = 4242
+
+	var (
+		dir      string
+		executor *testhelper.TestExecutor
+		signals  []syscall.Signal
+		exited   bool
+		servers  []utils.PgServer
+	)
+
+	BeforeEach(func() {
+		testhelper.SetupTestLogger()
+
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		executor = &testhelper.TestExecutor{}
+		signals = nil
+		exited = false
+		servers = []utils.PgServer{
+			{BinDir: "/old/bin", DataDir: "/old/datadir"},
+			{BinDir: "/new/bin", DataDir: "/new/datadir"},
+		}
+
+		// The process group exits once it is sent anything but signal 0.
+		utils.System.Kill = func(p int, sig syscall.Signal) error {
+			Expect(p).To(Equal(-pid))
+			if sig != 0 {
+				signals = append(signals, sig)
+				exited = true
+			}
+			if exited {
+				return syscall.ESRCH
+			}
+			return nil
+		}
+		utils.System.ProcessArgs = func(p int) ([]string, error) {
+			return []string{"bash", "-c", "cd /tmp && pg_upgrade --old-datadir /old/datadir"}, nil
+		}
+	})
+
+	AfterEach(func() {
+		utils.System = utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	start := func() {
+		err := utils.WritePidFile(filepath.Join(dir, utils.PgUpgradePidFile), pid)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(dir, "1.inprogress"), nil, 0600)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("stops pg_upgrade and the servers it left running, and removes its progress files", func() {
+		start()
+
+		stopped, err := utils.StopPgUpgrade(executor, dir, time.Minute, servers...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stopped).To(Equal(pid))
+
+		Expect(signals).To(Equal([]syscall.Signal{syscall.SIGTERM}))
+		Expect(executor.LocalCommands).To(Equal([]string{
+			"pgrep -F /old/datadir/postmaster.pid",
+			"source /old/bin/../greenplum_path.sh; /old/bin/pg_ctl -D /old/datadir -m fast -w stop",
+			"pgrep -F /new/datadir/postmaster.pid",
+			"source /new/bin/../greenplum_path.sh; /new/bin/pg_ctl -D /new/datadir -m fast -w stop",
+		}))
+
+		files, err := filepath.Glob(filepath.Join(dir, "*"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(BeEmpty())
+	})
+
+	It("kills pg_upgrade if it does not exit in time", func() {
+		start()
+		utils.System.Kill = func(p int, sig syscall.Signal) error {
+			signals = append(signals, sig)
+			return nil
+		}
+
+		_, err := utils.StopPgUpgrade(executor, dir, 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(signals).To(Equal([]syscall.Signal{0, syscall.SIGTERM, syscall.SIGKILL}))
+	})
+
+	It("leaves servers alone that are not running", func() {
+		start()
+		executor.LocalError = errors.New("no such process")
+
+		_, err := utils.StopPgUpgrade(executor, dir, time.Minute, servers...)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(executor.LocalCommands).To(Equal([]string{
+			"pgrep -F /old/datadir/postmaster.pid",
+			"pgrep -F /new/datadir/postmaster.pid",
+		}))
+	})
+
+	It("does nothing if pg_upgrade was never started", func() {
+		stopped, err := utils.StopPgUpgrade(executor, dir, time.Minute, servers...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stopped).To(Equal(0))
+
+		Expect(signals).To(BeEmpty())
+		Expect(executor.NumExecutions).To(Equal(0))
+	})
+
+	It("does nothing if pg_upgrade has already exited", func() {
+		start()
+		exited = true
+
+		stopped, err := utils.StopPgUpgrade(executor, dir, time.Minute, servers...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stopped).To(Equal(0))
+
+		Expect(signals).To(BeEmpty())
+		Expect(executor.NumExecutions).To(Equal(0))
+		Expect(filepath.Join(dir, "1.inprogress")).To(BeAnExistingFile())
+		Expect(filepath.Join(dir, utils.PgUpgradePidFile)).ToNot(BeAnExistingFile())
+	})
+
+	It("leaves alone a process that has reused the pid of pg_upgrade", func() {
+		start()
+		utils.System.ProcessArgs = func(p int) ([]string, error) {
+			return []string{"/usr/sbin/sshd", "-D"}, nil
+		}
+
+		stopped, err := utils.StopPgUpgrade(executor, dir, time.Minute, servers...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stopped).To(Equal(0))
+
+		Expect(signals).To(BeEmpty())
+		Expect(executor.NumExecutions).To(Equal(0))
+		Expect(filepath.Join(dir, utils.PgUpgradePidFile)).ToNot(BeAnExistingFile())
+	})
+
+	It("returns an error if the pid file cannot be read", func() {
+		err := ioutil.WriteFile(filepath.Join(dir, utils.PgUpgradePidFile), []byte("garbage\n"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = utils.StopPgUpgrade(executor, dir, time.Minute, servers...)
+		Expect(err).To(MatchError(ContainSubstring("could not parse")))
+	})
+
+	It("returns an error if a server cannot be stopped", func() {
+		start()
+		executor.ErrorOnExecNum = 2
+		executor.LocalError = errors.New("pg_ctl failed")
+
+		_, err := utils.StopPgUpgrade(executor, dir, time.Minute, servers...)
+		Expect(err).To(MatchError(ContainSubstring("could not stop the server in /old/datadir")))
+	})
+})
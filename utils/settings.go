@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SETTINGS_FILENAME is read from the state directory, on every host, by the
+// hub, the agents and the CLI.
+const SETTINGS_FILENAME = "gpupgrade_config.json"
+
+const (
+	DefaultHubPort     = 7527
+	DefaultAgentPort   = 6416
+	DefaultDialTimeout = 3 * time.Second
+	DefaultParallelism = 16
+
+	// DefaultAgentTimeout is generous: some calls walk whole data
+	// directories.
+	DefaultAgentTimeout = 10 * time.Minute
+)
+
+// Settings configure how the CLI, the hub and the agents reach one another.
+// Each one comes from, in increasing order of precedence, its default, the
+// settings file in the state directory, and its environment variable. The
+// hub and agent executables let flags override them in turn.
+type Settings struct {
+	HubPort      int           // GPUPGRADE_HUB_PORT: the hub listens for the CLI here
+	AgentPort    int           // GPUPGRADE_AGENT_PORT: the agents listen for the hub here
+	LogDir       string        // GPUPGRADE_LOG_DIR: empty means gplog's default, ~/gpAdminLogs
+	DialTimeout  time.Duration // GPUPGRADE_DIAL_TIMEOUT: how long the hub waits to connect to an agent
+	Parallelism  int           // GPUPGRADE_PARALLELISM: how many hosts the hub works on at once
+	AgentTimeout time.Duration // GPUPGRADE_AGENT_TIMEOUT: how long the hub waits for each agent to answer a call
+	TLS          bool          // GPUPGRADE_TLS: authenticate every connection with certificates
+}
+
+// settingsFile is the form that Settings take on disk. Anything left out
+// keeps its default.
+type settingsFile struct {
+	HubPort      int    `json:",omitempty"`
+	AgentPort    int    `json:",omitempty"`
+	LogDir       string `json:",omitempty"`
+	DialTimeout  string `json:",omitempty"` // a duration, such as "3s"
+	Parallelism  int    `json:",omitempty"`
+	AgentTimeout string `json:",omitempty"` // a duration, such as "10m"
+	TLS          *bool  `json:",omitempty"`
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		HubPort:      DefaultHubPort,
+		AgentPort:    DefaultAgentPort,
+		DialTimeout:  DefaultDialTimeout,
+		Parallelism:  DefaultParallelism,
+		AgentTimeout: DefaultAgentTimeout,
+	}
+}
+
+// LoadSettings reads the settings file in stateDir, if there is one, and then
+// the environment.
+func LoadSettings(stateDir string) (Settings, error) {
+	settings := DefaultSettings()
+
+	path := filepath.Join(stateDir, SETTINGS_FILENAME)
+	contents, err := System.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return Settings{}, errors.Wrapf(err, "could not read %s", path)
+	}
+	if err == nil {
+		file := settingsFile{}
+		err = json.Unmarshal(contents, &file)
+		if err != nil {
+			return Settings{}, errors.Wrapf(err, "could not parse %s", path)
+		}
+		err = settings.apply(file)
+		if err != nil {
+			return Settings{}, errors.Wrapf(err, "invalid setting in %s", path)
+		}
+	}
+
+	file := settingsFile{
+		LogDir:       System.Getenv("GPUPGRADE_LOG_DIR"),
+		DialTimeout:  System.Getenv("GPUPGRADE_DIAL_TIMEOUT"),
+		AgentTimeout: System.Getenv("GPUPGRADE_AGENT_TIMEOUT"),
+	}
+	for name, value := range map[string]*int{
+		"GPUPGRADE_HUB_PORT":    &file.HubPort,
+		"GPUPGRADE_AGENT_PORT":  &file.AgentPort,
+		"GPUPGRADE_PARALLELISM": &file.Parallelism,
+	} {
+		if env := System.Getenv(name); env != "" {
+			*value, err = strconv.Atoi(env)
+			if err != nil {
+				return Settings{}, fmt.Errorf("%s must be a number, not %q", name, env)
+			}
+		}
+	}
+	if env := System.Getenv("GPUPGRADE_TLS"); env != "" {
+		tls, err := strconv.ParseBool(env)
+		if err != nil {
+			return Settings{}, fmt.Errorf("GPUPGRADE_TLS must be true or false, not %q", env)
+		}
+		file.TLS = &tls
+	}
+	err = settings.apply(file)
+	if err != nil {
+		return Settings{}, errors.Wrap(err, "invalid setting in the environment")
+	}
+
+	return settings, nil
+}
+
+// apply overrides the settings with any that are set in file.
+func (s *Settings) apply(file settingsFile) error {
+	if file.HubPort != 0 {
+		s.HubPort = file.HubPort
+	}
+	if file.AgentPort != 0 {
+		s.AgentPort = file.AgentPort
+	}
+	if file.LogDir != "" {
+		s.LogDir = file.LogDir
+	}
+	if file.DialTimeout != "" {
+		timeout, err := time.ParseDuration(file.DialTimeout)
+		if err != nil {
+			return errors.Wrap(err, "DialTimeout")
+		}
+		s.DialTimeout = timeout
+	}
+	if file.Parallelism != 0 {
+		s.Parallelism = file.Parallelism
+	}
+	if file.AgentTimeout != "" {
+		timeout, err := time.ParseDuration(file.AgentTimeout)
+		if err != nil {
+			return errors.Wrap(err, "AgentTimeout")
+		}
+		s.AgentTimeout = timeout
+	}
+	if file.TLS != nil {
+		s.TLS = *file.TLS
+	}
+	return s.Validate()
+}
+
+// Validate makes sure that the settings can be used.
+func (s Settings) Validate() error {
+	for name, port := range map[string]int{"HubPort": s.HubPort, "AgentPort": s.AgentPort} {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("%s %d is not a valid port", name, port)
+		}
+	}
+	if s.DialTimeout <= 0 {
+		return fmt.Errorf("DialTimeout must be positive, not %s", s.DialTimeout)
+	}
+	if s.Parallelism < 1 {
+		return fmt.Errorf("Parallelism must be at least 1, not %d", s.Parallelism)
+	}
+	if s.AgentTimeout <= 0 {
+		return fmt.Errorf("AgentTimeout must be positive, not %s", s.AgentTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("settings", func() {
+	var (
+		dir string
+		env map[string]string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		env = map[string]string{}
+		utils.System.Getenv = func(key string) string {
+			return env[key]
+		}
+	})
+
+	AfterEach(func() {
+		utils.InitializeSystemFunctions()
+		os.RemoveAll(dir)
+	})
+
+	writeSettings := func(contents string) {
+		err := ioutil.WriteFile(filepath.Join(dir, utils.SETTINGS_FILENAME), []byte(contents), 0600)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("uses the defaults when there is no settings file", func() {
+		settings, err := utils.LoadSettings(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal(utils.DefaultSettings()))
+	})
+
+	It("reads the settings file, keeping the defaults for anything it leaves out", func() {
+		writeSettings(`{"HubPort": 7000, "LogDir": "/logs", "DialTimeout": "10s"}`)
+
+		settings, err := utils.LoadSettings(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal(utils.Settings{
+			HubPort:      7000,
+			AgentPort:    utils.DefaultAgentPort,
+			LogDir:       "/logs",
+			DialTimeout:  10 * time.Second,
+			Parallelism:  utils.DefaultParallelism,
+			AgentTimeout: utils.DefaultAgentTimeout,
+		}))
+	})
+
+	It("lets the environment override the settings file", func() {
+		writeSettings(`{"HubPort": 7000, "AgentPort": 7001, "Parallelism": 4}`)
+		env["GPUPGRADE_HUB_PORT"] = "8000"
+		env["GPUPGRADE_PARALLELISM"] = "2"
+		env["GPUPGRADE_DIAL_TIMEOUT"] = "500ms"
+		env["GPUPGRADE_AGENT_TIMEOUT"] = "1h"
+
+		settings, err := utils.LoadSettings(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.HubPort).To(Equal(8000))
+		Expect(settings.AgentPort).To(Equal(7001))
+		Expect(settings.Parallelism).To(Equal(2))
+		Expect(settings.DialTimeout).To(Equal(500 * time.Millisecond))
+		Expect(settings.AgentTimeout).To(Equal(time.Hour))
+	})
+
+	It("turns TLS on from the settings file and off from the environment", func() {
+		writeSettings(`{"TLS": true}`)
+
+		settings, err := utils.LoadSettings(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.TLS).To(BeTrue())
+
+		env["GPUPGRADE_TLS"] = "false"
+
+		settings, err = utils.LoadSettings(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings.TLS).To(BeFalse())
+	})
+
+	It("returns an error for a settings file that cannot be parsed", func() {
+		writeSettings(`{"HubPort": "seven"}`)
+
+		_, err := utils.LoadSettings(dir)
+		Expect(err).To(MatchError(ContainSubstring("could not parse " + filepath.Join(dir, utils.SETTINGS_FILENAME))))
+	})
+
+	It("returns an error for an invalid duration", func() {
+		writeSettings(`{"DialTimeout": "soon"}`)
+
+		_, err := utils.LoadSettings(dir)
+		Expect(err).To(MatchError(ContainSubstring("DialTimeout")))
+	})
+
+	It("returns an error for an environment variable that is not a number", func() {
+		env["GPUPGRADE_AGENT_PORT"] = "six"
+
+		_, err := utils.LoadSettings(dir)
+		Expect(err).To(MatchError(`GPUPGRADE_AGENT_PORT must be a number, not "six"`))
+	})
+
+	It("rejects settings that cannot be used", func() {
+		valid := utils.DefaultSettings()
+
+		settings := valid
+		settings.HubPort = 70000
+		Expect(settings.Validate()).To(MatchError("HubPort 70000 is not a valid port"))
+
+		settings = valid
+		settings.DialTimeout = 0
+		Expect(settings.Validate()).To(MatchError("DialTimeout must be positive, not 0s"))
+
+		settings = valid
+		settings.Parallelism = 0
+		Expect(settings.Validate()).To(MatchError("Parallelism must be at least 1, not 0"))
+
+		settings = valid
+		settings.AgentTimeout = -time.Second
+		Expect(settings.Validate()).To(MatchError("AgentTimeout must be positive, not -1s"))
+	})
+})
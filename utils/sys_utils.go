@@ -9,8 +9,11 @@ import (
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/cloudfoundry/gosigar"
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/pkg/errors"
 )
@@ -28,66 +31,113 @@ var (
  */
 
 type SystemFunctions struct {
-	CurrentUser     func() (*user.User, error)
-	Getenv          func(key string) string
-	Getpid          func() int
-	Hostname        func() (string, error)
-	IsNotExist      func(err error) bool
-	MkdirAll        func(path string, perm os.FileMode) error
-	Now             func() time.Time
-	Open            func(name string) (*os.File, error)
-	OpenFile        func(name string, flag int, perm os.FileMode) (*os.File, error)
-	Remove          func(name string) error
-	RemoveAll       func(name string) error
-	ReadFile        func(filename string) ([]byte, error)
-	WriteFile       func(filename string, data []byte, perm os.FileMode) error
-	Stat            func(name string) (os.FileInfo, error)
-	FilePathGlob    func(pattern string) ([]string, error)
-	Create          func(name string) (*os.File, error)
-	RunCommandAsync func(cmdStr, logFile string) error
+	CurrentUser      func() (*user.User, error)
+	Getenv           func(key string) string
+	Getpid           func() int
+	Hostname         func() (string, error)
+	IsNotExist       func(err error) bool
+	Kill             func(pid int, sig syscall.Signal) error
+	MkdirAll         func(path string, perm os.FileMode) error
+	Now              func() time.Time
+	Open             func(name string) (*os.File, error)
+	OpenFile         func(name string, flag int, perm os.FileMode) (*os.File, error)
+	ProcessArgs      func(pid int) ([]string, error)
+	ProcessStartTime func(pid int) (uint64, error)
+	Remove           func(name string) error
+	RemoveAll        func(name string) error
+	ReadFile         func(filename string) ([]byte, error)
+	WriteFile        func(filename string, data []byte, perm os.FileMode) error
+	Stat             func(name string) (os.FileInfo, error)
+	FilePathGlob     func(pattern string) ([]string, error)
+	Create           func(name string) (*os.File, error)
+	RunCommandAsync  func(cmdStr, logFile string) (int, error)
 }
 
 func InitializeSystemFunctions() *SystemFunctions {
 	return &SystemFunctions{
-		CurrentUser:     user.Current,
-		Getenv:          os.Getenv,
-		Getpid:          os.Getpid,
-		Hostname:        os.Hostname,
-		IsNotExist:      os.IsNotExist,
-		MkdirAll:        os.MkdirAll,
-		Now:             time.Now,
-		Open:            os.Open,
-		OpenFile:        os.OpenFile,
-		Remove:          os.Remove,
-		RemoveAll:       os.RemoveAll,
-		Stat:            os.Stat,
-		FilePathGlob:    filepath.Glob,
-		ReadFile:        ioutil.ReadFile,
-		WriteFile:       ioutil.WriteFile,
-		Create:          os.Create,
-		RunCommandAsync: RunCommandAsync,
+		CurrentUser:      user.Current,
+		Getenv:           os.Getenv,
+		Getpid:           os.Getpid,
+		Hostname:         os.Hostname,
+		IsNotExist:       os.IsNotExist,
+		Kill:             syscall.Kill,
+		MkdirAll:         os.MkdirAll,
+		Now:              time.Now,
+		Open:             os.Open,
+		OpenFile:         os.OpenFile,
+		ProcessArgs:      processArgs,
+		ProcessStartTime: processStartTime,
+		Remove:           os.Remove,
+		RemoveAll:        os.RemoveAll,
+		Stat:             os.Stat,
+		FilePathGlob:     filepath.Glob,
+		ReadFile:         ioutil.ReadFile,
+		WriteFile:        ioutil.WriteFile,
+		Create:           os.Create,
+		RunCommandAsync:  RunCommandAsync,
 	}
 }
 
-func RunCommandAsync(cmdStr, logFile string) error {
+// processArgs returns the command line of the process with the pid.
+func processArgs(pid int) ([]string, error) {
+	procArgs := sigar.ProcArgs{}
+	err := procArgs.Get(pid)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find the command line of process %d", pid)
+	}
+	return procArgs.List, nil
+}
+
+// processStartTime returns when the process with the pid started, in
+// milliseconds since the epoch.
+func processStartTime(pid int) (uint64, error) {
+	procTime := sigar.ProcTime{}
+	err := procTime.Get(pid)
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not find when process %d started", pid)
+	}
+	return procTime.StartTime, nil
+}
+
+// RunCommandAsync starts the command in a process group of its own, so that
+// it can be stopped along with everything it starts, and returns the pid that
+// names the group.
+func RunCommandAsync(cmdStr, logFile string) (int, error) {
 	f, err := os.OpenFile(logFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
 	if err != nil {
 		errMsg := fmt.Sprintf("mkdir %s failed: %v.", logFile, err)
 		gplog.Error(errMsg)
-		return errors.New(errMsg)
+		return 0, errors.New(errMsg)
 	}
 
 	cmd := exec.Command("bash", "-c", cmdStr)
 	cmd.Stdout = f
 	cmd.Stderr = f
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
 	err = cmd.Start()
 	if err != nil {
+		f.Close()
 		errMsg := fmt.Sprintf("Command %s failed to run: %s", cmdStr, err)
 		gplog.Error(errMsg)
-		return errors.New(errMsg)
+		return 0, errors.New(errMsg)
 	}
-	return nil
+
+	// Reap the command when it exits, so that it doesn't linger as a zombie
+	// that still looks like it is running.
+	go func() {
+		cmd.Wait()
+		f.Close()
+	}()
+
+	return cmd.Process.Pid, nil
+}
+
+// AsyncCommandString is the shell equivalent of RunCommandAsync, for showing
+// what RunCommandAsync would do without running anything.
+func AsyncCommandString(cmdStr, logFile string) string {
+	quoted := "'" + strings.Replace(cmdStr, "'", `'\''`, -1) + "'"
+	return fmt.Sprintf("bash -c %s > %s 2>&1 &", quoted, logFile)
 }
 
 func TryEnv(varname string, defval string) string {
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// TARGET_DIR_MARKER is written into every directory that gpupgrade creates
+// for the target cluster. Only directories carrying it are ever removed again
+// by a revert, so that a bad layout can never cost the user source data.
+const TARGET_DIR_MARKER = ".gpupgrade_target"
+
+// CreateTargetDir creates dir, and any missing parents, and marks it as
+// belonging to the target cluster. A directory that already exists is only
+// marked if it is empty, so that one set aside for the upgrade ahead of time
+// is treated the same as one that gpupgrade made. created reports whether dir
+// had to be made.
+func CreateTargetDir(dir string) (created bool, err error) {
+	_, err = System.Stat(dir)
+	if err == nil {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return false, err
+		}
+	} else if !os.IsNotExist(err) {
+		return false, err
+	} else {
+		err = System.MkdirAll(dir, 0755)
+		if err != nil {
+			return false, err
+		}
+		created = true
+	}
+
+	err = System.WriteFile(filepath.Join(dir, TARGET_DIR_MARKER), nil, 0644)
+	return created, err
+}
+
+// IsTargetDir is true if dir was marked by CreateTargetDir.
+func IsTargetDir(dir string) bool {
+	_, err := System.Stat(filepath.Join(dir, TARGET_DIR_MARKER))
+	return err == nil
+}
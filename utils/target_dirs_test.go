@@ -0,0 +1,50 @@
+package utils_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("target directories", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("marks the directories it creates", func() {
+		targetDir := filepath.Join(dir, "primary_upgrade")
+
+		created, err := utils.CreateTargetDir(targetDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(created).To(BeTrue())
+		Expect(utils.IsTargetDir(targetDir)).To(BeTrue())
+	})
+
+	It("marks an existing directory only if it is empty", func() {
+		empty := filepath.Join(dir, "empty")
+		Expect(os.Mkdir(empty, 0755)).To(Succeed())
+
+		created, err := utils.CreateTargetDir(empty)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(created).To(BeFalse())
+		Expect(utils.IsTargetDir(empty)).To(BeTrue())
+
+		created, err = utils.CreateTargetDir(dir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(created).To(BeFalse())
+		Expect(utils.IsTargetDir(dir)).To(BeFalse())
+	})
+})